@@ -0,0 +1,52 @@
+// Package main writes the JSON Schema of robot configs, including the attributes of every
+// registered component and service model.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	// trigger registrations.
+	_ "go.viam.com/rdk/robot/impl"
+)
+
+var logger = golog.NewDebugLogger("config_schema")
+
+// Arguments for the command.
+type Arguments struct {
+	Output     string `flag:"output,usage=file to write the schema to instead of stdout"`
+	Attributes bool   `flag:"attributes,usage=write the attribute schema of each model instead of the robot config schema"`
+}
+
+func main() {
+	utils.ContextualMain(mainWithArgs, logger)
+}
+
+func mainWithArgs(ctx context.Context, args []string, logger golog.Logger) error {
+	var argsParsed Arguments
+	if err := utils.ParseFlags(args, &argsParsed); err != nil {
+		return err
+	}
+
+	var schema interface{} = config.RobotConfigSchema()
+	if argsParsed.Attributes {
+		schema = config.RegisteredAttributeSchemas()
+	}
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+
+	if argsParsed.Output == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	//nolint:gosec
+	return os.WriteFile(argsParsed.Output, out, 0o644)
+}
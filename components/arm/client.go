@@ -8,7 +8,6 @@ import (
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/arm/v1"
 	robotpb "go.viam.com/api/robot/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -43,7 +42,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) EndPosition(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +62,7 @@ func (c *client) MoveToPosition(
 	worldState *referenceframe.WorldState,
 	extra map[string]interface{},
 ) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -81,7 +80,7 @@ func (c *client) MoveToPosition(
 }
 
 func (c *client) MoveToJointPositions(ctx context.Context, positions *pb.JointPositions, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -94,7 +93,7 @@ func (c *client) MoveToJointPositions(ctx context.Context, positions *pb.JointPo
 }
 
 func (c *client) JointPositions(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +108,7 @@ func (c *client) JointPositions(ctx context.Context, extra map[string]interface{
 }
 
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
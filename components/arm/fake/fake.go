@@ -183,6 +183,11 @@ func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
 	return nil
 }
 
+// SafeStop parks the fake arm by moving all of its joints to zero.
+func (a *Arm) SafeStop(ctx context.Context) error {
+	return a.MoveToJointPositions(ctx, &pb.JointPositions{Values: make([]float64, len(a.joints.Values))}, nil)
+}
+
 // IsMoving is always false for a fake arm.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
 	return false, nil
@@ -8,7 +8,6 @@ import (
 	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/base/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -34,7 +33,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -51,7 +50,7 @@ func (c *client) MoveStraight(ctx context.Context, distanceMm int, mmPerSec floa
 }
 
 func (c *client) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -68,7 +67,7 @@ func (c *client) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra m
 }
 
 func (c *client) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -85,7 +84,7 @@ func (c *client) SetPower(ctx context.Context, linear, angular r3.Vector, extra
 }
 
 func (c *client) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -102,7 +101,7 @@ func (c *client) SetVelocity(ctx context.Context, linear, angular r3.Vector, ext
 }
 
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
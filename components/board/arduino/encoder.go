@@ -60,6 +60,7 @@ func NewEncoder(ctx context.Context, deps registry.Dependencies, config config.C
 
 		e.A = cfg.Pins.A
 		e.B = cfg.Pins.B
+		e.ticksPerRotation = cfg.TicksPerRotation
 
 		e.name = cfg.MotorName
 		if e.name == "" {
@@ -72,9 +73,10 @@ func NewEncoder(ctx context.Context, deps registry.Dependencies, config config.C
 
 // Encoder keeps track of an arduino motor position.
 type Encoder struct {
-	board *arduinoBoard
-	A, B  string
-	name  string
+	board            *arduinoBoard
+	A, B             string
+	name             string
+	ticksPerRotation int
 
 	generic.Unimplemented
 }
@@ -90,6 +92,9 @@ type EncoderConfig struct {
 	Pins      EncoderPins `json:"pins"`
 	BoardName string      `json:"board"`
 	MotorName string      `json:"motor_name"`
+
+	// ticks counted in a rotation, if known
+	TicksPerRotation int `json:"ticks_per_rotation,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -99,6 +104,9 @@ func (cfg *EncoderConfig) Validate(path string) ([]string, error) {
 		return nil, rdkutils.NewConfigValidationFieldRequiredError(path, "board")
 	}
 	deps = append(deps, cfg.BoardName)
+	if cfg.TicksPerRotation < 0 {
+		return nil, errors.New("ticks_per_rotation cannot be negative")
+	}
 	return deps, nil
 }
 
@@ -117,6 +125,11 @@ func (e *Encoder) TicksCount(ctx context.Context, extra map[string]interface{})
 	return float64(ticks), nil
 }
 
+// Properties returns the ticks per rotation of the encoder, which is 0 if not configured.
+func (e *Encoder) Properties(ctx context.Context, extra map[string]interface{}) (encoder.Properties, error) {
+	return encoder.Properties{TicksPerRotation: e.ticksPerRotation}, nil
+}
+
 // Reset sets the current position of the motor (adjusted by a given offset)
 // to be its new zero position.
 func (e *Encoder) Reset(ctx context.Context, offset float64, extra map[string]interface{}) error {
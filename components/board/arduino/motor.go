@@ -86,6 +86,13 @@ func configureMotorForBoard(
 		return nil, errors.New("arduino needs at least a & b, or dir & pwm pins")
 	}
 
+	if e != nil {
+		ticksPerRotation, err := encoder.TicksPerRotation(ctx, e, motorConfig.TicksPerRotation)
+		if err != nil {
+			return nil, err
+		}
+		motorConfig.TicksPerRotation = ticksPerRotation
+	}
 	if motorConfig.TicksPerRotation <= 0 {
 		return nil, errors.New("arduino motors TicksPerRotation to be set")
 	}
@@ -230,7 +237,7 @@ func (m *arduinoMotor) Position(ctx context.Context, extra map[string]interface{
 		return 0, fmt.Errorf("couldn't parse # ticks (%s) : %w", res, err)
 	}
 
-	return float64(ticks) / float64(m.cfg.TicksPerRotation), nil
+	return encoder.ConvertTicks(float64(ticks), m.cfg.TicksPerRotation, encoder.PositionTypeRotations)
 }
 
 // Properties returns the status of optional features supported by the motor.
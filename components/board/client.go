@@ -10,7 +10,6 @@ import (
 	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/board/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -128,7 +127,7 @@ func (c *client) Status(ctx context.Context, extra map[string]interface{}) (*com
 		return status, nil
 	}
 
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +197,7 @@ type analogReaderClient struct {
 }
 
 func (arc *analogReaderClient) Read(ctx context.Context, extra map[string]interface{}) (int, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return 0, err
 	}
@@ -222,7 +221,7 @@ type digitalInterruptClient struct {
 }
 
 func (dic *digitalInterruptClient) Value(ctx context.Context, extra map[string]interface{}) (int64, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return 0, err
 	}
@@ -262,7 +261,7 @@ type gpioPinClient struct {
 }
 
 func (gpc *gpioPinClient) Set(ctx context.Context, high bool, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -276,7 +275,7 @@ func (gpc *gpioPinClient) Set(ctx context.Context, high bool, extra map[string]i
 }
 
 func (gpc *gpioPinClient) Get(ctx context.Context, extra map[string]interface{}) (bool, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return false, err
 	}
@@ -292,7 +291,7 @@ func (gpc *gpioPinClient) Get(ctx context.Context, extra map[string]interface{})
 }
 
 func (gpc *gpioPinClient) PWM(ctx context.Context, extra map[string]interface{}) (float64, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return math.NaN(), err
 	}
@@ -308,7 +307,7 @@ func (gpc *gpioPinClient) PWM(ctx context.Context, extra map[string]interface{})
 }
 
 func (gpc *gpioPinClient) SetPWM(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -322,7 +321,7 @@ func (gpc *gpioPinClient) SetPWM(ctx context.Context, dutyCyclePct float64, extr
 }
 
 func (gpc *gpioPinClient) PWMFreq(ctx context.Context, extra map[string]interface{}) (uint, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return 0, err
 	}
@@ -338,7 +337,7 @@ func (gpc *gpioPinClient) PWMFreq(ctx context.Context, extra map[string]interfac
 }
 
 func (gpc *gpioPinClient) SetPWMFreq(ctx context.Context, freqHz uint, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -101,6 +101,7 @@ func NewBoard(ctx context.Context, config config.Component, logger golog.Logger)
 		Digitals: map[string]board.DigitalInterrupt{},
 		GPIOPins: map[string]*GPIOPin{},
 	}
+	b.health.SetState(resource.HealthStateReady, nil)
 
 	for _, c := range boardConfig.I2Cs {
 		b.I2Cs[c.Name] = &I2C{}
@@ -146,6 +147,12 @@ type Board struct {
 	GPIOPins map[string]*GPIOPin
 
 	CloseCount int
+	health     resource.HealthTracker
+}
+
+// Health returns the health of the board, which fails if its parts could not be closed.
+func (b *Board) Health(ctx context.Context) (resource.Health, error) {
+	return b.health.Health(ctx)
 }
 
 // SPIByName returns the SPI by the given name if it exists.
@@ -250,6 +257,9 @@ func (b *Board) Close(ctx context.Context) error {
 	for _, digital := range b.Digitals {
 		err = multierr.Combine(err, utils.TryClose(ctx, digital))
 	}
+	if err != nil {
+		b.health.SetState(resource.HealthStateFailed, err)
+	}
 	return err
 }
 
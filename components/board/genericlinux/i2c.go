@@ -3,6 +3,7 @@ package genericlinux
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"periph.io/x/conn/v3/i2c"
@@ -10,8 +11,16 @@ import (
 	"periph.io/x/host/v3"
 
 	"go.viam.com/rdk/components/board"
+	rutils "go.viam.com/rdk/utils"
 )
 
+// i2cRetryOptions retry transactions a few times when the bus is busy or loses arbitration.
+var i2cRetryOptions = rutils.RetryOptions{
+	Backoff:     rutils.Backoff{Initial: time.Millisecond, Max: 10 * time.Millisecond},
+	MaxAttempts: 3,
+	Retryable:   rutils.IsTemporary,
+}
+
 func init() {
 	if _, err := host.Init(); err != nil {
 		golog.Global().Debugw("error initializing host", "error", err)
@@ -47,13 +56,20 @@ type i2cHandle struct { // Implements the board.I2CHandle interface
 	mu     *sync.Mutex // Points to the i2cBus' mutex
 }
 
+// tx runs a transaction on the device, retrying it if the bus is temporarily unavailable.
+func (h *i2cHandle) tx(ctx context.Context, w, r []byte) error {
+	return rutils.Retry(ctx, i2cRetryOptions, func(ctx context.Context) error {
+		return h.device.Tx(w, r)
+	})
+}
+
 func (h *i2cHandle) Write(ctx context.Context, tx []byte) error {
-	return h.device.Tx(tx, nil)
+	return h.tx(ctx, tx, nil)
 }
 
 func (h *i2cHandle) Read(ctx context.Context, count int) ([]byte, error) {
 	buffer := make([]byte, count)
-	err := h.device.Tx(nil, buffer)
+	err := h.tx(ctx, nil, buffer)
 	if err != nil {
 		return nil, err
 	}
@@ -61,19 +77,19 @@ func (h *i2cHandle) Read(ctx context.Context, count int) ([]byte, error) {
 }
 
 // This is a private helper function, used to implement the rest of the board.I2CHandle interface.
-func (h *i2cHandle) transactAtRegister(register byte, w, r []byte) error {
+func (h *i2cHandle) transactAtRegister(ctx context.Context, register byte, w, r []byte) error {
 	if w == nil {
 		w = []byte{}
 	}
 	fullW := make([]byte, len(w)+1)
 	fullW[0] = register
 	copy(fullW[1:], w)
-	return h.device.Tx(fullW, r)
+	return h.tx(ctx, fullW, r)
 }
 
 func (h *i2cHandle) ReadByteData(ctx context.Context, register byte) (byte, error) {
 	result := make([]byte, 1)
-	err := h.transactAtRegister(register, nil, result)
+	err := h.transactAtRegister(ctx, register, nil, result)
 	if err != nil {
 		return 0, err
 	}
@@ -81,12 +97,12 @@ func (h *i2cHandle) ReadByteData(ctx context.Context, register byte) (byte, erro
 }
 
 func (h *i2cHandle) WriteByteData(ctx context.Context, register, data byte) error {
-	return h.transactAtRegister(register, []byte{data}, nil)
+	return h.transactAtRegister(ctx, register, []byte{data}, nil)
 }
 
 func (h *i2cHandle) ReadBlockData(ctx context.Context, register byte, numBytes uint8) ([]byte, error) {
 	result := make([]byte, numBytes)
-	err := h.transactAtRegister(register, nil, result)
+	err := h.transactAtRegister(ctx, register, nil, result)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +110,7 @@ func (h *i2cHandle) ReadBlockData(ctx context.Context, register byte, numBytes u
 }
 
 func (h *i2cHandle) WriteBlockData(ctx context.Context, register byte, data []byte) error {
-	return h.transactAtRegister(register, data, nil)
+	return h.transactAtRegister(ctx, register, data, nil)
 }
 
 func (h *i2cHandle) Close() error {
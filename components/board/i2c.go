@@ -41,3 +41,27 @@ func (reg *I2CRegister) ReadByteData(ctx context.Context) (byte, error) {
 func (reg *I2CRegister) WriteByteData(ctx context.Context, data byte) error {
 	return reg.Handle.WriteByteData(ctx, reg.Register, data)
 }
+
+// ScanI2C returns the addresses among addrs of the devices on the bus that respond to a one
+// byte read. Reading can change the state of some devices, so only the addresses of devices
+// expected on the bus should be scanned. Devices that ignore reads until written to are not
+// found.
+func ScanI2C(ctx context.Context, bus I2C, addrs []byte) ([]byte, error) {
+	var found []byte
+	for _, addr := range addrs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		handle, err := bus.OpenHandle(addr)
+		if err != nil {
+			continue
+		}
+		if _, err := handle.Read(ctx, 1); err == nil {
+			found = append(found, addr)
+		}
+		if err := handle.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
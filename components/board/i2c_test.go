@@ -0,0 +1,51 @@
+package board_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+type scanHandle struct {
+	board.I2CHandle
+	present bool
+}
+
+func (h *scanHandle) Read(ctx context.Context, count int) ([]byte, error) {
+	if !h.present {
+		return nil, errors.New("no device")
+	}
+	return make([]byte, count), nil
+}
+
+func (h *scanHandle) Close() error {
+	return nil
+}
+
+func TestScanI2C(t *testing.T) {
+	bus := &inject.I2C{}
+	var opened []byte
+	bus.OpenHandleFunc = func(addr byte) (board.I2CHandle, error) {
+		opened = append(opened, addr)
+		if addr == 0x10 {
+			return nil, errors.New("bus busy")
+		}
+		return &scanHandle{present: addr == 0x08 || addr == 0x68 || addr == 0x77}, nil
+	}
+
+	addrs, err := board.ScanI2C(context.Background(), bus, []byte{0x08, 0x10, 0x20, 0x68, 0x77})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, addrs, test.ShouldResemble, []byte{0x08, 0x68, 0x77})
+	// only the given addresses are read
+	test.That(t, opened, test.ShouldResemble, []byte{0x08, 0x10, 0x20, 0x68, 0x77})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = board.ScanI2C(ctx, bus, []byte{0x08})
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
@@ -114,6 +114,34 @@ type PointCloudSource interface {
 	NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error)
 }
 
+// A PointCloudStreamer can hand out its next point cloud in chunks of points, without holding
+// all of it in memory at once.
+type PointCloudStreamer interface {
+	// StreamPointCloud calls fn with the points of the next point cloud in chunks of up to
+	// chunkSize points, like pointcloud.IterateChunks.
+	StreamPointCloud(ctx context.Context, chunkSize int, fn func(chunk []pointcloud.PointAndData) bool) error
+}
+
+// StreamNextPointCloud calls fn with the points of the next point cloud of a source in chunks
+// of up to chunkSize points. Sources that are not PointCloudStreamers have their whole point
+// cloud read first.
+func StreamNextPointCloud(
+	ctx context.Context,
+	src PointCloudSource,
+	chunkSize int,
+	fn func(chunk []pointcloud.PointAndData) bool,
+) error {
+	if streamer, ok := src.(PointCloudStreamer); ok {
+		return streamer.StreamPointCloud(ctx, chunkSize, fn)
+	}
+	pc, err := src.NextPointCloud(ctx)
+	if err != nil {
+		return err
+	}
+	pointcloud.IterateChunks(pc, chunkSize, fn)
+	return nil
+}
+
 // NewFromReader creates a Camera either with or without a projector. The stream type
 // argument is for detecting whether or not the resulting camera supports return
 // of pointcloud data in the absence of an implemented NextPointCloud function.
@@ -351,6 +379,7 @@ type LivenessMonitor interface {
 
 var (
 	_ = Camera(&reconfigurableCamera{})
+	_ = PointCloudStreamer(&reconfigurableCamera{})
 	_ = resource.Reconfigurable(&reconfigurableCamera{})
 	_ = viamutils.ContextCloser(&reconfigurableCamera{})
 )
@@ -464,6 +493,16 @@ func (c *reconfigurableCamera) NextPointCloud(ctx context.Context) (pointcloud.P
 	return c.actual.NextPointCloud(ctx)
 }
 
+func (c *reconfigurableCamera) StreamPointCloud(
+	ctx context.Context,
+	chunkSize int,
+	fn func(chunk []pointcloud.PointAndData) bool,
+) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return StreamNextPointCloud(ctx, c.actual, chunkSize, fn)
+}
+
 func (c *reconfigurableCamera) Projector(ctx context.Context) (transform.Projector, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -124,12 +124,44 @@ func (c *client) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, err
 	ctx, span := trace.StartSpan(ctx, "camera::client::NextPointCloud")
 	defer span.End()
 
-	ctx, getPcdSpan := trace.StartSpan(ctx, "camera::client::NextPointCloud::GetPointCloud")
+	pcd, err := c.getPointCloudPCD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() (pointcloud.PointCloud, error) {
+		_, span := trace.StartSpan(ctx, "camera::client::NextPointCloud::ReadPCD")
+		defer span.End()
+
+		return pointcloud.ReadPCD(bytes.NewReader(pcd))
+	}()
+}
+
+// StreamPointCloud decodes the points of the next point cloud in chunks rather than into a
+// point cloud, which takes far less memory than the cloud would for big clouds.
+func (c *client) StreamPointCloud(
+	ctx context.Context,
+	chunkSize int,
+	fn func(chunk []pointcloud.PointAndData) bool,
+) error {
+	ctx, span := trace.StartSpan(ctx, "camera::client::StreamPointCloud")
+	defer span.End()
+
+	pcd, err := c.getPointCloudPCD(ctx)
+	if err != nil {
+		return err
+	}
+	return pointcloud.StreamPCD(bytes.NewReader(pcd), chunkSize, fn)
+}
+
+func (c *client) getPointCloudPCD(ctx context.Context) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "camera::client::GetPointCloud")
+	defer span.End()
+
 	resp, err := c.client.GetPointCloud(ctx, &pb.GetPointCloudRequest{
 		Name:     c.name,
 		MimeType: utils.MimeTypePCD,
 	})
-	getPcdSpan.End()
 	if err != nil {
 		return nil, err
 	}
@@ -137,13 +169,7 @@ func (c *client) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, err
 	if resp.MimeType != utils.MimeTypePCD {
 		return nil, fmt.Errorf("unknown pc mime type %s", resp.MimeType)
 	}
-
-	return func() (pointcloud.PointCloud, error) {
-		_, span := trace.StartSpan(ctx, "camera::client::NextPointCloud::ReadPCD")
-		defer span.End()
-
-		return pointcloud.ReadPCD(bytes.NewReader(resp.PointCloud))
-	}()
+	return resp.PointCloud, nil
 }
 
 func (c *client) Projector(ctx context.Context) (transform.Projector, error) {
@@ -170,6 +170,15 @@ func TestClient(t *testing.T) {
 		_, got := pcB.At(5, 5, 5)
 		test.That(t, got, test.ShouldBeTrue)
 
+		var streamed []pointcloud.PointAndData
+		err = camera.StreamNextPointCloud(context.Background(), camera1Client, 0, func(chunk []pointcloud.PointAndData) bool {
+			streamed = append(streamed, chunk...)
+			return true
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, streamed, test.ShouldHaveLength, 1)
+		test.That(t, streamed[0].P.X, test.ShouldAlmostEqual, 5, 1e-3)
+
 		projB, err := camera1Client.Projector(context.Background())
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, projB, test.ShouldNotBeNil)
@@ -0,0 +1,336 @@
+// Package replay implements a camera that replays images and point clouds captured by the data manager, or
+// exported from the cloud with `viam data export`.
+package replay
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/utils"
+)
+
+var model = resource.NewDefaultModel("replay")
+
+// The names of the methods camera readings are captured by.
+const (
+	readImageMethod      = "ReadImage"
+	nextPointCloudMethod = "NextPointCloud"
+)
+
+var errEndOfRecording = errors.New("replayed the whole recording")
+
+// imageExts are the extensions of exported images that can be replayed.
+var imageExts = map[string]bool{".jpeg": true, ".jpg": true, ".png": true}
+
+const pcdExt = ".pcd"
+
+func init() {
+	registry.RegisterComponent(camera.Subtype, model,
+		registry.Component{Constructor: func(ctx context.Context, _ registry.Dependencies,
+			cfg config.Component, logger golog.Logger,
+		) (interface{}, error) {
+			attrs, ok := cfg.ConvertedAttributes.(*Attrs)
+			if !ok {
+				return nil, utils.NewUnexpectedTypeError(attrs, cfg.ConvertedAttributes)
+			}
+			src, err := newReplaySource(attrs, time.Now)
+			if err != nil {
+				return nil, err
+			}
+			cameraModel := camera.NewPinholeModelWithBrownConradyDistortion(attrs.CameraParameters, attrs.DistortionParameters)
+			return camera.NewFromReader(ctx, src, &cameraModel, camera.UnspecifiedStream)
+		}})
+
+	config.RegisterComponentAttributeMapConverter(camera.Subtype, model,
+		func(attributes config.AttributeMap) (interface{}, error) {
+			var conf Attrs
+			attrs, err := config.TransformAttributeMapToStruct(&conf, attributes)
+			if err != nil {
+				return nil, err
+			}
+			result, ok := attrs.(*Attrs)
+			if !ok {
+				return nil, utils.NewUnexpectedTypeError(result, attrs)
+			}
+			return result, nil
+		},
+		&Attrs{})
+}
+
+// Attrs are the attributes of the replay camera config.
+type Attrs struct {
+	CameraParameters     *transform.PinholeCameraIntrinsics `json:"intrinsic_parameters,omitempty"`
+	DistortionParameters *transform.BrownConrady            `json:"distortion_parameters,omitempty"`
+	// Source is a directory of data capture files, or of data exported from the cloud.
+	Source string `json:"source"`
+	// CameraName picks the recordings of one camera when the capture files have recordings of several.
+	CameraName string `json:"camera_name,omitempty"`
+	// Speed is how many times faster than real time recordings are replayed. Defaults to 1.
+	Speed float64 `json:"speed,omitempty"`
+	// Loop restarts the replay once it has replayed every recording.
+	Loop bool `json:"loop,omitempty"`
+	// Sequential replays the next recording on every read, regardless of when it was recorded, so that no
+	// recording is skipped or repeated however fast or slow they are read.
+	Sequential bool `json:"sequential,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (attrs *Attrs) Validate(path string) ([]string, error) {
+	if attrs.Source == "" {
+		return nil, goutils.NewConfigValidationFieldRequiredError(path, "source")
+	}
+	if attrs.Speed < 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("speed cannot be negative"))
+	}
+	if attrs.Sequential && attrs.Speed != 0 {
+		return nil, goutils.NewConfigValidationError(path, errors.New("speed does not apply to a sequential replay"))
+	}
+	return nil, nil
+}
+
+// recording is an image or point cloud, at its offset from the start of the replay. It is held in memory when it
+// was read from a capture file, and otherwise read from its exported file when it is replayed.
+type recording struct {
+	offset time.Duration
+	data   []byte
+	path   string
+}
+
+func (r recording) bytes() ([]byte, error) {
+	if r.data != nil {
+		return r.data, nil
+	}
+	//nolint:gosec
+	return os.ReadFile(r.path)
+}
+
+// replaySource replays recordings as if they were being taken now, or one after the other when sequential.
+type replaySource struct {
+	images      []recording
+	pointClouds []recording
+	duration    time.Duration
+	speed       float64
+	loop        bool
+	sequential  bool
+	now         func() time.Time
+
+	mu             sync.Mutex
+	start          time.Time
+	nextImage      int
+	nextPointCloud int
+}
+
+func newReplaySource(attrs *Attrs, now func() time.Time) (*replaySource, error) {
+	images, pointClouds, err := readRecordings(attrs.Source, attrs.CameraName)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 && len(pointClouds) == 0 {
+		return nil, errors.Errorf("no recorded images or point clouds found in %q", attrs.Source)
+	}
+
+	// offsets are from the first recording of either kind
+	var start, end time.Time
+	for _, recs := range [][]timedRecording{images, pointClouds} {
+		if len(recs) == 0 {
+			continue
+		}
+		if start.IsZero() || recs[0].time.Before(start) {
+			start = recs[0].time
+		}
+		if last := recs[len(recs)-1].time; last.After(end) {
+			end = last
+		}
+	}
+	toRecordings := func(recs []timedRecording) []recording {
+		out := make([]recording, 0, len(recs))
+		for _, rec := range recs {
+			rec.offset = rec.time.Sub(start)
+			out = append(out, rec.recording)
+		}
+		return out
+	}
+
+	speed := attrs.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	return &replaySource{
+		images:      toRecordings(images),
+		pointClouds: toRecordings(pointClouds),
+		duration:    end.Sub(start),
+		speed:       speed,
+		loop:        attrs.Loop,
+		sequential:  attrs.Sequential,
+		now:         now,
+		start:       now(),
+	}, nil
+}
+
+type timedRecording struct {
+	recording
+	time time.Time
+}
+
+// readRecordings returns the images and point clouds recorded in source, in order of time.
+func readRecordings(source, cameraName string) ([]timedRecording, []timedRecording, error) {
+	var images, pointClouds []timedRecording
+	cameraNames := map[string]bool{}
+	err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		switch {
+		case ext == datacapture.FileExt:
+			//nolint:gosec
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer goutils.UncheckedErrorFunc(f.Close)
+			dcFile, err := datacapture.ReadFile(f)
+			if err != nil {
+				return err
+			}
+			md := dcFile.ReadMetadata()
+			if md.GetComponentType() != camera.Subtype.String() || md.GetType() != v1.DataType_DATA_TYPE_BINARY_SENSOR ||
+				(cameraName != "" && md.GetComponentName() != cameraName) {
+				return nil
+			}
+			data, err := datacapture.SensorDataFromFile(dcFile)
+			if err != nil {
+				return errors.Wrapf(err, "error reading %s", path)
+			}
+			cameraNames[md.GetComponentName()] = true
+			for _, d := range data {
+				rec := timedRecording{
+					recording: recording{data: d.GetBinary()},
+					time:      d.GetMetadata().GetTimeRequested().AsTime(),
+				}
+				if md.GetMethodName() == nextPointCloudMethod || md.GetFileExtension() == pcdExt {
+					pointClouds = append(pointClouds, rec)
+				} else if md.GetMethodName() == readImageMethod {
+					images = append(images, rec)
+				}
+			}
+		case imageExts[ext] || ext == pcdExt:
+			// exported files are named by the time they were recorded at, then an underscore
+			name := filepath.Base(path)
+			recorded, err := time.Parse(time.RFC3339Nano, strings.SplitN(name, "_", 2)[0])
+			if err != nil {
+				return errors.Wrapf(err, "%s is not named by the time it was recorded at", path)
+			}
+			rec := timedRecording{recording: recording{path: path}, time: recorded}
+			if ext == pcdExt {
+				pointClouds = append(pointClouds, rec)
+			} else {
+				images = append(images, rec)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(cameraNames) > 1 {
+		return nil, nil, errors.Errorf("%q has recordings of %d cameras, camera_name must pick one", source, len(cameraNames))
+	}
+	for _, recs := range [][]timedRecording{images, pointClouds} {
+		recs := recs
+		sort.SliceStable(recs, func(i, j int) bool {
+			return recs[i].time.Before(recs[j].time)
+		})
+	}
+	return images, pointClouds, nil
+}
+
+// current returns the recording that is due, and advances a sequential replay past it.
+func (rs *replaySource) current(recs []recording, next *int) (recording, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.sequential {
+		if *next == len(recs) {
+			if !rs.loop {
+				return recording{}, errEndOfRecording
+			}
+			*next = 0
+		}
+		*next++
+		return recs[*next-1], nil
+	}
+
+	elapsed := time.Duration(float64(rs.now().Sub(rs.start)) * rs.speed)
+	if rs.loop && rs.duration > 0 {
+		elapsed %= rs.duration
+	}
+	due := sort.Search(len(recs), func(i int) bool {
+		return recs[i].offset > elapsed
+	})
+	return recs[utils.MaxInt(due-1, 0)], nil
+}
+
+// Read returns the recorded image that is due.
+func (rs *replaySource) Read(ctx context.Context) (image.Image, func(), error) {
+	if len(rs.images) == 0 {
+		return nil, nil, errors.New("no images were recorded")
+	}
+	rec, err := rs.current(rs.images, &rs.nextImage)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := rec.bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	img, err := rimage.DecodeImage(ctx, data, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, func() {}, nil
+}
+
+// NextPointCloud returns the recorded point cloud that is due.
+func (rs *replaySource) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	if len(rs.pointClouds) == 0 {
+		return nil, errors.New("no point clouds were recorded")
+	}
+	rec, err := rs.current(rs.pointClouds, &rs.nextPointCloud)
+	if err != nil {
+		return nil, err
+	}
+	data, err := rec.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return pointcloud.ReadPCD(bytes.NewReader(data))
+}
+
+// Close does nothing, as recordings are read when they are replayed.
+func (rs *replaySource) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,196 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+var recordingStart = time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// encodedImage returns a png of a single pixel, whose red value identifies it.
+func encodedImage(t *testing.T, red uint8) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.NRGBA{R: red, A: 255})
+	var buf bytes.Buffer
+	test.That(t, png.Encode(&buf, img), test.ShouldBeNil)
+	return buf.Bytes()
+}
+
+// encodedPointCloud returns a pcd of a single point, whose x identifies it.
+func encodedPointCloud(t *testing.T, x float64) []byte {
+	t.Helper()
+	pc := pointcloud.New()
+	test.That(t, pc.Set(r3.Vector{X: x}, nil), test.ShouldBeNil)
+	var buf bytes.Buffer
+	test.That(t, pointcloud.ToPCD(pc, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+	return buf.Bytes()
+}
+
+// writeCapture writes recordings of a method of a camera, taken every second from the start of the recording, to a
+// data capture file in dir.
+func writeCapture(t *testing.T, dir, cameraName, method string, recordings [][]byte) {
+	t.Helper()
+	md, err := datacapture.BuildCaptureMetadata(camera.Subtype, cameraName, resource.NewDefaultModel("webcam"),
+		method, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	f, err := datacapture.NewFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	for i, rec := range recordings {
+		requested := recordingStart.Add(time.Duration(i) * time.Second)
+		test.That(t, f.WriteNext(&v1.SensorData{
+			Metadata: &v1.SensorMetadata{
+				TimeRequested: timestamppb.New(requested),
+				TimeReceived:  timestamppb.New(requested),
+			},
+			Data: &v1.SensorData_Binary{Binary: rec},
+		}), test.ShouldBeNil)
+	}
+	test.That(t, f.Close(), test.ShouldBeNil)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func readRed(t *testing.T, rs *replaySource) uint8 {
+	t.Helper()
+	img, release, err := rs.Read(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	defer release()
+	r, _, _, _ := img.At(0, 0).RGBA()
+	return uint8(r >> 8)
+}
+
+func readX(t *testing.T, rs *replaySource) float64 {
+	t.Helper()
+	pc, err := rs.NextPointCloud(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pc.Size(), test.ShouldEqual, 1)
+	var x float64
+	pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		x = p.X
+		return true
+	})
+	return x
+}
+
+func TestValidate(t *testing.T) {
+	_, err := (&Attrs{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&Attrs{Source: "dir", Speed: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&Attrs{Source: "dir", Speed: 2, Sequential: true}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&Attrs{Source: "dir", Speed: 2, Loop: true}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestReplayCaptured(t *testing.T) {
+	dir := t.TempDir()
+	writeCapture(t, dir, "cam1", readImageMethod, [][]byte{encodedImage(t, 10), encodedImage(t, 20), encodedImage(t, 30)})
+	writeCapture(t, dir, "cam1", nextPointCloudMethod, [][]byte{encodedPointCloud(t, 1), encodedPointCloud(t, 2)})
+
+	clock := &fakeClock{now: time.Now()}
+	rs, err := newReplaySource(&Attrs{Source: dir}, clock.Now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readRed(t, rs), test.ShouldEqual, 10)
+	test.That(t, readX(t, rs), test.ShouldEqual, 1)
+
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	test.That(t, readRed(t, rs), test.ShouldEqual, 20)
+	test.That(t, readRed(t, rs), test.ShouldEqual, 20)
+	test.That(t, readX(t, rs), test.ShouldEqual, 2)
+
+	// the last recordings are held once the recording ends
+	clock.now = clock.now.Add(time.Hour)
+	test.That(t, readRed(t, rs), test.ShouldEqual, 30)
+	test.That(t, readX(t, rs), test.ShouldEqual, 2)
+
+	t.Run("accelerated and looping", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		rs, err := newReplaySource(&Attrs{Source: dir, Speed: 4, Loop: true}, clock.Now)
+		test.That(t, err, test.ShouldBeNil)
+		clock.now = clock.now.Add(250 * time.Millisecond)
+		test.That(t, readRed(t, rs), test.ShouldEqual, 20)
+		// the replay lasts two seconds, so three seconds in is a second into the second loop
+		clock.now = clock.now.Add(500 * time.Millisecond)
+		test.That(t, readRed(t, rs), test.ShouldEqual, 20)
+	})
+
+	t.Run("sequential", func(t *testing.T) {
+		rs, err := newReplaySource(&Attrs{Source: dir, Sequential: true}, time.Now)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readX(t, rs), test.ShouldEqual, 1)
+		test.That(t, readX(t, rs), test.ShouldEqual, 2)
+		_, err = rs.NextPointCloud(context.Background())
+		test.That(t, err, test.ShouldBeError, errEndOfRecording)
+		test.That(t, readRed(t, rs), test.ShouldEqual, 10)
+
+		rs, err = newReplaySource(&Attrs{Source: dir, Sequential: true, Loop: true}, time.Now)
+		test.That(t, err, test.ShouldBeNil)
+		for _, expected := range []float64{1, 2, 1, 2} {
+			test.That(t, readX(t, rs), test.ShouldEqual, expected)
+		}
+	})
+}
+
+func TestReplayExported(t *testing.T) {
+	dir := t.TempDir()
+	dataDir := filepath.Join(dir, "data")
+	test.That(t, os.MkdirAll(dataDir, 0o700), test.ShouldBeNil)
+	// written out of order, to be replayed in order of time
+	for i, red := range []uint8{30, 10, 20} {
+		recorded := recordingStart.Add(time.Duration(red/10) * time.Second).Format(time.RFC3339Nano)
+		name := filepath.Join(dataDir, recorded+"_"+string(rune('a'+i))+".png")
+		test.That(t, os.WriteFile(name, encodedImage(t, red), 0o600), test.ShouldBeNil)
+	}
+
+	rs, err := newReplaySource(&Attrs{Source: dir, Sequential: true}, time.Now)
+	test.That(t, err, test.ShouldBeNil)
+	for _, expected := range []uint8{10, 20, 30} {
+		test.That(t, readRed(t, rs), test.ShouldEqual, expected)
+	}
+	_, err = rs.NextPointCloud(context.Background())
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, os.WriteFile(filepath.Join(dataDir, "untimed.png"), encodedImage(t, 0), 0o600), test.ShouldBeNil)
+	_, err = newReplaySource(&Attrs{Source: dir}, time.Now)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestReplayCameraSelection(t *testing.T) {
+	dir := t.TempDir()
+	_, err := newReplaySource(&Attrs{Source: dir}, time.Now)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	writeCapture(t, dir, "cam1", readImageMethod, [][]byte{encodedImage(t, 10)})
+	writeCapture(t, dir, "cam2", readImageMethod, [][]byte{encodedImage(t, 20)})
+	_, err = newReplaySource(&Attrs{Source: dir}, time.Now)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	rs, err := newReplaySource(&Attrs{Source: dir, CameraName: "cam2"}, time.Now)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readRed(t, rs), test.ShouldEqual, 20)
+}
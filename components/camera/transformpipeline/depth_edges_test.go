@@ -89,7 +89,7 @@ func (h *depthSourceTestHelper) Process(
 
 	// preprocess depth map
 	source = &videosource.StaticSource{DepthImg: dm}
-	rs, stream, err := newDepthPreprocessTransform(context.Background(), gostream.NewVideoSource(source, prop.Video{}))
+	rs, stream, err := newDepthPreprocessTransform(context.Background(), gostream.NewVideoSource(source, prop.Video{}), config.AttributeMap{})
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, stream, test.ShouldEqual, camera.DepthStream)
 
@@ -52,6 +52,10 @@ func (rs *rotateSource) Read(ctx context.Context) (image.Image, func(), error) {
 	}
 	switch rs.stream {
 	case camera.ColorStream, camera.UnspecifiedStream:
+		// keep YUV frames as they are for encoders rather than converting them to RGB
+		if ycbcr, ok := orig.(*image.YCbCr); ok {
+			return rimage.RotateYCbCr180(ycbcr), release, nil
+		}
 		return imaging.Rotate(orig, 180, color.Black), release, nil
 	case camera.DepthStream:
 		dm, err := rimage.ConvertImageToDepthMap(ctx, orig)
@@ -116,6 +120,9 @@ func (rs *resizeSource) Read(ctx context.Context) (image.Image, func(), error) {
 	}
 	switch rs.stream {
 	case camera.ColorStream, camera.UnspecifiedStream:
+		if ycbcr, ok := orig.(*image.YCbCr); ok {
+			return rimage.ResizeYCbCr(ycbcr, rs.width, rs.height), release, nil
+		}
 		dst := image.NewRGBA(image.Rect(0, 0, rs.width, rs.height))
 		draw.NearestNeighbor.Scale(dst, dst.Bounds(), orig, orig.Bounds(), draw.Over, nil)
 		return dst, release, nil
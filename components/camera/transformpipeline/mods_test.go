@@ -147,6 +147,35 @@ func TestRotateDepthSource(t *testing.T) {
 	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestRotateAndResizeYCbCr(t *testing.T) {
+	img, err := rimage.NV12ToYCbCr(make([]byte, 64*48*3/2), 64, 48)
+	test.That(t, err, test.ShouldBeNil)
+	img.Y[0] = 200
+	source := gostream.NewVideoSource(&videosource.StaticSource{ColorImg: img}, prop.Video{})
+
+	rs, _, err := newRotateTransform(context.Background(), source, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	rotated, _, err := camera.ReadImage(context.Background(), rs)
+	test.That(t, err, test.ShouldBeNil)
+	// YUV frames are not converted to RGB
+	rotatedYCbCr, ok := rotated.(*image.YCbCr)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, rotatedYCbCr.YCbCrAt(63, 47).Y, test.ShouldEqual, 200)
+	test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+
+	am := config.AttributeMap{"height_px": 24, "width_px": 32}
+	rs, _, err = newResizeTransform(context.Background(), source, camera.ColorStream, am)
+	test.That(t, err, test.ShouldBeNil)
+	resized, _, err := camera.ReadImage(context.Background(), rs)
+	test.That(t, err, test.ShouldBeNil)
+	resizedYCbCr, ok := resized.(*image.YCbCr)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resizedYCbCr.Bounds(), test.ShouldResemble, image.Rect(0, 0, 32, 24))
+	test.That(t, resizedYCbCr.YCbCrAt(0, 0).Y, test.ShouldEqual, 200)
+	test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, source.Close(context.Background()), test.ShouldBeNil)
+}
+
 func BenchmarkColorRotate(b *testing.B) {
 	img, err := rimage.NewImageFromFile(artifact.MustPath("rimage/board1.png"))
 	test.That(b, err, test.ShouldBeNil)
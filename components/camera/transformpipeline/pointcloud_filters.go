@@ -0,0 +1,83 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+
+	"github.com/edaniels/gostream"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/rimage/transform"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+type pointCloudFiltersAttrs struct {
+	Filters []pointcloud.FilterConfig `json:"filters"`
+}
+
+// pointCloudFiltersSource passes images through, and filters the point clouds of its source.
+type pointCloudFiltersSource struct {
+	src    camera.PointCloudSource
+	stream gostream.VideoStream
+	filter pointcloud.Filter
+}
+
+func newPointCloudFiltersTransform(
+	ctx context.Context, source gostream.VideoSource, stream camera.ImageType, am config.AttributeMap,
+) (gostream.VideoSource, camera.ImageType, error) {
+	conf, err := config.TransformAttributeMapToStruct(&(pointCloudFiltersAttrs{}), am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	attrs, ok := conf.(*pointCloudFiltersAttrs)
+	if !ok {
+		return nil, camera.UnspecifiedStream, rdkutils.NewUnexpectedTypeError(attrs, conf)
+	}
+	src, ok := source.(camera.PointCloudSource)
+	if !ok {
+		return nil, camera.UnspecifiedStream, errors.New("source of point cloud filters transform does not have PointCloud method")
+	}
+	filter, err := pointcloud.FiltersFromConfigs(attrs.Filters)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	props, err := propsFromVideoSource(ctx, source)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	var cameraModel transform.PinholeCameraModel
+	cameraModel.PinholeCameraIntrinsics = props.IntrinsicParams
+
+	if props.DistortionParams != nil {
+		cameraModel.Distortion = props.DistortionParams
+	}
+	reader := &pointCloudFiltersSource{src, gostream.NewEmbeddedVideoStream(source), filter}
+	cam, err := camera.NewFromReader(ctx, reader, &cameraModel, stream)
+	return cam, stream, err
+}
+
+// Read returns the next image of the source unchanged.
+func (pfs *pointCloudFiltersSource) Read(ctx context.Context) (image.Image, func(), error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::pointCloudFilters::Read")
+	defer span.End()
+	return pfs.stream.Next(ctx)
+}
+
+// NextPointCloud applies the filters to the next point cloud of the source.
+func (pfs *pointCloudFiltersSource) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	ctx, span := trace.StartSpan(ctx, "camera::transformpipeline::pointCloudFilters::NextPointCloud")
+	defer span.End()
+	pc, err := pfs.src.NextPointCloud(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pfs.filter(pc)
+}
+
+func (pfs *pointCloudFiltersSource) Close(ctx context.Context) error {
+	return pfs.stream.Close(ctx)
+}
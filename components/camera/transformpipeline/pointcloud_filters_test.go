@@ -0,0 +1,57 @@
+package transformpipeline
+
+import (
+	"context"
+	"image"
+	"testing"
+
+	"github.com/edaniels/gostream"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestPointCloudFilters(t *testing.T) {
+	cloudSource := &inject.Camera{}
+	cloudSource.NextPointCloudFunc = func(ctx context.Context) (pointcloud.PointCloud, error) {
+		p := pointcloud.New()
+		for z := 0.; z < 10; z++ {
+			if err := p.Set(pointcloud.NewVector(0, 0, z*100), pointcloud.NewBasicData()); err != nil {
+				return nil, err
+			}
+		}
+		return p, nil
+	}
+	cloudSource.StreamFunc = func(ctx context.Context, errHandlers ...gostream.ErrorHandler) (gostream.VideoStream, error) {
+		return &streamTest{}, nil
+	}
+	cloudSource.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{SupportsPCD: true}, nil
+	}
+	am := config.AttributeMap{
+		"filters": []interface{}{
+			map[string]interface{}{"type": "passthrough", "axis": "z", "min_mm": 150, "max_mm": 550},
+		},
+	}
+
+	filtered, stream, err := newPointCloudFiltersTransform(context.Background(), cloudSource, camera.ColorStream, am)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stream, test.ShouldEqual, camera.ColorStream)
+	pc, err := filtered.(camera.Camera).NextPointCloud(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pc.Size(), test.ShouldEqual, 4)
+	pic, _, err := camera.ReadImage(context.Background(), filtered)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pic.Bounds(), test.ShouldResemble, image.Rect(0, 0, 1280, 720))
+	test.That(t, filtered.Close(context.Background()), test.ShouldBeNil)
+
+	badAttrs := config.AttributeMap{
+		"filters": []interface{}{map[string]interface{}{"type": "voxel_grid"}},
+	}
+	_, _, err = newPointCloudFiltersTransform(context.Background(), cloudSource, camera.ColorStream, badAttrs)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "voxelSize")
+}
@@ -9,18 +9,77 @@ import (
 	"go.opencensus.io/trace"
 
 	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/rimage/transform"
+	rdkutils "go.viam.com/rdk/utils"
 )
 
+// the smoothing filters that depth preprocessing can apply.
+const (
+	depthSmoothingBilateral = "bilateral"
+	depthSmoothingGuided    = "guided"
+)
+
+// depthPreprocessAttrs configures depth preprocessing. When none of them are set, the default preprocessing, which
+// removes small regions of data and fills and smooths edges with morphological operations, is applied instead.
+type depthPreprocessAttrs struct {
+	// MaxHoleSizePx is the size, in pixels, of the largest holes of missing data to fill in.
+	MaxHoleSizePx int    `json:"max_hole_size_px,omitempty"`
+	Smoothing     string `json:"smoothing,omitempty"`
+	// SpatialSigmaPx and DepthSigmaMm are the parameters of bilateral smoothing.
+	SpatialSigmaPx float64 `json:"spatial_sigma_px,omitempty"`
+	DepthSigmaMm   float64 `json:"depth_sigma_mm,omitempty"`
+	// RadiusPx and EpsilonMm2 are the parameters of guided smoothing.
+	RadiusPx   int     `json:"radius_px,omitempty"`
+	EpsilonMm2 float64 `json:"epsilon_mm2,omitempty"`
+}
+
+func (attrs *depthPreprocessAttrs) isDefault() bool {
+	return attrs.MaxHoleSizePx == 0 && attrs.Smoothing == ""
+}
+
+func (attrs *depthPreprocessAttrs) validate() error {
+	if attrs.MaxHoleSizePx < 0 {
+		return errors.Errorf("max_hole_size_px cannot be negative, got %d", attrs.MaxHoleSizePx)
+	}
+	switch attrs.Smoothing {
+	case "":
+	case depthSmoothingBilateral:
+		if attrs.SpatialSigmaPx <= 0 || attrs.DepthSigmaMm <= 0 {
+			return errors.New("bilateral smoothing needs spatial_sigma_px and depth_sigma_mm greater than 0")
+		}
+	case depthSmoothingGuided:
+		if attrs.RadiusPx <= 0 || attrs.EpsilonMm2 <= 0 {
+			return errors.New("guided smoothing needs radius_px and epsilon_mm2 greater than 0")
+		}
+	default:
+		return errors.Errorf("do not know depth smoothing %q, can be %q or %q",
+			attrs.Smoothing, depthSmoothingBilateral, depthSmoothingGuided)
+	}
+	return nil
+}
+
 // preprocessDepthTransform applies pre-processing functions to depth maps in order to smooth edges and fill holes.
 type preprocessDepthTransform struct {
 	stream gostream.VideoStream
+	attrs  *depthPreprocessAttrs
 }
 
-func newDepthPreprocessTransform(ctx context.Context, source gostream.VideoSource,
+func newDepthPreprocessTransform(ctx context.Context, source gostream.VideoSource, am config.AttributeMap,
 ) (gostream.VideoSource, camera.ImageType, error) {
-	reader := &preprocessDepthTransform{gostream.NewEmbeddedVideoStream(source)}
+	conf, err := config.TransformAttributeMapToStruct(&(depthPreprocessAttrs{}), am)
+	if err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	attrs, ok := conf.(*depthPreprocessAttrs)
+	if !ok {
+		return nil, camera.UnspecifiedStream, rdkutils.NewUnexpectedTypeError(attrs, conf)
+	}
+	if err := attrs.validate(); err != nil {
+		return nil, camera.UnspecifiedStream, err
+	}
+	reader := &preprocessDepthTransform{gostream.NewEmbeddedVideoStream(source), attrs}
 
 	props, err := propsFromVideoSource(ctx, source)
 	if err != nil {
@@ -48,7 +107,20 @@ func (os *preprocessDepthTransform) Read(ctx context.Context) (image.Image, func
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "transform source does not provide depth image")
 	}
-	dm, err = rimage.PreprocessDepthMap(dm, nil)
+	if os.attrs.isDefault() {
+		dm, err = rimage.PreprocessDepthMap(dm, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return dm, release, nil
+	}
+	dm = rimage.FillDepthHoles(dm, os.attrs.MaxHoleSizePx)
+	switch os.attrs.Smoothing {
+	case depthSmoothingBilateral:
+		dm, err = rimage.JointBilateralSmoothing(dm, os.attrs.SpatialSigmaPx, os.attrs.DepthSigmaMm)
+	case depthSmoothingGuided:
+		dm, err = rimage.GuidedSmoothing(dm, os.attrs.RadiusPx, os.attrs.EpsilonMm2)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
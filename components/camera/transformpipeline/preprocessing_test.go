@@ -0,0 +1,54 @@
+package transformpipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/gostream"
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/camera/videosource"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/rimage"
+)
+
+func TestDepthPreprocessFilters(t *testing.T) {
+	dm := rimage.NewEmptyDepthMap(20, 10)
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			dm.Set(x, y, 1000)
+		}
+	}
+	dm.Set(5, 5, 0)
+	dm.Set(6, 5, 0)
+	source := gostream.NewVideoSource(&videosource.StaticSource{DepthImg: dm}, prop.Video{})
+
+	for _, am := range []config.AttributeMap{
+		{"max_hole_size_px": 2},
+		{"max_hole_size_px": 2, "smoothing": "bilateral", "spatial_sigma_px": 1.0, "depth_sigma_mm": 20.0},
+		{"max_hole_size_px": 2, "smoothing": "guided", "radius_px": 2, "epsilon_mm2": 100.0},
+	} {
+		rs, stream, err := newDepthPreprocessTransform(context.Background(), source, am)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stream, test.ShouldEqual, camera.DepthStream)
+		output, _, err := camera.ReadImage(context.Background(), rs)
+		test.That(t, err, test.ShouldBeNil)
+		filled, err := rimage.ConvertImageToDepthMap(context.Background(), output)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, float64(filled.GetDepth(5, 5)), test.ShouldAlmostEqual, 1000, 1)
+		test.That(t, float64(filled.GetDepth(6, 5)), test.ShouldAlmostEqual, 1000, 1)
+		test.That(t, rs.Close(context.Background()), test.ShouldBeNil)
+	}
+
+	for _, am := range []config.AttributeMap{
+		{"max_hole_size_px": -1},
+		{"smoothing": "median"},
+		{"smoothing": "bilateral", "spatial_sigma_px": 1.0},
+		{"smoothing": "guided", "radius_px": 2},
+	} {
+		_, _, err := newDepthPreprocessTransform(context.Background(), source, am)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
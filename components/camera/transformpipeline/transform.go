@@ -17,17 +17,18 @@ type transformType string
 
 // the allowed transforms.
 const (
-	transformTypeUnspecified     = transformType("")
-	transformTypeIdentity        = transformType("identity")
-	transformTypeRotate          = transformType("rotate")
-	transformTypeResize          = transformType("resize")
-	transformTypeDepthPretty     = transformType("depth_to_pretty")
-	transformTypeOverlay         = transformType("overlay")
-	transformTypeUndistort       = transformType("undistort")
-	transformTypeDetections      = transformType("detections")
-	transformTypeClassifications = transformType("classifications")
-	transformTypeDepthEdges      = transformType("depth_edges")
-	transformTypeDepthPreprocess = transformType("depth_preprocess")
+	transformTypeUnspecified       = transformType("")
+	transformTypeIdentity          = transformType("identity")
+	transformTypeRotate            = transformType("rotate")
+	transformTypeResize            = transformType("resize")
+	transformTypeDepthPretty       = transformType("depth_to_pretty")
+	transformTypeOverlay           = transformType("overlay")
+	transformTypeUndistort         = transformType("undistort")
+	transformTypeDetections        = transformType("detections")
+	transformTypeClassifications   = transformType("classifications")
+	transformTypeDepthEdges        = transformType("depth_edges")
+	transformTypeDepthPreprocess   = transformType("depth_preprocess")
+	transformTypePointCloudFilters = transformType("point_cloud_filters")
 )
 
 // emptyAttrs is for transforms that have no attribute fields.
@@ -89,8 +90,14 @@ var registeredTransformConfigs = map[transformType]*transformRegistration{
 	},
 	transformTypeDepthPreprocess: {
 		string(transformTypeDepthPreprocess),
-		&emptyAttrs{},
-		"Applies some basic hole-filling and edge smoothing to a depth map.",
+		&depthPreprocessAttrs{},
+		"Applies some basic hole-filling and edge smoothing to a depth map. Can instead fill holes up to a size, " +
+			"and smooth with a bilateral or guided filter that preserves edges.",
+	},
+	transformTypePointCloudFilters: {
+		string(transformTypePointCloudFilters),
+		&pointCloudFiltersAttrs{},
+		"Applies voxel grid, outlier removal and passthrough filters, in order, to the point clouds of the source.",
 	},
 }
 
@@ -139,7 +146,9 @@ func buildTransform(
 	case transformTypeDepthEdges:
 		return newDepthEdgesTransform(ctx, source, tr.Attributes)
 	case transformTypeDepthPreprocess:
-		return newDepthPreprocessTransform(ctx, source)
+		return newDepthPreprocessTransform(ctx, source, tr.Attributes)
+	case transformTypePointCloudFilters:
+		return newPointCloudFiltersTransform(ctx, source, stream, tr.Attributes)
 	default:
 		return nil, camera.UnspecifiedStream, errors.Errorf("do not know camera transform of type %q", tr.Type)
 	}
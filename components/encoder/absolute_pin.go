@@ -0,0 +1,241 @@
+package encoder
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+var absolutePinModel = resource.NewDefaultModel("absolute-pin")
+
+func init() {
+	registry.RegisterComponent(
+		Subtype,
+		absolutePinModel,
+		registry.Component{
+			Constructor: func(
+				ctx context.Context,
+				deps registry.Dependencies,
+				config config.Component,
+				logger golog.Logger,
+			) (interface{}, error) {
+				return newAbsolutePinEncoder(ctx, deps, config, logger)
+			},
+		},
+	)
+	config.RegisterComponentAttributeMapConverter(
+		Subtype,
+		absolutePinModel,
+		func(attributes config.AttributeMap) (interface{}, error) {
+			var conf AbsolutePinConfig
+			return config.TransformAttributeMapToStruct(&conf, attributes)
+		},
+		&AbsolutePinConfig{},
+	)
+}
+
+// AbsolutePinConfig describes the configuration of an absolute encoder giving its angle on a single pin of a board, either as the
+// duty cycle of a PWM signal read by a digital interrupt, or as a voltage read by an analog reader.
+type AbsolutePinConfig struct {
+	BoardName string `json:"board"`
+	// exactly one of these is set
+	DigitalInterrupt string `json:"digital_interrupt,omitempty"`
+	AnalogReader     string `json:"analog_reader,omitempty"`
+	// the duty cycle, from 0 to 1, or the analog reading at 0 and at 360 degrees, which may be reversed for encoders turning the other
+	// way
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *AbsolutePinConfig) Validate(path string) ([]string, error) {
+	var deps []string
+
+	if len(conf.BoardName) == 0 {
+		return nil, errors.New("expected nonempty board")
+	}
+	if (conf.DigitalInterrupt == "") == (conf.AnalogReader == "") {
+		return nil, errors.New("expected exactly one of digital_interrupt and analog_reader")
+	}
+	if conf.Min == conf.Max {
+		return nil, errors.New("expected different min and max")
+	}
+	if conf.DigitalInterrupt != "" && (conf.Min < 0 || conf.Min > 1 || conf.Max < 0 || conf.Max > 1) {
+		return nil, errors.New("min and max duty cycles must be from 0 to 1")
+	}
+	deps = append(deps, conf.BoardName)
+
+	return deps, nil
+}
+
+// absolutePinEncoder maps the duty cycle or voltage on a pin to an angle, counting whole turns across samples so that its ticks are
+// rotations from zero.
+type absolutePinEncoder struct {
+	mu       sync.Mutex
+	logger   golog.Logger
+	min, max float64
+	turns    turnCounter
+	// whether a whole PWM period has been measured yet
+	measured bool
+
+	interrupt board.DigitalInterrupt
+	analog    board.AnalogReader
+
+	cancelCtx               context.Context
+	cancel                  context.CancelFunc
+	activeBackgroundWorkers sync.WaitGroup
+	generic.Unimplemented
+}
+
+func newAbsolutePinEncoder(
+	ctx context.Context,
+	deps registry.Dependencies,
+	cfg config.Component,
+	logger golog.Logger,
+) (*absolutePinEncoder, error) {
+	attr, ok := cfg.ConvertedAttributes.(*AbsolutePinConfig)
+	if !ok {
+		return nil, rdkutils.NewUnexpectedTypeError(attr, cfg.ConvertedAttributes)
+	}
+	brd, err := board.FromDependencies(deps, attr.BoardName)
+	if err != nil {
+		return nil, err
+	}
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	enc := &absolutePinEncoder{
+		logger:    logger,
+		min:       attr.Min,
+		max:       attr.Max,
+		cancelCtx: cancelCtx,
+		cancel:    cancel,
+	}
+	if attr.DigitalInterrupt != "" {
+		enc.interrupt, ok = brd.DigitalInterruptByName(attr.DigitalInterrupt)
+		if !ok {
+			cancel()
+			return nil, errors.Errorf("cannot find digital interrupt (%s) for absolute pin encoder", attr.DigitalInterrupt)
+		}
+		enc.startPWMLoop()
+		return enc, nil
+	}
+	enc.analog, ok = brd.AnalogReaderByName(attr.AnalogReader)
+	if !ok {
+		cancel()
+		return nil, errors.Errorf("cannot find analog reader (%s) for absolute pin encoder", attr.AnalogReader)
+	}
+	if err := enc.readAnalog(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	enc.startAnalogLoop()
+	return enc, nil
+}
+
+// startPWMLoop measures the duty cycle of each period of the PWM signal, from one rising edge to the next.
+func (enc *absolutePinEncoder) startPWMLoop() {
+	ticks := make(chan board.Tick)
+	enc.interrupt.AddCallback(ticks)
+	enc.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		defer enc.interrupt.RemoveCallback(ticks)
+		var rise, fall uint64
+		var risen, fallen bool
+		for {
+			var tick board.Tick
+			select {
+			case <-enc.cancelCtx.Done():
+				return
+			case tick = <-ticks:
+			}
+			if !tick.High {
+				fall, fallen = tick.TimestampNanosec, risen
+				continue
+			}
+			if fallen && tick.TimestampNanosec > rise {
+				enc.update(float64(fall-rise) / float64(tick.TimestampNanosec-rise))
+			}
+			rise, risen, fallen = tick.TimestampNanosec, true, false
+		}
+	}, enc.activeBackgroundWorkers.Done)
+}
+
+func (enc *absolutePinEncoder) startAnalogLoop() {
+	enc.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		for utils.SelectContextOrWait(enc.cancelCtx, time.Duration(waitTimeNano)) {
+			if err := enc.readAnalog(enc.cancelCtx); err != nil {
+				enc.logger.Errorf("error in position loop (skipping update): %s", err.Error())
+			}
+		}
+	}, enc.activeBackgroundWorkers.Done)
+}
+
+func (enc *absolutePinEncoder) readAnalog(ctx context.Context) error {
+	value, err := enc.analog.Read(ctx, nil)
+	if err != nil {
+		return err
+	}
+	enc.update(float64(value))
+	return nil
+}
+
+// update maps a duty cycle or analog reading to an angle, counting any turns made since the last one.
+func (enc *absolutePinEncoder) update(value float64) {
+	fraction := math.Min(math.Max((value-enc.min)/(enc.max-enc.min), 0), 1)
+	angle := math.Mod(fraction*360, 360)
+
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	if !enc.measured {
+		enc.turns = newTurnCounter(angle)
+		enc.measured = true
+		return
+	}
+	enc.turns.update(angle)
+}
+
+// TicksCount returns the rotations from zero, as with the AM5-AS5048 model. It is an error until a whole PWM period has been
+// measured.
+func (enc *absolutePinEncoder) TicksCount(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	if !enc.measured {
+		return 0, errors.New("absolute pin encoder has not measured a position yet")
+	}
+	return enc.turns.rotations, nil
+}
+
+// Properties returns a single tick per rotation, as its ticks are rotations.
+func (enc *absolutePinEncoder) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	return Properties{TicksPerRotation: 1}, nil
+}
+
+// Reset sets the rotations at the current position to the offset.
+func (enc *absolutePinEncoder) Reset(ctx context.Context, offset float64, extra map[string]interface{}) error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	if !enc.measured {
+		return errors.New("absolute pin encoder has not measured a position yet")
+	}
+	enc.turns.reset(enc.turns.angle, offset)
+	return nil
+}
+
+// Close stops the position loop of the encoder.
+func (enc *absolutePinEncoder) Close() error {
+	enc.cancel()
+	enc.activeBackgroundWorkers.Wait()
+	return nil
+}
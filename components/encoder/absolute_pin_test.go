@@ -0,0 +1,102 @@
+package encoder
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestAbsolutePinEncoderPWM(t *testing.T) {
+	ctx := context.Background()
+	interrupt := &board.BasicDigitalInterrupt{}
+	cancelCtx, cancel := context.WithCancel(ctx)
+	enc := &absolutePinEncoder{min: 0.1, max: 0.9, interrupt: interrupt, cancelCtx: cancelCtx, cancel: cancel}
+	enc.startPWMLoop()
+	defer func() {
+		test.That(t, enc.Close(), test.ShouldBeNil)
+	}()
+
+	_, err := enc.TicksCount(ctx, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// each duty cycle is measured at the rising edge starting the next period
+	now := uint64(0)
+	pwm := func(duties ...float64) {
+		for _, duty := range duties {
+			test.That(t, interrupt.Tick(ctx, true, now), test.ShouldBeNil)
+			test.That(t, interrupt.Tick(ctx, false, now+uint64(duty*1000)), test.ShouldBeNil)
+			now += 1000
+		}
+		test.That(t, interrupt.Tick(ctx, true, now), test.ShouldBeNil)
+	}
+	ticksShouldBe := func(expected float64) {
+		t.Helper()
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			ticks, err := enc.TicksCount(ctx, nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, ticks, test.ShouldAlmostEqual, expected)
+		})
+	}
+
+	pwm(0.5)
+	ticksShouldBe(0.5)
+	pwm(0.7, 0.1, 0.3)
+	ticksShouldBe(1.25)
+	pwm(0.1, 0.7)
+	ticksShouldBe(0.75)
+}
+
+func TestAbsolutePinEncoderAnalog(t *testing.T) {
+	ctx := context.Background()
+	var reading int
+	reader := &inject.AnalogReader{}
+	reader.ReadFunc = func(ctx context.Context, extra map[string]interface{}) (int, error) {
+		return reading, nil
+	}
+	// readings falling from 1000 to 0 across a turn
+	enc := &absolutePinEncoder{min: 1000, max: 0, analog: reader}
+
+	for _, step := range []struct {
+		reading int
+		ticks   float64
+	}{{750, 0.25}, {500, 0.5}, {250, 0.75}, {0, 1}} {
+		reading = step.reading
+		test.That(t, enc.readAnalog(ctx), test.ShouldBeNil)
+		ticks, err := enc.TicksCount(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ticks, test.ShouldAlmostEqual, step.ticks)
+	}
+
+	test.That(t, enc.Reset(ctx, 3, nil), test.ShouldBeNil)
+	reading = 750
+	test.That(t, enc.readAnalog(ctx), test.ShouldBeNil)
+	ticks, err := enc.TicksCount(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticks, test.ShouldAlmostEqual, 3.25)
+}
+
+func TestAbsolutePinConfig(t *testing.T) {
+	conf := AbsolutePinConfig{BoardName: "board", DigitalInterrupt: "pwm", Min: 0.029, Max: 0.971}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"board"})
+
+	conf.AnalogReader = "adc"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	conf.DigitalInterrupt, conf.Min, conf.Max = "", 0, 1023
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	conf.AnalogReader = ""
+	conf.DigitalInterrupt = "pwm"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
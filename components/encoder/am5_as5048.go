@@ -246,9 +246,8 @@ func (enc *AS5048) updatePosition(ctx context.Context) error {
 // TicksCount returns the total number of rotations detected
 // by the encoder (rather than a number of pulse state transitions)
 // because this encoder is absolute and not incremental. As a result
-// a user MUST set ticks_per_rotation on the config of the corresponding
-// motor to 1. Any other value will result in completely incorrect
-// position measurements by the motor.
+// its properties report a single tick per rotation, and the
+// ticks_per_rotation of the corresponding motor, if set, must be 1.
 func (enc *AS5048) TicksCount(
 	ctx context.Context, extra map[string]interface{},
 ) (float64, error) {
@@ -258,6 +257,14 @@ func (enc *AS5048) TicksCount(
 	return ticks, nil
 }
 
+// Properties returns a single tick per rotation, as the ticks
+// of the encoder are rotations.
+func (enc *AS5048) Properties(
+	ctx context.Context, extra map[string]interface{},
+) (Properties, error) {
+	return Properties{TicksPerRotation: 1}, nil
+}
+
 // Reset sets the current position measured by the encoder to be considered
 // its new zero position. If the offset provided is not 0.0, it also
 // sets the positionOffset attribute and adjusts all future recorded
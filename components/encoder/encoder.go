@@ -46,9 +46,29 @@ type Encoder interface {
 	// to be its new zero position.
 	Reset(ctx context.Context, offset float64, extra map[string]interface{}) error
 
+	// Properties returns what is known about the encoder, such as how many ticks it counts in a rotation.
+	Properties(ctx context.Context, extra map[string]interface{}) (Properties, error)
+
 	generic.Generic
 }
 
+// Properties describes an encoder.
+type Properties struct {
+	// TicksPerRotation is how many ticks the encoder counts in a rotation, or 0 if it is not known, in which case its position can
+	// only be had in ticks.
+	TicksPerRotation int
+}
+
+// PositionType is a unit an encoder position can be given in.
+type PositionType string
+
+// The units an encoder position can be given in.
+const (
+	PositionTypeTicks     = PositionType("ticks")
+	PositionTypeDegrees   = PositionType("degrees")
+	PositionTypeRotations = PositionType("rotations")
+)
+
 // Named is a helper for getting the named Encoder's typed resource name.
 func Named(name string) resource.Name {
 	return resource.NameFromSubtype(Subtype, name)
@@ -128,6 +148,12 @@ func (r *reconfigurableEncoder) Reset(ctx context.Context, offset float64, extra
 	return r.actual.Reset(ctx, offset, extra)
 }
 
+func (r *reconfigurableEncoder) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.actual.Properties(ctx, extra)
+}
+
 func (r *reconfigurableEncoder) Close(ctx context.Context) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -177,3 +203,57 @@ func ValidateIntegerOffset(offset float64) error {
 	}
 	return nil
 }
+
+// Position returns the position of an encoder since its last zeroing in the given units, converting its ticks by the ticks per
+// rotation in its properties.
+func Position(ctx context.Context, e Encoder, positionType PositionType, extra map[string]interface{}) (float64, error) {
+	ticks, err := e.TicksCount(ctx, extra)
+	if err != nil {
+		return 0, err
+	}
+	if positionType == PositionTypeTicks {
+		return ticks, nil
+	}
+	props, err := e.Properties(ctx, extra)
+	if err != nil {
+		return 0, err
+	}
+	return ConvertTicks(ticks, props.TicksPerRotation, positionType)
+}
+
+// ConvertTicks converts a number of ticks of an encoder counting ticksPerRotation ticks in a rotation to the given units.
+func ConvertTicks(ticks float64, ticksPerRotation int, positionType PositionType) (float64, error) {
+	if positionType == PositionTypeTicks {
+		return ticks, nil
+	}
+	if ticksPerRotation <= 0 {
+		return 0, errors.Errorf("cannot give position in %s without knowing the ticks per rotation of the encoder", positionType)
+	}
+	rotations := ticks / float64(ticksPerRotation)
+	switch positionType {
+	case PositionTypeRotations:
+		return rotations, nil
+	case PositionTypeDegrees:
+		return rotations * 360, nil
+	default:
+		return 0, errors.Errorf("unknown position type %q", positionType)
+	}
+}
+
+// TicksPerRotation returns how many ticks an encoder counts in a rotation, from its properties if it knows, or else the fallback,
+// such as the ticks_per_rotation configured on the motor it is attached to, which is 0 if unknown. It is an error for the two to
+// disagree.
+func TicksPerRotation(ctx context.Context, e Encoder, fallback int) (int, error) {
+	props, err := e.Properties(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if props.TicksPerRotation == 0 {
+		return fallback, nil
+	}
+	if fallback != 0 && fallback != props.TicksPerRotation {
+		return 0, errors.Errorf(
+			"ticks_per_rotation of %d does not match the %d ticks per rotation of the encoder", fallback, props.TicksPerRotation)
+	}
+	return props.TicksPerRotation, nil
+}
@@ -0,0 +1,53 @@
+package encoder
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestPosition(t *testing.T) {
+	ctx := context.Background()
+	e := &IncrementalEncoder{}
+	test.That(t, e.Reset(ctx, 150, nil), test.ShouldBeNil)
+
+	ticks, err := Position(ctx, e, PositionTypeTicks, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticks, test.ShouldEqual, 150)
+	_, err = Position(ctx, e, PositionTypeRotations, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	e.TicksPerRotation = 100
+	rotations, err := Position(ctx, e, PositionTypeRotations, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rotations, test.ShouldEqual, 1.5)
+	degrees, err := Position(ctx, e, PositionTypeDegrees, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, degrees, test.ShouldEqual, 540)
+	_, err = Position(ctx, e, PositionType("radians"), nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestTicksPerRotation(t *testing.T) {
+	ctx := context.Background()
+	e := &IncrementalEncoder{}
+
+	// with nothing known about the encoder, what the motor is configured with is used
+	ticksPerRotation, err := TicksPerRotation(ctx, e, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticksPerRotation, test.ShouldEqual, 0)
+	ticksPerRotation, err = TicksPerRotation(ctx, e, 200)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticksPerRotation, test.ShouldEqual, 200)
+
+	e.TicksPerRotation = 100
+	ticksPerRotation, err = TicksPerRotation(ctx, e, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticksPerRotation, test.ShouldEqual, 100)
+	ticksPerRotation, err = TicksPerRotation(ctx, e, 100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticksPerRotation, test.ShouldEqual, 100)
+	_, err = TicksPerRotation(ctx, e, 200)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -28,6 +28,7 @@ func init() {
 		) (interface{}, error) {
 			e := &Encoder{}
 			e.updateRate = cfg.ConvertedAttributes.(*AttrConfig).UpdateRate
+			e.ticksPerRotation = cfg.ConvertedAttributes.(*AttrConfig).TicksPerRotation
 
 			e.Start(ctx)
 			return e, nil
@@ -46,7 +47,8 @@ func init() {
 
 // AttrConfig describes the configuration of a fake encoder.
 type AttrConfig struct {
-	UpdateRate int64 `json:"update_rate_msec,omitempty"`
+	UpdateRate       int64 `json:"update_rate_msec,omitempty"`
+	TicksPerRotation int   `json:"ticks_per_rotation,omitempty"`
 }
 
 // Validate ensures all parts of a config is valid.
@@ -60,6 +62,7 @@ type Encoder struct {
 	position                int64
 	speed                   float64 // ticks per minute
 	updateRate              int64   // update position in start every updateRate ms
+	ticksPerRotation        int     // 0 if not known
 	activeBackgroundWorkers sync.WaitGroup
 
 	generic.Unimplemented
@@ -72,6 +75,13 @@ func (e *Encoder) TicksCount(ctx context.Context, extra map[string]interface{})
 	return float64(e.position), nil
 }
 
+// Properties returns the ticks per rotation of the encoder, which is 0 if not configured.
+func (e *Encoder) Properties(ctx context.Context, extra map[string]interface{}) (encoder.Properties, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return encoder.Properties{TicksPerRotation: e.ticksPerRotation}, nil
+}
+
 // Start starts a background thread to run the encoder.
 func (e *Encoder) Start(cancelCtx context.Context) {
 	if e.updateRate == 0 {
@@ -120,6 +130,14 @@ func (e *Encoder) SetSpeed(ctx context.Context, speed float64) error {
 	return nil
 }
 
+// SetTicksPerRotation sets the ticks per rotation the encoder reports in its properties.
+func (e *Encoder) SetTicksPerRotation(ctx context.Context, ticksPerRotation int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ticksPerRotation = ticksPerRotation
+	return nil
+}
+
 // SetPosition sets the position of the encoder.
 func (e *Encoder) SetPosition(ctx context.Context, position int64) error {
 	e.mu.Lock()
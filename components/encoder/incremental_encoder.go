@@ -2,6 +2,7 @@ package encoder
 
 import (
 	"context"
+	"math"
 	"sync"
 	"sync/atomic"
 
@@ -41,6 +42,15 @@ func init() {
 		&IncrementalConfig{})
 }
 
+// The ways an IncrementalEncoder with an index pin can use the pulse it gives once per revolution.
+const (
+	// IndexModeCorrect corrects the tick count at each index pulse to a whole number of revolutions from where the index was first
+	// seen, undoing any drift from missed interrupts. It is the default.
+	IndexModeCorrect = "correct"
+	// IndexModeValidate leaves the tick count as it is, logging any drift found at each index pulse.
+	IndexModeValidate = "validate"
+)
+
 // IncrementalEncoder keeps track of a motor position using a rotary incremental encoder.
 type IncrementalEncoder struct {
 	A, B     board.DigitalInterrupt
@@ -48,6 +58,16 @@ type IncrementalEncoder struct {
 	pRaw     int64
 	pState   int64
 
+	// TicksPerRotation is how many ticks are counted in a rotation, or 0 if not known.
+	TicksPerRotation int64
+	// Z is the optional index pin, pulsed once per revolution, with TicksPerRotation ticks between pulses, used as IndexMode says.
+	Z             board.DigitalInterrupt
+	IndexMode     string
+	indexMu       sync.Mutex
+	indexSeen     bool
+	indexPosition int64
+	indexDrift    int64
+
 	logger                  golog.Logger
 	CancelCtx               context.Context
 	cancelFunc              func()
@@ -60,12 +80,19 @@ type IncrementalEncoder struct {
 type IncrementalPins struct {
 	A string `json:"a"`
 	B string `json:"b"`
+	// Z is the index pin, also known as the Z channel, for encoders which pulse once per revolution.
+	Z string `json:"z,omitempty"`
 }
 
 // IncrementalConfig describes the configuration of a quadrature encoder.
 type IncrementalConfig struct {
 	Pins      IncrementalPins `json:"pins"`
 	BoardName string          `json:"board"`
+
+	// ticks counted in a rotation, which are the ticks between index pulses, required with an index pin
+	TicksPerRotation int `json:"ticks_per_rotation,omitempty"`
+	// how the index pulse is used, either "correct" (the default) or "validate"
+	IndexMode string `json:"index_mode,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -84,6 +111,22 @@ func (config *IncrementalConfig) Validate(path string) ([]string, error) {
 	}
 	deps = append(deps, config.BoardName)
 
+	if config.TicksPerRotation < 0 {
+		return nil, errors.New("ticks_per_rotation cannot be negative")
+	}
+	if config.Pins.Z == "" {
+		if config.IndexMode != "" {
+			return nil, errors.New("index_mode requires an index pin z")
+		}
+	} else if config.TicksPerRotation <= 0 {
+		return nil, errors.New("expected positive ticks_per_rotation with an index pin z")
+	}
+	switch config.IndexMode {
+	case "", IndexModeCorrect, IndexModeValidate:
+	default:
+		return nil, errors.Errorf("unknown index_mode %q, expected %q or %q", config.IndexMode, IndexModeCorrect, IndexModeValidate)
+	}
+
 	return deps, nil
 }
 
@@ -110,6 +153,14 @@ func NewIncrementalEncoder(
 		if !ok {
 			return nil, errors.Errorf("cannot find pin (%s) for incremental Encoder", cfg.Pins.B)
 		}
+		if cfg.Pins.Z != "" {
+			e.Z, ok = board.DigitalInterruptByName(cfg.Pins.Z)
+			if !ok {
+				return nil, errors.Errorf("cannot find pin (%s) for incremental Encoder", cfg.Pins.Z)
+			}
+			e.IndexMode = cfg.IndexMode
+		}
+		e.TicksPerRotation = int64(cfg.TicksPerRotation)
 
 		e.Start(ctx)
 
@@ -162,6 +213,13 @@ func (e *IncrementalEncoder) Start(ctx context.Context) {
 	e.A.AddCallback(chanA)
 	e.B.AddCallback(chanB)
 
+	// without an index pin, chanZ is never ready
+	var chanZ chan board.Tick
+	if e.Z != nil && e.TicksPerRotation > 0 {
+		chanZ = make(chan board.Tick)
+		e.Z.AddCallback(chanZ)
+	}
+
 	aLevel, err := e.A.Value(ctx, nil)
 	if err != nil {
 		utils.Logger.Errorw("error reading a level", "error", err)
@@ -177,6 +235,9 @@ func (e *IncrementalEncoder) Start(ctx context.Context) {
 	utils.ManagedGo(func() {
 		defer e.A.RemoveCallback(chanA)
 		defer e.B.RemoveCallback(chanB)
+		if chanZ != nil {
+			defer e.Z.RemoveCallback(chanZ)
+		}
 		for {
 			select {
 			case <-e.CancelCtx.Done():
@@ -199,6 +260,12 @@ func (e *IncrementalEncoder) Start(ctx context.Context) {
 				if tick.High {
 					bLevel = 1
 				}
+			case tick = <-chanZ:
+				// the index is handled here, between ticks of a and b, so that it sees and corrects a settled count
+				if tick.High {
+					e.index()
+				}
+				continue
 			}
 			nState := aLevel | (bLevel << 1)
 			if e.pState == nState {
@@ -236,6 +303,11 @@ func (e *IncrementalEncoder) TicksCount(ctx context.Context, extra map[string]in
 	return float64(res), nil
 }
 
+// Properties returns the ticks per rotation of the encoder, which is 0 if not configured.
+func (e *IncrementalEncoder) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	return Properties{TicksPerRotation: int(e.TicksPerRotation)}, nil
+}
+
 // Reset sets the current position of the motor (adjusted by a given offset)
 // to be its new zero position..
 func (e *IncrementalEncoder) Reset(ctx context.Context, offset float64, extra map[string]interface{}) error {
@@ -243,11 +315,49 @@ func (e *IncrementalEncoder) Reset(ctx context.Context, offset float64, extra ma
 		return err
 	}
 	offsetInt := int64(offset)
+	e.indexMu.Lock()
+	// the index stays where it is on the encoder, so moves in the count along with everything else
+	e.indexPosition += offsetInt - atomic.LoadInt64(&e.position)
 	atomic.StoreInt64(&e.position, offsetInt)
 	atomic.StoreInt64(&e.pRaw, (offsetInt<<1)|atomic.LoadInt64(&e.pRaw)&0x1)
+	e.indexMu.Unlock()
 	return nil
 }
 
+// index handles a pulse of the index pin, which is at the same place in every revolution.
+func (e *IncrementalEncoder) index() {
+	e.indexMu.Lock()
+	defer e.indexMu.Unlock()
+
+	position := atomic.LoadInt64(&e.position)
+	if !e.indexSeen {
+		e.indexSeen = true
+		e.indexPosition = position
+		return
+	}
+	// the nearest position a whole number of revolutions from where the index was first seen is where the encoder really is
+	revolutions := math.Round(float64(position-e.indexPosition) / float64(e.TicksPerRotation))
+	expected := e.indexPosition + int64(revolutions)*e.TicksPerRotation
+	e.indexDrift = position - expected
+	if e.indexDrift == 0 {
+		return
+	}
+	if e.IndexMode == IndexModeValidate {
+		e.logger.Warnw("incremental encoder drifted from its index", "drift", e.indexDrift)
+		return
+	}
+	atomic.StoreInt64(&e.position, expected)
+	atomic.StoreInt64(&e.pRaw, (expected<<1)|atomic.LoadInt64(&e.pRaw)&0x1)
+}
+
+// IndexDrift returns how many ticks the count had drifted by at the last index pulse, before any correction, which is always 0
+// without an index pin.
+func (e *IncrementalEncoder) IndexDrift() int64 {
+	e.indexMu.Lock()
+	defer e.indexMu.Unlock()
+	return e.indexDrift
+}
+
 // RawPosition returns the raw position of the encoder.
 func (e *IncrementalEncoder) RawPosition() int64 {
 	return atomic.LoadInt64(&e.pRaw)
@@ -0,0 +1,107 @@
+package encoder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/board"
+)
+
+func TestIncrementalEncoderIndex(t *testing.T) {
+	setup := func(t *testing.T, mode string) (*IncrementalEncoder, func(cycles int), func()) {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		a, b, z := &board.BasicDigitalInterrupt{}, &board.BasicDigitalInterrupt{}, &board.BasicDigitalInterrupt{}
+		e := &IncrementalEncoder{
+			A:                a,
+			B:                b,
+			Z:                z,
+			TicksPerRotation: 8,
+			IndexMode:        mode,
+			CancelCtx:        ctx,
+			logger:           golog.NewTestLogger(t),
+		}
+		e.Start(ctx)
+		t.Cleanup(func() {
+			cancel()
+			e.activeBackgroundWorkers.Wait()
+		})
+
+		// each quadrature cycle forward is two ticks
+		forward := func(cycles int) {
+			for i := 0; i < cycles; i++ {
+				test.That(t, b.Tick(ctx, true, 0), test.ShouldBeNil)
+				test.That(t, a.Tick(ctx, true, 0), test.ShouldBeNil)
+				test.That(t, b.Tick(ctx, false, 0), test.ShouldBeNil)
+				test.That(t, a.Tick(ctx, false, 0), test.ShouldBeNil)
+			}
+		}
+		// once the falling edge is received, the pulse has been handled
+		index := func() {
+			test.That(t, z.Tick(ctx, true, 0), test.ShouldBeNil)
+			test.That(t, z.Tick(ctx, false, 0), test.ShouldBeNil)
+		}
+		return e, forward, index
+	}
+	ticksShouldBe := func(t *testing.T, e *IncrementalEncoder, expected float64) {
+		t.Helper()
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			ticks, err := e.TicksCount(context.Background(), nil)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, ticks, test.ShouldEqual, expected)
+		})
+	}
+
+	t.Run("correct", func(t *testing.T) {
+		e, forward, index := setup(t, "")
+		forward(1)
+		index()
+		forward(4)
+		index()
+		ticksShouldBe(t, e, 10)
+		test.That(t, e.IndexDrift(), test.ShouldEqual, 0)
+
+		// a missed cycle is corrected at the next index
+		forward(3)
+		ticksShouldBe(t, e, 16)
+		index()
+		ticksShouldBe(t, e, 18)
+		test.That(t, e.IndexDrift(), test.ShouldEqual, -2)
+
+		// resetting moves where the index is expected along with the count
+		test.That(t, e.Reset(context.Background(), 1, nil), test.ShouldBeNil)
+		forward(4)
+		index()
+		ticksShouldBe(t, e, 9)
+		test.That(t, e.IndexDrift(), test.ShouldEqual, 0)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		e, forward, index := setup(t, IndexModeValidate)
+		index()
+		forward(3)
+		index()
+		ticksShouldBe(t, e, 6)
+		test.That(t, e.IndexDrift(), test.ShouldEqual, -2)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		cfg := IncrementalConfig{Pins: IncrementalPins{A: "a", B: "b", Z: "z"}, BoardName: "board"}
+		_, err := cfg.Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+		cfg.TicksPerRotation = 8
+		_, err = cfg.Validate("")
+		test.That(t, err, test.ShouldBeNil)
+		cfg.IndexMode = "ignore"
+		_, err = cfg.Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+		cfg.Pins.Z, cfg.IndexMode = "", IndexModeValidate
+		_, err = cfg.Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,339 @@
+package encoder
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+var magneticI2CModel = resource.NewDefaultModel("magnetic-i2c")
+
+// The chips supported by the magnetic-i2c encoder model.
+const (
+	AS5600Chip = "as5600"
+	AS5048Chip = "as5048"
+)
+
+// MagnetStatusCommand is the DoCommand command of a magnetic-i2c encoder which returns the health of the magnet it reads.
+const MagnetStatusCommand = "magnet_status"
+
+// magneticChip is the register map of an absolute magnetic encoder. Angles and magnitudes span two registers, the most
+// significant bits in the first and the rest in the lowest bits of the one after it.
+type magneticChip struct {
+	bits              int
+	lowBits           int
+	angleRegister     byte
+	magnitudeRegister byte
+	agcRegister       byte
+	statusRegister    byte
+	// bits of the status register: set when a magnet is found, when the angle read is invalid, and when the field is too weak or
+	// too strong for an accurate angle
+	detectedMask, invalidMask, weakMask, strongMask byte
+}
+
+var magneticChips = map[string]magneticChip{
+	AS5600Chip: {
+		bits:              12,
+		lowBits:           8,
+		angleRegister:     0x0C,
+		magnitudeRegister: 0x1B,
+		agcRegister:       0x1A,
+		statusRegister:    0x0B,
+		detectedMask:      0x20,
+		weakMask:          0x10,
+		strongMask:        0x08,
+	},
+	AS5048Chip: {
+		bits:              14,
+		lowBits:           6,
+		angleRegister:     0xFE,
+		magnitudeRegister: 0xFC,
+		agcRegister:       0xFA,
+		statusRegister:    0xFB,
+		// the AS5048 has no magnet detection of its own, so offset compensation having finished stands in for it
+		detectedMask: 0x01,
+		invalidMask:  0x02,
+		weakMask:     0x08,
+		strongMask:   0x04,
+	},
+}
+
+func init() {
+	registry.RegisterComponent(
+		Subtype,
+		magneticI2CModel,
+		registry.Component{
+			Constructor: func(
+				ctx context.Context,
+				deps registry.Dependencies,
+				config config.Component,
+				logger golog.Logger,
+			) (interface{}, error) {
+				return newMagneticI2CEncoder(ctx, deps, config, logger)
+			},
+		},
+	)
+	config.RegisterComponentAttributeMapConverter(
+		Subtype,
+		magneticI2CModel,
+		func(attributes config.AttributeMap) (interface{}, error) {
+			var conf MagneticI2CConfig
+			return config.TransformAttributeMapToStruct(&conf, attributes)
+		},
+		&MagneticI2CConfig{},
+	)
+}
+
+// MagneticI2CConfig describes the configuration of an absolute magnetic encoder, such as the AS5600 or the AS5048, read over I2C.
+type MagneticI2CConfig struct {
+	BoardName      string `json:"board"`
+	Chip           string `json:"chip"`
+	*I2CAttrConfig `json:"i2c_attributes,omitempty"`
+	// the angle read by the chip, in degrees, which is taken as zero
+	ZeroOffsetDegrees float64 `json:"zero_offset_degrees,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *MagneticI2CConfig) Validate(path string) ([]string, error) {
+	var deps []string
+
+	if len(conf.BoardName) == 0 {
+		return nil, errors.New("expected nonempty board")
+	}
+	if _, ok := magneticChips[conf.Chip]; !ok {
+		return nil, errors.Errorf("unsupported chip %q, expected %q or %q", conf.Chip, AS5600Chip, AS5048Chip)
+	}
+	if conf.I2CAttrConfig == nil {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "i2c_attributes")
+	}
+	if err := conf.I2CAttrConfig.ValidateI2C(path); err != nil {
+		return nil, err
+	}
+	if conf.ZeroOffsetDegrees < 0 || conf.ZeroOffsetDegrees >= 360 {
+		return nil, errors.New("zero_offset_degrees must be at least 0 and less than 360")
+	}
+	deps = append(deps, conf.BoardName)
+
+	return deps, nil
+}
+
+// magneticEncoder reads the absolute angle of a magnet from an encoder chip over I2C, counting whole turns across samples so that
+// its ticks are rotations of the magnet from zero.
+type magneticEncoder struct {
+	mu         sync.Mutex
+	logger     golog.Logger
+	chip       magneticChip
+	i2cBus     board.I2C
+	i2cAddr    byte
+	zeroOffset float64
+	turns      turnCounter
+
+	cancelCtx               context.Context
+	cancel                  context.CancelFunc
+	activeBackgroundWorkers sync.WaitGroup
+	generic.Unimplemented
+}
+
+func newMagneticI2CEncoder(
+	ctx context.Context,
+	deps registry.Dependencies,
+	cfg config.Component,
+	logger golog.Logger,
+) (*magneticEncoder, error) {
+	attr, ok := cfg.ConvertedAttributes.(*MagneticI2CConfig)
+	if !ok {
+		return nil, rdkutils.NewUnexpectedTypeError(attr, cfg.ConvertedAttributes)
+	}
+	brd, err := board.FromDependencies(deps, attr.BoardName)
+	if err != nil {
+		return nil, err
+	}
+	localBoard, ok := brd.(board.LocalBoard)
+	if !ok {
+		return nil, errors.Errorf("board with name %s does not implement the LocalBoard interface", attr.BoardName)
+	}
+	i2c, ok := localBoard.I2CByName(attr.I2CBus)
+	if !ok {
+		return nil, errors.Errorf("unable to find I2C bus: %s", attr.I2CBus)
+	}
+	enc, err := newMagneticEncoder(ctx, i2c, byte(attr.I2CAddr), magneticChips[attr.Chip], attr.ZeroOffsetDegrees, logger)
+	if err != nil {
+		return nil, err
+	}
+	enc.startPositionLoop()
+	return enc, nil
+}
+
+// newMagneticEncoder returns an encoder starting at the angle the chip reads, within the first rotation from zero.
+func newMagneticEncoder(
+	ctx context.Context,
+	bus board.I2C,
+	addr byte,
+	chip magneticChip,
+	zeroOffset float64,
+	logger golog.Logger,
+) (*magneticEncoder, error) {
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	enc := &magneticEncoder{
+		logger:     logger,
+		chip:       chip,
+		i2cBus:     bus,
+		i2cAddr:    addr,
+		zeroOffset: zeroOffset,
+		cancelCtx:  cancelCtx,
+		cancel:     cancel,
+	}
+	angle, err := enc.readAngle(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	enc.turns = newTurnCounter(angle)
+	return enc, nil
+}
+
+func (enc *magneticEncoder) startPositionLoop() {
+	enc.activeBackgroundWorkers.Add(1)
+	utils.ManagedGo(func() {
+		for utils.SelectContextOrWait(enc.cancelCtx, time.Duration(waitTimeNano)) {
+			if err := enc.updatePosition(enc.cancelCtx); err != nil {
+				enc.logger.Errorf("error in position loop (skipping update): %s", err.Error())
+			}
+		}
+	}, enc.activeBackgroundWorkers.Done)
+}
+
+// readAngle returns the angle the chip reads, in degrees from zero.
+func (enc *magneticEncoder) readAngle(ctx context.Context) (float64, error) {
+	raw, err := enc.readValue(ctx, enc.chip.angleRegister)
+	if err != nil {
+		return 0, err
+	}
+	angle := math.Mod(float64(raw)*360/float64(int(1)<<enc.chip.bits)-enc.zeroOffset, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	return angle, nil
+}
+
+// updatePosition reads the angle, counting any turns the magnet made since the last sample.
+func (enc *magneticEncoder) updatePosition(ctx context.Context) error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	angle, err := enc.readAngle(ctx)
+	if err != nil {
+		return err
+	}
+	enc.turns.update(angle)
+	return nil
+}
+
+// TicksCount returns the rotations of the magnet from zero, as with the AM5-AS5048 model.
+func (enc *magneticEncoder) TicksCount(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	return enc.turns.rotations, nil
+}
+
+// Properties returns a single tick per rotation, as its ticks are rotations.
+func (enc *magneticEncoder) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	return Properties{TicksPerRotation: 1}, nil
+}
+
+// Reset sets the rotations at the current position to the offset.
+func (enc *magneticEncoder) Reset(ctx context.Context, offset float64, extra map[string]interface{}) error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	angle, err := enc.readAngle(ctx)
+	if err != nil {
+		return err
+	}
+	enc.turns.reset(angle, offset)
+	return nil
+}
+
+// DoCommand returns the health of the magnet for MagnetStatusCommand: whether the chip detects it, whether its field is too weak
+// or too strong for an accurate angle, and the automatic gain and field magnitude the chip measures it with.
+func (enc *magneticEncoder) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd["command"]
+	if !ok {
+		return nil, errors.New("missing 'command' value")
+	}
+	if name != MagnetStatusCommand {
+		return nil, errors.Errorf("no such command: %s", name)
+	}
+	status, err := enc.readByte(ctx, enc.chip.statusRegister)
+	if err != nil {
+		return nil, err
+	}
+	agc, err := enc.readByte(ctx, enc.chip.agcRegister)
+	if err != nil {
+		return nil, err
+	}
+	magnitude, err := enc.readValue(ctx, enc.chip.magnitudeRegister)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"magnet_detected": status&enc.chip.detectedMask != 0 && status&enc.chip.invalidMask == 0,
+		"too_weak":        status&enc.chip.weakMask != 0,
+		"too_strong":      status&enc.chip.strongMask != 0,
+		"agc":             int(agc),
+		"magnitude":       magnitude,
+	}, nil
+}
+
+// Close stops the position loop of the encoder.
+func (enc *magneticEncoder) Close() error {
+	enc.cancel()
+	enc.activeBackgroundWorkers.Wait()
+	return nil
+}
+
+// readValue reads a value spanning the register and the one after it.
+func (enc *magneticEncoder) readValue(ctx context.Context, register byte) (int, error) {
+	handle, err := enc.i2cBus.OpenHandle(enc.i2cAddr)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := handle.Close(); err != nil {
+			enc.logger.Error(err)
+		}
+	}()
+	high, err := handle.ReadByteData(ctx, register)
+	if err != nil {
+		return 0, err
+	}
+	low, err := handle.ReadByteData(ctx, register+1)
+	if err != nil {
+		return 0, err
+	}
+	value := int(high)<<enc.chip.lowBits | int(low)&(1<<enc.chip.lowBits-1)
+	return value & (1<<enc.chip.bits - 1), nil
+}
+
+func (enc *magneticEncoder) readByte(ctx context.Context, register byte) (byte, error) {
+	handle, err := enc.i2cBus.OpenHandle(enc.i2cAddr)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := handle.Close(); err != nil {
+			enc.logger.Error(err)
+		}
+	}()
+	return handle.ReadByteData(ctx, register)
+}
@@ -0,0 +1,125 @@
+package encoder
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// fakeMagneticChip is the registers of an encoder chip on an I2C bus.
+type fakeMagneticChip struct {
+	board.I2CHandle
+	mu        sync.Mutex
+	registers map[byte]byte
+}
+
+func (c *fakeMagneticChip) ReadByteData(ctx context.Context, register byte) (byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.registers[register], nil
+}
+
+func (c *fakeMagneticChip) Close() error {
+	return nil
+}
+
+func (c *fakeMagneticChip) setAS5600Angle(degrees float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	raw := int(degrees / 360 * 4096)
+	c.registers[0x0C], c.registers[0x0D] = byte(raw>>8), byte(raw)
+}
+
+func TestMagneticEncoder(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ctx := context.Background()
+	chip := &fakeMagneticChip{registers: map[byte]byte{}}
+	bus := &inject.I2C{}
+	bus.OpenHandleFunc = func(addr byte) (board.I2CHandle, error) {
+		return chip, nil
+	}
+	ticksShouldBe := func(enc *magneticEncoder, expected float64) {
+		t.Helper()
+		ticks, err := enc.TicksCount(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ticks, test.ShouldAlmostEqual, expected, 1e-3)
+	}
+
+	chip.setAS5600Angle(270)
+	enc, err := newMagneticEncoder(ctx, bus, 0x36, magneticChips[AS5600Chip], 180, logger)
+	test.That(t, err, test.ShouldBeNil)
+	ticksShouldBe(enc, 0.25)
+
+	// turning forward past zero and back again counts whole turns
+	for _, angle := range []float64{30, 150, 270} {
+		chip.setAS5600Angle(angle)
+		test.That(t, enc.updatePosition(ctx), test.ShouldBeNil)
+	}
+	ticksShouldBe(enc, 1.25)
+	for _, angle := range []float64{150, 30, 270, 150, 30} {
+		chip.setAS5600Angle(angle)
+		test.That(t, enc.updatePosition(ctx), test.ShouldBeNil)
+	}
+	ticksShouldBe(enc, 1.25-5.0/3)
+
+	test.That(t, enc.Reset(ctx, 2, nil), test.ShouldBeNil)
+	ticksShouldBe(enc, 2)
+	chip.setAS5600Angle(120)
+	test.That(t, enc.updatePosition(ctx), test.ShouldBeNil)
+	ticksShouldBe(enc, 2.25)
+
+	chip.registers[0x0B] = 0x20 | 0x10
+	chip.registers[0x1A] = 128
+	chip.registers[0x1B], chip.registers[0x1C] = 0x0A, 0xBC
+	status, err := enc.DoCommand(ctx, map[string]interface{}{"command": MagnetStatusCommand})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status, test.ShouldResemble, map[string]interface{}{
+		"magnet_detected": true,
+		"too_weak":        true,
+		"too_strong":      false,
+		"agc":             128,
+		"magnitude":       0xABC,
+	})
+	_, err = enc.DoCommand(ctx, map[string]interface{}{"command": "calibrate"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, enc.Close(), test.ShouldBeNil)
+}
+
+func TestMagneticEncoderAS5048(t *testing.T) {
+	chip := &fakeMagneticChip{registers: map[byte]byte{0xFE: 0x80, 0xFF: 0x00, 0xFB: 0x01 | 0x02}}
+	bus := &inject.I2C{}
+	bus.OpenHandleFunc = func(addr byte) (board.I2CHandle, error) {
+		return chip, nil
+	}
+	enc, err := newMagneticEncoder(context.Background(), bus, 0x40, magneticChips[AS5048Chip], 0, golog.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	ticks, err := enc.TicksCount(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ticks, test.ShouldEqual, 0.5)
+
+	// a CORDIC overflow means the angle cannot be trusted, even with offset compensation finished
+	status, err := enc.DoCommand(context.Background(), map[string]interface{}{"command": MagnetStatusCommand})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status["magnet_detected"], test.ShouldBeFalse)
+}
+
+func TestMagneticI2CConfig(t *testing.T) {
+	conf := MagneticI2CConfig{BoardName: "board", Chip: AS5600Chip, I2CAttrConfig: &I2CAttrConfig{I2CBus: "1", I2CAddr: 0x36}}
+	deps, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deps, test.ShouldResemble, []string{"board"})
+
+	conf.ZeroOffsetDegrees = 360
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	conf.ZeroOffsetDegrees = 0
+	conf.Chip = "as5047"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -53,6 +53,8 @@ type SingleEncoder struct {
 	I        board.DigitalInterrupt
 	position int64
 	m        DirectionAware
+	// TicksPerRotation is how many ticks are counted in a rotation, or 0 if not known.
+	TicksPerRotation int
 
 	logger                  golog.Logger
 	CancelCtx               context.Context
@@ -69,6 +71,9 @@ type SingleWirePin struct {
 type SingleWireConfig struct {
 	Pins      SingleWirePin `json:"pins"`
 	BoardName string        `json:"board"`
+
+	// ticks counted in a rotation, if known
+	TicksPerRotation int `json:"ticks_per_rotation,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -84,6 +89,10 @@ func (cfg *SingleWireConfig) Validate(path string) ([]string, error) {
 	}
 	deps = append(deps, cfg.BoardName)
 
+	if cfg.TicksPerRotation < 0 {
+		return nil, errors.New("ticks_per_rotation cannot be negative")
+	}
+
 	return deps, nil
 }
 
@@ -111,6 +120,7 @@ func NewSingleEncoder(
 		if !ok {
 			return nil, errors.Errorf("cannot find pin (%s) for SingleEncoder", cfg.Pins.I)
 		}
+		e.TicksPerRotation = cfg.TicksPerRotation
 
 		logger.Info("no direction attached to SingleEncoder yet. SingleEncoder will not take measurements until attached to encoded motor.")
 
@@ -158,6 +168,11 @@ func (e *SingleEncoder) TicksCount(ctx context.Context, extra map[string]interfa
 	return float64(res), nil
 }
 
+// Properties returns the ticks per rotation of the encoder, which is 0 if not configured.
+func (e *SingleEncoder) Properties(ctx context.Context, extra map[string]interface{}) (Properties, error) {
+	return Properties{TicksPerRotation: e.TicksPerRotation}, nil
+}
+
 // Reset sets the current position of the motor (adjusted by a given offset)
 // to be its new zero position.
 func (e *SingleEncoder) Reset(ctx context.Context, offset float64, extra map[string]interface{}) error {
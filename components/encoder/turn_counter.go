@@ -0,0 +1,33 @@
+package encoder
+
+// turnCounter counts the rotations of an absolute encoder from the angles it reads, which wrap around once per turn. It must be
+// sampled often enough that the encoder turns less than half a turn between samples, so that the shorter way around is the way it
+// moved.
+type turnCounter struct {
+	// the angle at the last sample, in degrees from 0 to 360, and the rotations from zero it was at
+	angle     float64
+	rotations float64
+}
+
+// newTurnCounter returns a turnCounter starting at the angle, within the first rotation from zero.
+func newTurnCounter(angle float64) turnCounter {
+	return turnCounter{angle: angle, rotations: angle / 360}
+}
+
+// update adds how far the encoder moved to reach the angle to the rotations.
+func (c *turnCounter) update(angle float64) {
+	delta := angle - c.angle
+	if delta > 180 {
+		delta -= 360
+	} else if delta < -180 {
+		delta += 360
+	}
+	c.rotations += delta / 360
+	c.angle = angle
+}
+
+// reset sets the rotations at the angle.
+func (c *turnCounter) reset(angle, rotations float64) {
+	c.angle = angle
+	c.rotations = rotations
+}
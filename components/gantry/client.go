@@ -6,7 +6,6 @@ import (
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/gantry/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -33,7 +32,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) Position(ctx context.Context, extra map[string]interface{}) ([]float64, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +47,7 @@ func (c *client) Position(ctx context.Context, extra map[string]interface{}) ([]
 }
 
 func (c *client) Lengths(ctx context.Context, extra map[string]interface{}) ([]float64, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +67,7 @@ func (c *client) MoveToPosition(
 	worldState *referenceframe.WorldState,
 	extra map[string]interface{},
 ) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -86,7 +85,7 @@ func (c *client) MoveToPosition(
 }
 
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/gripper/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -33,7 +32,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) Open(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -45,7 +44,7 @@ func (c *client) Open(ctx context.Context, extra map[string]interface{}) error {
 }
 
 func (c *client) Grab(ctx context.Context, extra map[string]interface{}) (bool, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return false, err
 	}
@@ -60,7 +59,7 @@ func (c *client) Grab(ctx context.Context, extra map[string]interface{}) (bool,
 }
 
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
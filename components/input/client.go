@@ -9,7 +9,6 @@ import (
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/inputcontroller/v1"
 	"go.viam.com/utils"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -52,7 +51,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) Controls(ctx context.Context, extra map[string]interface{}) ([]Control, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +70,7 @@ func (c *client) Controls(ctx context.Context, extra map[string]interface{}) ([]
 }
 
 func (c *client) Events(ctx context.Context, extra map[string]interface{}) (map[Control]Event, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +96,7 @@ func (c *client) Events(ctx context.Context, extra map[string]interface{}) (map[
 
 // TriggerEvent allows directly sending an Event (such as a button press) from external code.
 func (c *client) TriggerEvent(ctx context.Context, event Event, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -146,7 +145,7 @@ func (c *client) RegisterControlCallback(
 	// We want to start one and only one connectStream()
 	c.streamMu.Lock()
 	defer c.streamMu.Unlock()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
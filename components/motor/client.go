@@ -6,7 +6,6 @@ import (
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/motor/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -32,7 +31,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) SetPower(ctx context.Context, powerPct float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -46,7 +45,7 @@ func (c *client) SetPower(ctx context.Context, powerPct float64, extra map[strin
 }
 
 func (c *client) GoFor(ctx context.Context, rpm, revolutions float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -61,7 +60,7 @@ func (c *client) GoFor(ctx context.Context, rpm, revolutions float64, extra map[
 }
 
 func (c *client) GoTo(ctx context.Context, rpm, positionRevolutions float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -76,7 +75,7 @@ func (c *client) GoTo(ctx context.Context, rpm, positionRevolutions float64, ext
 }
 
 func (c *client) ResetZeroPosition(ctx context.Context, offset float64, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -90,7 +89,7 @@ func (c *client) ResetZeroPosition(ctx context.Context, offset float64, extra ma
 }
 
 func (c *client) Position(ctx context.Context, extra map[string]interface{}) (float64, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return 0, err
 	}
@@ -103,7 +102,7 @@ func (c *client) Position(ctx context.Context, extra map[string]interface{}) (fl
 }
 
 func (c *client) Properties(ctx context.Context, extra map[string]interface{}) (map[Feature]bool, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +115,7 @@ func (c *client) Properties(ctx context.Context, extra map[string]interface{}) (
 }
 
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -126,7 +125,7 @@ func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
 }
 
 func (c *client) IsPowered(ctx context.Context, extra map[string]interface{}) (bool, float64, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return false, 0.0, err
 	}
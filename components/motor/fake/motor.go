@@ -58,6 +58,7 @@ func init() {
 	_motor := registry.Component{
 		Constructor: func(ctx context.Context, deps registry.Dependencies, config config.Component, logger golog.Logger) (interface{}, error) {
 			m := &Motor{Name: config.Name, Logger: logger}
+			m.health.SetState(resource.HealthStateReady, nil)
 			if mcfg, ok := config.ConvertedAttributes.(*Config); ok {
 				if mcfg.BoardName != "" {
 					m.Board = mcfg.BoardName
@@ -83,17 +84,20 @@ func init() {
 				}
 
 				if mcfg.Encoder != "" {
-					if mcfg.TicksPerRotation <= 0 {
-						return nil, errors.New("need nonzero TicksPerRotation for encoded motor")
-					}
-					m.TicksPerRotation = mcfg.TicksPerRotation
-
 					e, err := encoder.FromDependencies(deps, mcfg.Encoder)
 					if err != nil {
 						return nil, err
 					}
 					realEncoder := rdkutils.UnwrapProxy(e)
 					m.Encoder = realEncoder.(*fakeencoder.Encoder)
+
+					m.TicksPerRotation, err = encoder.TicksPerRotation(ctx, m.Encoder, mcfg.TicksPerRotation)
+					if err != nil {
+						return nil, err
+					}
+					if m.TicksPerRotation <= 0 {
+						return nil, errors.New("need nonzero TicksPerRotation for encoded motor")
+					}
 					m.PositionReporting = true
 				} else {
 					m.PositionReporting = false
@@ -135,9 +139,16 @@ type Motor struct {
 	DirFlip           bool
 	opMgr             operation.SingleOperationManager
 	TicksPerRotation  int
+	health            resource.HealthTracker
 	generic.Echo
 }
 
+// Health returns the health of the motor, which is degraded while its encoder cannot be
+// set to the motor's speed.
+func (m *Motor) Health(ctx context.Context) (resource.Health, error) {
+	return m.health.Health(ctx)
+}
+
 // Position returns motor position in rotations.
 func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (float64, error) {
 	m.mu.Lock()
@@ -152,11 +163,7 @@ func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (flo
 		return 0, err
 	}
 
-	if m.TicksPerRotation == 0 {
-		return 0, errors.New("need nonzero TicksPerRotation for motor")
-	}
-
-	return ticks / float64(m.TicksPerRotation), nil
+	return encoder.ConvertTicks(ticks, m.TicksPerRotation, encoder.PositionTypeRotations)
 }
 
 // Properties returns the status of whether the motor supports certain optional features.
@@ -183,8 +190,10 @@ func (m *Motor) SetPower(ctx context.Context, powerPct float64, extra map[string
 		newSpeed := (m.MaxRPM * m.powerPct) * float64(m.TicksPerRotation)
 		err := m.Encoder.SetSpeed(ctx, newSpeed)
 		if err != nil {
+			m.health.SetState(resource.HealthStateDegraded, err)
 			return err
 		}
+		m.health.SetState(resource.HealthStateReady, nil)
 	}
 	return nil
 }
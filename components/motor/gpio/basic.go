@@ -13,6 +13,7 @@ import (
 	"go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
 )
 
 // NewMotor constructs a new GPIO based motor on the given board using the
@@ -42,6 +43,7 @@ func NewMotor(b board.Board, mc Config, name string, logger golog.Logger) (motor
 		logger:      logger,
 		motorName:   name,
 	}
+	m.health.SetState(resource.HealthStateReady, nil)
 
 	if mc.Pins.A != "" {
 		a, err := b.GPIOPinByName(mc.Pins.A)
@@ -108,10 +110,16 @@ type Motor struct {
 
 	opMgr  operation.SingleOperationManager
 	logger golog.Logger
+	health resource.HealthTracker
 
 	generic.Unimplemented
 }
 
+// Health returns the health of the motor, which is degraded while its pins cannot be set.
+func (m *Motor) Health(ctx context.Context) (resource.Health, error) {
+	return m.health.Health(ctx)
+}
+
 // Position always returns 0.
 func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (float64, error) {
 	return 0, nil
@@ -124,8 +132,19 @@ func (m *Motor) Properties(ctx context.Context, extra map[string]interface{}) (m
 	}, nil
 }
 
-// setPWM sets the associated pins (as discovered) and sets PWM to the given power percentage.
+// setPWM sets the associated pins (as discovered) and sets PWM to the given power percentage,
+// recording in the motor's health whether the pins could be set.
 func (m *Motor) setPWM(ctx context.Context, powerPct float64, extra map[string]interface{}) error {
+	err := m.setPWMPins(ctx, powerPct, extra)
+	if err != nil {
+		m.health.SetState(resource.HealthStateDegraded, err)
+	} else {
+		m.health.SetState(resource.HealthStateReady, nil)
+	}
+	return err
+}
+
+func (m *Motor) setPWMPins(ctx context.Context, powerPct float64, extra map[string]interface{}) error {
 	var errs error
 	powerPct = math.Min(powerPct, m.maxPowerPct)
 	powerPct = math.Max(powerPct, -1*m.maxPowerPct)
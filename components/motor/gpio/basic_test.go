@@ -13,6 +13,8 @@ import (
 	fakeboard "go.viam.com/rdk/components/board/fake"
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
 )
 
 const maxRPM = 100
@@ -318,6 +320,42 @@ func TestMotorABNoEncoder(t *testing.T) {
 	})
 }
 
+func TestMotorHealth(t *testing.T) {
+	ctx := context.Background()
+	errPWM := errors.New("pwm failed")
+	var failPWM bool
+	pin := &inject.GPIOPin{
+		SetPWMFreqFunc: func(ctx context.Context, freqHz uint, extra map[string]interface{}) error {
+			return nil
+		},
+		SetPWMFunc: func(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
+			if failPWM {
+				return errPWM
+			}
+			return nil
+		},
+	}
+	m := &Motor{PWM: pin, maxPowerPct: 1}
+
+	test.That(t, m.setPWM(ctx, 0.5, nil), test.ShouldBeNil)
+	health, err := m.Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health.State, test.ShouldEqual, resource.HealthStateReady)
+
+	failPWM = true
+	test.That(t, m.setPWM(ctx, 0.5, nil), test.ShouldBeError, errPWM)
+	health, err = m.Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health.State, test.ShouldEqual, resource.HealthStateDegraded)
+	test.That(t, health.LastError, test.ShouldBeError, errPWM)
+
+	failPWM = false
+	test.That(t, m.setPWM(ctx, 0.5, nil), test.ShouldBeNil)
+	health, err = m.Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health.State, test.ShouldEqual, resource.HealthStateReady)
+}
+
 func TestGoForMath(t *testing.T) {
 	powerPct, waitDur := goForMath(100, 100, 100)
 	test.That(t, powerPct, test.ShouldEqual, 1)
@@ -19,6 +19,7 @@ import (
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/control"
 	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -63,10 +64,6 @@ func WrapMotorWithEncoder(
 		return nil, utils.NewConfigValidationError("", errors.New("ticks_per_rotation should be positive or zero"))
 	}
 
-	if mc.TicksPerRotation == 0 {
-		mc.TicksPerRotation = 1
-	}
-
 	mm, err := newEncodedMotor(c, mc, m, e, logger)
 	if err != nil {
 		return nil, err
@@ -105,6 +102,16 @@ func newEncodedMotor(
 	if !ok {
 		return nil, motor.NewUnimplementedLocalInterfaceError(realMotor)
 	}
+	// the encoder knows best how many ticks it counts in a rotation, falling back to what is configured on the motor
+	ticksPerRotation, err := encoder.TicksPerRotation(context.Background(), realEncoder, motorConfig.TicksPerRotation)
+	if err != nil {
+		return nil, err
+	}
+	if ticksPerRotation == 0 {
+		ticksPerRotation = 1
+	}
+	motorConfig.TicksPerRotation = ticksPerRotation
+
 	cancelCtx, cancel := context.WithCancel(context.Background())
 	em := &EncodedMotor{
 		activeBackgroundWorkers: &sync.WaitGroup{},
@@ -120,6 +127,7 @@ func newEncodedMotor(
 		logger:                  logger,
 		loop:                    nil,
 	}
+	em.health.SetState(resource.HealthStateReady, nil)
 
 	if len(motorConfig.ControlLoop.Blocks) != 0 {
 		cLoop, err := control.NewLoop(logger, motorConfig.ControlLoop, em)
@@ -187,10 +195,24 @@ type EncodedMotor struct {
 	cancel          func()
 	loop            *control.Loop
 	opMgr           operation.SingleOperationManager
+	health          resource.HealthTracker
 
 	generic.Unimplemented
 }
 
+// Health returns the health of the motor, which is degraded while its encoder cannot be read.
+// Otherwise it is the health of the motor driven, if that reports any.
+func (m *EncodedMotor) Health(ctx context.Context) (resource.Health, error) {
+	health, err := m.health.Health(ctx)
+	if err != nil || health.State != resource.HealthStateReady {
+		return health, err
+	}
+	if reporter, ok := m.real.(resource.HealthReporter); ok {
+		return reporter.Health(ctx)
+	}
+	return health, nil
+}
+
 // EncodedMotorState is the core, non-statistical state for the motor.
 // Multiple values should be updated atomically at the same time.
 type EncodedMotorState struct {
@@ -208,7 +230,7 @@ func (m *EncodedMotor) Position(ctx context.Context, extra map[string]interface{
 		return 0, err
 	}
 
-	return ticks / float64(m.cfg.TicksPerRotation), nil
+	return encoder.ConvertTicks(ticks, m.cfg.TicksPerRotation, encoder.PositionTypeRotations)
 }
 
 // DirectionMoving returns the direction we are currently mpving in, with 1 representing
@@ -327,6 +349,7 @@ func (m *EncodedMotor) rpmMonitor() {
 
 		pos, err := m.encoder.TicksCount(m.cancelCtx, nil)
 		if err != nil {
+			m.health.SetState(resource.HealthStateDegraded, err)
 			m.logger.Info("error getting encoder position, sleeping then continuing: %w", err)
 			if !utils.SelectContextOrWait(m.cancelCtx, 100*time.Millisecond) {
 				m.logger.Info("error sleeping, giving up %w", m.cancelCtx.Err())
@@ -334,6 +357,7 @@ func (m *EncodedMotor) rpmMonitor() {
 			}
 			continue
 		}
+		m.health.SetState(resource.HealthStateReady, nil)
 		now := time.Now().UnixNano()
 		if now == lastTime {
 			// this really only happens in testing, b/c we decrease sleep, but nice defense anyway
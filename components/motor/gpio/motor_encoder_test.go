@@ -634,6 +634,36 @@ func TestWrapMotorWithEncoder(t *testing.T) {
 		test.That(t, ok, test.ShouldBeTrue)
 		test.That(t, utils.TryClose(context.Background(), m), test.ShouldBeNil)
 	})
+
+	t.Run("wrap motor with encoder knowing its ticks per rotation", func(t *testing.T) {
+		fakeMotor := &fakemotor.Motor{}
+		e := &encoder.IncrementalEncoder{
+			A:                &board.BasicDigitalInterrupt{},
+			B:                &board.BasicDigitalInterrupt{},
+			TicksPerRotation: 200,
+			CancelCtx:        context.Background(),
+		}
+		e.Start(context.Background())
+		test.That(t, e.Reset(context.Background(), 100, nil), test.ShouldBeNil)
+
+		m, err := WrapMotorWithEncoder(context.Background(), e, config.Component{Name: "motor1"}, Config{MaxRPM: 60}, fakeMotor, logger)
+		test.That(t, err, test.ShouldBeNil)
+		pos, err := m.Position(context.Background(), nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pos, test.ShouldEqual, 0.5)
+		test.That(t, utils.TryClose(context.Background(), m), test.ShouldBeNil)
+
+		// the motor cannot disagree with the encoder
+		_, err = WrapMotorWithEncoder(
+			context.Background(),
+			e,
+			config.Component{Name: "motor1"},
+			Config{TicksPerRotation: 100, MaxRPM: 60},
+			fakeMotor,
+			logger,
+		)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
 }
 
 func TestDirFlipMotor(t *testing.T) {
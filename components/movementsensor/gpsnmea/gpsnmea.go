@@ -3,6 +3,7 @@ package gpsnmea
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
@@ -10,6 +11,7 @@ import (
 
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/discovery"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
 	rdkutils "go.viam.com/rdk/utils"
@@ -121,6 +123,31 @@ func init() {
 			return config.TransformAttributeMapToStruct(&attr, attributes)
 		},
 		&AttrConfig{})
+
+	registry.RegisterConfigProposer(discovery.NewQuery(movementsensor.Subtype, modelname), proposeConfigs)
+}
+
+// uBloxVendorID is the USB vendor ID of u-blox, whose GPS receivers speak NMEA over serial.
+const uBloxVendorID = 0x1546
+
+// proposeConfigs proposes a serial config for every serial port of a u-blox USB receiver.
+func proposeConfigs(ctx context.Context, hw discovery.Hardware) ([]discovery.Proposal, error) {
+	var proposals []discovery.Proposal
+	for _, dev := range hw.USB {
+		if dev.VendorID != uBloxVendorID {
+			continue
+		}
+		for _, tty := range dev.TTYs {
+			proposals = append(proposals, discovery.Proposal{
+				Attributes: map[string]interface{}{
+					connectionType:      serialStr,
+					"serial_attributes": map[string]interface{}{"serial_path": tty},
+				},
+				Reason: fmt.Sprintf("u-blox USB device %q at %s", dev.Product, tty),
+			})
+		}
+	}
+	return proposals, nil
 }
 
 const (
@@ -7,7 +7,6 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -274,28 +273,10 @@ func (g *RTKMovementSensor) Start() error {
 
 // Connect attempts to connect to ntrip client until successful connection or timeout.
 func (g *RTKMovementSensor) Connect(casterAddr, user, pwd string, maxAttempts int) error {
-	success := false
-	attempts := 0
-
-	var c *ntrip.Client
-	var err error
-
 	g.logger.Debug("Connecting to NTRIP caster")
-	for !success && attempts < maxAttempts {
-		select {
-		case <-g.cancelCtx.Done():
-			return errors.New("Canceled")
-		default:
-		}
-
-		c, err = ntrip.NewClient(casterAddr, ntrip.Options{Username: user, Password: pwd})
-		if err == nil {
-			success = true
-		}
-
-		attempts++
-	}
-
+	c, err := rdkutils.RetryValue(g.cancelCtx, ntripRetryOptions(maxAttempts), func(ctx context.Context) (*ntrip.Client, error) {
+		return ntrip.NewClient(casterAddr, ntrip.Options{Username: user, Password: pwd})
+	})
 	if err != nil {
 		g.logger.Errorf("Can't connect to NTRIP caster: %s", err)
 		return err
@@ -311,32 +292,12 @@ func (g *RTKMovementSensor) Connect(casterAddr, user, pwd string, maxAttempts in
 
 // GetStream attempts to connect to ntrip streak until successful connection or timeout.
 func (g *RTKMovementSensor) GetStream(mountPoint string, maxAttempts int) error {
-	success := false
-	attempts := 0
-
-	var rc io.ReadCloser
-	var err error
-
 	g.logger.Debug("Getting NTRIP stream")
-
-	for !success && attempts < maxAttempts {
-		select {
-		case <-g.cancelCtx.Done():
-			return errors.New("Canceled")
-		default:
-		}
-
-		rc, err = func() (io.ReadCloser, error) {
-			g.mu.Lock()
-			defer g.mu.Unlock()
-			return g.ntripClient.Client.GetStream(mountPoint)
-		}()
-		if err == nil {
-			success = true
-		}
-		attempts++
-	}
-
+	rc, err := rdkutils.RetryValue(g.cancelCtx, ntripRetryOptions(maxAttempts), func(ctx context.Context) (io.ReadCloser, error) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.ntripClient.Client.GetStream(mountPoint)
+	})
 	if err != nil {
 		g.logger.Errorf("Can't connect to NTRIP stream: %s", err)
 		return err
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/de-bkg/gognss/pkg/ntrip"
 	"github.com/edaniels/golog"
@@ -93,29 +94,24 @@ func newNtripInfo(cfg *NtripAttrConfig, logger golog.Logger) (*NtripInfo, error)
 	return n, nil
 }
 
+// ntripRetryOptions are how connecting to an NTRIP caster and its streams is retried. As before the
+// shared helper, any error is retried until the attempts run out.
+func ntripRetryOptions(maxAttempts int) rdkutils.RetryOptions {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return rdkutils.RetryOptions{
+		Backoff:     rdkutils.Backoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second, Jitter: 0.2},
+		MaxAttempts: maxAttempts,
+	}
+}
+
 // Connect attempts to connect to ntrip client until successful connection or timeout.
 func (n *ntripCorrectionSource) Connect() error {
-	success := false
-	attempts := 0
-
-	var c *ntrip.Client
-	var err error
-
 	n.logger.Debug("Connecting to NTRIP caster")
-	for !success && attempts < n.info.MaxConnectAttempts {
-		select {
-		case <-n.cancelCtx.Done():
-			return errors.New("Canceled")
-		default:
-		}
-
-		c, err = ntrip.NewClient(n.info.URL, ntrip.Options{Username: n.info.Username, Password: n.info.Password})
-		if err == nil {
-			success = true
-		}
-		attempts++
-	}
-
+	c, err := rdkutils.RetryValue(n.cancelCtx, ntripRetryOptions(n.info.MaxConnectAttempts), func(ctx context.Context) (*ntrip.Client, error) {
+		return ntrip.NewClient(n.info.URL, ntrip.Options{Username: n.info.Username, Password: n.info.Password})
+	})
 	if err != nil {
 		n.logger.Errorf("Can't connect to NTRIP caster: %s", err)
 		return err
@@ -130,28 +126,10 @@ func (n *ntripCorrectionSource) Connect() error {
 
 // GetStream attempts to connect to ntrip stream until successful connection or timeout.
 func (n *ntripCorrectionSource) GetStream() error {
-	success := false
-	attempts := 0
-
-	var rc io.ReadCloser
-	var err error
-
 	n.logger.Debug("Getting NTRIP stream")
-
-	for !success && attempts < n.info.MaxConnectAttempts {
-		select {
-		case <-n.cancelCtx.Done():
-			return errors.New("Canceled")
-		default:
-		}
-
-		rc, err = n.info.Client.GetStream(n.info.MountPoint)
-		if err == nil {
-			success = true
-		}
-		attempts++
-	}
-
+	rc, err := rdkutils.RetryValue(n.cancelCtx, ntripRetryOptions(n.info.MaxConnectAttempts), func(ctx context.Context) (io.ReadCloser, error) {
+		return n.info.Client.GetStream(n.info.MountPoint)
+	})
 	if err != nil {
 		n.logger.Errorf("Can't connect to NTRIP stream: %s", err)
 		return err
@@ -20,6 +20,7 @@ package mpu6050
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/discovery"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/spatialmath"
@@ -84,6 +86,29 @@ func init() {
 			return config.TransformAttributeMapToStruct(&attr, attributes)
 		},
 		&AttrConfig{})
+
+	registry.RegisterConfigProposer(
+		discovery.NewQuery(movementsensor.Subtype, model), proposeConfigs, 0x68, 0x69)
+}
+
+// proposeConfigs proposes a config for every device answering on one of the chip's addresses.
+// Other chips use these addresses too, so the proposals need confirming.
+func proposeConfigs(ctx context.Context, hw discovery.Hardware) ([]discovery.Proposal, error) {
+	var proposals []discovery.Proposal
+	for _, dev := range hw.I2C {
+		if dev.Address != 0x68 && dev.Address != 0x69 {
+			continue
+		}
+		attrs := map[string]interface{}{"board": dev.Board, "i2c_bus": dev.Bus}
+		if dev.Address == 0x69 {
+			attrs["use_alt_i2c_address"] = true
+		}
+		proposals = append(proposals, discovery.Proposal{
+			Attributes: attrs,
+			Reason:     fmt.Sprintf("device at I2C address %#x on bus %q of board %q", dev.Address, dev.Bus, dev.Board),
+		})
+	}
+	return proposals, nil
 }
 
 type mpu6050 struct {
@@ -0,0 +1,337 @@
+// Package replay implements a movement sensor that replays readings captured by the data manager.
+package replay
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+var modelname = resource.NewDefaultModel("replay")
+
+// The names of the methods movement sensor readings are captured by.
+const (
+	positionMethod           = "Position"
+	linearVelocityMethod     = "LinearVelocity"
+	angularVelocityMethod    = "AngularVelocity"
+	compassHeadingMethod     = "CompassHeading"
+	linearAccelerationMethod = "LinearAcceleration"
+	orientationMethod        = "Orientation"
+)
+
+// AttrConfig is used for converting replay movement sensor attributes.
+type AttrConfig struct {
+	// Source is a data capture file of movement sensor readings, or a directory that is searched for them.
+	Source string `json:"source"`
+	// SensorName picks the readings of one movement sensor when the source has readings of several.
+	SensorName string `json:"sensor_name,omitempty"`
+	// Speed is how many times faster than real time readings are replayed. Defaults to 1.
+	Speed float64 `json:"speed,omitempty"`
+	// Loop restarts the replay once it has replayed every reading.
+	Loop bool `json:"loop,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *AttrConfig) Validate(path string) ([]string, error) {
+	if cfg.Source == "" {
+		return nil, utils.NewConfigValidationFieldRequiredError(path, "source")
+	}
+	if cfg.Speed < 0 {
+		return nil, utils.NewConfigValidationError(path, errors.New("speed cannot be negative"))
+	}
+	return nil, nil
+}
+
+func init() {
+	registry.RegisterComponent(
+		movementsensor.Subtype,
+		modelname,
+		registry.Component{Constructor: func(
+			ctx context.Context,
+			deps registry.Dependencies,
+			cfg config.Component,
+			logger golog.Logger,
+		) (interface{}, error) {
+			attr, ok := cfg.ConvertedAttributes.(*AttrConfig)
+			if !ok {
+				return nil, rdkutils.NewUnexpectedTypeError(attr, cfg.ConvertedAttributes)
+			}
+			return newReplayMovementSensor(attr, time.Now)
+		}})
+
+	config.RegisterComponentAttributeMapConverter(movementsensor.Subtype, modelname,
+		func(attributes config.AttributeMap) (interface{}, error) {
+			var attr AttrConfig
+			return config.TransformAttributeMapToStruct(&attr, attributes)
+		},
+		&AttrConfig{})
+}
+
+// timedReading is a captured reading, at its offset from the start of the replay.
+type timedReading struct {
+	offset time.Duration
+	fields map[string]*structpb.Value
+}
+
+// replayMovementSensor replays readings as if they were being read now. The replay starts once every method has
+// a reading, and readings are held until the next one of their method is due.
+type replayMovementSensor struct {
+	readings map[string][]timedReading
+	duration time.Duration
+	speed    float64
+	loop     bool
+	now      func() time.Time
+
+	mu    sync.Mutex
+	start time.Time
+}
+
+func newReplayMovementSensor(attr *AttrConfig, now func() time.Time) (movementsensor.MovementSensor, error) {
+	captured, err := readCapturedData(attr.Source, attr.SensorName)
+	if err != nil {
+		return nil, err
+	}
+	if len(captured) == 0 {
+		return nil, errors.Errorf("no captured movement sensor readings found in %q", attr.Source)
+	}
+
+	// the replay starts at the first time every method has a reading
+	var start, end time.Time
+	for _, data := range captured {
+		if first := data[0].GetMetadata().GetTimeRequested().AsTime(); first.After(start) {
+			start = first
+		}
+		if last := data[len(data)-1].GetMetadata().GetTimeRequested().AsTime(); last.After(end) {
+			end = last
+		}
+	}
+	readings := make(map[string][]timedReading, len(captured))
+	for method, data := range captured {
+		for _, d := range data {
+			readings[method] = append(readings[method], timedReading{
+				offset: d.GetMetadata().GetTimeRequested().AsTime().Sub(start),
+				fields: d.GetStruct().GetFields(),
+			})
+		}
+	}
+
+	speed := attr.Speed
+	if speed == 0 {
+		speed = 1
+	}
+	return &replayMovementSensor{
+		readings: readings,
+		duration: end.Sub(start),
+		speed:    speed,
+		loop:     attr.Loop,
+		now:      now,
+		start:    now(),
+	}, nil
+}
+
+// readCapturedData returns the tabular readings of a movement sensor in the data capture files at source, by method
+// and in order of time.
+func readCapturedData(source, sensorName string) (map[string][]*v1.SensorData, error) {
+	captured := map[string][]*v1.SensorData{}
+	sensorNames := map[string]bool{}
+	err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != datacapture.FileExt {
+			return nil
+		}
+		//nolint:gosec
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer utils.UncheckedErrorFunc(f.Close)
+		dcFile, err := datacapture.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		md := dcFile.ReadMetadata()
+		if md.GetComponentType() != movementsensor.Subtype.String() || md.GetType() != v1.DataType_DATA_TYPE_TABULAR_SENSOR ||
+			(sensorName != "" && md.GetComponentName() != sensorName) {
+			return nil
+		}
+		data, err := datacapture.SensorDataFromFile(dcFile)
+		if err != nil {
+			return errors.Wrapf(err, "error reading %s", path)
+		}
+		sensorNames[md.GetComponentName()] = true
+		captured[md.GetMethodName()] = append(captured[md.GetMethodName()], data...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(sensorNames) > 1 {
+		return nil, errors.Errorf("%q has readings of %d movement sensors, sensor_name must pick one", source, len(sensorNames))
+	}
+	for method, data := range captured {
+		if len(data) == 0 {
+			delete(captured, method)
+			continue
+		}
+		sort.SliceStable(data, func(i, j int) bool {
+			return data[i].GetMetadata().GetTimeRequested().AsTime().Before(data[j].GetMetadata().GetTimeRequested().AsTime())
+		})
+	}
+	return captured, nil
+}
+
+// elapsed returns how far into the recording the replay is.
+func (r *replayMovementSensor) elapsed() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Duration(float64(r.now().Sub(r.start)) * r.speed)
+	if r.loop && r.duration > 0 {
+		elapsed %= r.duration
+	}
+	return elapsed
+}
+
+// reading returns the fields of the latest reading of a method that is due.
+func (r *replayMovementSensor) reading(method string, unimplemented error) (map[string]*structpb.Value, error) {
+	readings, ok := r.readings[method]
+	if !ok {
+		return nil, unimplemented
+	}
+	elapsed := r.elapsed()
+	next := sort.Search(len(readings), func(i int) bool {
+		return readings[i].offset > elapsed
+	})
+	// every method has a reading at the start of the replay
+	return readings[rdkutils.MaxInt(next-1, 0)].fields, nil
+}
+
+func vectorFromFields(fields map[string]*structpb.Value) r3.Vector {
+	return r3.Vector{X: fields["X"].GetNumberValue(), Y: fields["Y"].GetNumberValue(), Z: fields["Z"].GetNumberValue()}
+}
+
+// Position returns the replayed position. Altitude is not captured, so it is always 0.
+func (r *replayMovementSensor) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	fields, err := r.reading(positionMethod, movementsensor.ErrMethodUnimplementedPosition)
+	if err != nil {
+		return nil, 0, err
+	}
+	return geo.NewPoint(fields["Lat"].GetNumberValue(), fields["Lng"].GetNumberValue()), 0, nil
+}
+
+// LinearVelocity returns the replayed linear velocity.
+func (r *replayMovementSensor) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	fields, err := r.reading(linearVelocityMethod, movementsensor.ErrMethodUnimplementedLinearVelocity)
+	if err != nil {
+		return r3.Vector{}, err
+	}
+	return vectorFromFields(fields), nil
+}
+
+// AngularVelocity returns the replayed angular velocity.
+func (r *replayMovementSensor) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	fields, err := r.reading(angularVelocityMethod, movementsensor.ErrMethodUnimplementedAngularVelocity)
+	if err != nil {
+		return spatialmath.AngularVelocity{}, err
+	}
+	return spatialmath.AngularVelocity(vectorFromFields(fields)), nil
+}
+
+// LinearAcceleration returns the replayed linear acceleration.
+func (r *replayMovementSensor) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	fields, err := r.reading(linearAccelerationMethod, movementsensor.ErrMethodUnimplementedLinearAcceleration)
+	if err != nil {
+		return r3.Vector{}, err
+	}
+	return vectorFromFields(fields), nil
+}
+
+// CompassHeading returns the replayed compass heading.
+func (r *replayMovementSensor) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	fields, err := r.reading(compassHeadingMethod, movementsensor.ErrMethodUnimplementedCompassHeading)
+	if err != nil {
+		return 0, err
+	}
+	return fields["Heading"].GetNumberValue(), nil
+}
+
+// Orientation returns the replayed orientation.
+func (r *replayMovementSensor) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	fields, err := r.reading(orientationMethod, movementsensor.ErrMethodUnimplementedOrientation)
+	if err != nil {
+		return nil, err
+	}
+	return &spatialmath.OrientationVectorDegrees{
+		Theta: fields["th"].GetNumberValue(),
+		OX:    fields["x"].GetNumberValue(),
+		OY:    fields["y"].GetNumberValue(),
+		OZ:    fields["z"].GetNumberValue(),
+	}, nil
+}
+
+// Properties returns which methods have captured readings to replay.
+func (r *replayMovementSensor) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	has := func(method string) bool {
+		_, ok := r.readings[method]
+		return ok
+	}
+	return &movementsensor.Properties{
+		PositionSupported:           has(positionMethod),
+		LinearVelocitySupported:     has(linearVelocityMethod),
+		AngularVelocitySupported:    has(angularVelocityMethod),
+		CompassHeadingSupported:     has(compassHeadingMethod),
+		LinearAccelerationSupported: has(linearAccelerationMethod),
+		OrientationSupported:        has(orientationMethod),
+	}, nil
+}
+
+// Accuracy is not captured, so there is none to replay.
+func (r *replayMovementSensor) Accuracy(ctx context.Context, extra map[string]interface{}) (map[string]float32, error) {
+	return map[string]float32{}, nil
+}
+
+// Readings returns the replayed readings.
+func (r *replayMovementSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	return movementsensor.Readings(ctx, r, extra)
+}
+
+// DoCommand seeks the replay to {"command": "seek", "seconds": <seconds into the recording>}, so that tests can
+// replay from a known point, and returns how far into the recording the replay is.
+func (r *replayMovementSensor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	name, ok := cmd["command"]
+	if !ok {
+		return nil, errors.New("missing 'command' value")
+	}
+	if name != "seek" {
+		return nil, errors.Errorf("no such command: %s", name)
+	}
+	seconds, ok := cmd["seconds"].(float64)
+	if !ok || seconds < 0 {
+		return nil, errors.New("seek needs a non-negative number of 'seconds'")
+	}
+	r.mu.Lock()
+	r.start = r.now().Add(-time.Duration(seconds * float64(time.Second) / r.speed))
+	r.mu.Unlock()
+	return map[string]interface{}{"seconds": r.elapsed().Seconds()}, nil
+}
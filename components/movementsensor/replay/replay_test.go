@@ -0,0 +1,166 @@
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager/datacapture"
+)
+
+var recordingStart = time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// writeCapture writes readings of a method of a movement sensor, taken every second from the start of the recording
+// plus an offset, to a data capture file in dir.
+func writeCapture(t *testing.T, dir, sensorName, method string, offset time.Duration, readings []map[string]interface{}) {
+	t.Helper()
+	md, err := datacapture.BuildCaptureMetadata(movementsensor.Subtype, sensorName, resource.NewDefaultModel("gps-nmea"),
+		method, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	f, err := datacapture.NewFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	for i, reading := range readings {
+		s, err := structpb.NewStruct(reading)
+		test.That(t, err, test.ShouldBeNil)
+		requested := recordingStart.Add(offset + time.Duration(i)*time.Second)
+		test.That(t, f.WriteNext(&v1.SensorData{
+			Metadata: &v1.SensorMetadata{
+				TimeRequested: timestamppb.New(requested),
+				TimeReceived:  timestamppb.New(requested),
+			},
+			Data: &v1.SensorData_Struct{Struct: s},
+		}), test.ShouldBeNil)
+	}
+	test.That(t, f.Close(), test.ShouldBeNil)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestValidate(t *testing.T) {
+	_, err := (&AttrConfig{}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&AttrConfig{Source: "dir", Speed: -1}).Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&AttrConfig{Source: "dir", Speed: 2}).Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeCapture(t, dir, "gps1", positionMethod, 0, []map[string]interface{}{
+		{"Lat": 40.0, "Lng": -73.0},
+		{"Lat": 40.1, "Lng": -73.1},
+		{"Lat": 40.2, "Lng": -73.2},
+		{"Lat": 40.3, "Lng": -73.3},
+	})
+	// velocity readings start a second later, which is when the replay starts
+	writeCapture(t, dir, "gps1", linearVelocityMethod, time.Second, []map[string]interface{}{
+		{"X": 0.0, "Y": 1.0, "Z": 0.0},
+		{"X": 0.0, "Y": 2.0, "Z": 0.0},
+	})
+
+	clock := &fakeClock{now: time.Now()}
+	ms, err := newReplayMovementSensor(&AttrConfig{Source: dir}, clock.Now)
+	test.That(t, err, test.ShouldBeNil)
+
+	props, err := ms.Properties(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, props, test.ShouldResemble, &movementsensor.Properties{
+		PositionSupported:       true,
+		LinearVelocitySupported: true,
+	})
+	_, err = ms.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeError, movementsensor.ErrMethodUnimplementedCompassHeading)
+
+	p, _, err := ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p.Lat(), test.ShouldEqual, 40.1)
+	v, err := ms.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, v, test.ShouldResemble, r3.Vector{Y: 1})
+
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	p, _, err = ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p.Lat(), test.ShouldEqual, 40.2)
+	v, err = ms.LinearVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, v, test.ShouldResemble, r3.Vector{Y: 2})
+
+	// the last readings are held once the recording ends
+	clock.now = clock.now.Add(time.Hour)
+	p, _, err = ms.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p.Lat(), test.ShouldEqual, 40.3)
+
+	readings, err := ms.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["linear_velocity"], test.ShouldResemble, r3.Vector{Y: 2})
+
+	t.Run("seek", func(t *testing.T) {
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{"command": "seek", "seconds": 1.0})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["seconds"], test.ShouldEqual, 1.0)
+		p, _, err := ms.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, p.Lat(), test.ShouldEqual, 40.2)
+
+		_, err = ms.DoCommand(ctx, map[string]interface{}{"command": "seek"})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = ms.DoCommand(ctx, map[string]interface{}{"command": "rewind"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("accelerated and looping", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+		ms, err := newReplayMovementSensor(&AttrConfig{Source: dir, Speed: 2, Loop: true}, clock.Now)
+		test.That(t, err, test.ShouldBeNil)
+
+		clock.now = clock.now.Add(500 * time.Millisecond)
+		p, _, err := ms.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, p.Lat(), test.ShouldEqual, 40.2)
+
+		// the replay lasts two seconds, so three seconds in at double speed is a second into the second loop
+		clock.now = clock.now.Add(time.Second)
+		p, _, err = ms.Position(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, p.Lat(), test.ShouldEqual, 40.2)
+	})
+}
+
+func TestReplaySensorSelection(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now
+	_, err := newReplayMovementSensor(&AttrConfig{Source: dir}, now)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	writeCapture(t, dir, "gps1", compassHeadingMethod, 0, []map[string]interface{}{{"Heading": 90.0}})
+	writeCapture(t, dir, "gps2", compassHeadingMethod, 0, []map[string]interface{}{{"Heading": 180.0}})
+	_, err = newReplayMovementSensor(&AttrConfig{Source: dir}, now)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	ms, err := newReplayMovementSensor(&AttrConfig{Source: dir, SensorName: "gps2"}, now)
+	test.That(t, err, test.ShouldBeNil)
+	heading, err := ms.CompassHeading(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldEqual, 180.0)
+
+	_, err = newReplayMovementSensor(&AttrConfig{Source: dir, SensorName: "gps3"}, now)
+	test.That(t, err, test.ShouldNotBeNil)
+}
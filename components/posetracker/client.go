@@ -5,7 +5,6 @@ import (
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/posetracker/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -34,7 +33,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 func (c *client) Poses(
 	ctx context.Context, bodyNames []string, extra map[string]interface{},
 ) (BodyToPoseInFrame, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
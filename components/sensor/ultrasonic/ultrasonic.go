@@ -46,23 +46,18 @@ func (config *AttrConfig) Validate(path string) ([]string, error) {
 }
 
 func init() {
-	registry.RegisterComponent(
+	registry.RegisterTypedComponent(
 		sensor.Subtype,
 		modelname,
-		registry.Component{Constructor: func(
+		func(
 			ctx context.Context,
 			deps registry.Dependencies,
 			config config.Component,
+			attrs *AttrConfig,
 			logger golog.Logger,
-		) (interface{}, error) {
-			return newSensor(ctx, deps, config.Name, config.ConvertedAttributes.(*AttrConfig))
-		}})
-
-	config.RegisterComponentAttributeMapConverter(sensor.Subtype, modelname,
-		func(attributes config.AttributeMap) (interface{}, error) {
-			var conf AttrConfig
-			return config.TransformAttributeMapToStruct(&conf, attributes)
-		}, &AttrConfig{})
+		) (sensor.Sensor, error) {
+			return newSensor(ctx, deps, config.Name, attrs)
+		})
 }
 
 func newSensor(ctx context.Context, deps registry.Dependencies, name string, config *AttrConfig) (sensor.Sensor, error) {
@@ -6,7 +6,6 @@ import (
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/component/servo/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -32,7 +31,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) Move(ctx context.Context, angleDeg uint32, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -44,7 +43,7 @@ func (c *client) Move(ctx context.Context, angleDeg uint32, extra map[string]int
 }
 
 func (c *client) Position(ctx context.Context, extra map[string]interface{}) (uint32, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return 0, err
 	}
@@ -57,7 +56,7 @@ func (c *client) Position(ctx context.Context, extra map[string]interface{}) (ui
 }
 
 func (c *client) Stop(ctx context.Context, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// An AttributeGetter reads typed values out of an AttributeMap. Unlike the getters of
+// AttributeMap it does not panic on values of the wrong type; instead every problem is
+// collected as a *ValidationError with the path of the offending key and returned by Err,
+// so that all of them can be reported at once.
+type AttributeGetter struct {
+	attrs AttributeMap
+	path  string
+	errs  *error
+}
+
+// Getter returns an AttributeGetter for the map.
+func (am AttributeMap) Getter() *AttributeGetter {
+	return &AttributeGetter{attrs: am, errs: new(error)}
+}
+
+// Err returns every problem found by this getter, the getter it came from and the getters of
+// nested objects, combined with multierr.
+func (g *AttributeGetter) Err() error {
+	return *g.errs
+}
+
+// Has returns whether or not the given key is set to a non-nil value.
+func (g *AttributeGetter) Has(key string) bool {
+	return g.attrs[key] != nil
+}
+
+// GetString returns the string at key, or def if it is not set.
+func (g *AttributeGetter) GetString(key, def string) string {
+	x, ok := g.lookup(key)
+	if !ok {
+		return def
+	}
+	if v, ok := x.(string); ok {
+		return v
+	}
+	g.addTypeError(key, "a string", x)
+	return def
+}
+
+// GetBool returns the bool at key, or def if it is not set.
+func (g *AttributeGetter) GetBool(key string, def bool) bool {
+	x, ok := g.lookup(key)
+	if !ok {
+		return def
+	}
+	if v, ok := x.(bool); ok {
+		return v
+	}
+	g.addTypeError(key, "a bool", x)
+	return def
+}
+
+// GetInt returns the int at key, or def if it is not set. Floats without a fractional part,
+// as decoded from JSON, are accepted.
+func (g *AttributeGetter) GetInt(key string, def int) int {
+	x, ok := g.lookup(key)
+	if !ok {
+		return def
+	}
+	if v, ok := toInt(x); ok {
+		return v
+	}
+	g.addTypeError(key, "an int", x)
+	return def
+}
+
+// GetFloat64 returns the number at key as a float64, or def if it is not set.
+func (g *AttributeGetter) GetFloat64(key string, def float64) float64 {
+	x, ok := g.lookup(key)
+	if !ok {
+		return def
+	}
+	if v, ok := toFloat64(x); ok {
+		return v
+	}
+	g.addTypeError(key, "a number", x)
+	return def
+}
+
+// GetDuration returns the duration at key, or def if it is not set. Durations are strings
+// such as "1.5s" or "300ms" as accepted by time.ParseDuration.
+func (g *AttributeGetter) GetDuration(key string, def time.Duration) time.Duration {
+	x, ok := g.lookup(key)
+	if !ok {
+		return def
+	}
+	s, ok := x.(string)
+	if !ok {
+		g.addTypeError(key, "a duration string", x)
+		return def
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		g.addError(key, err)
+		return def
+	}
+	return v
+}
+
+// GetStringSlice returns the strings at key, or nil if it is not set.
+func (g *AttributeGetter) GetStringSlice(key string) []string {
+	x, ok := g.lookup(key)
+	if !ok {
+		return nil
+	}
+	if v, ok := x.([]string); ok {
+		return v
+	}
+	slice, ok := x.([]interface{})
+	if !ok {
+		g.addTypeError(key, "a list of strings", x)
+		return nil
+	}
+	strs := make([]string, 0, len(slice))
+	for idx, item := range slice {
+		s, ok := item.(string)
+		if !ok {
+			g.addTypeError(indexKey(key, idx), "a string", item)
+			continue
+		}
+		strs = append(strs, s)
+	}
+	return strs
+}
+
+// GetFloat64Slice returns the numbers at key as float64s, or nil if it is not set.
+func (g *AttributeGetter) GetFloat64Slice(key string) []float64 {
+	x, ok := g.lookup(key)
+	if !ok {
+		return nil
+	}
+	if v, ok := x.([]float64); ok {
+		return v
+	}
+	slice, ok := x.([]interface{})
+	if !ok {
+		g.addTypeError(key, "a list of numbers", x)
+		return nil
+	}
+	floats := make([]float64, 0, len(slice))
+	for idx, item := range slice {
+		f, ok := toFloat64(item)
+		if !ok {
+			g.addTypeError(indexKey(key, idx), "a number", item)
+			continue
+		}
+		floats = append(floats, f)
+	}
+	return floats
+}
+
+// GetObject returns a getter for the object at key whose problems are reported by this getter
+// as well. If the key is not set, the returned getter is empty.
+func (g *AttributeGetter) GetObject(key string) *AttributeGetter {
+	nested := &AttributeGetter{path: g.keyPath(key), errs: g.errs}
+	x, ok := g.lookup(key)
+	if !ok {
+		return nested
+	}
+	switch v := x.(type) {
+	case AttributeMap:
+		nested.attrs = v
+	case map[string]interface{}:
+		nested.attrs = v
+	default:
+		g.addTypeError(key, "an object", x)
+	}
+	return nested
+}
+
+// Decode decodes the object at key into the struct pointed to by to by its json field names.
+// If the key is not set, to is left untouched.
+func (g *AttributeGetter) Decode(key string, to interface{}) {
+	nested := g.GetObject(key)
+	if nested.attrs == nil {
+		return
+	}
+	if _, err := TransformAttributeMapToStruct(to, nested.attrs); err != nil {
+		g.addError(key, err)
+	}
+}
+
+func (g *AttributeGetter) lookup(key string) (interface{}, bool) {
+	x, ok := g.attrs[key]
+	return x, ok && x != nil
+}
+
+func (g *AttributeGetter) keyPath(key string) string {
+	if g.path == "" {
+		return key
+	}
+	return g.path + "." + key
+}
+
+func (g *AttributeGetter) addError(key string, err error) {
+	*g.errs = multierr.Append(*g.errs, &ValidationError{Path: g.keyPath(key), Err: err})
+}
+
+func (g *AttributeGetter) addTypeError(key, wanted string, x interface{}) {
+	g.addError(key, errors.Errorf("wanted %s but got (%v) %T", wanted, x, x))
+}
+
+func indexKey(key string, idx int) string {
+	return fmt.Sprintf("%s.%d", key, idx)
+}
+
+func toInt(x interface{}) (int, bool) {
+	switch v := x.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		if v == float64(int64(v)) {
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+func toFloat64(x interface{}) (float64, bool) {
+	switch v := x.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
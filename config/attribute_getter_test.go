@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.viam.com/test"
+)
+
+func TestAttributeGetter(t *testing.T) {
+	attrs := AttributeMap{
+		"speed":    1.5,
+		"count":    3.0,
+		"name":     "arm",
+		"enabled":  true,
+		"timeout":  "1.5s",
+		"names":    []interface{}{"a", "b"},
+		"weights":  []interface{}{1.0, 2, 3.5},
+		"nothing":  nil,
+		"position": map[string]interface{}{"x": 1.0, "y": "two", "unit": "mm"},
+	}
+
+	getter := attrs.Getter()
+	test.That(t, getter.GetFloat64("speed", 0), test.ShouldEqual, 1.5)
+	test.That(t, getter.GetInt("count", 0), test.ShouldEqual, 3)
+	test.That(t, getter.GetString("name", ""), test.ShouldEqual, "arm")
+	test.That(t, getter.GetBool("enabled", false), test.ShouldBeTrue)
+	test.That(t, getter.GetDuration("timeout", 0), test.ShouldEqual, 1500*time.Millisecond)
+	test.That(t, getter.GetStringSlice("names"), test.ShouldResemble, []string{"a", "b"})
+	test.That(t, getter.GetFloat64Slice("weights"), test.ShouldResemble, []float64{1, 2, 3.5})
+
+	// unset and nil keys return the default
+	test.That(t, getter.Has("nothing"), test.ShouldBeFalse)
+	test.That(t, getter.GetFloat64("nothing", 2), test.ShouldEqual, 2)
+	test.That(t, getter.GetString("missing", "default"), test.ShouldEqual, "default")
+	test.That(t, getter.GetStringSlice("missing"), test.ShouldBeNil)
+	test.That(t, getter.GetObject("missing").GetInt("x", 4), test.ShouldEqual, 4)
+	test.That(t, getter.Err(), test.ShouldBeNil)
+
+	var position struct {
+		X    float64 `json:"x"`
+		Unit string  `json:"unit"`
+	}
+	getter.Decode("position", &position)
+	test.That(t, getter.Err(), test.ShouldBeNil)
+	test.That(t, position.Unit, test.ShouldEqual, "mm")
+
+	// every problem is collected with the path of its key
+	getter = attrs.Getter()
+	test.That(t, getter.GetFloat64("name", 7), test.ShouldEqual, 7)
+	test.That(t, getter.GetInt("speed", 1), test.ShouldEqual, 1)
+	getter.GetDuration("speed", 0)
+	getter.GetDuration("name", 0)
+	getter.GetStringSlice("weights")
+	position2 := getter.GetObject("position")
+	test.That(t, position2.GetFloat64("x", 0), test.ShouldEqual, 1.0)
+	position2.GetFloat64("y", 0)
+	getter.GetObject("name")
+
+	errs := multierr.Errors(getter.Err())
+	paths := make([]string, 0, len(errs))
+	for _, err := range errs {
+		validationErr, ok := err.(*ValidationError)
+		test.That(t, ok, test.ShouldBeTrue)
+		paths = append(paths, validationErr.Path)
+	}
+	test.That(t, paths, test.ShouldResemble, []string{
+		"name", "speed", "speed", "name", "weights.0", "weights.1", "weights.2", "position.y", "name",
+	})
+	test.That(t, getter.Err().Error(), test.ShouldContainSubstring, "position.y: wanted a number but got (two) string")
+}
@@ -3,10 +3,16 @@ package config
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sync"
 	"time"
@@ -35,6 +41,11 @@ type Config struct {
 	Network    NetworkConfig         `json:"network"`
 	Auth       AuthConfig            `json:"auth"`
 	Debug      bool                  `json:"debug,omitempty"`
+	// Log sets the log levels of individual resources and models. Changes take effect without
+	// rebuilding any resources.
+	Log []ResourceLogConfig `json:"log,omitempty"`
+	// Fragments are local files whose resources are added to the config when it is processed.
+	Fragments []FragmentConfig `json:"fragments,omitempty"`
 
 	ConfigFilePath string `json:"-"`
 
@@ -143,6 +154,15 @@ func (c *Config) Ensure(fromCloud bool) error {
 		}
 	}
 
+	for idx := 0; idx < len(c.Log); idx++ {
+		if err := c.Log[idx].Validate(fmt.Sprintf("%s.%d", "log", idx)); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			golog.Global().Error(errors.Wrap(err, "Log config error, ignoring log level"))
+		}
+	}
+
 	return nil
 }
 
@@ -186,6 +206,9 @@ type Remote struct {
 	Insecure                bool
 	ConnectionCheckInterval time.Duration
 	ReconnectInterval       time.Duration
+	ReconnectMaxInterval    time.Duration
+	LazyConnect             bool
+	TLS                     *RemoteTLS
 	ServiceConfig           []ResourceLevelServiceConfig
 
 	// Secret is a helper for a robot location secret.
@@ -202,6 +225,9 @@ type remoteData struct {
 	Insecure                bool                         `json:"insecure"`
 	ConnectionCheckInterval string                       `json:"connection_check_interval,omitempty"`
 	ReconnectInterval       string                       `json:"reconnect_interval,omitempty"`
+	ReconnectMaxInterval    string                       `json:"reconnect_max_interval,omitempty"`
+	LazyConnect             bool                         `json:"lazy_connect,omitempty"`
+	TLS                     *RemoteTLS                   `json:"tls,omitempty"`
 	ServiceConfig           []ResourceLevelServiceConfig `json:"service_config"`
 
 	// Secret is a helper for a robot location secret.
@@ -221,6 +247,8 @@ func (config *Remote) UnmarshalJSON(data []byte) error {
 		Auth:          temp.Auth,
 		ManagedBy:     temp.ManagedBy,
 		Insecure:      temp.Insecure,
+		LazyConnect:   temp.LazyConnect,
+		TLS:           temp.TLS,
 		ServiceConfig: temp.ServiceConfig,
 		Secret:        temp.Secret,
 	}
@@ -238,6 +266,13 @@ func (config *Remote) UnmarshalJSON(data []byte) error {
 		}
 		config.ReconnectInterval = dur
 	}
+	if temp.ReconnectMaxInterval != "" {
+		dur, err := time.ParseDuration(temp.ReconnectMaxInterval)
+		if err != nil {
+			return err
+		}
+		config.ReconnectMaxInterval = dur
+	}
 	return nil
 }
 
@@ -250,6 +285,8 @@ func (config Remote) MarshalJSON() ([]byte, error) {
 		Auth:          config.Auth,
 		ManagedBy:     config.ManagedBy,
 		Insecure:      config.Insecure,
+		LazyConnect:   config.LazyConnect,
+		TLS:           config.TLS,
 		ServiceConfig: config.ServiceConfig,
 		Secret:        config.Secret,
 	}
@@ -259,9 +296,46 @@ func (config Remote) MarshalJSON() ([]byte, error) {
 	if config.ReconnectInterval != 0 {
 		temp.ReconnectInterval = config.ReconnectInterval.String()
 	}
+	if config.ReconnectMaxInterval != 0 {
+		temp.ReconnectMaxInterval = config.ReconnectMaxInterval.String()
+	}
 	return json.Marshal(temp)
 }
 
+// RemoteTLS specifies TLS settings used only when connecting to a particular remote.
+// When set, they take precedence over the TLS configuration of the robot itself.
+type RemoteTLS struct {
+	// CACertFile is a PEM encoded set of certificate authorities to verify the remote against.
+	CACertFile string `json:"ca_cert_file,omitempty"`
+	// ServerName overrides the name used to verify the remote's certificate.
+	ServerName string `json:"server_name,omitempty"`
+	// InsecureSkipVerify disables verification of the remote's certificate.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// TLSConfig builds the client TLS configuration described by these settings.
+func (rt *RemoteTLS) TLSConfig() (*tls.Config, error) {
+	//nolint:gosec
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         rt.ServerName,
+		InsecureSkipVerify: rt.InsecureSkipVerify,
+	}
+	if rt.CACertFile != "" {
+		//nolint:gosec
+		caCert, err := os.ReadFile(rt.CACertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read remote CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("no valid certificates found in %q", rt.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
 // RemoteAuth specifies how to authenticate against a remote. If no credentials are
 // specified, authentication does not happen. If an entity is specified, the
 // authentication request will specify it.
@@ -292,6 +366,9 @@ func (config *Remote) Validate(path string) error {
 			return utils.NewConfigValidationFieldRequiredError(path, "frame.parent")
 		}
 	}
+	if config.ReconnectMaxInterval != 0 && config.ReconnectMaxInterval < config.ReconnectInterval {
+		return utils.NewConfigValidationError(path, errors.New("reconnect_max_interval must not be less than reconnect_interval"))
+	}
 
 	if config.Secret != "" {
 		config.Auth = RemoteAuth{
@@ -560,6 +637,26 @@ func (sc *SessionsConfig) Validate(path string) error {
 type AuthConfig struct {
 	Handlers        []AuthHandlerConfig `json:"handlers"`
 	TLSAuthEntities []string            `json:"tls_auth_entities"`
+	// AccessPolicies restrict what certain API keys may access. Keys without a policy have full access.
+	AccessPolicies []AccessPolicyConfig `json:"access_policies,omitempty"`
+}
+
+// AccessPolicyConfig scopes a set of API keys to the resources and methods allowed by its rules.
+// Anything not allowed by a rule is denied. Keys under a policy must connect with gRPC directly, as
+// calls over WebRTC are not authenticated per caller.
+type AccessPolicyConfig struct {
+	Name  string             `json:"name"`
+	Keys  []string           `json:"keys"`
+	Rules []AccessRuleConfig `json:"rules"`
+}
+
+// AccessRuleConfig allows calling the given methods on the given resources. Resources may be full
+// resource names (rdk:component:sensor/temp), short names (temp), subtypes (rdk:component:sensor)
+// or "robot" for robot wide methods. Methods are gRPC method names (GetReadings). Both may end in
+// "*" to match by prefix.
+type AccessRuleConfig struct {
+	Resources []string `json:"resources"`
+	Methods   []string `json:"methods"`
 }
 
 // WebOAuthConfig contains the structued AuthHandlerConfig for the CredentialsTypeOAuthWeb type.
@@ -647,6 +744,43 @@ func (config *AuthConfig) Validate(path string) error {
 			return err
 		}
 	}
+
+	apiKeys := map[string]bool{}
+	for _, handler := range config.Handlers {
+		if handler.Type != rpc.CredentialsTypeAPIKey {
+			continue
+		}
+		if key := handler.Config.String("key"); key != "" {
+			apiKeys[key] = true
+		}
+		for _, key := range handler.Config.StringSlice("keys") {
+			apiKeys[key] = true
+		}
+	}
+	seenPolicies := make(map[string]struct{}, len(config.AccessPolicies))
+	seenKeys := map[string]struct{}{}
+	for idx, policy := range config.AccessPolicies {
+		policyPath := fmt.Sprintf("%s.%s.%d", path, "access_policies", idx)
+		if policy.Name == "" {
+			return utils.NewConfigValidationFieldRequiredError(policyPath, "name")
+		}
+		if _, ok := seenPolicies[policy.Name]; ok {
+			return utils.NewConfigValidationError(policyPath, errors.Errorf("duplicate access policy %q", policy.Name))
+		}
+		seenPolicies[policy.Name] = struct{}{}
+		if len(policy.Keys) == 0 {
+			return utils.NewConfigValidationFieldRequiredError(policyPath, "keys")
+		}
+		for _, key := range policy.Keys {
+			if !apiKeys[key] {
+				return utils.NewConfigValidationError(policyPath, errors.New("keys must be configured in an api-key handler"))
+			}
+			if _, ok := seenKeys[key]; ok {
+				return utils.NewConfigValidationError(policyPath, errors.New("a key may only belong to one access policy"))
+			}
+			seenKeys[key] = struct{}{}
+		}
+	}
 	return nil
 }
 
@@ -767,20 +901,53 @@ type Updateable interface {
 // Valid package name regex.
 var packageNameRegEx = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
 
+// Valid hex encoded SHA-256 checksum regex.
+var sha256RegEx = regexp.MustCompile(`^[A-Fa-f0-9]{64}$`)
+
 // Regex to match if a config is referencing a Package. Group is the package name.
 var packageReferenceRegex = regexp.MustCompile(`^\$\{packages\.([A-Za-z0-9_\/-]+)}(.*)`)
 
 // DefaultPackageVersionValue default value of the package version used when empty.
 const DefaultPackageVersionValue = "latest"
 
+// A PackageSource is where the contents of a package come from.
+type PackageSource string
+
+// The sources packages can come from.
+const (
+	// PackageSourceCloud packages are hosted by the package service of the Viam app. Package is the
+	// package id. This is the default.
+	PackageSourceCloud PackageSource = ""
+	// PackageSourceLocal packages are existing directories on the robot. Package is the absolute path
+	// of the directory.
+	PackageSourceLocal PackageSource = "local"
+	// PackageSourceHTTP packages are gzipped tarballs downloaded over HTTPS. Package is the URL of the
+	// tarball.
+	PackageSourceHTTP PackageSource = "http"
+	// PackageSourceGit packages are git repositories. Package is the URL of the repository and
+	// Version the ref to check out, defaulting to the repository's default branch.
+	PackageSourceGit PackageSource = "git"
+)
+
 // A PackageConfig describes the configuration of a Package.
 type PackageConfig struct {
 	// Name is the local name of the package on the RDK. Must be unique across Packages. Must not be empty.
 	Name string `json:"name"`
 	// Package is the unqiue package name hosted by a remote PackageService. Must not be empty.
+	// For other sources its meaning depends on the Source.
 	Package string `json:"package"`
 	// Version of the package ID hosted by a remote PackageService. If not specified "latest" is assumed.
 	Version string `json:"version,omitempty"`
+	// Source is where the package comes from. If not specified the package service is assumed.
+	Source PackageSource `json:"source,omitempty"`
+	// SHA256 is the expected hex encoded SHA-256 checksum of the archive downloaded from the
+	// package service or an HTTP source. Downloads that do not match are rejected.
+	SHA256 string `json:"sha256,omitempty"`
+	// Signature is an optional base64 encoded ed25519 signature of the SHA-256 digest of the
+	// downloaded archive, made with the private key matching PublicKey.
+	Signature string `json:"signature,omitempty"`
+	// PublicKey is the base64 encoded ed25519 public key that Signature is checked with.
+	PublicKey string `json:"public_key,omitempty"`
 }
 
 // Validate package config is valid.
@@ -797,6 +964,40 @@ func (p *PackageConfig) Validate(path string) error {
 		return errors.Errorf("package %s name must contain only letters, numbers, underscores and hyphens", path)
 	}
 
+	switch p.Source {
+	case PackageSourceCloud:
+	case PackageSourceLocal, PackageSourceGit:
+		if p.SHA256 != "" || p.Signature != "" {
+			return utils.NewConfigValidationError(path, errors.Errorf("%s packages cannot be verified by checksum or signature", p.Source))
+		}
+		if p.Source == PackageSourceLocal && !filepath.IsAbs(p.Package) {
+			return utils.NewConfigValidationError(path, errors.New("local package must be an absolute path"))
+		}
+	case PackageSourceHTTP:
+		u, err := url.Parse(p.Package)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return utils.NewConfigValidationError(path, errors.New("http package must be an https URL"))
+		}
+	default:
+		return utils.NewConfigValidationError(path, errors.Errorf("unknown package source %q", p.Source))
+	}
+
+	if p.SHA256 != "" && !sha256RegEx.MatchString(p.SHA256) {
+		return utils.NewConfigValidationError(path, errors.New("sha256 must be a hex encoded SHA-256 checksum"))
+	}
+	if p.Signature != "" || p.PublicKey != "" {
+		if p.Signature == "" || p.PublicKey == "" {
+			return utils.NewConfigValidationError(path, errors.New("signature and public_key must be set together"))
+		}
+		if _, err := base64.StdEncoding.DecodeString(p.Signature); err != nil {
+			return utils.NewConfigValidationError(path, errors.New("signature must be base64 encoded"))
+		}
+		key, err := base64.StdEncoding.DecodeString(p.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return utils.NewConfigValidationError(path, errors.New("public_key must be a base64 encoded ed25519 public key"))
+		}
+	}
+
 	return nil
 }
 
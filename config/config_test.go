@@ -5,10 +5,12 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -120,6 +122,42 @@ func TestConfig3(t *testing.T) {
 	test.That(t, cfg.Remotes[0].ReconnectInterval, test.ShouldEqual, 3*time.Second)
 }
 
+func TestRemoteConnectionSettings(t *testing.T) {
+	var remote config.Remote
+	err := json.Unmarshal([]byte(`{
+		"name": "rover",
+		"address": "rover.local:8080",
+		"reconnect_interval": "1s",
+		"reconnect_max_interval": "30s",
+		"lazy_connect": true,
+		"tls": {"server_name": "rover.viam.cloud", "insecure_skip_verify": true}
+	}`), &remote)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, remote.ReconnectMaxInterval, test.ShouldEqual, 30*time.Second)
+	test.That(t, remote.LazyConnect, test.ShouldBeTrue)
+	test.That(t, remote.TLS, test.ShouldResemble, &config.RemoteTLS{ServerName: "rover.viam.cloud", InsecureSkipVerify: true})
+	test.That(t, remote.Validate("path"), test.ShouldBeNil)
+
+	tlsConfig, err := remote.TLS.TLSConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tlsConfig.ServerName, test.ShouldEqual, "rover.viam.cloud")
+
+	md, err := json.Marshal(remote)
+	test.That(t, err, test.ShouldBeNil)
+	var roundTrip config.Remote
+	test.That(t, json.Unmarshal(md, &roundTrip), test.ShouldBeNil)
+	test.That(t, roundTrip, test.ShouldResemble, remote)
+
+	remote.ReconnectMaxInterval = 500 * time.Millisecond
+	err = remote.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "reconnect_max_interval")
+
+	remote.TLS = &config.RemoteTLS{CACertFile: "/does/not/exist.pem"}
+	_, err = remote.TLS.TLSConfig()
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestCreateCloudRequest(t *testing.T) {
 	cfg := config.Cloud{
 		ID:     "a",
@@ -945,6 +983,45 @@ func TestAuthConfigEnsure(t *testing.T) {
 		test.That(t, err, test.ShouldNotBeNil)
 		test.That(t, err.Error(), test.ShouldContainSubstring, "must contain at least 1 key")
 	})
+
+	t.Run("access policies", func(t *testing.T) {
+		newConfig := func(policies ...config.AccessPolicyConfig) config.Config {
+			return config.Config{
+				Auth: config.AuthConfig{
+					Handlers: []config.AuthHandlerConfig{
+						{
+							Type:   rpc.CredentialsTypeAPIKey,
+							Config: config.AttributeMap{"keys": []string{"abc123", "def456"}},
+						},
+					},
+					AccessPolicies: policies,
+				},
+			}
+		}
+		readOnly := config.AccessPolicyConfig{
+			Name:  "read-only",
+			Keys:  []string{"def456"},
+			Rules: []config.AccessRuleConfig{{Resources: []string{"rdk:component:sensor"}, Methods: []string{"Get*"}}},
+		}
+
+		cfg := newConfig(readOnly)
+		test.That(t, cfg.Ensure(true), test.ShouldBeNil)
+
+		cfg = newConfig(config.AccessPolicyConfig{Keys: []string{"def456"}})
+		err := cfg.Ensure(true)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `"name" is required`)
+
+		cfg = newConfig(readOnly, readOnly)
+		err = cfg.Ensure(true)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "duplicate access policy")
+
+		cfg = newConfig(config.AccessPolicyConfig{Name: "unknown", Keys: []string{"nope"}})
+		err = cfg.Ensure(true)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be configured in an api-key handler")
+	})
 }
 
 func keysetToAttributeMap(t *testing.T, keyset jwks.KeySet) config.AttributeMap {
@@ -989,3 +1066,40 @@ func TestGetPackageReference(t *testing.T) {
 			&config.PackageReference{Package: "some-package", PathInPackage: ""})
 	})
 }
+
+func TestPackageConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		pkg config.PackageConfig
+		err string
+	}{
+		{config.PackageConfig{Name: "model", Package: "org1/model", Version: "v1"}, ""},
+		{config.PackageConfig{Name: "model", Package: "/opt/models/model", Source: config.PackageSourceLocal}, ""},
+		{config.PackageConfig{Name: "model", Package: "models/model", Source: config.PackageSourceLocal}, "absolute path"},
+		{config.PackageConfig{Name: "model", Package: "https://example.com/model.tar.gz", Source: config.PackageSourceHTTP}, ""},
+		{config.PackageConfig{Name: "model", Package: "http://example.com/model.tar.gz", Source: config.PackageSourceHTTP}, "https URL"},
+		{config.PackageConfig{
+			Name: "model", Package: "https://example.com/model.tar.gz", Source: config.PackageSourceHTTP, SHA256: "abc",
+		}, "SHA-256"},
+		{config.PackageConfig{Name: "model", Package: "https://github.com/acme/model.git", Version: "v1.2.0", Source: config.PackageSourceGit}, ""},
+		{config.PackageConfig{Name: "model", Package: "org1/model", Source: "ftp"}, `unknown package source "ftp"`},
+		{config.PackageConfig{
+			Name: "model", Package: "org1/model", SHA256: strings.Repeat("a", 64),
+			Signature: "c2lnbmF0dXJl", PublicKey: base64.StdEncoding.EncodeToString(make([]byte, 32)),
+		}, ""},
+		{config.PackageConfig{Name: "model", Package: "org1/model", Signature: "c2lnbmF0dXJl"}, "set together"},
+		{config.PackageConfig{
+			Name: "model", Package: "org1/model", Signature: "c2lnbmF0dXJl", PublicKey: "a2V5",
+		}, "ed25519 public key"},
+		{config.PackageConfig{
+			Name: "model", Package: "https://github.com/acme/model.git", Source: config.PackageSourceGit, SHA256: strings.Repeat("a", 64),
+		}, "cannot be verified"},
+	} {
+		err := tc.pkg.Validate("packages.0")
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+}
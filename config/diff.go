@@ -3,8 +3,10 @@ package config
 import (
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
 	"go.viam.com/utils/pexec"
@@ -175,6 +177,57 @@ func (diff *Diff) String() string {
 	return diff.PrettyDiff
 }
 
+// Summary lists, by name, what the diff adds, modifies and removes. Unlike the pretty
+// diff it never contains attribute values, so it is safe to log.
+func (diff *Diff) Summary() string {
+	var parts []string
+	for _, change := range []struct {
+		verb  string
+		names []string
+	}{
+		{"added", changedNames(diff.Added.Components, diff.Added.Services, diff.Added.Remotes,
+			diff.Added.Processes, diff.Added.Packages)},
+		{"modified", changedNames(diff.Modified.Components, diff.Modified.Services, diff.Modified.Remotes,
+			diff.Modified.Processes, diff.Modified.Packages)},
+		{"removed", changedNames(diff.Removed.Components, diff.Removed.Services, diff.Removed.Remotes,
+			diff.Removed.Processes, diff.Removed.Packages)},
+	} {
+		if len(change.names) != 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", change.verb, strings.Join(change.names, ", ")))
+		}
+	}
+	if len(parts) == 0 {
+		return "no resource changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func changedNames(
+	components []Component,
+	services []Service,
+	remotes []Remote,
+	processes []pexec.ProcessConfig,
+	packages []PackageConfig,
+) []string {
+	var names []string
+	for _, c := range components {
+		names = append(names, c.ResourceName().String())
+	}
+	for _, s := range services {
+		names = append(names, s.ResourceName().String())
+	}
+	for _, r := range remotes {
+		names = append(names, "remote "+r.Name)
+	}
+	for _, p := range processes {
+		names = append(names, "process "+p.ID)
+	}
+	for _, p := range packages {
+		names = append(names, "package "+p.Name)
+	}
+	return names
+}
+
 func diffRemotes(left, right []Remote, diff *Diff) bool {
 	leftIndex := make(map[string]int)
 	leftM := make(map[string]Remote)
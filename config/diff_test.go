@@ -676,3 +676,34 @@ func TestDiffMedia(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffSummary(t *testing.T) {
+	left := config.Config{
+		Components: []config.Component{
+			{Name: "arm1", Namespace: resource.ResourceNamespaceRDK, Type: arm.SubtypeName, Model: fakeModel},
+			{Name: "base1", Namespace: resource.ResourceNamespaceRDK, Type: base.SubtypeName, Model: fakeModel},
+		},
+		Processes: []pexec.ProcessConfig{{ID: "1", Name: "echo"}},
+	}
+	right := config.Config{
+		Components: []config.Component{
+			{
+				Name: "arm1", Namespace: resource.ResourceNamespaceRDK, Type: arm.SubtypeName, Model: fakeModel,
+				Attributes: config.AttributeMap{"secret": "hunter2"},
+			},
+			{Name: "board1", Namespace: resource.ResourceNamespaceRDK, Type: board.SubtypeName, Model: fakeModel},
+		},
+		Remotes: []config.Remote{{Name: "remote1", Address: "localhost:8081"}},
+	}
+
+	diff, err := config.DiffConfigs(left, right, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.Summary(), test.ShouldEqual,
+		"added: rdk:component:board/board1, remote remote1; "+
+			"modified: rdk:component:arm/arm1; "+
+			"removed: rdk:component:base/base1, process 1")
+
+	diff, err = config.DiffConfigs(left, left, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.Summary(), test.ShouldEqual, "no resource changes")
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"go.viam.com/utils/pexec"
+)
+
+// A FragmentConfig includes the resources of a local fragment file into a config.
+type FragmentConfig struct {
+	// Path is the fragment file. Relative paths are relative to the directory of the config
+	// file including it.
+	Path string `json:"path"`
+	// Params are substituted for the `${params.NAME}` references in the fragment, overriding the
+	// defaults the fragment declares.
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (f *FragmentConfig) Validate(path string) error {
+	if f.Path == "" {
+		return utils.NewConfigValidationError(path, errors.New("path is required"))
+	}
+	return nil
+}
+
+// filePath returns the fragment file, resolving a relative path against the directory of the
+// given config file.
+func (f *FragmentConfig) filePath(configFilePath string) string {
+	if filepath.IsAbs(f.Path) || configFilePath == "" {
+		return f.Path
+	}
+	return filepath.Join(filepath.Dir(configFilePath), f.Path)
+}
+
+// A fragment is the contents of a fragment file. It is written like a config, but may only
+// hold resources, along with default values for its parameters.
+type fragment struct {
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Modules    []Module               `json:"modules,omitempty"`
+	Remotes    []Remote               `json:"remotes,omitempty"`
+	Components []Component            `json:"components,omitempty"`
+	Processes  []pexec.ProcessConfig  `json:"processes,omitempty"`
+	Services   []Service              `json:"services,omitempty"`
+	Packages   []PackageConfig        `json:"packages,omitempty"`
+}
+
+// paramPattern matches `${params.NAME}` fragment parameter references.
+var paramPattern = regexp.MustCompile(`\$\{params\.([A-Za-z0-9_]+)\}`)
+
+// includeFragments adds the resources of every fragment to the config. A fragment that fails
+// to load is left out, unless partial start is disabled.
+func (c *Config) includeFragments() error {
+	for idx := range c.Fragments {
+		if err := c.includeFragment(fmt.Sprintf("%s.%d", "fragments", idx), c.Fragments[idx]); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			golog.Global().Error(errors.Wrap(err, "Fragment config error, starting robot without fragment: "+c.Fragments[idx].Path))
+		}
+	}
+	return nil
+}
+
+// includeFragment loads a fragment and appends its resources to the config.
+func (c *Config) includeFragment(path string, f FragmentConfig) error {
+	if err := f.Validate(path); err != nil {
+		return err
+	}
+	frag, err := readFragment(f.filePath(c.ConfigFilePath), f.Params)
+	if err != nil {
+		return utils.NewConfigValidationError(path, err)
+	}
+	c.Modules = append(c.Modules, frag.Modules...)
+	c.Remotes = append(c.Remotes, frag.Remotes...)
+	c.Components = append(c.Components, frag.Components...)
+	c.Processes = append(c.Processes, frag.Processes...)
+	c.Services = append(c.Services, frag.Services...)
+	c.Packages = append(c.Packages, frag.Packages...)
+	return nil
+}
+
+// readFragment reads a fragment file and substitutes the given parameters, or the defaults of
+// the fragment, for its parameter references. Environment variables are substituted like they
+// are in config files.
+func readFragment(filePath string, params map[string]interface{}) (*fragment, error) {
+	buf, err := readFileWithEnv(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read fragment %q", filePath)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(buf, &raw); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode fragment %q from json", filePath)
+	}
+	if _, ok := raw["fragments"]; ok {
+		return nil, errors.Errorf("fragment %q cannot include other fragments", filePath)
+	}
+
+	values := map[string]interface{}{}
+	if defaults, ok := raw["params"].(map[string]interface{}); ok {
+		for k, v := range defaults {
+			values[k] = v
+		}
+	}
+	for k, v := range params {
+		values[k] = v
+	}
+	delete(raw, "params")
+	substituted, err := substituteParams(raw, values, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "fragment %q", filePath)
+	}
+
+	buf, err = json.Marshal(substituted)
+	if err != nil {
+		return nil, err
+	}
+	var frag fragment
+	if err := json.Unmarshal(buf, &frag); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode fragment %q", filePath)
+	}
+	return &frag, nil
+}
+
+// substituteParams replaces the parameter references in all string values of v, descending
+// into nested maps and lists. A string that is a single reference is replaced by the value of
+// the parameter itself, so that numbers, bools and objects keep their type; otherwise the
+// value is formatted into the string.
+func substituteParams(v interface{}, params map[string]interface{}, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		if match := paramPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			value, ok := params[match[1]]
+			if !ok {
+				return nil, errors.Errorf("parameter %q of %q is not set", match[1], path)
+			}
+			return value, nil
+		}
+		var err error
+		out := paramPattern.ReplaceAllStringFunc(v, func(ref string) string {
+			name := paramPattern.FindStringSubmatch(ref)[1]
+			value, ok := params[name]
+			if !ok {
+				if err == nil {
+					err = errors.Errorf("parameter %q of %q is not set", name, path)
+				}
+				return ref
+			}
+			return fmt.Sprint(value)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	case map[string]interface{}:
+		// keys are visited in order so that the first missing parameter reported does not change
+		// from one read of the fragment to the next.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			elem := v[k]
+			elemPath := k
+			if path != "" {
+				elemPath = path + "." + k
+			}
+			resolved, err := substituteParams(elem, params, elemPath)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+		return v, nil
+	case []interface{}:
+		for i, elem := range v {
+			resolved, err := substituteParams(elem, params, fmt.Sprintf("%s.%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.uber.org/multierr"
+	"go.viam.com/test"
+)
+
+func TestReadFragments(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	t.Setenv("RDK_TEST_BOARD", "pi")
+	dir := t.TempDir()
+
+	test.That(t, os.WriteFile(filepath.Join(dir, "motor.json"), []byte(`{
+		"params": {"name": "motor", "max_rpm": 100},
+		"components": [{
+			"name": "${params.name}-left", "type": "motor", "model": "fake",
+			"attributes": {"board": "${RDK_TEST_BOARD}", "pin": "${params.pin}", "max_rpm": "${params.max_rpm}",
+				"label": "pin ${params.pin}"}
+		}]
+	}`), 0o600), test.ShouldBeNil)
+
+	configPath := filepath.Join(dir, "robot.json")
+	writeConfig := func(fragments string) {
+		test.That(t, os.WriteFile(configPath, []byte(`{
+			"components": [{"name": "board1", "type": "board", "model": "fake"}],
+			"fragments": `+fragments+`
+		}`), 0o600), test.ShouldBeNil)
+	}
+
+	writeConfig(`[{"path": "motor.json", "params": {"pin": 37}}, {"path": "motor.json", "params": {"name": "m2", "pin": "38"}}]`)
+	cfg, err := ReadLocalConfig(context.Background(), configPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components, test.ShouldHaveLength, 3)
+	test.That(t, cfg.Components[1].Name, test.ShouldEqual, "motor-left")
+	test.That(t, cfg.Components[1].Attributes, test.ShouldResemble, AttributeMap{
+		"board": "pi", "pin": 37.0, "max_rpm": 100.0, "label": "pin 37",
+	})
+	test.That(t, cfg.Components[2].Name, test.ShouldEqual, "m2-left")
+	test.That(t, cfg.Components[2].Attributes["pin"], test.ShouldEqual, "38")
+
+	// fragments missing parameters or files are left out
+	writeConfig(`[{"path": "motor.json"}, {"path": "missing.json"}]`)
+	cfg, err = ReadLocalConfig(context.Background(), configPath, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components, test.ShouldHaveLength, 1)
+
+	err = ValidateConfig(&Config{
+		ConfigFilePath: configPath,
+		Fragments:      []FragmentConfig{{Path: "motor.json"}, {Path: "missing.json"}, {}},
+	}, false)
+	errs := multierr.Errors(err)
+	test.That(t, errs, test.ShouldHaveLength, 3)
+	test.That(t, errs[0].Error(), test.ShouldContainSubstring, `parameter "pin" of "components.0.attributes.label" is not set`)
+	test.That(t, errs[1].(*ValidationError).Path, test.ShouldEqual, "fragments.1")
+	test.That(t, errs[2].Error(), test.ShouldContainSubstring, "path is required")
+
+	// with partial start disabled a broken fragment fails the config
+	_, err = processConfigLocalConfig(&Config{
+		ConfigFilePath:      configPath,
+		DisablePartialStart: true,
+		Fragments:           []FragmentConfig{{Path: "motor.json"}},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+}
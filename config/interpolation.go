@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/a8m/envsubst"
+	"github.com/pkg/errors"
+)
+
+// ErrSecretNotFound is returned by a SecretResolver for a secret it does not know.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// A SecretResolver returns the value of the named secret, or ErrSecretNotFound.
+type SecretResolver func(name string) (string, error)
+
+// secretsDirEnvVar overrides the directory the default SecretResolver reads secrets from.
+const secretsDirEnvVar = "VIAM_SECRETS_DIR"
+
+var (
+	secretResolverMu sync.Mutex
+	secretResolver   SecretResolver = fileSecretResolver
+)
+
+// RegisterSecretResolver replaces how `${secret:NAME}` references in attributes are resolved.
+// By default, a secret is the contents of the file NAME in $VIAM_SECRETS_DIR, which defaults
+// to ~/.viam/secrets.
+func RegisterSecretResolver(resolver SecretResolver) {
+	secretResolverMu.Lock()
+	defer secretResolverMu.Unlock()
+	secretResolver = resolver
+}
+
+func lookupSecret(name string) (string, error) {
+	secretResolverMu.Lock()
+	resolver := secretResolver
+	secretResolverMu.Unlock()
+	return resolver(name)
+}
+
+func fileSecretResolver(name string) (string, error) {
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", errors.Errorf("invalid secret name %q", name)
+	}
+	dir := os.Getenv(secretsDirEnvVar)
+	if dir == "" {
+		dir = filepath.Join(viamDotDir, "secrets")
+	}
+	//nolint:gosec
+	value, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrSecretNotFound
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(value), "\r\n"), nil
+}
+
+// referencePattern matches `${NAME}` environment variable and `${secret:NAME}` secret references,
+// and `$${` escapes, which stand for a literal `${`.
+var referencePattern = regexp.MustCompile(`\$\$\{|\$\{(?:secret:([^}]+)|([A-Za-z_][A-Za-z0-9_]*))\}`)
+
+// interpolate replaces every reference in s with its value, and every escaped `$${` with `${`.
+// Anything else that looks like a reference, such as `${not a name}`, is left as it is.
+func interpolate(s string) (string, error) {
+	var err error
+	out := referencePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		if err != nil {
+			return ref
+		}
+		if ref == "$${" {
+			return "${"
+		}
+		match := referencePattern.FindStringSubmatch(ref)
+		if secret := match[1]; secret != "" {
+			var value string
+			value, err = lookupSecret(secret)
+			if errors.Is(err, ErrSecretNotFound) {
+				err = errors.Errorf("secret %q is not set", secret)
+			}
+			return value
+		}
+		value, ok := os.LookupEnv(match[2])
+		if !ok {
+			err = errors.Errorf("environment variable %q is not set", match[2])
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// interpolateAttributes resolves the references in all string values of attrs, descending
+// into nested maps and lists. The path of the offending attribute is part of any error.
+func interpolateAttributes(attrs AttributeMap) error {
+	for k, v := range attrs {
+		resolved, err := interpolateValue(v, k)
+		if err != nil {
+			return err
+		}
+		attrs[k] = resolved
+	}
+	return nil
+}
+
+func interpolateValue(v interface{}, path string) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		resolved, err := interpolate(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "attribute %q", path)
+		}
+		return resolved, nil
+	case map[string]interface{}:
+		for k, elem := range v {
+			resolved, err := interpolateValue(elem, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+		return v, nil
+	case AttributeMap:
+		for k, elem := range v {
+			resolved, err := interpolateValue(elem, path+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+		return v, nil
+	case []interface{}:
+		for i, elem := range v {
+			resolved, err := interpolateValue(elem, fmt.Sprintf("%s.%d", path, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// fileReferencePattern matches anything in a config file that envsubst would substitute in braces,
+// along with `$${` escapes.
+var fileReferencePattern = regexp.MustCompile(`\$?\$\{[^}]*\}?`)
+
+// envsubstExpressionPattern matches the `${NAME:-default}` style expressions envsubst supports.
+var envsubstExpressionPattern = regexp.MustCompile(`^\$\{[A-Za-z_][A-Za-z0-9_]*:?[-=+?]`)
+
+// readFileWithEnv reads a config file and substitutes environment variables in it.
+// References that are left for attribute interpolation, that is secrets and unset
+// environment variables, are escaped so that they survive substitution and decode back to
+// themselves from JSON. This way they can be reported clearly rather than becoming empty.
+// Fragment parameter references, `$${` escapes and anything else envsubst does not know
+// how to substitute are escaped the same way, so that they are left as they are.
+func readFileWithEnv(filePath string) ([]byte, error) {
+	//nolint:gosec
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	raw = fileReferencePattern.ReplaceAllFunc(raw, func(ref []byte) []byte {
+		if match := referencePattern.FindSubmatch(ref); match != nil && len(match[2]) != 0 && len(match[0]) == len(ref) {
+			if _, ok := os.LookupEnv(string(match[2])); ok {
+				return ref
+			}
+		}
+		if envsubstExpressionPattern.Match(ref) && ref[len(ref)-1] == '}' {
+			return ref
+		}
+		if ref[1] == '$' {
+			return append([]byte(`\u0024\u0024`), ref[2:]...)
+		}
+		return append([]byte(`\u0024`), ref[1:]...)
+	})
+	return envsubst.Bytes(raw)
+}
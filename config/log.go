@@ -0,0 +1,51 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A ResourceLogConfig sets the log level of the components and services it matches. Exactly one
+// of Resource and Model must be set. Levels set for a resource take precedence over levels set
+// for its model, which take precedence over the level of the robot.
+type ResourceLogConfig struct {
+	// Resource is the name of a component or service, such as "arm1".
+	Resource string `json:"resource,omitempty"`
+	// Model matches every component and service of a model, such as "rdk:builtin:fake".
+	Model string `json:"model,omitempty"`
+	// Level is one of "debug", "info", "warn" or "error".
+	Level string `json:"level"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (c *ResourceLogConfig) Validate(path string) error {
+	if (c.Resource == "") == (c.Model == "") {
+		return utils.NewConfigValidationError(path, errors.New("exactly one of resource and model must be set"))
+	}
+	if c.Model != "" {
+		if _, err := resource.NewModelFromString(c.Model); err != nil {
+			return utils.NewConfigValidationError(path, err)
+		}
+	}
+	if _, err := zapcore.ParseLevel(c.Level); err != nil {
+		return utils.NewConfigValidationError(path, err)
+	}
+	return nil
+}
+
+// ParsedModel returns the model the config matches, if any.
+func (c *ResourceLogConfig) ParsedModel() (resource.Model, bool) {
+	if c.Model == "" {
+		return resource.Model{}, false
+	}
+	model, err := resource.NewModelFromString(c.Model)
+	return model, err == nil
+}
+
+// ParsedLevel returns the log level of the config.
+func (c *ResourceLogConfig) ParsedLevel() (zapcore.Level, error) {
+	return zapcore.ParseLevel(c.Level)
+}
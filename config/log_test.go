@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+func TestResourceLogConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		conf ResourceLogConfig
+		err  string
+	}{
+		{ResourceLogConfig{Resource: "arm1", Level: "debug"}, ""},
+		{ResourceLogConfig{Model: "rdk:builtin:fake", Level: "warn"}, ""},
+		{ResourceLogConfig{Model: "fake", Level: "error"}, ""},
+		{ResourceLogConfig{Level: "debug"}, "exactly one"},
+		{ResourceLogConfig{Resource: "arm1", Model: "fake", Level: "debug"}, "exactly one"},
+		{ResourceLogConfig{Model: "a:b:c:d", Level: "debug"}, "not a valid model name"},
+		{ResourceLogConfig{Resource: "arm1", Level: "verbose"}, "unrecognized level"},
+	} {
+		err := tc.conf.Validate("log.0")
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+
+	conf := ResourceLogConfig{Model: "fake", Level: "debug"}
+	model, ok := conf.ParsedModel()
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, model, test.ShouldResemble, resource.NewDefaultModel("fake"))
+	level, err := conf.ParsedLevel()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, level, test.ShouldEqual, zapcore.DebugLevel)
+}
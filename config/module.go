@@ -17,6 +17,9 @@ type Module struct {
 	Name string `json:"name"`
 	// ExePath is the path (either absolute, or relative to the working directory) to the executable module file.
 	ExePath string `json:"executable_path"`
+	// Version is an optional, opaque version of the module. Changing it causes a running module
+	// to be restarted even if the executable itself has not changed.
+	Version string `json:"version,omitempty"`
 }
 
 // Validate checks if the config is valid.
@@ -12,8 +12,8 @@ import (
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"sync"
 
-	"github.com/a8m/envsubst"
 	"github.com/edaniels/golog"
 	"github.com/mitchellh/copystructure"
 	"github.com/mitchellh/mapstructure"
@@ -69,6 +69,7 @@ type ServiceAttributeMapConverterRegistration struct {
 }
 
 var (
+	convertersMu                    sync.RWMutex
 	componentAttributeConverters    = []ComponentAttributeConverterRegistration{}
 	componentAttributeMapConverters = []ComponentAttributeMapConverterRegistration{}
 	serviceAttributeMapConverters   = []ServiceAttributeMapConverterRegistration{}
@@ -77,6 +78,8 @@ var (
 // RegisterComponentAttributeConverter associates a component type and model with a way to convert a
 // particular attribute name.
 func RegisterComponentAttributeConverter(subtype resource.Subtype, model resource.Model, attr string, conv AttributeConverter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
 	componentAttributeConverters = append(componentAttributeConverters, ComponentAttributeConverterRegistration{subtype, model, attr, conv})
 }
 
@@ -90,6 +93,8 @@ func RegisterComponentAttributeMapConverter(
 	if retType == nil {
 		panic("retType should not be nil")
 	}
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
 	componentAttributeMapConverters = append(
 		componentAttributeMapConverters,
 		ComponentAttributeMapConverterRegistration{subtype, model, conv, retType},
@@ -146,14 +151,53 @@ func RegisterServiceAttributeMapConverter(
 	if retType == nil {
 		panic("retType should not be nil")
 	}
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
 	serviceAttributeMapConverters = append(
 		serviceAttributeMapConverters,
 		ServiceAttributeMapConverterRegistration{svcType, model, conv, retType},
 	)
 }
 
+// DeregisterComponentAttributeConverters removes every attribute and attribute map converter
+// registered for a component type and model.
+func DeregisterComponentAttributeConverters(subtype resource.Subtype, model resource.Model) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	attrConvs := make([]ComponentAttributeConverterRegistration, 0, len(componentAttributeConverters))
+	for _, r := range componentAttributeConverters {
+		if !(r.Subtype == subtype && r.Model == model) {
+			attrConvs = append(attrConvs, r)
+		}
+	}
+	componentAttributeConverters = attrConvs
+	mapConvs := make([]ComponentAttributeMapConverterRegistration, 0, len(componentAttributeMapConverters))
+	for _, r := range componentAttributeMapConverters {
+		if !(r.Subtype == subtype && r.Model == model) {
+			mapConvs = append(mapConvs, r)
+		}
+	}
+	componentAttributeMapConverters = mapConvs
+}
+
+// DeregisterServiceAttributeMapConverter removes the attribute map converter registered for a
+// service type and model.
+func DeregisterServiceAttributeMapConverter(svcType resource.Subtype, model resource.Model) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	mapConvs := make([]ServiceAttributeMapConverterRegistration, 0, len(serviceAttributeMapConverters))
+	for _, r := range serviceAttributeMapConverters {
+		if !(r.SvcType == svcType && r.Model == model) {
+			mapConvs = append(mapConvs, r)
+		}
+	}
+	serviceAttributeMapConverters = mapConvs
+}
+
 // RegisteredComponentAttributeConverters returns a copy of the registered component attribute converters.
 func RegisteredComponentAttributeConverters() []ComponentAttributeConverterRegistration {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
 	copied, err := copystructure.Copy(componentAttributeConverters)
 	if err != nil {
 		panic(err)
@@ -163,6 +207,8 @@ func RegisteredComponentAttributeConverters() []ComponentAttributeConverterRegis
 
 // RegisteredComponentAttributeMapConverters returns a copy of the registered component attribute converters.
 func RegisteredComponentAttributeMapConverters() []ComponentAttributeMapConverterRegistration {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
 	copied, err := copystructure.Copy(componentAttributeMapConverters)
 	if err != nil {
 		panic(err)
@@ -172,6 +218,8 @@ func RegisteredComponentAttributeMapConverters() []ComponentAttributeMapConverte
 
 // RegisteredServiceAttributeMapConverters returns a copy of the registered component attribute converters.
 func RegisteredServiceAttributeMapConverters() []ServiceAttributeMapConverterRegistration {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
 	copied, err := copystructure.Copy(serviceAttributeMapConverters)
 	if err != nil {
 		panic(err)
@@ -180,6 +228,8 @@ func RegisteredServiceAttributeMapConverters() []ServiceAttributeMapConverterReg
 }
 
 func findConverter(subtype resource.Subtype, model resource.Model, attr string) AttributeConverter {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
 	for _, r := range componentAttributeConverters {
 		if r.Subtype == subtype && r.Model == model && r.Attr == attr {
 			return r.Conv
@@ -189,6 +239,8 @@ func findConverter(subtype resource.Subtype, model resource.Model, attr string)
 }
 
 func findMapConverter(subtype resource.Subtype, model resource.Model) AttributeMapConverter {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
 	for _, r := range componentAttributeMapConverters {
 		if r.Subtype == subtype && r.Model == model {
 			return r.Conv
@@ -198,6 +250,8 @@ func findMapConverter(subtype resource.Subtype, model resource.Model) AttributeM
 }
 
 func findServiceMapConverter(svcType resource.Subtype, model resource.Model) AttributeMapConverter {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
 	for _, r := range serviceAttributeMapConverters {
 		if r.SvcType == svcType && r.Model == model {
 			return r.Conv
@@ -567,7 +621,7 @@ func Read(
 	filePath string,
 	logger golog.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := readFileWithEnv(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -581,7 +635,7 @@ func ReadLocalConfig(
 	filePath string,
 	logger golog.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := readFileWithEnv(filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -634,17 +688,35 @@ func fromReader(
 // and config validated with the assumption the config came from the cloud.
 // Returns an error if the unprocessedConfig is non-valid.
 func processConfigFromCloud(unprocessedConfig *Config) (*Config, error) {
-	return processConfig(unprocessedConfig, true)
+	return processConfig(unprocessedConfig, true, false)
 }
 
 // processConfigLocalConfig returns a copy of the current config with all attributes parsed
 // and config validated with the assumption the config came from a local file.
 // Returns an error if the unprocessedConfig is non-valid.
 func processConfigLocalConfig(unprocessedConfig *Config) (*Config, error) {
-	return processConfig(unprocessedConfig, false)
+	return processConfig(unprocessedConfig, false, false)
+}
+
+// ProcessConfigFromSource returns a copy of the given config processed the way a config read from the
+// cloud or a local file is before a robot is configured with it.
+// Returns an error if the unprocessedConfig is non-valid.
+func ProcessConfigFromSource(unprocessedConfig *Config, fromCloud bool) (*Config, error) {
+	return processConfig(unprocessedConfig, fromCloud, false)
 }
 
-func processConfig(unprocessedConfig *Config, fromCloud bool) (*Config, error) {
+// ProcessConfigFromRequest returns a copy of a config sent to the robot by a client processed the way
+// a local config is, except that nothing on the robot is read into it: references to environment
+// variables and secrets are left unresolved, and a config including fragments, which are files on the
+// robot, is rejected.
+func ProcessConfigFromRequest(unprocessedConfig *Config) (*Config, error) {
+	if len(unprocessedConfig.Fragments) != 0 {
+		return nil, errFragmentInRequest
+	}
+	return processConfig(unprocessedConfig, false, true)
+}
+
+func processConfig(unprocessedConfig *Config, fromCloud, fromRequest bool) (*Config, error) {
 	if err := unprocessedConfig.Ensure(fromCloud); err != nil {
 		return nil, err
 	}
@@ -657,44 +729,61 @@ func processConfig(unprocessedConfig *Config, fromCloud bool) (*Config, error) {
 	// Copy does not presve ConfigFilePath and we need to pass it along manually
 	cfg.ConfigFilePath = unprocessedConfig.ConfigFilePath
 
-	for idx, c := range cfg.Components {
-		cType := resource.NewSubtype(c.Namespace, "component", c.Type)
-		conv := findMapConverter(cType, c.Model)
-		// inner attributes may have their own converters
-		for k, v := range c.Attributes {
-			attrConv := findConverter(cType, c.Model, k)
-			if attrConv == nil {
-				continue
-			}
+	if err := cfg.includeFragments(); err != nil {
+		return nil, err
+	}
 
-			n, err := attrConv(v)
-			if err != nil {
-				return nil, errors.Wrapf(err, "error converting attribute for (%s, %s, %s)", c.Type, c.Model, k)
+	// a resource whose attributes cannot be resolved is left out rather than built with unresolved references
+	if !fromRequest {
+		components := cfg.Components[:0]
+		for _, c := range cfg.Components {
+			if err := interpolateAttributes(c.Attributes); err != nil {
+				fullErr := errors.Wrapf(err, "error resolving attributes of component %q", c.Name)
+				if cfg.DisablePartialStart {
+					return nil, fullErr
+				}
+				golog.Global().Error(errors.Wrap(fullErr, "starting robot without component: "+c.Name))
+				continue
 			}
-			cfg.Components[idx].Attributes[k] = n
+			components = append(components, c)
 		}
-		if conv == nil {
-			continue
+		cfg.Components = components
+
+		services := cfg.Services[:0]
+		for _, c := range cfg.Services {
+			if err := interpolateAttributes(c.Attributes); err != nil {
+				fullErr := errors.Wrapf(err, "error resolving attributes of service %q", c.Name)
+				if cfg.DisablePartialStart {
+					return nil, fullErr
+				}
+				golog.Global().Error(errors.Wrap(fullErr, "starting robot without service: "+c.Name))
+				continue
+			}
+			services = append(services, c)
 		}
+		cfg.Services = services
+	}
 
-		converted, err := conv(c.Attributes)
+	for idx, c := range cfg.Components {
+		converted, err := convertComponentAttributes(&cfg.Components[idx])
 		if err != nil {
 			return nil, errors.Wrapf(err, "error converting attributes for (%s, %s)", c.Type, c.Model)
 		}
+		if converted == nil {
+			continue
+		}
 		cfg.Components[idx].Attributes = nil
 		cfg.Components[idx].ConvertedAttributes = converted
 	}
 
 	for idx, c := range cfg.Services {
-		conv := findServiceMapConverter(resource.NewSubtype(c.Namespace, resource.ResourceTypeService, c.Type), c.Model)
-		if conv == nil {
-			continue
-		}
-
-		converted, err := conv(c.Attributes)
+		converted, err := convertServiceAttributes(&cfg.Services[idx])
 		if err != nil {
 			return nil, errors.Wrapf(err, "error converting attributes for %s", c.Type)
 		}
+		if converted == nil {
+			continue
+		}
 		cfg.Services[idx].Attributes = nil
 		cfg.Services[idx].ConvertedAttributes = converted
 	}
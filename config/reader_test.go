@@ -9,6 +9,8 @@ import (
 	"github.com/edaniels/golog"
 	"github.com/google/uuid"
 	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
 )
 
 func TestStoreToCache(t *testing.T) {
@@ -112,7 +114,30 @@ func TestProcessConfig(t *testing.T) {
 		ConfigFilePath: "path",
 	}
 
-	cfg, err := processConfig(&unprocessedConfig, true)
+	cfg, err := processConfig(&unprocessedConfig, true, false)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, *cfg, test.ShouldResemble, unprocessedConfig)
 }
+
+func TestProcessConfigFromRequest(t *testing.T) {
+	t.Setenv("RDK_TEST_REQUEST_VARIABLE", "robot value")
+	unprocessedConfig := Config{
+		Components: []Component{{
+			Name:       "foo",
+			Type:       "request_test",
+			Model:      resource.NewDefaultModel("request_test_model"),
+			Attributes: AttributeMap{"value": "${RDK_TEST_REQUEST_VARIABLE}"},
+		}},
+	}
+	cfg, err := ProcessConfigFromRequest(&unprocessedConfig)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components[0].Attributes["value"], test.ShouldEqual, "${RDK_TEST_REQUEST_VARIABLE}")
+
+	cfg, err = ProcessConfigFromSource(&unprocessedConfig, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components[0].Attributes["value"], test.ShouldEqual, "robot value")
+
+	unprocessedConfig.Fragments = []FragmentConfig{{Path: "fragment.json"}}
+	_, err = ProcessConfigFromRequest(&unprocessedConfig)
+	test.That(t, err, test.ShouldBeError, errFragmentInRequest)
+}
@@ -47,6 +47,39 @@ type validator interface {
 	Validate(path string) error
 }
 
+// An OptionalDependencyDeclarer is implemented by the attribute types of models that can be built
+// without some of their dependencies. Unlike the dependencies returned by Validate, a resource is
+// built whether or not its optional dependencies are, and is given those that are built later
+// if it implements registry.OptionalDependencyBinder.
+type OptionalDependencyDeclarer interface {
+	OptionalDependencies() []string
+}
+
+// optionalDependencies returns the deduplicated union of user-defined and declared optional
+// dependencies that are not also required.
+func optionalDependencies(optionalDependsOn []string, converted interface{}, required []string) []string {
+	seen := make(map[string]struct{}, len(required))
+	for _, dep := range required {
+		seen[dep] = struct{}{}
+	}
+	var result []string
+	appendUniq := func(dep string) {
+		if _, ok := seen[dep]; !ok {
+			seen[dep] = struct{}{}
+			result = append(result, dep)
+		}
+	}
+	for _, dep := range optionalDependsOn {
+		appendUniq(dep)
+	}
+	if declarer, ok := converted.(OptionalDependencyDeclarer); ok {
+		for _, dep := range declarer.OptionalDependencies() {
+			appendUniq(dep)
+		}
+	}
+	return result
+}
+
 // A ResourceConfig represents an implmentation of a config for any type of resource.
 type ResourceConfig interface {
 	validator
@@ -75,6 +108,9 @@ type Component struct {
 	Frame         *referenceframe.LinkConfig   `json:"frame,omitempty"`
 	DependsOn     []string                     `json:"depends_on"`
 	ServiceConfig []ResourceLevelServiceConfig `json:"service_config"`
+	// OptionalDependsOn names local resources the component uses when they are built, but can
+	// be built without.
+	OptionalDependsOn []string `json:"optional_depends_on,omitempty"`
 
 	Attributes          AttributeMap `json:"attributes"`
 	ConvertedAttributes interface{}  `json:"-"`
@@ -100,6 +136,12 @@ func (config *Component) Dependencies() []string {
 	return result
 }
 
+// OptionalDependencies returns the deduplicated union of user-defined and declared optional
+// dependencies, leaving out those that are also required.
+func (config *Component) OptionalDependencies() []string {
+	return optionalDependencies(config.OptionalDependsOn, config.ConvertedAttributes, config.Dependencies())
+}
+
 // Ensure Component conforms to flag.Value.
 var _ = flag.Value(&Component{})
 
@@ -292,6 +334,9 @@ type Service struct {
 	Type      resource.SubtypeName `json:"type"`
 	Model     resource.Model       `json:"model"`
 	DependsOn []string             `json:"depends_on"`
+	// OptionalDependsOn names local resources the service uses when they are built, but can be
+	// built without.
+	OptionalDependsOn []string `json:"optional_depends_on,omitempty"`
 
 	Attributes          AttributeMap `json:"attributes"`
 	ConvertedAttributes interface{}  `json:"-"`
@@ -485,3 +530,9 @@ func (config *Service) Dependencies() []string {
 	}
 	return result
 }
+
+// OptionalDependencies returns the deduplicated union of user-defined and declared optional
+// dependencies, leaving out those that are also required.
+func (config *Service) OptionalDependencies() []string {
+	return optionalDependencies(config.OptionalDependsOn, config.ConvertedAttributes, config.Dependencies())
+}
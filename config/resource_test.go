@@ -725,3 +725,24 @@ func TestSet(t *testing.T) {
 		"one": "two",
 	})
 }
+
+type optionalAttrs struct{}
+
+func (optionalAttrs) OptionalDependencies() []string {
+	return []string{"camera1", "arm1"}
+}
+
+func TestOptionalDependencies(t *testing.T) {
+	component := config.Component{
+		Name:                "arm2",
+		DependsOn:           []string{"board1"},
+		ImplicitDependsOn:   []string{"arm1"},
+		OptionalDependsOn:   []string{"board1", "sensor1", "sensor1"},
+		ConvertedAttributes: optionalAttrs{},
+	}
+	test.That(t, component.OptionalDependencies(), test.ShouldResemble, []string{"sensor1", "camera1"})
+
+	service := config.Service{Name: "motion1", OptionalDependsOn: []string{"arm1"}}
+	test.That(t, service.OptionalDependencies(), test.ShouldResemble, []string{"arm1"})
+	test.That(t, (&config.Service{}).OptionalDependencies(), test.ShouldBeEmpty)
+}
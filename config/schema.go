@@ -0,0 +1,122 @@
+package config
+
+import (
+	"sort"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/invopop/jsonschema"
+
+	"go.viam.com/rdk/resource"
+)
+
+// An AttributeSchema is the JSON Schema of the attributes of one model of a resource subtype.
+type AttributeSchema struct {
+	Subtype resource.Subtype   `json:"subtype"`
+	Model   resource.Model     `json:"model"`
+	Schema  *jsonschema.Schema `json:"schema"`
+}
+
+// AttributesSchema returns the JSON Schema of the attributes that convert to retType, the
+// type a model registers as the result of its attribute map converter. Like attribute
+// conversion, the schema reads the json tags of the type and allows unknown attributes. Nested
+// types are inlined so that the schema can be embedded in other schemas.
+func AttributesSchema(retType interface{}) *jsonschema.Schema {
+	reflector := jsonschema.Reflector{
+		AllowAdditionalProperties: true,
+		DoNotReference:            true,
+	}
+	schema := reflector.Reflect(retType)
+	schema.Version = ""
+	return schema
+}
+
+// RegisteredAttributeSchemas returns the attribute schemas of every component and service model
+// that registered an attribute map converter, sorted by subtype and model.
+func RegisteredAttributeSchemas() []AttributeSchema {
+	var schemas []AttributeSchema
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	for _, reg := range componentAttributeMapConverters {
+		schemas = append(schemas, AttributeSchema{reg.Subtype, reg.Model, AttributesSchema(reg.RetType)})
+	}
+	for _, reg := range serviceAttributeMapConverters {
+		schemas = append(schemas, AttributeSchema{reg.SvcType, reg.Model, AttributesSchema(reg.RetType)})
+	}
+	sort.SliceStable(schemas, func(i, j int) bool {
+		if schemas[i].Subtype.String() != schemas[j].Subtype.String() {
+			return schemas[i].Subtype.String() < schemas[j].Subtype.String()
+		}
+		return schemas[i].Model.String() < schemas[j].Model.String()
+	})
+	return schemas
+}
+
+// RobotConfigSchema returns a JSON Schema for robot configs in which the attributes of every
+// component and service are checked against the schema of its registered model. Editors can
+// use it for autocompletion and tools to validate a config before it reaches a robot.
+func RobotConfigSchema() *jsonschema.Schema {
+	var components, services []*jsonschema.Schema
+	for _, attrSchema := range RegisteredAttributeSchemas() {
+		cond := attributesCondition(attrSchema)
+		if attrSchema.Subtype.ResourceType == resource.ResourceTypeService {
+			services = append(services, cond)
+		} else {
+			components = append(components, cond)
+		}
+	}
+
+	properties := orderedmap.New()
+	properties.Set("components", resourcesSchema(components))
+	properties.Set("services", resourcesSchema(services))
+	return &jsonschema.Schema{
+		Version:    jsonschema.Version,
+		Title:      "robot config",
+		Type:       "object",
+		Properties: properties,
+	}
+}
+
+// resourcesSchema describes a list of resource configs given the conditional attribute
+// schemas of their models.
+func resourcesSchema(conditions []*jsonschema.Schema) *jsonschema.Schema {
+	properties := orderedmap.New()
+	properties.Set("name", &jsonschema.Schema{Type: "string"})
+	properties.Set("namespace", &jsonschema.Schema{Type: "string"})
+	properties.Set("type", &jsonschema.Schema{Type: "string"})
+	properties.Set("model", &jsonschema.Schema{Type: "string"})
+	properties.Set("depends_on", &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{Type: "string"}})
+	properties.Set("attributes", &jsonschema.Schema{Type: "object"})
+	return &jsonschema.Schema{
+		Type: "array",
+		Items: &jsonschema.Schema{
+			Type:       "object",
+			Required:   []string{"name", "type"},
+			Properties: properties,
+			AllOf:      conditions,
+		},
+	}
+}
+
+// attributesCondition applies the attribute schema of a model to resource configs of its
+// subtype and model. Builtin models may be referred to by their name alone.
+func attributesCondition(attrSchema AttributeSchema) *jsonschema.Schema {
+	models := []interface{}{attrSchema.Model.String()}
+	if attrSchema.Model.ModelFamily == resource.DefaultModelFamily {
+		models = append(models, string(attrSchema.Model.Name))
+	}
+	match := orderedmap.New()
+	match.Set("type", &jsonschema.Schema{Const: string(attrSchema.Subtype.ResourceSubtype)})
+	match.Set("model", &jsonschema.Schema{Enum: models})
+	required := []string{"type", "model"}
+	if attrSchema.Subtype.Namespace != resource.ResourceNamespaceRDK {
+		match.Set("namespace", &jsonschema.Schema{Const: string(attrSchema.Subtype.Namespace)})
+		required = append(required, "namespace")
+	}
+
+	then := orderedmap.New()
+	then.Set("attributes", attrSchema.Schema)
+	return &jsonschema.Schema{
+		If:   &jsonschema.Schema{Properties: match, Required: required},
+		Then: &jsonschema.Schema{Properties: then},
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+type schemaTestNested struct {
+	Pin string `json:"pin"`
+}
+
+type schemaTestAttributes struct {
+	Port   string             `json:"serial_path"`
+	Speed  float64            `json:"speed,omitempty"`
+	Nested []schemaTestNested `json:"nested"`
+}
+
+func TestRobotConfigSchema(t *testing.T) {
+	subtype := resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "schema_test")
+	model := resource.NewDefaultModel("schema_test_model")
+	origConverters := componentAttributeMapConverters
+	componentAttributeMapConverters = append([]ComponentAttributeMapConverterRegistration{}, origConverters...)
+	defer func() {
+		componentAttributeMapConverters = origConverters
+	}()
+	RegisterComponentAttributeMapConverter(subtype, model, func(attributes AttributeMap) (interface{}, error) {
+		return TransformAttributeMapToStruct(&schemaTestAttributes{}, attributes)
+	}, &schemaTestAttributes{})
+
+	var found *AttributeSchema
+	for _, attrSchema := range RegisteredAttributeSchemas() {
+		attrSchema := attrSchema
+		if attrSchema.Subtype == subtype && attrSchema.Model == model {
+			found = &attrSchema
+		}
+	}
+	test.That(t, found, test.ShouldNotBeNil)
+	test.That(t, found.Schema.Ref, test.ShouldBeEmpty)
+	test.That(t, found.Schema.Required, test.ShouldResemble, []string{"serial_path", "nested"})
+
+	out, err := json.Marshal(RobotConfigSchema())
+	test.That(t, err, test.ShouldBeNil)
+	var decoded map[string]interface{}
+	test.That(t, json.Unmarshal(out, &decoded), test.ShouldBeNil)
+
+	//nolint:forcetypeassert
+	items := decoded["properties"].(map[string]interface{})["components"].(map[string]interface{})["items"].(map[string]interface{})
+	var then map[string]interface{}
+	//nolint:forcetypeassert
+	for _, cond := range items["allOf"].([]interface{}) {
+		cond := cond.(map[string]interface{})
+		ifProps := cond["if"].(map[string]interface{})["properties"].(map[string]interface{})
+		if ifProps["type"].(map[string]interface{})["const"] != "schema_test" {
+			continue
+		}
+		test.That(t, ifProps["model"].(map[string]interface{})["enum"], test.ShouldResemble,
+			[]interface{}{"rdk:builtin:schema_test_model", "schema_test_model"})
+		then = cond["then"].(map[string]interface{})["properties"].(map[string]interface{})["attributes"].(map[string]interface{})
+	}
+	test.That(t, then, test.ShouldNotBeNil)
+	//nolint:forcetypeassert
+	attrProps := then["properties"].(map[string]interface{})
+	test.That(t, attrProps["speed"], test.ShouldResemble, map[string]interface{}{"type": "number"})
+	//nolint:forcetypeassert
+	nestedItems := attrProps["nested"].(map[string]interface{})["items"].(map[string]interface{})
+	test.That(t, nestedItems["properties"], test.ShouldResemble, map[string]interface{}{
+		"pin": map[string]interface{}{"type": "string"},
+	})
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mitchellh/copystructure"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils/pexec"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A ValidationError is a problem found in a config and the path of the part of the config it
+// was found in, such as "components.2.attributes".
+type ValidationError struct {
+	Path string `json:"path"`
+	// Name is the name of the resource the problem belongs to, if any.
+	Name string `json:"name,omitempty"`
+	Err  error  `json:"-"`
+}
+
+// Error returns the path and the error message.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON includes the error message.
+func (e *ValidationError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path  string `json:"path"`
+		Name  string `json:"name,omitempty"`
+		Error string `json:"error"`
+	}{e.Path, e.Name, e.Err.Error()})
+}
+
+// ValidateConfig checks an unprocessed config the way a robot would when starting from it,
+// without constructing any resources: fragments are included, every module, remote, component,
+// service, process and package is validated, and the attributes of components and services are
+// resolved, converted and validated against the config type of their model. Instead of stopping at the first
+// problem, all of them are returned as *ValidationError values combined with multierr; use
+// multierr.Errors to list them. The given config is left untouched.
+func ValidateConfig(unprocessedConfig *Config, fromCloud bool) error {
+	return validateConfig(unprocessedConfig, fromCloud, false)
+}
+
+// ValidateConfigFromRequest checks a config sent to the robot by a client like ValidateConfig does,
+// except that nothing on the robot is read into it, so that none of it can come back in the errors:
+// references to environment variables and secrets are validated as written rather than resolved, and
+// fragments, which are files on the robot, are reported instead of included.
+func ValidateConfigFromRequest(unprocessedConfig *Config) error {
+	return validateConfig(unprocessedConfig, false, true)
+}
+
+func validateConfig(unprocessedConfig *Config, fromCloud, fromRequest bool) error {
+	var errs error
+	addErr := func(path, name string, err error) {
+		if err != nil {
+			errs = multierr.Append(errs, &ValidationError{Path: path, Name: name, Err: err})
+		}
+	}
+
+	// everything is validated on copies, since validating fills in defaults.
+	cfg := *unprocessedConfig
+	included := &Config{ConfigFilePath: cfg.ConfigFilePath}
+	for idx := range cfg.Fragments {
+		path := fmt.Sprintf("fragments.%d", idx)
+		if fromRequest {
+			addErr(path, "", errFragmentInRequest)
+			continue
+		}
+		addErr(path, "", included.includeFragment(path, cfg.Fragments[idx]))
+	}
+	cfg.Modules = append(append([]Module{}, cfg.Modules...), included.Modules...)
+	cfg.Remotes = append(append([]Remote{}, cfg.Remotes...), included.Remotes...)
+	cfg.Components = append(append([]Component{}, cfg.Components...), included.Components...)
+	cfg.Processes = append(append([]pexec.ProcessConfig{}, cfg.Processes...), included.Processes...)
+	cfg.Services = append(append([]Service{}, cfg.Services...), included.Services...)
+	cfg.Packages = append(append([]PackageConfig{}, cfg.Packages...), included.Packages...)
+
+	if cfg.Cloud != nil {
+		cloud := *cfg.Cloud
+		addErr("cloud", "", cloud.Validate("cloud", fromCloud))
+	}
+	for idx := range cfg.Modules {
+		path := fmt.Sprintf("modules.%d", idx)
+		addErr(path, cfg.Modules[idx].Name, cfg.Modules[idx].Validate(path))
+	}
+	for idx := range cfg.Remotes {
+		path := fmt.Sprintf("remotes.%d", idx)
+		addErr(path, cfg.Remotes[idx].Name, cfg.Remotes[idx].Validate(path))
+	}
+	for idx := range cfg.Components {
+		path := fmt.Sprintf("components.%d", idx)
+		c := &cfg.Components[idx]
+		if _, err := c.Validate(path); err != nil {
+			addErr(path, c.Name, err)
+			continue
+		}
+		attrs, err := copyAttributes(c.Attributes)
+		if err != nil {
+			addErr(path+".attributes", c.Name, err)
+			continue
+		}
+		c.Attributes = attrs
+		if !fromRequest {
+			if err := interpolateAttributes(c.Attributes); err != nil {
+				addErr(path+".attributes", c.Name, err)
+				continue
+			}
+		}
+		converted, err := convertComponentAttributes(c)
+		if err != nil {
+			addErr(path+".attributes", c.Name, err)
+			continue
+		}
+		if converted == nil {
+			continue
+		}
+		c.Attributes = nil
+		c.ConvertedAttributes = converted
+		_, err = c.Validate(path)
+		addErr(path+".attributes", c.Name, err)
+	}
+	for idx := range cfg.Processes {
+		path := fmt.Sprintf("processes.%d", idx)
+		addErr(path, cfg.Processes[idx].ID, cfg.Processes[idx].Validate(path))
+	}
+	for idx := range cfg.Services {
+		path := fmt.Sprintf("services.%d", idx)
+		s := &cfg.Services[idx]
+		if _, err := s.Validate(path); err != nil {
+			addErr(path, s.Name, err)
+			continue
+		}
+		attrs, err := copyAttributes(s.Attributes)
+		if err != nil {
+			addErr(path+".attributes", s.Name, err)
+			continue
+		}
+		s.Attributes = attrs
+		if !fromRequest {
+			if err := interpolateAttributes(s.Attributes); err != nil {
+				addErr(path+".attributes", s.Name, err)
+				continue
+			}
+		}
+		converted, err := convertServiceAttributes(s)
+		if err != nil {
+			addErr(path+".attributes", s.Name, err)
+			continue
+		}
+		if converted == nil {
+			continue
+		}
+		s.Attributes = nil
+		s.ConvertedAttributes = converted
+		_, err = s.Validate(path)
+		addErr(path+".attributes", s.Name, err)
+	}
+	network := cfg.Network
+	addErr("network", "", network.Validate("network"))
+	auth := cfg.Auth
+	addErr("auth", "", auth.Validate("auth"))
+	for idx := range cfg.Packages {
+		path := fmt.Sprintf("packages.%d", idx)
+		addErr(path, cfg.Packages[idx].Name, cfg.Packages[idx].Validate(path))
+	}
+	for idx := range cfg.Log {
+		path := fmt.Sprintf("log.%d", idx)
+		addErr(path, "", cfg.Log[idx].Validate(path))
+	}
+	return errs
+}
+
+// errFragmentInRequest is the problem with fragments in configs sent to the robot.
+var errFragmentInRequest = errors.New("fragments are files on the robot, so configs sent to it cannot include them")
+
+// copyAttributes returns a deep copy of attrs, so that resolving and converting them leaves the
+// attributes of the config they came from untouched.
+func copyAttributes(attrs AttributeMap) (AttributeMap, error) {
+	if attrs == nil {
+		return nil, nil
+	}
+	copied, err := copystructure.Copy(attrs)
+	if err != nil {
+		return nil, errors.Wrap(err, "error copying attributes")
+	}
+	return copied.(AttributeMap), nil
+}
+
+// convertComponentAttributes runs the attribute converters of a component's model on its
+// attributes in place and returns the result of the model's map converter, if any.
+func convertComponentAttributes(c *Component) (interface{}, error) {
+	cType := resource.NewSubtype(c.Namespace, resource.ResourceTypeComponent, c.Type)
+	for k, v := range c.Attributes {
+		attrConv := findConverter(cType, c.Model, k)
+		if attrConv == nil {
+			continue
+		}
+		n, err := attrConv(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error converting attribute %q", k)
+		}
+		c.Attributes[k] = n
+	}
+	conv := findMapConverter(cType, c.Model)
+	if conv == nil {
+		return nil, nil
+	}
+	return conv(c.Attributes)
+}
+
+// convertServiceAttributes returns the result of the map converter of a service's model, if any.
+func convertServiceAttributes(s *Service) (interface{}, error) {
+	conv := findServiceMapConverter(resource.NewSubtype(s.Namespace, resource.ResourceTypeService, s.Type), s.Model)
+	if conv == nil {
+		return nil, nil
+	}
+	return conv(s.Attributes)
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/multierr"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+type validateTestAttributes struct {
+	Pin string `json:"pin"`
+}
+
+func (attrs *validateTestAttributes) Validate(path string) error {
+	if attrs.Pin == "" {
+		return errors.New(`"pin" is required`)
+	}
+	return nil
+}
+
+func TestValidateConfig(t *testing.T) {
+	subtype := resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "validate_test")
+	model := resource.NewDefaultModel("validate_test_model")
+	origConverters := componentAttributeMapConverters
+	componentAttributeMapConverters = append([]ComponentAttributeMapConverterRegistration{}, origConverters...)
+	defer func() {
+		componentAttributeMapConverters = origConverters
+	}()
+	RegisterComponentAttributeMapConverter(subtype, model, func(attributes AttributeMap) (interface{}, error) {
+		return TransformAttributeMapToStruct(&validateTestAttributes{}, attributes)
+	}, &validateTestAttributes{})
+
+	cfg := &Config{
+		Remotes: []Remote{{Name: "remote1"}},
+		Components: []Component{
+			{Name: "good", Type: "validate_test", Model: model, Attributes: AttributeMap{"pin": "7"}},
+			{Name: "bad name!", Type: "validate_test", Model: model},
+			{Name: "no_pin", Type: "validate_test", Model: model, Attributes: AttributeMap{}},
+			{Name: "wrong_type", Type: "validate_test", Model: model, Attributes: AttributeMap{"pin": []interface{}{1}}},
+			{Name: "unset_env", Type: "validate_test", Model: model, Attributes: AttributeMap{"pin": "${RDK_TEST_UNSET_VARIABLE}"}},
+		},
+		Services: []Service{{Name: "svc"}},
+	}
+	err := ValidateConfig(cfg, false)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	var paths, names []string
+	for _, err := range multierr.Errors(err) {
+		var validationErr *ValidationError
+		test.That(t, errors.As(err, &validationErr), test.ShouldBeTrue)
+		paths = append(paths, validationErr.Path)
+		names = append(names, validationErr.Name)
+	}
+	test.That(t, paths, test.ShouldResemble, []string{
+		"remotes.0",
+		"components.1",
+		"components.2.attributes",
+		"components.3.attributes",
+		"components.4.attributes",
+		"services.0",
+	})
+	test.That(t, names, test.ShouldResemble, []string{"remote1", "bad name!", "no_pin", "wrong_type", "unset_env", "svc"})
+	test.That(t, err.Error(), test.ShouldContainSubstring, `components.2.attributes: "pin" is required`)
+
+	// the config is not processed in place
+	test.That(t, cfg.Components[0].ConvertedAttributes, test.ShouldBeNil)
+	test.That(t, cfg.Components[0].Attributes, test.ShouldResemble, AttributeMap{"pin": "7"})
+
+	cfg.Remotes[0].Address = "localhost:8080"
+	cfg.Components = cfg.Components[:1]
+	cfg.Services[0].Type = "navigation"
+	test.That(t, ValidateConfig(cfg, false), test.ShouldBeNil)
+}
+
+func TestValidateConfigFromRequest(t *testing.T) {
+	subtype := resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "validate_test")
+	model := resource.NewDefaultModel("validate_test_model")
+	origConverters := componentAttributeMapConverters
+	componentAttributeMapConverters = append([]ComponentAttributeMapConverterRegistration{}, origConverters...)
+	defer func() {
+		componentAttributeMapConverters = origConverters
+	}()
+	RegisterComponentAttributeMapConverter(subtype, model, func(attributes AttributeMap) (interface{}, error) {
+		return TransformAttributeMapToStruct(&validateTestAttributes{}, attributes)
+	}, &validateTestAttributes{})
+
+	// references are not resolved, so neither the environment nor secrets can end up in errors
+	cfg := &Config{
+		Components: []Component{
+			{Name: "unset_env", Type: "validate_test", Model: model, Attributes: AttributeMap{"pin": "${RDK_TEST_UNSET_VARIABLE}"}},
+		},
+	}
+	test.That(t, ValidateConfig(cfg, false), test.ShouldNotBeNil)
+	test.That(t, ValidateConfigFromRequest(cfg), test.ShouldBeNil)
+	test.That(t, cfg.Components[0].Attributes, test.ShouldResemble, AttributeMap{"pin": "${RDK_TEST_UNSET_VARIABLE}"})
+
+	// fragments are files on the robot, so they are not read
+	cfg.Fragments = []FragmentConfig{{Path: "/etc/passwd"}}
+	errs := multierr.Errors(ValidateConfigFromRequest(cfg))
+	test.That(t, errs, test.ShouldHaveLength, 1)
+	test.That(t, errs[0].(*ValidationError).Path, test.ShouldEqual, "fragments.0")
+	test.That(t, errs[0], test.ShouldWrap, errFragmentInRequest)
+}
@@ -3,12 +3,17 @@ package config
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/bep/debounce"
 	"github.com/edaniels/golog"
 	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.viam.com/utils"
 )
 
@@ -110,19 +115,58 @@ type fsConfigWatcher struct {
 }
 
 // newFSWatcher returns a new v that will fetch new configs
-// as soon as the underlying file is written to.
+// as soon as the underlying file, or a fragment file it includes, is written to. The
+// directories of the files are watched rather than the files themselves so that editors
+// which save by replacing the file are picked up too. A config that fails to read or
+// validate is reported and skipped so that the robot keeps running with the last good
+// config until the file is fixed.
 func newFSWatcher(ctx context.Context, configPath string, logger golog.Logger) (*fsConfigWatcher, error) {
 	fsWatcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
-	if err := fsWatcher.Add(configPath); err != nil {
-		return nil, err
+	configPath = filepath.Clean(configPath)
+	if err := fsWatcher.Add(filepath.Dir(configPath)); err != nil {
+		return nil, multierr.Combine(err, fsWatcher.Close())
+	}
+
+	// the fragments a config includes change along with the config, so the files watched are
+	// updated every time it is read.
+	var watchedMu sync.Mutex
+	watchedFiles := map[string]bool{configPath: true}
+	watchedDirs := map[string]bool{filepath.Dir(configPath): true}
+	watchFragments := func(paths []string) {
+		watchedMu.Lock()
+		defer watchedMu.Unlock()
+		watchedFiles = map[string]bool{configPath: true}
+		for _, path := range paths {
+			watchedFiles[path] = true
+			dir := filepath.Dir(path)
+			if watchedDirs[dir] {
+				continue
+			}
+			if err := fsWatcher.Add(dir); err != nil {
+				logger.Errorw("error watching fragment file", "path", path, "error", err)
+				continue
+			}
+			watchedDirs[dir] = true
+		}
+	}
+	isWatched := func(path string) bool {
+		watchedMu.Lock()
+		defer watchedMu.Unlock()
+		return watchedFiles[filepath.Clean(path)]
 	}
+	//nolint:gosec
+	if rd, err := os.ReadFile(configPath); err == nil {
+		watchFragments(fragmentPaths(configPath, rd))
+	}
+
 	configCh := make(chan *Config)
 	watcherDoneCh := make(chan struct{})
 	cancelCtx, cancel := context.WithCancel(ctx)
 	var lastRd []byte
+	var lastInvalid bool
 	utils.ManagedGo(func() {
 		debounced := debounce.New(time.Millisecond * 500)
 		for {
@@ -132,31 +176,54 @@ func newFSWatcher(ctx context.Context, configPath string, logger golog.Logger) (
 			select {
 			case <-cancelCtx.Done():
 				return
+			case err := <-fsWatcher.Errors:
+				logger.Errorw("error watching config file", "path", configPath, "error", err)
 			case event := <-fsWatcher.Events:
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					debounced(func() {
-						//nolint:gosec
-						rd, err := os.ReadFile(configPath)
-						if err != nil {
+				if !isWatched(event.Name) ||
+					event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				debounced(func() {
+					//nolint:gosec
+					rd, err := os.ReadFile(configPath)
+					if err != nil {
+						if !os.IsNotExist(err) {
 							logger.Errorw("error reading config file after write", "error", err)
-							return
-						}
-						if bytes.Equal(rd, lastRd) {
-							return
 						}
-						lastRd = rd
-						newConfig, err := FromReader(cancelCtx, configPath, bytes.NewReader(rd), logger)
-						if err != nil {
-							logger.Errorw("error reading config after write", "error", err)
-							return
-						}
-						select {
-						case <-cancelCtx.Done():
-							return
-						case configCh <- newConfig:
+						return
+					}
+					// a config whose fragments changed is read again even if the config itself did not.
+					fragments := fragmentPaths(configPath, rd)
+					watchFragments(fragments)
+					snapshot := bytes.NewBuffer(append([]byte(nil), rd...))
+					for _, path := range fragments {
+						//nolint:gosec
+						if fragmentRd, err := os.ReadFile(path); err == nil {
+							snapshot.WriteByte(0)
+							snapshot.Write(fragmentRd)
 						}
-					})
-				}
+					}
+					if bytes.Equal(snapshot.Bytes(), lastRd) {
+						return
+					}
+					lastRd = snapshot.Bytes()
+					newConfig, err := readWatchedConfig(cancelCtx, configPath, rd, logger)
+					if err != nil {
+						lastInvalid = true
+						logger.Errorw("config file is invalid; keeping the current config until it is fixed",
+							"path", configPath, "error", err)
+						return
+					}
+					if lastInvalid {
+						lastInvalid = false
+						logger.Infow("config file is valid again", "path", configPath)
+					}
+					select {
+					case <-cancelCtx.Done():
+						return
+					case configCh <- newConfig:
+					}
+				})
 			}
 		}
 	}, func() {
@@ -170,6 +237,38 @@ func newFSWatcher(ctx context.Context, configPath string, logger golog.Logger) (
 	}, nil
 }
 
+// readWatchedConfig reads a config file that changed while it was watched. Unlike on startup, where the
+// robot starts without the parts of a config that are invalid, any problem with the config is an error,
+// so that the robot is not torn down to the valid parts of a config that is still being edited.
+func readWatchedConfig(ctx context.Context, configPath string, rd []byte, logger golog.Logger) (*Config, error) {
+	unprocessedConfig := Config{ConfigFilePath: configPath}
+	if err := json.Unmarshal(rd, &unprocessedConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Config from json")
+	}
+	if err := ValidateConfig(&unprocessedConfig, false); err != nil {
+		return nil, err
+	}
+	return FromReader(ctx, configPath, bytes.NewReader(rd), logger)
+}
+
+// fragmentPaths returns the fragment files included by the given contents of a config file. A
+// config that cannot be decoded includes none.
+func fragmentPaths(configPath string, rd []byte) []string {
+	var cfg struct {
+		Fragments []FragmentConfig `json:"fragments"`
+	}
+	if err := json.Unmarshal(rd, &cfg); err != nil {
+		return nil
+	}
+	paths := make([]string, 0, len(cfg.Fragments))
+	for idx := range cfg.Fragments {
+		if cfg.Fragments[idx].Path != "" {
+			paths = append(paths, filepath.Clean(cfg.Fragments[idx].filePath(configPath)))
+		}
+	}
+	return paths
+}
+
 func (w *fsConfigWatcher) Config() <-chan *Config {
 	return w.configCh
 }
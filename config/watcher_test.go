@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -181,6 +182,64 @@ func TestNewWatcherFile(t *testing.T) {
 	test.That(t, utils.TryClose(context.Background(), watcher), test.ShouldBeNil)
 }
 
+func TestNewWatcherFileReplaced(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "robot.json")
+	test.That(t, os.WriteFile(configPath, []byte(`{}`), 0o600), test.ShouldBeNil)
+
+	watcher, err := config.NewWatcher(context.Background(), &config.Config{ConfigFilePath: configPath}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(context.Background(), watcher), test.ShouldBeNil)
+	}()
+
+	// editors often save by writing a new file and renaming it over the old one
+	replace := func(contents string) {
+		tmpPath := filepath.Join(dir, "robot.json.tmp")
+		test.That(t, os.WriteFile(tmpPath, []byte(contents), 0o600), test.ShouldBeNil)
+		test.That(t, os.Rename(tmpPath, configPath), test.ShouldBeNil)
+	}
+
+	// invalid configs are skipped
+	replace(`{"components": [{"name": "", "type": "arm", "model": "fake"}]}`)
+	time.Sleep(time.Second)
+	replace(`{"components": [{"name": "arm1", "type": "arm", "model": "fake"}]}`)
+
+	newConf := <-watcher.Config()
+	test.That(t, newConf.Components, test.ShouldHaveLength, 1)
+	test.That(t, newConf.Components[0].Name, test.ShouldEqual, "arm1")
+}
+
+func TestNewWatcherFragment(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	dir := t.TempDir()
+	fragmentDir := filepath.Join(dir, "fragments")
+	test.That(t, os.Mkdir(fragmentDir, 0o700), test.ShouldBeNil)
+	fragmentPath := filepath.Join(fragmentDir, "arm.json")
+	writeFragment := func(name string) {
+		test.That(t, os.WriteFile(fragmentPath,
+			[]byte(`{"components": [{"name": "`+name+`", "type": "arm", "model": "fake"}]}`), 0o600), test.ShouldBeNil)
+	}
+	writeFragment("arm1")
+	configPath := filepath.Join(dir, "robot.json")
+	test.That(t, os.WriteFile(configPath, []byte(`{"fragments": [{"path": "fragments/arm.json"}]}`), 0o600), test.ShouldBeNil)
+
+	watcher, err := config.NewWatcher(context.Background(), &config.Config{ConfigFilePath: configPath}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(context.Background(), watcher), test.ShouldBeNil)
+	}()
+
+	// changing an included fragment reloads the config including it
+	writeFragment("arm2")
+	newConf := <-watcher.Config()
+	test.That(t, newConf.Components, test.ShouldHaveLength, 1)
+	test.That(t, newConf.Components[0].Name, test.ShouldEqual, "arm2")
+}
+
 func TestNewWatcherCloud(t *testing.T) {
 	logger := golog.NewTestLogger(t)
 
@@ -0,0 +1,147 @@
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// An AttributeTemplate describes one attribute of a model's config.
+	AttributeTemplate struct {
+		Name string `json:"name"`
+		// Type is one of "string", "number", "bool", "list" or "object".
+		Type     string `json:"type"`
+		Required bool   `json:"required"`
+		// Default is the value used when the attribute is not set, if it is not the zero value
+		// of its type.
+		Default interface{} `json:"default,omitempty"`
+	}
+
+	// A ConfigTemplate describes the attributes a model is configured with, so that a config
+	// for it can be filled in.
+	ConfigTemplate struct {
+		Query      Query               `json:"query"`
+		Attributes []AttributeTemplate `json:"attributes"`
+	}
+
+	// A Proposal is a component config proposed for detected hardware.
+	Proposal struct {
+		Query      Query                  `json:"query"`
+		Name       string                 `json:"name"`
+		Attributes map[string]interface{} `json:"attributes"`
+		// Reason describes the hardware the proposal was made for.
+		Reason string `json:"reason"`
+	}
+
+	// Hardware is what was detected on the machine a robot runs on.
+	Hardware struct {
+		USB []USBDevice `json:"usb"`
+		I2C []I2CDevice `json:"i2c"`
+	}
+
+	// An I2CDevice is an address that responded on the I2C bus of a board.
+	I2CDevice struct {
+		Board   string `json:"board"`
+		Bus     string `json:"bus"`
+		Address byte   `json:"address"`
+	}
+
+	// Propose is a function that proposes component configs of a model for detected hardware.
+	// The Query of the proposals is filled in by the robot, and so is the Name if left empty.
+	Propose func(ctx context.Context, hw Hardware) ([]Proposal, error)
+)
+
+// NewConfigTemplate returns the template of the attributes that decode into attrs, the type a
+// model registers as the result of its attribute map converter. Attributes are named by their
+// json tags and are required unless tagged omitempty. The non-zero fields of attrs are their
+// defaults, so a model may pass a struct with its defaults filled in.
+func NewConfigTemplate(q Query, attrs interface{}) ConfigTemplate {
+	template := ConfigTemplate{Query: q, Attributes: []AttributeTemplate{}}
+	if attrs == nil {
+		return template
+	}
+	v := reflect.ValueOf(attrs)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem()).Elem()
+			break
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return template
+	}
+	template.Attributes = attributeTemplates(v)
+	sort.SliceStable(template.Attributes, func(i, j int) bool {
+		if template.Attributes[i].Required != template.Attributes[j].Required {
+			return template.Attributes[i].Required
+		}
+		return template.Attributes[i].Name < template.Attributes[j].Name
+	})
+	return template
+}
+
+func attributeTemplates(v reflect.Value) []AttributeTemplate {
+	var attrs []AttributeTemplate
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		// untagged embedded structs are flattened like encoding/json does, which promotes the
+		// exported fields of unexported embedded structs too.
+		if field.Anonymous && !hasTag {
+			fv := v.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					fv = reflect.New(fv.Type().Elem())
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				attrs = append(attrs, attributeTemplates(fv)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		attr := AttributeTemplate{
+			Name:     name,
+			Type:     attributeType(field.Type),
+			Required: !strings.Contains(","+opts+",", ",omitempty,"),
+		}
+		if fv := v.Field(i); !fv.IsZero() {
+			attr.Default = fv.Interface()
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
+
+func attributeType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	default:
+		return "object"
+	}
+}
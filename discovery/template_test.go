@@ -0,0 +1,80 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+type nestedAttrs struct {
+	Path string `json:"path"`
+}
+
+type templateAttrs struct {
+	Board    string            `json:"board"`
+	Pins     []int             `json:"pins,omitempty"`
+	MaxRPM   float64           `json:"max_rpm,omitempty"`
+	Inverted bool              `json:"inverted,omitempty"`
+	Nested   *nestedAttrs      `json:"nested,omitempty"`
+	Extra    map[string]string `json:"-"`
+	*embeddedAttrs
+}
+
+type embeddedAttrs struct {
+	Bus string `json:"bus"`
+}
+
+func TestNewConfigTemplate(t *testing.T) {
+	q := NewQuery(resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "motor"), resource.NewDefaultModel("fake"))
+
+	template := NewConfigTemplate(q, &templateAttrs{MaxRPM: 100})
+	test.That(t, template.Query, test.ShouldResemble, q)
+	test.That(t, template.Attributes, test.ShouldResemble, []AttributeTemplate{
+		{Name: "board", Type: "string", Required: true},
+		{Name: "bus", Type: "string", Required: true},
+		{Name: "inverted", Type: "bool"},
+		{Name: "max_rpm", Type: "number", Default: 100.0},
+		{Name: "nested", Type: "object"},
+		{Name: "pins", Type: "list"},
+	})
+
+	template = NewConfigTemplate(q, &templateAttrs{embeddedAttrs: &embeddedAttrs{Bus: "1"}})
+	test.That(t, template.Attributes[1], test.ShouldResemble, AttributeTemplate{
+		Name: "bus", Type: "string", Required: true, Default: "1",
+	})
+
+	test.That(t, NewConfigTemplate(q, nil).Attributes, test.ShouldBeEmpty)
+	test.That(t, NewConfigTemplate(q, (*templateAttrs)(nil)).Attributes, test.ShouldHaveLength, 6)
+}
+
+func TestUSBDevices(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(path, contents string) {
+		test.That(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0o700), test.ShouldBeNil)
+		test.That(t, os.WriteFile(filepath.Join(dir, path), []byte(contents+"\n"), 0o600), test.ShouldBeNil)
+	}
+	writeFile("1-1/idVendor", "1546")
+	writeFile("1-1/idProduct", "01a8")
+	writeFile("1-1/product", "u-blox GNSS receiver")
+	writeFile("1-1/1-1:1.0/tty/ttyACM0/dev", "166:0")
+	writeFile("1-2/idVendor", "0403")
+	writeFile("1-2/idProduct", "6001")
+	writeFile("1-2/serial", "A50285BI")
+	writeFile("1-2/1-2:1.0/ttyUSB0/dev", "188:0")
+	writeFile("1-2:1.0/bInterfaceClass", "ff")
+
+	devices, err := usbDevicesIn(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, devices, test.ShouldResemble, []USBDevice{
+		{VendorID: 0x1546, ProductID: 0x01a8, Product: "u-blox GNSS receiver", TTYs: []string{"/dev/ttyACM0"}},
+		{VendorID: 0x0403, ProductID: 0x6001, Serial: "A50285BI", TTYs: []string{"/dev/ttyUSB0"}},
+	})
+
+	devices, err = usbDevicesIn(filepath.Join(dir, "missing"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, devices, test.ShouldBeEmpty)
+}
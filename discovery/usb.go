@@ -0,0 +1,98 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// usbDevicesDir is where Linux lists USB devices.
+const usbDevicesDir = "/sys/bus/usb/devices"
+
+// A USBDevice is a device plugged into a USB port of the machine.
+type USBDevice struct {
+	VendorID     uint16 `json:"vendor_id"`
+	ProductID    uint16 `json:"product_id"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Product      string `json:"product,omitempty"`
+	Serial       string `json:"serial,omitempty"`
+	// TTYs are the serial ports of the device, such as /dev/ttyUSB0.
+	TTYs []string `json:"ttys,omitempty"`
+}
+
+// USBDevices returns the USB devices plugged into the machine. Only Linux is supported; on
+// other systems no devices are returned.
+func USBDevices() ([]USBDevice, error) {
+	return usbDevicesIn(usbDevicesDir)
+}
+
+// usbDevicesIn reads the USB devices listed in a sysfs directory.
+func usbDevicesIn(dir string) ([]USBDevice, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var devices []USBDevice
+	for _, entry := range entries {
+		devDir := filepath.Join(dir, entry.Name())
+		vendorID, ok := readHexID(filepath.Join(devDir, "idVendor"))
+		if !ok {
+			// interfaces of devices are listed alongside them
+			continue
+		}
+		productID, ok := readHexID(filepath.Join(devDir, "idProduct"))
+		if !ok {
+			continue
+		}
+		devices = append(devices, USBDevice{
+			VendorID:     vendorID,
+			ProductID:    productID,
+			Manufacturer: readSysfsString(filepath.Join(devDir, "manufacturer")),
+			Product:      readSysfsString(filepath.Join(devDir, "product")),
+			Serial:       readSysfsString(filepath.Join(devDir, "serial")),
+			TTYs:         usbTTYs(devDir),
+		})
+	}
+	return devices, nil
+}
+
+// usbTTYs returns the serial ports of a USB device. CDC ACM devices list them in a tty
+// directory of their interfaces and USB serial converters directly in their interfaces.
+func usbTTYs(devDir string) []string {
+	var ttys []string
+	for _, pattern := range []string{"*/tty/tty*", "*/tty*"} {
+		matches, err := filepath.Glob(filepath.Join(devDir, pattern))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			if base := filepath.Base(m); base != "tty" {
+				ttys = append(ttys, filepath.Join("/dev", base))
+			}
+		}
+	}
+	sort.Strings(ttys)
+	return ttys
+}
+
+func readHexID(path string) (uint16, bool) {
+	id, err := strconv.ParseUint(readSysfsString(path), 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(id), true
+}
+
+func readSysfsString(path string) string {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,358 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+)
+
+// defaultMaxConcurrentBulkCalls is how many bulk calls may be in flight on a shared
+// connection at once when not configured otherwise.
+const defaultMaxConcurrentBulkCalls = 2
+
+// CallPriority is the priority a call gets on a shared connection.
+type CallPriority int
+
+const (
+	// PriorityControl is for calls that command or query a resource. They are never held back.
+	PriorityControl CallPriority = iota
+	// PriorityBulk is for calls carrying large payloads such as images and point clouds. They
+	// are limited in how many may run at once so that they cannot starve control calls.
+	PriorityBulk
+)
+
+// bulkMethodNames are the names of methods that transfer video, audio or point cloud data.
+var bulkMethodNames = map[string]bool{
+	"GetImage":             true,
+	"GetImages":            true,
+	"RenderFrame":          true,
+	"GetPointCloud":        true,
+	"GetPointCloudMap":     true,
+	"GetObjectPointClouds": true,
+	"Chunks":               true,
+}
+
+// MethodPriority returns the priority of the given full gRPC method name.
+func MethodPriority(method string) CallPriority {
+	if bulkMethodNames[method[strings.LastIndex(method, "/")+1:]] {
+		return PriorityBulk
+	}
+	return PriorityControl
+}
+
+// A SharedConnPool multiplexes clients of the same host over a single connection. Clients
+// dialing the same key share one underlying connection, which is closed once the last of
+// them closes their handle. On every shared connection, bulk calls are throttled in favor of
+// control calls.
+type SharedConnPool struct {
+	logger  golog.Logger
+	maxBulk int
+	dial    func(ctx context.Context, address string, logger golog.Logger, opts ...rpc.DialOption) (rpc.ClientConn, error)
+
+	mu     sync.Mutex
+	conns  map[string]*sharedConn
+	closed bool
+}
+
+// NewSharedConnPool returns a new, empty pool of shared connections.
+func NewSharedConnPool(logger golog.Logger) *SharedConnPool {
+	return &SharedConnPool{
+		logger:  logger,
+		maxBulk: defaultMaxConcurrentBulkCalls,
+		dial:    Dial,
+		conns:   map[string]*sharedConn{},
+	}
+}
+
+type sharedConn struct {
+	key    string
+	ready  chan struct{}
+	conn   rpc.ClientConn
+	err    error
+	refs   int
+	closed bool
+	bulk   chan struct{}
+}
+
+// Dial returns a handle to the connection shared under key, dialing address with opts if
+// there is none yet. The key must identify everything that makes a connection distinct,
+// such as the address and credentials used. Closing the handle releases it.
+func (p *SharedConnPool) Dial(
+	ctx context.Context,
+	key, address string,
+	opts ...rpc.DialOption,
+) (rpc.ClientConn, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, errors.New("shared connection pool is closed")
+	}
+	sc, ok := p.conns[key]
+	if !ok {
+		sc = &sharedConn{key: key, ready: make(chan struct{}), bulk: make(chan struct{}, p.maxBulk)}
+		p.conns[key] = sc
+	}
+	sc.refs++
+	p.mu.Unlock()
+
+	if !ok {
+		p.logger.Debugw("dialing shared connection", "address", address)
+		sc.conn, sc.err = p.dial(ctx, address, p.logger, opts...)
+		close(sc.ready)
+	} else {
+		select {
+		case <-ctx.Done():
+			p.release(sc)
+			return nil, ctx.Err()
+		case <-sc.ready:
+		}
+	}
+	if sc.err != nil {
+		p.release(sc)
+		return nil, sc.err
+	}
+	return &sharedConnHandle{sharedConn: sc, pool: p}, nil
+}
+
+// Invalidate makes the next Dial for the connection behind the given handle create a new
+// connection. It is used when a client detected that the connection is no longer working;
+// other clients keep their handles until they detect the same.
+func (p *SharedConnPool) Invalidate(conn rpc.ClientConn) {
+	if intercepted, ok := conn.(*interceptedClientConn); ok {
+		conn = intercepted.ClientConn
+	}
+	handle, ok := conn.(*sharedConnHandle)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conns[handle.key] == handle.sharedConn {
+		delete(p.conns, handle.key)
+	}
+}
+
+// release drops a reference to sc, closing its connection once unreferenced.
+func (p *SharedConnPool) release(sc *sharedConn) error {
+	p.mu.Lock()
+	sc.refs--
+	if sc.refs > 0 || sc.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	sc.closed = true
+	if p.conns[sc.key] == sc {
+		delete(p.conns, sc.key)
+	}
+	p.mu.Unlock()
+
+	<-sc.ready
+	if sc.conn == nil {
+		return nil
+	}
+	return sc.conn.Close()
+}
+
+// Close closes every connection in the pool, regardless of outstanding handles.
+func (p *SharedConnPool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	conns := p.conns
+	p.conns = map[string]*sharedConn{}
+	for _, sc := range conns {
+		sc.closed = true
+	}
+	p.mu.Unlock()
+
+	var err error
+	for _, sc := range conns {
+		<-sc.ready
+		if sc.conn != nil {
+			err = multierr.Combine(err, sc.conn.Close())
+		}
+	}
+	return err
+}
+
+// sharedConnHandle is a single client's reference to a shared connection.
+type sharedConnHandle struct {
+	*sharedConn
+	pool      *SharedConnPool
+	closeOnce sync.Once
+}
+
+// acquire waits until a bulk call may proceed and returns a function to release its slot,
+// which may be called any number of times.
+func (h *sharedConnHandle) acquire(ctx context.Context, method string) (func(), error) {
+	if MethodPriority(method) != PriorityBulk {
+		return func() {}, nil
+	}
+	// a call whose context is already done must not take a free slot
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case h.bulk <- struct{}{}:
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-h.bulk })
+	}, nil
+}
+
+func (h *sharedConnHandle) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	release, err := h.acquire(ctx, method)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return h.conn.Invoke(ctx, method, args, reply, opts...)
+}
+
+func (h *sharedConnHandle) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	release, err := h.acquire(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := h.conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if MethodPriority(method) != PriorityBulk {
+		return cs, nil
+	}
+	// the context of a stream is done once the stream has finished, as well as when the
+	// context it was opened with is
+	utils.PanicCapturingGo(func() {
+		select {
+		case <-ctx.Done():
+		case <-cs.Context().Done():
+		}
+		release()
+	})
+	return &bulkClientStream{ClientStream: cs, release: release}, nil
+}
+
+// Close releases the handle's reference to the shared connection.
+func (h *sharedConnHandle) Close() error {
+	var err error
+	h.closeOnce.Do(func() {
+		err = h.pool.release(h.sharedConn)
+	})
+	return err
+}
+
+// bulkClientStream releases its bulk slot as soon as the stream has ended, without waiting for
+// its context to be done.
+type bulkClientStream struct {
+	googlegrpc.ClientStream
+	release func()
+}
+
+func (s *bulkClientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.release()
+	}
+	return err
+}
+
+// NewInterceptedClientConn returns a connection which runs every call through the given
+// interceptors before passing it to conn, in order from first to last. It allows clients
+// sharing a connection to each apply their own interceptors, which would otherwise be fixed
+// by the dial options of whoever dialed first. The interceptors are passed a nil
+// *grpc.ClientConn.
+func NewInterceptedClientConn(
+	conn rpc.ClientConn,
+	unary []googlegrpc.UnaryClientInterceptor,
+	stream []googlegrpc.StreamClientInterceptor,
+) rpc.ClientConn {
+	invoker := func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *googlegrpc.ClientConn,
+		opts ...googlegrpc.CallOption,
+	) error {
+		return conn.Invoke(ctx, method, req, reply, opts...)
+	}
+	for i := len(unary) - 1; i >= 0; i-- {
+		next, interceptor := invoker, unary[i]
+		invoker = func(
+			ctx context.Context,
+			method string,
+			req, reply interface{},
+			cc *googlegrpc.ClientConn,
+			opts ...googlegrpc.CallOption,
+		) error {
+			return interceptor(ctx, method, req, reply, cc, next, opts...)
+		}
+	}
+
+	streamer := func(
+		ctx context.Context,
+		desc *googlegrpc.StreamDesc,
+		cc *googlegrpc.ClientConn,
+		method string,
+		opts ...googlegrpc.CallOption,
+	) (googlegrpc.ClientStream, error) {
+		return conn.NewStream(ctx, desc, method, opts...)
+	}
+	for i := len(stream) - 1; i >= 0; i-- {
+		next, interceptor := streamer, stream[i]
+		streamer = func(
+			ctx context.Context,
+			desc *googlegrpc.StreamDesc,
+			cc *googlegrpc.ClientConn,
+			method string,
+			opts ...googlegrpc.CallOption,
+		) (googlegrpc.ClientStream, error) {
+			return interceptor(ctx, desc, cc, method, next, opts...)
+		}
+	}
+	return &interceptedClientConn{ClientConn: conn, invoker: invoker, streamer: streamer}
+}
+
+type interceptedClientConn struct {
+	rpc.ClientConn
+	invoker  googlegrpc.UnaryInvoker
+	streamer googlegrpc.Streamer
+}
+
+func (c *interceptedClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	return c.invoker(ctx, method, args, reply, nil, opts...)
+}
+
+func (c *interceptedClientConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	return c.streamer(ctx, desc, nil, method, opts...)
+}
@@ -0,0 +1,196 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+)
+
+type fakeClientConn struct {
+	closed  int32
+	invoked chan string
+	block   chan struct{}
+}
+
+func (c *fakeClientConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	if c.invoked != nil {
+		c.invoked <- method
+	}
+	if c.block != nil && MethodPriority(method) == PriorityBulk {
+		<-c.block
+	}
+	return nil
+}
+
+func (c *fakeClientConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &fakeClientStream{ctx: ctx, cancel: cancel}, nil
+}
+
+// fakeClientStream ends, like a real stream, with its context done after RecvMsg fails.
+type fakeClientStream struct {
+	googlegrpc.ClientStream
+	ctx    context.Context
+	cancel func()
+}
+
+func (s *fakeClientStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeClientStream) RecvMsg(m interface{}) error {
+	s.cancel()
+	return io.EOF
+}
+
+func (c *fakeClientConn) Close() error {
+	atomic.AddInt32(&c.closed, 1)
+	return nil
+}
+
+func TestMethodPriority(t *testing.T) {
+	test.That(t, MethodPriority("/viam.component.camera.v1.CameraService/GetImage"), test.ShouldEqual, PriorityBulk)
+	test.That(t, MethodPriority("/viam.component.camera.v1.CameraService/GetProperties"), test.ShouldEqual, PriorityControl)
+	test.That(t, MethodPriority("/viam.component.motor.v1.MotorService/SetPower"), test.ShouldEqual, PriorityControl)
+}
+
+func TestSharedConnPool(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	pool := NewSharedConnPool(logger)
+	var dialed []*fakeClientConn
+	pool.dial = func(ctx context.Context, address string, logger golog.Logger, opts ...rpc.DialOption) (rpc.ClientConn, error) {
+		conn := &fakeClientConn{}
+		dialed = append(dialed, conn)
+		return conn, nil
+	}
+
+	conn1, err := pool.Dial(context.Background(), "a", "host1:8080")
+	test.That(t, err, test.ShouldBeNil)
+	conn2, err := pool.Dial(context.Background(), "a", "host1:8080")
+	test.That(t, err, test.ShouldBeNil)
+	conn3, err := pool.Dial(context.Background(), "b", "host2:8080")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dialed, test.ShouldHaveLength, 2)
+
+	test.That(t, conn1.Close(), test.ShouldBeNil)
+	test.That(t, conn1.Close(), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&dialed[0].closed), test.ShouldEqual, 0)
+	test.That(t, conn2.Close(), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&dialed[0].closed), test.ShouldEqual, 1)
+
+	// an invalidated connection is no longer handed out but stays open for its holders
+	pool.Invalidate(NewInterceptedClientConn(conn3, nil, nil))
+	conn4, err := pool.Dial(context.Background(), "b", "host2:8080")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dialed, test.ShouldHaveLength, 3)
+	test.That(t, atomic.LoadInt32(&dialed[1].closed), test.ShouldEqual, 0)
+	test.That(t, conn3.Close(), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&dialed[1].closed), test.ShouldEqual, 1)
+
+	test.That(t, pool.Close(), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&dialed[2].closed), test.ShouldEqual, 1)
+	test.That(t, conn4.Close(), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&dialed[2].closed), test.ShouldEqual, 1)
+	_, err = pool.Dial(context.Background(), "a", "host1:8080")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestSharedConnPoolPriority(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	pool := NewSharedConnPool(logger)
+	pool.maxBulk = 1
+	fake := &fakeClientConn{invoked: make(chan string, 10), block: make(chan struct{})}
+	pool.dial = func(ctx context.Context, address string, logger golog.Logger, opts ...rpc.DialOption) (rpc.ClientConn, error) {
+		return fake, nil
+	}
+	conn, err := pool.Dial(context.Background(), "a", "host1:8080")
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, pool.Close(), test.ShouldBeNil)
+	}()
+
+	const getImage = "/viam.component.camera.v1.CameraService/GetImage"
+	bulkDone := make(chan error, 1)
+	go func() {
+		bulkDone <- conn.Invoke(context.Background(), getImage, nil, nil)
+	}()
+	test.That(t, <-fake.invoked, test.ShouldEqual, getImage)
+
+	// a second bulk call has to wait for the first
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = conn.Invoke(ctx, getImage, nil, nil)
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+
+	// while control calls go right through
+	test.That(t, conn.Invoke(context.Background(), "/viam.component.motor.v1.MotorService/Stop", nil, nil), test.ShouldBeNil)
+	test.That(t, <-fake.invoked, test.ShouldEqual, "/viam.component.motor.v1.MotorService/Stop")
+
+	close(fake.block)
+	test.That(t, <-bulkDone, test.ShouldBeNil)
+	test.That(t, conn.Invoke(context.Background(), getImage, nil, nil), test.ShouldBeNil)
+
+	// a bulk stream holds its slot until it ends or its context is done
+	const getStream = "/viam.component.camera.v1.CameraService/GetPointCloud"
+	waitForSlot := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		test.That(t, conn.Invoke(ctx, getImage, nil, nil), test.ShouldBeNil)
+	}
+	stream, err := conn.NewStream(context.Background(), &googlegrpc.StreamDesc{}, getStream)
+	test.That(t, err, test.ShouldBeNil)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	test.That(t, conn.Invoke(ctx, getImage, nil, nil), test.ShouldBeError, context.DeadlineExceeded)
+	test.That(t, stream.RecvMsg(nil), test.ShouldEqual, io.EOF)
+	waitForSlot()
+
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	_, err = conn.NewStream(streamCtx, &googlegrpc.StreamDesc{}, getStream)
+	test.That(t, err, test.ShouldBeNil)
+	streamCancel()
+	waitForSlot()
+	_, err = conn.NewStream(streamCtx, &googlegrpc.StreamDesc{}, getStream)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
+
+func TestInterceptedClientConn(t *testing.T) {
+	var order []string
+	unary := func(name string) googlegrpc.UnaryClientInterceptor {
+		return func(
+			ctx context.Context,
+			method string,
+			req, reply interface{},
+			cc *googlegrpc.ClientConn,
+			invoker googlegrpc.UnaryInvoker,
+			opts ...googlegrpc.CallOption,
+		) error {
+			order = append(order, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	fake := &fakeClientConn{invoked: make(chan string, 1)}
+	conn := NewInterceptedClientConn(fake, []googlegrpc.UnaryClientInterceptor{unary("first"), unary("second")}, nil)
+	test.That(t, conn.Invoke(context.Background(), "/a/B", nil, nil), test.ShouldBeNil)
+	test.That(t, order, test.ShouldResemble, []string{"first", "second"})
+	test.That(t, <-fake.invoked, test.ShouldEqual, "/a/B")
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	test.That(t, atomic.LoadInt32(&fake.closed), test.ShouldEqual, 1)
+}
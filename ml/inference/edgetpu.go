@@ -0,0 +1,38 @@
+//go:build edgetpu && !arm && !windows
+
+package inference
+
+import (
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/mattn/go-tflite/delegates/edgetpu"
+)
+
+// listEdgeTPUDevices returns the EdgeTPUs connected to the system.
+func listEdgeTPUDevices() ([]EdgeTPUDevice, error) {
+	devices, err := edgetpu.DeviceList()
+	if err != nil {
+		return nil, err
+	}
+	found := make([]EdgeTPUDevice, 0, len(devices))
+	for _, device := range devices {
+		deviceType := EdgeTPUUSB
+		if device.Type == edgetpu.TypeApexPCI {
+			deviceType = EdgeTPUPCI
+		}
+		found = append(found, EdgeTPUDevice{Type: deviceType, Path: device.Path})
+	}
+	return found, nil
+}
+
+// newEdgeTPUDelegate returns a delegate that runs models on an EdgeTPU.
+func newEdgeTPUDelegate(device EdgeTPUDevice) (delegates.Delegater, error) {
+	deviceType := edgetpu.TypeApexUSB
+	if device.Type == EdgeTPUPCI {
+		deviceType = edgetpu.TypeApexPCI
+	}
+	delegate := edgetpu.New(edgetpu.Device{Type: deviceType, Path: device.Path})
+	if delegate == nil {
+		return nil, FailedToLoadError("EdgeTPU delegate")
+	}
+	return delegate, nil
+}
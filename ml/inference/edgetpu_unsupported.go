@@ -0,0 +1,18 @@
+//go:build !edgetpu && !arm && !windows
+
+package inference
+
+import (
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/pkg/errors"
+)
+
+var errEdgeTPUNotBuilt = errors.New("EdgeTPU support requires building with the edgetpu tag and libedgetpu installed")
+
+func listEdgeTPUDevices() ([]EdgeTPUDevice, error) {
+	return nil, errEdgeTPUNotBuilt
+}
+
+func newEdgeTPUDelegate(device EdgeTPUDevice) (delegates.Delegater, error) {
+	return nil, errEdgeTPUNotBuilt
+}
@@ -7,7 +7,9 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/edaniels/golog"
 	tflite "github.com/mattn/go-tflite"
+	"github.com/mattn/go-tflite/delegates"
 	"github.com/pkg/errors"
 
 	tfliteSchema "go.viam.com/rdk/ml/inference/tflite"
@@ -23,6 +25,9 @@ type TFLiteStruct struct {
 	interpreterOptions *tflite.InterpreterOptions
 	Info               *TFLiteInfo
 	modelPath          string
+	edgeTPU            *EdgeTPUDevice
+	edgeTPUDelegate    delegates.Delegater
+	listEdgeTPUs       func() ([]EdgeTPUDevice, error)
 }
 
 // Interpreter interface holds methods used by a tflite interpreter.
@@ -42,6 +47,8 @@ type TFLiteModelLoader struct {
 	newInterpreter     func(model *tflite.Model, options *tflite.InterpreterOptions) (Interpreter, error)
 	interpreterOptions *tflite.InterpreterOptions
 	getInfo            func(inter Interpreter) *TFLiteInfo
+	edgeTPU            *EdgeTPUDevice
+	edgeTPUDelegate    delegates.Delegater
 }
 
 // NewDefaultTFLiteModelLoader returns the default loader when using tflite.
@@ -82,6 +89,71 @@ func NewTFLiteModelLoader(numThreads int) (*TFLiteModelLoader, error) {
 	return loader, nil
 }
 
+// The kinds of EdgeTPU.
+const (
+	EdgeTPUUSB = "usb"
+	EdgeTPUPCI = "pci"
+)
+
+// EdgeTPUOptions select the Coral EdgeTPU accelerator that models run on.
+type EdgeTPUOptions struct {
+	// Device is the kind of EdgeTPU to use, "usb" or "pci". Any kind is used when it is empty.
+	Device string `json:"device,omitempty"`
+	// Path selects the EdgeTPU at a device path, such as /dev/apex_0, when there are several.
+	Path string `json:"path,omitempty"`
+}
+
+// EdgeTPUDevice is an EdgeTPU connected to the system.
+type EdgeTPUDevice struct {
+	Type string
+	Path string
+}
+
+// NewTFLiteModelLoaderWithEdgeTPU returns a loader whose models run on the EdgeTPU selected by opts. When no such
+// EdgeTPU can be used, it logs a warning and returns a loader whose models run on the CPU with numThreads threads.
+func NewTFLiteModelLoaderWithEdgeTPU(numThreads int, opts EdgeTPUOptions, logger golog.Logger) (*TFLiteModelLoader, error) {
+	if opts.Device != "" && opts.Device != EdgeTPUUSB && opts.Device != EdgeTPUPCI {
+		return nil, errors.Errorf("EdgeTPU device must be %q or %q, got %q", EdgeTPUUSB, EdgeTPUPCI, opts.Device)
+	}
+	loader, err := NewTFLiteModelLoader(numThreads)
+	if err != nil {
+		return nil, err
+	}
+	device, delegate, err := openEdgeTPU(opts, listEdgeTPUDevices, newEdgeTPUDelegate)
+	if err != nil {
+		logger.Warnw("running model on the CPU instead of an EdgeTPU, models compiled for the EdgeTPU will fail to load",
+			"error", err)
+		return loader, nil
+	}
+	loader.interpreterOptions.AddDelegate(delegate)
+	loader.edgeTPU = &device
+	loader.edgeTPUDelegate = delegate
+	return loader, nil
+}
+
+// openEdgeTPU finds the first EdgeTPU that matches opts, and makes a delegate that runs models on it.
+func openEdgeTPU(
+	opts EdgeTPUOptions,
+	list func() ([]EdgeTPUDevice, error),
+	newDelegate func(EdgeTPUDevice) (delegates.Delegater, error),
+) (EdgeTPUDevice, delegates.Delegater, error) {
+	devices, err := list()
+	if err != nil {
+		return EdgeTPUDevice{}, nil, err
+	}
+	for _, device := range devices {
+		if (opts.Device != "" && device.Type != opts.Device) || (opts.Path != "" && device.Path != opts.Path) {
+			continue
+		}
+		delegate, err := newDelegate(device)
+		if err != nil {
+			return EdgeTPUDevice{}, nil, err
+		}
+		return device, delegate, nil
+	}
+	return EdgeTPUDevice{}, nil, errors.Errorf("found no EdgeTPU matching %+v among %d connected", opts, len(devices))
+}
+
 // createTFLiteInterpreterOptions returns tflite interpreterOptions with settings.
 func createTFLiteInterpreterOptions(numThreads int) (*tflite.InterpreterOptions, error) {
 	options := tflite.NewInterpreterOptions()
@@ -123,6 +195,9 @@ func (loader TFLiteModelLoader) Load(modelPath string) (*TFLiteStruct, error) {
 		interpreterOptions: loader.interpreterOptions,
 		Info:               info,
 		modelPath:          modelPath,
+		edgeTPU:            loader.edgeTPU,
+		edgeTPUDelegate:    loader.edgeTPUDelegate,
+		listEdgeTPUs:       listEdgeTPUDevices,
 	}
 
 	return modelStruct, nil
@@ -183,6 +258,9 @@ func (model *TFLiteStruct) Infer(inputTensor interface{}) ([]interface{}, error)
 
 	status = interpreter.Invoke()
 	if status != tflite.OK {
+		if err := model.CheckEdgeTPU(); err != nil {
+			return nil, errors.Wrap(err, "invoke failed")
+		}
 		return nil, errors.New("invoke failed")
 	}
 
@@ -281,11 +359,37 @@ func getTFLiteMetadataAsStruct(metaBytes []byte) *metadata.ModelMetadataT {
 	return structMeta
 }
 
+// EdgeTPU returns the EdgeTPU that the model runs on, or nil if it runs on the CPU.
+func (model *TFLiteStruct) EdgeTPU() *EdgeTPUDevice {
+	return model.edgeTPU
+}
+
+// CheckEdgeTPU returns an error if the model runs on an EdgeTPU that is no longer connected.
+func (model *TFLiteStruct) CheckEdgeTPU() error {
+	if model.edgeTPU == nil {
+		return nil
+	}
+	devices, err := model.listEdgeTPUs()
+	if err != nil {
+		return errors.Wrap(err, "could not check on EdgeTPU")
+	}
+	for _, device := range devices {
+		if device == *model.edgeTPU {
+			return nil
+		}
+	}
+	return errors.Errorf("%s EdgeTPU at %s is no longer connected", model.edgeTPU.Type, model.edgeTPU.Path)
+}
+
 // Close should be called at the end of using the interpreter to delete related models and interpreters.
 func (model *TFLiteStruct) Close() error {
 	model.model.Delete()
 	model.interpreterOptions.Delete()
 	model.interpreter.Delete()
+	// the delegate must outlive the interpreter that uses it
+	if model.edgeTPUDelegate != nil {
+		model.edgeTPUDelegate.Delete()
+	}
 	return nil
 }
 
@@ -4,8 +4,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"testing"
+	"unsafe"
 
+	"github.com/edaniels/golog"
 	tflite "github.com/mattn/go-tflite"
+	"github.com/mattn/go-tflite/delegates"
+	"github.com/pkg/errors"
 	"go.viam.com/test"
 )
 
@@ -177,3 +181,93 @@ func modelLoader(path string) *tflite.Model {
 
 	return &tflite.Model{}
 }
+
+type fakeDelegate struct {
+	deleted bool
+}
+
+func (fd *fakeDelegate) Delete() {
+	fd.deleted = true
+}
+
+func (fd *fakeDelegate) Ptr() unsafe.Pointer {
+	return nil
+}
+
+func TestOpenEdgeTPU(t *testing.T) {
+	devices := []EdgeTPUDevice{{Type: EdgeTPUPCI, Path: "/dev/apex_0"}, {Type: EdgeTPUUSB, Path: "/sys/bus/usb/1"}}
+	list := func() ([]EdgeTPUDevice, error) {
+		return devices, nil
+	}
+	var opened []EdgeTPUDevice
+	newDelegate := func(device EdgeTPUDevice) (delegates.Delegater, error) {
+		opened = append(opened, device)
+		return &fakeDelegate{}, nil
+	}
+
+	device, delegate, err := openEdgeTPU(EdgeTPUOptions{}, list, newDelegate)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, device, test.ShouldResemble, devices[0])
+	test.That(t, delegate, test.ShouldNotBeNil)
+
+	device, _, err = openEdgeTPU(EdgeTPUOptions{Device: EdgeTPUUSB}, list, newDelegate)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, device, test.ShouldResemble, devices[1])
+	test.That(t, opened, test.ShouldResemble, devices)
+
+	_, _, err = openEdgeTPU(EdgeTPUOptions{Device: EdgeTPUPCI, Path: "/dev/apex_1"}, list, newDelegate)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "found no EdgeTPU")
+
+	_, _, err = openEdgeTPU(EdgeTPUOptions{}, func() ([]EdgeTPUDevice, error) {
+		return nil, errors.New("no runtime")
+	}, newDelegate)
+	test.That(t, err, test.ShouldBeError, errors.New("no runtime"))
+}
+
+func TestEdgeTPUFallback(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	_, err := NewTFLiteModelLoaderWithEdgeTPU(1, EdgeTPUOptions{Device: "firewire"}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	if _, err := listEdgeTPUDevices(); err == nil {
+		t.Skip("an EdgeTPU runtime is available")
+	}
+	loader, err := NewTFLiteModelLoaderWithEdgeTPU(1, EdgeTPUOptions{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, loader.edgeTPU, test.ShouldBeNil)
+	tfliteStruct, err := loader.Load(basePath + "/testing_files/model_with_metadata.tflite")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tfliteStruct.EdgeTPU(), test.ShouldBeNil)
+	test.That(t, tfliteStruct.CheckEdgeTPU(), test.ShouldBeNil)
+	test.That(t, tfliteStruct.Close(), test.ShouldBeNil)
+}
+
+func TestCheckEdgeTPU(t *testing.T) {
+	delegate := &fakeDelegate{}
+	connected := []EdgeTPUDevice{{Type: EdgeTPUUSB, Path: "/sys/bus/usb/1"}}
+	loader := &TFLiteModelLoader{
+		newModelFromFile: modelLoader,
+		newInterpreter: func(model *tflite.Model, options *tflite.InterpreterOptions) (Interpreter, error) {
+			return &fakeInterpreter{}, nil
+		},
+		interpreterOptions: goodOptions,
+		getInfo:            goodGetInfo,
+		edgeTPU:            &connected[0],
+		edgeTPUDelegate:    delegate,
+	}
+	tfStruct, err := loader.Load("random path")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tfStruct.EdgeTPU(), test.ShouldResemble, &connected[0])
+	tfStruct.listEdgeTPUs = func() ([]EdgeTPUDevice, error) {
+		return connected, nil
+	}
+	test.That(t, tfStruct.CheckEdgeTPU(), test.ShouldBeNil)
+
+	tfStruct.listEdgeTPUs = func() ([]EdgeTPUDevice, error) {
+		return nil, nil
+	}
+	err = tfStruct.CheckEdgeTPU()
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no longer connected")
+}
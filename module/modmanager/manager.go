@@ -3,7 +3,11 @@ package modmanager
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"sync"
 	"time"
@@ -13,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 	pb "go.viam.com/api/module/v1"
+	"go.viam.com/utils"
 	"go.viam.com/utils/pexec"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -40,11 +45,32 @@ func NewManager(r robot.LocalRobot) (modmaninterface.ModuleManager, error) {
 type module struct {
 	name    string
 	exe     string
+	version string
+	exeStat exeStat
 	process pexec.ManagedProcess
 	handles modlib.HandlerMap
 	conn    *grpc.ClientConn
 	client  pb.ModuleServiceClient
 	addr    string
+
+	// resources are the resources currently served by the module, kept so that
+	// they can be rebuilt after the module is restarted.
+	resources map[resource.Name]*addedResource
+	// restoreRegistrations undo the registrations of the module's models, bringing
+	// back any builtin models they replaced.
+	restoreRegistrations []func()
+}
+
+type addedResource struct {
+	conf config.Component
+	deps []string
+}
+
+// exeStat identifies the contents of a module executable at a point in time.
+type exeStat struct {
+	size    int64
+	modTime time.Time
+	hash    string
 }
 
 // Manager is the root structure for the module system.
@@ -82,7 +108,18 @@ func (mgr *Manager) Add(ctx context.Context, cfg config.Module) error {
 		return nil
 	}
 
-	mod := &module{name: cfg.Name, exe: cfg.ExePath}
+	stat, err := statExe(cfg.ExePath, exeStat{})
+	if err != nil {
+		return err
+	}
+
+	mod := &module{
+		name:      cfg.Name,
+		exe:       cfg.ExePath,
+		version:   cfg.Version,
+		exeStat:   stat,
+		resources: map[resource.Name]*addedResource{},
+	}
 	mgr.modules[cfg.Name] = mod
 
 	parentAddr, err := mgr.r.ModuleAddress()
@@ -90,7 +127,7 @@ func (mgr *Manager) Add(ctx context.Context, cfg config.Module) error {
 		return err
 	}
 
-	if err := mod.startProcess(ctx, parentAddr, mgr.logger); err != nil {
+	if err := mod.startProcess(ctx, parentAddr, mod.exe, mgr.logger); err != nil {
 		return errors.WithMessage(err, "error while starting module "+mod.name)
 	}
 
@@ -135,6 +172,7 @@ func (mgr *Manager) AddResource(ctx context.Context, cfg config.Component, deps
 		return nil, err
 	}
 	mgr.rMap[cfg.ResourceName()] = module
+	module.resources[cfg.ResourceName()] = &addedResource{conf: cfg, deps: deps}
 
 	c := registry.ResourceSubtypeLookup(cfg.ResourceName().Subtype)
 	if c == nil || c.RPCClient == nil {
@@ -147,8 +185,8 @@ func (mgr *Manager) AddResource(ctx context.Context, cfg config.Component, deps
 
 // ReconfigureResource updates/reconfigures a modular component with a new configuration.
 func (mgr *Manager) ReconfigureResource(ctx context.Context, cfg config.Component, deps []string) error {
-	mgr.mu.RLock()
-	defer mgr.mu.RUnlock()
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
 	module, ok := mgr.getModule(cfg)
 	if !ok {
 		return errors.Errorf("no module registered to serve resource api %s and model %s", cfg.ResourceName().Subtype, cfg.Model)
@@ -162,6 +200,7 @@ func (mgr *Manager) ReconfigureResource(ctx context.Context, cfg config.Componen
 	if err != nil {
 		return err
 	}
+	module.resources[cfg.ResourceName()] = &addedResource{conf: cfg, deps: deps}
 
 	return nil
 }
@@ -191,33 +230,124 @@ func (mgr *Manager) RemoveResource(ctx context.Context, name resource.Name) erro
 		return errors.Errorf("resource %+v not found in module", name)
 	}
 	delete(mgr.rMap, name)
+	delete(module.resources, name)
 	_, err := module.client.RemoveResource(ctx, &pb.RemoveResourceRequest{Name: name.String()})
 	return err
 }
 
-func (mgr *Manager) getModule(cfg config.Component) (*module, bool) {
-	for _, module := range mgr.modules {
-		var api resource.RPCSubtype
-		var ok bool
-		for a := range module.handles {
-			if a.Subtype == cfg.ResourceName().Subtype {
-				api = a
-				ok = true
-				break
-			}
+// Reload restarts a running module if it has changed since it was started, either because its
+// configured version differs or because the contents of its executable do. The resources served
+// by the module are drained before the restart and rebuilt afterwards with their last known
+// configuration. The connection to the module is kept, so clients previously returned by
+// AddResource continue to work. It returns whether the module was restarted; modules that are
+// not running are left alone.
+func (mgr *Manager) Reload(ctx context.Context, cfg config.Module) (bool, error) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	mod, ok := mgr.modules[cfg.Name]
+	if !ok || mod.process == nil {
+		return false, nil
+	}
+
+	stat, err := statExe(cfg.ExePath, mod.exeStat)
+	if err != nil {
+		return false, err
+	}
+	if mod.exe == cfg.ExePath && mod.version == cfg.Version && mod.exeStat.hash == stat.hash {
+		// contents are the same but remember the new stat so we don't hash again
+		mod.exeStat = stat
+		return false, nil
+	}
+	mgr.logger.Infow("module changed, restarting", "module", mod.name, "version", cfg.Version)
+
+	// drain the old process of its resources before stopping it
+	for name := range mod.resources {
+		if _, err := mod.client.RemoveResource(ctx, &pb.RemoveResourceRequest{Name: name.String()}); err != nil {
+			mgr.logger.Warnw("error draining resource from module", "module", mod.name, "resource", name, "error", err)
+		}
+	}
+	if err := mod.process.Stop(); err != nil {
+		mgr.logger.Warnw("error stopping module", "module", mod.name, "error", err)
+	}
+	if err := os.Remove(mod.addr); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return true, errors.WithMessage(err, "error removing socket of module "+mod.name)
+	}
+	mod.deregisterResources()
+	// the old executable is no longer running, so until the new one is ready any later reload
+	// restarts the module, even with the old config.
+	mod.exeStat = exeStat{}
+
+	parentAddr, err := mgr.r.ModuleAddress()
+	if err != nil {
+		return true, err
+	}
+	if err := mod.startProcess(ctx, parentAddr, cfg.ExePath, mgr.logger); err != nil {
+		return true, errors.WithMessage(err, "error while restarting module "+mod.name)
+	}
+	// the process listens on the same socket as before, so reconnect right away rather than waiting
+	// out the connection's backoff.
+	mod.conn.ResetConnectBackoff()
+	if err := mod.checkReady(ctx, parentAddr); err != nil {
+		return true, errors.WithMessage(err, "error while waiting for module to be ready "+mod.name)
+	}
+	mod.exe = cfg.ExePath
+	mod.version = cfg.Version
+	mod.exeStat = stat
+	mod.registerResources(mgr, mgr.logger)
+
+	// rebuild the resources in the new process
+	var allErrs error
+	for name, res := range mod.resources {
+		if !mod.provides(res.conf) {
+			allErrs = multierr.Combine(allErrs,
+				errors.Errorf("module %s no longer serves resource api %s and model %s", mod.name, name.Subtype, res.conf.Model))
+			delete(mod.resources, name)
+			delete(mgr.rMap, name)
+			continue
 		}
-		if !ok {
+		cfgProto, err := config.ComponentConfigToProto(&res.conf)
+		if err != nil {
+			allErrs = multierr.Combine(allErrs, err)
 			continue
 		}
-		for _, model := range module.handles[api] {
-			if cfg.Model == model {
-				return module, true
-			}
+		if _, err := mod.client.AddResource(ctx, &pb.AddResourceRequest{Config: cfgProto, Dependencies: res.deps}); err != nil {
+			allErrs = multierr.Combine(allErrs, errors.WithMessagef(err, "error rebuilding resource %s", name))
+		}
+	}
+	return true, allErrs
+}
+
+func (mgr *Manager) getModule(cfg config.Component) (*module, bool) {
+	for _, module := range mgr.modules {
+		if module.provides(cfg) {
+			return module, true
 		}
 	}
 	return nil, false
 }
 
+func (m *module) provides(cfg config.Component) bool {
+	var api resource.RPCSubtype
+	var ok bool
+	for a := range m.handles {
+		if a.Subtype == cfg.ResourceName().Subtype {
+			api = a
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return false
+	}
+	for _, model := range m.handles[api] {
+		if cfg.Model == model {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *module) dial() error {
 	var err error
 	// TODO(PRODUCT-343): session support probably means interceptors here
@@ -259,14 +389,14 @@ func (m *module) checkReady(ctx context.Context, parentAddr string) error {
 	}
 }
 
-func (m *module) startProcess(ctx context.Context, parentAddr string, logger golog.Logger) error {
+func (m *module) startProcess(ctx context.Context, parentAddr, exe string, logger golog.Logger) error {
 	m.addr = filepath.ToSlash(filepath.Join(filepath.Dir(parentAddr), m.name+".sock"))
 	if err := modlib.CheckSocketAddressLength(m.addr); err != nil {
 		return err
 	}
 	pcfg := pexec.ProcessConfig{
 		ID:   m.name,
-		Name: m.exe,
+		Name: exe,
 		Args: []string{m.addr},
 		Log:  true,
 	}
@@ -307,19 +437,25 @@ func (m *module) registerResources(mgr modmaninterface.ModuleManager, logger gol
 		switch api.Subtype.ResourceType {
 		case resource.ResourceTypeComponent:
 			for _, model := range models {
-				registry.RegisterComponent(api.Subtype, model, registry.Component{
+				if registry.ComponentLookup(api.Subtype, model) != nil {
+					logger.Warnw("module replaces an existing model", "module", m.name, "api", api.Subtype, "model", model)
+				}
+				m.restoreRegistrations = append(m.restoreRegistrations, registry.OverrideComponent(api.Subtype, model, registry.Component{
 					Constructor: func(ctx context.Context, deps registry.Dependencies, cfg config.Component, logger golog.Logger) (interface{}, error) {
 						return mgr.AddResource(ctx, cfg, DepsToNames(deps))
 					},
-				})
+				}))
 			}
 		case resource.ResourceTypeService:
 			for _, model := range models {
-				registry.RegisterService(api.Subtype, model, registry.Service{
+				if registry.ServiceLookup(api.Subtype, model) != nil {
+					logger.Warnw("module replaces an existing model", "module", m.name, "api", api.Subtype, "model", model)
+				}
+				m.restoreRegistrations = append(m.restoreRegistrations, registry.OverrideService(api.Subtype, model, registry.Service{
 					Constructor: func(ctx context.Context, deps registry.Dependencies, cfg config.Service, logger golog.Logger) (interface{}, error) {
 						return mgr.AddResource(ctx, config.ServiceConfigToShared(cfg), DepsToNames(deps))
 					},
-				})
+				}))
 			}
 		default:
 			logger.Errorf("invalid module type: %s", api.Subtype.Type)
@@ -327,6 +463,40 @@ func (m *module) registerResources(mgr modmaninterface.ModuleManager, logger gol
 	}
 }
 
+func (m *module) deregisterResources() {
+	for i := len(m.restoreRegistrations) - 1; i >= 0; i-- {
+		m.restoreRegistrations[i]()
+	}
+	m.restoreRegistrations = nil
+	m.handles = nil
+}
+
+// statExe returns the current exeStat of the executable at path. The executable is only
+// hashed again if its size or modification time differ from prev.
+func statExe(path string, prev exeStat) (exeStat, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return exeStat{}, errors.Wrapf(err, "module executable path error")
+	}
+	stat := exeStat{size: info.Size(), modTime: info.ModTime(), hash: prev.hash}
+	if prev.hash != "" && stat.size == prev.size && stat.modTime.Equal(prev.modTime) {
+		return stat, nil
+	}
+
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return exeStat{}, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return exeStat{}, err
+	}
+	stat.hash = hex.EncodeToString(h.Sum(nil))
+	return stat, nil
+}
+
 // DepsToNames converts a dependency list to a simple string slice.
 func DepsToNames(deps registry.Dependencies) []string {
 	var depStrings []string
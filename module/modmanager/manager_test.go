@@ -4,7 +4,9 @@ import (
 	"context"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/edaniels/golog"
 	"go.viam.com/test"
@@ -62,7 +64,7 @@ func TestModManagerFunctions(t *testing.T) {
 
 	mod := &module{name: "test", exe: modExe}
 
-	err = mod.startProcess(ctx, parentAddr, logger)
+	err = mod.startProcess(ctx, parentAddr, modExe, logger)
 	test.That(t, err, test.ShouldBeNil)
 
 	err = mod.dial()
@@ -138,6 +140,52 @@ func TestModManagerFunctions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, ret["total"], test.ShouldEqual, 0)
 
+	t.Log("test Reload")
+	reloaded, err := mgr.Reload(ctx, modCfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reloaded, test.ShouldBeFalse)
+
+	ret, err = counter.DoCommand(ctx, map[string]interface{}{"command": "add", "value": 5})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ret["total"], test.ShouldEqual, 5)
+
+	modCfg.Version = "2"
+	reloaded, err = mgr.Reload(ctx, modCfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reloaded, test.ShouldBeTrue)
+
+	// the existing client talks to the rebuilt resource in the new process
+	ret, err = counter.DoCommand(ctx, map[string]interface{}{"command": "get"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ret["total"], test.ShouldEqual, 0)
+	ok = mgr.IsModularResource(rNameCounter1)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	reloaded, err = mgr.Reload(ctx, modCfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reloaded, test.ShouldBeFalse)
+
+	// a module that fails to restart is restarted by the next reload, even with the same config
+	badExe := filepath.Join(filepath.Dir(parentAddr), "bad.sh")
+	test.That(t, os.WriteFile(badExe, []byte("#!/bin/sh\nexit 1\n"), 0o700), test.ShouldBeNil)
+	badCfg := modCfg
+	badCfg.ExePath = badExe
+	for i := 0; i < 2; i++ {
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+		reloaded, err = mgr.Reload(timeoutCtx, badCfg)
+		cancel()
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, reloaded, test.ShouldBeTrue)
+	}
+
+	reloaded, err = mgr.Reload(ctx, modCfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, reloaded, test.ShouldBeTrue)
+
+	ret, err = counter.DoCommand(ctx, map[string]interface{}{"command": "add", "value": 2})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ret["total"], test.ShouldEqual, 2)
+
 	t.Log("test RemoveResource")
 	err = mgr.RemoveResource(ctx, rNameCounter1)
 	test.That(t, err, test.ShouldBeNil)
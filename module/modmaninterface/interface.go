@@ -11,6 +11,7 @@ import (
 // ModuleManager abstracts the module manager interface.
 type ModuleManager interface {
 	Add(ctx context.Context, cfg config.Module) error
+	Reload(ctx context.Context, cfg config.Module) (bool, error)
 
 	AddResource(ctx context.Context, cfg config.Component, deps []string) (interface{}, error)
 	ReconfigureResource(ctx context.Context, cfg config.Component, deps []string) error
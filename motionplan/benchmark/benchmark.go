@@ -0,0 +1,202 @@
+// Package benchmark measures how well motion planners solve a set of planning scenarios, so that changes to planning algorithms can
+// be evaluated quantitatively. Scenarios are read from JSON files, each registered planner plans each scenario several times, and
+// how often it succeeds, what its plans cost and how long planning takes are written out as CSV.
+package benchmark
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/motionplan"
+)
+
+var (
+	plannersMu sync.Mutex
+	planners   = map[string]map[string]interface{}{
+		// the planner used when no planning_alg is given, which falls back from RRT* to CBiRRT
+		"default": {},
+		"cbirrt":  {"planning_alg": "cbirrt"},
+		"rrtstar": {"planning_alg": "rrtstar"},
+		"prm":     {"planning_alg": "prm", "reuse_roadmap": false},
+	}
+)
+
+// RegisterPlanner registers a planner to benchmark under a name, as the planning options which set it up. Its options are set over
+// those of each scenario. It panics if the name is already registered.
+func RegisterPlanner(name string, planningOpts map[string]interface{}) {
+	plannersMu.Lock()
+	defer plannersMu.Unlock()
+	if _, ok := planners[name]; ok {
+		panic(errors.Errorf("trying to register two planners with the same name %q", name))
+	}
+	planners[name] = planningOpts
+}
+
+// Planners returns the names of every registered planner, in order.
+func Planners() []string {
+	plannersMu.Lock()
+	defer plannersMu.Unlock()
+	names := make([]string, 0, len(planners))
+	for name := range planners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// plannerOptions returns the planning options for a run of the named planner on a scenario.
+func plannerOptions(name string, s *Scenario, run int) (map[string]interface{}, error) {
+	plannersMu.Lock()
+	defer plannersMu.Unlock()
+	plannerOpts, ok := planners[name]
+	if !ok {
+		return nil, errors.Errorf("no planner registered with the name %q", name)
+	}
+	opts := map[string]interface{}{}
+	for key, value := range s.Options {
+		opts[key] = value
+	}
+	for key, value := range plannerOpts {
+		opts[key] = value
+	}
+	// each run is seeded differently, but the same from one benchmark to the next
+	opts["rseed"] = run
+	return opts, nil
+}
+
+// Result is how a planner did on a scenario.
+type Result struct {
+	Scenario  string
+	Planner   string
+	Runs      int
+	Successes int
+	// Mean cost of the plans found, and mean time taken to find them
+	MeanCost    float64
+	MeanLatency time.Duration
+	// Longest time taken by any run, successful or not
+	MaxLatency time.Duration
+}
+
+// SuccessRate returns the fraction of runs which found a plan.
+func (r *Result) SuccessRate() float64 {
+	if r.Runs == 0 {
+		return 0
+	}
+	return float64(r.Successes) / float64(r.Runs)
+}
+
+// Run plans each scenario runs times with each of the named planners, or every registered planner if none are named. Runs which
+// fail to plan count against the success rate of the planner, but only a canceled context stops the benchmark.
+func Run(
+	ctx context.Context,
+	logger golog.Logger,
+	scenarios []*Scenario,
+	plannerNames []string,
+	runs int,
+) ([]*Result, error) {
+	if runs < 1 {
+		return nil, errors.New("must run each planner at least once")
+	}
+	if len(plannerNames) == 0 {
+		plannerNames = Planners()
+	}
+	results := make([]*Result, 0, len(scenarios)*len(plannerNames))
+	for _, s := range scenarios {
+		fs, err := s.FrameSystem(logger)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario %q", s.Name)
+		}
+		worldState, err := s.WorldState()
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario %q", s.Name)
+		}
+		seedMap, err := s.SeedMap(fs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario %q", s.Name)
+		}
+		goal, err := s.GoalPose()
+		if err != nil {
+			return nil, errors.Wrapf(err, "scenario %q", s.Name)
+		}
+		f := fs.Frame(s.Frame)
+		if f == nil {
+			return nil, errors.Errorf("scenario %q moves frame %q, which is not in its frame system", s.Name, s.Frame)
+		}
+
+		for _, name := range plannerNames {
+			result := &Result{Scenario: s.Name, Planner: name, Runs: runs}
+			var totalLatency time.Duration
+			for i := 0; i < runs; i++ {
+				opts, err := plannerOptions(name, s, i)
+				if err != nil {
+					return nil, err
+				}
+				start := time.Now()
+				_, report, err := motionplan.PlanMotionWithReport(ctx, logger, goal, f, seedMap, fs, worldState, opts)
+				latency := time.Since(start)
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					return nil, ctxErr
+				}
+				if latency > result.MaxLatency {
+					result.MaxLatency = latency
+				}
+				if err != nil {
+					logger.Debugf("planner %q failed run %d of scenario %q: %v", name, i, s.Name, err)
+					continue
+				}
+				result.Successes++
+				result.MeanCost += report.Cost
+				totalLatency += latency
+			}
+			if result.Successes > 0 {
+				result.MeanCost /= float64(result.Successes)
+				result.MeanLatency = totalLatency / time.Duration(result.Successes)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// WriteCSV writes benchmark results as CSV, one row per scenario and planner, with times in milliseconds. The mean cost and
+// latency are left empty for planners which never succeeded.
+func WriteCSV(w io.Writer, results []*Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"scenario", "planner", "runs", "success_rate", "mean_cost", "mean_latency_ms", "max_latency_ms",
+	}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		meanCost, meanLatency := "", ""
+		if r.Successes > 0 {
+			meanCost = strconv.FormatFloat(r.MeanCost, 'f', 3, 64)
+			meanLatency = formatMilliseconds(r.MeanLatency)
+		}
+		if err := writer.Write([]string{
+			r.Scenario,
+			r.Planner,
+			strconv.Itoa(r.Runs),
+			strconv.FormatFloat(r.SuccessRate(), 'f', 3, 64),
+			meanCost,
+			meanLatency,
+			formatMilliseconds(r.MaxLatency),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatMilliseconds(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
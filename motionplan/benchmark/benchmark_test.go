@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+)
+
+func TestLoadScenarios(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	scenarios, err := LoadScenarios("scenarios")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, scenarios, test.ShouldHaveLength, 2)
+	for _, s := range scenarios {
+		fs, err := s.FrameSystem(logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.Frame(s.Frame), test.ShouldNotBeNil)
+		seedMap, err := s.SeedMap(fs)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, seedMap[s.Frame], test.ShouldHaveLength, len(fs.Frame(s.Frame).DoF()))
+		worldState, err := s.WorldState()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, worldState.Obstacles[0].Geometries(), test.ShouldHaveLength, len(s.Obstacles))
+		_, err = s.GoalPose()
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	dir := t.TempDir()
+	_, err = LoadScenarios(dir)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"frames": [{"id": "arm", "model_file": "missing.json"}]}`), 0o600),
+		test.ShouldBeNil)
+	s, err := LoadScenario(filepath.Join(dir, "bad.json"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.Name, test.ShouldEqual, "bad.json")
+	test.That(t, s.Frames[0].ModelFile, test.ShouldEqual, filepath.Join(dir, "missing.json"))
+	_, err = s.FrameSystem(logger)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestRun(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	s, err := LoadScenario(filepath.Join("scenarios", "base_around_box.json"))
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, func() { RegisterPlanner("cbirrt", nil) }, test.ShouldPanic)
+	RegisterPlanner("test_cbirrt_no_smoothing", map[string]interface{}{"planning_alg": "cbirrt", "smooth_iter": 0})
+	test.That(t, Planners(), test.ShouldContain, "test_cbirrt_no_smoothing")
+
+	_, err = Run(context.Background(), logger, []*Scenario{s}, []string{"cbirrt"}, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = Run(context.Background(), logger, []*Scenario{s}, []string{"missing"}, 1)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	results, err := Run(context.Background(), logger, []*Scenario{s}, []string{"cbirrt", "test_cbirrt_no_smoothing"}, 2)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, results, test.ShouldHaveLength, 2)
+	for _, result := range results {
+		test.That(t, result.Scenario, test.ShouldEqual, "base_around_box")
+		test.That(t, result.Runs, test.ShouldEqual, 2)
+		test.That(t, result.SuccessRate(), test.ShouldEqual, 1)
+		// the base must drive around the box, so further than the straight line to the goal
+		test.That(t, result.MeanCost, test.ShouldBeGreaterThan, 120)
+		test.That(t, result.MeanLatency, test.ShouldBeGreaterThan, 0)
+		test.That(t, result.MaxLatency, test.ShouldBeGreaterThanOrEqualTo, result.MeanLatency)
+	}
+
+	var buf bytes.Buffer
+	test.That(t, WriteCSV(&buf, append(results, &Result{Scenario: "never", Planner: "cbirrt", Runs: 1})), test.ShouldBeNil)
+	rows, err := csv.NewReader(&buf).ReadAll()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rows, test.ShouldHaveLength, 4)
+	test.That(t, rows[0], test.ShouldResemble,
+		[]string{"scenario", "planner", "runs", "success_rate", "mean_cost", "mean_latency_ms", "max_latency_ms"})
+	test.That(t, rows[1][:4], test.ShouldResemble, []string{"base_around_box", "cbirrt", "2", "1.000"})
+	test.That(t, rows[3][3:6], test.ShouldResemble, []string{"0.000", "", ""})
+}
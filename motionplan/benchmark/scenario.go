@@ -0,0 +1,178 @@
+package benchmark
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Scenario is a planning problem to benchmark planners on: a frame system, the obstacles around it, where it starts, and where one of
+// its frames is to be moved.
+type Scenario struct {
+	Name string `json:"name"`
+	// Frames of the frame system, which must connect to the world frame
+	Frames []FrameConfig `json:"frames"`
+	// Obstacles, in the world frame
+	Obstacles []spatialmath.GeometryConfig `json:"obstacles,omitempty"`
+	// Inputs of frames at the start, by frame name. Frames which are not listed start with all inputs at zero.
+	Start map[string][]float64 `json:"start,omitempty"`
+	// Name of the frame to move
+	Frame string     `json:"frame"`
+	Goal  GoalConfig `json:"goal"`
+	// Planning options used for every planner, below the options of the planner itself
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// FrameConfig is a frame of a scenario: a static frame placed relative to its parent, with a kinematic model if it moves. A model
+// is either given inline, or read from a kinematics JSON file, whose path is relative to the scenario file.
+type FrameConfig struct {
+	referenceframe.LinkConfig
+	Model     *referenceframe.ModelConfig `json:"model,omitempty"`
+	ModelFile string                      `json:"model_file,omitempty"`
+}
+
+// GoalConfig is where the frame of a scenario is to be moved: a pose relative to a parent frame, the world frame if none is given.
+type GoalConfig struct {
+	Parent      string                         `json:"parent,omitempty"`
+	Translation r3.Vector                      `json:"translation"`
+	Orientation *spatialmath.OrientationConfig `json:"orientation,omitempty"`
+}
+
+// LoadScenario reads a scenario from a JSON file. Its name defaults to the name of the file.
+func LoadScenario(filename string) (*Scenario, error) {
+	//nolint:gosec
+	scenarioBytes, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	s := &Scenario{}
+	if err := json.Unmarshal(scenarioBytes, s); err != nil {
+		return nil, errors.Wrapf(err, "cannot parse scenario file %q", filename)
+	}
+	if s.Name == "" {
+		s.Name = filepath.Base(filename)
+	}
+	for i, f := range s.Frames {
+		if f.ModelFile != "" && !filepath.IsAbs(f.ModelFile) {
+			s.Frames[i].ModelFile = filepath.Join(filepath.Dir(filename), f.ModelFile)
+		}
+	}
+	return s, nil
+}
+
+// LoadScenarios reads every scenario file with a .json extension in a directory, in order of file name.
+func LoadScenarios(dir string) ([]*Scenario, error) {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(filenames) == 0 {
+		return nil, errors.Errorf("no scenario files in %q", dir)
+	}
+	sort.Strings(filenames)
+	scenarios := make([]*Scenario, 0, len(filenames))
+	for _, filename := range filenames {
+		s, err := LoadScenario(filename)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// FrameSystem builds the frame system of the scenario.
+func (s *Scenario) FrameSystem(logger golog.Logger) (referenceframe.FrameSystem, error) {
+	parts := make(framesystemparts.Parts, 0, len(s.Frames))
+	for _, f := range s.Frames {
+		link := f.LinkConfig
+		if link.Parent == "" {
+			link.Parent = referenceframe.World
+		}
+		lif, err := link.ParseConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse frame %q", f.ID)
+		}
+		part := &referenceframe.FrameSystemPart{FrameConfig: lif}
+		switch {
+		case f.Model != nil && f.ModelFile != "":
+			return nil, errors.Errorf("frame %q cannot have both a model and a model_file", f.ID)
+		case f.Model != nil:
+			part.ModelFrame, err = f.Model.ParseConfig(f.ID)
+		case f.ModelFile != "":
+			part.ModelFrame, err = referenceframe.ParseModelJSONFile(f.ModelFile, f.ID)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot parse model of frame %q", f.ID)
+		}
+		parts = append(parts, part)
+	}
+	return framesystem.NewFrameSystemFromParts(s.Name, "", parts, logger)
+}
+
+// WorldState returns the obstacles of the scenario. Obstacles without a label are named by their place in the scenario.
+func (s *Scenario) WorldState() (*referenceframe.WorldState, error) {
+	geometries := make(map[string]spatialmath.Geometry, len(s.Obstacles))
+	for i, cfg := range s.Obstacles {
+		cfg := cfg
+		geometry, err := cfg.ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		name := geometry.Label()
+		if name == "" {
+			name = fmt.Sprintf("obstacle_%d", i)
+		}
+		if _, ok := geometries[name]; ok {
+			return nil, errors.Errorf("two obstacles are named %q", name)
+		}
+		geometries[name] = geometry
+	}
+	return &referenceframe.WorldState{
+		Obstacles: []*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, geometries)},
+	}, nil
+}
+
+// SeedMap returns the inputs of every frame of a frame system built by FrameSystem at the start of the scenario.
+func (s *Scenario) SeedMap(fs referenceframe.FrameSystem) (map[string][]referenceframe.Input, error) {
+	seedMap := referenceframe.StartPositions(fs)
+	for name, inputs := range s.Start {
+		f := fs.Frame(name)
+		if f == nil {
+			return nil, errors.Errorf("cannot start frame %q, which is not in the frame system", name)
+		}
+		if len(inputs) != len(f.DoF()) {
+			return nil, errors.Errorf("frame %q starts with %d inputs but has %d degrees of freedom", name, len(inputs), len(f.DoF()))
+		}
+		seedMap[name] = referenceframe.FloatsToInputs(inputs)
+	}
+	return seedMap, nil
+}
+
+// GoalPose returns where the frame of the scenario is to be moved.
+func (s *Scenario) GoalPose() (*referenceframe.PoseInFrame, error) {
+	parent := s.Goal.Parent
+	if parent == "" {
+		parent = referenceframe.World
+	}
+	orientation := spatialmath.Orientation(spatialmath.NewZeroOrientation())
+	if s.Goal.Orientation != nil {
+		var err error
+		orientation, err = s.Goal.Orientation.ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return referenceframe.NewPoseInFrame(parent, spatialmath.NewPose(s.Goal.Translation, orientation)), nil
+}
@@ -0,0 +1,104 @@
+package motionplan
+
+import (
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r3"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// boundingBox is an axis aligned box around a geometry. Boxes are quick to compare, so they are used to rule out collisions between
+// geometries that are far apart before measuring the distance between the geometries themselves.
+type boundingBox struct {
+	min, max r3.Vector
+}
+
+// newBoundingBox returns the box around the bounding sphere of a geometry.
+func newBoundingBox(g spatial.Geometry) boundingBox {
+	center := g.Pose().Point()
+	r := spatial.BoundingRadius(g)
+	extent := r3.Vector{X: r, Y: r, Z: r}
+	return boundingBox{min: center.Sub(extent), max: center.Add(extent)}
+}
+
+func (b boundingBox) union(other boundingBox) boundingBox {
+	return boundingBox{
+		min: r3.Vector{X: math.Min(b.min.X, other.min.X), Y: math.Min(b.min.Y, other.min.Y), Z: math.Min(b.min.Z, other.min.Z)},
+		max: r3.Vector{X: math.Max(b.max.X, other.max.X), Y: math.Max(b.max.Y, other.max.Y), Z: math.Max(b.max.Z, other.max.Z)},
+	}
+}
+
+func (b boundingBox) center() r3.Vector {
+	return b.min.Add(b.max).Mul(0.5)
+}
+
+// distance returns the distance between two boxes, which is never more than the distance between the geometries inside them.
+func (b boundingBox) distance(other boundingBox) float64 {
+	gap := func(minA, maxA, minB, maxB float64) float64 {
+		return math.Max(0, math.Max(minA-maxB, minB-maxA))
+	}
+	return r3.Vector{
+		X: gap(b.min.X, b.max.X, other.min.X, other.max.X),
+		Y: gap(b.min.Y, b.max.Y, other.min.Y, other.max.Y),
+		Z: gap(b.min.Z, b.max.Z, other.min.Z, other.max.Z),
+	}.Norm()
+}
+
+// aabbTree is a bounding volume hierarchy of named boxes. Each node bounds the boxes below it, so a query can skip every box under
+// a node that is too far away, finding the boxes near another one without comparing it to all of them.
+type aabbTree struct {
+	box         boundingBox
+	left, right *aabbTree
+	name        string // set on leaves only
+}
+
+// newAABBTree builds a tree of the given boxes by splitting them in half along the longest side of the box around them, until
+// every leaf holds a single box. It returns nil if there are no boxes.
+func newAABBTree(boxes map[string]boundingBox) *aabbTree {
+	leaves := make([]*aabbTree, 0, len(boxes))
+	for name, box := range boxes {
+		leaves = append(leaves, &aabbTree{box: box, name: name})
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].name < leaves[j].name
+	})
+	return buildAABBTree(leaves)
+}
+
+func buildAABBTree(leaves []*aabbTree) *aabbTree {
+	if len(leaves) == 0 {
+		return nil
+	}
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	box := leaves[0].box
+	for _, leaf := range leaves[1:] {
+		box = box.union(leaf.box)
+	}
+	size := box.max.Sub(box.min)
+	axis := func(v r3.Vector) float64 { return v.X }
+	if size.Y > size.X && size.Y >= size.Z {
+		axis = func(v r3.Vector) float64 { return v.Y }
+	} else if size.Z > size.X && size.Z > size.Y {
+		axis = func(v r3.Vector) float64 { return v.Z }
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		return axis(leaves[i].box.center()) < axis(leaves[j].box.center())
+	})
+	mid := len(leaves) / 2
+	return &aabbTree{box: box, left: buildAABBTree(leaves[:mid]), right: buildAABBTree(leaves[mid:])}
+}
+
+// near appends to names the name of every box in the tree that is no further than distance from box, and returns the result.
+func (t *aabbTree) near(box boundingBox, distance float64, names []string) []string {
+	if t == nil || t.box.distance(box) > distance {
+		return names
+	}
+	if t.left == nil {
+		return append(names, t.name)
+	}
+	return t.right.near(box, distance, t.left.near(box, distance, names))
+}
@@ -0,0 +1,99 @@
+package motionplan
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// randomBoxes returns n boxes of random sizes at random places within a cube of the given side, with its lowest corner at corner.
+func randomBoxes(t testing.TB, randseed *rand.Rand, n int, corner r3.Vector, side float64) map[string]spatial.Geometry {
+	t.Helper()
+	boxes := map[string]spatial.Geometry{}
+	for i := 0; i < n; i++ {
+		pt := corner.Add(r3.Vector{X: randseed.Float64() * side, Y: randseed.Float64() * side, Z: randseed.Float64() * side})
+		dims := r3.Vector{X: 10 + randseed.Float64()*100, Y: 10 + randseed.Float64()*100, Z: 10 + randseed.Float64()*100}
+		box, err := spatial.NewBox(spatial.NewPoseFromPoint(pt), dims, "")
+		test.That(t, err, test.ShouldBeNil)
+		boxes[fmt.Sprintf("box%d", i)] = box
+	}
+	return boxes
+}
+
+func TestAABBTree(t *testing.T) {
+	//nolint: gosec
+	randseed := rand.New(rand.NewSource(1))
+	boxes := boundingBoxes(randomBoxes(t, randseed, 50, r3.Vector{}, 1000))
+	tree := newAABBTree(boxes)
+	for _, query := range boundingBoxes(randomBoxes(t, randseed, 20, r3.Vector{}, 1000)) {
+		for _, distance := range []float64{0, 100} {
+			near := tree.near(query, distance, nil)
+			var expected []string
+			for name, box := range boxes {
+				if box.distance(query) <= distance {
+					expected = append(expected, name)
+				}
+			}
+			sort.Strings(near)
+			sort.Strings(expected)
+			test.That(t, near, test.ShouldResemble, expected)
+		}
+	}
+
+	var empty *aabbTree
+	test.That(t, empty.near(boundingBox{}, 100, nil), test.ShouldBeEmpty)
+	test.That(t, newAABBTree(nil), test.ShouldBeNil)
+}
+
+func TestPrunedCollisionDistances(t *testing.T) {
+	//nolint: gosec
+	randseed := rand.New(rand.NewSource(1))
+	x := randomBoxes(t, randseed, 10, r3.Vector{}, 1000)
+	y := map[string]spatial.Geometry{}
+	for name, geometry := range randomBoxes(t, randseed, 30, r3.Vector{}, 1000) {
+		y["obstacle"+name] = geometry
+	}
+	cg, err := newCollisionGraph(x, y, nil, true, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	for xName, xGeometry := range x {
+		for yName, yGeometry := range y {
+			expected, err := xGeometry.DistanceFrom(yGeometry)
+			test.That(t, err, test.ShouldBeNil)
+			distance, ok := cg.getDistance(xName, yName)
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, distance, test.ShouldBeLessThanOrEqualTo, expected+1e-9)
+			test.That(t, distance <= spatial.CollisionBuffer, test.ShouldEqual, expected <= spatial.CollisionBuffer)
+		}
+	}
+	boolCG, err := newCollisionGraph(x, y, nil, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(boolCG.collisions()) > 0, test.ShouldEqual, len(cg.collisions()) > 0)
+}
+
+func BenchmarkCollisionGraph(b *testing.B) {
+	m, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(b, err, test.ShouldBeNil)
+	gf, err := m.Geometries(make([]frame.Input, len(m.DoF())))
+	test.That(b, err, test.ShouldBeNil)
+	for _, n := range []int{10, 50, 200} {
+		// obstacles out of reach of the arm, as most configurations checked while planning are not in collision
+		//nolint: gosec
+		obstacles := randomBoxes(b, rand.New(rand.NewSource(1)), n, r3.Vector{X: 1000, Y: -1000, Z: -1000}, 2000)
+		for _, reportDistances := range []bool{false, true} {
+			b.Run(fmt.Sprintf("%d obstacles, reporting distances %t", n, reportDistances), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, err := newCollisionGraph(gf.Geometries(), obstacles, nil, reportDistances, defaultCollisionBufferMM)
+					test.That(b, err, test.ShouldBeNil)
+				}
+			})
+		}
+	}
+}
@@ -230,17 +230,17 @@ func (mp *cBiRRTMotionPlanner) rrtBackgroundRunner(
 		}
 
 		// sample near map 1 and switch which map is which to keep adding to them even
-		target = mp.sample(map1reached, i)
+		target = mp.sample(map1reached, i, seed, rrt.maps.optNode.Q())
 		map1, map2 = map2, map1
 	}
 	rrt.solutionChan <- &rrtPlanReturn{planerr: errPlannerFailed, maps: rrt.maps}
 }
 
-func (mp *cBiRRTMotionPlanner) sample(rSeed node, sampleNum int) []referenceframe.Input {
+func (mp *cBiRRTMotionPlanner) sample(rSeed node, sampleNum int, start, goal []referenceframe.Input) []referenceframe.Input {
 	// If we have done more than 50 iterations, start seeding off completely random positions 2 at a time
 	// The 2 at a time is to ensure random seeds are added onto both the seed and goal maps.
 	if sampleNum >= mp.algOpts.IterBeforeRand && sampleNum%4 >= 2 {
-		return referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+		return mp.drawSample(start, goal, math.Inf(1))
 	}
 	// Seeding nearby to valid points results in much faster convergence in less constrained space
 	q := referenceframe.RestrictedRandomFrameInputs(mp.frame, mp.randseed, 0.1)
@@ -0,0 +1,76 @@
+package motionplan
+
+import "sync"
+
+// lruCache holds up to size values by key, dropping the least recently used one to make room for a new one. It is safe for
+// concurrent use.
+type lruCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]interface{}
+	used    []string // keys from least to most recently used
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{size: size, entries: map[string]interface{}{}}
+}
+
+// get returns the value for key, if there is one.
+func (c *lruCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.entries[key]
+	if ok {
+		c.touch(key)
+	}
+	return value, ok
+}
+
+// put sets the value for key.
+func (c *lruCache) put(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(key, value)
+}
+
+// getOrAdd returns the value for key, setting it to newValue() if there is none.
+func (c *lruCache) getOrAdd(key string, newValue func() interface{}) interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value, ok := c.entries[key]; ok {
+		c.touch(key)
+		return value
+	}
+	value := newValue()
+	c.add(key, value)
+	return value
+}
+
+// clear drops every value.
+func (c *lruCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]interface{}{}
+	c.used = nil
+}
+
+// touch marks key as the most recently used. The cache must be locked.
+func (c *lruCache) touch(key string) {
+	for i, used := range c.used {
+		if used == key {
+			c.used = append(c.used[:i], c.used[i+1:]...)
+			break
+		}
+	}
+	c.used = append(c.used, key)
+}
+
+// add sets the value for key, dropping the least recently used value if the cache is full. The cache must be locked.
+func (c *lruCache) add(key string, value interface{}) {
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		delete(c.entries, c.used[0])
+		c.used = c.used[1:]
+	}
+	c.entries[key] = value
+	c.touch(key)
+}
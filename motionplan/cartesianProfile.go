@@ -0,0 +1,289 @@
+package motionplan
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// Number of bisection steps taken to invert the position of a Cartesian profile, or to find its peak speed.
+const cartesianProfileBisections = 60
+
+// CartesianLimits bound how fast the end effector moves along the straight line of a linear motion, e.g. for gluing or welding.
+// Limits are in mm/s, mm/s^2 and mm/s^3, and set by the `max_cartesian_velocity`, `max_cartesian_acceleration` and
+// `max_cartesian_jerk` planning options. Limits of zero do not bound the motion.
+type CartesianLimits struct {
+	MaxVelocity     float64 `json:"max_cartesian_velocity"`
+	MaxAcceleration float64 `json:"max_cartesian_acceleration"`
+	MaxJerk         float64 `json:"max_cartesian_jerk"`
+}
+
+func (limits CartesianLimits) isSet() bool {
+	return limits.MaxVelocity > 0 || limits.MaxAcceleration > 0 || limits.MaxJerk > 0
+}
+
+func (limits CartesianLimits) validate() error {
+	if limits.MaxVelocity < 0 || limits.MaxAcceleration < 0 || limits.MaxJerk < 0 {
+		return errors.New("cartesian limits cannot be negative")
+	}
+	return nil
+}
+
+// rampUp returns the durations of the phases of speeding up from rest to a speed as fast as the acceleration and jerk limits allow,
+// and the highest acceleration reached. Acceleration first rises at the jerk limit for tj, holds at the acceleration limit for tc,
+// then falls at the jerk limit for tj again. Slowing down to rest takes the same time.
+func (limits CartesianLimits) rampUp(speed float64) (tj, tc, peakAccel float64) {
+	accel, jerk := limits.MaxAcceleration, limits.MaxJerk
+	switch {
+	case accel <= 0 && jerk <= 0:
+		// the speed is reached instantly
+		return 0, 0, 0
+	case jerk <= 0:
+		return 0, speed / accel, accel
+	case accel <= 0 || speed <= accel*accel/jerk:
+		// the acceleration limit is not reached before the speed is
+		tj = math.Sqrt(speed / jerk)
+		return tj, 0, jerk * tj
+	default:
+		return accel / jerk, speed/accel - accel/jerk, accel
+	}
+}
+
+// rampLength returns the distance covered speeding up from rest to a speed, which is the same as that covered slowing down.
+func (limits CartesianLimits) rampLength(speed float64) float64 {
+	tj, tc, _ := limits.rampUp(speed)
+	return speed * (2*tj + tc) / 2
+}
+
+// cartesianProfile is the fastest way to move a distance from rest to rest within Cartesian limits: speeding up, cruising, then
+// slowing down, with jerk limited S-curves where there is a jerk limit.
+type cartesianProfile struct {
+	length, peakSpeed, duration float64
+	phases                      []cartesianPhase
+}
+
+// cartesianPhase is a part of a profile in which the jerk is constant.
+type cartesianPhase struct {
+	duration, startSpeed, startAccel, jerk float64
+}
+
+func newCartesianProfile(length float64, limits CartesianLimits) (*cartesianProfile, error) {
+	if err := limits.validate(); err != nil {
+		return nil, err
+	}
+	if !limits.isSet() {
+		return nil, errors.New("at least one cartesian limit must be set")
+	}
+	if length <= 0 {
+		return nil, errors.New("cartesian profile must have a positive length")
+	}
+
+	peak := limits.MaxVelocity
+	if peak <= 0 {
+		peak = math.Inf(1)
+	}
+	// if the profile is too short to reach the velocity limit, find the speed it can reach before it must slow down again
+	if 2*limits.rampLength(peak) > length {
+		lo, hi := 0., peak
+		if math.IsInf(hi, 1) {
+			hi = 1.
+			for 2*limits.rampLength(hi) < length {
+				hi *= 2
+			}
+		}
+		for i := 0; i < cartesianProfileBisections; i++ {
+			mid := (lo + hi) / 2
+			if 2*limits.rampLength(mid) > length {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		peak = lo
+	}
+
+	tj, tc, peakAccel := limits.rampUp(peak)
+	jerk := limits.MaxJerk
+	// speed gained while the acceleration rises or falls at the jerk limit
+	jerkSpeed := peakAccel * tj / 2
+	cruise := (length - 2*limits.rampLength(peak)) / peak
+	profile := &cartesianProfile{
+		length:    length,
+		peakSpeed: peak,
+		phases: []cartesianPhase{
+			{tj, 0, 0, jerk},
+			{tc, jerkSpeed, peakAccel, 0},
+			{tj, peak - jerkSpeed, peakAccel, -jerk},
+			{cruise, peak, 0, 0},
+			{tj, peak, 0, -jerk},
+			{tc, peak - jerkSpeed, -peakAccel, 0},
+			{tj, jerkSpeed, -peakAccel, jerk},
+		},
+	}
+	for _, ph := range profile.phases {
+		profile.duration += ph.duration
+	}
+	return profile, nil
+}
+
+// position returns how far along the profile it is at a time in seconds.
+func (p *cartesianProfile) position(t float64) float64 {
+	var pos float64
+	for _, ph := range p.phases {
+		if ph.duration <= 0 {
+			continue
+		}
+		dt := math.Min(t, ph.duration)
+		pos += ph.startSpeed*dt + ph.startAccel*dt*dt/2 + ph.jerk*dt*dt*dt/6
+		if t <= ph.duration {
+			break
+		}
+		t -= ph.duration
+	}
+	return math.Max(0, math.Min(p.length, pos))
+}
+
+// timeAt returns the time in seconds at which the profile is a distance along.
+func (p *cartesianProfile) timeAt(pos float64) float64 {
+	lo, hi := 0., p.duration
+	for i := 0; i < cartesianProfileBisections; i++ {
+		mid := (lo + hi) / 2
+		if p.position(mid) < pos {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi
+}
+
+// CartesianPathStepCount is PathStepCount for a linear motion within Cartesian limits, whose steps are evenly spaced in time rather
+// than in distance. Steps are no further apart than stepSize where the motion is fastest, so there are more of them than
+// PathStepCount gives, closer together where the motion speeds up and slows down.
+func CartesianPathStepCount(seedPos, goalPos spatialmath.Pose, stepSize float64, limits CartesianLimits) (int, error) {
+	_, numSteps, err := linearPathProfile(seedPos, goalPos, stepSize, limits, SeparateInterpolation)
+	return numSteps, err
+}
+
+// linearPathFractions returns how far along the straight line between two poses to place each intermediate goal of a linear
+// motion, as fractions of the whole line. Without Cartesian limits they are evenly spaced, as given by PathStepCount, or more closely
+// along the longer path of a screw motion.
+func linearPathFractions(
+	seedPos, goalPos spatialmath.Pose,
+	stepSize float64,
+	limits CartesianLimits,
+	interpolation string,
+) ([]float64, error) {
+	profile, numSteps, err := linearPathProfile(seedPos, goalPos, stepSize, limits, interpolation)
+	if err != nil {
+		return nil, err
+	}
+	fractions := make([]float64, 0, numSteps-1)
+	for i := 1; i < numSteps; i++ {
+		if profile == nil {
+			fractions = append(fractions, float64(i)/float64(numSteps))
+		} else {
+			fractions = append(fractions, profile.position(profile.duration*float64(i)/float64(numSteps))/profile.length)
+		}
+	}
+	return fractions, nil
+}
+
+// linearPathProfile returns the profile of the end effector moving between two poses within Cartesian limits, and the number of steps
+// to break the motion into. The end effector moves along the line between the poses, or along the helix of the screw motion between them
+// with ScrewInterpolation. The profile is nil if there are no limits, or the motion only rotates.
+func linearPathProfile(
+	seedPos, goalPos spatialmath.Pose,
+	stepSize float64,
+	limits CartesianLimits,
+	interpolation string,
+) (*cartesianProfile, int, error) {
+	if err := limits.validate(); err != nil {
+		return nil, 0, err
+	}
+	if stepSize == 0 {
+		stepSize = 1.
+	}
+	numSteps := PathStepCount(seedPos, goalPos, stepSize)
+	length := seedPos.Point().Distance(goalPos.Point())
+	if interpolation == ScrewInterpolation {
+		// the helix is longer than the line, and its steps must be no longer than stepSize either
+		length = spatialmath.ScrewPathLength(seedPos, goalPos)
+		numSteps = int(math.Max(float64(numSteps), float64(int(length/stepSize)+1)))
+	}
+	if !limits.isSet() || length <= 0 {
+		return nil, numSteps, nil
+	}
+	profile, err := newCartesianProfile(length, limits)
+	if err != nil {
+		return nil, 0, err
+	}
+	// the largest step is taken at the peak speed, and turns by as much of the rotation as it moves along the line
+	rotation := utils.RadToDeg(spatialmath.OrientationBetween(seedPos.Orientation(), goalPos.Orientation()).AxisAngles().Theta)
+	largestStep := profile.peakSpeed * profile.duration * math.Max(1, math.Abs(rotation)/length)
+	return profile, int(math.Max(math.Ceil(largestStep/stepSize), float64(numSteps))), nil
+}
+
+// TimeParameterizeLinearPath times the path of a frame planned with the linear motion profile so that its end effector moves along
+// the path within Cartesian limits, rather than within the dynamic limits of its inputs as TimeParameterize does. The velocities of
+// the inputs at each waypoint are estimated from those either side of it.
+func TimeParameterizeLinearPath(f referenceframe.Frame, path [][]referenceframe.Input, limits CartesianLimits) (Trajectory, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot time an empty path")
+	}
+	// drop repeated waypoints, which would be at the same time
+	waypoints := [][]float64{referenceframe.InputsToFloats(path[0])}
+	for _, step := range path {
+		q := referenceframe.InputsToFloats(step)
+		if distance(waypoints[len(waypoints)-1], q) > defaultEpsilon {
+			waypoints = append(waypoints, q)
+		}
+	}
+
+	// how far the end effector has moved by each waypoint
+	traveled := make([]float64, len(waypoints))
+	var last spatialmath.Pose
+	for i, q := range waypoints {
+		pose, err := f.Transform(referenceframe.FloatsToInputs(q))
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			traveled[i] = traveled[i-1] + last.Point().Distance(pose.Point())
+		}
+		last = pose
+	}
+	if traveled[len(traveled)-1] <= 0 {
+		return nil, errors.New("cannot time a path along which the end effector does not move")
+	}
+	profile, err := newCartesianProfile(traveled[len(traveled)-1], limits)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]float64, len(waypoints))
+	for i, pos := range traveled {
+		times[i] = profile.timeAt(pos)
+	}
+	times[len(times)-1] = profile.duration
+	traj := make(Trajectory, 0, len(waypoints))
+	for i, q := range waypoints {
+		velocities := make([]float64, len(q))
+		if i > 0 && i < len(waypoints)-1 && times[i+1] > times[i-1] {
+			for j := range q {
+				velocities[j] = (waypoints[i+1][j] - waypoints[i-1][j]) / (times[i+1] - times[i-1])
+			}
+		}
+		traj = append(traj, TimedWaypoint{
+			Time:       time.Duration(times[i] * float64(time.Second)),
+			Inputs:     referenceframe.FloatsToInputs(q),
+			Velocities: velocities,
+		})
+	}
+	return traj, nil
+}
@@ -0,0 +1,144 @@
+package motionplan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestCartesianProfile(t *testing.T) {
+	_, err := newCartesianProfile(100, CartesianLimits{})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = newCartesianProfile(100, CartesianLimits{MaxVelocity: -1, MaxAcceleration: 10})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	for _, tc := range []struct {
+		length float64
+		limits CartesianLimits
+	}{
+		// reaches every limit
+		{500, CartesianLimits{MaxVelocity: 100, MaxAcceleration: 200, MaxJerk: 1000}},
+		// too short to reach the velocity limit
+		{20, CartesianLimits{MaxVelocity: 100, MaxAcceleration: 200, MaxJerk: 1000}},
+		// trapezoidal, without a jerk limit
+		{500, CartesianLimits{MaxVelocity: 100, MaxAcceleration: 200}},
+		// only a jerk limit
+		{500, CartesianLimits{MaxJerk: 1000}},
+		// constant speed
+		{500, CartesianLimits{MaxVelocity: 100}},
+	} {
+		profile, err := newCartesianProfile(tc.length, tc.limits)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, profile.position(0), test.ShouldEqual, 0)
+		test.That(t, profile.position(profile.duration), test.ShouldAlmostEqual, tc.length, 1e-6)
+
+		// differentiate the profile numerically to check it stays within its limits
+		const dt = 1e-3
+		limit := func(l float64) float64 {
+			if l == 0 {
+				return math.Inf(1)
+			}
+			// allow for the error of differentiating numerically
+			return l * 1.01
+		}
+		lastPos, lastSpeed, lastAccel := 0., 0., 0.
+		for i := 1; float64(i)*dt <= profile.duration; i++ {
+			pos := profile.position(float64(i) * dt)
+			test.That(t, pos, test.ShouldBeGreaterThanOrEqualTo, lastPos)
+			speed := (pos - lastPos) / dt
+			accel := (speed - lastSpeed) / dt
+			test.That(t, speed, test.ShouldBeLessThanOrEqualTo, limit(tc.limits.MaxVelocity))
+			if i > 1 {
+				test.That(t, math.Abs(accel), test.ShouldBeLessThanOrEqualTo, limit(tc.limits.MaxAcceleration))
+			}
+			if i > 2 && tc.limits.MaxJerk > 0 {
+				test.That(t, math.Abs((accel-lastAccel)/dt), test.ShouldBeLessThanOrEqualTo, limit(tc.limits.MaxJerk))
+			}
+			lastPos, lastSpeed, lastAccel = pos, speed, accel
+		}
+		test.That(t, profile.timeAt(profile.position(profile.duration/3)), test.ShouldAlmostEqual, profile.duration/3, 1e-6)
+	}
+
+	// the profile which reaches every limit spends 0.7s speeding up over 35mm, 0.4s of it jerking, and as long slowing down
+	profile, err := newCartesianProfile(500, CartesianLimits{MaxVelocity: 100, MaxAcceleration: 200, MaxJerk: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, profile.peakSpeed, test.ShouldEqual, 100)
+	test.That(t, profile.duration, test.ShouldAlmostEqual, 0.7+4.3+0.7, 1e-9)
+}
+
+func TestLinearPathSteps(t *testing.T) {
+	seedPos := spatialmath.NewZeroPose()
+	goalPos := spatialmath.NewPoseFromPoint(r3.Vector{X: 500})
+	limits := CartesianLimits{MaxVelocity: 100, MaxAcceleration: 200, MaxJerk: 1000}
+
+	numSteps, err := CartesianPathStepCount(seedPos, goalPos, 10, CartesianLimits{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, numSteps, test.ShouldEqual, PathStepCount(seedPos, goalPos, 10))
+	numSteps, err = CartesianPathStepCount(seedPos, goalPos, 10, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, numSteps, test.ShouldBeGreaterThan, PathStepCount(seedPos, goalPos, 10))
+	_, err = CartesianPathStepCount(seedPos, goalPos, 10, CartesianLimits{MaxVelocity: -1})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// steps are evenly spaced in time, so closest together where the motion speeds up and slows down
+	fractions, err := linearPathFractions(seedPos, goalPos, 10, limits, SeparateInterpolation)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fractions, test.ShouldHaveLength, numSteps-1)
+	last := 0.
+	largest := 0.
+	for _, fraction := range fractions {
+		largest = math.Max(largest, fraction-last)
+		last = fraction
+	}
+	test.That(t, fractions[0], test.ShouldBeLessThan, largest)
+	test.That(t, 500*largest, test.ShouldBeLessThanOrEqualTo, 10+1e-9)
+
+	// steps of screw motions are no longer along the helix they follow, whose turns are longer than the line
+	goalPos = spatialmath.NewPose(r3.Vector{X: 500}, &spatialmath.R4AA{Theta: math.Pi / 2, RZ: 1})
+	for _, stepLimits := range []CartesianLimits{{}, limits} {
+		fractions, err = linearPathFractions(seedPos, goalPos, 10, stepLimits, ScrewInterpolation)
+		test.That(t, err, test.ShouldBeNil)
+		fractions = append(fractions, 1)
+		last := seedPos
+		for _, fraction := range fractions {
+			next := spatialmath.ScrewInterpolate(seedPos, goalPos, fraction)
+			test.That(t, spatialmath.ScrewPathLength(last, next), test.ShouldBeLessThanOrEqualTo, 10+1e-9)
+			last = next
+		}
+	}
+}
+
+func TestTimeParameterizeLinearPath(t *testing.T) {
+	limits := []frame.Limit{{Min: -1000, Max: 1000}, {Min: -1000, Max: 1000}}
+	model, err := frame.NewMobile2DFrame("base", limits, nil)
+	test.That(t, err, test.ShouldBeNil)
+	cartesianLimits := CartesianLimits{MaxVelocity: 100, MaxAcceleration: 200, MaxJerk: 1000}
+
+	_, err = TimeParameterizeLinearPath(model, nil, cartesianLimits)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = TimeParameterizeLinearPath(model, [][]frame.Input{frame.FloatsToInputs([]float64{1, 1})}, cartesianLimits)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	path := [][]frame.Input{}
+	for i := 0; i <= 50; i++ {
+		path = append(path, frame.FloatsToInputs([]float64{6 * float64(i), 8 * float64(i)}))
+	}
+	traj, err := TimeParameterizeLinearPath(model, path, cartesianLimits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, traj, test.ShouldHaveLength, len(path))
+	test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 5.7, 1e-6)
+	test.That(t, traj[0].Velocities, test.ShouldResemble, []float64{0, 0})
+	test.That(t, traj[len(traj)-1].Velocities, test.ShouldResemble, []float64{0, 0})
+	for i := 1; i < len(traj); i++ {
+		speed := 10 / (traj[i].Time - traj[i-1].Time).Seconds()
+		test.That(t, speed, test.ShouldBeLessThanOrEqualTo, 100*1.001)
+	}
+	// in the middle of the path, the end effector cruises at the velocity limit
+	_, velocities := traj.At(traj.Duration() / 2)
+	test.That(t, math.Hypot(velocities[0], velocities[1]), test.ShouldAlmostEqual, 100, 1e-3)
+}
@@ -2,7 +2,12 @@ package motionplan
 
 import (
 	"math"
+	"sort"
 
+	"github.com/edaniels/golog"
+	"gonum.org/v1/gonum/floats"
+
+	"go.viam.com/rdk/referenceframe"
 	spatial "go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/utils"
 )
@@ -14,6 +19,60 @@ type Collision struct {
 	penetrationDepth float64
 }
 
+// Names returns the names of the two geometries in collision.
+func (c Collision) Names() (string, string) {
+	return c.name1, c.name2
+}
+
+// PenetrationDepth returns how far one of the geometries would have to be moved to no longer be in collision with the other.
+func (c Collision) PenetrationDepth() float64 {
+	return math.Max(-c.penetrationDepth, 0)
+}
+
+// CheckCollisions returns the collisions between the geometries of the frames of a frame system at the given inputs, and between
+// those geometries and the obstacles of a world state. Frames that move and have no inputs are left out. Geometries of the same
+// frame, such as the links of an arm, are not checked against each other, as neighboring ones always touch.
+func CheckCollisions(
+	fs referenceframe.FrameSystem,
+	inputs map[string][]referenceframe.Input,
+	worldState *referenceframe.WorldState,
+) ([]Collision, error) {
+	worldState, err := worldState.ToWorldFrame(fs, inputs)
+	if err != nil {
+		return nil, err
+	}
+	// can use zeroth element of worldState.Obstacles because ToWorldFrame returns only one GeometriesInFrame
+	obstacles := worldState.Obstacles[0].Geometries()
+
+	frameGeometries, err := referenceframe.FrameSystemGeometries(fs, inputs, golog.Global())
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(frameGeometries))
+	for name := range frameGeometries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var collisions []Collision
+	for i, name := range names {
+		geometries := frameGeometries[name].Geometries()
+		cg, err := newCollisionGraph(geometries, obstacles, nil, true, defaultCollisionBufferMM)
+		if err != nil {
+			return nil, err
+		}
+		collisions = append(collisions, cg.collisions()...)
+		for _, otherName := range names[i+1:] {
+			cg, err := newCollisionGraph(geometries, frameGeometries[otherName].Geometries(), nil, true, defaultCollisionBufferMM)
+			if err != nil {
+				return nil, err
+			}
+			collisions = append(collisions, cg.collisions()...)
+		}
+	}
+	return collisions, nil
+}
+
 // collisionsAlmostEqual compares two Collisions and returns if they are almost equal.
 func collisionsAlmostEqual(c1, c2 Collision) bool {
 	return ((c1.name1 == c2.name1 && c1.name2 == c2.name2) || (c1.name1 == c2.name2 && c1.name2 == c2.name1)) &&
@@ -101,36 +160,76 @@ type collisionGraph struct {
 	//    - true:  all distances will be determined and numerically reported
 	//    - false: collisions will be reported as bools, not numerically. Upon finding a collision, will exit early
 	reportDistances bool
+
+	// collisionBufferMM is the distance at or below which two geometries are considered to be in collision
+	collisionBufferMM float64
 }
 
 // newCollisionGraph instantiates a collisionGraph object and checks for collisions between the x and y sets of geometries
 // collisions that are reported in the reference CollisionSystem argument will be ignored and not stored as edges in the graph.
 // if the set y is nil, the graph will be instantiated with y = x.
-func newCollisionGraph(x, y map[string]spatial.Geometry, reference *collisionGraph, reportDistances bool) (cg *collisionGraph, err error) {
+// pairs of geometries whose bounding boxes are too far apart to collide are not checked, and when reporting distances, the distance
+// between their bounding boxes is reported instead, which is never more than the distance between the geometries.
+// geometries closer together than collisionBufferMM are considered to be in collision, which gives a margin of safety around obstacles.
+func newCollisionGraph(
+	x, y map[string]spatial.Geometry,
+	reference *collisionGraph,
+	reportDistances bool,
+	collisionBufferMM float64,
+) (cg *collisionGraph, err error) {
 	if y == nil {
 		y = x
 	}
 	cg = &collisionGraph{
-		geometryGraph:   newGeometryGraph(x, y),
-		reportDistances: reportDistances,
+		geometryGraph:     newGeometryGraph(x, y),
+		reportDistances:   reportDistances,
+		collisionBufferMM: collisionBufferMM,
+	}
+
+	xBoxes := boundingBoxes(cg.x)
+	yBoxes := boundingBoxes(cg.y)
+	// when distances are not needed, pairs too far apart to collide are never visited: the smaller set of geometries is put in a
+	// tree, which is searched for the geometries near each of the larger set
+	outer, inner, outerBoxes, innerBoxes := cg.x, cg.y, xBoxes, yBoxes
+	swapped := len(cg.y) > len(cg.x)
+	if swapped {
+		outer, inner, outerBoxes, innerBoxes = cg.y, cg.x, yBoxes, xBoxes
+	}
+	candidates := make([]string, 0, len(inner))
+	for name := range inner {
+		candidates = append(candidates, name)
+	}
+	var tree *aabbTree
+	if !reportDistances {
+		tree = newAABBTree(innerBoxes)
 	}
 
 	var distance float64
-	for xName, xGeometry := range cg.x {
-		for yName, yGeometry := range cg.y {
+	for outerName := range outer {
+		if tree != nil {
+			candidates = tree.near(outerBoxes[outerName], collisionBufferMM, candidates[:0])
+		}
+		for _, innerName := range candidates {
+			xName, yName := outerName, innerName
+			if swapped {
+				xName, yName = innerName, outerName
+			}
+			xGeometry, yGeometry := cg.x[xName], cg.y[yName]
 			if _, ok := cg.getDistance(xName, yName); ok || xGeometry == yGeometry {
 				// geometry pair already has distance information associated with it, or is comparing with itself - skip to next pair
 				continue
 			}
-			if reference != nil && reference.collisionBetween(xName, yName) {
+			if reference != nil && reference.ignoresCollision(xName, yName) {
 				// represent previously seen collisions as NaNs
 				// per IEE standards, any comparison with NaN will return false, so these will never be considered collisions
 				distance = math.NaN()
+			} else if boxDistance := xBoxes[xName].distance(yBoxes[yName]); boxDistance > collisionBufferMM {
+				distance = boxDistance
 			} else if distance, err = cg.checkCollision(xGeometry, yGeometry); err != nil {
 				return nil, err
 			}
 			cg.setDistance(xName, yName, distance)
-			if !reportDistances && distance <= spatial.CollisionBuffer {
+			if !reportDistances && distance <= collisionBufferMM {
 				// collision found, can return early
 				return cg, nil
 			}
@@ -139,10 +238,20 @@ func newCollisionGraph(x, y map[string]spatial.Geometry, reference *collisionGra
 	return cg, nil
 }
 
+// boundingBoxes returns the bounding box of each of the given geometries.
+func boundingBoxes(geometries map[string]spatial.Geometry) map[string]boundingBox {
+	boxes := make(map[string]boundingBox, len(geometries))
+	for name, geometry := range geometries {
+		boxes[name] = newBoundingBox(geometry)
+	}
+	return boxes
+}
+
 // checkCollision takes a pair of geometries and returns the distance between them.
-// If this number is less than the CollisionBuffer they can be considered to be in collision.
+// If this number is less than the collision buffer of the graph they can be considered to be in collision.
 func (cg *collisionGraph) checkCollision(x, y spatial.Geometry) (float64, error) {
-	if cg.reportDistances {
+	// geometries can only be found to be within a buffer larger than the default one by measuring the distance between them
+	if cg.reportDistances || cg.collisionBufferMM > spatial.CollisionBuffer {
 		return x.DistanceFrom(y)
 	}
 	col, err := x.CollidesWith(y)
@@ -152,20 +261,12 @@ func (cg *collisionGraph) checkCollision(x, y spatial.Geometry) (float64, error)
 	return math.Inf(1), err
 }
 
-// collisionBetween returns a bool describing if the collisionGraph has a collision between the two entities that are specified by name.
-func (cg *collisionGraph) collisionBetween(name1, name2 string) bool {
-	if distance, ok := cg.getDistance(name1, name2); ok {
-		return distance <= spatial.CollisionBuffer
-	}
-	return false
-}
-
 // collisions returns a list of all the collisions present in the collisionGraph.
 func (cg *collisionGraph) collisions() []Collision {
 	var collisions []Collision
 	for xName, row := range cg.distances {
 		for yName, distance := range row {
-			if distance <= spatial.CollisionBuffer {
+			if distance <= cg.collisionBufferMM {
 				collisions = append(collisions, Collision{xName, yName, distance})
 				if !cg.reportDistances {
 					// collision found, can return early
@@ -177,7 +278,135 @@ func (cg *collisionGraph) collisions() []Collision {
 	return collisions
 }
 
-// ignoreCollision finds the specified collision and marks it as something never to check for or report.
+// ignoresCollision returns whether collisions between the two entities specified by name are not checked for by graphs built with this
+// one as their reference: those already in collision in it, and those marked with addCollisionSpecification.
+func (cg *collisionGraph) ignoresCollision(name1, name2 string) bool {
+	distance, ok := cg.getDistance(name1, name2)
+	return ok && (math.IsNaN(distance) || distance <= cg.collisionBufferMM)
+}
+
+// addCollisionSpecification finds the specified collision and marks it as something never to check for or report. Specifications naming
+// geometries which are not in the graph are skipped, as allowed collisions may be declared for geometries of other frames.
 func (cg *collisionGraph) addCollisionSpecification(specification *Collision) {
-	cg.setDistance(specification.name1, specification.name2, math.NaN())
+	_, xHas1 := cg.x[specification.name1]
+	_, yHas2 := cg.y[specification.name2]
+	_, xHas2 := cg.x[specification.name2]
+	_, yHas1 := cg.y[specification.name1]
+	switch {
+	case xHas1 && yHas2:
+		cg.setDistance(specification.name1, specification.name2, math.NaN())
+	case xHas2 && yHas1:
+		cg.setDistance(specification.name2, specification.name1, math.NaN())
+	}
+}
+
+// allowedCollisionSpecifications returns collision specifications for the pairs of geometries which the frames of a frame system
+// declare may touch, and those given.
+func allowedCollisionSpecifications(fs referenceframe.FrameSystem, allowed []referenceframe.AllowedCollision) []*Collision {
+	allowed = append(referenceframe.FrameSystemAllowedCollisions(fs), allowed...)
+	specifications := make([]*Collision, 0, len(allowed))
+	for _, pair := range allowed {
+		specifications = append(specifications, &Collision{name1: pair.Geometry1, name2: pair.Geometry2})
+	}
+	return specifications
+}
+
+// collisionState is a configuration of a frame, its geometries there, and their distances from each other and from obstacles.
+type collisionState struct {
+	inputs     []referenceframe.Input
+	geometries map[string]spatial.Geometry
+	graph      *collisionGraph
+}
+
+// continuousCollisionChecker checks the whole motion between two configurations for collisions, rather than only the
+// configurations themselves, so that a geometry cannot tunnel through a thin obstacle between them.
+//
+// It uses conservative advancement: no point of a geometry moves further between two configurations than its center does plus its
+// rotation times its bounding radius, so a pair of geometries cannot have collided between two configurations if the distances
+// between them at either end add up to more than they moved. This takes geometries to move steadily from one configuration to the
+// other, which holds well for the short motions between the steps of a path. Where it cannot be shown that a motion is clear, it
+// is split in half and each half is checked, down to motions too small to matter.
+type continuousCollisionChecker struct {
+	frame     referenceframe.Frame
+	obstacles map[string]spatial.Geometry
+	reference *collisionGraph
+	radii     map[string]float64
+}
+
+func newContinuousCollisionChecker(
+	frame referenceframe.Frame,
+	obstacles map[string]spatial.Geometry,
+	reference *collisionGraph,
+) *continuousCollisionChecker {
+	radii := map[string]float64{}
+	for name, geometry := range reference.x {
+		radii[name] = spatial.BoundingRadius(geometry)
+	}
+	return &continuousCollisionChecker{frame: frame, obstacles: obstacles, reference: reference, radii: radii}
+}
+
+// state returns the collision state of the frame at the given inputs.
+func (cc *continuousCollisionChecker) state(inputs []referenceframe.Input) (*collisionState, error) {
+	internal, err := cc.frame.Geometries(inputs)
+	if err != nil && internal == nil {
+		return nil, err
+	}
+	cg, err := newCollisionGraph(internal.Geometries(), cc.obstacles, cc.reference, true, cc.reference.collisionBufferMM)
+	if err != nil {
+		return nil, err
+	}
+	return &collisionState{inputs: inputs, geometries: internal.Geometries(), graph: cg}, nil
+}
+
+// motionIsClear returns whether the frame is never in collision moving from one state to another.
+func (cc *continuousCollisionChecker) motionIsClear(from, to *collisionState) (bool, error) {
+	if len(from.graph.collisions()) > 0 || len(to.graph.collisions()) > 0 {
+		return false, nil
+	}
+	if cc.clearanceCoversMotion(from, to) {
+		return true, nil
+	}
+	diff := make([]float64, 0, len(from.inputs))
+	for i, input := range from.inputs {
+		diff = append(diff, input.Value-to.inputs[i].Value)
+	}
+	if floats.Norm(diff, 2) < defaultEpsilon {
+		// both ends are clear and the motion is negligible
+		return true, nil
+	}
+	mid, err := cc.state(referenceframe.InterpolateInputs(from.inputs, to.inputs, 0.5))
+	if err != nil {
+		return false, err
+	}
+	if clear, err := cc.motionIsClear(from, mid); !clear || err != nil {
+		return false, err
+	}
+	return cc.motionIsClear(mid, to)
+}
+
+// clearanceCoversMotion returns whether every pair of geometries is further apart at the ends of a motion than they move relative
+// to each other during it.
+func (cc *continuousCollisionChecker) clearanceCoversMotion(from, to *collisionState) bool {
+	moved := map[string]float64{}
+	for name, geometry := range from.geometries {
+		end, ok := to.geometries[name]
+		if !ok {
+			return false
+		}
+		rotation := orientDist(geometry.Pose().Orientation(), end.Pose().Orientation())
+		moved[name] = geometry.Pose().Point().Distance(end.Pose().Point()) + rotation*cc.radii[name]
+	}
+	for xName, row := range from.graph.distances {
+		for yName, startDistance := range row {
+			if math.IsNaN(startDistance) {
+				// collisions that are allowed are never checked
+				continue
+			}
+			endDistance, ok := to.graph.getDistance(xName, yName)
+			if !ok || startDistance+endDistance <= moved[xName]+moved[yName]+2*cc.reference.collisionBufferMM {
+				return false
+			}
+		}
+	}
+	return true
 }
@@ -1,6 +1,7 @@
 package motionplan
 
 import (
+	"context"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -57,7 +58,7 @@ func TestCheckCollisions(t *testing.T) {
 	obstacles["obstacleCube000"] = bc1.Transform(spatial.NewZeroPose())
 	obstacles["obstacleCube444"] = bc1.Transform(spatial.NewPoseFromPoint(r3.Vector{4, 4, 4}))
 	obstacles["obstacleCube666"] = bc1.Transform(spatial.NewPoseFromPoint(r3.Vector{6, 6, 6}))
-	cg, err := newCollisionGraph(robot, obstacles, nil, true)
+	cg, err := newCollisionGraph(robot, obstacles, nil, true, defaultCollisionBufferMM)
 	test.That(t, err, test.ShouldBeNil)
 	expectedCollisions := []Collision{
 		{"robotCube333", "obstacleCube444", -1},
@@ -71,11 +72,53 @@ func TestCheckCollisions(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	gf, _ := m.Geometries(make([]frame.Input, len(m.DoF())))
 	test.That(t, gf, test.ShouldNotBeNil)
-	cg, err = newCollisionGraph(gf.Geometries(), gf.Geometries(), nil, true)
+	cg, err = newCollisionGraph(gf.Geometries(), gf.Geometries(), nil, true, defaultCollisionBufferMM)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(cg.collisions()), test.ShouldEqual, 4)
 }
 
+func TestCheckFrameSystemCollisions(t *testing.T) {
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	addBox := func(name string, pt r3.Vector) {
+		box, err := spatial.NewBox(spatial.NewPoseFromPoint(pt), r3.Vector{2, 2, 2}, "")
+		test.That(t, err, test.ShouldBeNil)
+		f, err := frame.NewStaticFrameWithGeometry(name, spatial.NewZeroPose(), box)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(f, fs.World()), test.ShouldBeNil)
+	}
+	addBox("a", r3.Vector{})
+	addBox("b", r3.Vector{1, 0, 0})
+	addBox("c", r3.Vector{10, 0, 0})
+
+	obstacle, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{10, 0, 1.5}), r3.Vector{2, 2, 2}, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatial.Geometry{"": obstacle})},
+	}
+
+	collisions, err := CheckCollisions(fs, frame.StartPositions(fs), worldState)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(collisions), test.ShouldEqual, 2)
+	depths := map[string]float64{}
+	for _, c := range collisions {
+		name1, name2 := c.Names()
+		depths[name1+" "+name2] = c.PenetrationDepth()
+	}
+	test.That(t, len(depths), test.ShouldEqual, 2)
+	for names, depth := range depths {
+		if names == "a b" || names == "b a" {
+			test.That(t, depth, test.ShouldAlmostEqual, 1)
+		} else {
+			test.That(t, names, test.ShouldContainSubstring, "c")
+			test.That(t, depth, test.ShouldAlmostEqual, 0.5)
+		}
+	}
+
+	collisions, err = CheckCollisions(fs, frame.StartPositions(fs), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(collisions), test.ShouldEqual, 1)
+}
+
 func TestUniqueCollisions(t *testing.T) {
 	m, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
 	test.That(t, err, test.ShouldBeNil)
@@ -84,14 +127,18 @@ func TestUniqueCollisions(t *testing.T) {
 	input := make([]frame.Input, len(m.DoF()))
 	internalGeometries, _ := m.Geometries(input)
 	test.That(t, internalGeometries, test.ShouldNotBeNil)
-	zeroPositionCG, err := newCollisionGraph(internalGeometries.Geometries(), internalGeometries.Geometries(), nil, true)
+	zeroPositionCG, err := newCollisionGraph(
+		internalGeometries.Geometries(), internalGeometries.Geometries(), nil, true, defaultCollisionBufferMM,
+	)
 	test.That(t, err, test.ShouldBeNil)
 
 	// case 1: no self collision - check no new collisions are returned
 	input[0] = frame.Input{Value: 1}
 	internalGeometries, _ = m.Geometries(input)
 	test.That(t, internalGeometries, test.ShouldNotBeNil)
-	cg, err := newCollisionGraph(internalGeometries.Geometries(), internalGeometries.Geometries(), zeroPositionCG, true)
+	cg, err := newCollisionGraph(
+		internalGeometries.Geometries(), internalGeometries.Geometries(), zeroPositionCG, true, defaultCollisionBufferMM,
+	)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, len(cg.collisions()), test.ShouldEqual, 0)
 
@@ -99,7 +146,9 @@ func TestUniqueCollisions(t *testing.T) {
 	input[4] = frame.Input{Value: 2}
 	internalGeometries, _ = m.Geometries(input)
 	test.That(t, internalGeometries, test.ShouldNotBeNil)
-	cg, err = newCollisionGraph(internalGeometries.Geometries(), internalGeometries.Geometries(), zeroPositionCG, true)
+	cg, err = newCollisionGraph(
+		internalGeometries.Geometries(), internalGeometries.Geometries(), zeroPositionCG, true, defaultCollisionBufferMM,
+	)
 	test.That(t, err, test.ShouldBeNil)
 	expectedCollisions := []Collision{{"xArm6:base_top", "xArm6:wrist_link", -41.6}, {"xArm6:wrist_link", "xArm6:upper_arm", -48.1}}
 	test.That(t, collisionListsAlmostEqual(cg.collisions(), expectedCollisions), test.ShouldBeTrue)
@@ -108,3 +157,80 @@ func TestUniqueCollisions(t *testing.T) {
 	cg.addCollisionSpecification(&expectedCollisions[1])
 	test.That(t, collisionListsAlmostEqual(cg.collisions(), expectedCollisions[:1]), test.ShouldBeTrue)
 }
+
+func TestAllowedCollisions(t *testing.T) {
+	apart := map[string]spatial.Geometry{}
+	touching := map[string]spatial.Geometry{}
+	for name, x := range map[string]float64{"a": 0, "b": 20} {
+		var err error
+		apart[name], err = spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: x}), r3.Vector{X: 10, Y: 10, Z: 10}, name)
+		test.That(t, err, test.ShouldBeNil)
+		touching[name], err = spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: x / 4}), r3.Vector{X: 10, Y: 10, Z: 10}, name)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	reference, err := newCollisionGraph(apart, nil, nil, true, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	// specifications of geometries not in the graph are skipped
+	reference.addCollisionSpecification(&Collision{name1: "a", name2: "missing"})
+	cg, err := newCollisionGraph(touching, nil, reference, true, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cg.collisions(), test.ShouldHaveLength, 1)
+
+	// geometries allowed to touch are not in collision when they do
+	reference.addCollisionSpecification(&Collision{name1: "b", name2: "a"})
+	cg, err = newCollisionGraph(touching, nil, reference, true, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cg.collisions(), test.ShouldBeEmpty)
+
+	// plans may pass through obstacles the frame is allowed to touch
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	curtain, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 10, Y: 220, Z: 10}, "curtain")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{
+		frame.NewGeometriesInFrame(frame.World, map[string]spatial.Geometry{"curtain": curtain}),
+	}}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-50, 0})}
+	goal := frame.NewPoseInFrame(frame.World, spatial.NewPoseFromPoint(r3.Vector{X: 50}))
+	newFS := func() frame.FrameSystem {
+		fs := frame.NewEmptySimpleFrameSystem("test")
+		test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+		return fs
+	}
+
+	_, err = PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, newFS(), worldState, map[string]interface{}{"timeout": 1})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	opts := map[string]interface{}{
+		"allowed_collisions": []interface{}{map[string]interface{}{"geometry1": "base", "geometry2": "curtain"}},
+	}
+	plan, err := PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, newFS(), worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+
+	// obstacles may also be named by their labels qualified by the frames they are given in
+	opts = map[string]interface{}{
+		"allowed_collisions": []interface{}{map[string]interface{}{"geometry1": "base", "geometry2": "world:curtain"}},
+	}
+	plan, err = PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, newFS(), worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+
+	// as may those declared by a part of the frame system
+	lif, err := (&frame.LinkConfig{
+		ID:                "marker",
+		Parent:            frame.World,
+		AllowedCollisions: []frame.AllowedCollision{{Geometry1: "curtain", Geometry2: "base"}},
+	}).ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	modelFrame, originFrame, err := frame.CreateFramesFromPart(&frame.FrameSystemPart{FrameConfig: lif}, logger.Sugar())
+	test.That(t, err, test.ShouldBeNil)
+	fs := newFS()
+	test.That(t, fs.AddFrame(originFrame, fs.World()), test.ShouldBeNil)
+	test.That(t, fs.AddFrame(modelFrame, originFrame), test.ShouldBeNil)
+	plan, err = PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+}
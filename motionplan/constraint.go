@@ -3,6 +3,7 @@ package motionplan
 import (
 	"errors"
 	"math"
+	"sync/atomic"
 
 	"github.com/golang/geo/r3"
 
@@ -30,7 +31,9 @@ type Constraint func(*ConstraintInput) (bool, float64)
 // constraintHandler is a convenient wrapper for constraint handling which is likely to be common among most motion
 // planners. Including a constraint handler as an anonymous struct member allows reuse.
 type constraintHandler struct {
-	constraints map[string]Constraint
+	checks             int64 // number of calls to CheckConstraints, kept first for alignment as it is accessed atomically
+	constraints        map[string]Constraint
+	segmentConstraints map[string]Constraint
 }
 
 // CheckConstraintPath will interpolate between two joint inputs and check that `true` is returned for all constraints
@@ -56,6 +59,9 @@ func (c *constraintHandler) CheckConstraintPath(ci *ConstraintInput, resolution
 			return false, nil
 		}
 		pass, _, _ := c.CheckConstraints(interpC)
+		if pass {
+			pass, _ = c.checkSegmentConstraints(interpC)
+		}
 		if !pass {
 			if i > 1 {
 				return false, &ConstraintInput{StartInput: lastGood, EndInput: interpC.StartInput}
@@ -94,17 +100,35 @@ func (c *constraintHandler) AddConstraint(name string, cons Constraint) {
 	}
 }
 
-// RemoveConstraint will remove the given constraint.
+// AddSegmentConstraint will add or overwrite a segment constraint with a given name. Rather than only the StartInput, a segment
+// constraint checks the whole motion from the StartInput to the EndInput, and is checked for each step of a path by
+// CheckConstraintPath.
+func (c *constraintHandler) AddSegmentConstraint(name string, cons Constraint) {
+	if c.segmentConstraints == nil {
+		c.segmentConstraints = map[string]Constraint{}
+	}
+	if cons != nil {
+		c.segmentConstraints[name] = cons
+	}
+}
+
+// RemoveConstraint will remove the given constraint, and the segment constraint of the same name.
 func (c *constraintHandler) RemoveConstraint(name string) {
 	delete(c.constraints, name)
+	delete(c.segmentConstraints, name)
 }
 
-// Constraints will list all constraints by name.
+// Constraints will list all constraints by name, including segment constraints.
 func (c *constraintHandler) Constraints() []string {
-	names := make([]string, 0, len(c.constraints))
+	names := make([]string, 0, len(c.constraints)+len(c.segmentConstraints))
 	for name := range c.constraints {
 		names = append(names, name)
 	}
+	for name := range c.segmentConstraints {
+		if _, ok := c.constraints[name]; !ok {
+			names = append(names, name)
+		}
+	}
 	return names
 }
 
@@ -114,6 +138,7 @@ func (c *constraintHandler) Constraints() []string {
 // -- if passing, a score representing the distance to a non-passing state. Inf(1) if failing.
 // -- if failing, a string naming the failed constraint.
 func (c *constraintHandler) CheckConstraints(cInput *ConstraintInput) (bool, float64, string) {
+	atomic.AddInt64(&c.checks, 1)
 	score := 0.
 
 	for name, cFunc := range c.constraints {
@@ -126,50 +151,104 @@ func (c *constraintHandler) CheckConstraints(cInput *ConstraintInput) (bool, flo
 	return true, score, ""
 }
 
+// checkCount returns the number of times CheckConstraints has been called.
+func (c *constraintHandler) checkCount() int {
+	return int(atomic.LoadInt64(&c.checks))
+}
+
+// checkSegmentConstraints will check the motion between the StartInput and the EndInput of a given input against all segment
+// constraints, returning whether all passed and if not, the name of the failed constraint.
+func (c *constraintHandler) checkSegmentConstraints(cInput *ConstraintInput) (bool, string) {
+	for name, cFunc := range c.segmentConstraints {
+		if pass, _ := cFunc(cInput); !pass {
+			return false, name
+		}
+	}
+	return true, ""
+}
+
 // newSelfCollisionConstraint creates a constraint that will be violated if geometries constituting the given frame ever come
 // into collision with themselves outside of the collisions present for the observationInput.
 // Collisions specified as collisionSpecifications will also be ignored
 // if reportDistances is false, this check will be done as fast as possible, if true maximum information will be available for debugging.
+// if continuous is true, the constraint checks the whole motion between its StartInput and EndInput, see newCollisionConstraint.
+// geometries closer together than collisionBufferMM are considered to be in collision.
 func newSelfCollisionConstraint(
 	frame referenceframe.Frame,
 	observationInput map[string][]referenceframe.Input,
 	collisionSpecifications []*Collision,
 	reportDistances bool,
+	continuous bool,
+	collisionBufferMM float64,
 ) (Constraint, error) {
-	return newCollisionConstraint(frame, nil, observationInput, collisionSpecifications, reportDistances)
+	return newCollisionConstraint(frame, nil, observationInput, collisionSpecifications, reportDistances, continuous, collisionBufferMM)
 }
 
 // newObstacleConstraint creates a constraint that will be violated if geometries constituting the given frame ever come
 // into collision with worldState geometries outside of the collisions present for the observationInput.
 // Collisions specified as collisionSpecifications will also be ignored
 // if reportDistances is false, this check will be done as fast as possible, if true maximum information will be available for debugging.
+// if continuous is true, the constraint checks the whole motion between its StartInput and EndInput, see newCollisionConstraint.
+// geometries closer to obstacles than collisionBufferMM are considered to be in collision with them.
 func newObstacleConstraint(frame referenceframe.Frame,
 	fs referenceframe.FrameSystem,
 	worldState *referenceframe.WorldState,
 	observationInput map[string][]referenceframe.Input,
 	collisionSpecifications []*Collision,
 	reportDistances bool,
+	continuous bool,
+	collisionBufferMM float64,
 ) (Constraint, error) {
 	// TODO(rb) it is bad practice to assume that the current inputs of the robot correspond to the passed in world state
 	// the state that observed the worldState should ultimately be included as part of the worldState message
+	obstacleNames := worldState.ObstacleWorldFrameNames()
 	worldState, err := worldState.ToWorldFrame(fs, observationInput)
 	if err != nil {
 		return nil, err
 	}
 	// can use zeroth element of worldState.Obstacles because ToWorldFrame returns only one GeometriesInFrame
-	return newCollisionConstraint(frame, worldState.Obstacles[0].Geometries(), observationInput, collisionSpecifications, reportDistances)
+	return newCollisionConstraint(
+		frame,
+		worldState.Obstacles[0].Geometries(),
+		observationInput,
+		obstacleCollisionSpecifications(collisionSpecifications, obstacleNames),
+		reportDistances,
+		continuous,
+		collisionBufferMM,
+	)
+}
+
+// obstacleCollisionSpecifications returns collision specifications naming obstacles as ToWorldFrame names them, so that specifications
+// naming obstacles by their labels, or by their labels qualified by the frames they are given in, match them.
+func obstacleCollisionSpecifications(specifications []*Collision, obstacleNames map[string][]string) []*Collision {
+	expanded := make([]*Collision, 0, len(specifications))
+	for _, specification := range specifications {
+		expanded = append(expanded, specification)
+		for _, name := range obstacleNames[specification.name1] {
+			expanded = append(expanded, &Collision{name1: name, name2: specification.name2})
+		}
+		for _, name := range obstacleNames[specification.name2] {
+			expanded = append(expanded, &Collision{name1: specification.name1, name2: name})
+		}
+	}
+	return expanded
 }
 
 // newCollisionConstraint is the most general method to create a collision constraint, which ill be violated if geometries constituting
 // the given frame ever come into collision with obstacle geometries outside of the collisions present for the observationInput.
 // Collisions specified as collisionSpecifications will also be ignored
 // if reportDistances is false, this check will be done as fast as possible, if true maximum information will be available for debugging.
+// if continuous is true, the constraint checks the whole motion between its StartInput and EndInput rather than only its StartInput, so
+// that it catches collisions a path would pass through between its steps. It is meant to be added as a segment constraint.
+// geometries closer together than collisionBufferMM are considered to be in collision, giving a margin of safety around them.
 func newCollisionConstraint(
 	frame referenceframe.Frame,
 	obstacles map[string]spatial.Geometry,
 	observationInput map[string][]referenceframe.Input,
 	collisionSpecifications []*Collision,
 	reportDistances bool,
+	continuous bool,
+	collisionBufferMM float64,
 ) (Constraint, error) {
 	// extract inputs corresponding to the frame
 	var goodInputs []referenceframe.Input
@@ -191,7 +270,7 @@ func newCollisionConstraint(
 	}
 
 	// create the reference collisionGraph
-	zeroCG, err := newCollisionGraph(zeroVols.Geometries(), obstacles, nil, true)
+	zeroCG, err := newCollisionGraph(zeroVols.Geometries(), obstacles, nil, true, collisionBufferMM)
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +278,23 @@ func newCollisionConstraint(
 		zeroCG.addCollisionSpecification(specification)
 	}
 
+	if continuous {
+		checker := newContinuousCollisionChecker(frame, obstacles, zeroCG)
+		constraint := func(cInput *ConstraintInput) (bool, float64) {
+			from, err := checker.state(cInput.StartInput)
+			if err != nil {
+				return false, 0
+			}
+			to, err := checker.state(cInput.EndInput)
+			if err != nil {
+				return false, 0
+			}
+			clear, err := checker.motionIsClear(from, to)
+			return clear && err == nil, 0
+		}
+		return constraint, nil
+	}
+
 	// create constraint from reference collision graph
 	constraint := func(cInput *ConstraintInput) (bool, float64) {
 		internal, err := cInput.Frame.Geometries(cInput.StartInput)
@@ -206,7 +302,7 @@ func newCollisionConstraint(
 			return false, 0
 		}
 
-		cg, err := newCollisionGraph(internal.Geometries(), obstacles, zeroCG, reportDistances)
+		cg, err := newCollisionGraph(internal.Geometries(), obstacles, zeroCG, reportDistances, collisionBufferMM)
 		if err != nil {
 			return false, 0
 		}
@@ -317,6 +413,85 @@ func NewSlerpOrientationConstraint(start, goal spatial.Pose, tolerance float64)
 	return validFunc, gradFunc
 }
 
+// NewUprightConstraint is used to hold the orientation vector of a pose within a cone around a fixed axis for a whole path, e.g. to keep
+// a cup of liquid carried by an arm from spilling. Unlike NewSlerpOrientationConstraint, the valid orientations do not depend on the
+// start and goal of the motion, and rotation about the axis is unconstrained. tolerance is the half angle of the cone in radians.
+// It returns the constraint and a distance function which will bring a pose into the valid constraint space.
+func NewUprightConstraint(axis r3.Vector, tolerance float64) (Constraint, Metric) {
+	ov := &spatial.OrientationVector{OX: axis.X, OY: axis.Y, OZ: axis.Z}
+	ov.Normalize()
+	dFunc := orientDistToRegion(ov, tolerance)
+
+	gradFunc := func(from, _ spatial.Pose) float64 {
+		oDist := dFunc(from.Orientation())
+		return oDist * oDist
+	}
+
+	validFunc := func(cInput *ConstraintInput) (bool, float64) {
+		err := resolveInputsToPositions(cInput)
+		if err != nil {
+			return false, 0
+		}
+		dist := gradFunc(cInput.StartPos, cInput.EndPos)
+		if dist < defaultEpsilon*defaultEpsilon {
+			return true, 0
+		}
+		return false, dist
+	}
+
+	return validFunc, gradFunc
+}
+
+// NewWorkspaceConstraint returns a constraint which will be violated if the named frame of a frame system ever leaves the given
+// workspace geometry, which is in the world frame, e.g. to keep an arm near a wall or inside of a glass enclosure. If the frame has
+// geometries they must all stay inside of the workspace, otherwise its origin must.
+func NewWorkspaceConstraint(fs referenceframe.FrameSystem, frameName string, workspace spatial.Geometry) (Constraint, error) {
+	f := fs.Frame(frameName)
+	if f == nil {
+		return nil, referenceframe.NewFrameMissingError(frameName)
+	}
+	inWorkspace := func(solveFrame referenceframe.Frame, inputs []referenceframe.Input) bool {
+		inputMap := map[string][]referenceframe.Input{solveFrame.Name(): inputs}
+		if sf, ok := solveFrame.(*solverFrame); ok {
+			inputMap = sf.sliceToMap(inputs)
+		}
+		frameInputs, err := referenceframe.GetFrameInputs(f, inputMap)
+		if err != nil {
+			return false
+		}
+		var geometries map[string]spatial.Geometry
+		if gf, _ := f.Geometries(frameInputs); gf != nil && len(gf.Geometries()) > 0 {
+			tf, err := fs.Transform(inputMap, gf, referenceframe.World)
+			if err != nil {
+				return false
+			}
+			geometries = tf.(*referenceframe.GeometriesInFrame).Geometries()
+		} else {
+			tf, err := fs.Transform(inputMap, referenceframe.NewPoseInFrame(frameName, spatial.NewZeroPose()), referenceframe.World)
+			if err != nil {
+				return false
+			}
+			geometries = map[string]spatial.Geometry{frameName: spatial.NewPoint(tf.(*referenceframe.PoseInFrame).Pose().Point(), frameName)}
+		}
+		for _, geometry := range geometries {
+			if inside, err := geometry.EncompassedBy(workspace); err != nil || !inside {
+				return false
+			}
+		}
+		return true
+	}
+
+	return func(cInput *ConstraintInput) (bool, float64) {
+		if cInput.StartInput != nil && !inWorkspace(cInput.Frame, cInput.StartInput) {
+			return false, 0
+		}
+		if cInput.EndInput != nil && !inWorkspace(cInput.Frame, cInput.EndInput) {
+			return false, 0
+		}
+		return true, 0
+	}, nil
+}
+
 // NewPlaneConstraint is used to define a constraint space for a plane, and will return 1) a constraint
 // function which will determine whether a point is on the plane and in a valid orientation, and 2) a distance function
 // which will bring a pose into the valid constraint space. The plane normal is assumed to point towards the valid area.
@@ -206,10 +206,12 @@ func TestCollisionConstraints(t *testing.T) {
 	err = fs.AddFrame(model, fs.Frame(frame.World))
 	test.That(t, err, test.ShouldBeNil)
 	handler := &constraintHandler{}
-	selfCollisionConstraint, err := newSelfCollisionConstraint(model, frame.StartPositions(fs), nil, true)
+	selfCollisionConstraint, err := newSelfCollisionConstraint(model, frame.StartPositions(fs), nil, true, false, defaultCollisionBufferMM)
 	test.That(t, err, test.ShouldBeNil)
 	handler.AddConstraint(defaultSelfCollisionConstraintName, selfCollisionConstraint)
-	obstacleConstraint, err := newObstacleConstraint(model, fs, worldState, frame.StartPositions(fs), nil, true)
+	obstacleConstraint, err := newObstacleConstraint(
+		model, fs, worldState, frame.StartPositions(fs), nil, true, false, defaultCollisionBufferMM,
+	)
 	test.That(t, err, test.ShouldBeNil)
 	handler.AddConstraint(defaultObstacleConstraintName, obstacleConstraint)
 
@@ -223,6 +225,102 @@ func TestCollisionConstraints(t *testing.T) {
 	}
 }
 
+func TestCollisionBuffer(t *testing.T) {
+	wall, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{20, 0, 0}), r3.Vector{10, 100, 100}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+	obstacles := map[string]spatial.Geometry{"wall": wall}
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, obstacles)}}
+
+	robotGeometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{10, 10, 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("robot", []frame.Limit{{-100, 100}, {-100, 100}}, robotGeometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	seedMap := map[string][]frame.Input{"robot": frame.FloatsToInputs([]float64{-20, 0})}
+
+	// 7mm from the wall, and 4mm from it
+	far := &ConstraintInput{StartInput: frame.FloatsToInputs([]float64{3, 0}), Frame: model}
+	near := &ConstraintInput{StartInput: frame.FloatsToInputs([]float64{6, 0}), Frame: model}
+	for _, reportDistances := range []bool{true, false} {
+		constraint, err := newObstacleConstraint(model, fs, worldState, seedMap, nil, reportDistances, false, defaultCollisionBufferMM)
+		test.That(t, err, test.ShouldBeNil)
+		ok, _ := constraint(far)
+		test.That(t, ok, test.ShouldBeTrue)
+		ok, _ = constraint(near)
+		test.That(t, ok, test.ShouldBeTrue)
+
+		constraint, err = newObstacleConstraint(model, fs, worldState, seedMap, nil, reportDistances, false, 5)
+		test.That(t, err, test.ShouldBeNil)
+		ok, _ = constraint(far)
+		test.That(t, ok, test.ShouldBeTrue)
+		ok, _ = constraint(near)
+		test.That(t, ok, test.ShouldBeFalse)
+	}
+
+	// starting within the buffer of an obstacle does not stop the robot from moving away from it
+	seedMap["robot"] = near.StartInput
+	constraint, err := newObstacleConstraint(model, fs, worldState, seedMap, nil, false, false, 5)
+	test.That(t, err, test.ShouldBeNil)
+	ok, _ := constraint(near)
+	test.That(t, ok, test.ShouldBeTrue)
+}
+
+func TestContinuousCollisionConstraint(t *testing.T) {
+	// a thin wall, which a robot crossing it in one large step would pass through
+	wall, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{1, 100, 100}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+	obstacles := map[string]spatial.Geometry{"wall": wall}
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, obstacles)}}
+
+	robotGeometry, err := spatial.NewSphere(spatial.NewZeroPose(), 2, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("robot", []frame.Limit{{-100, 100}, {-100, 100}}, robotGeometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	err = fs.AddFrame(model, fs.Frame(frame.World))
+	test.That(t, err, test.ShouldBeNil)
+	seedMap := map[string][]frame.Input{"robot": frame.FloatsToInputs([]float64{-20, 0})}
+
+	handler := &constraintHandler{}
+	obstacleConstraint, err := newObstacleConstraint(model, fs, worldState, seedMap, nil, false, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	handler.AddConstraint(defaultObstacleConstraintName, obstacleConstraint)
+
+	through := &ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{-20, 0}),
+		EndInput:   frame.FloatsToInputs([]float64{20, 0}),
+		Frame:      model,
+	}
+	around := &ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{-20, 55}),
+		EndInput:   frame.FloatsToInputs([]float64{20, 51}),
+		Frame:      model,
+	}
+	ok, _ := handler.CheckConstraintPath(through, 50)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	continuousConstraint, err := newObstacleConstraint(model, fs, worldState, seedMap, nil, false, true, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	handler.AddSegmentConstraint(defaultObstacleConstraintName, continuousConstraint)
+	test.That(t, len(handler.Constraints()), test.ShouldEqual, 1)
+
+	through.StartPos, through.EndPos = nil, nil
+	ok, _ = handler.CheckConstraintPath(through, 50)
+	test.That(t, ok, test.ShouldBeFalse)
+	ok, _ = handler.CheckConstraintPath(around, 50)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// passing the corner of the wall closer than the robot's radius is a collision
+	around.StartInput = frame.FloatsToInputs([]float64{-20, 50})
+	around.StartPos, around.EndPos = nil, nil
+	ok, _ = handler.CheckConstraintPath(around, 50)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	handler.RemoveConstraint(defaultObstacleConstraintName)
+	test.That(t, handler.Constraints(), test.ShouldBeEmpty)
+}
+
 var bt bool
 
 func BenchmarkCollisionConstraints(b *testing.B) {
@@ -241,10 +339,12 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 	err = fs.AddFrame(model, fs.Frame(frame.World))
 	test.That(b, err, test.ShouldBeNil)
 	handler := &constraintHandler{}
-	selfCollisionConstraint, err := newSelfCollisionConstraint(model, frame.StartPositions(fs), nil, false)
+	selfCollisionConstraint, err := newSelfCollisionConstraint(model, frame.StartPositions(fs), nil, false, false, defaultCollisionBufferMM)
 	test.That(b, err, test.ShouldBeNil)
 	handler.AddConstraint(defaultSelfCollisionConstraintName, selfCollisionConstraint)
-	obstacleConstraint, err := newObstacleConstraint(model, fs, worldState, frame.StartPositions(fs), nil, false)
+	obstacleConstraint, err := newObstacleConstraint(
+		model, fs, worldState, frame.StartPositions(fs), nil, false, false, defaultCollisionBufferMM,
+	)
 	test.That(b, err, test.ShouldBeNil)
 	handler.AddConstraint(defaultObstacleConstraintName, obstacleConstraint)
 	rseed := rand.New(rand.NewSource(1))
@@ -258,3 +358,136 @@ func BenchmarkCollisionConstraints(b *testing.B) {
 	}
 	bt = b1
 }
+
+func TestUprightConstraint(t *testing.T) {
+	// a frame which tilts its orientation vector away from the z axis
+	tilt, err := frame.NewRotationalFrame("tilt", spatial.R4AA{RX: 1}, frame.Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	constraint, metric := NewUprightConstraint(r3.Vector{Z: 2}, 0.1)
+	handler := &constraintHandler{}
+	handler.AddConstraint("upright", constraint)
+
+	// tilting within the tolerance is fine, however the frame is rotated about the axis
+	ok, _ := handler.CheckConstraintPath(&ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{-0.09}),
+		EndInput:   frame.FloatsToInputs([]float64{0.09}),
+		Frame:      tilt,
+	}, defaultResolution)
+	test.That(t, ok, test.ShouldBeTrue)
+	spun := spatial.NewPose(r3.Vector{X: 100}, &spatial.OrientationVector{OZ: 1, Theta: 2})
+	test.That(t, metric(spun, nil), test.ShouldEqual, 0)
+
+	// tilting any further is not, even if the end of the motion is upright again
+	ok, lastGood := handler.CheckConstraintPath(&ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{0}),
+		EndInput:   frame.FloatsToInputs([]float64{0.5}),
+		Frame:      tilt,
+	}, defaultResolution)
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, lastGood, test.ShouldNotBeNil)
+	ok, _ = constraint(&ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{0.5}),
+		EndInput:   frame.FloatsToInputs([]float64{0}),
+		Frame:      tilt,
+	})
+	test.That(t, ok, test.ShouldBeFalse)
+	tilted, err := tilt.Transform(frame.FloatsToInputs([]float64{0.5}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, metric(tilted, nil), test.ShouldAlmostEqual, 0.4*0.4, 1e-6)
+
+	// the upright motion profile holds the orientation the frame starts with unless given an axis
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(tilt, fs.World()), test.ShouldBeNil)
+	cupGeometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "cup")
+	test.That(t, err, test.ShouldBeNil)
+	cup, err := frame.NewStaticFrameWithGeometry("cup", spatial.NewPoseFromPoint(r3.Vector{Z: 100}), cupGeometry)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(cup, tilt), test.ShouldBeNil)
+	sFrames, err := fs.TracebackFrame(cup)
+	test.That(t, err, test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	opt, err := pm.plannerSetupFromMoveRequest(
+		tilted, spatial.NewZeroPose(), seedMap, nil, map[string]interface{}{"motion_profile": UprightMotionProfile},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.Constraints(), test.ShouldContain, defaultUprightConstraintName)
+	test.That(t, opt.pathDist(tilted, nil), test.ShouldEqual, 0)
+	test.That(t, opt.pathDist(spatial.NewZeroPose(), nil), test.ShouldBeGreaterThan, 0)
+	opt, err = pm.plannerSetupFromMoveRequest(
+		tilted, spatial.NewZeroPose(), seedMap, nil,
+		map[string]interface{}{"motion_profile": UprightMotionProfile, "upright_axis": []interface{}{0, 0, 1}, "tolerance": 0.5},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.pathDist(tilted, nil), test.ShouldEqual, 0)
+	_, err = pm.plannerSetupFromMoveRequest(
+		tilted, spatial.NewZeroPose(), seedMap, nil,
+		map[string]interface{}{"motion_profile": UprightMotionProfile, "upright_axis": []interface{}{0, 1}},
+	)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestWorkspaceConstraint(t *testing.T) {
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	baseGeometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "base")
+	test.That(t, err, test.ShouldBeNil)
+	base, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -200, Max: 200}, {Min: -200, Max: 200}}, baseGeometry)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(base, fs.World()), test.ShouldBeNil)
+	marker, err := frame.NewStaticFrame("marker", spatial.NewPoseFromPoint(r3.Vector{X: -10}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(marker, base), test.ShouldBeNil)
+	workspace, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 200, Y: 200, Z: 100}, "")
+	test.That(t, err, test.ShouldBeNil)
+
+	// the geometry of the base has to stay entirely inside of the workspace
+	constraint, err := NewWorkspaceConstraint(fs, "base", workspace)
+	test.That(t, err, test.ShouldBeNil)
+	ok, _ := constraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{0, 0}), Frame: base})
+	test.That(t, ok, test.ShouldBeTrue)
+	ok, _ = constraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{94, 0}), Frame: base})
+	test.That(t, ok, test.ShouldBeTrue)
+	ok, _ = constraint(&ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{0, 0}),
+		EndInput:   frame.FloatsToInputs([]float64{96, 0}),
+		Frame:      base,
+	})
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// a frame without geometries only has to keep its origin inside
+	constraint, err = NewWorkspaceConstraint(fs, "marker", workspace)
+	test.That(t, err, test.ShouldBeNil)
+	ok, _ = constraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{105, 0}), Frame: base})
+	test.That(t, ok, test.ShouldBeTrue)
+	ok, _ = constraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{-95, 0}), Frame: base})
+	test.That(t, ok, test.ShouldBeFalse)
+	_, err = NewWorkspaceConstraint(fs, "missing", workspace)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// plans stay inside of a workspace given in the planning options
+	planningOpts := map[string]interface{}{
+		"planning_alg": "cbirrt",
+		"workspace":    map[string]interface{}{"type": "box", "x": 200, "y": 200, "z": 100},
+	}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-50, 0})}
+	plan, err := PlanMotion(
+		context.Background(),
+		logger.Sugar(),
+		frame.NewPoseInFrame(frame.World, spatial.NewPoseFromPoint(r3.Vector{X: 50, Y: 50})),
+		base,
+		seedMap,
+		fs,
+		nil,
+		planningOpts,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	constraint, err = NewWorkspaceConstraint(fs, "base", workspace)
+	test.That(t, err, test.ShouldBeNil)
+	for _, step := range plan {
+		ok, _ = constraint(&ConstraintInput{StartInput: step["base"], Frame: base})
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+}
@@ -119,12 +119,12 @@ func (mp *DubinsRRTMotionPlanner) planRunner(
 
 		var target node
 		//nolint:gosec
-		if (rand.Float64() > 1-goalRate) || i == 0 {
+		if (mp.randseed.Float64() > 1-goalRate) || i == 0 {
 			target = goalConfig
 		} else {
 			inputDubins := referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
 			//nolint:gosec
-			inputDubins = append(inputDubins, referenceframe.Input{Value: rand.Float64() * 2 * math.Pi})
+			inputDubins = append(inputDubins, referenceframe.Input{Value: mp.randseed.Float64() * 2 * math.Pi})
 			target = &basicNode{q: inputDubins}
 		}
 
@@ -36,7 +36,9 @@ func TestDubinsRRT(t *testing.T) {
 
 	testDubin := func(worldState *frame.WorldState) bool {
 		opt := newBasicPlannerOptions()
-		collisionConstraint, err := newObstacleConstraint(dubins.Frame(), fs, worldState, frame.StartPositions(fs), nil, true)
+		collisionConstraint, err := newObstacleConstraint(
+			dubins.Frame(), fs, worldState, frame.StartPositions(fs), nil, true, false, defaultCollisionBufferMM,
+		)
 		if err != nil {
 			return false
 		}
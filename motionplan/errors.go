@@ -6,6 +6,10 @@ import (
 )
 
 var (
+	// ErrPartialPlan is returned alongside the steps planned so far when planning with the return_partial_plan option times out
+	// before reaching the goal. The steps lead from the start towards the goal, and planning may be resumed from their end.
+	ErrPartialPlan = errors.New("motion planner timed out before reaching the goal, returning a partial plan")
+
 	errIKSolve = errors.New("zero IK solutions produced, goal positions appears to be physically unreachable")
 
 	errPlannerFailed = errors.New("motion planner failed to find path")
@@ -15,10 +19,24 @@ var (
 	errIKConstraint = "all IK solutions failed constraints. Failures: "
 )
 
-func genIKConstraintErr(failures map[string]int, constraintFailCnt int) error {
+// IKConstraintError is returned when every IK solution found for a goal fails the constraints of a plan, e.g. by colliding with
+// obstacles in a cluttered scene. It records how many solutions were found and how many failed each constraint, so that callers can
+// tell why the goal could not be planned to.
+type IKConstraintError struct {
+	// Number of IK solutions found, none of which met the constraints
+	Solutions int
+	// Number of solutions which failed each constraint, by the name of the constraint
+	Failures map[string]int
+}
+
+func (e *IKConstraintError) Error() string {
 	ikConstraintFailures := errIKConstraint
-	for failName, count := range failures {
-		ikConstraintFailures += fmt.Sprintf("{ %s: %.2f%% }, ", failName, 100*float64(count)/float64(constraintFailCnt))
+	for failName, count := range e.Failures {
+		ikConstraintFailures += fmt.Sprintf("{ %s: %.2f%% }, ", failName, 100*float64(count)/float64(e.Solutions))
 	}
-	return errors.New(ikConstraintFailures)
+	return ikConstraintFailures
+}
+
+func genIKConstraintErr(failures map[string]int, constraintFailCnt int) error {
+	return &IKConstraintError{Solutions: constraintFailCnt, Failures: failures}
 }
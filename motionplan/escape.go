@@ -0,0 +1,165 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// most descent steps taken to move a frame out of collision before giving up.
+	escapeMaxIter = 200
+
+	// size in input units of the first descent step taken to move a frame out of collision.
+	escapeInitialStep = 0.1
+
+	// number of bisections made to shorten the last step out of collision.
+	escapeBisections = 20
+)
+
+// escapeCollision returns a path moving the frame out of collision with the obstacles of worldState, starting from its inputs in
+// seedMap, or nil if it is not in collision there. Collision constraints ignore collisions already present at the start of a motion,
+// so without escaping first a plan starting in collision could carry on through the obstacle it is in.
+//
+// The path descends the total depth the geometries of the frame penetrate obstacles by, as measured with getCollisionDepth, so that it
+// takes the frame out the shortest way it can, and ends once the frame is clear of every obstacle by more than collisionBufferMM.
+// Only obstacles are escaped, not collisions of the frame with itself, which may be between parts that always touch.
+func (pm *planManager) escapeCollision(
+	ctx context.Context,
+	seedMap map[string][]referenceframe.Input,
+	worldState *referenceframe.WorldState,
+	collisionBufferMM float64,
+) ([][]referenceframe.Input, error) {
+	seed, err := pm.frame.mapToSlice(seedMap)
+	if err != nil {
+		return nil, err
+	}
+	worldState, err = worldState.ToWorldFrame(pm.fs, seedMap)
+	if err != nil {
+		return nil, err
+	}
+	// can use zeroth element of worldState.Obstacles because ToWorldFrame returns only one GeometriesInFrame
+	obstacles := worldState.Obstacles[0].Geometries()
+	limits := pm.frame.DoF()
+
+	depth := func(q []float64) (float64, error) {
+		internal, err := pm.frame.Geometries(referenceframe.FloatsToInputs(q))
+		if err != nil && internal == nil {
+			return 0, err
+		}
+		cg, err := newCollisionGraph(internal.Geometries(), obstacles, nil, true, collisionBufferMM)
+		if err != nil {
+			return 0, err
+		}
+		total := 0.
+		for _, collision := range cg.collisions() {
+			total += collisionBufferMM - collision.penetrationDepth
+		}
+		return total, nil
+	}
+
+	q := referenceframe.InputsToFloats(seed)
+	current, err := depth(q)
+	if err != nil {
+		return nil, err
+	}
+	if current == 0 {
+		return nil, nil
+	}
+	pm.logger.Debugf("frame starts in collision, escaping %f mm of penetration", current)
+
+	path := [][]referenceframe.Input{seed}
+	stepSize := escapeInitialStep
+	for iter := 0; iter < escapeMaxIter; iter++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		// the penetration depth is only known at points, so its gradient is found by central differences
+		gradient := make([]float64, len(q))
+		norm := 0.
+		for j := range q {
+			probe := append([]float64{}, q...)
+			probe[j] = q[j] + defaultEpsilon
+			above, err := depth(probe)
+			if err != nil {
+				return nil, err
+			}
+			probe[j] = q[j] - defaultEpsilon
+			below, err := depth(probe)
+			if err != nil {
+				return nil, err
+			}
+			gradient[j] = (above - below) / (2 * defaultEpsilon)
+			norm += gradient[j] * gradient[j]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			return nil, errors.New("cannot escape from collision, no motion of the frame lessens it")
+		}
+
+		// backtrack until a step along the gradient lessens the penetration
+		for {
+			candidate := make([]float64, len(q))
+			for j := range q {
+				candidate[j] = math.Max(limits[j].Min, math.Min(limits[j].Max, q[j]-stepSize*gradient[j]/norm))
+			}
+			candidateDepth, err := depth(candidate)
+			if err != nil {
+				return nil, err
+			}
+			if candidateDepth == 0 {
+				// the step may go well past the edge of the obstacle, so it is shortened to end just clear of it
+				candidate, err = shortestClearStep(q, candidate, depth)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if candidateDepth < current {
+				q, current = candidate, candidateDepth
+				path = append(path, referenceframe.FloatsToInputs(q))
+				stepSize *= 1.5
+				break
+			}
+			stepSize /= 2
+			if stepSize < defaultEpsilon {
+				return nil, errors.New("cannot escape from collision, the frame is stuck")
+			}
+		}
+		if current == 0 {
+			return path, nil
+		}
+	}
+	return nil, errors.Errorf("cannot escape from collision in %d steps, %f mm of penetration remain", escapeMaxIter, current)
+}
+
+// shortestClearStep returns the nearest point to from, along the step from it to a point to which is clear of collision, that is still
+// clear of collision, found by bisection.
+func shortestClearStep(from, to []float64, depth func([]float64) (float64, error)) ([]float64, error) {
+	lo, hi := 0., 1.
+	at := func(frac float64) []float64 {
+		q := make([]float64, len(from))
+		for j := range q {
+			q[j] = from[j] + frac*(to[j]-from[j])
+		}
+		return q
+	}
+	for i := 0; i < escapeBisections; i++ {
+		mid := (lo + hi) / 2
+		midDepth, err := depth(at(mid))
+		if err != nil {
+			return nil, err
+		}
+		if midDepth == 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return at(hi), nil
+}
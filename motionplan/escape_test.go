@@ -0,0 +1,64 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestEscapeCollision(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 20, Y: 20, Z: 40}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	// the base has been jogged into the obstacle, less deeply along x than along y
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{3, 1})}
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+
+	escape, err := pm.escapeCollision(context.Background(), seedMap, worldState, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(escape), test.ShouldBeGreaterThan, 1)
+	test.That(t, escape[0], test.ShouldResemble, seedMap["base"])
+	// it leaves the obstacle the shortest way out, which is along x
+	end := frame.InputsToFloats(escape[len(escape)-1])
+	test.That(t, end[0], test.ShouldAlmostEqual, 15, 1)
+	test.That(t, end[1], test.ShouldAlmostEqual, 1, 1)
+	clearMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-50, 0})}
+	collisionConstraint, err := newObstacleConstraint(model, fs, worldState, clearMap, nil, false, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	ok, _ := collisionConstraint(&ConstraintInput{StartInput: escape[len(escape)-1], Frame: model})
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// frames which are not in collision have nothing to escape
+	escape, err = pm.escapeCollision(context.Background(), clearMap, worldState, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, escape, test.ShouldBeNil)
+
+	// the plan escapes before making the motion asked for
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 60, Y: 60}))
+	opts := map[string]interface{}{"escape_collision": true, "rseed": 1}
+	plan, err := PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, plan[0]["base"], test.ShouldResemble, seedMap["base"])
+	last := frame.InputsToFloats(plan[len(plan)-1]["base"])
+	test.That(t, last[0], test.ShouldAlmostEqual, 60, 1e-2)
+	test.That(t, last[1], test.ShouldAlmostEqual, 60, 1e-2)
+}
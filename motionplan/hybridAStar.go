@@ -0,0 +1,358 @@
+package motionplan
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// The tightest circle the base can turn in, in mm.
+	defaultTurningRadius = 500.
+
+	// The distance driven by each motion of the search, in mm.
+	defaultHybridAStarStepLength = 100.
+
+	// The size of the square cells the search divides the plane into, in mm.
+	defaultHybridAStarCellSize = 50.
+
+	// The number of headings the search divides a full turn into.
+	defaultHeadingBins = 72
+
+	// How many times more driving in reverse costs than driving forward.
+	defaultReversePenalty = 2.
+
+	// The distance between the places the motion of the base is checked for collisions, in mm.
+	defaultCollisionCheckDistance = 10.
+
+	// The number of motions the search may expand before giving up.
+	defaultHybridAStarMaxExpansions = 100000
+
+	// The number of expansions between attempts to drive straight to the goal along a Dubins path.
+	defaultAnalyticExpansionInterval = 10
+
+	// How close the end of a Dubins path must be to the goal, in mm and radians, to be used.
+	dubinsGoalTolerance = 1e-3
+)
+
+type hybridAStarOptions struct {
+	// The tightest circle the base can turn in, in mm
+	TurningRadius float64 `json:"turning_radius"`
+
+	// The distance driven by each motion of the search, in mm
+	StepLength float64 `json:"step_length"`
+
+	// The size of the square cells the search divides the plane into, in mm. Only the cheapest way to reach each heading in
+	// each cell is explored further
+	CellSize float64 `json:"cell_size"`
+
+	// The number of headings the search divides a full turn into
+	HeadingBins int `json:"heading_bins"`
+
+	// Whether the base may drive in reverse
+	AllowReverse bool `json:"allow_reverse"`
+
+	// How many times more driving in reverse costs than driving forward
+	ReversePenalty float64 `json:"reverse_penalty"`
+
+	// The distance between the places the motion of the base is checked for collisions, in mm
+	CollisionCheckDistance float64 `json:"collision_check_distance"`
+
+	// The number of motions the search may expand before giving up
+	MaxExpansions int `json:"max_expansions"`
+}
+
+// newHybridAStarOptions creates a struct controlling the running of a single invocation of the algorithm.
+// All values are pre-set to reasonable defaults, but can be tweaked if needed.
+func newHybridAStarOptions(planOpts *plannerOptions) (*hybridAStarOptions, error) {
+	algOpts := &hybridAStarOptions{
+		TurningRadius:          defaultTurningRadius,
+		StepLength:             defaultHybridAStarStepLength,
+		CellSize:               defaultHybridAStarCellSize,
+		HeadingBins:            defaultHeadingBins,
+		AllowReverse:           true,
+		ReversePenalty:         defaultReversePenalty,
+		CollisionCheckDistance: defaultCollisionCheckDistance,
+		MaxExpansions:          defaultHybridAStarMaxExpansions,
+	}
+	// convert map to json
+	jsonString, err := json.Marshal(planOpts.extra)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(jsonString, algOpts)
+	if err != nil {
+		return nil, err
+	}
+	if algOpts.TurningRadius <= 0 || algOpts.StepLength <= 0 || algOpts.CellSize <= 0 || algOpts.CollisionCheckDistance <= 0 {
+		return nil, errors.New("turning_radius, step_length, cell_size and collision_check_distance must be greater than 0")
+	}
+	if algOpts.HeadingBins <= 0 {
+		return nil, errors.New("heading_bins must be greater than 0")
+	}
+	if algOpts.ReversePenalty < 1 {
+		return nil, errors.New("reverse_penalty must be at least 1")
+	}
+	return algOpts, nil
+}
+
+// hybridAStarMotionPlanner plans drivable paths for bases that cannot move sideways and have a minimum turning radius, such as
+// wheeled bases and cars, which the straight lines between configurations that other planners produce do not give. It plans for
+// frames whose inputs are an x and y position and a heading in radians, such as a mobile 2D frame with three limits.
+//
+// It uses Hybrid A*, Dolgov et al 2008: an A* search over a grid of positions and headings, expanded by driving forward and in
+// reverse while turning as tightly as possible in either direction or going straight. The search keeps the exact pose reached in
+// each cell, so every path it finds can be driven, and it regularly tries to finish with a Dubins path straight to the goal.
+// https://ai.stanford.edu/~ddolgov/papers/dolgov_gpp_stair08.pdf
+type hybridAStarMotionPlanner struct {
+	*planner
+	algOpts *hybridAStarOptions
+}
+
+// newHybridAStarMotionPlanner creates a hybridAStarMotionPlanner object.
+func newHybridAStarMotionPlanner(
+	frame referenceframe.Frame,
+	seed *rand.Rand,
+	logger golog.Logger,
+	opt *plannerOptions,
+) (motionPlanner, error) {
+	if len(frame.DoF()) != 3 {
+		return nil, errors.Errorf("hybrid A* plans for frames with an x, y and heading input, not %d inputs", len(frame.DoF()))
+	}
+	if opt == nil {
+		opt = newBasicPlannerOptions()
+	}
+	mp, err := newPlanner(frame, seed, logger, opt)
+	if err != nil {
+		return nil, err
+	}
+	algOpts, err := newHybridAStarOptions(opt)
+	if err != nil {
+		return nil, err
+	}
+	return &hybridAStarMotionPlanner{mp, algOpts}, nil
+}
+
+func (mp *hybridAStarMotionPlanner) plan(ctx context.Context,
+	goal spatialmath.Pose,
+	seed []referenceframe.Input,
+) ([][]referenceframe.Input, error) {
+	if len(seed) != 3 {
+		return nil, errors.Errorf("hybrid A* needs a seed of an x, y and heading input, not %d inputs", len(seed))
+	}
+	start := pose2D{seed[0].Value, seed[1].Value, normalizeAngle(seed[2].Value)}
+	end := pose2D{goal.Point().X, goal.Point().Y, goal.Orientation().EulerAngles().Yaw}
+	if !mp.checkInputs(start.inputs()) {
+		return nil, errors.New("cannot plan, the base is in collision where it is")
+	}
+	if !mp.checkInputs(end.inputs()) {
+		return nil, errors.New("cannot plan, the goal is in collision")
+	}
+
+	queue := &hybridAStarQueue{{node: &hybridAStarNode{pose: start}, priority: start.distance(end)}}
+	expanded := map[hybridAStarCell]float64{}
+	for expansions := 0; queue.Len() > 0 && expansions < mp.algOpts.MaxExpansions; {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		current := heap.Pop(queue).(hybridAStarQueueItem).node
+		cell := mp.cell(current.pose)
+		if cost, ok := expanded[cell]; ok && cost <= current.cost {
+			// a cheaper way to this cell was already expanded
+			continue
+		}
+		expanded[cell] = current.cost
+		if expansions%defaultAnalyticExpansionInterval == 0 {
+			if shot := mp.dubinsShot(current.pose, end); shot != nil {
+				mp.logger.Debugf("hybrid A* found a path after %d expansions", expansions)
+				path := current.path()
+				for _, p := range shot {
+					path = append(path, p.inputs())
+				}
+				return path, nil
+			}
+		}
+		expansions++
+
+		directions := []float64{1}
+		if mp.algOpts.AllowReverse {
+			directions = append(directions, -1)
+		}
+		for _, direction := range directions {
+			for _, curvature := range []float64{1 / mp.algOpts.TurningRadius, 0, -1 / mp.algOpts.TurningRadius} {
+				next, ok := mp.drive(current.pose, curvature, direction*mp.algOpts.StepLength)
+				if !ok {
+					continue
+				}
+				cost := current.cost + mp.algOpts.StepLength
+				if direction < 0 {
+					cost += mp.algOpts.StepLength * (mp.algOpts.ReversePenalty - 1)
+				}
+				if expandedCost, ok := expanded[mp.cell(next)]; ok && expandedCost <= cost {
+					continue
+				}
+				heap.Push(queue, hybridAStarQueueItem{
+					node:     &hybridAStarNode{pose: next, cost: cost, parent: current},
+					priority: cost + next.distance(end),
+				})
+			}
+		}
+	}
+	return nil, errPlannerFailed
+}
+
+// drive returns where the base ends up after driving distance along an arc of the given curvature from p, with positive curvatures
+// turning left and negative distances driving in reverse. It returns false if the base collides with anything along the way.
+func (mp *hybridAStarMotionPlanner) drive(p pose2D, curvature, distance float64) (pose2D, bool) {
+	steps := int(math.Ceil(math.Abs(distance) / mp.algOpts.CollisionCheckDistance))
+	for i := 1; i <= steps; i++ {
+		if !mp.checkInputs(p.drive(curvature, distance*float64(i)/float64(steps)).inputs()) {
+			return pose2D{}, false
+		}
+	}
+	return p.drive(curvature, distance), true
+}
+
+// dubinsShot returns the poses along the shortest collision free Dubins path from p to goal, a step length apart, or nil if there
+// is none. This lets the search finish exactly at the goal, rather than wherever its motions happen to lead.
+func (mp *hybridAStarMotionPlanner) dubinsShot(p, goal pose2D) []pose2D {
+	d := &Dubins{Radius: mp.algOpts.TurningRadius, PointSeparation: mp.algOpts.StepLength}
+	for _, path := range d.AllPaths([]float64{p.x, p.y, p.theta}, []float64{goal.x, goal.y, goal.theta}, true) {
+		if math.IsInf(path.TotalLen, 1) {
+			break
+		}
+		if shot, ok := mp.driveDubinsPath(p, path); ok && shot[len(shot)-1].distance(goal) < dubinsGoalTolerance &&
+			math.Abs(normalizeAngle(shot[len(shot)-1].theta-goal.theta)) < dubinsGoalTolerance {
+			shot[len(shot)-1] = goal
+			return shot
+		}
+	}
+	return nil
+}
+
+// driveDubinsPath returns the poses along a Dubins path from p, a step length apart, and false if the base collides along it.
+func (mp *hybridAStarMotionPlanner) driveDubinsPath(p pose2D, path DubinPathAttr) ([]pose2D, bool) {
+	// each of the two turns is a signed angle, positive to the left, and the middle is either a straight length or a turn the
+	// other way from the first
+	first := path.DubinsPath[0]
+	turnLeft := 1 / mp.algOpts.TurningRadius
+	turnFirst := math.Copysign(turnLeft, first)
+	middle := dubinsSegment{curvature: 0, length: path.DubinsPath[2]}
+	if !path.Straight {
+		middle = dubinsSegment{curvature: -turnFirst, length: path.DubinsPath[2] * mp.algOpts.TurningRadius}
+	}
+	segments := []dubinsSegment{
+		{curvature: turnFirst, length: math.Abs(first) * mp.algOpts.TurningRadius},
+		middle,
+		{curvature: math.Copysign(turnLeft, path.DubinsPath[1]), length: math.Abs(path.DubinsPath[1]) * mp.algOpts.TurningRadius},
+	}
+
+	var poses []pose2D
+	for _, segment := range segments {
+		for remaining := segment.length; remaining > defaultEpsilon; remaining -= mp.algOpts.StepLength {
+			next, ok := mp.drive(p, segment.curvature, math.Min(remaining, mp.algOpts.StepLength))
+			if !ok {
+				return nil, false
+			}
+			poses = append(poses, next)
+			p = next
+		}
+	}
+	return poses, len(poses) > 0
+}
+
+// cell returns the cell of the search grid that p is in.
+func (mp *hybridAStarMotionPlanner) cell(p pose2D) hybridAStarCell {
+	binSize := 2 * math.Pi / float64(mp.algOpts.HeadingBins)
+	return hybridAStarCell{
+		x:       int(math.Floor(p.x / mp.algOpts.CellSize)),
+		y:       int(math.Floor(p.y / mp.algOpts.CellSize)),
+		heading: int(math.Floor((p.theta+math.Pi)/binSize)) % mp.algOpts.HeadingBins,
+	}
+}
+
+// pose2D is the position and heading of a base on the plane.
+type pose2D struct {
+	x, y, theta float64
+}
+
+func (p pose2D) inputs() []referenceframe.Input {
+	return referenceframe.FloatsToInputs([]float64{p.x, p.y, p.theta})
+}
+
+func (p pose2D) distance(other pose2D) float64 {
+	return math.Hypot(other.x-p.x, other.y-p.y)
+}
+
+// drive returns where a base at p ends up after driving distance along an arc of the given curvature, with positive curvatures
+// turning left and negative distances driving in reverse.
+func (p pose2D) drive(curvature, distance float64) pose2D {
+	if curvature == 0 {
+		return pose2D{p.x + distance*math.Cos(p.theta), p.y + distance*math.Sin(p.theta), p.theta}
+	}
+	turn := curvature * distance
+	return pose2D{
+		x:     p.x + (math.Sin(p.theta+turn)-math.Sin(p.theta))/curvature,
+		y:     p.y + (math.Cos(p.theta)-math.Cos(p.theta+turn))/curvature,
+		theta: normalizeAngle(p.theta + turn),
+	}
+}
+
+// normalizeAngle returns the angle in the range [-pi, pi] pointing the same way as theta.
+func normalizeAngle(theta float64) float64 {
+	return math.Remainder(theta, 2*math.Pi)
+}
+
+type dubinsSegment struct {
+	curvature, length float64
+}
+
+type hybridAStarCell struct {
+	x, y, heading int
+}
+
+type hybridAStarNode struct {
+	pose   pose2D
+	cost   float64
+	parent *hybridAStarNode
+}
+
+// path returns the inputs of every node from the start of the search to n.
+func (n *hybridAStarNode) path() [][]referenceframe.Input {
+	var path [][]referenceframe.Input
+	for ; n != nil; n = n.parent {
+		path = append([][]referenceframe.Input{n.pose.inputs()}, path...)
+	}
+	return path
+}
+
+type hybridAStarQueueItem struct {
+	node     *hybridAStarNode
+	priority float64
+}
+
+// hybridAStarQueue is a min-heap of search nodes by their cost plus the distance left to the goal.
+type hybridAStarQueue []hybridAStarQueueItem
+
+func (q hybridAStarQueue) Len() int            { return len(q) }
+func (q hybridAStarQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q hybridAStarQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *hybridAStarQueue) Push(x interface{}) { *q = append(*q, x.(hybridAStarQueueItem)) }
+
+func (q *hybridAStarQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,103 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestHybridAStar(t *testing.T) {
+	turningRadius := 500.
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 200, Y: 100, Z: 100}, "")
+	test.That(t, err, test.ShouldBeNil)
+	limits := []frame.Limit{{Min: -5000, Max: 5000}, {Min: -5000, Max: 5000}, {Min: -math.Pi, Max: math.Pi}}
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+
+	// a wall between the base and the goal, open above y = 500
+	wall, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 1000, Y: -1000}), r3.Vector{X: 200, Y: 3000, Z: 100}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"wall": wall})},
+	}
+	seed := frame.FloatsToInputs([]float64{0, 0, 0})
+	opt := newBasicPlannerOptions()
+	opt.extra = map[string]interface{}{"turning_radius": turningRadius}
+	collisionConstraint, err := newObstacleConstraint(
+		model, fs, worldState, map[string][]frame.Input{"base": seed}, nil, false, false, defaultCollisionBufferMM,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	opt.AddConstraint("collision", collisionConstraint)
+
+	//nolint: gosec
+	mp, err := newHybridAStarMotionPlanner(model, rand.New(rand.NewSource(1)), logger.Sugar(), opt)
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, goal := range []spatialmath.Pose{
+		spatialmath.NewPose(r3.Vector{X: 2000}, &spatialmath.EulerAngles{}),
+		spatialmath.NewPose(r3.Vector{X: 2000, Y: -500}, &spatialmath.EulerAngles{Yaw: math.Pi}),
+		// directly to the side, which a base that cannot move sideways has to turn to reach
+		spatialmath.NewPose(r3.Vector{Y: 300}, &spatialmath.EulerAngles{}),
+	} {
+		path, err := mp.plan(context.Background(), goal, seed)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, path[0], test.ShouldResemble, seed)
+		end, err := model.Transform(path[len(path)-1])
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqual(end, goal), test.ShouldBeTrue)
+
+		for i := 0; i < len(path)-1; i++ {
+			from, to := path[i], path[i+1]
+			ok, _ := collisionConstraint(&ConstraintInput{StartInput: to, Frame: model})
+			test.That(t, ok, test.ShouldBeTrue)
+
+			// each step is an arc the base can drive: it moves along its heading halfway through the turn, never sideways, and turns
+			// no tighter than its turning radius
+			dx, dy := to[0].Value-from[0].Value, to[1].Value-from[1].Value
+			turn := normalizeAngle(to[2].Value - from[2].Value)
+			sideways := math.Sin(math.Atan2(dy, dx) - (from[2].Value + turn/2))
+			test.That(t, sideways, test.ShouldAlmostEqual, 0, 1e-6)
+			if math.Abs(turn) > 1e-9 {
+				radius := math.Hypot(dx, dy) / (2 * math.Sin(math.Abs(turn)/2))
+				test.That(t, radius, test.ShouldBeGreaterThanOrEqualTo, turningRadius-1e-6)
+			}
+		}
+	}
+
+	// only frames with a heading can be planned for
+	model2D, err := frame.NewMobile2DFrame("base", limits[:2], geometry)
+	test.That(t, err, test.ShouldBeNil)
+	//nolint: gosec
+	_, err = newHybridAStarMotionPlanner(model2D, rand.New(rand.NewSource(1)), logger.Sugar(), opt)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// nothing can be planned to a goal in the wall
+	_, err = mp.plan(context.Background(), spatialmath.NewPoseFromPoint(r3.Vector{X: 1000, Y: -1000}), seed)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// bases with a heading are planned for with hybrid A* when no planning_alg is given
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPose(r3.Vector{X: 2000}, &spatialmath.EulerAngles{}))
+	seedMap := map[string][]frame.Input{"base": seed}
+	opts := map[string]interface{}{"rseed": 1, "turning_radius": turningRadius}
+	plan, report, err := PlanMotionWithReport(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Planner, test.ShouldEqual, HybridAStarPlanningAlg)
+	end, err := model.Transform(plan[len(plan)-1]["base"])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(end, goal.Pose()), test.ShouldBeTrue)
+	for i := 0; i < len(plan)-1; i++ {
+		from, to := plan[i]["base"], plan[i+1]["base"]
+		dx, dy := to[0].Value-from[0].Value, to[1].Value-from[1].Value
+		turn := normalizeAngle(to[2].Value - from[2].Value)
+		test.That(t, math.Sin(math.Atan2(dy, dx)-(from[2].Value+turn/2)), test.ShouldAlmostEqual, 0, 1e-6)
+	}
+}
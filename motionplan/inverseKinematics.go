@@ -2,6 +2,10 @@ package motionplan
 
 import (
 	"context"
+	"sync"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/referenceframe"
 	spatial "go.viam.com/rdk/spatialmath"
@@ -14,6 +18,80 @@ type InverseKinematics interface {
 	Solve(context.Context, chan<- []referenceframe.Input, spatial.Pose, []referenceframe.Input, Metric, int) error
 }
 
+// IKSolverConstructor creates an InverseKinematics solver for a frame, which may use up to nCPU threads. The frame is the one being
+// planned for: it takes the same inputs as the model the solver was registered for, but its poses may be measured from a frame
+// other than the base of the model.
+type IKSolverConstructor func(frame referenceframe.Frame, logger golog.Logger, nCPU int) (InverseKinematics, error)
+
+// AnyModel is the model name to register an IK solver under for it to be used for frames of every model.
+const AnyModel = ""
+
+var (
+	ikSolversMu sync.Mutex
+	// registered IK solver constructors, by solver name and then by model name
+	ikSolvers = map[string]map[string]IKSolverConstructor{}
+)
+
+// RegisterIKSolver registers an IK solver, such as an analytic solver, under a name for the frames of the model with the given name,
+// so that planners use it to solve for those frames when `ik_solver` is set to that name in the planning options. A solver
+// registered for AnyModel is used for frames of models with no solver of that name of their own. It panics if a solver is already
+// registered with the same name for the same model.
+func RegisterIKSolver(name, modelName string, constructor IKSolverConstructor) {
+	ikSolversMu.Lock()
+	defer ikSolversMu.Unlock()
+	if constructor == nil {
+		panic(errors.Errorf("cannot register a nil IK solver constructor for %q", name))
+	}
+	if _, ok := ikSolvers[name]; !ok {
+		ikSolvers[name] = map[string]IKSolverConstructor{}
+	}
+	if _, ok := ikSolvers[name][modelName]; ok {
+		panic(errors.Errorf("trying to register two IK solvers with the name %q for model %q", name, modelName))
+	}
+	ikSolvers[name][modelName] = constructor
+}
+
+// ikSolverFor returns the constructor of the IK solver registered under name for the model of the given frame.
+func ikSolverFor(name string, frame referenceframe.Frame) (IKSolverConstructor, error) {
+	ikSolversMu.Lock()
+	defer ikSolversMu.Unlock()
+	byModel, ok := ikSolvers[name]
+	if !ok {
+		return nil, errors.Errorf("no IK solver registered with the name %q", name)
+	}
+	modelName := ikModelName(frame)
+	if constructor, ok := byModel[modelName]; ok && modelName != AnyModel {
+		return constructor, nil
+	}
+	if constructor, ok := byModel[AnyModel]; ok {
+		return constructor, nil
+	}
+	return nil, errors.Errorf("IK solver %q is not registered for model %q of frame %s", name, modelName, frame.Name())
+}
+
+// ikModelName returns the name of the model whose inputs a frame takes, or AnyModel if they are not those of exactly one model,
+// e.g. for a solver frame planning for a gantry and the arm on it together.
+func ikModelName(frame referenceframe.Frame) string {
+	frames := []referenceframe.Frame{frame}
+	if sf, ok := frame.(*solverFrame); ok {
+		frames = sf.frames
+	}
+	var moving []referenceframe.Frame
+	for _, f := range frames {
+		if len(f.DoF()) > 0 {
+			moving = append(moving, f)
+		}
+	}
+	if len(moving) != 1 {
+		return AnyModel
+	}
+	model, ok := moving[0].(referenceframe.Model)
+	if !ok || model.ModelConfig() == nil {
+		return AnyModel
+	}
+	return model.ModelConfig().Name
+}
+
 func limitsToArrays(limits []referenceframe.Limit) ([]float64, []float64) {
 	var min, max []float64
 	for _, limit := range limits {
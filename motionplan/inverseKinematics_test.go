@@ -0,0 +1,83 @@
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// fixedIK is an IK solver which always gives the same solution, whatever the goal.
+type fixedIK struct {
+	solution []referenceframe.Input
+}
+
+func (ik *fixedIK) Solve(
+	ctx context.Context,
+	solutions chan<- []referenceframe.Input,
+	goal spatialmath.Pose,
+	seed []referenceframe.Input,
+	metric Metric,
+	rseed int,
+) error {
+	select {
+	case solutions <- ik.solution:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func TestIKSolverRegistry(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	xarm, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm7_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	xarmSolution := referenceframe.FloatsToInputs([]float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7})
+	RegisterIKSolver("test_analytic", "xArm7", func(referenceframe.Frame, golog.Logger, int) (InverseKinematics, error) {
+		return &fixedIK{solution: xarmSolution}, nil
+	})
+	_, err = ikSolverFor("test_analytic", ur5e)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = ikSolverFor("unregistered", xarm)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, func() {
+		RegisterIKSolver("test_analytic", "xArm7", func(referenceframe.Frame, golog.Logger, int) (InverseKinematics, error) {
+			return nil, nil
+		})
+	}, test.ShouldPanic)
+
+	// solvers registered for any model are used where there is none for the model itself
+	RegisterIKSolver("test_analytic", AnyModel, func(frame referenceframe.Frame, _ golog.Logger, _ int) (InverseKinematics, error) {
+		return &fixedIK{solution: make([]referenceframe.Input, len(frame.DoF()))}, nil
+	})
+	constructor, err := ikSolverFor("test_analytic", ur5e)
+	test.That(t, err, test.ShouldBeNil)
+	ik, err := constructor(ur5e, logger, 1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ik.(*fixedIK).solution, test.ShouldHaveLength, 6)
+
+	// the solver is found for the model within a solver frame, and used by planners when named in their options
+	fs := referenceframe.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(xarm, fs.World()), test.ShouldBeNil)
+	sFrames, err := fs.TracebackFrame(xarm)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, sFrames, referenceframe.World, referenceframe.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	opt := newBasicPlannerOptions()
+	opt.IKSolver = "test_analytic"
+	mp, err := newPlanner(sf, rand.New(rand.NewSource(1)), logger, opt)
+	test.That(t, err, test.ShouldBeNil)
+	goal, err := xarm.Transform(xarmSolution)
+	test.That(t, err, test.ShouldBeNil)
+	solutions, err := mp.getSolutions(context.Background(), goal, make([]referenceframe.Input, 7))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, solutions[0].Q(), test.ShouldResemble, xarmSolution)
+}
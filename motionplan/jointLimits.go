@@ -0,0 +1,84 @@
+package motionplan
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// default percentage of the range of each input, nearest each of its limits, within which the soft limit cost accrues.
+const defaultSoftLimitZonePercent = 10.
+
+// marginLimits returns the limits of each input of a frame moved inwards by a margin, so that plans stay clear of the hard stops of
+// real hardware. The margin is either given in the units joint positions are reported in, degrees for revolute joints and mm for
+// prismatic ones, or as a percentage of the range of each input. Infinite limits are left as they are. Inputs starting within the
+// margin may still move anywhere between where they start and the limit moved inwards, so that plans can move them clear of it.
+func marginLimits(f referenceframe.Frame, seed []referenceframe.Input, margin, marginPercent float64) ([]referenceframe.Limit, error) {
+	hard := f.DoF()
+	if len(seed) != len(hard) {
+		return nil, referenceframe.NewIncorrectInputLengthError(len(seed), len(hard))
+	}
+	// inputs of revolute joints are in radians, so a margin in degrees is converted to each input's units as a joint position would be
+	ones := make([]float64, len(hard))
+	for i := range ones {
+		ones[i] = 1
+	}
+	units := f.InputFromProtobuf(&pb.JointPositions{Values: ones})
+
+	limits := make([]referenceframe.Limit, 0, len(hard))
+	for i, limit := range hard {
+		if math.IsInf(limit.Min, 0) || math.IsInf(limit.Max, 0) {
+			limits = append(limits, limit)
+			continue
+		}
+		m := margin * units[i].Value
+		if marginPercent > 0 {
+			m = marginPercent / 100 * (limit.Max - limit.Min)
+		}
+		if m > 0 && 2*m >= limit.Max-limit.Min {
+			return nil, errors.Errorf("joint limit margin leaves no room between the limits of input %d", i)
+		}
+		limits = append(limits, referenceframe.Limit{
+			Min: math.Min(limit.Min+m, seed[i].Value),
+			Max: math.Max(limit.Max-m, seed[i].Value),
+		})
+	}
+	return limits, nil
+}
+
+// NewJointLimitConstraint returns a constraint which will be violated if any input moves outside of the given limits, e.g. limits
+// narrower than those of the frame, to keep clear of its hard stops.
+func NewJointLimitConstraint(limits []referenceframe.Limit) Constraint {
+	within := func(inputs []referenceframe.Input) bool {
+		for i, input := range inputs {
+			if i >= len(limits) || input.Value < limits[i].Min-defaultEpsilon || input.Value > limits[i].Max+defaultEpsilon {
+				return false
+			}
+		}
+		return true
+	}
+	return func(cInput *ConstraintInput) (bool, float64) {
+		return within(cInput.StartInput) && within(cInput.EndInput), 0
+	}
+}
+
+// softLimitProximity returns a function measuring how deep inputs are into the zones nearest the given limits, each zonePercent of the
+// range of its input wide. Each input adds the square of how far it is into its zone as a fraction of the zone's width, so it is zero
+// outside of the zones and one for an input at its limit.
+func softLimitProximity(limits []referenceframe.Limit, zonePercent float64) func([]float64) float64 {
+	return func(q []float64) float64 {
+		proximity := 0.
+		for i, limit := range limits {
+			if i >= len(q) || math.IsInf(limit.Min, 0) || math.IsInf(limit.Max, 0) || limit.Max <= limit.Min {
+				continue
+			}
+			zone := zonePercent / 100 * (limit.Max - limit.Min)
+			into := math.Max(0, zone-math.Min(q[i]-limit.Min, limit.Max-q[i])) / zone
+			proximity += into * into
+		}
+		return proximity
+	}
+}
@@ -0,0 +1,124 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestMarginLimits(t *testing.T) {
+	joint, err := frame.NewRotationalFrame("joint", *spatial.NewR4AA(), frame.Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	base, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: math.Inf(-1), Max: math.Inf(1)}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+
+	// margins of revolute joints are in degrees, and of other inputs in their own units
+	limits, err := marginLimits(joint, frame.FloatsToInputs([]float64{0}), 10, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, limits[0].Min, test.ShouldAlmostEqual, -math.Pi+utils.DegToRad(10))
+	test.That(t, limits[0].Max, test.ShouldAlmostEqual, math.Pi-utils.DegToRad(10))
+	limits, err = marginLimits(base, frame.FloatsToInputs([]float64{0, 0}), 10, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, limits[0], test.ShouldResemble, frame.Limit{Min: -90, Max: 90})
+	test.That(t, math.IsInf(limits[1].Max, 1), test.ShouldBeTrue)
+
+	// inputs starting within the margin may move between where they start and the narrowed limit
+	limits, err = marginLimits(base, frame.FloatsToInputs([]float64{-95, 0}), 0, 25)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, limits[0], test.ShouldResemble, frame.Limit{Min: -95, Max: 50})
+
+	_, err = marginLimits(base, frame.FloatsToInputs([]float64{0, 0}), 100, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = marginLimits(base, frame.FloatsToInputs([]float64{0}), 10, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	constraint := NewJointLimitConstraint([]frame.Limit{{Min: -90, Max: 90}})
+	ok, _ := constraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{0}), EndInput: frame.FloatsToInputs([]float64{90})})
+	test.That(t, ok, test.ShouldBeTrue)
+	ok, _ = constraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{0}), EndInput: frame.FloatsToInputs([]float64{91})})
+	test.That(t, ok, test.ShouldBeFalse)
+
+	proximity := softLimitProximity([]frame.Limit{{Min: -100, Max: 100}}, 10)
+	test.That(t, proximity([]float64{0}), test.ShouldEqual, 0)
+	test.That(t, proximity([]float64{90}), test.ShouldAlmostEqual, 0.25)
+	test.That(t, proximity([]float64{-100}), test.ShouldAlmostEqual, 1)
+}
+
+func TestSoftLimitCost(t *testing.T) {
+	opt := newBasicPlannerOptions()
+	opt.jointLimits = []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	middle := [][]frame.Input{frame.FloatsToInputs([]float64{-50, 0}), frame.FloatsToInputs([]float64{50, 0})}
+	edge := [][]frame.Input{frame.FloatsToInputs([]float64{-50, 95}), frame.FloatsToInputs([]float64{50, 95})}
+	test.That(t, planCost(edge, opt), test.ShouldAlmostEqual, planCost(middle, opt))
+
+	// paths near the limits cost more, so of two paths of the same length the one keeping away from them is preferred
+	opt.SoftLimitWeight = 1
+	test.That(t, planCost(middle, opt), test.ShouldAlmostEqual, 100)
+	test.That(t, planCost(edge, opt), test.ShouldBeGreaterThan, planCost(middle, opt)+10)
+}
+
+func TestPlanWithJointLimitMargin(t *testing.T) {
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	// a wall leaving a gap only near the upper limit of y
+	wall, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{Y: -20}), r3.Vector{X: 10, Y: 160, Z: 10}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{
+		frame.NewGeometriesInFrame(frame.World, map[string]spatial.Geometry{"wall": wall}),
+	}}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-50, 0})}
+	goal := frame.NewPoseInFrame(frame.World, spatial.NewPoseFromPoint(r3.Vector{X: 50}))
+
+	opts := map[string]interface{}{"joint_limit_margin": 10, "soft_limit_weight": 0.1, "rseed": 1}
+	plan, err := PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	passedGap := false
+	for _, step := range plan {
+		q := frame.InputsToFloats(step["base"])
+		test.That(t, q[0], test.ShouldBeBetweenOrEqual, -90-defaultEpsilon, 90+defaultEpsilon)
+		test.That(t, q[1], test.ShouldBeBetweenOrEqual, -90-defaultEpsilon, 90+defaultEpsilon)
+		if q[1] > 60 {
+			passedGap = true
+		}
+	}
+	test.That(t, passedGap, test.ShouldBeTrue)
+
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	opt, err := pm.plannerSetupFromMoveRequest(spatial.NewZeroPose(), goal.Pose(), seedMap, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.Constraints(), test.ShouldContain, defaultJointLimitConstraintName)
+	planner, err := newPlanner(sf, nil, logger.Sugar(), opt)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, planner.frame.DoF(), test.ShouldResemble, []frame.Limit{{Min: -90, Max: 90}, {Min: -90, Max: 90}})
+	test.That(t, sf.DoF(), test.ShouldResemble, []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}})
+
+	for _, badOpts := range []map[string]interface{}{
+		{"joint_limit_margin": -1},
+		{"joint_limit_margin": 1, "joint_limit_margin_percent": 1},
+		{"joint_limit_margin_percent": 50},
+		{"joint_limit_margin": 100},
+		{"soft_limit_weight": -1},
+		{"soft_limit_weight": 1, "soft_limit_zone_percent": 60},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatial.NewZeroPose(), goal.Pose(), seedMap, worldState, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
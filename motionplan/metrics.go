@@ -3,7 +3,12 @@ package motionplan
 import (
 	"math"
 
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/num/quat"
+
 	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
 )
 
 // Metric defines a distance function to be minimized by gradient descent algorithms.
@@ -76,3 +81,58 @@ func NewPoseFlexOVMetric(goal spatial.Pose, alpha float64) Metric {
 		return pDist*pDist + oDist*oDist
 	}
 }
+
+// GoalTolerance is how far from a goal pose a frame may finish, per axis of the goal pose, e.g. anywhere within 5mm of the goal along
+// its Z axis with any rotation about it, for placing something anywhere on a shelf. Distances are in mm and rotations in degrees;
+// RZ is the rotation about the goal's Z axis, and RX and RY the tilt of that axis towards its X and Y axes. Rotations of 180 degrees
+// or more leave the frame free to turn about that axis. Set by the `goal_tolerance` planning option.
+type GoalTolerance struct {
+	X  float64 `json:"x"`
+	Y  float64 `json:"y"`
+	Z  float64 `json:"z"`
+	RX float64 `json:"rx"`
+	RY float64 `json:"ry"`
+	RZ float64 `json:"rz"`
+}
+
+func (tol GoalTolerance) validate() error {
+	if tol.X < 0 || tol.Y < 0 || tol.Z < 0 || tol.RX < 0 || tol.RY < 0 || tol.RZ < 0 {
+		return errors.New("goal_tolerance cannot be negative")
+	}
+	return nil
+}
+
+// NewGoalRegionMetric returns a distance function which is zero anywhere within a tolerance of the goal pose, and grows with how far
+// outside of it the pose is, weighing orientation as NewSquaredNormMetric does. IK solutions anywhere within the region solve the goal.
+func NewGoalRegionMetric(tol GoalTolerance) Metric {
+	excess := func(dist, tol float64) float64 {
+		return math.Max(0, math.Abs(dist)-tol)
+	}
+	return func(from, to spatial.Pose) float64 {
+		// the pose in the frame of the goal, so the tolerances are along the axes of the goal
+		delta := spatial.Compose(spatial.PoseInverse(to), from)
+		pt := delta.Point()
+		pDist := r3.Vector{X: excess(pt.X, tol.X), Y: excess(pt.Y, tol.Y), Z: excess(pt.Z, tol.Z)}
+
+		// split the rotation into a twist about the goal's Z axis and a swing of that axis
+		q := delta.Orientation().Quaternion()
+		twistAngle := 0.
+		twist := quat.Number{Real: 1}
+		if norm := math.Hypot(q.Real, q.Kmag); norm > 1e-9 {
+			twist = quat.Number{Real: q.Real / norm, Kmag: q.Kmag / norm}
+			twistAngle = 2 * math.Atan2(q.Kmag, q.Real)
+			if twistAngle > math.Pi {
+				twistAngle -= 2 * math.Pi
+			} else if twistAngle < -math.Pi {
+				twistAngle += 2 * math.Pi
+			}
+		}
+		swing := spatial.QuatToR3AA(quat.Mul(q, quat.Conj(twist)))
+		oDist := r3.Vector{
+			X: excess(swing.X, utils.DegToRad(tol.RX)),
+			Y: excess(swing.Y, utils.DegToRad(tol.RY)),
+			Z: excess(twistAngle, utils.DegToRad(tol.RZ)),
+		}
+		return pDist.Norm2() + oDist.Mul(10.).Norm2()
+	}
+}
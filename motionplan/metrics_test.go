@@ -7,6 +7,7 @@ import (
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
+	frame "go.viam.com/rdk/referenceframe"
 	spatial "go.viam.com/rdk/spatialmath"
 )
 
@@ -49,3 +50,111 @@ func BenchmarkDeltaPose1(b *testing.B) {
 	// Prevent compiler optimizations interfering with benchmark
 	result = r
 }
+
+func TestWeightedDistanceFunc(t *testing.T) {
+	// the second joint moving is ten times as expensive as the first
+	distFunc := NewWeightedDistanceFunc([]float64{1, 10}, nil)
+	zero := frame.FloatsToInputs([]float64{0, 0})
+	_, dist := distFunc(&ConstraintInput{StartInput: zero, EndInput: frame.FloatsToInputs([]float64{1, 0})})
+	test.That(t, dist, test.ShouldAlmostEqual, 1)
+	_, dist = distFunc(&ConstraintInput{StartInput: zero, EndInput: frame.FloatsToInputs([]float64{0, 1})})
+	test.That(t, dist, test.ShouldAlmostEqual, 10)
+
+	// weights apply to registered distance functions too
+	RegisterDistanceFunc("test_l1", func(ci *ConstraintInput) (bool, float64) {
+		dist := 0.
+		for i, input := range ci.StartInput {
+			dist += math.Abs(input.Value - ci.EndInput[i].Value)
+		}
+		return true, dist
+	})
+	l1, err := distanceFuncFor("test_l1")
+	test.That(t, err, test.ShouldBeNil)
+	ones := frame.FloatsToInputs([]float64{1, 1})
+	_, dist = NewWeightedDistanceFunc([]float64{2, 3}, l1)(&ConstraintInput{StartInput: zero, EndInput: ones})
+	test.That(t, dist, test.ShouldAlmostEqual, 5)
+	test.That(t, func() { RegisterDistanceFunc("test_l1", l1) }, test.ShouldPanic)
+	_, err = distanceFuncFor("missing")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// both can be chosen through the planning options
+	cfg, err := simple2DMap()
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(cfg.RobotFrame, fs.World()), test.ShouldBeNil)
+	sFrames, err := fs.TracebackFrame(cfg.RobotFrame)
+	test.That(t, err, test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	opt, err := pm.plannerSetupFromMoveRequest(
+		spatial.NewZeroPose(), cfg.Goal, seedMap, nil,
+		map[string]interface{}{"planning_alg": "cbirrt", "distance_metric": "test_l1", "joint_weights": []interface{}{2, 3}},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	_, dist = opt.DistanceFunc(&ConstraintInput{StartInput: zero, EndInput: ones})
+	test.That(t, dist, test.ShouldAlmostEqual, 5)
+	for _, badOpts := range []map[string]interface{}{
+		{"distance_metric": "missing"},
+		{"joint_weights": []float64{1}},
+		{"joint_weights": []float64{1, -1}},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatial.NewZeroPose(), cfg.Goal, seedMap, nil, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestGoalRegionMetric(t *testing.T) {
+	// within 5mm of the goal along its Z axis, free to turn about it
+	metric := NewGoalRegionMetric(GoalTolerance{Z: 5, RZ: 180})
+	goal := spatial.NewPose(r3.Vector{X: 100, Y: 0, Z: 50}, &spatial.OrientationVectorDegrees{OX: 1, Theta: 30})
+
+	test.That(t, metric(goal, goal), test.ShouldAlmostEqual, 0)
+	// the goal's Z axis points along world X
+	test.That(t, metric(spatial.Compose(goal, spatial.NewPoseFromPoint(r3.Vector{Z: 4})), goal), test.ShouldAlmostEqual, 0)
+	test.That(t, metric(spatial.Compose(goal, spatial.NewPoseFromPoint(r3.Vector{Z: -8})), goal), test.ShouldAlmostEqual, 9)
+	test.That(t, metric(spatial.Compose(goal, spatial.NewPoseFromPoint(r3.Vector{X: 2})), goal), test.ShouldAlmostEqual, 4)
+
+	twisted := spatial.Compose(goal, spatial.NewPoseFromOrientation(&spatial.R4AA{Theta: 2.5, RZ: 1}))
+	test.That(t, metric(twisted, goal), test.ShouldAlmostEqual, 0)
+	tilted := spatial.Compose(goal, spatial.NewPoseFromOrientation(&spatial.R4AA{Theta: 0.1, RX: 1}))
+	test.That(t, metric(tilted, goal), test.ShouldAlmostEqual, 1, 1e-6)
+	test.That(t, metric(spatial.Compose(twisted, spatial.NewPoseFromOrientation(&spatial.R4AA{Theta: 0.1, RY: 1})), goal),
+		test.ShouldAlmostEqual, 1, 1e-6)
+
+	// tilting within the tolerance of the X and Y axes
+	metric = NewGoalRegionMetric(GoalTolerance{RX: 10, RY: 10})
+	test.That(t, metric(tilted, goal), test.ShouldAlmostEqual, 0)
+	test.That(t, metric(twisted, goal), test.ShouldBeGreaterThan, 0)
+
+	// set through the planning options
+	cfg, err := simple2DMap()
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(cfg.RobotFrame, fs.World()), test.ShouldBeNil)
+	sFrames, err := fs.TracebackFrame(cfg.RobotFrame)
+	test.That(t, err, test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	opt, err := pm.plannerSetupFromMoveRequest(
+		spatial.NewZeroPose(), cfg.Goal, seedMap, nil,
+		map[string]interface{}{"planning_alg": "cbirrt", "goal_tolerance": map[string]interface{}{"x": 10, "y": 10}},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	near := spatial.Compose(cfg.Goal, spatial.NewPoseFromPoint(r3.Vector{X: 6, Y: -8}))
+	test.That(t, opt.metric(near, cfg.Goal), test.ShouldAlmostEqual, 0)
+	for _, badOpts := range []map[string]interface{}{
+		{"goal_tolerance": map[string]interface{}{"z": -1}},
+		{"goal_tolerance": "anywhere"},
+		{"goal_tolerance": map[string]interface{}{"z": 1}, "motion_profile": LinearMotionProfile},
+		{"goal_tolerance": map[string]interface{}{"z": 1}, "motion_profile": PositionOnlyMotionProfile},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatial.NewZeroPose(), cfg.Goal, seedMap, nil, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
@@ -36,6 +36,7 @@ type motionPlanner interface {
 type plannerConstructor func(frame.Frame, *rand.Rand, golog.Logger, *plannerOptions) (motionPlanner, error)
 
 // PlanMotion plans a motion to destination for a given frame. It takes a given frame system, wraps it with a SolvableFS, and solves.
+// If the return_partial_plan option is set and planning times out, the steps planned so far are returned along with ErrPartialPlan.
 func PlanMotion(ctx context.Context,
 	logger golog.Logger,
 	dst *frame.PoseInFrame,
@@ -45,7 +46,112 @@ func PlanMotion(ctx context.Context,
 	worldState *frame.WorldState,
 	planningOpts map[string]interface{},
 ) ([]map[string][]frame.Input, error) {
-	return motionPlanInternal(ctx, logger, []*frame.PoseInFrame{dst}, f, seedMap, fs, worldState, []map[string]interface{}{planningOpts})
+	return motionPlanInternal(ctx, logger, []*frame.PoseInFrame{dst}, f, seedMap, fs, worldState, []map[string]interface{}{planningOpts}, nil)
+}
+
+// PlanMotionWithConfig plans a motion like PlanMotion, with planning options given as a PlannerConfig, which may be nil to plan with
+// the defaults.
+func PlanMotionWithConfig(ctx context.Context,
+	logger golog.Logger,
+	dst *frame.PoseInFrame,
+	f frame.Frame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	cfg *PlannerConfig,
+) ([]map[string][]frame.Input, error) {
+	if cfg == nil {
+		return PlanMotion(ctx, logger, dst, f, seedMap, fs, worldState, nil)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	planningOpts, err := cfg.Map()
+	if err != nil {
+		return nil, err
+	}
+	return PlanMotion(ctx, logger, dst, f, seedMap, fs, worldState, planningOpts)
+}
+
+// PlanMotionWithReport plans a motion like PlanMotion, also returning a report of how the plan was found. The report is returned even if
+// planning fails, describing what was tried.
+func PlanMotionWithReport(ctx context.Context,
+	logger golog.Logger,
+	dst *frame.PoseInFrame,
+	f frame.Frame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	planningOpts map[string]interface{},
+) ([]map[string][]frame.Input, *PlanReport, error) {
+	recorder := &planRecorder{}
+	steps, err := motionPlanInternal(
+		ctx,
+		logger,
+		[]*frame.PoseInFrame{dst},
+		f,
+		seedMap,
+		fs,
+		worldState,
+		[]map[string]interface{}{planningOpts},
+		recorder,
+	)
+	return steps, recorder.report(), err
+}
+
+// PlanSegment is part of a plan sent by PlanMotionStream: the steps to one of the waypoints planned along the way to the destination, or
+// the error which stopped planning. If the error is ErrPartialPlan, the steps are those planned towards the waypoint before planning
+// stopped.
+type PlanSegment struct {
+	Steps []map[string][]frame.Input
+	Err   error
+}
+
+// PlanMotionStream plans a motion like PlanMotion, but sends the plan on the returned channel a segment at a time, as soon as the steps
+// to each of the waypoints planned along the way to the destination are found, so that the start of the motion can be run while the
+// rest is still being planned. Each segment carries on from the last step of the one before it. Errors in setting up the plan are
+// returned at once; an error in planning it is sent as the last segment. The channel is closed once planning is done.
+//
+// As a motion may be partly run by the time planning fails, plans needing to be checked as a whole, such as those around moving
+// obstacles, are sent as a single segment once checked.
+func PlanMotionStream(ctx context.Context,
+	logger golog.Logger,
+	dst *frame.PoseInFrame,
+	f frame.Frame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	planningOpts map[string]interface{},
+) (<-chan PlanSegment, error) {
+	solveFrame := fs.Frame(f.Name())
+	if solveFrame == nil {
+		return nil, frame.NewFrameMissingError(f.Name())
+	}
+	solveFrameList, err := fs.TracebackFrame(solveFrame)
+	if err != nil {
+		return nil, err
+	}
+	pm, err := newGoalPlanManager(logger, dst, f, solveFrameList, seedMap, fs, worldState, planningOpts, 0)
+	if err != nil {
+		return nil, err
+	}
+	pm.storageDirs = storageDirsFromContext(ctx)
+	stream, err := pm.PlanSingleWaypointStream(ctx, seedMap, dst.Pose(), worldState, planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	segments := make(chan PlanSegment, cap(stream))
+	utils.PanicCapturingGo(func() {
+		defer close(segments)
+		for segment := range stream {
+			steps := make([]map[string][]frame.Input, 0, len(segment.steps))
+			for _, resultSlice := range segment.steps {
+				steps = append(steps, pm.frame.sliceToMap(resultSlice))
+			}
+			segments <- PlanSegment{Steps: steps, Err: segment.err}
+		}
+	})
+	return segments, nil
 }
 
 // PlanRobotMotion plans a motion to destination for a given frame. A robot object is passed in and current position inputs are determined.
@@ -62,7 +168,17 @@ func PlanRobotMotion(ctx context.Context,
 		return nil, err
 	}
 
-	return motionPlanInternal(ctx, r.Logger(), []*frame.PoseInFrame{dst}, f, seedMap, fs, worldState, []map[string]interface{}{planningOpts})
+	return motionPlanInternal(
+		ctx,
+		r.Logger(),
+		[]*frame.PoseInFrame{dst},
+		f,
+		seedMap,
+		fs,
+		worldState,
+		[]map[string]interface{}{planningOpts},
+		nil,
+	)
 }
 
 // PlanFrameMotion plans a motion to destination for a given frame with no frame system. It will create a new FS just for the plan.
@@ -91,7 +207,15 @@ func PlanFrameMotion(ctx context.Context,
 		fs,
 		nil,
 		[]map[string]interface{}{planningOpts},
+		nil,
 	)
+	if errors.Is(err, ErrPartialPlan) {
+		steps, stepsErr := FrameStepsFromRobotPath(f.Name(), solutionMap)
+		if stepsErr != nil {
+			return nil, stepsErr
+		}
+		return steps, err
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -109,12 +233,78 @@ func PlanWaypoints(ctx context.Context,
 	worldState *frame.WorldState,
 	motionConfigs []map[string]interface{},
 ) ([]map[string][]frame.Input, error) {
-	return motionPlanInternal(ctx, logger, goals, f, seedMap, fs, worldState, motionConfigs)
+	return motionPlanInternal(ctx, logger, goals, f, seedMap, fs, worldState, motionConfigs, nil)
+}
+
+// PlanMultiMotion plans motions for several frames at once, such as the two arms of a dual-arm handover, each to its own destination.
+// The frames are planned for together, treating all of their degrees of freedom as one configuration space, so that they avoid each
+// other as well as the obstacles of the world state. Each step of the returned plan has the inputs of every frame, so that they can be
+// moved in sync. The frames may not share any frames with degrees of freedom, e.g. a torso both arms are mounted on.
+func PlanMultiMotion(ctx context.Context,
+	logger golog.Logger,
+	goals map[string]*frame.PoseInFrame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	planningOpts map[string]interface{},
+) ([]map[string][]frame.Input, error) {
+	if len(goals) == 0 {
+		return nil, errors.New("no destinations passed to PlanMultiMotion")
+	}
+	names := make([]string, 0, len(goals))
+	for name := range goals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]*solverFrame, 0, len(goals))
+	goalPoses := make([]spatialmath.Pose, 0, len(goals))
+	for _, name := range names {
+		solveFrame := fs.Frame(name)
+		if solveFrame == nil {
+			return nil, frame.NewFrameMissingError(name)
+		}
+		solveFrameList, err := fs.TracebackFrame(solveFrame)
+		if err != nil {
+			return nil, err
+		}
+		sf, err := newSolverFrame(fs, solveFrameList, goals[name].Parent(), seedMap)
+		if err != nil {
+			return nil, err
+		}
+		if len(sf.DoF()) == 0 {
+			return nil, errors.Errorf("solver frame for %s has no degrees of freedom, cannot perform inverse kinematics", name)
+		}
+		parts = append(parts, sf)
+		goalPoses = append(goalPoses, goals[name].Pose())
+	}
+	sf, err := newMultiSolverFrame(fs, parts, seedMap)
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("planning motion for frames %v, starting seed map %v", names, seedMap)
+
+	pm, err := newPlanManager(sf, fs, logger, 0)
+	if err != nil {
+		return nil, err
+	}
+	pm.storageDirs = storageDirsFromContext(ctx)
+	resultSlices, err := pm.PlanMultiWaypoint(ctx, seedMap, goalPoses, worldState, planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([]map[string][]frame.Input, 0, len(resultSlices))
+	for _, resultSlice := range resultSlices {
+		steps = append(steps, sf.sliceToMap(resultSlice))
+	}
+	logger.Debugf("final plan steps: %v", steps)
+	return steps, nil
 }
 
 // motionPlanInternal is the internal private function that all motion planning access calls. This will construct the plan manager for each
 // waypoint, and return at the end.
 // This has the same function signature as `PlanWaypoints` but is a private function so as to not have public functions call other.
+// If a recorder is given, what happens while planning is recorded with it.
 func motionPlanInternal(ctx context.Context,
 	logger golog.Logger,
 	goals []*frame.PoseInFrame,
@@ -123,6 +313,7 @@ func motionPlanInternal(ctx context.Context,
 	fs frame.FrameSystem,
 	worldState *frame.WorldState,
 	motionConfigs []map[string]interface{},
+	recorder *planRecorder,
 ) ([]map[string][]frame.Input, error) {
 	if len(goals) == 0 {
 		return nil, errors.New("no destinations passed to PlanWaypoints")
@@ -164,56 +355,29 @@ func motionPlanInternal(ctx context.Context,
 	// Each goal is a different PoseInFrame and so may have a different destination Frame. Since the motion can be solved from either end,
 	// each goal is solved independently.
 	for i, goal := range goals {
-		// Create a frame to solve for, and an IK solver with that frame.
-		sf, err := newSolverFrame(fs, solveFrameList, goal.Parent(), seedMap)
-		if err != nil {
-			return nil, err
-		}
-		if len(sf.DoF()) == 0 {
-			return nil, errors.New("solver frame has no degrees of freedom, cannot perform inverse kinematics")
-		}
-		seed, err := sf.mapToSlice(seedMap)
-		if err != nil {
-			return nil, err
-		}
-		startPose, err := sf.Transform(seed)
-		if err != nil {
-			return nil, err
-		}
-		wsPb := &commonpb.WorldState{}
-		if worldState != nil {
-			wsPb, err = frame.WorldStateToProtobuf(worldState)
-			if err != nil {
-				return nil, err
-			}
-		}
-
-		logger.Infof(
-			"planning motion for frame %s. Goal: %v Starting seed map %v, startPose %v, worldstate: %v",
-			f.Name(),
-			frame.PoseInFrameToProtobuf(goal),
-			seedMap,
-			spatialmath.PoseToProtobuf(startPose),
-			wsPb,
-		)
-		logger.Debugf("motion config for this step: %v", opts[i])
-
-		sfPlanner, err := newPlanManager(sf, fs, logger, i)
+		sfPlanner, err := newGoalPlanManager(logger, goal, f, solveFrameList, seedMap, fs, worldState, opts[i], i)
 		if err != nil {
 			return nil, err
 		}
+		sfPlanner.recorder = recorder
+		sfPlanner.storageDirs = storageDirsFromContext(ctx)
 		resultSlices, err := sfPlanner.PlanSingleWaypoint(ctx, seedMap, goal.Pose(), worldState, opts[i])
-		if err != nil {
+		partial := errors.Is(err, ErrPartialPlan)
+		if err != nil && !partial {
 			return nil, err
 		}
 		for j, resultSlice := range resultSlices {
-			stepMap := sf.sliceToMap(resultSlice)
+			stepMap := sfPlanner.frame.sliceToMap(resultSlice)
 			steps = append(steps, stepMap)
 			if j == len(resultSlices)-1 {
 				// update seed map
 				seedMap = stepMap
 			}
 		}
+		if partial {
+			// the remaining goals cannot be planned for from the end of a partial plan
+			return steps, err
+		}
 	}
 
 	logger.Debugf("final plan steps: %v", steps)
@@ -221,6 +385,59 @@ func motionPlanInternal(ctx context.Context,
 	return steps, nil
 }
 
+// newGoalPlanManager returns a plan manager for the i-th goal of a plan, moving f from seedMap. solveFrameList is the parentage of f.
+func newGoalPlanManager(
+	logger golog.Logger,
+	goal *frame.PoseInFrame,
+	f frame.Frame,
+	solveFrameList []frame.Frame,
+	seedMap map[string][]frame.Input,
+	fs frame.FrameSystem,
+	worldState *frame.WorldState,
+	motionConfig map[string]interface{},
+	i int,
+) (*planManager, error) {
+	// Create a frame to solve for, and an IK solver with that frame.
+	sf, err := newSolverFrame(fs, solveFrameList, goal.Parent(), seedMap)
+	if err != nil {
+		return nil, err
+	}
+	if len(sf.DoF()) == 0 {
+		return nil, errors.New("solver frame has no degrees of freedom, cannot perform inverse kinematics")
+	}
+	seed, err := sf.mapToSlice(seedMap)
+	if err != nil {
+		return nil, err
+	}
+	startPose, err := sf.Transform(seed)
+	if err != nil {
+		return nil, err
+	}
+	wsPb := &commonpb.WorldState{}
+	if worldState != nil {
+		wsPb, err = frame.WorldStateToProtobuf(worldState)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Infof(
+		"planning motion for frame %s. Goal: %v Starting seed map %v, startPose %v, worldstate: %v",
+		f.Name(),
+		frame.PoseInFrameToProtobuf(goal),
+		seedMap,
+		spatialmath.PoseToProtobuf(startPose),
+		wsPb,
+	)
+	logger.Debugf("motion config for this step: %v", motionConfig)
+
+	pmSeed, err := planManagerSeed(i, motionConfig)
+	if err != nil {
+		return nil, err
+	}
+	return newPlanManager(sf, fs, logger, pmSeed)
+}
+
 type planner struct {
 	solver   InverseKinematics
 	frame    frame.Frame
@@ -228,12 +445,29 @@ type planner struct {
 	randseed *rand.Rand
 	start    time.Time
 	planOpts *plannerOptions
+	sampler  Sampler
 }
 
 func newPlanner(frame frame.Frame, seed *rand.Rand, logger golog.Logger, opt *plannerOptions) (*planner, error) {
-	ik, err := CreateCombinedIKSolver(frame, logger, opt.NumThreads)
-	if err != nil {
-		return nil, err
+	if sf, ok := frame.(*solverFrame); ok && opt.jointLimits != nil {
+		// IK solutions and samples are drawn from within the limits moved inwards by the joint limit margin
+		frame = sf.withLimits(opt.jointLimits)
+	}
+	var ik InverseKinematics
+	if opt.IKSolver != "" {
+		constructor, err := ikSolverFor(opt.IKSolver, frame)
+		if err != nil {
+			return nil, err
+		}
+		if ik, err = constructor(frame, logger, opt.NumThreads); err != nil {
+			return nil, err
+		}
+	} else {
+		combined, err := CreateCombinedIKSolver(frame, logger, opt.NumThreads)
+		if err != nil {
+			return nil, err
+		}
+		ik = combined
 	}
 	mp := &planner{
 		solver:   ik,
@@ -242,6 +476,15 @@ func newPlanner(frame frame.Frame, seed *rand.Rand, logger golog.Logger, opt *pl
 		randseed: seed,
 		planOpts: opt,
 	}
+	if opt.Sampler != "" {
+		constructor, err := samplerFor(opt.Sampler)
+		if err != nil {
+			return nil, err
+		}
+		if mp.sampler, err = constructor(frame, opt.extra); err != nil {
+			return nil, err
+		}
+	}
 	return mp, nil
 }
 
@@ -336,6 +579,7 @@ func (mp *planner) getSolutions(ctx context.Context, goal spatialmath.Pose, seed
 	}
 	goalPos := fixOvIncrement(goal, seedPos)
 
+	ikStart := time.Now()
 	solutionGen := make(chan []frame.Input)
 	ikErr := make(chan error, 1)
 	defer func() { <-ikErr }()
@@ -354,6 +598,33 @@ func (mp *planner) getSolutions(ctx context.Context, goal spatialmath.Pose, seed
 	// A map keeping track of which constraints fail
 	failures := map[string]int{}
 	constraintFailCnt := 0
+	// Number of solutions which failed constraints until moved through the nullspace
+	perturbedCnt := 0
+
+	// checkSolution returns whether a solution meets the constraints, its score if so, and the name of a failed constraint if not
+	checkSolution := func(step []frame.Input) (bool, float64, string) {
+		cPass, cScore, failName := mp.planOpts.CheckConstraints(&ConstraintInput{
+			seedPos,
+			goalPos,
+			seed,
+			step,
+			mp.frame,
+		})
+		if !cPass {
+			return false, 0, failName
+		}
+		// TODO (pl): Current implementation of constraints treats the starting input of a ConstraintInput as the state to check for
+		// validity. Since we use CheckConstraints instead of CheckConstraintPath here, we need to check both the start and
+		// end pose for validity
+		endPass, _, failName := mp.planOpts.CheckConstraints(&ConstraintInput{
+			goalPos,
+			goalPos,
+			step,
+			step,
+			mp.frame,
+		})
+		return endPass, cScore, failName
+	}
 
 	// Solve the IK solver. Loop labels are required because `break` etc in a `select` will break only the `select`.
 IK:
@@ -366,45 +637,46 @@ IK:
 
 		select {
 		case step := <-solutionGen:
-			cPass, cScore, failName := mp.planOpts.CheckConstraints(&ConstraintInput{
-				seedPos,
-				goalPos,
-				seed,
-				step,
-				mp.frame,
-			})
-			if cPass {
-				// TODO (pl): Current implementation of constraints treats the starting input of a ConstraintInput as the state to check for
-				// validity. Since we use CheckConstraints instead of CheckConstraintPath here, we need to check both the start and
-				// end pose for validity
-				endPass, _, failName := mp.planOpts.CheckConstraints(&ConstraintInput{
-					goalPos,
-					goalPos,
-					step,
-					step,
-					mp.frame,
-				})
-
-				if endPass {
-					if cScore < mp.planOpts.MinScore && mp.planOpts.MinScore > 0 {
-						solutions = map[float64][]frame.Input{}
-						solutions[cScore] = step
-						// good solution, stopping early
-						break IK
+			if mp.planOpts.nullspace != nil {
+				// redundant frames can reach the same goal with many inputs; move to those the secondary objective prefers
+				step = nullspaceOptimize(mp.frame, step, goalPos, mp.planOpts.metric, mp.planOpts.nullspace)
+			}
+			pass, cScore, failName := checkSolution(step)
+			if !pass && hasNullspace(mp.frame, step) {
+				// a solution which fails, e.g. by colliding, may be moved through the nullspace to one reaching the same goal which
+				// does not
+				for i := 0; i < defaultIKPerturbations && !pass; i++ {
+					perturbed := perturbInNullspace(mp.frame, step, goalPos, mp.planOpts.metric, mp.randseed)
+					if perturbed == nil {
+						continue
 					}
-
-					solutions[cScore] = step
-					if len(solutions) >= nSolutions {
-						// sufficient solutions found, stopping early
-						break IK
+					if perturbedPass, perturbedScore, _ := checkSolution(perturbed); perturbedPass {
+						pass, cScore, step = true, perturbedScore, perturbed
+						perturbedCnt++
 					}
-				} else {
-					constraintFailCnt++
-					failures[failName]++
 				}
-			} else {
+			}
+			if !pass {
 				constraintFailCnt++
 				failures[failName]++
+				if mp.planOpts.MaxIKRejections > 0 && constraintFailCnt >= mp.planOpts.MaxIKRejections {
+					// too many solutions have been rejected, e.g. in a cluttered scene, so stop rather than keep searching
+					break IK
+				}
+				continue IK
+			}
+
+			if cScore < mp.planOpts.MinScore && mp.planOpts.MinScore > 0 {
+				solutions = map[float64][]frame.Input{}
+				solutions[cScore] = step
+				// good solution, stopping early
+				break IK
+			}
+
+			solutions[cScore] = step
+			if len(solutions) >= nSolutions {
+				// sufficient solutions found, stopping early
+				break IK
 			}
 			// Skip the return check below until we have nothing left to read from solutionGen
 			continue IK
@@ -419,6 +691,16 @@ IK:
 		default:
 		}
 	}
+	mp.logger.Debugf(
+		"found %d valid IK solutions, %d of them after perturbing, rejected %d failing constraints",
+		len(solutions), perturbedCnt, constraintFailCnt,
+	)
+	mp.planOpts.recorder.record(func(report *PlanReport) {
+		report.IKTime += time.Since(ikStart)
+		report.IKSolutions += len(solutions)
+		report.IKRejections += constraintFailCnt
+		report.IKPerturbations += perturbedCnt
+	})
 	if len(solutions) == 0 {
 		// We have failed to produce a usable IK solution. Let the user know if zero IK solutions were produced, or if non-zero solutions
 		// were produced, which constraints were failed
@@ -5,8 +5,10 @@ import (
 	"math"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/test"
@@ -41,6 +43,7 @@ func TestUnconstrainedMotion(t *testing.T) {
 	planners := []plannerConstructor{
 		newRRTStarConnectMotionPlanner,
 		newCBiRRTMotionPlanner,
+		newPRMMotionPlanner,
 	}
 	testCases := []struct {
 		name   string
@@ -81,6 +84,223 @@ func TestConstrainedMotion(t *testing.T) {
 	}
 }
 
+func TestAnytimeMotion(t *testing.T) {
+	cfg, err := simple2DMap()
+	test.That(t, err, test.ShouldBeNil)
+	cfg.Options.extra = map[string]interface{}{"anytime": true}
+	mp, err := newRRTStarConnectMotionPlanner(cfg.RobotFrame, rand.New(rand.NewSource(1)), logger.Sugar(), cfg.Options)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the planner keeps improving the path until it times out, then returns the best one found
+	timeout := 500 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	start := time.Now()
+	path, err := mp.plan(ctx, cfg.Goal, cfg.Start)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, timeout)
+	test.That(t, len(path), test.ShouldBeGreaterThanOrEqualTo, 2)
+	for j := 0; j < len(path)-1; j++ {
+		ok, _ := cfg.Options.constraintHandler.CheckConstraintPath(&ConstraintInput{
+			StartInput: path[j],
+			EndInput:   path[j+1],
+			Frame:      cfg.RobotFrame,
+		}, cfg.Options.Resolution)
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+
+	// anytime planning is only done by RRT*, and needs a timeout to stop at
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(cfg.RobotFrame, fs.World()), test.ShouldBeNil)
+	sFrames, err := fs.TracebackFrame(cfg.RobotFrame)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, frame.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	opt, err := pm.plannerSetupFromMoveRequest(
+		spatialmath.NewZeroPose(), cfg.Goal, seedMap, nil, map[string]interface{}{"anytime": true, "timeout": 1.},
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.Fallback, test.ShouldBeNil)
+	test.That(t, opt.Timeout, test.ShouldEqual, 1.)
+	_, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), cfg.Goal, seedMap, nil, map[string]interface{}{"anytime": true})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = pm.plannerSetupFromMoveRequest(
+		spatialmath.NewZeroPose(), cfg.Goal, seedMap, nil, map[string]interface{}{"anytime": true, "timeout": 1., "planning_alg": "cbirrt"},
+	)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPartialPlan(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+
+	// walls enclosing the goal, so that it can be solved for but never reached
+	obstacles := map[string]spatialmath.Geometry{}
+	for name, wall := range map[string][2]r3.Vector{
+		"n": {{70, 90, 0}, {40, 2, 10}},
+		"s": {{70, 50, 0}, {40, 2, 10}},
+		"e": {{90, 70, 0}, {2, 40, 10}},
+		"w": {{50, 70, 0}, {2, 40, 10}},
+	} {
+		obstacles[name], err = spatialmath.NewBox(spatialmath.NewPoseFromPoint(wall[0]), wall[1], name)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, obstacles)}}
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 70, Y: 70}))
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-80, -80})}
+	opts := map[string]interface{}{"planning_alg": "cbirrt", "timeout": 0.5, "rseed": 1}
+
+	plan, err := PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, plan, test.ShouldBeNil)
+
+	// with the option set, the path towards the goal found before the timeout is returned
+	opts["return_partial_plan"] = true
+	plan, err = PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, errors.Is(err, ErrPartialPlan), test.ShouldBeTrue)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+	test.That(t, plan[0]["base"], test.ShouldResemble, seedMap["base"])
+	end := frame.InputsToFloats(plan[len(plan)-1]["base"])
+	test.That(t, r3.Vector{X: end[0], Y: end[1]}.Distance(goal.Pose().Point()), test.ShouldBeLessThan, r3.Vector{X: 150, Y: 150}.Norm())
+	for _, step := range plan {
+		q := frame.InputsToFloats(step["base"])
+		test.That(t, q[0] > 50 && q[0] < 90 && q[1] > 50 && q[1] < 90, test.ShouldBeFalse)
+	}
+}
+
+func TestPlanMotionStream(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 80}))
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-80, 0})}
+	opts := map[string]interface{}{"motion_profile": LinearMotionProfile, "path_step_size": 40, "rseed": 1}
+
+	// a linear motion is planned through waypoints along the line, each sent on as soon as it is planned
+	stream, err := PlanMotionStream(context.Background(), logger.Sugar(), goal, model, seedMap, fs, nil, opts)
+	test.That(t, err, test.ShouldBeNil)
+	segments := []PlanSegment{}
+	for segment := range stream {
+		test.That(t, segment.Err, test.ShouldBeNil)
+		test.That(t, segment.Steps, test.ShouldNotBeEmpty)
+		segments = append(segments, segment)
+	}
+	test.That(t, len(segments), test.ShouldBeGreaterThan, 1)
+	test.That(t, segments[0].Steps[0]["base"], test.ShouldResemble, seedMap["base"])
+	for i := 1; i < len(segments); i++ {
+		last := segments[i-1].Steps[len(segments[i-1].Steps)-1]
+		test.That(t, segments[i].Steps[0]["base"], test.ShouldResemble, last["base"])
+	}
+	lastSegment := segments[len(segments)-1]
+	end := frame.InputsToFloats(lastSegment.Steps[len(lastSegment.Steps)-1]["base"])
+	test.That(t, end[0], test.ShouldAlmostEqual, 80, 0.1)
+	test.That(t, end[1], test.ShouldAlmostEqual, 0, 0.1)
+
+	// errors setting up the plan are returned before anything is planned
+	_, err = PlanMotionStream(context.Background(), logger.Sugar(), goal, model, seedMap, fs, nil, map[string]interface{}{"timeout": "soon"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// errors planning it are sent last, after the segments planned before it: here a waypoint along the line is inside an obstacle
+	obstacle, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{
+		frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"obstacle": obstacle}),
+	}}
+	opts["timeout"] = 5
+	stream, err = PlanMotionStream(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	var lastErr error
+	planned := 0
+	for segment := range stream {
+		test.That(t, lastErr, test.ShouldBeNil)
+		lastErr = segment.Err
+		planned += len(segment.Steps)
+	}
+	test.That(t, lastErr, test.ShouldNotBeNil)
+	test.That(t, planned, test.ShouldBeGreaterThan, 0)
+	test.That(t, lastErr, test.ShouldNotBeNil)
+}
+
+func TestIKRejections(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Y: 50}), r3.Vector{X: 20, Y: 20, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": box})},
+	}
+	seed := frame.FloatsToInputs([]float64{-50, -50})
+	collisionConstraint, err := newObstacleConstraint(
+		model, fs, worldState, map[string][]frame.Input{"base": seed}, nil, false, false, defaultCollisionBufferMM,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	// every solution for a goal inside of the obstacle collides, which is reported once enough solutions have been rejected
+	opt := newBasicPlannerOptions()
+	test.That(t, opt.MaxIKRejections, test.ShouldEqual, defaultMaxIKRejections)
+	opt.AddConstraint(defaultObstacleConstraintName, collisionConstraint)
+	opt.MaxIKRejections = 3
+	mp, err := newPlanner(model, rand.New(rand.NewSource(1)), logger.Sugar(), opt)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = mp.getSolutions(context.Background(), spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Y: 50}), seed)
+	var constraintErr *IKConstraintError
+	test.That(t, errors.As(err, &constraintErr), test.ShouldBeTrue)
+	test.That(t, constraintErr.Solutions, test.ShouldEqual, 3)
+	test.That(t, constraintErr.Failures, test.ShouldResemble, map[string]int{defaultObstacleConstraintName: 3})
+}
+
+func TestDeterministicPlanning(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 40, Y: 40, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": box})},
+	}
+	goals := []*frame.PoseInFrame{
+		frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 60, Y: 60})),
+		frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: -60, Y: 60})),
+	}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-60, -60})}
+	opts := map[string]interface{}{"deterministic": true, "rseed": 3, "num_threads": 4}
+
+	// the same request gives the same plan every time
+	plan, err := PlanWaypoints(context.Background(), logger.Sugar(), goals, model, seedMap, fs, worldState, []map[string]interface{}{opts})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 2; i++ {
+		again, err := PlanWaypoints(context.Background(), logger.Sugar(), goals, model, seedMap, fs, worldState, []map[string]interface{}{opts})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, again, test.ShouldResemble, plan)
+	}
+
+	opts["anytime"] = true
+	opts["timeout"] = 1.
+	_, err = PlanWaypoints(context.Background(), logger.Sugar(), goals, model, seedMap, fs, worldState, []map[string]interface{}{opts})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 // TestConstrainedArmMotion tests a simple linear motion on a longer path, with a no-spill constraint.
 func constrainedXArmMotion() (*planConfig, error) {
 	model, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm7_kinematics.json"), "")
@@ -190,7 +410,7 @@ func simple2DMap() (*planConfig, error) {
 	opt := newBasicPlannerOptions()
 	startInput := frame.StartPositions(fs)
 	startInput[modelName] = frame.FloatsToInputs([]float64{-90., 90.})
-	collisionConstraint, err := newObstacleConstraint(model, fs, worldState, startInput, nil, false)
+	collisionConstraint, err := newObstacleConstraint(model, fs, worldState, startInput, nil, false, false, defaultCollisionBufferMM)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +439,7 @@ func simpleXArmMotion() (*planConfig, error) {
 
 	// setup planner options
 	opt := newBasicPlannerOptions()
-	collisionConstraint, err := newSelfCollisionConstraint(xarm, frame.StartPositions(fs), nil, false)
+	collisionConstraint, err := newSelfCollisionConstraint(xarm, frame.StartPositions(fs), nil, false, false, defaultCollisionBufferMM)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +466,7 @@ func simpleUR5eMotion() (*planConfig, error) {
 
 	// setup planner options
 	opt := newBasicPlannerOptions()
-	collisionConstraint, err := newSelfCollisionConstraint(ur5e, frame.StartPositions(fs), nil, false)
+	collisionConstraint, err := newSelfCollisionConstraint(ur5e, frame.StartPositions(fs), nil, false, false, defaultCollisionBufferMM)
 	if err != nil {
 		return nil, err
 	}
@@ -430,6 +650,71 @@ func TestMultiArmSolve(t *testing.T) {
 	test.That(t, spatialmath.PoseAlmostCoincidentEps(solvedPose2.(*frame.PoseInFrame).Pose(), goal2, 0.1), test.ShouldBeTrue)
 }
 
+func TestMultiMotion(t *testing.T) {
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	for _, name := range []string{"a", "b"} {
+		geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 20, Y: 20, Z: 20}, name+"Box")
+		test.That(t, err, test.ShouldBeNil)
+		model, err := frame.NewMobile2DFrame(name, []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, geometry)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	}
+	marker, err := frame.NewStaticFrame("marker", spatialmath.NewZeroPose())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(marker, fs.Frame("a")), test.ShouldBeNil)
+
+	// the straight lines between the starts and goals of the two bases cross halfway along, where they would collide if followed
+	seedMap := map[string][]frame.Input{
+		"a": frame.FloatsToInputs([]float64{-60, 0}),
+		"b": frame.FloatsToInputs([]float64{0, -60}),
+	}
+	goals := map[string]*frame.PoseInFrame{
+		"a": frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 60})),
+		"b": frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{Y: 60})),
+	}
+	plan, err := PlanMultiMotion(context.Background(), logger.Sugar(), goals, seedMap, fs, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+	for name, goal := range goals {
+		test.That(t, plan[0][name], test.ShouldResemble, seedMap[name])
+		solved, err := fs.Transform(plan[len(plan)-1], frame.NewPoseInFrame(name, spatialmath.NewZeroPose()), frame.World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(solved.(*frame.PoseInFrame).Pose(), goal.Pose(), 0.1), test.ShouldBeTrue)
+	}
+
+	// every step of the plan moves both bases, without them ever colliding with each other
+	parts := make([]*solverFrame, 0, 2)
+	for _, name := range []string{"a", "b"} {
+		sFrames, err := fs.TracebackFrame(fs.Frame(name))
+		test.That(t, err, test.ShouldBeNil)
+		part, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+		test.That(t, err, test.ShouldBeNil)
+		parts = append(parts, part)
+	}
+	sf, err := newMultiSolverFrame(fs, parts, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(sf.DoF()), test.ShouldEqual, 4)
+	selfCollision, err := newSelfCollisionConstraint(sf, seedMap, nil, false, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	handler := &constraintHandler{}
+	handler.AddConstraint("self", selfCollision)
+	for i := 0; i < len(plan)-1; i++ {
+		from, err := sf.mapToSlice(plan[i])
+		test.That(t, err, test.ShouldBeNil)
+		to, err := sf.mapToSlice(plan[i+1])
+		test.That(t, err, test.ShouldBeNil)
+		ok, _ := handler.CheckConstraintPath(&ConstraintInput{StartInput: from, EndInput: to, Frame: sf}, defaultResolution)
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+
+	// frames cannot be planned for together if they share a frame that moves
+	goals["marker"] = frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{Y: -60}))
+	_, err = PlanMultiMotion(context.Background(), logger.Sugar(), goals, seedMap, fs, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = PlanMultiMotion(context.Background(), logger.Sugar(), nil, seedMap, fs, nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestSliceUniq(t *testing.T) {
 	fs := makeTestFS(t)
 	slice := []frame.Frame{}
@@ -0,0 +1,171 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// how often a scheduled path is checked against moving obstacles between the waypoints of its trajectory.
+	movingObstacleCheckInterval = 50 * time.Millisecond
+
+	// number of times a path is planned before giving up if it collides with moving obstacles once scheduled.
+	movingObstacleAttempts = 3
+)
+
+// TimedConstraint determines whether the inputs of a frame are valid at a time since the start of a motion, for constraints which
+// change over time such as keeping clear of moving obstacles.
+type TimedConstraint func(inputs []referenceframe.Input, t time.Duration) bool
+
+// newMovingObstacleConstraint returns a TimedConstraint which is violated if the geometries of frame collide with the moving obstacles
+// of worldState where they are at the time. As with other collision constraints, collisions already present at observationInput at
+// the start of the motion are ignored. It returns nil if there are no moving obstacles.
+func newMovingObstacleConstraint(
+	frame referenceframe.Frame,
+	fs referenceframe.FrameSystem,
+	worldState *referenceframe.WorldState,
+	observationInput map[string][]referenceframe.Input,
+	collisionBufferMM float64,
+) (TimedConstraint, error) {
+	worldState, err := worldState.ToWorldFrame(fs, observationInput)
+	if err != nil {
+		return nil, err
+	}
+	movingObstacles := worldState.MovingObstacles
+	if len(movingObstacles) == 0 {
+		return nil, nil
+	}
+	obstaclesAt := func(t time.Duration) map[string]spatial.Geometry {
+		obstacles := map[string]spatial.Geometry{}
+		for i, mo := range movingObstacles {
+			for name, geometry := range mo.GeometriesAt(t).Geometries() {
+				obstacles["moving_"+strconv.Itoa(i)+"_"+name] = geometry
+			}
+		}
+		return obstacles
+	}
+
+	var goodInputs []referenceframe.Input
+	switch f := frame.(type) {
+	case *solverFrame:
+		goodInputs, err = f.mapToSlice(observationInput)
+	default:
+		goodInputs, err = referenceframe.GetFrameInputs(f, observationInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+	zeroVols, err := frame.Geometries(goodInputs)
+	if err != nil && len(zeroVols.Geometries()) == 0 {
+		return nil, err // no geometries defined for frame
+	}
+	zeroCG, err := newCollisionGraph(zeroVols.Geometries(), obstaclesAt(0), nil, true, collisionBufferMM)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(inputs []referenceframe.Input, t time.Duration) bool {
+		internal, err := frame.Geometries(inputs)
+		if err != nil && internal == nil {
+			return false
+		}
+		cg, err := newCollisionGraph(internal.Geometries(), obstaclesAt(t), zeroCG, false, collisionBufferMM)
+		if err != nil {
+			return false
+		}
+		return len(cg.collisions()) == 0
+	}, nil
+}
+
+// earliestArrival returns a function estimating when inputs are reached during a motion from seed, as the soonest they could be if
+// each input moved straight to them at its velocity limit. Inputs without a velocity limit are taken to move instantly. While paths
+// are planned their timing is not known, so this is what planners check moving obstacles at.
+func earliestArrival(seed []referenceframe.Input, limits []referenceframe.DynamicLimit) func([]referenceframe.Input) time.Duration {
+	return func(inputs []referenceframe.Input) time.Duration {
+		seconds := 0.
+		for i, limit := range limits {
+			if limit.MaxVelocity > 0 {
+				seconds = math.Max(seconds, math.Abs(inputs[i].Value-seed[i].Value)/limit.MaxVelocity)
+			}
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+}
+
+// newEarliestArrivalConstraint returns a Constraint checking a TimedConstraint at the earliest its inputs could be reached from seed.
+func newEarliestArrivalConstraint(frame referenceframe.Frame, seed []referenceframe.Input, timed TimedConstraint) (Constraint, error) {
+	limits := referenceframe.DynamicLimitsOf(frame)
+	limited := false
+	for _, limit := range limits {
+		limited = limited || limit.MaxVelocity > 0
+	}
+	if !limited {
+		return nil, errors.New("planning around moving obstacles requires velocity limits, to know when the frame would meet them")
+	}
+	arrival := earliestArrival(seed, limits)
+	return func(ci *ConstraintInput) (bool, float64) {
+		return timed(ci.StartInput, arrival(ci.StartInput)), 0
+	}, nil
+}
+
+// checkScheduledPath times a path as fast as the dynamic limits of frame allow, and checks each waypoint of the trajectory against
+// a TimedConstraint at the time it is reached, as well as every movingObstacleCheckInterval between them. It returns whether the
+// path meets the constraint, and if not the first time at which it does not.
+func checkScheduledPath(
+	frame referenceframe.Frame,
+	path [][]referenceframe.Input,
+	constraint TimedConstraint,
+) (bool, time.Duration, error) {
+	traj, err := TimeParameterizeFramePath(frame, path)
+	if err != nil {
+		return false, 0, err
+	}
+	for i, wp := range traj {
+		if i > 0 {
+			for t := traj[i-1].Time + movingObstacleCheckInterval; t < wp.Time; t += movingObstacleCheckInterval {
+				if inputs, _ := traj.At(t); !constraint(inputs, t) {
+					return false, t, nil
+				}
+			}
+		}
+		if !constraint(wp.Inputs, wp.Time) {
+			return false, wp.Time, nil
+		}
+	}
+	return true, 0, nil
+}
+
+// planScheduledWaypoints plans a motion as planAtomicWaypoints does, then checks the path once scheduled against a TimedConstraint.
+// Planners only check moving obstacles at the soonest each step could be reached, so a path meeting one after all is planned again.
+func (pm *planManager) planScheduledWaypoints(
+	ctx context.Context,
+	goals []spatial.Pose,
+	seed []referenceframe.Input,
+	planners []motionPlanner,
+	constraint TimedConstraint,
+) ([][]referenceframe.Input, error) {
+	for attempt := 1; ; attempt++ {
+		resultSlices, err := pm.planAtomicWaypoints(ctx, goals, seed, planners)
+		if err != nil {
+			return resultSlices, err
+		}
+		clear, collisionTime, err := checkScheduledPath(pm.frame, resultSlices, constraint)
+		if err != nil {
+			return nil, err
+		}
+		if clear {
+			return resultSlices, nil
+		}
+		pm.logger.Debugf("path collides with a moving obstacle %v into the motion", collisionTime)
+		if attempt == movingObstacleAttempts {
+			return nil, errors.Errorf("every path found collides with a moving obstacle, the last %v into the motion", collisionTime)
+		}
+	}
+}
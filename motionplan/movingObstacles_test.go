@@ -0,0 +1,98 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestMovingObstacles(t *testing.T) {
+	// a carriage sliding along x at up to 100 mm/s
+	cfg := &frame.ModelConfig{
+		Name: "gantry",
+		Joints: []frame.JointConfig{{
+			ID:     "slide",
+			Type:   frame.PrismaticJoint,
+			Parent: frame.World,
+			Axis:   spatialmath.AxisConfig{X: 1},
+			Max:    200,
+			MaxVel: 100,
+		}},
+		Links: []frame.LinkConfig{{
+			ID:       "carriage",
+			Parent:   "slide",
+			Geometry: &spatialmath.GeometryConfig{Type: "box", X: 10, Y: 10, Z: 10},
+		}},
+	}
+	model, err := cfg.ParseConfig("gantry")
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 100}))
+
+	// a box on a conveyor crossing the path of the carriage at x = 50, which the carriage reaches half a second in
+	worldStateCrossingAt := func(y float64) *frame.WorldState {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 50, Y: y}), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+		test.That(t, err, test.ShouldBeNil)
+		return &frame.WorldState{MovingObstacles: []*frame.MovingObstacle{{
+			Geometries: frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"box": box}),
+			Velocity:   r3.Vector{Y: 200},
+		}}}
+	}
+	opts := map[string]interface{}{"planning_alg": "cbirrt", "rseed": 1, "timeout": 5.}
+
+	// the box is in the way when the carriage would pass it
+	_, err = PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldStateCrossingAt(-100), opts)
+	test.That(t, err, test.ShouldNotBeNil)
+	// but not if it comes by after the carriage has passed
+	plan, err := PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldStateCrossingAt(-300), opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, plan[len(plan)-1]["gantry"][0].Value, test.ShouldAlmostEqual, 100, 1e-3)
+
+	// a path coming back through x = 50 a second and a half in meets the box once scheduled, though it could be there sooner
+	movingObstacles, err := newMovingObstacleConstraint(model, fs, worldStateCrossingAt(-300), seedMap, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	arrivalConstraint, err := newEarliestArrivalConstraint(model, frame.FloatsToInputs([]float64{0}), movingObstacles)
+	test.That(t, err, test.ShouldBeNil)
+	path := [][]frame.Input{frame.FloatsToInputs([]float64{0}), frame.FloatsToInputs([]float64{100}), frame.FloatsToInputs([]float64{0})}
+	ok, _ := arrivalConstraint(&ConstraintInput{StartInput: frame.FloatsToInputs([]float64{50})})
+	test.That(t, ok, test.ShouldBeTrue)
+	clear, collisionTime, err := checkScheduledPath(model, path, movingObstacles)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, clear, test.ShouldBeFalse)
+	test.That(t, collisionTime, test.ShouldBeBetween, time.Second, 2*time.Second)
+	clear, _, err = checkScheduledPath(model, path[:2], movingObstacles)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, clear, test.ShouldBeTrue)
+
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	for _, badOpts := range []map[string]interface{}{
+		{"planning_alg": "prm"},
+		{"reuse_rrt_maps": true},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal.Pose(), seedMap, worldStateCrossingAt(-300), badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+	_, err = pm.PlanSingleWaypoint(context.Background(), seedMap, goal.Pose(), worldStateCrossingAt(-300), map[string]interface{}{
+		"use_plan_cache": true,
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// without velocity limits, there is no telling when the frame would meet moving obstacles
+	base, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = newEarliestArrivalConstraint(base, frame.FloatsToInputs([]float64{0, 0}), movingObstacles)
+	test.That(t, err, test.ShouldNotBeNil)
+}
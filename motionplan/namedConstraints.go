@@ -0,0 +1,272 @@
+package motionplan
+
+import (
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ConstraintRequest is what a ConstraintConstructor builds a constraint for: a motion of a frame between two poses, with the
+// attributes given for the constraint in the `constraints` planning option.
+type ConstraintRequest struct {
+	From, To    spatialmath.Pose
+	FrameSystem referenceframe.FrameSystem
+	// Name of the frame being moved
+	Frame      string
+	Attributes config.AttributeMap
+}
+
+// ConstraintConstructor builds a constraint for a motion. It may also return a Metric measuring how far a pose is from satisfying the
+// constraint, which planners minimize to bring poses back within it, or nil if the constraint can only be checked.
+type ConstraintConstructor func(req *ConstraintRequest) (Constraint, Metric, error)
+
+var (
+	constraintsMu sync.Mutex
+	constraints   = map[string]ConstraintConstructor{
+		"linear":       newNamedLinearConstraint,
+		"pseudolinear": newNamedPseudolinearConstraint,
+		"orientation":  newNamedOrientationConstraint,
+		"upright":      newNamedUprightConstraint,
+		"workspace":    newNamedWorkspaceConstraint,
+		"plane":        newNamedPlaneConstraint,
+		"line":         newNamedLineConstraint,
+	}
+)
+
+// RegisterConstraint registers a constraint constructor under a type name, so that constraints of that type can be composed on a plan
+// through the `constraints` planning option. It panics if the type name is already registered.
+//
+// The `constraints` option is a list of objects, each with the `type` of a registered constraint, an optional `name` which defaults to
+// the type and must be unique within the plan, and the attributes of the constraint. For example, a linear motion keeping a tool
+// upright inside of a box is
+//
+//	"constraints": [
+//		{"type": "linear", "line_tolerance": 1},
+//		{"type": "upright", "tolerance": 0.1},
+//		{"type": "workspace", "geometry": {"type": "box", "x": 500, "y": 500, "z": 500}}
+//	]
+//
+// Paths must satisfy every constraint of a plan, those of its motion profile included, so where constraints return metrics to bring
+// poses back within them, the metrics are summed rather than one taking precedence over another. Constraints only constrain paths;
+// how close to its goal a plan must end is set by the motion profile and the `goal_tolerance` option alone.
+func RegisterConstraint(typeName string, constructor ConstraintConstructor) {
+	constraintsMu.Lock()
+	defer constraintsMu.Unlock()
+	if _, ok := constraints[typeName]; ok {
+		panic(errors.Errorf("trying to register two constraints with the same type %q", typeName))
+	}
+	if constructor == nil {
+		panic(errors.Errorf("cannot register a nil constraint constructor for %q", typeName))
+	}
+	constraints[typeName] = constructor
+}
+
+// constraintFor returns the constraint constructor registered under typeName.
+func constraintFor(typeName string) (ConstraintConstructor, error) {
+	constraintsMu.Lock()
+	defer constraintsMu.Unlock()
+	constructor, ok := constraints[typeName]
+	if !ok {
+		return nil, errors.Errorf("no constraint registered with the type %q", typeName)
+	}
+	return constructor, nil
+}
+
+// addNamedConstraints adds the constraints listed in the `constraints` planning option to opt, returning the metrics which bring poses
+// back within them.
+func addNamedConstraints(opt *plannerOptions, req ConstraintRequest, specs interface{}) ([]Metric, error) {
+	var attrsList []config.AttributeMap
+	switch specs := specs.(type) {
+	case []map[string]interface{}:
+		for _, spec := range specs {
+			attrsList = append(attrsList, spec)
+		}
+	case []interface{}:
+		for i, spec := range specs {
+			attrs, ok := spec.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("constraint %d must be an object, got %T", i, spec)
+			}
+			attrsList = append(attrsList, attrs)
+		}
+	default:
+		return nil, errors.Errorf("constraints must be a list of objects, got %T", specs)
+	}
+
+	existing := map[string]bool{}
+	for _, name := range opt.Constraints() {
+		existing[name] = true
+	}
+	var metrics []Metric
+	for _, attrs := range attrsList {
+		getter := attrs.Getter()
+		typeName := getter.GetString("type", "")
+		name := getter.GetString("name", typeName)
+		if err := getter.Err(); err != nil {
+			return nil, err
+		}
+		if typeName == "" {
+			return nil, errors.New("every constraint must have a type")
+		}
+		if existing[name] {
+			return nil, errors.Errorf("two constraints are named %q", name)
+		}
+		existing[name] = true
+		constructor, err := constraintFor(typeName)
+		if err != nil {
+			return nil, err
+		}
+		req.Attributes = attrs
+		constraint, metric, err := constructor(&req)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot build constraint %q", name)
+		}
+		opt.AddConstraint(name, constraint)
+		if metric != nil {
+			metrics = append(metrics, metric)
+		}
+	}
+	return metrics, nil
+}
+
+// combinePathDists returns the metric bringing poses back within all of the given constraints at once.
+func combinePathDists(pathDists []Metric) Metric {
+	if len(pathDists) == 1 {
+		return pathDists[0]
+	}
+	return CombineMetrics(pathDists...)
+}
+
+// getVector reads a vector given as a list of 3 numbers, returning def if it is not given.
+func getVector(attrs *config.AttributeGetter, name string, def r3.Vector) (r3.Vector, error) {
+	values := attrs.GetFloat64Slice(name)
+	if err := attrs.Err(); err != nil {
+		return r3.Vector{}, err
+	}
+	if values == nil {
+		return def, nil
+	}
+	if len(values) != 3 {
+		return r3.Vector{}, errors.Errorf("%s must have 3 values, got %d", name, len(values))
+	}
+	return r3.Vector{X: values[0], Y: values[1], Z: values[2]}, nil
+}
+
+// uprightAxis returns the axis to keep a frame upright about: the given one, or the way the frame points at the start of a motion.
+func uprightAxis(attrs *config.AttributeGetter, name string, from spatialmath.Pose) (r3.Vector, error) {
+	ov := from.Orientation().OrientationVectorRadians()
+	axis, err := getVector(attrs, name, r3.Vector{X: ov.OX, Y: ov.OY, Z: ov.OZ})
+	if err != nil {
+		return r3.Vector{}, err
+	}
+	if axis.Norm() == 0 {
+		return r3.Vector{}, errors.Errorf("%s cannot be zero", name)
+	}
+	return axis, nil
+}
+
+func newNamedLinearConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	linTol := attrs.GetFloat64("line_tolerance", defaultLinearDeviation)
+	orientTol := attrs.GetFloat64("orient_tolerance", defaultLinearDeviation)
+	if err := attrs.Err(); err != nil {
+		return nil, nil, err
+	}
+	constraint, pathDist := NewAbsoluteLinearInterpolatingConstraint(req.From, req.To, linTol, orientTol)
+	return constraint, pathDist, nil
+}
+
+func newNamedPseudolinearConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	tolerance := attrs.GetFloat64("tolerance", defaultPseudolinearTolerance)
+	if err := attrs.Err(); err != nil {
+		return nil, nil, err
+	}
+	constraint, pathDist := NewProportionalLinearInterpolatingConstraint(req.From, req.To, tolerance)
+	return constraint, pathDist, nil
+}
+
+func newNamedOrientationConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	tolerance := attrs.GetFloat64("tolerance", defaultOrientationDeviation)
+	if err := attrs.Err(); err != nil {
+		return nil, nil, err
+	}
+	constraint, pathDist := NewSlerpOrientationConstraint(req.From, req.To, tolerance)
+	return constraint, pathDist, nil
+}
+
+func newNamedUprightConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	tolerance := attrs.GetFloat64("tolerance", defaultUprightTolerance)
+	axis, err := uprightAxis(attrs, "axis", req.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	constraint, pathDist := NewUprightConstraint(axis, tolerance)
+	return constraint, pathDist, nil
+}
+
+func newNamedWorkspaceConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	if !attrs.Has("geometry") {
+		return nil, nil, errors.New("workspace constraint must have a geometry")
+	}
+	var workspaceConfig spatialmath.GeometryConfig
+	attrs.Decode("geometry", &workspaceConfig)
+	frameName := attrs.GetString("frame", req.Frame)
+	if err := attrs.Err(); err != nil {
+		return nil, nil, err
+	}
+	workspace, err := workspaceConfig.ParseConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	constraint, err := NewWorkspaceConstraint(req.FrameSystem, frameName, workspace)
+	return constraint, nil, err
+}
+
+func newNamedPlaneConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	normal, err := getVector(attrs, "normal", r3.Vector{Z: 1})
+	if err != nil {
+		return nil, nil, err
+	}
+	point, err := getVector(attrs, "point", req.From.Point())
+	if err != nil {
+		return nil, nil, err
+	}
+	writingAngle := attrs.GetFloat64("writing_angle", 0)
+	tolerance := attrs.GetFloat64("tolerance", defaultLinearDeviation)
+	if err := attrs.Err(); err != nil {
+		return nil, nil, err
+	}
+	if normal.Norm() == 0 {
+		return nil, nil, errors.New("normal cannot be zero")
+	}
+	constraint, pathDist := NewPlaneConstraint(normal, point, writingAngle, tolerance)
+	return constraint, pathDist, nil
+}
+
+func newNamedLineConstraint(req *ConstraintRequest) (Constraint, Metric, error) {
+	attrs := req.Attributes.Getter()
+	start, err := getVector(attrs, "start", req.From.Point())
+	if err != nil {
+		return nil, nil, err
+	}
+	end, err := getVector(attrs, "end", req.To.Point())
+	if err != nil {
+		return nil, nil, err
+	}
+	tolerance := attrs.GetFloat64("tolerance", defaultLinearDeviation)
+	if err := attrs.Err(); err != nil {
+		return nil, nil, err
+	}
+	constraint, pathDist := NewLineConstraint(start, end, tolerance)
+	return constraint, pathDist, nil
+}
@@ -0,0 +1,112 @@
+package motionplan
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestNamedConstraints(t *testing.T) {
+	// keeps the frame on the near side of a wall at y = 10
+	RegisterConstraint("test_wall", func(req *ConstraintRequest) (Constraint, Metric, error) {
+		attrs := req.Attributes.Getter()
+		wall := attrs.GetFloat64("y", 10)
+		if err := attrs.Err(); err != nil {
+			return nil, nil, err
+		}
+		metric := func(from, _ spatial.Pose) float64 {
+			return math.Pow(math.Max(0, from.Point().Y-wall), 2)
+		}
+		return func(ci *ConstraintInput) (bool, float64) {
+			if err := resolveInputsToPositions(ci); err != nil {
+				return false, 0
+			}
+			dist := metric(ci.EndPos, nil)
+			return dist == 0, dist
+		}, metric, nil
+	})
+	test.That(t, func() { RegisterConstraint("test_wall", nil) }, test.ShouldPanic)
+	test.That(t, func() { RegisterConstraint("test_nil", nil) }, test.ShouldPanic)
+
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	base, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -200, Max: 200}, {Min: -200, Max: 200}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(base, fs.World()), test.ShouldBeNil)
+	sFrames, err := fs.TracebackFrame(base)
+	test.That(t, err, test.ShouldBeNil)
+	seedMap := frame.StartPositions(fs)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	from := spatial.NewZeroPose()
+	to := spatial.NewPoseFromPoint(r3.Vector{X: 100})
+
+	opt, err := pm.plannerSetupFromMoveRequest(from, to, seedMap, nil, map[string]interface{}{
+		"constraints": []interface{}{
+			map[string]interface{}{"type": "line", "tolerance": 1},
+			map[string]interface{}{"type": "test_wall", "name": "wall", "y": 5},
+			map[string]interface{}{"type": "workspace", "geometry": map[string]interface{}{"type": "box", "x": 300, "y": 300, "z": 10}},
+		},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.Constraints(), test.ShouldContain, "line")
+	test.That(t, opt.Constraints(), test.ShouldContain, "wall")
+	test.That(t, opt.Constraints(), test.ShouldContain, "workspace")
+	// constrained paths are planned by CBiRRT alone, without a timed RRT* attempt first
+	test.That(t, opt.Fallback, test.ShouldBeNil)
+
+	// poses are brought back within the line and the wall at once
+	test.That(t, opt.pathDist(spatial.NewPoseFromPoint(r3.Vector{X: 50}), to), test.ShouldEqual, 0)
+	test.That(t, opt.pathDist(spatial.NewPoseFromPoint(r3.Vector{X: 50, Y: 3}), to), test.ShouldAlmostEqual, 2)
+	test.That(t, opt.pathDist(spatial.NewPoseFromPoint(r3.Vector{X: 50, Y: 8}), to), test.ShouldAlmostEqual, 7+9)
+	ok, _, failed := opt.CheckConstraints(&ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{0, 0}),
+		EndInput:   frame.FloatsToInputs([]float64{50, 0}),
+		Frame:      sf,
+	})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, failed, test.ShouldBeEmpty)
+	ok, _, _ = opt.CheckConstraints(&ConstraintInput{
+		StartInput: frame.FloatsToInputs([]float64{0, 0}),
+		EndInput:   frame.FloatsToInputs([]float64{180, 0}),
+		Frame:      sf,
+	})
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// constraints are combined with those of the motion profile
+	opt, err = pm.plannerSetupFromMoveRequest(from, to, seedMap, nil, map[string]interface{}{
+		"motion_profile": PseudolinearMotionProfile,
+		"constraints":    []map[string]interface{}{{"type": "test_wall", "y": 5}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.Constraints(), test.ShouldContain, defaultPseudolinearConstraintName)
+	test.That(t, opt.Constraints(), test.ShouldContain, "test_wall")
+	pseudolinear, pathDist := NewProportionalLinearInterpolatingConstraint(from, to, defaultPseudolinearTolerance)
+	test.That(t, pseudolinear, test.ShouldNotBeNil)
+	offLine := spatial.NewPoseFromPoint(r3.Vector{X: 50, Y: 8})
+	test.That(t, opt.pathDist(offLine, to), test.ShouldAlmostEqual, pathDist(offLine, to)+9)
+
+	for _, badOpts := range []map[string]interface{}{
+		{"constraints": "linear"},
+		{"constraints": []interface{}{"linear"}},
+		{"constraints": []interface{}{map[string]interface{}{"tolerance": 1}}},
+		{"constraints": []interface{}{map[string]interface{}{"type": "missing"}}},
+		{"constraints": []interface{}{map[string]interface{}{"type": "line"}, map[string]interface{}{"type": "line"}}},
+		{"constraints": []interface{}{map[string]interface{}{"type": "line", "name": defaultObstacleConstraintName}}},
+		{"constraints": []interface{}{map[string]interface{}{"type": "line", "start": []interface{}{1, 2}}}},
+		{"constraints": []interface{}{map[string]interface{}{"type": "workspace"}}},
+		{"constraints": []interface{}{map[string]interface{}{"type": "line"}}, "planning_alg": "rrtstar"},
+		{"constraints": []interface{}{map[string]interface{}{"type": "line"}}, "reuse_rrt_maps": true},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(from, to, seedMap, nil, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
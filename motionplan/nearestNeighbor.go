@@ -43,12 +43,16 @@ func kNearestNeighbors(planOpts *plannerOptions, rrtMap map[node]node, target []
 		allCosts = append(allCosts, &neighbor{dist: dist, node: node})
 	}
 	sort.Slice(allCosts, func(i, j int) bool {
+		iCost, jCost := allCosts[i].dist, allCosts[j].dist
 		if cn1, ok := allCosts[i].node.(*costNode); ok {
 			if cn2, ok := allCosts[j].node.(*costNode); ok {
-				return (allCosts[i].dist + cn1.cost) < (allCosts[j].dist + cn2.cost)
+				iCost, jCost = iCost+cn1.cost, jCost+cn2.cost
 			}
 		}
-		return allCosts[i].dist < allCosts[j].dist
+		if iCost != jCost {
+			return iCost < jCost
+		}
+		return nodeBefore(rrtMap, allCosts[i].node, allCosts[j].node)
 	})
 	return allCosts[:kNeighbors]
 }
@@ -70,7 +74,7 @@ func (nm *neighborManager) nearestNeighbor(
 			StartInput: seed,
 			EndInput:   k.Q(),
 		})
-		if dist < bestDist {
+		if dist < bestDist || (dist == bestDist && best != nil && nodeBefore(rrtMap, k, best)) {
 			bestDist = dist
 			best = k
 		}
@@ -78,6 +82,28 @@ func (nm *neighborManager) nearestNeighbor(
 	return best
 }
 
+// nodeBefore orders two nodes of an rrtMap the same way every time, to break ties between nodes which are as near as each other to
+// a target, as the order of iterating over the map would otherwise break them at random. Nodes are ordered by their inputs, then by
+// those of their parents in turn.
+func nodeBefore(rrtMap map[node]node, a, b node) bool {
+	for a != nil && b != nil {
+		if a == b {
+			return false
+		}
+		qa, qb := a.Q(), b.Q()
+		for i := 0; i < len(qa) && i < len(qb); i++ {
+			if qa[i].Value != qb[i].Value {
+				return qa[i].Value < qb[i].Value
+			}
+		}
+		if len(qa) != len(qb) {
+			return len(qa) < len(qb)
+		}
+		a, b = rrtMap[a], rrtMap[b]
+	}
+	return a == nil && b != nil
+}
+
 func (nm *neighborManager) parallelNearestNeighbor(
 	ctx context.Context,
 	planOpts *plannerOptions,
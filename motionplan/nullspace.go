@@ -0,0 +1,345 @@
+package motionplan
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// names of the secondary objectives which can be given weights in the `nullspace_objectives` planning option.
+const (
+	// prefer inputs near the middle of their limits.
+	JointCenterObjective = "joint_center"
+	// prefer inputs near the `preferred_posture` planning option, e.g. to keep the elbow of an arm up.
+	PostureObjective = "posture"
+	// prefer configurations where the end effector can move easily in every direction, away from singularities.
+	ManipulabilityObjective = "manipulability"
+)
+
+const (
+	// Number of steps to take through the nullspace of an IK solution.
+	defaultNullspaceIter = 20
+
+	// Largest change in any input in a single step through the nullspace.
+	defaultNullspaceStep = 0.1
+
+	// Number of Gauss-Newton steps taken to bring the end effector back after each step through the nullspace.
+	defaultDriftCorrections = 3
+
+	// Number of random moves through the nullspace to try to turn an IK solution which fails constraints into one which meets them.
+	defaultIKPerturbations = 5
+
+	// Largest change in any input in a random move through the nullspace.
+	defaultIKPerturbationStep = 0.3
+
+	// Number of degrees of freedom of a pose, the primary goal that secondary objectives must not disturb.
+	poseDoF = 6
+)
+
+// nullspaceObjective is a cost of the inputs to a frame, to be lowered by moving the frame without moving its end effector.
+type nullspaceObjective func([]float64) float64
+
+// newNullspaceObjective returns the weighted sum of the named secondary objectives for frame. Only frames with more degrees of
+// freedom than a pose, such as 7 DoF arms, can move without moving their end effector, so other frames are an error.
+func newNullspaceObjective(frame referenceframe.Frame, weights map[string]float64, posture []float64) (nullspaceObjective, error) {
+	limits := frame.DoF()
+	if len(limits) <= poseDoF {
+		return nil, errors.Errorf(
+			"nullspace objectives need a frame with more than %d degrees of freedom, %s has %d", poseDoF, frame.Name(), len(limits),
+		)
+	}
+	var objectives []nullspaceObjective
+	for name, weight := range weights {
+		if weight < 0 {
+			return nil, errors.Errorf("weight of nullspace objective %q cannot be negative", name)
+		}
+		w := weight
+		switch name {
+		case JointCenterObjective:
+			objectives = append(objectives, func(q []float64) float64 {
+				cost := 0.
+				for i, limit := range limits {
+					if math.IsInf(limit.Min, 0) || math.IsInf(limit.Max, 0) || limit.Max <= limit.Min {
+						continue
+					}
+					offset := (q[i] - (limit.Max+limit.Min)/2) / ((limit.Max - limit.Min) / 2)
+					cost += offset * offset
+				}
+				return w * cost
+			})
+		case PostureObjective:
+			if len(posture) != len(limits) {
+				return nil, errors.Errorf("got %d values of preferred_posture for %d degrees of freedom", len(posture), len(limits))
+			}
+			objectives = append(objectives, func(q []float64) float64 {
+				cost := 0.
+				for i, preferred := range posture {
+					cost += (q[i] - preferred) * (q[i] - preferred)
+				}
+				return w * cost
+			})
+		case ManipulabilityObjective:
+			objectives = append(objectives, func(q []float64) float64 {
+				jacobian, err := poseJacobian(frame, q)
+				if err != nil {
+					return math.Inf(1)
+				}
+				var jjt mat.Dense
+				jjt.Mul(jacobian, jacobian.T())
+				return -w * math.Sqrt(math.Max(mat.Det(&jjt), 0))
+			})
+		default:
+			return nil, errors.Errorf("unknown nullspace objective %q", name)
+		}
+	}
+	return func(q []float64) float64 {
+		cost := 0.
+		for _, objective := range objectives {
+			cost += objective(q)
+		}
+		return cost
+	}, nil
+}
+
+// nullspaceOptimize lowers the cost of an IK solution by the secondary objective by gradient descent through the nullspace of the
+// Jacobian of the frame, in which the inputs can change without moving the end effector to first order. Any drift of the end
+// effector from where the solution put it is corrected after each step, which is only kept if the solution still reaches the goal
+// as measured by metric.
+func nullspaceOptimize(
+	frame referenceframe.Frame,
+	solution []referenceframe.Input,
+	goal spatialmath.Pose,
+	metric Metric,
+	objective nullspaceObjective,
+) []referenceframe.Input {
+	limits := frame.DoF()
+	q := referenceframe.InputsToFloats(solution)
+	cost := objective(q)
+	// the whole pose of the end effector is held, even if the goal is only a position
+	target, err := frame.Transform(solution)
+	if err != nil {
+		return solution
+	}
+	reachesGoal := func(q []float64) bool {
+		pose, err := frame.Transform(referenceframe.FloatsToInputs(q))
+		return err == nil && metric(pose, goal) < defaultEpsilon*defaultEpsilon
+	}
+
+	stepSize := defaultNullspaceStep
+	for iter := 0; iter < defaultNullspaceIter && stepSize > defaultEpsilon; iter++ {
+		jacobian, err := poseJacobian(frame, q)
+		if err != nil {
+			break
+		}
+		gradient := make([]float64, len(q))
+		for i := range q {
+			x := append([]float64{}, q...)
+			x[i] = q[i] + defaultEpsilon
+			above := objective(x)
+			x[i] = q[i] - defaultEpsilon
+			gradient[i] = (above - objective(x)) / (2 * defaultEpsilon)
+		}
+		// the part of the gradient in the nullspace is what remains after removing its projection onto the rows of the Jacobian
+		rowPart, ok := rightPseudoInverseMul(jacobian, mat.NewVecDense(len(gradient), gradient), jacobian)
+		if !ok {
+			break
+		}
+		direction := make([]float64, len(q))
+		largest := 0.
+		for i := range direction {
+			direction[i] = rowPart.AtVec(i) - gradient[i]
+			largest = math.Max(largest, math.Abs(direction[i]))
+		}
+		if largest < defaultEpsilon*defaultEpsilon {
+			break
+		}
+
+		for stepSize > defaultEpsilon {
+			candidate := make([]float64, len(q))
+			for i := range q {
+				candidate[i] = clampToLimit(q[i]+stepSize*direction[i]/largest, limits[i])
+			}
+			for i := 0; i < defaultDriftCorrections; i++ {
+				candidate = correctDrift(frame, candidate, target)
+			}
+			if candidateCost := objective(candidate); candidateCost < cost && reachesGoal(candidate) {
+				q = candidate
+				cost = candidateCost
+				break
+			}
+			stepSize /= 2
+		}
+	}
+	return referenceframe.FloatsToInputs(q)
+}
+
+// perturbInNullspace returns a random move of a solution through the nullspace of the Jacobian of its frame, which still reaches the
+// goal as measured by metric, or nil if the move does not reach the goal or the frame cannot move without moving its end effector there.
+// Redundant frames such as 7 DoF arms always have a nullspace to move through, and other arms have one at their singularities.
+func perturbInNullspace(
+	frame referenceframe.Frame,
+	solution []referenceframe.Input,
+	goal spatialmath.Pose,
+	metric Metric,
+	randseed *rand.Rand,
+) []referenceframe.Input {
+	limits := frame.DoF()
+	q := referenceframe.InputsToFloats(solution)
+	target, err := frame.Transform(solution)
+	if err != nil {
+		return nil
+	}
+	jacobian, err := poseJacobian(frame, q)
+	if err != nil {
+		return nil
+	}
+	basis := nullspaceBasis(jacobian)
+	if basis == nil {
+		return nil
+	}
+	_, dims := basis.Dims()
+	random := make([]float64, dims)
+	for i := range random {
+		random[i] = 2*randseed.Float64() - 1
+	}
+	var direction mat.VecDense
+	direction.MulVec(basis, mat.NewVecDense(dims, random))
+	largest := mat.Norm(&direction, math.Inf(1))
+	if largest < defaultEpsilon {
+		return nil
+	}
+	perturbed := make([]float64, len(q))
+	for i := range q {
+		perturbed[i] = clampToLimit(q[i]+defaultIKPerturbationStep*direction.AtVec(i)/largest, limits[i])
+	}
+	for i := 0; i < defaultDriftCorrections; i++ {
+		perturbed = correctDrift(frame, perturbed, target)
+	}
+	pose, err := frame.Transform(referenceframe.FloatsToInputs(perturbed))
+	if err != nil || metric(pose, goal) >= defaultEpsilon*defaultEpsilon {
+		return nil
+	}
+	return referenceframe.FloatsToInputs(perturbed)
+}
+
+// hasNullspace returns whether the inputs of a frame can change at q without moving its end effector, to first order.
+func hasNullspace(frame referenceframe.Frame, q []referenceframe.Input) bool {
+	jacobian, err := poseJacobian(frame, referenceframe.InputsToFloats(q))
+	return err == nil && nullspaceBasis(jacobian) != nil
+}
+
+// nullspaceBasis returns a matrix whose columns span the nullspace of a Jacobian, or nil if it only has the zero vector. Singular values
+// below defaultEpsilon are counted as zero, so that a Jacobian close to losing rank has a nullspace as well.
+func nullspaceBasis(jacobian *mat.Dense) *mat.Dense {
+	_, cols := jacobian.Dims()
+	var svd mat.SVD
+	if !svd.Factorize(jacobian, mat.SVDFull) {
+		return nil
+	}
+	rank := jacobianRank(&svd)
+	if rank >= cols {
+		return nil
+	}
+	var v mat.Dense
+	svd.VTo(&v)
+	return mat.DenseCopyOf(v.Slice(0, cols, rank, cols))
+}
+
+// jacobianRank returns the number of singular values of a factorized Jacobian above defaultEpsilon. The tolerance is not relative to the
+// largest singular value as the rows for the change in position, in mm, are far larger than those for the change in orientation.
+func jacobianRank(svd *mat.SVD) int {
+	rank := 0
+	for _, value := range svd.Values(nil) {
+		if value > defaultEpsilon {
+			rank++
+		}
+	}
+	return rank
+}
+
+// correctDrift moves inputs which have drifted from putting the end effector at a target pose back towards it with a Gauss-Newton step.
+// The step is the least squares one, so that it is also taken when the Jacobian has lost rank.
+func correctDrift(frame referenceframe.Frame, q []float64, target spatialmath.Pose) []float64 {
+	pose, err := frame.Transform(referenceframe.FloatsToInputs(q))
+	if err != nil {
+		return q
+	}
+	jacobian, err := poseJacobian(frame, q)
+	if err != nil {
+		return q
+	}
+	var svd mat.SVD
+	if !svd.Factorize(jacobian, mat.SVDThin) {
+		return q
+	}
+	rank := jacobianRank(&svd)
+	if rank == 0 {
+		return q
+	}
+	var step mat.VecDense
+	svd.SolveVecTo(&step, mat.NewVecDense(poseDoF, poseDiff(pose, target)), rank)
+	corrected := make([]float64, len(q))
+	for i := range q {
+		corrected[i] = clampToLimit(q[i]+step.AtVec(i), frame.DoF()[i])
+	}
+	return corrected
+}
+
+// rightPseudoInverseMul returns J^T (J J^T)^-1 v for a Jacobian J with full row rank, where v is first multiplied by pre if given.
+// The bool is false if J does not have full row rank.
+func rightPseudoInverseMul(jacobian *mat.Dense, v *mat.VecDense, pre *mat.Dense) (*mat.VecDense, bool) {
+	if pre != nil {
+		var projected mat.VecDense
+		projected.MulVec(pre, v)
+		v = &projected
+	}
+	var jjt mat.Dense
+	jjt.Mul(jacobian, jacobian.T())
+	var solved mat.VecDense
+	if err := solved.SolveVec(&jjt, v); err != nil {
+		return nil, false
+	}
+	var result mat.VecDense
+	result.MulVec(jacobian.T(), &solved)
+	return &result, true
+}
+
+// poseJacobian returns the Jacobian of the pose of frame with respect to its inputs at q by finite differences, with rows for the
+// change in position followed by the change in orientation as an R3 axis angle.
+func poseJacobian(frame referenceframe.Frame, q []float64) (*mat.Dense, error) {
+	pose, err := frame.Transform(referenceframe.FloatsToInputs(q))
+	if err != nil {
+		return nil, err
+	}
+	jacobian := mat.NewDense(poseDoF, len(q), nil)
+	x := append([]float64{}, q...)
+	for i := range q {
+		x[i] = q[i] + defaultEpsilon
+		moved, err := frame.Transform(referenceframe.FloatsToInputs(x))
+		x[i] = q[i]
+		if err != nil {
+			return nil, err
+		}
+		for row, diff := range poseDiff(pose, moved) {
+			jacobian.Set(row, i, diff/defaultEpsilon)
+		}
+	}
+	return jacobian, nil
+}
+
+// poseDiff returns the change from one pose to another as a change in position followed by a change in orientation.
+func poseDiff(from, to spatialmath.Pose) []float64 {
+	delta := spatialmath.PoseDelta(from, to)
+	point := delta.Point()
+	orientation := spatialmath.QuatToR3AA(delta.Orientation().Quaternion())
+	return []float64{point.X, point.Y, point.Z, orientation.X, orientation.Y, orientation.Z}
+}
+
+func clampToLimit(value float64, limit referenceframe.Limit) float64 {
+	return math.Max(limit.Min, math.Min(limit.Max, value))
+}
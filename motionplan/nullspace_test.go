@@ -0,0 +1,92 @@
+package motionplan
+
+import (
+	"math/rand"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/utils"
+)
+
+func TestNullspaceOptimize(t *testing.T) {
+	xarm, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm7_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	// a 6 DoF arm cannot move without moving its end effector
+	_, err = newNullspaceObjective(ur5e, map[string]float64{JointCenterObjective: 1}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = newNullspaceObjective(xarm, map[string]float64{"elbow": 1}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = newNullspaceObjective(xarm, map[string]float64{PostureObjective: 1}, []float64{0, 0})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	solution := referenceframe.FloatsToInputs([]float64{0.3, 0.5, -0.4, 1, 0.2, 0.8, 0.1})
+	goal, err := xarm.Transform(solution)
+	test.That(t, err, test.ShouldBeNil)
+	metric := NewSquaredNormMetric()
+
+	for _, tc := range []struct {
+		weights map[string]float64
+		posture []float64
+	}{
+		{map[string]float64{JointCenterObjective: 1}, nil},
+		{map[string]float64{PostureObjective: 1}, []float64{0.5, 0.5, 0.5, 1, 0, 0.8, 0}},
+		{map[string]float64{ManipulabilityObjective: 1e-6, JointCenterObjective: 1}, nil},
+	} {
+		objective, err := newNullspaceObjective(xarm, tc.weights, tc.posture)
+		test.That(t, err, test.ShouldBeNil)
+		optimized := nullspaceOptimize(xarm, solution, goal, metric, objective)
+
+		// the end effector stays at the goal while the secondary objective improves
+		pose, err := xarm.Transform(optimized)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, metric(pose, goal), test.ShouldBeLessThan, defaultEpsilon*defaultEpsilon)
+		test.That(t, objective(referenceframe.InputsToFloats(optimized)), test.ShouldBeLessThan,
+			objective(referenceframe.InputsToFloats(solution)))
+	}
+}
+
+func TestPerturbInNullspace(t *testing.T) {
+	xarm, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm7_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	solution := referenceframe.FloatsToInputs([]float64{0.3, 0.5, -0.4, 1, 0.2, 0.8, 0.1})
+	goal, err := xarm.Transform(solution)
+	test.That(t, err, test.ShouldBeNil)
+	metric := NewSquaredNormMetric()
+
+	perturbed := perturbInNullspace(xarm, solution, goal, metric, rand.New(rand.NewSource(1)))
+	test.That(t, perturbed, test.ShouldNotBeNil)
+	test.That(t, perturbed, test.ShouldNotResemble, solution)
+	pose, err := xarm.Transform(perturbed)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, metric(pose, goal), test.ShouldBeLessThan, defaultEpsilon*defaultEpsilon)
+}
+
+func TestPerturbInNullspaceAtSingularity(t *testing.T) {
+	ur5e, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	metric := NewSquaredNormMetric()
+
+	// away from singularities a 6 DoF arm has no nullspace to move through
+	solution := referenceframe.FloatsToInputs([]float64{0.3, -1, 1.2, -0.5, 0.8, 0.1})
+	test.That(t, hasNullspace(ur5e, solution), test.ShouldBeFalse)
+	goal, err := ur5e.Transform(solution)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, perturbInNullspace(ur5e, solution, goal, metric, rand.New(rand.NewSource(1))), test.ShouldBeNil)
+
+	// with its wrist straight, turning the first and last wrist joints against each other leaves the end effector where it is
+	solution = referenceframe.FloatsToInputs([]float64{0.3, -1, 1.2, -0.5, 0, 0.1})
+	test.That(t, hasNullspace(ur5e, solution), test.ShouldBeTrue)
+	goal, err = ur5e.Transform(solution)
+	test.That(t, err, test.ShouldBeNil)
+	perturbed := perturbInNullspace(ur5e, solution, goal, metric, rand.New(rand.NewSource(1)))
+	test.That(t, perturbed, test.ShouldNotBeNil)
+	test.That(t, perturbed, test.ShouldNotResemble, solution)
+	pose, err := ur5e.Transform(perturbed)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, metric(pose, goal), test.ShouldBeLessThan, defaultEpsilon*defaultEpsilon)
+}
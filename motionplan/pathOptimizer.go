@@ -0,0 +1,262 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// default number of steps an optimized path is divided into.
+	defaultOptimizeSteps = 20
+
+	// default number of gradient descent iterations to optimize a path for.
+	defaultOptimizeIter = 100
+
+	// default distance in mm from obstacles within which an optimized path is pushed away from them.
+	defaultOptimizeClearance = 50.
+
+	// how much more keeping clear of obstacles matters than keeping a path short when optimizing it.
+	optimizeClearanceWeight = 10.
+
+	// smallest step size to try before an optimization is considered to have converged.
+	minOptimizeStepSize = 1e-6
+)
+
+// clearanceFunc returns how far the geometries of a frame at the given inputs are from the nearest obstacle.
+type clearanceFunc func([]referenceframe.Input) float64
+
+// newClearanceFunc returns a clearanceFunc measuring the distances from the geometries of frame to the obstacles of worldState. As in
+// collision constraints, pairs which are already in collision at observationInput, being closer than collisionBufferMM, are ignored.
+// If there are no other pairs the clearance is always infinite.
+func newClearanceFunc(
+	frame referenceframe.Frame,
+	fs referenceframe.FrameSystem,
+	worldState *referenceframe.WorldState,
+	observationInput map[string][]referenceframe.Input,
+	collisionBufferMM float64,
+) (clearanceFunc, error) {
+	worldState, err := worldState.ToWorldFrame(fs, observationInput)
+	if err != nil {
+		return nil, err
+	}
+	// can use zeroth element of worldState.Obstacles because ToWorldFrame returns only one GeometriesInFrame
+	obstacles := worldState.Obstacles[0].Geometries()
+
+	var goodInputs []referenceframe.Input
+	switch f := frame.(type) {
+	case *solverFrame:
+		goodInputs, err = f.mapToSlice(observationInput)
+	default:
+		goodInputs, err = referenceframe.GetFrameInputs(f, observationInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+	zeroVols, err := frame.Geometries(goodInputs)
+	if err != nil && zeroVols == nil {
+		return nil, err
+	}
+	ignored := map[string]map[string]bool{}
+	for name, geometry := range zeroVols.Geometries() {
+		ignored[name] = map[string]bool{}
+		for obstacleName, obstacle := range obstacles {
+			distance, err := geometry.DistanceFrom(obstacle)
+			if err != nil || distance <= collisionBufferMM {
+				ignored[name][obstacleName] = true
+			}
+		}
+	}
+
+	return func(inputs []referenceframe.Input) float64 {
+		clearance := math.Inf(1)
+		geometries, err := frame.Geometries(inputs)
+		if err != nil && geometries == nil {
+			return clearance
+		}
+		for name, geometry := range geometries.Geometries() {
+			for obstacleName, obstacle := range obstacles {
+				if ignored[name][obstacleName] {
+					continue
+				}
+				if distance, err := geometry.DistanceFrom(obstacle); err == nil {
+					clearance = math.Min(clearance, distance)
+				}
+			}
+		}
+		return clearance
+	}, nil
+}
+
+// optimizePath improves a path found by a planner by gradient descent over its waypoints, in the style of CHOMP. The path is first
+// divided into evenly sized steps, whose waypoints are then moved to minimize the sum of the squared lengths of the steps, which
+// favors short paths with evenly spread waypoints, plus a cost for each waypoint closer to an obstacle than the OptimizeClearance of the
+// planner's options. The ends of the path stay fixed, and a descent step is only taken if the path it makes still meets every
+// constraint of the planner, so the optimized path is always at least as valid as the one given.
+func optimizePath(ctx context.Context, mp motionPlanner, frame referenceframe.Frame, path []node) []node {
+	opt := mp.opt()
+	if !opt.OptimizePath || len(path) < 2 {
+		return path
+	}
+	qs := divideSteps(path, opt.OptimizeSteps)
+	if len(qs) < 3 {
+		return path
+	}
+	limits := frame.DoF()
+
+	obstacleCost := func(q []float64) float64 {
+		if opt.clearance == nil {
+			return 0
+		}
+		clearance := opt.clearance(referenceframe.FloatsToInputs(q))
+		if clearance >= opt.OptimizeClearance {
+			return 0
+		}
+		if clearance < 0 {
+			return optimizeClearanceWeight * (opt.OptimizeClearance/2 - clearance)
+		}
+		return optimizeClearanceWeight * (opt.OptimizeClearance - clearance) * (opt.OptimizeClearance - clearance) / (2 * opt.OptimizeClearance)
+	}
+	cost := func(qs [][]float64) float64 {
+		total := 0.
+		for i := 1; i < len(qs); i++ {
+			for j := range qs[i] {
+				total += (qs[i][j] - qs[i-1][j]) * (qs[i][j] - qs[i-1][j])
+			}
+			if i < len(qs)-1 {
+				total += obstacleCost(qs[i])
+			}
+		}
+		return total
+	}
+	valid := func(qs [][]float64) bool {
+		for i := 1; i < len(qs); i++ {
+			if !mp.checkPath(referenceframe.FloatsToInputs(qs[i-1]), referenceframe.FloatsToInputs(qs[i])) {
+				return false
+			}
+		}
+		return true
+	}
+
+	current := cost(qs)
+	stepSize := 1.
+	for iter := 0; iter < opt.OptimizeIter && stepSize > minOptimizeStepSize; iter++ {
+		select {
+		case <-ctx.Done():
+			return floatsToNodes(qs)
+		default:
+		}
+
+		// the gradient of the squared step lengths is found exactly, and that of the obstacle cost by central differences
+		gradient := make([][]float64, len(qs))
+		for i := 1; i < len(qs)-1; i++ {
+			gradient[i] = make([]float64, len(qs[i]))
+			for j := range qs[i] {
+				gradient[i][j] = 2 * (2*qs[i][j] - qs[i-1][j] - qs[i+1][j])
+				if opt.clearance == nil {
+					continue
+				}
+				q := append([]float64{}, qs[i]...)
+				q[j] = qs[i][j] + defaultEpsilon
+				above := obstacleCost(q)
+				q[j] = qs[i][j] - defaultEpsilon
+				below := obstacleCost(q)
+				gradient[i][j] += (above - below) / (2 * defaultEpsilon)
+			}
+		}
+
+		// backtrack until a descent step lowers the cost of the path without breaking any constraints
+		for stepSize > minOptimizeStepSize {
+			candidate := make([][]float64, len(qs))
+			candidate[0] = qs[0]
+			candidate[len(qs)-1] = qs[len(qs)-1]
+			for i := 1; i < len(qs)-1; i++ {
+				candidate[i] = make([]float64, len(qs[i]))
+				for j := range qs[i] {
+					candidate[i][j] = math.Max(limits[j].Min, math.Min(limits[j].Max, qs[i][j]-stepSize*gradient[i][j]))
+				}
+			}
+			if candidateCost := cost(candidate); candidateCost < current && valid(candidate) {
+				qs = candidate
+				current = candidateCost
+				stepSize *= 1.5
+				break
+			}
+			stepSize /= 2
+		}
+	}
+	return floatsToNodes(qs)
+}
+
+// planCost returns the cost of a plan, used to choose between plans: its length as measured by the distance function of opt, plus, if
+// opt has a ClearanceWeight, a soft cost for passing closer to obstacles than its OptimizeClearance. That cost is the weight times how
+// many mm too close the plan is, integrated over the length of the plan, so that of two plans of about the same length the one keeping
+// clear of obstacles costs less, while a much shorter plan still costs less than a long detour. A SoftLimitWeight likewise adds the
+// cost of inputs nearing their limits.
+func planCost(plan [][]referenceframe.Input, opt *plannerOptions) float64 {
+	cost := EvaluatePlan(plan, opt.DistanceFunc)
+	if len(plan) < 2 {
+		return cost
+	}
+	if opt.ClearanceWeight > 0 && opt.clearance != nil {
+		cost += opt.ClearanceWeight * integratePenalty(plan, opt, func(q []float64) float64 {
+			return math.Max(0, opt.OptimizeClearance-opt.clearance(referenceframe.FloatsToInputs(q)))
+		})
+	}
+	if opt.SoftLimitWeight > 0 && opt.jointLimits != nil {
+		cost += opt.SoftLimitWeight * integratePenalty(plan, opt, softLimitProximity(opt.jointLimits, opt.SoftLimitZonePercent))
+	}
+	return cost
+}
+
+// integratePenalty integrates a penalty over the length of a plan, as measured by the distance function of opt.
+func integratePenalty(plan [][]referenceframe.Input, opt *plannerOptions, penaltyAt func([]float64) float64) float64 {
+	// the plan is divided up so that what is passed by partway along a long step is not missed
+	qs := divideSteps(stepsToNodes(plan), opt.OptimizeSteps)
+	penalty := 0.
+	last := penaltyAt(qs[0])
+	for i := 1; i < len(qs); i++ {
+		next := penaltyAt(qs[i])
+		_, length := opt.DistanceFunc(&ConstraintInput{
+			StartInput: referenceframe.FloatsToInputs(qs[i-1]),
+			EndInput:   referenceframe.FloatsToInputs(qs[i]),
+		})
+		penalty += length * (last + next) / 2
+		last = next
+	}
+	return penalty
+}
+
+// divideSteps returns the waypoints of path divided up into roughly numSteps steps of similar length in input space.
+func divideSteps(path []node, numSteps int) [][]float64 {
+	total := 0.
+	for i := 1; i < len(path); i++ {
+		total += inputDist(path[i-1].Q(), path[i].Q())
+	}
+	qs := [][]float64{referenceframe.InputsToFloats(path[0].Q())}
+	for i := 1; i < len(path); i++ {
+		pieces := 1
+		if total > 0 {
+			pieces = int(math.Ceil(inputDist(path[i-1].Q(), path[i].Q()) / total * float64(numSteps)))
+		}
+		for p := 1; p <= pieces; p++ {
+			interp := referenceframe.InterpolateInputs(path[i-1].Q(), path[i].Q(), float64(p)/float64(pieces))
+			qs = append(qs, referenceframe.InputsToFloats(interp))
+		}
+	}
+	return qs
+}
+
+func inputDist(from, to []referenceframe.Input) float64 {
+	_, dist := defaultDistanceFunc(&ConstraintInput{StartInput: from, EndInput: to})
+	return dist
+}
+
+func floatsToNodes(qs [][]float64) []node {
+	nodes := make([]node, 0, len(qs))
+	for _, q := range qs {
+		nodes = append(nodes, &basicNode{q: referenceframe.FloatsToInputs(q)})
+	}
+	return nodes
+}
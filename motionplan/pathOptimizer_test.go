@@ -0,0 +1,145 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestOptimizePath(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 20, Y: 20, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-60, -20})}
+
+	opt := newBasicPlannerOptions()
+	collisionConstraint, err := newObstacleConstraint(model, fs, worldState, seedMap, nil, false, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	opt.AddConstraint(defaultObstacleConstraintName, collisionConstraint)
+	mp, err := newCBiRRTMotionPlanner(model, rand.New(rand.NewSource(1)), logger.Sugar(), opt)
+	test.That(t, err, test.ShouldBeNil)
+
+	// a path which zigzags, passing just by the obstacle
+	path := stepsToNodes([][]frame.Input{
+		frame.FloatsToInputs([]float64{-60, -20}),
+		frame.FloatsToInputs([]float64{-30, -40}),
+		frame.FloatsToInputs([]float64{0, -17}),
+		frame.FloatsToInputs([]float64{30, -40}),
+		frame.FloatsToInputs([]float64{60, -20}),
+	})
+	test.That(t, optimizePath(context.Background(), mp, model, path), test.ShouldResemble, path)
+
+	opt.OptimizePath = true
+	opt.clearance, err = newClearanceFunc(model, fs, worldState, seedMap, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.clearance(path[2].Q()), test.ShouldAlmostEqual, 2)
+	// obstacles already within the collision buffer of the frame where it starts are not kept clear of, as they are not collided with
+	bufferedClearance, err := newClearanceFunc(model, fs, worldState, seedMap, 50)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, math.IsInf(bufferedClearance(path[2].Q()), 1), test.ShouldBeTrue)
+	optimized := optimizePath(context.Background(), mp, model, path)
+	test.That(t, optimized[0].Q(), test.ShouldResemble, path[0].Q())
+	test.That(t, optimized[len(optimized)-1].Q(), test.ShouldResemble, path[len(path)-1].Q())
+
+	length := func(path []node) float64 {
+		total := 0.
+		for i := 1; i < len(path); i++ {
+			total += inputDist(path[i-1].Q(), path[i].Q())
+		}
+		return total
+	}
+	minClearance := math.Inf(1)
+	for i, step := range optimized {
+		minClearance = math.Min(minClearance, opt.clearance(step.Q()))
+		if i > 0 {
+			test.That(t, mp.checkPath(optimized[i-1].Q(), step.Q()), test.ShouldBeTrue)
+		}
+	}
+	test.That(t, minClearance, test.ShouldBeGreaterThan, 2)
+
+	// with no obstacles to keep clear of, the zigzags are straightened out
+	opt.clearance = nil
+	optimized = optimizePath(context.Background(), mp, model, path)
+	test.That(t, length(optimized), test.ShouldBeLessThan, length(path))
+}
+
+func TestPlanCost(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 20, Y: 20, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-60, -20})}
+
+	// a path skimming past the obstacle, and one a little longer keeping well clear of it
+	skimming := [][]frame.Input{frame.FloatsToInputs([]float64{-60, -20}), frame.FloatsToInputs([]float64{60, -20})}
+	wide := [][]frame.Input{
+		frame.FloatsToInputs([]float64{-60, -20}),
+		frame.FloatsToInputs([]float64{0, -40}),
+		frame.FloatsToInputs([]float64{60, -20}),
+	}
+	opt := newBasicPlannerOptions()
+	test.That(t, planCost(skimming, opt), test.ShouldAlmostEqual, 120)
+	test.That(t, planCost(wide, opt), test.ShouldBeGreaterThan, planCost(skimming, opt))
+
+	opt.ClearanceWeight = 0.1
+	opt.clearance, err = newClearanceFunc(model, fs, worldState, seedMap, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, planCost(skimming, opt), test.ShouldBeGreaterThan, EvaluatePlan(skimming, opt.DistanceFunc))
+	test.That(t, planCost(wide, opt), test.ShouldBeLessThan, planCost(skimming, opt))
+	// the path keeping clear still costs something, as it does not keep OptimizeClearance away the whole way
+	test.That(t, planCost(wide, opt), test.ShouldBeGreaterThan, EvaluatePlan(wide, opt.DistanceFunc))
+
+	// with clearance weighted lightly, a detour much longer than it is clearer costs more
+	opt.ClearanceWeight = 0.01
+	detour := [][]frame.Input{
+		frame.FloatsToInputs([]float64{-60, -20}),
+		frame.FloatsToInputs([]float64{-60, -100}),
+		frame.FloatsToInputs([]float64{60, -100}),
+		frame.FloatsToInputs([]float64{60, -20}),
+	}
+	test.That(t, planCost(detour, opt), test.ShouldBeGreaterThan, planCost(wide, opt))
+
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 60, Y: -20})
+	opt, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal, seedMap, worldState, map[string]interface{}{
+		"clearance_weight": 0.1,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.clearance, test.ShouldNotBeNil)
+	for _, badOpts := range []map[string]interface{}{
+		{"clearance_weight": -1},
+		{"clearance_weight": 0.1, "optimize_clearance": 0},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal, seedMap, worldState, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
@@ -0,0 +1,211 @@
+package motionplan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The number of plans kept in memory for reuse. The least recently used one is dropped to make room for a new one.
+const defaultPlanCacheSize = 32
+
+// plans holds recently found plans, so that a planning problem that has already been solved is not solved again.
+var plans = newLRUCache(defaultPlanCacheSize)
+
+// StorageDirs are the directories plans and the RRT maps grown while planning are kept in, so that they outlast a restart. They are
+// part of the configuration of the robot, such as the attributes of the motion service, rather than options of a plan, as those may
+// come from any client, and are passed to a planning call with its context by ContextWithStorageDirs.
+type StorageDirs struct {
+	PlanCacheDir string
+	RRTMapDir    string
+}
+
+type storageDirsKey struct{}
+
+// ContextWithStorageDirs returns a context planning with which keeps plans and RRT maps in the given directories. Those left empty,
+// or planning with a context without any, are kept in memory only.
+func ContextWithStorageDirs(ctx context.Context, dirs StorageDirs) context.Context {
+	return context.WithValue(ctx, storageDirsKey{}, dirs)
+}
+
+// storageDirsFromContext returns the directories set on a context with ContextWithStorageDirs.
+func storageDirsFromContext(ctx context.Context) StorageDirs {
+	dirs, _ := ctx.Value(storageDirsKey{}).(StorageDirs)
+	return dirs
+}
+
+// planCacheKey identifies a planning problem: the frame being moved, the frame system it moves in and where that starts, the goal, the
+// options it is planned with, and the obstacles of the world state. The option that turns the cache on is left out.
+func planCacheKey(
+	sf *solverFrame,
+	seedMap map[string][]referenceframe.Input,
+	goal spatialmath.Pose,
+	worldState *referenceframe.WorldState,
+	motionConfig map[string]interface{},
+) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s %v\n", sf.Name(), sf.goalFrame.Name(), sf.DoF())
+	if err := writeFrameSystem(hash, sf.fss); err != nil {
+		return "", err
+	}
+	frames := make([]string, 0, len(seedMap))
+	for name := range seedMap {
+		frames = append(frames, name)
+	}
+	sort.Strings(frames)
+	for _, name := range frames {
+		fmt.Fprintf(hash, "%s %v\n", name, referenceframe.InputsToFloats(seedMap[name]))
+	}
+
+	goalBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(spatialmath.PoseToProtobuf(goal))
+	if err != nil {
+		return "", err
+	}
+	hash.Write(goalBytes)
+
+	options := make(map[string]interface{}, len(motionConfig))
+	for name, value := range motionConfig {
+		if name != "use_plan_cache" {
+			options[name] = value
+		}
+	}
+	// maps are marshalled with sorted keys, so equal options always give the same bytes
+	optionBytes, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+	hash.Write(optionBytes)
+
+	if worldState != nil {
+		wsPb, err := referenceframe.WorldStateToProtobuf(worldState)
+		if err != nil {
+			return "", err
+		}
+		wsBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(wsPb)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(wsBytes)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeFrameSystem writes every frame of a frame system to w, each with its parent, its serialized model, and where it and its geometries
+// are with all of its inputs zero, so that plans are not reused once the frames they were planned for change.
+func writeFrameSystem(w io.Writer, fs referenceframe.FrameSystem) error {
+	names := fs.FrameNames()
+	sort.Strings(names)
+	marshal := proto.MarshalOptions{Deterministic: true}
+	for _, name := range names {
+		f := fs.Frame(name)
+		parentName := ""
+		if parent, err := fs.Parent(f); err == nil {
+			parentName = parent.Name()
+		}
+		fmt.Fprintf(w, "%s %s %T %v\n", name, parentName, f, f.DoF())
+		// not every kind of frame can be serialized, and those which cannot are still told apart by where they place things
+		if frameBytes, err := json.Marshal(f); err == nil {
+			if _, err := w.Write(frameBytes); err != nil {
+				return err
+			}
+		}
+		zero := make([]referenceframe.Input, len(f.DoF()))
+		if pose, err := f.Transform(zero); err == nil {
+			poseBytes, err := marshal.Marshal(spatialmath.PoseToProtobuf(pose))
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(poseBytes); err != nil {
+				return err
+			}
+		}
+		geometries, err := f.Geometries(zero)
+		if err != nil || geometries == nil {
+			continue
+		}
+		geometryNames := make([]string, 0, len(geometries.Geometries()))
+		for geometryName := range geometries.Geometries() {
+			geometryNames = append(geometryNames, geometryName)
+		}
+		sort.Strings(geometryNames)
+		for _, geometryName := range geometryNames {
+			geometryBytes, err := marshal.Marshal(geometries.Geometries()[geometryName].ToProtobuf())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s ", geometryName)
+			if _, err := w.Write(geometryBytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cachedPlan returns the plan cached for key, looking for it in dir if it is not in memory and dir is not empty.
+func cachedPlan(key, dir string) ([][]referenceframe.Input, bool, error) {
+	if plan, ok := plans.get(key); ok {
+		return copyPlan(plan.([][]referenceframe.Input)), true, nil
+	}
+	if dir == "" {
+		return nil, false, nil
+	}
+	//nolint:gosec
+	planBytes, err := os.ReadFile(filepath.Join(dir, key+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	var steps [][]float64
+	if err := json.Unmarshal(planBytes, &steps); err != nil {
+		return nil, false, err
+	}
+	plan := make([][]referenceframe.Input, 0, len(steps))
+	for _, step := range steps {
+		plan = append(plan, referenceframe.FloatsToInputs(step))
+	}
+	plans.put(key, plan)
+	return copyPlan(plan), true, nil
+}
+
+// cachePlan caches plan for key, also writing it to dir if dir is not empty.
+func cachePlan(key, dir string, plan [][]referenceframe.Input) error {
+	plans.put(key, copyPlan(plan))
+	if dir == "" {
+		return nil
+	}
+	steps := make([][]float64, 0, len(plan))
+	for _, step := range plan {
+		steps = append(steps, referenceframe.InputsToFloats(step))
+	}
+	planBytes, err := json.Marshal(steps)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, key+".json"), planBytes, 0o600)
+}
+
+// copyPlan returns a copy of plan that can be changed without changing plan.
+func copyPlan(plan [][]referenceframe.Input) [][]referenceframe.Input {
+	planCopy := make([][]referenceframe.Input, 0, len(plan))
+	for _, step := range plan {
+		planCopy = append(planCopy, append([]referenceframe.Input{}, step...))
+	}
+	return planCopy
+}
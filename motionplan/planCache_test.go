@@ -0,0 +1,102 @@
+package motionplan
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPlanCache(t *testing.T) {
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{0, 0})}
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 50})
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 20}), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": box})},
+	}
+	key, err := planCacheKey(sf, seedMap, goal, worldState, map[string]interface{}{"timeout": 5.})
+	test.That(t, err, test.ShouldBeNil)
+
+	t.Run("key", func(t *testing.T) {
+		sameKey, err := planCacheKey(sf, seedMap, goal, worldState, map[string]interface{}{"timeout": 5., "use_plan_cache": true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sameKey, test.ShouldEqual, key)
+
+		otherKey, err := planCacheKey(sf, seedMap, spatialmath.NewPoseFromPoint(r3.Vector{X: 60}), worldState, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, otherKey, test.ShouldNotEqual, key)
+		otherKey, err = planCacheKey(sf, seedMap, goal, nil, map[string]interface{}{"timeout": 5.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, otherKey, test.ShouldNotEqual, key)
+		otherKey, err = planCacheKey(sf, seedMap, goal, worldState, map[string]interface{}{"timeout": 10.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, otherKey, test.ShouldNotEqual, key)
+		otherSeed := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{1, 0})}
+		otherKey, err = planCacheKey(sf, otherSeed, goal, worldState, map[string]interface{}{"timeout": 5.})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, otherKey, test.ShouldNotEqual, key)
+
+		// the same frame with a different model or in a different frame system is a different problem
+		narrowed := []frame.Limit{{Min: -100, Max: 100}, {Min: -50, Max: 50}}
+		unchanged := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+		for _, otherLimits := range [][]frame.Limit{narrowed, unchanged} {
+			otherModel, err := frame.NewMobile2DFrame("base", otherLimits, nil)
+			test.That(t, err, test.ShouldBeNil)
+			otherFS := frame.NewEmptySimpleFrameSystem("test")
+			test.That(t, otherFS.AddFrame(otherModel, otherFS.World()), test.ShouldBeNil)
+			other, err := frame.NewStaticFrame("other", spatialmath.NewPoseFromPoint(r3.Vector{Z: 10}))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, otherFS.AddFrame(other, otherFS.World()), test.ShouldBeNil)
+			otherFrameList, err := otherFS.TracebackFrame(otherModel)
+			test.That(t, err, test.ShouldBeNil)
+			otherSF, err := newSolverFrame(otherFS, otherFrameList, frame.World, seedMap)
+			test.That(t, err, test.ShouldBeNil)
+			otherKey, err = planCacheKey(otherSF, seedMap, goal, worldState, map[string]interface{}{"timeout": 5.})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, otherKey, test.ShouldNotEqual, key)
+		}
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		dir := t.TempDir()
+		plan := [][]frame.Input{frame.FloatsToInputs([]float64{0, 0}), frame.FloatsToInputs([]float64{50, 0})}
+		_, ok, err := cachedPlan(key, dir)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, cachePlan(key, dir, plan), test.ShouldBeNil)
+
+		cached, ok, err := cachedPlan(key, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, cached, test.ShouldResemble, plan)
+
+		// a cached plan is not changed by changing a plan it was returned as
+		cached[0][0] = frame.Input{Value: 1}
+		cached, _, err = cachedPlan(key, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cached, test.ShouldResemble, plan)
+
+		// once dropped from memory, the plan is read back from the directory
+		plans = newLRUCache(defaultPlanCacheSize)
+		_, ok, err = cachedPlan(key, "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ok, test.ShouldBeFalse)
+		cached, ok, err = cachedPlan(key, dir)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, cached, test.ShouldResemble, plan)
+	})
+}
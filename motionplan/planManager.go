@@ -3,15 +3,17 @@ package motionplan
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.viam.com/utils"
 
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/spatialmath"
 )
@@ -20,6 +22,9 @@ const (
 	defaultOptimalityMultiple = 2.0
 	defaultFallbackTimeout    = 1.5
 
+	// How many of the best IK solutions of each frame are combined into goals when planning for several frames at once.
+	defaultMultiSolutionsPerFrame = 10
+
 	// set this to true to get collision penetration depth, which is useful for debugging.
 	getCollisionDepth = false
 )
@@ -29,8 +34,10 @@ const (
 // motionplan.PlanMotion() -> SolvableFrameSystem.SolveWaypointsWithOptions() -> planManager.planSingleWaypoint().
 type planManager struct {
 	*planner
-	frame *solverFrame
-	fs    referenceframe.FrameSystem
+	frame       *solverFrame
+	fs          referenceframe.FrameSystem
+	recorder    *planRecorder
+	storageDirs StorageDirs
 }
 
 func newPlanManager(frame *solverFrame, fs referenceframe.FrameSystem, logger golog.Logger, seed int) (*planManager, error) {
@@ -39,7 +46,34 @@ func newPlanManager(frame *solverFrame, fs referenceframe.FrameSystem, logger go
 	if err != nil {
 		return nil, err
 	}
-	return &planManager{p, frame, fs}, nil
+	return &planManager{planner: p, frame: frame, fs: fs}, nil
+}
+
+// planManagerSeed returns the seed of the plan manager for the i-th goal of a motion. Planning deterministically, it is derived from
+// the `rseed` option.
+func planManagerSeed(i int, planningOpts map[string]interface{}) (int, error) {
+	attrs := config.AttributeMap(planningOpts).Getter()
+	if !attrs.GetBool("deterministic", false) {
+		return i, attrs.Err()
+	}
+	rseed := attrs.GetInt("rseed", 0)
+	if err := attrs.Err(); err != nil {
+		return 0, err
+	}
+	return rseed + i, nil
+}
+
+// plannerRandSeed returns the source of randomness of a planner with the given options. The `rseed` option seeds it if set, unless
+// planning deterministically, in which case its seed is drawn from that of the plan manager so that every planner has its own.
+func (pm *planManager) plannerRandSeed(opt *plannerOptions) *rand.Rand {
+	// options decoded from JSON hold the seed as a float64
+	attrs := config.AttributeMap(opt.extra).Getter()
+	if seed := attrs.GetInt("rseed", 0); attrs.Has("rseed") && attrs.Err() == nil && !opt.Deterministic {
+		//nolint: gosec
+		return rand.New(rand.NewSource(int64(seed)))
+	}
+	//nolint: gosec
+	return rand.New(rand.NewSource(int64(pm.randseed.Int())))
 }
 
 // PlanSingleWaypoint will solve the solver frame to one individual pose. If you have multiple waypoints to hit, call this multiple times.
@@ -50,6 +84,26 @@ func (pm *planManager) PlanSingleWaypoint(ctx context.Context,
 	worldState *referenceframe.WorldState,
 	motionConfig map[string]interface{},
 ) ([][]referenceframe.Input, error) {
+	stream, err := pm.PlanSingleWaypointStream(ctx, seedMap, goalPos, worldState, motionConfig)
+	if err != nil {
+		return nil, err
+	}
+	return collectSteps(stream)
+}
+
+// PlanSingleWaypointStream plans like PlanSingleWaypoint, but rather than returning the whole plan once it is found, it sends the steps
+// to each atomic waypoint of the plan on the returned channel as soon as they are planned, so that the start of the motion can be run
+// while the rest is still being planned. Errors in setting up the plan are returned at once; errors in planning it are sent as the last
+// item on the channel, with the steps planned before a timeout if it is ErrPartialPlan. The channel is closed once planning is done.
+//
+// Where the plan must be checked as a whole before any of it can be run, as it must be to avoid moving obstacles, it is sent all at once.
+// Steps out of collision are sent before anything else is planned, so a plan that then fails may leave the frame out of collision.
+func (pm *planManager) PlanSingleWaypointStream(ctx context.Context,
+	seedMap map[string][]referenceframe.Input,
+	goalPos spatialmath.Pose,
+	worldState *referenceframe.WorldState,
+	motionConfig map[string]interface{},
+) (_ <-chan waypointSteps, err error) {
 	seed, err := pm.frame.mapToSlice(seedMap)
 	if err != nil {
 		return nil, err
@@ -59,14 +113,95 @@ func (pm *planManager) PlanSingleWaypoint(ctx context.Context,
 		return nil, err
 	}
 
-	var cancel func()
+	attrs := config.AttributeMap(motionConfig).Getter()
+	timeout := attrs.GetFloat64("timeout", 0)
+	motionProfile := attrs.GetString("motion_profile", "")
+	pathStepSize := attrs.GetFloat64("path_step_size", defaultPathStepSize)
+	interpolation := attrs.GetString("interpolation", SeparateInterpolation)
+	anytime := attrs.GetBool("anytime", false)
+	returnPartialPlan := attrs.GetBool("return_partial_plan", false)
+	usePlanCache := attrs.GetBool("use_plan_cache", false)
+	planCacheDir := pm.storageDirs.PlanCacheDir
+	planAlg := attrs.GetString("planning_alg", pm.defaultPlanningAlg(attrs, worldState))
+	escape := attrs.GetBool("escape_collision", false)
+	collisionBufferMM := attrs.GetFloat64("collision_buffer_mm", defaultCollisionBufferMM)
+	cartesianLimits := CartesianLimits{
+		MaxVelocity:     attrs.GetFloat64("max_cartesian_velocity", 0),
+		MaxAcceleration: attrs.GetFloat64("max_cartesian_acceleration", 0),
+		MaxJerk:         attrs.GetFloat64("max_cartesian_jerk", 0),
+	}
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
+	var interpolate func(p1, p2 spatialmath.Pose, by float64) spatialmath.Pose
+	switch interpolation {
+	case SeparateInterpolation:
+		interpolate = spatialmath.Interpolate
+	case ScrewInterpolation:
+		interpolate = spatialmath.ScrewInterpolate
+	default:
+		return nil, errors.Errorf("unsupported interpolation %q", interpolation)
+	}
+
+	// look for a plan already found for the same problem before planning it again
+	var planKey string
+	if usePlanCache {
+		if worldState != nil && len(worldState.MovingObstacles) > 0 {
+			return nil, errors.New("use_plan_cache is not supported with moving obstacles, as plans are cached by where obstacles start")
+		}
+		planKey, err = planCacheKey(pm.frame, seedMap, goalPos, worldState, motionConfig)
+		if err != nil {
+			return nil, err
+		}
+		plan, ok, cacheErr := cachedPlan(planKey, planCacheDir)
+		if cacheErr != nil {
+			pm.logger.Warnf("could not read cached plan: %v", cacheErr)
+		} else if ok {
+			valid, err := pm.cachedPlanValid(plan, seed, seedPos, goalPos, seedMap, worldState, motionConfig)
+			if err != nil {
+				return nil, err
+			}
+			if valid {
+				pm.logger.Debug("using cached plan")
+				pm.recorder.record(func(report *PlanReport) { report.Cached = true })
+				cached := make(chan waypointSteps, 1)
+				cached <- waypointSteps{steps: plan}
+				close(cached)
+				return cached, nil
+			}
+			pm.logger.Debug("cached plan does not hold to the motion, planning it again")
+		}
+	}
 
 	// set timeout for entire planning process if specified
-	if timeout, ok := motionConfig["timeout"].(float64); ok {
+	// anytime planners and those returning partial plans stop at the timeout themselves, returning the best plan found, so are not cut
+	// off at the same time
+	cancel := func() {}
+	if timeout > 0 && !anytime && !returnPartialPlan {
 		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout*float64(time.Second)))
 	}
-	if cancel != nil {
-		defer cancel()
+	// the timeout lasts until planning is done, unless it never starts
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	// move out of any obstacle the frame starts in, then plan the motion from wherever that leaves it
+	var escapePath [][]referenceframe.Input
+	if escape {
+		escapePath, err = pm.escapeCollision(ctx, seedMap, worldState, collisionBufferMM)
+		if err != nil {
+			return nil, err
+		}
+		if escapePath != nil {
+			seed = escapePath[len(escapePath)-1]
+			seedMap = pm.frame.sliceToMap(seed)
+			seedPos, err = pm.frame.Transform(seed)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// If we are world rooted, translate the goal pose into the world frame
@@ -82,17 +217,16 @@ func (pm *planManager) PlanSingleWaypoint(ctx context.Context,
 	var opts []*plannerOptions
 
 	// linear motion profile has known intermediate points, so solving can be broken up and sped up
-	if profile, ok := motionConfig["motion_profile"]; ok && profile == LinearMotionProfile {
-		pathStepSize, ok := motionConfig["path_step_size"].(float64)
-		if !ok {
-			pathStepSize = defaultPathStepSize
+	if motionProfile == LinearMotionProfile {
+		// within cartesian limits, the intermediate points are evenly spaced in time rather than along the line
+		fractions, err := linearPathFractions(seedPos, goalPos, pathStepSize, cartesianLimits, interpolation)
+		if err != nil {
+			return nil, err
 		}
-		numSteps := PathStepCount(seedPos, goalPos, pathStepSize)
 
 		from := seedPos
-		for i := 1; i < numSteps; i++ {
-			by := float64(i) / float64(numSteps)
-			to := spatialmath.Interpolate(seedPos, goalPos, by)
+		for _, by := range fractions {
+			to := interpolate(seedPos, goalPos, by)
 			goals = append(goals, to)
 			opt, err := pm.plannerSetupFromMoveRequest(from, to, seedMap, worldState, motionConfig)
 			if err != nil {
@@ -111,22 +245,24 @@ func (pm *planManager) PlanSingleWaypoint(ctx context.Context,
 	}
 	opts = append(opts, opt)
 
+	if anytime {
+		planAlg = RRTStarPlanningAlg
+	} else if planAlg == "" {
+		planAlg = CBiRRTPlanningAlg
+	}
+	pm.recorder.record(func(report *PlanReport) {
+		report.Planner = planAlg
+		report.Waypoints += len(goals)
+	})
+
 	planners := make([]motionPlanner, 0, len(opts))
 	// Set up planners for later execution
 	for _, opt := range opts {
-		// Build planner
-		var randseed *rand.Rand
-		if seed, ok := opt.extra["rseed"].(int); ok {
-			//nolint: gosec
-			randseed = rand.New(rand.NewSource(int64(seed)))
-		} else {
-			//nolint: gosec
-			randseed = rand.New(rand.NewSource(int64(pm.randseed.Int())))
-		}
 
+		// Build planner
 		pathPlanner, err := opt.PlannerConstructor(
 			pm.frame,
-			randseed,
+			pm.plannerRandSeed(opt),
 			pm.logger,
 			opt,
 		)
@@ -145,13 +281,302 @@ func (pm *planManager) PlanSingleWaypoint(ctx context.Context,
 		}
 	}
 
-	resultSlices, err := pm.planAtomicWaypoints(ctx, goals, seed, planners)
+	// at most one item is sent for each goal and one for the escape path, so planning never waits on the channel being read
+	out := make(chan waypointSteps, len(goals)+1)
+	utils.PanicCapturingGo(func() {
+		defer cancel()
+		defer close(out)
+		if escapePath != nil {
+			out <- waypointSteps{steps: escapePath[:len(escapePath)-1]}
+		}
+
+		var stream <-chan waypointSteps
+		if opt.movingObstacles != nil {
+			scheduled := make(chan waypointSteps, 1)
+			steps, err := pm.planScheduledWaypoints(ctx, goals, seed, planners, opt.movingObstacles)
+			scheduled <- waypointSteps{steps: steps, err: err}
+			close(scheduled)
+			stream = scheduled
+		} else {
+			stream = pm.streamAtomicWaypoints(ctx, goals, seed, planners)
+		}
+
+		resultSlices := [][]referenceframe.Input{}
+		var err error
+		for segment := range stream {
+			if segment.err != nil && !errors.Is(segment.err, ErrPartialPlan) && len(goals) > 1 {
+				segment.err = fmt.Errorf("failed to plan path for valid goal: %w", segment.err)
+			}
+			resultSlices = append(resultSlices, segment.steps...)
+			err = segment.err
+			out <- segment
+		}
+
+		pm.recorder.record(func(report *PlanReport) {
+			for _, opt := range opts {
+				report.ConstraintChecks += opt.checkCount()
+			}
+			if err == nil && len(resultSlices) > 1 {
+				report.Cost += EvaluatePlan(resultSlices, opts[0].DistanceFunc)
+			}
+		})
+		if err == nil && usePlanCache {
+			if escapePath != nil {
+				resultSlices = append(escapePath[:len(escapePath)-1], resultSlices...)
+			}
+			if err := cachePlan(planKey, planCacheDir, resultSlices); err != nil {
+				pm.logger.Warnf("could not cache plan: %v", err)
+			}
+		}
+	})
+	return out, nil
+}
+
+// cachedPlanValid checks a cached plan before it is reused, as it may have been read from a file changed since it was written: the plan
+// must start where the frame is, and the path through its steps must hold to the constraints of the motion, such as staying clear of
+// obstacles. Plans which escape a collision the frame starts in never pass, and are planned again.
+func (pm *planManager) cachedPlanValid(
+	plan [][]referenceframe.Input,
+	seed []referenceframe.Input,
+	seedPos, goalPos spatialmath.Pose,
+	seedMap map[string][]referenceframe.Input,
+	worldState *referenceframe.WorldState,
+	motionConfig map[string]interface{},
+) (bool, error) {
+	if len(plan) == 0 || len(plan[0]) != len(seed) {
+		return false, nil
+	}
+	if distance(referenceframe.InputsToFloats(plan[0]), referenceframe.InputsToFloats(seed)) > defaultEpsilon {
+		return false, nil
+	}
+	if pm.frame.worldRooted {
+		tf, err := pm.frame.fss.Transform(seedMap, referenceframe.NewPoseInFrame(pm.frame.goalFrame.Name(), goalPos), referenceframe.World)
+		if err != nil {
+			return false, err
+		}
+		goalPos = tf.(*referenceframe.PoseInFrame).Pose()
+	}
+	opt, err := pm.plannerSetupFromMoveRequest(seedPos, goalPos, seedMap, worldState, motionConfig)
 	if err != nil {
-		if len(goals) > 1 {
-			err = fmt.Errorf("failed to plan path for valid goal: %w", err)
+		return false, err
+	}
+	// each check covers the states at both ends of its segment, so starting from the seed checks every step
+	from := seed
+	for _, step := range plan {
+		if len(step) != len(seed) {
+			return false, nil
+		}
+		if ok, _ := opt.CheckConstraintPath(&ConstraintInput{StartInput: from, EndInput: step, Frame: pm.frame}, opt.Resolution); !ok {
+			return false, nil
 		}
+		from = step
+	}
+	return true, nil
+}
+
+// PlanMultiWaypoint plans for every chain of a solver frame made by newMultiSolverFrame at once, moving each to its own goal, which
+// are given in the same order as the chains. IK is solved for each chain on its own, and the solutions are combined into goals for the
+// whole configuration space, discarding those where the chains collide with each other. CBiRRT then plans through the combined
+// configuration space to whichever of those goals it reaches first, so that the chains also avoid each other on the way.
+func (pm *planManager) PlanMultiWaypoint(ctx context.Context,
+	seedMap map[string][]referenceframe.Input,
+	goals []spatialmath.Pose,
+	worldState *referenceframe.WorldState,
+	motionConfig map[string]interface{},
+) ([][]referenceframe.Input, error) {
+	parts := pm.frame.parts
+	if len(parts) == 0 {
+		return nil, errors.New("planning for several goals at once requires a solver frame combining several frames")
+	}
+	if len(goals) != len(parts) {
+		return nil, errors.Errorf("got %d goals for %d frames", len(goals), len(parts))
+	}
+	seed, err := pm.frame.mapToSlice(seedMap)
+	if err != nil {
+		return nil, err
+	}
+	seedPos, err := pm.frame.Transform(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := config.AttributeMap(motionConfig).Getter()
+	timeout := attrs.GetFloat64("timeout", 0)
+	motionProfile := attrs.GetString("motion_profile", "")
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
+	if motionProfile != "" && motionProfile != FreeMotionProfile {
+		// motion profiles constrain how a single pose moves, which there is not one of here
+		return nil, errors.Errorf("motion profile %q is not supported when planning for several frames at once", motionProfile)
+	}
+	if timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeout*float64(time.Second)))
+		defer cancel()
+	}
+
+	planningOpts := deepAtomicCopyMap(motionConfig)
+	planningOpts["planning_alg"] = CBiRRTPlanningAlg
+	opt, err := pm.plannerSetupFromMoveRequest(seedPos, seedPos, seedMap, worldState, planningOpts)
+	if err != nil {
 		return nil, err
 	}
+	//nolint: gosec
+	mp, err := newCBiRRTMotionPlanner(pm.frame, rand.New(rand.NewSource(int64(pm.randseed.Int()))), pm.logger, opt)
+	if err != nil {
+		return nil, err
+	}
+	rrtPlanner, ok := mp.(rrtParallelPlanner)
+	if !ok {
+		return nil, errors.New("planning for several frames at once requires an RRT planner")
+	}
+	if !mp.checkInputs(seed) {
+		return nil, errors.New("cannot plan, the frames are in collision where they are")
+	}
+
+	// solve IK for each chain on its own, against the obstacles and itself
+	partSolutions := make([][]*costNode, 0, len(parts))
+	for i, part := range parts {
+		partPM, err := newPlanManager(part, pm.fs, pm.logger, pm.randseed.Int())
+		if err != nil {
+			return nil, err
+		}
+		partPM.storageDirs = pm.storageDirs
+		partSeed, err := part.mapToSlice(seedMap)
+		if err != nil {
+			return nil, err
+		}
+		partSeedPos, err := part.Transform(partSeed)
+		if err != nil {
+			return nil, err
+		}
+		goalPos := goals[i]
+		if part.worldRooted {
+			tf, err := pm.fs.Transform(seedMap, referenceframe.NewPoseInFrame(part.goalFrame.Name(), goalPos), referenceframe.World)
+			if err != nil {
+				return nil, err
+			}
+			goalPos = tf.(*referenceframe.PoseInFrame).Pose()
+		}
+		partOpt, err := partPM.plannerSetupFromMoveRequest(partSeedPos, goalPos, seedMap, worldState, planningOpts)
+		if err != nil {
+			return nil, err
+		}
+		partPM.planOpts = partOpt
+		solutions, err := partPM.getSolutions(ctx, goalPos, partSeed)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot solve for the goal of %s", part.Name())
+		}
+		if len(solutions) > defaultMultiSolutionsPerFrame {
+			solutions = solutions[:defaultMultiSolutionsPerFrame]
+		}
+		partSolutions = append(partSolutions, solutions)
+	}
+
+	// combine the solutions of every chain, keeping the best of those where the chains are clear of each other
+	combined := combineSolutions(pm.frame, seedMap, partSolutions)
+	nSolutions := opt.MaxSolutions
+	if nSolutions == 0 {
+		nSolutions = defaultSolutionsToSeed
+	}
+	maps := &rrtMaps{startMap: rrtMap{}, goalMap: rrtMap{}}
+	for _, solution := range combined {
+		if !mp.checkInputs(solution.Q()) {
+			continue
+		}
+		if maps.optNode == nil {
+			maps.optNode = solution
+		}
+		maps.goalMap[&basicNode{q: solution.Q()}] = nil
+		if len(maps.goalMap) >= nSolutions {
+			break
+		}
+	}
+	if len(maps.goalMap) == 0 {
+		return nil, errors.New("every solution for the goals has the frames colliding with each other")
+	}
+	maps.startMap[&basicNode{q: seed}] = nil
+	goalPos, err := pm.frame.Transform(maps.optNode.Q())
+	if err != nil {
+		return nil, err
+	}
+
+	plannerctx, cancel := context.WithTimeout(ctx, time.Duration(opt.Timeout*float64(time.Second)))
+	defer cancel()
+	solutionChan := make(chan *rrtPlanReturn, 1)
+	utils.PanicCapturingGo(func() {
+		rrtPlanner.rrtBackgroundRunner(plannerctx, goalPos, seed, &rrtParallelPlannerShared{maps, nil, solutionChan})
+	})
+	var result *rrtPlanReturn
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-solutionChan:
+	}
+	if result.err() != nil {
+		return nil, result.err()
+	}
+	steps := rrtPlanner.smoothPath(plannerctx, result.steps)
+	resultSlices := make([][]referenceframe.Input, 0, len(steps))
+	for _, step := range steps {
+		resultSlices = append(resultSlices, step.Q())
+	}
+	return resultSlices, nil
+}
+
+// combineSolutions returns every combination of one solution of each chain of a solver frame made by newMultiSolverFrame, as inputs
+// to it, ordered by the summed cost of the solutions combined.
+func combineSolutions(sf *solverFrame, seedMap map[string][]referenceframe.Input, partSolutions [][]*costNode) []*costNode {
+	combined := []*costNode{}
+	inputMap := map[string][]referenceframe.Input{}
+	for k, v := range seedMap {
+		inputMap[k] = v
+	}
+	var combine func(i int, cost float64)
+	combine = func(i int, cost float64) {
+		if i == len(partSolutions) {
+			// every frame of the combined solver frame is in one of its parts, so this cannot fail
+			q, err := sf.mapToSlice(inputMap)
+			if err == nil {
+				combined = append(combined, newCostNode(q, cost))
+			}
+			return
+		}
+		for _, solution := range partSolutions[i] {
+			idx := 0
+			for _, f := range sf.parts[i].frames {
+				dof := len(f.DoF())
+				inputMap[f.Name()] = solution.Q()[idx : idx+dof]
+				idx += dof
+			}
+			combine(i+1, cost+solution.cost)
+		}
+	}
+	combine(0, 0)
+	sort.SliceStable(combined, func(i, j int) bool { return combined[i].cost < combined[j].cost })
+	return combined
+}
+
+// waypointSteps are the steps planned to reach an atomic waypoint, or the error which stopped planning. If the error is ErrPartialPlan,
+// the steps are those planned towards the waypoint before planning stopped.
+type waypointSteps struct {
+	steps [][]referenceframe.Input
+	err   error
+}
+
+// collectSteps joins all of the steps sent on a stream of waypoints into one plan, returning the first error sent.
+func collectSteps(stream <-chan waypointSteps) ([][]referenceframe.Input, error) {
+	resultSlices := [][]referenceframe.Input{}
+	for segment := range stream {
+		if errors.Is(segment.err, ErrPartialPlan) {
+			return append(resultSlices, segment.steps...), segment.err
+		}
+		if segment.err != nil {
+			return nil, segment.err
+		}
+		resultSlices = append(resultSlices, segment.steps...)
+	}
 	return resultSlices, nil
 }
 
@@ -164,41 +589,69 @@ func (pm *planManager) planAtomicWaypoints(
 	seed []referenceframe.Input,
 	planners []motionPlanner,
 ) ([][]referenceframe.Input, error) {
+	return collectSteps(pm.streamAtomicWaypoints(ctx, goals, seed, planners))
+}
+
+// streamAtomicWaypoints plans a motion as planAtomicWaypoints does, but sends the steps to each waypoint on the returned channel, in
+// order, as soon as they are planned. The channel is closed after the last waypoint, or after the first error, which is sent last.
+func (pm *planManager) streamAtomicWaypoints(
+	ctx context.Context,
+	goals []spatialmath.Pose,
+	seed []referenceframe.Input,
+	planners []motionPlanner,
+) <-chan waypointSteps {
 	// A resultPromise can be queried in the future and will eventually yield either a set of planner waypoints, or an error.
-	// Each atomic waypoint produces one result promise, all of which are resolved at the end, allowing multiple to be solved in parallel.
-	resultPromises := []*resultPromise{}
+	// Each atomic waypoint produces one result promise, which are resolved in order while later waypoints are still being solved.
+	// Both channels hold as many items as there are goals, so that neither goroutine is left waiting if the stream is not read.
+	resultPromises := make(chan *resultPromise, len(goals))
+	stream := make(chan waypointSteps, len(goals))
 
 	// try to solve each goal, one at a time
-	for i, goal := range goals {
-		// Check if ctx is done between each waypoint
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-		}
+	utils.PanicCapturingGo(func() {
+		defer close(resultPromises)
+		for i, goal := range goals {
+			// Check if ctx is done between each waypoint
+			select {
+			case <-ctx.Done():
+				resultPromises <- &resultPromise{err: ctx.Err()}
+				return
+			default:
+			}
 
-		pathPlanner := planners[i]
-		// Plan the single waypoint, and accumulate objects which will be used to constrauct the plan after all planning has finished
-		newseed, future, err := pm.planSingleAtomicWaypoint(ctx, goal, seed, pathPlanner, nil)
-		if err != nil {
-			return nil, err
+			pathPlanner := planners[i]
+			newseed, future, err := pm.planSingleAtomicWaypoint(ctx, goal, seed, pathPlanner, nil)
+			if errors.Is(err, ErrPartialPlan) {
+				// later waypoints cannot be planned from the end of a partial plan, so stop here
+				resultPromises <- future
+				return
+			}
+			if err != nil {
+				resultPromises <- &resultPromise{err: err}
+				return
+			}
+			seed = newseed
+			resultPromises <- future
 		}
-		seed = newseed
-		resultPromises = append(resultPromises, future)
-	}
-
-	resultSlices := [][]referenceframe.Input{}
+	})
 
-	// All goals have been submitted for solving. Reconstruct in order
-	for _, future := range resultPromises {
-		steps, err := future.result(ctx)
-		if err != nil {
-			return nil, err
+	// Send the steps of each goal as they are resolved, in order
+	utils.PanicCapturingGo(func() {
+		defer close(stream)
+		for future := range resultPromises {
+			var steps [][]referenceframe.Input
+			var err error
+			if future.future == nil {
+				steps, err = future.steps, future.err
+			} else {
+				steps, err = future.result(ctx)
+			}
+			stream <- waypointSteps{steps: steps, err: err}
+			if err != nil {
+				return
+			}
 		}
-		resultSlices = append(resultSlices, steps...)
-	}
-
-	return resultSlices, nil
+	})
+	return stream
 }
 
 // planSingleAtomicWaypoint attempts to plan a single waypoint. It may optionally be pre-seeded with rrt maps; these will be passed to the
@@ -215,6 +668,10 @@ func (pm *planManager) planSingleAtomicWaypoint(
 		// This will set that up, and if we get a result on `endpointPreview`, then the next iteration will be started, and the steps
 		// for this solve will be rectified at the end.
 		endpointPreview := make(chan node, 1)
+		if pathPlanner.opt().Deterministic {
+			// waypoints are planned one at a time, so that seeds are drawn in the same order every time
+			endpointPreview = nil
+		}
 		solutionChan := make(chan *rrtPlanReturn, 1)
 		utils.PanicCapturingGo(func() {
 			pm.planParallelRRTMotion(ctx, goal, seed, parPlan, endpointPreview, solutionChan, maps)
@@ -229,6 +686,10 @@ func (pm *planManager) planSingleAtomicWaypoint(
 		case nextSeed := <-endpointPreview:
 			return nextSeed.Q(), &resultPromise{future: solutionChan}, nil
 		case planReturn := <-solutionChan:
+			if errors.Is(planReturn.planerr, ErrPartialPlan) {
+				steps := planReturn.toInputs()
+				return steps[len(steps)-1], &resultPromise{steps: steps, err: planReturn.planerr}, planReturn.planerr
+			}
 			if planReturn.planerr != nil {
 				return nil, nil, planReturn.planerr
 			}
@@ -241,10 +702,19 @@ func (pm *planManager) planSingleAtomicWaypoint(
 		// This ctx is used exclusively for the running of the new planner and timing it out.
 		plannerctx, cancel := context.WithTimeout(ctx, time.Duration(pathPlanner.opt().Timeout*float64(time.Second)))
 		defer cancel()
+		recorder := pathPlanner.opt().recorder
+		planStart := time.Now()
 		steps, err := pathPlanner.plan(plannerctx, goal, seed)
+		recorder.record(func(report *PlanReport) { report.PlanningTime += time.Since(planStart) })
 		if err != nil {
 			return nil, nil, err
 		}
+		if pathPlanner.opt().OptimizePath || pathPlanner.opt().SmoothMethod == SplineSmoothMethod {
+			smoothStart := time.Now()
+			steps = nodesToInputs(splineSmoothPath(plannerctx, pathPlanner, pm.frame,
+				optimizePath(plannerctx, pathPlanner, pm.frame, stepsToNodes(steps))))
+			recorder.record(func(report *PlanReport) { report.SmoothingTime += time.Since(smoothStart) })
+		}
 		// Update seed for the next waypoint to be the final configuration of this waypoint
 		seed = steps[len(steps)-1]
 		return seed, &resultPromise{steps: steps}, nil
@@ -263,13 +733,19 @@ func (pm *planManager) planParallelRRTMotion(
 	maps *rrtMaps,
 ) {
 	var err error
-	// If we don't pass in pre-made maps, initialize and seed with IK solutions here
+	// If we don't pass in pre-made maps, pick up from stored ones, and initialize and seed with IK solutions if there are none for the goal
 	if maps == nil {
+		maps = pm.warmStartRRTMaps(pathPlanner.opt(), goal, seed)
+	}
+	if maps == nil || maps.goalMap == nil {
 		planSeed := initRRTSolutions(ctx, pathPlanner, goal, seed)
 		if planSeed.planerr != nil || planSeed.steps != nil {
 			solutionChan <- planSeed
 			return
 		}
+		if maps != nil {
+			planSeed.maps.startMap = maps.startMap
+		}
 		maps = planSeed.maps
 	}
 
@@ -291,8 +767,11 @@ func (pm *planManager) planParallelRRTMotion(
 	defer cancel()
 
 	plannerChan := make(chan *rrtPlanReturn, 1)
+	recorder := pathPlanner.opt().recorder
+	recorder.record(func(report *PlanReport) { report.OptimalCost += maps.optNode.cost })
 
 	// start the planner
+	planStart := time.Now()
 	utils.PanicCapturingGo(func() {
 		pathPlanner.rrtBackgroundRunner(plannerctx, goal, seed, &rrtParallelPlannerShared{maps, endpointPreview, plannerChan})
 	})
@@ -310,22 +789,35 @@ func (pm *planManager) planParallelRRTMotion(
 		// We didn't get a solution preview (possible error), so we get and process the full step set and error.
 
 		mapSeed := finalSteps.maps
+		recorder.record(func(report *PlanReport) {
+			report.PlanningTime += time.Since(planStart)
+			if mapSeed != nil {
+				report.Nodes += len(mapSeed.startMap) + len(mapSeed.goalMap)
+			}
+		})
+
+		// store the maps before a fallback grows them further
+		if key := pathPlanner.opt().rrtMapKey; key != "" && mapSeed != nil && mapSeed.optNode != nil {
+			if err := storeRRTMaps(key, pathPlanner.opt().rrtMapDir, mapSeed, goal, seed); err != nil {
+				pm.logger.Warnf("could not store RRT maps: %v", err)
+			}
+		}
+
+		// If the planner ran out of time, return as far as it got towards the goal if asked to, rather than trying a fallback
+		if pathPlanner.opt().ReturnPartialPlan && mapSeed != nil && errors.Is(finalSteps.err(), context.DeadlineExceeded) {
+			if steps := partialPath(mapSeed, pathPlanner.opt().DistanceFunc); len(steps) > 0 {
+				pm.logger.Debugf("planner timed out, returning partial path of %d steps", len(steps))
+				solutionChan <- &rrtPlanReturn{steps: steps, planerr: ErrPartialPlan}
+				return
+			}
+		}
 
 		// Create fallback planner
 		var fallbackPlanner motionPlanner
 		if pathPlanner.opt().Fallback != nil {
-			var randseed *rand.Rand
-			if seed, ok := pathPlanner.opt().extra["rseed"].(int); ok {
-				//nolint: gosec
-				randseed = rand.New(rand.NewSource(int64(seed)))
-			} else {
-				//nolint: gosec
-				randseed = rand.New(rand.NewSource(int64(pm.randseed.Int())))
-			}
-
 			fallbackPlanner, err = pathPlanner.opt().Fallback.PlannerConstructor(
 				pm.frame,
-				randseed,
+				pm.plannerRandSeed(pathPlanner.opt().Fallback),
 				pm.logger,
 				pathPlanner.opt().Fallback,
 			)
@@ -354,7 +846,11 @@ func (pm *planManager) planParallelRRTMotion(
 		// Start smoothing before initializing the fallback plan. This allows both to run simultaneously.
 		smoothChan := make(chan []node, 1)
 		utils.PanicCapturingGo(func() {
-			smoothChan <- pathPlanner.smoothPath(ctx, finalSteps.steps)
+			smoothStart := time.Now()
+			smoothed := optimizePath(ctx, pathPlanner, pm.frame, pathPlanner.smoothPath(ctx, finalSteps.steps))
+			smoothed = splineSmoothPath(ctx, pathPlanner, pm.frame, smoothed)
+			recorder.record(func(report *PlanReport) { report.SmoothingTime += time.Since(smoothStart) })
+			smoothChan <- smoothed
 		})
 		var alternateFuture *resultPromise
 
@@ -382,10 +878,11 @@ func (pm *planManager) planParallelRRTMotion(
 			if err == nil {
 				// If the fallback successfully found a path, check if it is better than our smoothed previous path.
 				// The fallback should emerge pre-smoothed, so that should be a non-issue
-				altCost := EvaluatePlan(alternate, pathPlanner.opt().DistanceFunc)
+				altCost := planCost(alternate, pathPlanner.opt())
 				if altCost < score {
 					pm.logger.Debugf("replacing path with score %f with better score %f", score, altCost)
 					finalSteps = &rrtPlanReturn{steps: stepsToNodes(alternate)}
+					recorder.record(func(report *PlanReport) { report.FallbackUsed = true })
 				} else {
 					pm.logger.Debugf("fallback path with score %f worse than original score %f; using original", altCost, score)
 				}
@@ -400,6 +897,22 @@ func (pm *planManager) planParallelRRTMotion(
 	}
 }
 
+// defaultPlanningAlg returns the planning algorithm used when none is given. Bases that can only drive in the direction they are
+// facing are planned for with hybrid A*, so that their paths can be driven, unless something asked of the plan needs another
+// planner. Otherwise it is empty, and a timed RRT* attempt is made before planning with CBiRRT.
+func (pm *planManager) defaultPlanningAlg(attrs *config.AttributeGetter, worldState *referenceframe.WorldState) string {
+	if !pm.frame.drivesWithHeading() || attrs.GetBool("anytime", false) || attrs.Has("constraints") {
+		return ""
+	}
+	if motionProfile := attrs.GetString("motion_profile", ""); motionProfile != "" && motionProfile != FreeMotionProfile {
+		return ""
+	}
+	if worldState != nil && len(worldState.MovingObstacles) > 0 {
+		return ""
+	}
+	return HybridAStarPlanningAlg
+}
+
 // This is where the map[string]interface{} passed in via `extra` is used to decide how planning happens.
 func (pm *planManager) plannerSetupFromMoveRequest(
 	from, to spatialmath.Pose,
@@ -407,31 +920,115 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	worldState *referenceframe.WorldState,
 	planningOpts map[string]interface{},
 ) (*plannerOptions, error) {
+	// reject unsupported options, and those which cannot be used together, before setting anything up
+	cfg, err := NewPlannerConfigFromMap(planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	// geometries declared as allowed to touch, by the frame system or the options, are not in collision when they do
+	allowedCollisions := allowedCollisionSpecifications(pm.fs, cfg.AllowedCollisions)
+
 	// Start with normal options
 	opt := newBasicPlannerOptions()
 
 	opt.extra = planningOpts
+	// planners set up here, including those fallen back to, are recorded in the report of the plan
+	opt.recorder = pm.recorder
+
+	attrs := config.AttributeMap(planningOpts).Getter()
+	motionProfile := attrs.GetString("motion_profile", "")
+	planAlg := attrs.GetString("planning_alg", pm.defaultPlanningAlg(attrs, worldState))
+	linTol := attrs.GetFloat64("line_tolerance", defaultLinearDeviation)
+	orientTol := attrs.GetFloat64("orient_tolerance", defaultLinearDeviation)
+	continuousCollision := attrs.GetBool("continuous_collision", false)
+	anytime := attrs.GetBool("anytime", false)
+	deterministic := attrs.GetBool("deterministic", false)
+	collisionBufferMM := attrs.GetFloat64("collision_buffer_mm", defaultCollisionBufferMM)
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
+	if anytime {
+		planAlg = RRTStarPlanningAlg
+	}
 
 	// add collision constraints
-	selfCollisionConstraint, err := newSelfCollisionConstraint(pm.frame, seedMap, []*Collision{}, getCollisionDepth)
+	selfCollisionConstraint, err := newSelfCollisionConstraint(
+		pm.frame, seedMap, allowedCollisions, getCollisionDepth, false, collisionBufferMM,
+	)
 	if err != nil {
 		return nil, err
 	}
-	obstacleConstraint, err := newObstacleConstraint(pm.frame, pm.fs, worldState, seedMap, []*Collision{}, getCollisionDepth)
+	obstacleConstraint, err := newObstacleConstraint(
+		pm.frame, pm.fs, worldState, seedMap, allowedCollisions, getCollisionDepth, false, collisionBufferMM,
+	)
 	if err != nil {
 		return nil, err
 	}
 	opt.AddConstraint(defaultObstacleConstraintName, obstacleConstraint)
 	opt.AddConstraint(defaultSelfCollisionConstraintName, selfCollisionConstraint)
 
-	// error handling around extracting motion_profile information from map[string]interface{}
-	var motionProfile string
-	profile, ok := planningOpts["motion_profile"]
-	if ok {
-		motionProfile, ok = profile.(string)
-		if !ok {
-			return nil, errors.New("could not interpret motion_profile field as string")
+	if continuousCollision {
+		// also check the motion between the steps of paths, so that large steps cannot pass through thin obstacles
+		selfCollisionConstraint, err := newSelfCollisionConstraint(
+			pm.frame, seedMap, allowedCollisions, getCollisionDepth, true, collisionBufferMM,
+		)
+		if err != nil {
+			return nil, err
+		}
+		obstacleConstraint, err := newObstacleConstraint(
+			pm.frame, pm.fs, worldState, seedMap, allowedCollisions, getCollisionDepth, true, collisionBufferMM,
+		)
+		if err != nil {
+			return nil, err
+		}
+		opt.AddSegmentConstraint(defaultObstacleConstraintName, obstacleConstraint)
+		opt.AddSegmentConstraint(defaultSelfCollisionConstraintName, selfCollisionConstraint)
+	}
+
+	// keep clear of moving obstacles, checked while planning where they are when each configuration could be reached at the soonest
+	movingObstacleConstraint, err := newMovingObstacleConstraint(pm.frame, pm.fs, worldState, seedMap, collisionBufferMM)
+	if err != nil {
+		return nil, err
+	}
+	if movingObstacleConstraint != nil {
+		if planAlg == PRMPlanningAlg || planAlg == HybridAStarPlanningAlg {
+			return nil, errors.Errorf("moving obstacles are not supported by planning_alg %q", planAlg)
+		}
+		if attrs.GetBool("reuse_rrt_maps", false) {
+			return nil, errors.New("reuse_rrt_maps is not supported with moving obstacles, as maps are only clear of them at one time")
+		}
+		seed, err := pm.frame.mapToSlice(seedMap)
+		if err != nil {
+			return nil, err
+		}
+		arrivalConstraint, err := newEarliestArrivalConstraint(pm.frame, seed, movingObstacleConstraint)
+		if err != nil {
+			return nil, err
+		}
+		opt.AddConstraint(defaultMovingObstacleConstraintName, arrivalConstraint)
+		opt.movingObstacles = movingObstacleConstraint
+	}
+
+	// keep a frame, by default the one being moved, inside of a workspace geometry
+	if attrs.Has("workspace") {
+		var workspaceConfig spatialmath.GeometryConfig
+		attrs.Decode("workspace", &workspaceConfig)
+		workspaceFrame := attrs.GetString("workspace_frame", pm.frame.solveFrame.Name())
+		if err := attrs.Err(); err != nil {
+			return nil, err
+		}
+		workspace, err := workspaceConfig.ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		workspaceConstraint, err := NewWorkspaceConstraint(pm.fs, workspaceFrame, workspace)
+		if err != nil {
+			return nil, err
 		}
+		opt.AddConstraint(defaultWorkspaceConstraintName, workspaceConstraint)
 	}
 
 	// convert map to json, then to a struct, overwriting present defaults
@@ -443,76 +1040,218 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 	if err != nil {
 		return nil, err
 	}
+	if opt.Deterministic {
+		// parallel IK solvers and neighbor searches race each other, so which solutions are found first would vary
+		opt.NumThreads = 1
+	}
+	if attrs.Has("nullspace_objectives") {
+		var weights map[string]float64
+		attrs.Decode("nullspace_objectives", &weights)
+		posture := attrs.GetFloat64Slice("preferred_posture")
+		if err := attrs.Err(); err != nil {
+			return nil, err
+		}
+		opt.nullspace, err = newNullspaceObjective(pm.frame, weights, posture)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.IKSolver != "" {
+		// fail before planning starts if the solver cannot be used for this frame
+		if _, err := ikSolverFor(opt.IKSolver, pm.frame); err != nil {
+			return nil, err
+		}
+	}
+	if opt.Sampler != "" {
+		// fail before planning starts if the options of the sampler are bad
+		constructor, err := samplerFor(opt.Sampler)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := constructor(pm.frame, planningOpts); err != nil {
+			return nil, err
+		}
+	}
+	// clearance from obstacles is measured both to optimize paths and to prefer paths keeping clear of obstacles
+	if opt.OptimizePath || opt.ClearanceWeight > 0 {
+		if opt.OptimizeSteps < 2 || opt.OptimizeClearance <= 0 {
+			return nil, errors.New("optimize_steps must be at least 2, and optimize_clearance must be positive")
+		}
+		opt.clearance, err = newClearanceFunc(pm.frame, pm.fs, worldState, seedMap, collisionBufferMM)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	var planAlg string
-	alg, ok := planningOpts["planning_alg"]
-	if ok {
-		planAlg, ok = alg.(string)
-		if !ok {
-			return nil, errors.New("could not interpret planning_alg field as string")
-		}
-		switch planAlg {
-		// TODO(pl): make these consts
-		case "cbirrt":
-			opt.PlannerConstructor = newCBiRRTMotionPlanner
-		case "rrtstar":
-			// no motion profiles for RRT*
-			opt.PlannerConstructor = newRRTStarConnectMotionPlanner
-			// TODO(pl): more logic for RRT*?
-			return opt, nil
-		default:
-			// use default, already set
+	if opt.SmoothMethod == SplineSmoothMethod && opt.SplineSteps < 2 {
+		return nil, errors.New("spline_steps must be at least 2")
+	}
+
+	// measure how far apart inputs are with a registered distance function and per joint weights, if given
+	distanceMetric := attrs.GetString("distance_metric", "")
+	jointWeights := attrs.GetFloat64Slice("joint_weights")
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
+	if distanceMetric != "" {
+		opt.DistanceFunc, err = distanceFuncFor(distanceMetric)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if jointWeights != nil {
+		if len(jointWeights) != len(pm.frame.DoF()) {
+			return nil, errors.Errorf("got %d joint_weights for %d degrees of freedom", len(jointWeights), len(pm.frame.DoF()))
 		}
+		opt.DistanceFunc = NewWeightedDistanceFunc(jointWeights, opt.DistanceFunc)
+	}
+
+	// finish anywhere within a region around the goal rather than exactly at it
+	if attrs.Has("goal_tolerance") {
+		var tolerance GoalTolerance
+		attrs.Decode("goal_tolerance", &tolerance)
+		if err := attrs.Err(); err != nil {
+			return nil, err
+		}
+		opt.SetMetric(NewGoalRegionMetric(tolerance))
+	}
+
+	// keep inputs clear of the hard stops of the hardware by planning within limits moved inwards by a margin, and prefer paths
+	// keeping away from them
+	limitedFrame := pm.frame
+	margin := attrs.GetFloat64("joint_limit_margin", 0)
+	marginPercent := attrs.GetFloat64("joint_limit_margin_percent", 0)
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
+	if margin > 0 || marginPercent > 0 || opt.SoftLimitWeight > 0 {
+		if opt.SoftLimitZonePercent <= 0 || opt.SoftLimitZonePercent > 50 {
+			return nil, errors.New("soft_limit_zone_percent must be positive and at most 50")
+		}
+		// the soft limit cost is measured along paths divided up as when optimizing them
+		if opt.SoftLimitWeight > 0 && opt.OptimizeSteps < 2 {
+			return nil, errors.New("optimize_steps must be at least 2")
+		}
+		seed, err := pm.frame.mapToSlice(seedMap)
+		if err != nil {
+			return nil, err
+		}
+		opt.jointLimits, err = marginLimits(pm.frame, seed, margin, marginPercent)
+		if err != nil {
+			return nil, err
+		}
+		opt.AddConstraint(defaultJointLimitConstraintName, NewJointLimitConstraint(opt.jointLimits))
+		limitedFrame = pm.frame.withLimits(opt.jointLimits)
+	}
+
+	// pick up growing RRT maps where planning the same frame among the same obstacles last left off
+	reuseRRTMaps := attrs.GetBool("reuse_rrt_maps", false)
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
+	if reuseRRTMaps {
+		opt.rrtMapKey, err = roadmapKey(limitedFrame, worldState, opt.Resolution, planningOpts, rrtMapKeyOptions)
+		if err != nil {
+			return nil, err
+		}
+		opt.rrtMapDir = pm.storageDirs.RRTMapDir
+	}
+
+	// compose the named constraints of the `constraints` option with those of the motion profile
+	var pathDists []Metric
+	hasConstraints := attrs.Has("constraints")
+	if hasConstraints {
+		pathDists, err = addNamedConstraints(opt, ConstraintRequest{
+			From:        from,
+			To:          to,
+			FrameSystem: pm.fs,
+			Frame:       pm.frame.solveFrame.Name(),
+		}, planningOpts["constraints"])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch planAlg {
+	case CBiRRTPlanningAlg:
+		opt.PlannerConstructor = newCBiRRTMotionPlanner
+	case RRTStarPlanningAlg:
+		// no motion profiles for RRT*
+		opt.PlannerConstructor = newRRTStarConnectMotionPlanner
+		// TODO(pl): more logic for RRT*?
+		return opt, nil
+	case PRMPlanningAlg:
+		// no motion profiles for PRM, as its roadmap is only valid for the obstacles it was built among
+		opt.PlannerConstructor = newPRMMotionPlanner
+		// a roadmap built by an earlier plan would make the plan depend on what was planned before
+		reuse := attrs.GetBool("reuse_roadmap", !deterministic)
+		if err := attrs.Err(); err != nil {
+			return nil, err
+		}
+		if reuse {
+			key, err := roadmapKey(limitedFrame, worldState, opt.Resolution, planningOpts, roadmapKeyOptions)
+			if err != nil {
+				return nil, err
+			}
+			opt.roadmap = roadmapFor(key)
+		}
+		return opt, nil
+	case HybridAStarPlanningAlg:
+		// no motion profiles for hybrid A*, as it plans how a base drives rather than how a pose moves
+		opt.PlannerConstructor = newHybridAStarMotionPlanner
+		return opt, nil
+	default:
+		// use default, already set
 	}
 
 	switch motionProfile {
 	case LinearMotionProfile:
 		// Linear constraints
-		linTol, ok := planningOpts["line_tolerance"].(float64)
-		if !ok {
-			// Default
-			linTol = defaultLinearDeviation
-		}
-		orientTol, ok := planningOpts["orient_tolerance"].(float64)
-		if !ok {
-			// Default
-			orientTol = defaultLinearDeviation
-		}
 		constraint, pathDist := NewAbsoluteLinearInterpolatingConstraint(from, to, linTol, orientTol)
 		opt.AddConstraint(defaultLinearConstraintName, constraint)
-		opt.pathDist = pathDist
+		pathDists = append(pathDists, pathDist)
 	case PseudolinearMotionProfile:
-		tolerance, ok := planningOpts["tolerance"].(float64)
-		if !ok {
-			// Default
-			tolerance = defaultPseudolinearTolerance
+		tolerance := attrs.GetFloat64("tolerance", defaultPseudolinearTolerance)
+		if err := attrs.Err(); err != nil {
+			return nil, err
 		}
 		constraint, pathDist := NewProportionalLinearInterpolatingConstraint(from, to, tolerance)
 		opt.AddConstraint(defaultPseudolinearConstraintName, constraint)
-		opt.pathDist = pathDist
+		pathDists = append(pathDists, pathDist)
 	case OrientationMotionProfile:
-		tolerance, ok := planningOpts["tolerance"].(float64)
-		if !ok {
-			// Default
-			tolerance = defaultOrientationDeviation
+		tolerance := attrs.GetFloat64("tolerance", defaultOrientationDeviation)
+		if err := attrs.Err(); err != nil {
+			return nil, err
 		}
 		constraint, pathDist := NewSlerpOrientationConstraint(from, to, tolerance)
 		opt.AddConstraint(defaultOrientationConstraintName, constraint)
-		opt.pathDist = pathDist
+		pathDists = append(pathDists, pathDist)
+	case UprightMotionProfile:
+		tolerance := attrs.GetFloat64("tolerance", defaultUprightTolerance)
+		// unless given an axis, the frame is held pointing the way it starts out
+		upright, err := uprightAxis(attrs, "upright_axis", from)
+		if err != nil {
+			return nil, err
+		}
+		constraint, pathDist := NewUprightConstraint(upright, tolerance)
+		opt.AddConstraint(defaultUprightConstraintName, constraint)
+		pathDists = append(pathDists, pathDist)
 	case PositionOnlyMotionProfile:
 		opt.SetMetric(NewPositionOnlyMetric())
 	case FreeMotionProfile:
 		// No restrictions on motion
 		fallthrough
 	default:
-		if planAlg == "" {
+		// the first attempt is cut off at a timeout, so its path depends on how fast planning was, and constrained paths are only
+		// planned by CBiRRT
+		if planAlg == "" && !deterministic && !hasConstraints {
 			// set up deep copy for fallback
 			try1 := deepAtomicCopyMap(planningOpts)
 			// No need to generate tons more IK solutions when the first alg will do it
 
 			// time to run the first planning attempt before falling back
 			try1["timeout"] = defaultFallbackTimeout
-			try1["planning_alg"] = "rrtstar"
+			try1["planning_alg"] = RRTStarPlanningAlg
 			try1Opt, err := pm.plannerSetupFromMoveRequest(from, to, seedMap, worldState, try1)
 			if err != nil {
 				return nil, err
@@ -522,6 +1261,10 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 			opt = try1Opt
 		}
 	}
+	if len(pathDists) > 0 {
+		// paths must satisfy every constraint, so poses are brought back within all of them at once
+		opt.pathDist = combinePathDists(pathDists)
+	}
 	return opt, nil
 }
 
@@ -532,7 +1275,7 @@ func goodPlan(pr *rrtPlanReturn, opt *plannerOptions) (bool, float64) {
 		if pr.maps.optNode.cost <= 0 {
 			return true, solutionCost
 		}
-		solutionCost = EvaluatePlan(pr.toInputs(), opt.DistanceFunc)
+		solutionCost = planCost(pr.toInputs(), opt)
 		if solutionCost < pr.maps.optNode.cost*defaultOptimalityMultiple {
 			return true, solutionCost
 		}
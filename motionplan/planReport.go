@@ -0,0 +1,59 @@
+package motionplan
+
+import (
+	"sync"
+	"time"
+)
+
+// PlanReport describes how a plan was found, so that slow plans and regressions in planning can be debugged without reading debug
+// logs. Counts and times are summed over every waypoint planned for.
+type PlanReport struct {
+	// Planning algorithm used, as named by the `planning_alg` option
+	Planner string
+	// Whether the plan was read from the plan cache, in which case nothing else is reported
+	Cached bool
+	// Number of atomic waypoints planned for, e.g. those a linear motion is broken up into
+	Waypoints int
+	// Number of nodes in the maps grown by RRT based planners
+	Nodes int
+	// Number of IK solutions found which met the constraints, and which did not. These are the seeds goal maps are grown from.
+	IKSolutions, IKRejections int
+	// Number of the IK solutions meeting the constraints which only met them after being moved through the nullspace of the frame
+	IKPerturbations int
+	// Number of times states were checked against the constraints of the plan
+	ConstraintChecks int
+	// Time spent solving IK, planning paths and smoothing them. As some of these run at the same time, the sum can be more than the
+	// total time spent planning.
+	IKTime, PlanningTime, SmoothingTime time.Duration
+	// Whether a fallback planner found a better path than the planner first tried
+	FallbackUsed bool
+	// Cost of the plan as measured by the distance function of the planner, and the lower bound on it from the distances between the
+	// start and the best IK solutions
+	Cost, OptimalCost float64
+}
+
+// planRecorder builds a PlanReport from planners which may run concurrently. A nil planRecorder records nothing.
+type planRecorder struct {
+	mu      sync.Mutex
+	current PlanReport
+}
+
+func (r *planRecorder) record(update func(*PlanReport)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	update(&r.current)
+}
+
+// report returns a copy of the report recorded so far.
+func (r *planRecorder) report() *PlanReport {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report := r.current
+	return &report
+}
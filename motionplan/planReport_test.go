@@ -0,0 +1,85 @@
+package motionplan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestPlanReport(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 40, Y: 40, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 60, Y: 60}))
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-60, -60})}
+	dir := t.TempDir()
+	ctx := ContextWithStorageDirs(context.Background(), StorageDirs{PlanCacheDir: dir})
+	opts := map[string]interface{}{"rseed": 1, "use_plan_cache": true}
+
+	plan, report, err := PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+	test.That(t, report.Planner, test.ShouldEqual, "cbirrt")
+	test.That(t, report.Cached, test.ShouldBeFalse)
+	test.That(t, report.Waypoints, test.ShouldEqual, 1)
+	test.That(t, report.Nodes, test.ShouldBeGreaterThan, 0)
+	test.That(t, report.IKSolutions, test.ShouldBeGreaterThan, 0)
+	test.That(t, report.ConstraintChecks, test.ShouldBeGreaterThan, 0)
+	test.That(t, report.IKTime, test.ShouldBeGreaterThan, 0)
+	test.That(t, report.PlanningTime, test.ShouldBeGreaterThan, 0)
+	test.That(t, report.OptimalCost, test.ShouldBeGreaterThan, 0)
+	// the path must go around the obstacle, so costs more than the straight line to the goal
+	test.That(t, report.Cost, test.ShouldBeGreaterThan, report.OptimalCost)
+
+	// planning the same motion again reads the plan from the cache
+	_, report, err = PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Cached, test.ShouldBeTrue)
+	test.That(t, report.Nodes, test.ShouldEqual, 0)
+
+	// a cached plan which no longer holds to the motion, here one changed to go straight through the obstacle, is planned again
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldHaveLength, 1)
+	test.That(t, os.WriteFile(files[0], []byte("[[-60,-60],[60,60]]"), 0o600), test.ShouldBeNil)
+	plans.clear()
+	_, report, err = PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Cached, test.ShouldBeFalse)
+	test.That(t, report.Nodes, test.ShouldBeGreaterThan, 0)
+
+	// planners fallen back to are recorded in the same report
+	sFrames, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	pm.recorder = &planRecorder{}
+	opt, err := pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal.Pose(), seedMap, worldState, map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, opt.Fallback, test.ShouldNotBeNil)
+	test.That(t, opt.recorder, test.ShouldEqual, pm.recorder)
+	test.That(t, opt.Fallback.recorder, test.ShouldEqual, pm.recorder)
+
+	// where plans are kept is not up to the options of a plan
+	opts["plan_cache_dir"] = t.TempDir()
+	_, _, err = PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldNotBeNil)
+}
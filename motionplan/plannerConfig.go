@@ -0,0 +1,418 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// PlannerConfig is the typed form of the planning options which PlanMotion and the other planning functions take as a map. Its JSON
+// form is the same as that map's, so a PlannerConfig can be turned into one with Map, and a map read into one with
+// NewPlannerConfigFromMap. Options left at their zero value are unset, and take their default.
+type PlannerConfig struct {
+	// How the pose of the frame may move on the way to the goal, one of the motion profiles such as LinearMotionProfile. Motion is
+	// free by default
+	MotionProfile string `json:"motion_profile,omitempty"`
+
+	// Distance in mm the linear motion profile may stray from the straight line from the start to the goal
+	LineTolerance float64 `json:"line_tolerance,omitempty"`
+
+	// Distance the linear motion profile may stray from the slerp from the orientation at the start to the goal
+	OrientTolerance float64 `json:"orient_tolerance,omitempty"`
+
+	// Tolerance of the pseudolinear, orientation and upright motion profiles
+	Tolerance float64 `json:"tolerance,omitempty"`
+
+	// Axis the upright motion profile keeps the frame pointing along, as 3 numbers, or the way it points at the start if unset
+	UprightAxis []float64 `json:"upright_axis,omitempty"`
+
+	// Distance in mm between the waypoints a linear motion is broken up into
+	PathStepSize float64 `json:"path_step_size,omitempty"`
+
+	// How the waypoints of a linear motion are interpolated, one of SeparateInterpolation, which moves the position in a straight line
+	// while the orientation slerps and is the default, and ScrewInterpolation, which moves along the screw motion from the start to the goal
+	Interpolation string `json:"interpolation,omitempty"`
+
+	// Limits on how the end effector of a linear motion moves, in mm per second, per second squared and per second cubed
+	MaxCartesianVelocity     float64 `json:"max_cartesian_velocity,omitempty"`
+	MaxCartesianAcceleration float64 `json:"max_cartesian_acceleration,omitempty"`
+	MaxCartesianJerk         float64 `json:"max_cartesian_jerk,omitempty"`
+
+	// Registered constraints to hold along the motion, see RegisterConstraint
+	Constraints []map[string]interface{} `json:"constraints,omitempty"`
+
+	// Region around the goal the motion may end anywhere within
+	GoalTolerance *GoalTolerance `json:"goal_tolerance,omitempty"`
+
+	// Geometry a frame must stay inside of, and the frame, which is the one being moved if unset
+	Workspace      *spatialmath.GeometryConfig `json:"workspace,omitempty"`
+	WorkspaceFrame string                      `json:"workspace_frame,omitempty"`
+
+	// Distance in mm at or below which geometries are considered to be in collision
+	CollisionBufferMM *float64 `json:"collision_buffer_mm,omitempty"`
+
+	// Pairs of geometries which may touch without it being a collision, as well as those declared by the frame system
+	AllowedCollisions []referenceframe.AllowedCollision `json:"allowed_collisions,omitempty"`
+
+	// Whether to also check the motion between the steps of paths for collisions, so that they cannot pass through thin obstacles
+	ContinuousCollision bool `json:"continuous_collision,omitempty"`
+
+	// Whether to first move the frame out of any obstacle it starts in
+	EscapeCollision bool `json:"escape_collision,omitempty"`
+
+	// Algorithm to plan with, one of the planning algorithms such as CBiRRTPlanningAlg. By default a timed RRT* attempt is made
+	// before planning with CBiRRT
+	PlanningAlg string `json:"planning_alg,omitempty"`
+
+	// Number of seconds before terminating planning
+	Timeout float64 `json:"timeout,omitempty"`
+
+	// Whether to keep improving the path until the timeout, returning the best found
+	Anytime bool `json:"anytime,omitempty"`
+
+	// Whether to return the path found so far towards the goal, rather than only an error, if planning times out
+	ReturnPartialPlan bool `json:"return_partial_plan,omitempty"`
+
+	// Whether the same planning request should always give the same plan, see plannerOptions
+	Deterministic bool `json:"deterministic,omitempty"`
+
+	// Seed of the randomness of planning
+	Rseed int `json:"rseed,omitempty"`
+
+	// Number of cpu cores to use
+	NumThreads int `json:"num_threads,omitempty"`
+
+	// Check constraints are still met every this many mm/degrees of movement
+	Resolution float64 `json:"resolution,omitempty"`
+
+	// Max number of IK solutions to consider
+	MaxIKSolutions int `json:"max_ik_solutions,omitempty"`
+
+	// IK solutions scoring below this are considered good enough and returned immediately
+	MinIKScore float64 `json:"min_ik_score,omitempty"`
+
+	// Number of IK solutions failing constraints after which to stop looking for more, 250 by default, or a negative number to keep
+	// looking until IK finishes
+	MaxIKRejections int `json:"max_ik_rejections,omitempty"`
+
+	// Name of a registered IK solver to use instead of the built in one, see RegisterIKSolver
+	IKSolver string `json:"ik_solver,omitempty"`
+
+	// Weights of the secondary objectives IK solutions are moved towards without moving the end effector, and the posture the
+	// `posture` objective moves towards
+	NullspaceObjectives map[string]float64 `json:"nullspace_objectives,omitempty"`
+	PreferredPosture    []float64          `json:"preferred_posture,omitempty"`
+
+	// Name of a registered distance function to measure how far apart inputs are with, see RegisterDistanceFunc
+	DistanceMetric string `json:"distance_metric,omitempty"`
+
+	// Weight of each input when measuring how far apart inputs are
+	JointWeights []float64 `json:"joint_weights,omitempty"`
+
+	// Number of times to try to smooth the path, and how, one of ShortcutSmoothMethod and SplineSmoothMethod
+	SmoothIter   int    `json:"smooth_iter,omitempty"`
+	SmoothMethod string `json:"smooth_method,omitempty"`
+
+	// Number of steps to resample a path into when fitting a spline through it
+	SplineSteps int `json:"spline_steps,omitempty"`
+
+	// Whether to optimize paths for length and clearance from obstacles after smoothing them, and how
+	OptimizePath      bool    `json:"optimize_path,omitempty"`
+	OptimizeSteps     int     `json:"optimize_steps,omitempty"`
+	OptimizeIter      int     `json:"optimize_iter,omitempty"`
+	OptimizeClearance float64 `json:"optimize_clearance,omitempty"`
+
+	// Cost of each mm a path passes closer to obstacles than OptimizeClearance, per unit of its length
+	ClearanceWeight float64 `json:"clearance_weight,omitempty"`
+
+	// Margin to keep inputs inside of their limits by, so that plans stay clear of the hard stops of the hardware, either in the units
+	// joint positions are reported in, degrees for revolute joints and mm for prismatic ones, or as a percentage of the range of each
+	JointLimitMargin        float64 `json:"joint_limit_margin,omitempty"`
+	JointLimitMarginPercent float64 `json:"joint_limit_margin_percent,omitempty"`
+
+	// Cost of inputs nearing their limits, per unit of path length, and how near, as a percentage of the range of each input
+	SoftLimitWeight      float64 `json:"soft_limit_weight,omitempty"`
+	SoftLimitZonePercent float64 `json:"soft_limit_zone_percent,omitempty"`
+
+	// Name of a registered sampler to draw configurations to plan through with, see RegisterSampler, and the spread of its samples
+	Sampler       string  `json:"sampler,omitempty"`
+	SamplerStdDev float64 `json:"sampler_std_dev,omitempty"`
+
+	// Whether to reuse plans found for the same problem before, which are kept in the directory set with ContextWithStorageDirs if any
+	UsePlanCache bool `json:"use_plan_cache,omitempty"`
+
+	// Whether to pick up growing RRT maps where planning among the same obstacles last left off, which are kept in the directory set
+	// with ContextWithStorageDirs if any
+	ReuseRRTMaps bool `json:"reuse_rrt_maps,omitempty"`
+
+	// Whether PRM reuses the roadmap built by earlier plans among the same obstacles, which it does unless planning deterministically
+	ReuseRoadmap *bool `json:"reuse_roadmap,omitempty"`
+
+	// Options of the RRT planners
+	PlanIter         int     `json:"plan_iter,omitempty"`
+	FrameStep        float64 `json:"frame_step,omitempty"`
+	JointSolveDist   float64 `json:"joint_solve_dist,omitempty"`
+	SolutionsToSeed  int     `json:"solutions_to_seed,omitempty"`
+	IterBeforeRand   int     `json:"iter_before_rand,omitempty"`
+	NeighborhoodSize int     `json:"neighborhood_size,omitempty"`
+
+	// Options of PRM
+	PRMBatchSize  int `json:"prm_batch_size,omitempty"`
+	PRMMaxSamples int `json:"prm_max_samples,omitempty"`
+
+	// Options of hybrid A*
+	TurningRadius          float64 `json:"turning_radius,omitempty"`
+	StepLength             float64 `json:"step_length,omitempty"`
+	CellSize               float64 `json:"cell_size,omitempty"`
+	HeadingBins            int     `json:"heading_bins,omitempty"`
+	AllowReverse           *bool   `json:"allow_reverse,omitempty"`
+	ReversePenalty         float64 `json:"reverse_penalty,omitempty"`
+	CollisionCheckDistance float64 `json:"collision_check_distance,omitempty"`
+	MaxExpansions          int     `json:"max_expansions,omitempty"`
+
+	// Percentage interval of max iterations after which to print debug logs
+	LoggingInterval float64 `json:"logging_interval,omitempty"`
+
+	// Options not listed above, such as those read by registered samplers, by their name in the map form
+	Extra map[string]interface{} `json:"-"`
+}
+
+// plannerConfigKeys are the names of the options which have a field of their own in PlannerConfig.
+var plannerConfigKeys = func() map[string]bool {
+	keys := map[string]bool{}
+	configType := reflect.TypeOf(PlannerConfig{})
+	for i := 0; i < configType.NumField(); i++ {
+		if name := strings.Split(configType.Field(i).Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}()
+
+// NewPlannerConfigFromMap reads planning options given as a map into a PlannerConfig, keeping those without a field of their own in
+// Extra. It returns an error if an option has the wrong type.
+func NewPlannerConfigFromMap(planningOpts map[string]interface{}) (*PlannerConfig, error) {
+	jsonString, err := json.Marshal(planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &PlannerConfig{}
+	if err := json.Unmarshal(jsonString, cfg); err != nil {
+		return nil, err
+	}
+	for key, value := range planningOpts {
+		if !plannerConfigKeys[key] {
+			if cfg.Extra == nil {
+				cfg.Extra = map[string]interface{}{}
+			}
+			cfg.Extra[key] = value
+		}
+	}
+	return cfg, nil
+}
+
+// Map returns the planning options as the map taken by PlanMotion and the other planning functions.
+func (c *PlannerConfig) Map() (map[string]interface{}, error) {
+	jsonString, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	planningOpts := map[string]interface{}{}
+	if err := json.Unmarshal(jsonString, &planningOpts); err != nil {
+		return nil, err
+	}
+	for key, value := range c.Extra {
+		if plannerConfigKeys[key] {
+			return nil, errors.Errorf("option %q has a field of its own, so cannot be given in Extra", key)
+		}
+		planningOpts[key] = value
+	}
+	return planningOpts, nil
+}
+
+// Validate returns an error if the options are unsupported or cannot be used together. Options depending on what is planned for,
+// such as IK solvers registered for some models, are only checked once planning starts.
+func (c *PlannerConfig) Validate() error {
+	switch c.MotionProfile {
+	case "", FreeMotionProfile, LinearMotionProfile, PseudolinearMotionProfile, OrientationMotionProfile, PositionOnlyMotionProfile,
+		UprightMotionProfile:
+	default:
+		return errors.Errorf("unsupported motion_profile %q", c.MotionProfile)
+	}
+	switch c.Interpolation {
+	case "", SeparateInterpolation, ScrewInterpolation:
+	default:
+		return errors.Errorf("unsupported interpolation %q", c.Interpolation)
+	}
+	switch c.PlanningAlg {
+	case "", CBiRRTPlanningAlg, RRTStarPlanningAlg, PRMPlanningAlg, HybridAStarPlanningAlg:
+	default:
+		return errors.Errorf("unsupported planning_alg %q", c.PlanningAlg)
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout cannot be negative")
+	}
+	if c.CollisionBufferMM != nil && *c.CollisionBufferMM < 0 {
+		return errors.New("collision_buffer_mm cannot be negative")
+	}
+	// the directories plans are written to are part of the configuration of the robot, as planning options may come from any client
+	for _, name := range []string{"plan_cache_dir", "rrt_map_dir"} {
+		if _, ok := c.Extra[name]; ok {
+			return errors.Errorf("%s can only be set in the config of the motion service", name)
+		}
+	}
+	for _, pair := range c.AllowedCollisions {
+		if pair.Geometry1 == "" || pair.Geometry2 == "" {
+			return errors.New("allowed_collisions must name two geometries")
+		}
+	}
+	if len(c.UprightAxis) != 0 && len(c.UprightAxis) != 3 {
+		return errors.Errorf("upright_axis must have 3 values, got %d", len(c.UprightAxis))
+	}
+	if err := (CartesianLimits{c.MaxCartesianVelocity, c.MaxCartesianAcceleration, c.MaxCartesianJerk}).validate(); err != nil {
+		return err
+	}
+
+	if c.Anytime {
+		if c.Deterministic {
+			return errors.New("anytime planning cannot be deterministic, as the path found depends on how long planning runs for")
+		}
+		// only RRT* keeps improving its path, and it would otherwise run until the default timeout
+		if c.PlanningAlg != "" && c.PlanningAlg != RRTStarPlanningAlg {
+			return errors.Errorf("anytime planning is not supported by planning_alg %q", c.PlanningAlg)
+		}
+		if c.Timeout == 0 {
+			return errors.New("anytime planning requires a timeout")
+		}
+	}
+
+	switch c.SmoothMethod {
+	case "", ShortcutSmoothMethod, SplineSmoothMethod:
+	default:
+		return errors.Errorf("unsupported smooth_method %q", c.SmoothMethod)
+	}
+	if c.ClearanceWeight < 0 {
+		return errors.New("clearance_weight cannot be negative")
+	}
+	if c.JointLimitMargin < 0 || c.JointLimitMarginPercent < 0 {
+		return errors.New("joint_limit_margin cannot be negative")
+	}
+	if c.JointLimitMargin > 0 && c.JointLimitMarginPercent > 0 {
+		return errors.New("joint_limit_margin and joint_limit_margin_percent cannot both be set")
+	}
+	if c.JointLimitMarginPercent >= 50 {
+		return errors.New("joint_limit_margin_percent must be less than 50")
+	}
+	if c.SoftLimitWeight < 0 {
+		return errors.New("soft_limit_weight cannot be negative")
+	}
+	if c.SoftLimitZonePercent < 0 || c.SoftLimitZonePercent > 50 {
+		return errors.New("soft_limit_zone_percent must be positive and at most 50")
+	}
+	if c.PlanningAlg == HybridAStarPlanningAlg {
+		if c.SmoothMethod == SplineSmoothMethod {
+			return errors.New("smooth_method spline is not supported by planning_alg hybridastar, as splined paths may not be drivable")
+		}
+		if c.OptimizePath {
+			return errors.New("optimize_path is not supported by planning_alg hybridastar, as optimized paths may not be drivable")
+		}
+		if c.Sampler != "" {
+			return errors.New("sampler is not supported by planning_alg hybridastar, as it searches a lattice of motions rather than sampling")
+		}
+	}
+	if c.Sampler != "" {
+		if _, err := samplerFor(c.Sampler); err != nil {
+			return err
+		}
+	}
+	if c.SamplerStdDev < 0 {
+		return errors.New("sampler_std_dev must be positive")
+	}
+
+	if c.DistanceMetric != "" {
+		if _, err := distanceFuncFor(c.DistanceMetric); err != nil {
+			return err
+		}
+	}
+	for _, weight := range c.JointWeights {
+		if weight < 0 {
+			return errors.New("joint_weights cannot be negative")
+		}
+	}
+
+	if c.GoalTolerance != nil {
+		if err := c.GoalTolerance.validate(); err != nil {
+			return err
+		}
+		if c.MotionProfile == LinearMotionProfile || c.MotionProfile == PositionOnlyMotionProfile {
+			return errors.Errorf("goal_tolerance is not supported by motion profile %q", c.MotionProfile)
+		}
+	}
+	if c.Constraints != nil {
+		// only CBiRRT brings poses back within constraints
+		if c.PlanningAlg != "" && c.PlanningAlg != CBiRRTPlanningAlg {
+			return errors.Errorf("constraints are not supported by planning_alg %q", c.PlanningAlg)
+		}
+	}
+	if c.ReuseRRTMaps {
+		if c.Deterministic {
+			return errors.New("reuse_rrt_maps cannot be deterministic, as reused maps depend on what was planned before")
+		}
+		// maps grown within the constraints of a motion profile only hold paths valid for the poses it was planned between
+		if c.MotionProfile != "" && c.MotionProfile != FreeMotionProfile {
+			return errors.Errorf("reuse_rrt_maps is not supported by motion profile %q", c.MotionProfile)
+		}
+		if c.Constraints != nil {
+			return errors.New("reuse_rrt_maps is not supported with constraints")
+		}
+		if c.PlanningAlg != "" && c.PlanningAlg != CBiRRTPlanningAlg && c.PlanningAlg != RRTStarPlanningAlg {
+			return errors.Errorf("reuse_rrt_maps is not supported by planning_alg %q", c.PlanningAlg)
+		}
+	}
+	return nil
+}
+
+// NewLinearMotionConfig returns planning options moving the frame in a straight line to the goal, straying at most lineTolerance mm
+// from it, and orientTolerance from the slerp between the orientations at the start and the goal. Zero tolerances take the default.
+func NewLinearMotionConfig(lineTolerance, orientTolerance float64) *PlannerConfig {
+	return &PlannerConfig{MotionProfile: LinearMotionProfile, LineTolerance: lineTolerance, OrientTolerance: orientTolerance}
+}
+
+// NewPseudolinearMotionConfig returns planning options moving the frame roughly in a straight line to the goal, straying from it by
+// at most tolerance as a proportion of the distance moved. A zero tolerance takes the default.
+func NewPseudolinearMotionConfig(tolerance float64) *PlannerConfig {
+	return &PlannerConfig{MotionProfile: PseudolinearMotionProfile, Tolerance: tolerance}
+}
+
+// NewOrientationMotionConfig returns planning options keeping the orientation of the frame within tolerance of the slerp between its
+// orientations at the start and the goal, wherever it moves. A zero tolerance takes the default.
+func NewOrientationMotionConfig(tolerance float64) *PlannerConfig {
+	return &PlannerConfig{MotionProfile: OrientationMotionProfile, Tolerance: tolerance}
+}
+
+// NewUprightMotionConfig returns planning options keeping the frame pointing within tolerance radians of axis, or of the way it points
+// at the start if axis is zero. A zero tolerance takes the default.
+func NewUprightMotionConfig(axis r3.Vector, tolerance float64) *PlannerConfig {
+	cfg := &PlannerConfig{MotionProfile: UprightMotionProfile, Tolerance: tolerance}
+	if axis.Norm() > 0 {
+		cfg.UprightAxis = []float64{axis.X, axis.Y, axis.Z}
+	}
+	return cfg
+}
+
+// NewPositionOnlyMotionConfig returns planning options moving the frame to the position of the goal in any orientation.
+func NewPositionOnlyMotionConfig() *PlannerConfig {
+	return &PlannerConfig{MotionProfile: PositionOnlyMotionProfile}
+}
+
+// NewFreeMotionConfig returns planning options moving the frame to the goal any way it can, with the given algorithm, or by default
+// if it is empty.
+func NewFreeMotionConfig(planningAlg string) *PlannerConfig {
+	return &PlannerConfig{MotionProfile: FreeMotionProfile, PlanningAlg: planningAlg}
+}
@@ -0,0 +1,137 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestPlannerConfigMap(t *testing.T) {
+	buffer := 2.
+	cfg := &PlannerConfig{
+		MotionProfile:     OrientationMotionProfile,
+		Tolerance:         0.3,
+		PlanningAlg:       CBiRRTPlanningAlg,
+		Timeout:           5,
+		CollisionBufferMM: &buffer,
+		GoalTolerance:     &GoalTolerance{Z: 10},
+		JointWeights:      []float64{1, 2},
+		Extra:             map[string]interface{}{"test_option": "value"},
+	}
+	planningOpts, err := cfg.Map()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, planningOpts["motion_profile"], test.ShouldEqual, OrientationMotionProfile)
+	test.That(t, planningOpts["collision_buffer_mm"], test.ShouldEqual, 2.)
+	test.That(t, planningOpts["test_option"], test.ShouldEqual, "value")
+	// unset options are left out, so they take their default
+	_, ok := planningOpts["anytime"]
+	test.That(t, ok, test.ShouldBeFalse)
+
+	roundtrip, err := NewPlannerConfigFromMap(planningOpts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, roundtrip, test.ShouldResemble, cfg)
+
+	// options in the map form, as given by older callers, read into their fields
+	fromMap, err := NewPlannerConfigFromMap(map[string]interface{}{
+		"motion_profile":      LinearMotionProfile,
+		"line_tolerance":      1,
+		"rseed":               3,
+		"collision_buffer_mm": 0,
+		"reuse_roadmap":       false,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fromMap.MotionProfile, test.ShouldEqual, LinearMotionProfile)
+	test.That(t, fromMap.LineTolerance, test.ShouldEqual, 1.)
+	test.That(t, fromMap.Rseed, test.ShouldEqual, 3)
+	test.That(t, *fromMap.CollisionBufferMM, test.ShouldEqual, 0.)
+	test.That(t, *fromMap.ReuseRoadmap, test.ShouldBeFalse)
+	test.That(t, fromMap.Extra, test.ShouldBeNil)
+
+	_, err = NewPlannerConfigFromMap(map[string]interface{}{"timeout": "soon"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = (&PlannerConfig{Timeout: 1, Extra: map[string]interface{}{"timeout": 2}}).Map()
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPlannerConfigValidate(t *testing.T) {
+	negative := -1.
+	for _, cfg := range []*PlannerConfig{
+		{},
+		NewLinearMotionConfig(0.1, 0.1),
+		NewPseudolinearMotionConfig(0.5),
+		NewOrientationMotionConfig(0.3),
+		NewUprightMotionConfig(r3.Vector{Z: 1}, 0.2),
+		NewPositionOnlyMotionConfig(),
+		NewFreeMotionConfig(PRMPlanningAlg),
+		{Anytime: true, Timeout: 1},
+		{ReuseRRTMaps: true, PlanningAlg: RRTStarPlanningAlg},
+		{Sampler: "gaussian", SamplerStdDev: 0.1, PlanningAlg: RRTStarPlanningAlg},
+	} {
+		test.That(t, cfg.Validate(), test.ShouldBeNil)
+	}
+	test.That(t, NewUprightMotionConfig(r3.Vector{}, 0).UprightAxis, test.ShouldBeNil)
+
+	for _, cfg := range []*PlannerConfig{
+		{MotionProfile: "sideways"},
+		{Interpolation: "bezier", MotionProfile: LinearMotionProfile},
+		{PlanningAlg: "astar"},
+		{Timeout: -1},
+		{CollisionBufferMM: &negative},
+		{UprightAxis: []float64{0, 1}},
+		{MaxCartesianVelocity: -1},
+		{Anytime: true},
+		{Anytime: true, Timeout: 1, Deterministic: true},
+		{Anytime: true, Timeout: 1, PlanningAlg: CBiRRTPlanningAlg},
+		{SmoothMethod: "wiggle"},
+		{ClearanceWeight: -1},
+		{PlanningAlg: HybridAStarPlanningAlg, SmoothMethod: SplineSmoothMethod},
+		{PlanningAlg: HybridAStarPlanningAlg, OptimizePath: true},
+		{PlanningAlg: HybridAStarPlanningAlg, Sampler: "uniform"},
+		{Sampler: "test_missing"},
+		{SamplerStdDev: -1},
+		{DistanceMetric: "test_missing"},
+		{JointWeights: []float64{1, -1}},
+		{GoalTolerance: &GoalTolerance{X: -1}},
+		{GoalTolerance: &GoalTolerance{X: 1}, MotionProfile: LinearMotionProfile},
+		{Constraints: []map[string]interface{}{{"type": "line"}}, PlanningAlg: PRMPlanningAlg},
+		{ReuseRRTMaps: true, Deterministic: true},
+		{ReuseRRTMaps: true, MotionProfile: LinearMotionProfile},
+		{ReuseRRTMaps: true, Constraints: []map[string]interface{}{{"type": "line"}}},
+		{ReuseRRTMaps: true, PlanningAlg: PRMPlanningAlg},
+	} {
+		test.That(t, cfg.Validate(), test.ShouldNotBeNil)
+	}
+}
+
+func TestPlanMotionWithConfig(t *testing.T) {
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-50, 0})}
+	goal := frame.NewPoseInFrame(frame.World, spatial.NewPoseFromPoint(r3.Vector{X: 50}))
+
+	cfg := NewFreeMotionConfig(RRTStarPlanningAlg)
+	cfg.Rseed = 1
+	cfg.NumThreads = 1
+	plan, err := PlanMotionWithConfig(context.Background(), logger.Sugar(), goal, model, seedMap, fs, nil, cfg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+
+	plan, err = PlanMotionWithConfig(context.Background(), logger.Sugar(), goal, model, seedMap, fs, nil, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThanOrEqualTo, 2)
+
+	_, err = PlanMotionWithConfig(context.Background(), logger.Sugar(), goal, model, seedMap, fs, nil, &PlannerConfig{PlanningAlg: "astar"})
+	test.That(t, err, test.ShouldNotBeNil)
+	// the map form is checked the same way
+	_, err = PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, nil, map[string]interface{}{"planning_alg": "astar"})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -3,8 +3,13 @@ package motionplan
 import (
 	"math"
 	"runtime"
+	"sync"
 
+	"github.com/pkg/errors"
 	"gonum.org/v1/gonum/floats"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
 )
 
 // default values for planning options.
@@ -15,6 +20,9 @@ const (
 	// allowable deviation from slerp between start/goal orientations, unit is the norm of the R3AA between start and goal.
 	defaultOrientationDeviation = 0.05
 
+	// allowable angle in radians between the orientation vector of a frame and the axis it is held upright along.
+	defaultUprightTolerance = 0.1
+
 	// allowable linear and orientation deviation from direct interpolation path, as a proportion of the linear and orientation distances
 	// between the start and goal.
 	defaultPseudolinearTolerance = 0.8
@@ -22,6 +30,9 @@ const (
 	// Number of IK solutions that should be generated before stopping.
 	defaultSolutionsToSeed = 50
 
+	// Number of IK solutions failing constraints after which to stop looking for more, so that goals in cluttered scenes fail fast.
+	defaultMaxIKRejections = 5 * defaultSolutionsToSeed
+
 	// Check constraints are still met every this many mm/degrees of movement.
 	defaultResolution = 2.0
 
@@ -37,13 +48,20 @@ const (
 	// default number of times to try to smooth the path.
 	defaultSmoothIter = 20
 
+	// default distance in mm at or below which geometries are considered to be in collision.
+	defaultCollisionBufferMM = spatialmath.CollisionBuffer
+
 	// names of constraints.
-	defaultLinearConstraintName        = "defaultLinearConstraint"
-	defaultPseudolinearConstraintName  = "defaultPseudolinearConstraint"
-	defaultOrientationConstraintName   = "defaultOrientationConstraint"
-	defaultObstacleConstraintName      = "defaultObstacleConstraint"
-	defaultSelfCollisionConstraintName = "defaultSelfCollisionConstraint"
-	defaultJointConstraint             = "defaultJointSwingConstraint"
+	defaultLinearConstraintName         = "defaultLinearConstraint"
+	defaultPseudolinearConstraintName   = "defaultPseudolinearConstraint"
+	defaultOrientationConstraintName    = "defaultOrientationConstraint"
+	defaultUprightConstraintName        = "defaultUprightConstraint"
+	defaultWorkspaceConstraintName      = "defaultWorkspaceConstraint"
+	defaultObstacleConstraintName       = "defaultObstacleConstraint"
+	defaultSelfCollisionConstraintName  = "defaultSelfCollisionConstraint"
+	defaultMovingObstacleConstraintName = "defaultMovingObstacleConstraint"
+	defaultJointConstraint              = "defaultJointSwingConstraint"
+	defaultJointLimitConstraintName     = "defaultJointLimitConstraint"
 
 	// When breaking down a path into smaller waypoints, add a waypoint every this many mm of movement.
 	defaultPathStepSize = 10
@@ -61,6 +79,27 @@ const (
 	PseudolinearMotionProfile = "pseudolinear"
 	OrientationMotionProfile  = "orientation"
 	PositionOnlyMotionProfile = "position_only"
+	UprightMotionProfile      = "upright"
+)
+
+// the set of supported planning algorithms.
+const (
+	CBiRRTPlanningAlg      = "cbirrt"
+	RRTStarPlanningAlg     = "rrtstar"
+	PRMPlanningAlg         = "prm"
+	HybridAStarPlanningAlg = "hybridastar"
+)
+
+// the set of supported path smoothing methods.
+const (
+	ShortcutSmoothMethod = "shortcut"
+	SplineSmoothMethod   = "spline"
+)
+
+// the set of supported ways to interpolate the waypoints a linear motion is broken up into.
+const (
+	SeparateInterpolation = "separate"
+	ScrewInterpolation    = "screw"
 )
 
 // defaultDistanceFunc returns the square of the two-norm between the StartInput and EndInput vectors in the given ConstraintInput.
@@ -73,6 +112,55 @@ func defaultDistanceFunc(ci *ConstraintInput) (bool, float64) {
 	return true, floats.Norm(diff, 2)
 }
 
+// NewWeightedDistanceFunc returns a distance function which scales the difference in each input by its weight before measuring it
+// with distFunc, e.g. so that moving the wrist of an arm is cheap while moving its shoulder is expensive. A nil distFunc measures
+// the weighted differences with the default two-norm.
+func NewWeightedDistanceFunc(weights []float64, distFunc Constraint) Constraint {
+	if distFunc == nil {
+		distFunc = defaultDistanceFunc
+	}
+	weigh := func(inputs []referenceframe.Input) []referenceframe.Input {
+		weighted := make([]referenceframe.Input, 0, len(inputs))
+		for i, input := range inputs {
+			weighted = append(weighted, referenceframe.Input{Value: input.Value * weights[i]})
+		}
+		return weighted
+	}
+	return func(ci *ConstraintInput) (bool, float64) {
+		return distFunc(&ConstraintInput{StartInput: weigh(ci.StartInput), EndInput: weigh(ci.EndInput), Frame: ci.Frame})
+	}
+}
+
+var (
+	distanceFuncsMu sync.Mutex
+	distanceFuncs   = map[string]Constraint{"l2": defaultDistanceFunc}
+)
+
+// RegisterDistanceFunc registers a distance function between inputs under a name, so that planners can be told to use it by setting
+// `distance_metric` to that name in the planning options. It panics if the name is already registered.
+func RegisterDistanceFunc(name string, distFunc Constraint) {
+	distanceFuncsMu.Lock()
+	defer distanceFuncsMu.Unlock()
+	if _, ok := distanceFuncs[name]; ok {
+		panic(errors.Errorf("trying to register two distance functions with the same name %q", name))
+	}
+	if distFunc == nil {
+		panic(errors.Errorf("cannot register a nil distance function for %q", name))
+	}
+	distanceFuncs[name] = distFunc
+}
+
+// distanceFuncFor returns the distance function registered under name.
+func distanceFuncFor(name string) (Constraint, error) {
+	distanceFuncsMu.Lock()
+	defer distanceFuncsMu.Unlock()
+	distFunc, ok := distanceFuncs[name]
+	if !ok {
+		return nil, errors.Errorf("no distance function registered with the name %q", name)
+	}
+	return distFunc, nil
+}
+
 // NewBasicPlannerOptions specifies a set of basic options for the planner.
 func newBasicPlannerOptions() *plannerOptions {
 	opt := &plannerOptions{}
@@ -82,6 +170,7 @@ func newBasicPlannerOptions() *plannerOptions {
 
 	// Set defaults
 	opt.MaxSolutions = defaultSolutionsToSeed
+	opt.MaxIKRejections = defaultMaxIKRejections
 	opt.MinScore = defaultMinIkScore
 	opt.Resolution = defaultResolution
 	opt.Timeout = defaultTimeout
@@ -93,9 +182,17 @@ func newBasicPlannerOptions() *plannerOptions {
 	opt.PlannerConstructor = newCBiRRTMotionPlanner
 
 	opt.SmoothIter = defaultSmoothIter
+	opt.SmoothMethod = ShortcutSmoothMethod
+	opt.SplineSteps = defaultSplineSteps
 
 	opt.NumThreads = defaultNumThreads
 
+	opt.OptimizeSteps = defaultOptimizeSteps
+	opt.OptimizeIter = defaultOptimizeIter
+	opt.OptimizeClearance = defaultOptimizeClearance
+
+	opt.SoftLimitZonePercent = defaultSoftLimitZonePercent
+
 	return opt
 }
 
@@ -122,12 +219,74 @@ type plannerOptions struct {
 	// Number of seconds before terminating planner
 	Timeout float64 `json:"timeout"`
 
+	// Whether to return the path found so far towards the goal, rather than only an error, if the planner times out
+	ReturnPartialPlan bool `json:"return_partial_plan"`
+
 	// Number of times to try to smooth the path
 	SmoothIter int `json:"smooth_iter"`
 
+	// How to smooth paths: by shortcutting between their waypoints, or by then also fitting a spline through them
+	SmoothMethod string `json:"smooth_method"`
+
+	// Number of steps to resample a path into when fitting a spline through it
+	SplineSteps int `json:"spline_steps"`
+
 	// Number of cpu cores to use
 	NumThreads int `json:"num_threads"`
 
+	// Whether the same planning request should always give the same plan, e.g. for CI and debugging. All randomness is then derived
+	// from the `rseed` option, or 0 if it is not set, in this order: the plan manager for the i-th goal is seeded with rseed+i, and
+	// draws the seeds of the planners of its atomic waypoints in order, then of any fallback planner as each waypoint is planned. Each
+	// planner draws the seed of each IK solve and sample from its own. Planning is done on one thread, one waypoint at a time, without
+	// timed fallbacks. Plans cut short by a timeout still depend on how fast planning was.
+	Deterministic bool `json:"deterministic"`
+
+	// Whether to optimize paths for length and clearance from obstacles after smoothing them
+	OptimizePath bool `json:"optimize_path"`
+
+	// Number of steps to divide a path into when optimizing it
+	OptimizeSteps int `json:"optimize_steps"`
+
+	// Number of gradient descent iterations to optimize a path for
+	OptimizeIter int `json:"optimize_iter"`
+
+	// Distance in mm from obstacles within which an optimized path is pushed away from them
+	OptimizeClearance float64 `json:"optimize_clearance"`
+
+	// Cost of each mm a path passes closer to obstacles than OptimizeClearance, per unit of its length, added to the cost of paths when
+	// choosing between them, so that paths keeping clear of obstacles are preferred if they are not much longer
+	ClearanceWeight float64 `json:"clearance_weight"`
+
+	// Cost of inputs nearing their limits, added to the cost of paths when choosing between them as ClearanceWeight is, so that paths
+	// keeping away from the hard stops of the hardware are preferred. Each input costs the square of how far it is into the zone
+	// SoftLimitZonePercent of its range wide nearest either of its limits, as a fraction of the zone's width, per unit of path length
+	SoftLimitWeight      float64 `json:"soft_limit_weight"`
+	SoftLimitZonePercent float64 `json:"soft_limit_zone_percent"`
+
+	// Limits inputs are planned within, narrower than those of the frame by the joint limit margin, if any is set
+	jointLimits []referenceframe.Limit
+
+	// Collision constraint against moving obstacles where they are at a time, which scheduled paths are checked against, if any
+	movingObstacles TimedConstraint
+
+	// Function measuring distance to obstacles, used when optimizing paths
+	clearance clearanceFunc
+
+	// Secondary objective lowered by IK solutions without moving the end effector, if any
+	nullspace nullspaceObjective
+
+	// Where to record how planning went, if anywhere
+	recorder *planRecorder
+
+	// Number of IK solutions failing constraints after which to stop looking for more, or 0 or less to keep looking until IK finishes
+	MaxIKRejections int `json:"max_ik_rejections"`
+
+	// Name of a registered IK solver to use instead of the built in one, see RegisterIKSolver
+	IKSolver string `json:"ik_solver"`
+
+	// Name of a registered sampler to draw configurations to plan through with, instead of sampling uniformly, see RegisterSampler
+	Sampler string `json:"sampler"`
+
 	// Function to use to measure distance between two inputs
 	// TODO(rb): this should really become a Metric once we change the way the constraint system works, its awkward to return 2 values here
 	DistanceFunc Constraint
@@ -135,6 +294,15 @@ type plannerOptions struct {
 	PlannerConstructor plannerConstructor
 
 	Fallback *plannerOptions
+
+	// The roadmap shared by PRM planners solving the same planning problem
+	roadmap *roadmap
+
+	// Key the RRT maps grown by planners are stored under for later planners to pick up from, if they are stored
+	rrtMapKey string
+
+	// Directory the RRT maps are also written to, so that they outlive the process, if any
+	rrtMapDir string
 }
 
 // SetMetric sets the distance metric for the solver.
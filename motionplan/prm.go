@@ -0,0 +1,324 @@
+package motionplan
+
+import (
+	"container/heap"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/edaniels/golog"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// The number of random samples added to the roadmap each time it cannot connect the start to a goal.
+	defaultPRMBatchSize = 100
+
+	// The number of samples a single query may add to the roadmap before giving up.
+	defaultPRMMaxSamples = 5000
+
+	// The number of roadmaps kept for reuse. The least recently used one is dropped to make room for a new one.
+	defaultRoadmapCacheSize = 8
+)
+
+type prmOptions struct {
+	// The number of nearest roadmap nodes each new node tries to connect to
+	NeighborhoodSize int `json:"neighborhood_size"`
+
+	// The number of random samples added to the roadmap each time it cannot connect the start to a goal
+	BatchSize int `json:"prm_batch_size"`
+
+	// The number of samples a single query may add to the roadmap before giving up
+	MaxSamples int `json:"prm_max_samples"`
+}
+
+// newPRMOptions creates a struct controlling the running of a single invocation of the algorithm.
+// All values are pre-set to reasonable defaults, but can be tweaked if needed.
+func newPRMOptions(planOpts *plannerOptions) (*prmOptions, error) {
+	algOpts := &prmOptions{
+		NeighborhoodSize: defaultNeighborhoodSize,
+		BatchSize:        defaultPRMBatchSize,
+		MaxSamples:       defaultPRMMaxSamples,
+	}
+	// convert map to json
+	jsonString, err := json.Marshal(planOpts.extra)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(jsonString, algOpts)
+	if err != nil {
+		return nil, err
+	}
+	return algOpts, nil
+}
+
+// prmMotionPlanner plans paths through a probabilistic roadmap, Kavraki et al 1996
+// https://ieeexplore.ieee.org/document/508439
+// Building the roadmap is the expensive part of planning, and it only depends on the frame and the obstacles it must avoid, so a
+// roadmap is kept and grown across queries in the same world state, making later queries much faster than the first.
+type prmMotionPlanner struct {
+	*planner
+	algOpts *prmOptions
+	roadmap *roadmap
+}
+
+// newPRMMotionPlanner creates a prmMotionPlanner object. It plans through the roadmap of its options if they have one, and through
+// a roadmap of its own otherwise.
+func newPRMMotionPlanner(
+	frame referenceframe.Frame,
+	seed *rand.Rand,
+	logger golog.Logger,
+	opt *plannerOptions,
+) (motionPlanner, error) {
+	if opt == nil {
+		opt = newBasicPlannerOptions()
+	}
+	mp, err := newPlanner(frame, seed, logger, opt)
+	if err != nil {
+		return nil, err
+	}
+	algOpts, err := newPRMOptions(opt)
+	if err != nil {
+		return nil, err
+	}
+	rm := opt.roadmap
+	if rm == nil {
+		rm = newRoadmap()
+	}
+	return &prmMotionPlanner{mp, algOpts, rm}, nil
+}
+
+func (mp *prmMotionPlanner) plan(ctx context.Context,
+	goal spatialmath.Pose,
+	seed []referenceframe.Input,
+) ([][]referenceframe.Input, error) {
+	mp.logger.Debug("Starting PRM")
+
+	solutions, err := mp.getSolutions(ctx, goal, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for direct interpolation for the subset of IK solutions within some multiple of optimal, as the RRT planners do
+	_, optimalCost := mp.planOpts.DistanceFunc(&ConstraintInput{StartInput: seed, EndInput: solutions[0].Q()})
+	for _, solution := range solutions {
+		_, cost := mp.planOpts.DistanceFunc(&ConstraintInput{StartInput: seed, EndInput: solution.Q()})
+		if cost >= optimalCost*defaultOptimalityMultiple {
+			break
+		}
+		if mp.checkPath(seed, solution.Q()) {
+			return [][]referenceframe.Input{seed, solution.Q()}, nil
+		}
+	}
+
+	mp.roadmap.mu.Lock()
+	defer mp.roadmap.mu.Unlock()
+
+	mp.logger.Debugf("PRM querying roadmap of %d nodes", len(mp.roadmap.nodes))
+	start := mp.addToRoadmap(seed)
+	goals := make(map[*prmNode]bool, len(solutions))
+	for _, solution := range solutions {
+		goals[mp.addToRoadmap(solution.Q())] = true
+	}
+
+	for sampled := 0; ; {
+		if path := mp.roadmap.shortestPath(start, goals); path != nil {
+			mp.logger.Debugf("PRM found path after adding %d samples, roadmap has %d nodes", sampled, len(mp.roadmap.nodes))
+			steps := make([][]referenceframe.Input, 0, len(path))
+			for _, step := range mp.smoothPath(ctx, path) {
+				steps = append(steps, step.Q())
+			}
+			return steps, nil
+		}
+		if sampled >= mp.algOpts.MaxSamples {
+			mp.logger.Debugf("PRM exceeded %d samples", mp.algOpts.MaxSamples)
+			return nil, errPlannerFailed
+		}
+		for i := 0; i < mp.algOpts.BatchSize && sampled < mp.algOpts.MaxSamples; i++ {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("prm timeout %w", ctx.Err())
+			default:
+			}
+			sampled++
+			if q := mp.drawSample(seed, solutions[0].Q(), math.Inf(1)); mp.checkInputs(q) {
+				mp.addToRoadmap(q)
+			}
+		}
+	}
+}
+
+// addToRoadmap returns the roadmap node at q, adding it and connecting it to its nearest neighbors if there is none.
+// The roadmap must be locked.
+func (mp *prmMotionPlanner) addToRoadmap(q []referenceframe.Input) *prmNode {
+	neighbors := mp.roadmap.nearest(mp.planOpts, q, mp.algOpts.NeighborhoodSize)
+	if len(neighbors) > 0 && neighbors[0].dist < defaultEpsilon {
+		return neighbors[0].node.(*prmNode)
+	}
+	newNode := &prmNode{q: q, edges: map[*prmNode]float64{}}
+	for _, neighbor := range neighbors {
+		neighborNode := neighbor.node.(*prmNode)
+		if mp.checkPath(neighborNode.q, q) {
+			newNode.edges[neighborNode] = neighbor.dist
+			neighborNode.edges[newNode] = neighbor.dist
+		}
+	}
+	mp.roadmap.nodes = append(mp.roadmap.nodes, newNode)
+	return newNode
+}
+
+type prmNode struct {
+	q     []referenceframe.Input
+	edges map[*prmNode]float64
+}
+
+func (n *prmNode) Q() []referenceframe.Input {
+	return n.q
+}
+
+// roadmap is a graph of configurations, connected wherever the path between them is valid. It is shared by every query that plans
+// through it, so it must be locked while in use.
+type roadmap struct {
+	mu    sync.Mutex
+	nodes []*prmNode
+}
+
+func newRoadmap() *roadmap {
+	return &roadmap{}
+}
+
+// nearest returns up to k roadmap nodes nearest to q, nearest first.
+func (rm *roadmap) nearest(planOpts *plannerOptions, q []referenceframe.Input, k int) []*neighbor {
+	allDists := make([]*neighbor, 0, len(rm.nodes))
+	for _, n := range rm.nodes {
+		_, dist := planOpts.DistanceFunc(&ConstraintInput{StartInput: q, EndInput: n.q})
+		allDists = append(allDists, &neighbor{dist: dist, node: n})
+	}
+	sort.Slice(allDists, func(i, j int) bool {
+		return allDists[i].dist < allDists[j].dist
+	})
+	if k < len(allDists) {
+		allDists = allDists[:k]
+	}
+	return allDists
+}
+
+// shortestPath returns the lowest cost path through the roadmap from start to any of goals, or nil if none are connected to start.
+func (rm *roadmap) shortestPath(start *prmNode, goals map[*prmNode]bool) []node {
+	costs := map[*prmNode]float64{start: 0}
+	parents := map[*prmNode]*prmNode{}
+	queue := &prmQueue{{node: start}}
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(prmQueueItem)
+		if current.cost > costs[current.node] {
+			// a cheaper path to this node was already expanded
+			continue
+		}
+		if goals[current.node] {
+			path := []node{}
+			for n := current.node; n != nil; n = parents[n] {
+				path = append([]node{n}, path...)
+			}
+			return path
+		}
+		for next, edgeCost := range current.node.edges {
+			cost := current.cost + edgeCost
+			if prevCost, ok := costs[next]; ok && prevCost <= cost {
+				continue
+			}
+			costs[next] = cost
+			parents[next] = current.node
+			heap.Push(queue, prmQueueItem{node: next, cost: cost})
+		}
+	}
+	return nil
+}
+
+type prmQueueItem struct {
+	node *prmNode
+	cost float64
+}
+
+// prmQueue is a min-heap of roadmap nodes by the cost of reaching them.
+type prmQueue []prmQueueItem
+
+func (q prmQueue) Len() int            { return len(q) }
+func (q prmQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q prmQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *prmQueue) Push(x interface{}) { *q = append(*q, x.(prmQueueItem)) }
+
+func (q *prmQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// roadmaps holds the roadmaps built for recent planning problems, so that repeated motions in the same scene can reuse them.
+var roadmaps = newLRUCache(defaultRoadmapCacheSize)
+
+// roadmapFor returns the roadmap for the planning problems identified by key, creating it if there is none.
+func roadmapFor(key string) *roadmap {
+	return roadmaps.getOrAdd(key, func() interface{} { return newRoadmap() }).(*roadmap)
+}
+
+// roadmapKeyOptions are the planning options which change which paths are valid or what they cost, so that roadmaps cannot be shared
+// between planning problems with different values of them.
+var roadmapKeyOptions = []string{
+	"continuous_collision", "collision_buffer_mm", "workspace", "workspace_frame", "distance_metric", "joint_weights",
+	"joint_limit_margin", "joint_limit_margin_percent", "allowed_collisions",
+}
+
+// roadmapKey identifies the planning problems a roadmap is valid for: those of the same frame, among the same obstacles, with the
+// rest of the frame system where it is, with paths checked at the same resolution, and with the same values of keyOptions, such as
+// roadmapKeyOptions.
+func roadmapKey(
+	sf *solverFrame,
+	worldState *referenceframe.WorldState,
+	resolution float64,
+	planningOpts map[string]interface{},
+	keyOptions []string,
+) (string, error) {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %v %f\n", sf.Name(), sf.DoF(), resolution)
+	keyOpts := map[string]interface{}{}
+	for _, name := range keyOptions {
+		if value, ok := planningOpts[name]; ok {
+			keyOpts[name] = value
+		}
+	}
+	keyOptsJSON, err := json.Marshal(keyOpts)
+	if err != nil {
+		return "", err
+	}
+	hash.Write(keyOptsJSON)
+	otherFrames := make([]string, 0, len(sf.origSeed))
+	for name := range sf.origSeed {
+		otherFrames = append(otherFrames, name)
+	}
+	sort.Strings(otherFrames)
+	for _, name := range otherFrames {
+		fmt.Fprintf(hash, "%s %v\n", name, referenceframe.InputsToFloats(sf.origSeed[name]))
+	}
+	if worldState != nil {
+		wsPb, err := referenceframe.WorldStateToProtobuf(worldState)
+		if err != nil {
+			return "", err
+		}
+		wsBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(wsPb)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(wsBytes)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,65 @@
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+func TestPRMRoadmapReuse(t *testing.T) {
+	cfg, err := simple2DMap()
+	test.That(t, err, test.ShouldBeNil)
+	rm := newRoadmap()
+	cfg.Options.roadmap = rm
+
+	mp, err := newPRMMotionPlanner(cfg.RobotFrame, rand.New(rand.NewSource(1)), logger.Sugar(), cfg.Options)
+	test.That(t, err, test.ShouldBeNil)
+	path, err := mp.plan(context.Background(), cfg.Goal, cfg.Start)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(path), test.ShouldBeGreaterThan, 2)
+	test.That(t, len(rm.nodes), test.ShouldBeGreaterThan, 0)
+
+	// without any sampling, a nearby start can only reach the goal through the roadmap built by the first query
+	cfg.Options.extra = map[string]interface{}{"prm_max_samples": 0}
+	nearStart := frame.FloatsToInputs([]float64{-85, 85})
+	mp, err = newPRMMotionPlanner(cfg.RobotFrame, rand.New(rand.NewSource(2)), logger.Sugar(), cfg.Options)
+	test.That(t, err, test.ShouldBeNil)
+	path, err = mp.plan(context.Background(), cfg.Goal, nearStart)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path[0], test.ShouldResemble, nearStart)
+	for j := 0; j < len(path)-1; j++ {
+		ok, _ := cfg.Options.constraintHandler.CheckConstraintPath(&ConstraintInput{
+			StartInput: path[j],
+			EndInput:   path[j+1],
+			Frame:      cfg.RobotFrame,
+		}, cfg.Options.Resolution)
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+
+	cfg.Options.roadmap = nil
+	mp, err = newPRMMotionPlanner(cfg.RobotFrame, rand.New(rand.NewSource(2)), logger.Sugar(), cfg.Options)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = mp.plan(context.Background(), cfg.Goal, nearStart)
+	test.That(t, err, test.ShouldBeError, errPlannerFailed)
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache(2)
+	a := cache.getOrAdd("a", func() interface{} { return newRoadmap() })
+	b := cache.getOrAdd("b", func() interface{} { return newRoadmap() })
+	test.That(t, a, test.ShouldNotEqual, b)
+	test.That(t, cache.getOrAdd("a", func() interface{} { return newRoadmap() }), test.ShouldEqual, a)
+
+	// b is the least recently used, so it is dropped to make room
+	cache.put("c", newRoadmap())
+	value, ok := cache.get("a")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, value, test.ShouldEqual, a)
+	_, ok = cache.get("b")
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, len(cache.entries), test.ShouldEqual, 2)
+}
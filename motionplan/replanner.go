@@ -0,0 +1,153 @@
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+// Replanner repairs plans that obstacles have moved into since they were planned, such as people sharing space with an arm.
+// Rather than planning again from scratch, it keeps the parts of a plan that are still valid and only replans the part that is
+// not. The replanning is done with CBiRRT, whose goal tree is seeded with the rest of the plan, so that the repair can rejoin the
+// plan at whichever of its later steps is easiest to reach.
+type Replanner struct {
+	logger       golog.Logger
+	frame        frame.Frame
+	fs           frame.FrameSystem
+	planningOpts map[string]interface{}
+	randseed     *rand.Rand
+}
+
+// NewReplanner creates a Replanner for plans that move the given frame, as returned by PlanMotion. The planning options are those
+// the plans were made with, and are used for the repairs.
+func NewReplanner(logger golog.Logger, f frame.Frame, fs frame.FrameSystem, planningOpts map[string]interface{}) *Replanner {
+	return &Replanner{
+		logger:       logger,
+		frame:        f,
+		fs:           fs,
+		planningOpts: planningOpts,
+		//nolint: gosec
+		randseed: rand.New(rand.NewSource(1)),
+	}
+}
+
+// Replan checks the steps of a plan from executionIndex, the step the frame is currently at, against an updated world state.
+// If they are all still valid the plan is returned as it is. Otherwise every motion from the first invalid one to the last is
+// replaced with a new path around the obstacles. The steps up to and including executionIndex are never changed, so the
+// returned plan can be carried on from executionIndex. As when planning, collisions the frame is already in at executionIndex are
+// allowed.
+func (r *Replanner) Replan(
+	ctx context.Context,
+	plan []map[string][]frame.Input,
+	executionIndex int,
+	worldState *frame.WorldState,
+) ([]map[string][]frame.Input, error) {
+	if executionIndex < 0 || executionIndex >= len(plan) {
+		return nil, errors.Errorf("execution index %d is outside of a plan of %d steps", executionIndex, len(plan))
+	}
+	solveFrame := r.fs.Frame(r.frame.Name())
+	if solveFrame == nil {
+		return nil, frame.NewFrameMissingError(r.frame.Name())
+	}
+	solveFrameList, err := r.fs.TracebackFrame(solveFrame)
+	if err != nil {
+		return nil, err
+	}
+	sf, err := newSolverFrame(r.fs, solveFrameList, frame.World, plan[executionIndex])
+	if err != nil {
+		return nil, err
+	}
+	steps := make([][]frame.Input, 0, len(plan)-executionIndex)
+	for _, stepMap := range plan[executionIndex:] {
+		step, err := sf.mapToSlice(stepMap)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	startPose, err := sf.Transform(steps[0])
+	if err != nil {
+		return nil, err
+	}
+	goalPose, err := sf.Transform(steps[len(steps)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	pm, err := newPlanManager(sf, r.fs, r.logger, r.randseed.Int())
+	if err != nil {
+		return nil, err
+	}
+	pm.storageDirs = storageDirsFromContext(ctx)
+	planningOpts := deepAtomicCopyMap(r.planningOpts)
+	planningOpts["planning_alg"] = CBiRRTPlanningAlg
+	opt, err := pm.plannerSetupFromMoveRequest(startPose, goalPose, plan[executionIndex], worldState, planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	//nolint: gosec
+	mp, err := newCBiRRTMotionPlanner(sf, rand.New(rand.NewSource(int64(r.randseed.Int()))), r.logger, opt)
+	if err != nil {
+		return nil, err
+	}
+	rrtPlanner, ok := mp.(rrtParallelPlanner)
+	if !ok {
+		return nil, errors.New("replanning requires an RRT planner")
+	}
+
+	if !mp.checkInputs(steps[len(steps)-1]) {
+		return nil, errors.New("cannot replan, the goal of the plan is now in collision")
+	}
+	first, last := -1, -1
+	for i := 0; i < len(steps)-1; i++ {
+		if !mp.checkPath(steps[i], steps[i+1]) {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return plan, nil
+	}
+	r.logger.Debugf("replanning motions %d to %d of a plan of %d steps", executionIndex+first, executionIndex+last, len(plan))
+
+	// the start tree grows from the last step before the invalid motions, and the goal tree is the rest of the plan after them
+	maps := &rrtMaps{startMap: rrtMap{}, goalMap: rrtMap{}, optNode: newCostNode(steps[last+1], 0)}
+	maps.startMap[&basicNode{q: steps[first]}] = nil
+	var parent node
+	for i := len(steps) - 1; i > last; i-- {
+		n := &basicNode{q: steps[i]}
+		maps.goalMap[n] = parent
+		parent = n
+	}
+
+	plannerctx, cancel := context.WithTimeout(ctx, time.Duration(opt.Timeout*float64(time.Second)))
+	defer cancel()
+	solutionChan := make(chan *rrtPlanReturn, 1)
+	utils.PanicCapturingGo(func() {
+		rrtPlanner.rrtBackgroundRunner(plannerctx, goalPose, steps[first], &rrtParallelPlannerShared{maps, nil, solutionChan})
+	})
+	var repair *rrtPlanReturn
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case repair = <-solutionChan:
+	}
+	if repair.err() != nil {
+		return nil, repair.err()
+	}
+
+	replanned := make([]map[string][]frame.Input, 0, executionIndex+first+len(repair.steps))
+	replanned = append(replanned, plan[:executionIndex+first]...)
+	for _, step := range rrtPlanner.smoothPath(plannerctx, repair.steps) {
+		replanned = append(replanned, sf.sliceToMap(step.Q()))
+	}
+	return replanned, nil
+}
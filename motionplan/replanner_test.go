@@ -0,0 +1,71 @@
+package motionplan
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestReplan(t *testing.T) {
+	robotGeometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, robotGeometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+
+	// a straight line along the x axis
+	plan := []map[string][]frame.Input{}
+	for _, x := range []float64{-90, -60, -30, 0, 30, 60, 90} {
+		plan = append(plan, map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{x, 0})})
+	}
+	worldStateWith := func(pt r3.Vector) *frame.WorldState {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(pt), r3.Vector{X: 20, Y: 20, Z: 20}, "")
+		test.That(t, err, test.ShouldBeNil)
+		return &frame.WorldState{
+			Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": box})},
+		}
+	}
+
+	replanner := NewReplanner(logger.Sugar(), model, fs, nil)
+
+	// an obstacle away from the plan leaves it alone
+	replanned, err := replanner.Replan(context.Background(), plan, 1, worldStateWith(r3.Vector{Y: 60}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, replanned, test.ShouldResemble, plan)
+
+	// an obstacle in the way of the rest of the plan is avoided, keeping the steps before it
+	worldState := worldStateWith(r3.Vector{X: 20})
+	replanned, err = replanner.Replan(context.Background(), plan, 1, worldState)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, replanned[:3], test.ShouldResemble, plan[:3])
+	test.That(t, replanned[len(replanned)-1]["base"], test.ShouldResemble, plan[len(plan)-1]["base"])
+	collisionConstraint, err := newObstacleConstraint(model, fs, worldState, plan[0], nil, false, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	handler := &constraintHandler{}
+	handler.AddConstraint("collision", collisionConstraint)
+	for i := 0; i < len(replanned)-1; i++ {
+		ok, _ := handler.CheckConstraintPath(&ConstraintInput{
+			StartInput: replanned[i]["base"],
+			EndInput:   replanned[i+1]["base"],
+			Frame:      model,
+		}, defaultResolution)
+		test.That(t, ok, test.ShouldBeTrue)
+	}
+
+	// nothing can be done about an obstacle on the goal
+	_, err = replanner.Replan(context.Background(), plan, 1, worldStateWith(r3.Vector{X: 90}))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// as when planning, collisions the frame is already in where it is are allowed
+	replanned, err = replanner.Replan(context.Background(), plan, 3, worldStateWith(r3.Vector{}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, replanned, test.ShouldResemble, plan)
+	_, err = replanner.Replan(context.Background(), plan, len(plan), worldState)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -2,6 +2,7 @@ package motionplan
 
 import (
 	"context"
+	"math"
 
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/spatialmath"
@@ -119,6 +120,28 @@ func shortestPath(maps *rrtMaps, nodePairs []*nodePair) *rrtPlanReturn {
 	return &rrtPlanReturn{steps: extractPath(maps.startMap, maps.goalMap, nodePairs[minIdx]), maps: maps}
 }
 
+// partialPath returns the path through the start map to whichever of its nodes is closest to a goal, for use when planning is stopped
+// before the maps are connected. Only the roots of the goal map, which are the goals themselves, are considered.
+func partialPath(maps *rrtMaps, distFunc Constraint) []node {
+	var closest node
+	minDist := math.Inf(1)
+	for goal, parent := range maps.goalMap {
+		if parent != nil {
+			continue
+		}
+		for n := range maps.startMap {
+			if _, dist := distFunc(&ConstraintInput{StartInput: n.Q(), EndInput: goal.Q()}); dist < minDist {
+				minDist = dist
+				closest = n
+			}
+		}
+	}
+	if closest == nil {
+		return nil
+	}
+	return extractPath(maps.startMap, map[node]node{}, &nodePair{a: closest})
+}
+
 // node interface is used to wrap a configuration for planning purposes.
 type node interface {
 	// return the configuration associated with the node
@@ -0,0 +1,194 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// The number of RRT maps kept in memory for reuse. The least recently used ones are dropped to make room for new ones.
+	defaultRRTMapCacheSize = 32
+
+	// The ending of the names of files RRT maps are written to, which sets them apart from cached plans written to the same directory.
+	rrtMapFileSuffix = ".rrt.json"
+)
+
+// rrtMapKeyOptions are the planning options which change which RRT maps are valid, so that maps cannot be reused between planning
+// problems with different values of them.
+var rrtMapKeyOptions = append([]string{"planning_alg", "goal_tolerance", "nullspace_objectives", "preferred_posture"}, roadmapKeyOptions...)
+
+// storedMaps holds the RRT maps last grown for each frame and world state, so that planning can pick up where it left off rather
+// than growing them again from scratch. They are kept serialized, so that the planners reusing them cannot change them.
+var storedMaps = newLRUCache(defaultRRTMapCacheSize)
+
+// storedRRTMaps are the maps grown by an RRT planner between a seed and the IK solutions of a goal pose, as written to disk.
+type storedRRTMaps struct {
+	Seed            []float64                             `json:"seed"`
+	GoalPoint       r3.Vector                             `json:"goal_point"`
+	GoalOrientation *spatialmath.OrientationVectorDegrees `json:"goal_orientation"`
+	StartMap        []storedRRTNode                       `json:"start_map"`
+	GoalMap         []storedRRTNode                       `json:"goal_map"`
+	OptNode         storedRRTNode                         `json:"opt_node"`
+}
+
+type storedRRTNode struct {
+	Q    []float64 `json:"q"`
+	Cost float64   `json:"cost"`
+	// Index of the parent of the node in its map, or -1 for the roots of the map
+	Parent int `json:"parent"`
+}
+
+func newStoredRRTMaps(maps *rrtMaps, goal spatialmath.Pose, seed []referenceframe.Input) *storedRRTMaps {
+	return &storedRRTMaps{
+		Seed:            referenceframe.InputsToFloats(seed),
+		GoalPoint:       goal.Point(),
+		GoalOrientation: goal.Orientation().OrientationVectorDegrees(),
+		StartMap:        storeRRTMap(maps.startMap),
+		GoalMap:         storeRRTMap(maps.goalMap),
+		OptNode:         storedRRTNode{Q: referenceframe.InputsToFloats(maps.optNode.Q()), Cost: maps.optNode.cost, Parent: -1},
+	}
+}
+
+func storeRRTMap(m rrtMap) []storedRRTNode {
+	index := make(map[node]int, len(m))
+	stored := make([]storedRRTNode, 0, len(m))
+	for n := range m {
+		index[n] = len(stored)
+		var cost float64
+		if cn, ok := n.(*costNode); ok {
+			cost = cn.cost
+		}
+		stored = append(stored, storedRRTNode{Q: referenceframe.InputsToFloats(n.Q()), Cost: cost, Parent: -1})
+	}
+	for n, parent := range m {
+		if i, ok := index[parent]; ok && parent != nil {
+			stored[index[n]].Parent = i
+		}
+	}
+	return stored
+}
+
+func loadRRTMap(stored []storedRRTNode) rrtMap {
+	nodes := make([]*costNode, 0, len(stored))
+	for _, n := range stored {
+		nodes = append(nodes, newCostNode(referenceframe.FloatsToInputs(n.Q), n.Cost))
+	}
+	m := make(rrtMap, len(stored))
+	for i, n := range stored {
+		m[nodes[i]] = nil
+		if n.Parent >= 0 && n.Parent < len(nodes) {
+			m[nodes[i]] = nodes[n.Parent]
+		}
+	}
+	return m
+}
+
+// warmStart returns maps to start planning from seed to goal with, built from the stored maps. The start map is reused if it was
+// grown from the same seed, and the goal map if it was grown towards the same goal. Maps that cannot be reused are replaced with
+// ones holding only their roots, with the goal map left nil, as the IK solutions of the goal must be found again.
+func (s *storedRRTMaps) warmStart(goal spatialmath.Pose, seed []referenceframe.Input, distFunc Constraint) *rrtMaps {
+	maps := &rrtMaps{startMap: rrtMap{newCostNode(seed, 0): nil}}
+	if len(s.StartMap) > 0 && distance(s.Seed, referenceframe.InputsToFloats(seed)) < defaultEpsilon {
+		maps.startMap = loadRRTMap(s.StartMap)
+	}
+	storedGoal := spatialmath.NewPose(s.GoalPoint, s.GoalOrientation)
+	if len(s.GoalMap) > 0 && spatialmath.PoseAlmostEqual(storedGoal, goal) {
+		maps.goalMap = loadRRTMap(s.GoalMap)
+		optQ := referenceframe.FloatsToInputs(s.OptNode.Q)
+		// the lower bound on cost depends on where planning starts from
+		_, optCost := distFunc(&ConstraintInput{StartInput: seed, EndInput: optQ})
+		maps.optNode = newCostNode(optQ, optCost)
+	}
+	return maps
+}
+
+// storedRRTMapsFor returns the maps stored for key, looking for them in dir if they are not in memory and dir is not empty.
+func storedRRTMapsFor(key, dir string) (*storedRRTMaps, bool, error) {
+	if stored, ok := storedMaps.get(key); ok {
+		return stored.(*storedRRTMaps), true, nil
+	}
+	if dir == "" {
+		return nil, false, nil
+	}
+	//nolint:gosec
+	mapBytes, err := os.ReadFile(filepath.Join(dir, key+rrtMapFileSuffix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	stored := &storedRRTMaps{}
+	if err := json.Unmarshal(mapBytes, stored); err != nil {
+		return nil, false, err
+	}
+	storedMaps.put(key, stored)
+	return stored, true, nil
+}
+
+// storeRRTMaps stores the maps grown planning from seed to goal for key, also writing them to dir if dir is not empty.
+func storeRRTMaps(key, dir string, maps *rrtMaps, goal spatialmath.Pose, seed []referenceframe.Input) error {
+	stored := newStoredRRTMaps(maps, goal, seed)
+	storedMaps.put(key, stored)
+	if dir == "" {
+		return nil
+	}
+	mapBytes, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, key+rrtMapFileSuffix), mapBytes, 0o600)
+}
+
+// InvalidateRRTMaps drops every RRT map kept for reuse by the `reuse_rrt_maps` planning option, also removing those written to dir
+// if it is not empty. Maps are only reused among the same obstacles, so this is only needed when the world changes in ways the world
+// states passed to planning do not show, such as when the geometry of a frame changes.
+func InvalidateRRTMaps(dir string) error {
+	storedMaps.clear()
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), rrtMapFileSuffix) {
+			err = multierr.Combine(err, os.Remove(filepath.Join(dir, entry.Name())))
+		}
+	}
+	return err
+}
+
+// warmStartRRTMaps returns maps to start planning from seed to goal with, built from those stored for planners with the given
+// options, or nil if none are stored.
+func (pm *planManager) warmStartRRTMaps(opt *plannerOptions, goal spatialmath.Pose, seed []referenceframe.Input) *rrtMaps {
+	if opt.rrtMapKey == "" {
+		return nil
+	}
+	stored, ok, err := storedRRTMapsFor(opt.rrtMapKey, opt.rrtMapDir)
+	if err != nil {
+		pm.logger.Warnf("could not read stored RRT maps: %v", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	maps := stored.warmStart(goal, seed, opt.DistanceFunc)
+	pm.logger.Debugf("warm starting with stored start map of size %d and goal map of size %d", len(maps.startMap), len(maps.goalMap))
+	return maps
+}
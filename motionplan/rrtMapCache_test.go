@@ -0,0 +1,139 @@
+package motionplan
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestStoredRRTMaps(t *testing.T) {
+	seed := frame.FloatsToInputs([]float64{0, 0})
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 50})
+	root := newCostNode(seed, 0)
+	child := newCostNode(frame.FloatsToInputs([]float64{10, 0}), 10)
+	solution := newCostNode(frame.FloatsToInputs([]float64{50, 0}), 0)
+	maps := &rrtMaps{
+		startMap: rrtMap{root: nil, child: root, newCostNode(frame.FloatsToInputs([]float64{20, 0}), 20): child},
+		goalMap:  rrtMap{solution: nil, newCostNode(frame.FloatsToInputs([]float64{40, 0}), 10): solution},
+		optNode:  newCostNode(solution.Q(), 50),
+	}
+	distFunc := newBasicPlannerOptions().DistanceFunc
+
+	dir := t.TempDir()
+	test.That(t, storeRRTMaps("key", dir, maps, goal, seed), test.ShouldBeNil)
+	// once dropped from memory, the maps are read back from the directory
+	storedMaps.clear()
+	_, ok, err := storedRRTMapsFor("key", "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeFalse)
+	stored, ok, err := storedRRTMapsFor("key", dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	warm := stored.warmStart(goal, seed, distFunc)
+	test.That(t, warm.startMap, test.ShouldHaveLength, 3)
+	test.That(t, warm.goalMap, test.ShouldHaveLength, 2)
+	test.That(t, warm.optNode.Q(), test.ShouldResemble, solution.Q())
+	test.That(t, warm.optNode.cost, test.ShouldAlmostEqual, 50)
+	for n, parent := range warm.startMap {
+		// the tree keeps its shape, each node the child of the one before it
+		q := frame.InputsToFloats(n.Q())
+		if q[0] == 0 {
+			test.That(t, parent, test.ShouldBeNil)
+		} else {
+			test.That(t, frame.InputsToFloats(parent.Q())[0], test.ShouldEqual, q[0]-10)
+			test.That(t, n.(*costNode).cost, test.ShouldEqual, q[0])
+		}
+	}
+
+	// maps grown from another seed or towards another goal are not reused
+	warm = stored.warmStart(goal, frame.FloatsToInputs([]float64{5, 0}), distFunc)
+	test.That(t, warm.startMap, test.ShouldHaveLength, 1)
+	test.That(t, warm.goalMap, test.ShouldHaveLength, 2)
+	test.That(t, warm.optNode.cost, test.ShouldAlmostEqual, 45)
+	warm = stored.warmStart(spatialmath.NewPoseFromPoint(r3.Vector{X: 60}), seed, distFunc)
+	test.That(t, warm.startMap, test.ShouldHaveLength, 3)
+	test.That(t, warm.goalMap, test.ShouldBeNil)
+
+	// invalidating leaves other files in the directory alone
+	otherFile := filepath.Join(dir, "plan.json")
+	test.That(t, os.WriteFile(otherFile, []byte("[]"), 0o600), test.ShouldBeNil)
+	test.That(t, InvalidateRRTMaps(dir), test.ShouldBeNil)
+	_, ok, err = storedRRTMapsFor("key", dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeFalse)
+	_, err = os.Stat(otherFile)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, InvalidateRRTMaps(filepath.Join(dir, "missing")), test.ShouldBeNil)
+}
+
+func TestReuseRRTMaps(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 40, Y: 40, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	goal := frame.NewPoseInFrame(frame.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 60, Y: 60}))
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-60, -60})}
+	dir := t.TempDir()
+	ctx := ContextWithStorageDirs(context.Background(), StorageDirs{RRTMapDir: dir})
+	opts := map[string]interface{}{"rseed": 1, "planning_alg": "cbirrt", "reuse_rrt_maps": true}
+	test.That(t, InvalidateRRTMaps(""), test.ShouldBeNil)
+
+	_, report, err := PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	files, err := filepath.Glob(filepath.Join(dir, "*"+rrtMapFileSuffix))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldHaveLength, 1)
+
+	// the directory is only used by planning calls it is passed to
+	storedMaps.clear()
+	test.That(t, os.Remove(files[0]), test.ShouldBeNil)
+	_, _, err = PlanMotionWithReport(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	files, err = filepath.Glob(filepath.Join(dir, "*"+rrtMapFileSuffix))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldBeEmpty)
+	_, report, err = PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	files, err = filepath.Glob(filepath.Join(dir, "*"+rrtMapFileSuffix))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldHaveLength, 1)
+
+	// after a restart, planning picks up from the maps written to disk
+	storedMaps.clear()
+	_, warmReport, err := PlanMotionWithReport(ctx, logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, warmReport.Nodes, test.ShouldBeGreaterThanOrEqualTo, report.Nodes)
+
+	// maps are only reused where the paths they hold stay valid
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	for _, badOpts := range []map[string]interface{}{
+		{"reuse_rrt_maps": true, "deterministic": true},
+		{"reuse_rrt_maps": true, "motion_profile": LinearMotionProfile},
+		{"reuse_rrt_maps": true, "planning_alg": "prm"},
+		{"reuse_rrt_maps": true, "rrt_map_dir": t.TempDir()},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal.Pose(), seedMap, worldState, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
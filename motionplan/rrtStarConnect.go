@@ -21,12 +21,18 @@ const (
 	defaultOptimalityThreshold = 1.05
 
 	defaultOptimalityCheckIter = 10
+
+	// The number of random samples drawn when looking for one that could improve on the best path, before using one anyway.
+	defaultInformedSampleTries = 100
 )
 
 type rrtStarConnectOptions struct {
 	// The number of nearest neighbors to consider when adding a new sample to the tree
 	NeighborhoodSize int `json:"neighborhood_size"`
 
+	// Keep improving the path until the planner times out, rather than returning the first one close enough to optimal
+	Anytime bool `json:"anytime"`
+
 	// Parameters common to all RRT implementations
 	*rrtOptions
 }
@@ -87,6 +93,11 @@ func (mp *rrtStarConnectMotionPlanner) plan(ctx context.Context,
 	utils.PanicCapturingGo(func() {
 		mp.rrtBackgroundRunner(ctx, goal, seed, &rrtParallelPlannerShared{nil, nil, solutionChan})
 	})
+	if mp.algOpts.Anytime {
+		// the best path found is returned once ctx is done
+		plan := <-solutionChan
+		return plan.toInputs(), plan.err()
+	}
 	select {
 	case <-ctx.Done():
 		return nil, ctx.Err()
@@ -131,8 +142,10 @@ func (mp *rrtStarConnectMotionPlanner) rrtBackgroundRunner(ctx context.Context,
 	defer close(m2chan)
 
 	nSolved := 0
+	bestCost := math.Inf(1)
+	var bestGoal []referenceframe.Input
 
-	for i := 0; i < mp.algOpts.PlanIter; i++ {
+	for i := 0; mp.algOpts.Anytime || i < mp.algOpts.PlanIter; i++ {
 		select {
 		case <-ctx.Done():
 			// stop and return best path
@@ -165,7 +178,13 @@ func (mp *rrtStarConnectMotionPlanner) rrtBackgroundRunner(ctx context.Context,
 			if nSolved%defaultOptimalityCheckIter == 0 {
 				solution := shortestPath(rrt.maps, shared)
 				solutionCost := EvaluatePlan(solution.toInputs(), mp.planOpts.DistanceFunc)
-				if solutionCost-rrt.maps.optNode.cost < defaultOptimalityThreshold*rrt.maps.optNode.cost {
+				if mp.algOpts.Anytime {
+					if solutionCost < bestCost {
+						bestCost = solutionCost
+						bestGoal = solution.steps[len(solution.steps)-1].Q()
+						mp.logger.Debugf("RRT* progress: best path cost %f after %d iterations and %v", bestCost, i, time.Since(mp.start))
+					}
+				} else if solutionCost-rrt.maps.optNode.cost < defaultOptimalityThreshold*rrt.maps.optNode.cost {
 					mp.logger.Debug("RRT* progress: sufficiently optimal path found, exiting")
 					rrt.solutionChan <- solution
 					return
@@ -176,12 +195,39 @@ func (mp *rrtStarConnectMotionPlanner) rrtBackgroundRunner(ctx context.Context,
 		}
 
 		// get next sample, switch map pointers
-		target = referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+		switch {
+		case mp.sampler != nil && bestGoal != nil:
+			target = mp.drawSample(seed, bestGoal, bestCost)
+		case mp.sampler != nil:
+			target = mp.drawSample(seed, rrt.maps.optNode.Q(), math.Inf(1))
+		case mp.algOpts.Anytime:
+			target = mp.informedSample(seed, bestGoal, bestCost)
+		default:
+			target = referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+		}
 	}
 	mp.logger.Debug("RRT* exceeded max iter")
 	rrt.solutionChan <- shortestPath(rrt.maps, shared)
 }
 
+// informedSample returns a random sample that could be on a path from seed to goal cheaper than bestCost, as only such samples can
+// improve on the best path found. If none is found after a number of tries, the last one is returned anyway.
+func (mp *rrtStarConnectMotionPlanner) informedSample(seed, goal []referenceframe.Input, bestCost float64) []referenceframe.Input {
+	q := referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+	if goal == nil {
+		return q
+	}
+	for i := 1; i < defaultInformedSampleTries; i++ {
+		_, toSample := mp.planOpts.DistanceFunc(&ConstraintInput{StartInput: seed, EndInput: q})
+		_, toGoal := mp.planOpts.DistanceFunc(&ConstraintInput{StartInput: q, EndInput: goal})
+		if toSample+toGoal < bestCost {
+			break
+		}
+		q = referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+	}
+	return q
+}
+
 func (mp *rrtStarConnectMotionPlanner) extend(
 	tree rrtMap,
 	target []referenceframe.Input,
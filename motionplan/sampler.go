@@ -0,0 +1,290 @@
+package motionplan
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// default standard deviation of the gaussian and bridge samplers, as a fraction of the range of each input.
+	defaultSamplerStdDev = 0.05
+
+	// number of pairs of configurations the bridge sampler tries before drawing a uniform sample instead.
+	bridgeSampleTries = 20
+)
+
+// Sampler draws the configurations which RRT planners grow their trees towards, and which PRM adds to its roadmap. Planners sample
+// uniformly across the limits of the frame unless the `sampler` planning option names a registered Sampler.
+type Sampler interface {
+	// Sample draws a configuration for a motion, taking any randomness from randseed so that deterministic plans stay deterministic.
+	Sample(randseed *rand.Rand, req *SampleRequest) []referenceframe.Input
+}
+
+// SampleRequest is what is known of the motion a Sampler draws a configuration for.
+type SampleRequest struct {
+	// Inputs at the start of the motion
+	Start []referenceframe.Input
+	// Best solution found for the goal of the motion
+	Goal []referenceframe.Input
+	// Length of the shortest path found so far from Start to Goal, as measured by the distance function of the plan, or +Inf if none
+	// has been found yet
+	BestCost float64
+	// Checks whether a configuration meets the constraints of the motion, such as keeping clear of obstacles
+	Valid func([]referenceframe.Input) bool
+}
+
+// SamplerConstructor builds a Sampler for a frame, configured by the planning options of the motion.
+type SamplerConstructor func(frame referenceframe.Frame, attrs config.AttributeMap) (Sampler, error)
+
+var (
+	samplersMu sync.Mutex
+	samplers   = map[string]SamplerConstructor{
+		"uniform":  newUniformSampler,
+		"gaussian": newGaussianSampler,
+		"bridge":   newBridgeSampler,
+		"informed": newInformedSampler,
+	}
+)
+
+// RegisterSampler registers a sampler constructor under a name, so that planners sample with it when `sampler` is set to that name
+// in the planning options. It panics if a sampler is already registered with the same name.
+//
+// The built in samplers are
+//   - uniform, drawing from anywhere within the limits of the frame, as planners do by default.
+//   - gaussian, drawing near the straight line from the start to the goal, so that trees grow around the direct path first. The
+//     `sampler_std_dev` option sets how far from it samples are spread, as a fraction of the range of each input.
+//   - bridge, drawing from narrow passages between obstacles, which uniform samples rarely fall in, by the bridge test: of two
+//     invalid configurations `sampler_std_dev` apart, the midpoint is sampled if it is valid.
+//   - informed, drawing only from the ellipsoid of configurations which could lie on a path shorter than the best found so far, as
+//     measured in the two-norm of the inputs. It samples uniformly until a path is found, so only helps anytime planning.
+func RegisterSampler(name string, constructor SamplerConstructor) {
+	samplersMu.Lock()
+	defer samplersMu.Unlock()
+	if _, ok := samplers[name]; ok {
+		panic(errors.Errorf("trying to register two samplers with the same name %q", name))
+	}
+	if constructor == nil {
+		panic(errors.Errorf("cannot register a nil sampler constructor for %q", name))
+	}
+	samplers[name] = constructor
+}
+
+// samplerFor returns the sampler constructor registered under name.
+func samplerFor(name string) (SamplerConstructor, error) {
+	samplersMu.Lock()
+	defer samplersMu.Unlock()
+	constructor, ok := samplers[name]
+	if !ok {
+		return nil, errors.Errorf("no sampler registered with the name %q", name)
+	}
+	return constructor, nil
+}
+
+// frameRanges returns the lower limit and range of each input of a frame, treating infinite limits as RandomFrameInputs does.
+func frameRanges(frame referenceframe.Frame) ([]float64, []float64) {
+	dof := frame.DoF()
+	mins := make([]float64, 0, len(dof))
+	spans := make([]float64, 0, len(dof))
+	for _, limit := range dof {
+		l, u := limit.Min, limit.Max
+		if math.IsInf(l, -1) {
+			l = -999
+		}
+		if math.IsInf(u, 1) {
+			u = 999
+		}
+		mins = append(mins, l)
+		spans = append(spans, u-l)
+	}
+	return mins, spans
+}
+
+// samplerStdDevs reads the `sampler_std_dev` option, returning the standard deviation to sample each input of a frame with.
+func samplerStdDevs(frame referenceframe.Frame, attrs config.AttributeMap) ([]float64, error) {
+	getter := attrs.Getter()
+	fraction := getter.GetFloat64("sampler_std_dev", defaultSamplerStdDev)
+	if err := getter.Err(); err != nil {
+		return nil, err
+	}
+	if fraction <= 0 {
+		return nil, errors.New("sampler_std_dev must be positive")
+	}
+	_, spans := frameRanges(frame)
+	stdDevs := make([]float64, 0, len(spans))
+	for _, span := range spans {
+		stdDevs = append(stdDevs, fraction*span)
+	}
+	return stdDevs, nil
+}
+
+// withinLimits returns whether every value of q is within the limits of a frame with the given ranges.
+func withinLimits(q, mins, spans []float64) bool {
+	for j, v := range q {
+		if v < mins[j] || v > mins[j]+spans[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// clampToLimits moves every value of q to the nearest point within the limits of a frame with the given ranges.
+func clampToLimits(q, mins, spans []float64) []referenceframe.Input {
+	inputs := make([]referenceframe.Input, 0, len(q))
+	for j, v := range q {
+		inputs = append(inputs, referenceframe.Input{Value: math.Max(mins[j], math.Min(mins[j]+spans[j], v))})
+	}
+	return inputs
+}
+
+type uniformSampler struct {
+	frame referenceframe.Frame
+}
+
+func newUniformSampler(frame referenceframe.Frame, _ config.AttributeMap) (Sampler, error) {
+	return &uniformSampler{frame: frame}, nil
+}
+
+func (s *uniformSampler) Sample(randseed *rand.Rand, _ *SampleRequest) []referenceframe.Input {
+	return referenceframe.RandomFrameInputs(s.frame, randseed)
+}
+
+// gaussianSampler draws configurations normally distributed about a random point on the straight line from the start to the goal.
+type gaussianSampler struct {
+	mins, spans, stdDevs []float64
+}
+
+func newGaussianSampler(frame referenceframe.Frame, attrs config.AttributeMap) (Sampler, error) {
+	stdDevs, err := samplerStdDevs(frame, attrs)
+	if err != nil {
+		return nil, err
+	}
+	mins, spans := frameRanges(frame)
+	return &gaussianSampler{mins: mins, spans: spans, stdDevs: stdDevs}, nil
+}
+
+func (s *gaussianSampler) Sample(randseed *rand.Rand, req *SampleRequest) []referenceframe.Input {
+	center := referenceframe.InputsToFloats(referenceframe.InterpolateInputs(req.Start, req.Goal, randseed.Float64()))
+	for j := range center {
+		center[j] += randseed.NormFloat64() * s.stdDevs[j]
+	}
+	return clampToLimits(center, s.mins, s.spans)
+}
+
+// bridgeSampler draws configurations halfway between pairs of invalid configurations a normally distributed distance apart. Such a
+// bridge spans a narrow passage if its midpoint is valid, so samples fall in narrow passages far more often than uniform samples do.
+// See Hsu et al. 2003, https://ieeexplore.ieee.org/document/1241860
+type bridgeSampler struct {
+	frame                referenceframe.Frame
+	mins, spans, stdDevs []float64
+}
+
+func newBridgeSampler(frame referenceframe.Frame, attrs config.AttributeMap) (Sampler, error) {
+	stdDevs, err := samplerStdDevs(frame, attrs)
+	if err != nil {
+		return nil, err
+	}
+	mins, spans := frameRanges(frame)
+	return &bridgeSampler{frame: frame, mins: mins, spans: spans, stdDevs: stdDevs}, nil
+}
+
+func (s *bridgeSampler) Sample(randseed *rand.Rand, req *SampleRequest) []referenceframe.Input {
+	for i := 0; i < bridgeSampleTries; i++ {
+		first := referenceframe.RandomFrameInputs(s.frame, randseed)
+		if req.Valid(first) {
+			continue
+		}
+		second := referenceframe.InputsToFloats(first)
+		for j := range second {
+			second[j] += randseed.NormFloat64() * s.stdDevs[j]
+		}
+		if !withinLimits(second, s.mins, s.spans) || req.Valid(referenceframe.FloatsToInputs(second)) {
+			continue
+		}
+		if mid := referenceframe.InterpolateInputs(first, referenceframe.FloatsToInputs(second), 0.5); req.Valid(mid) {
+			return mid
+		}
+	}
+	return referenceframe.RandomFrameInputs(s.frame, randseed)
+}
+
+// informedSampler draws configurations uniformly from within the ellipsoid of those whose distances from the start and to the goal sum
+// to less than the best path found, as only they could lie on a shorter path. See Gammell et al. 2014,
+// https://ieeexplore.ieee.org/document/6942976
+type informedSampler struct {
+	frame       referenceframe.Frame
+	mins, spans []float64
+}
+
+func newInformedSampler(frame referenceframe.Frame, _ config.AttributeMap) (Sampler, error) {
+	mins, spans := frameRanges(frame)
+	return &informedSampler{frame: frame, mins: mins, spans: spans}, nil
+}
+
+func (s *informedSampler) Sample(randseed *rand.Rand, req *SampleRequest) []referenceframe.Input {
+	start := referenceframe.InputsToFloats(req.Start)
+	goal := referenceframe.InputsToFloats(req.Goal)
+	minCost := distance(start, goal)
+	if math.IsInf(req.BestCost, 1) || minCost == 0 || req.BestCost <= minCost {
+		return referenceframe.RandomFrameInputs(s.frame, randseed)
+	}
+	n := len(start)
+	// the ellipsoid has the start and goal as its foci, a major radius of half the best cost, and equal minor radii
+	majorRadius := req.BestCost / 2
+	minorRadius := math.Sqrt(req.BestCost*req.BestCost-minCost*minCost) / 2
+	// the first axis is reflected onto the line from the start to the goal, by a Householder reflection about v
+	v := make([]float64, n)
+	vNormSq := 0.
+	for j := range v {
+		v[j] = -(goal[j] - start[j]) / minCost
+		if j == 0 {
+			v[j]++
+		}
+		vNormSq += v[j] * v[j]
+	}
+
+	var q []float64
+	for i := 0; i < defaultInformedSampleTries; i++ {
+		// a uniform sample of the unit ball, scaled by the radii of the ellipsoid
+		ball := make([]float64, n)
+		norm := 0.
+		for j := range ball {
+			ball[j] = randseed.NormFloat64()
+			norm += ball[j] * ball[j]
+		}
+		scale := math.Pow(randseed.Float64(), 1/float64(n)) / math.Sqrt(norm)
+		for j := range ball {
+			ball[j] *= scale * minorRadius
+		}
+		ball[0] *= majorRadius / minorRadius
+
+		dot := 0.
+		for j := range ball {
+			dot += v[j] * ball[j]
+		}
+		q = make([]float64, n)
+		for j := range q {
+			q[j] = (start[j]+goal[j])/2 + ball[j]
+			if vNormSq > 0 {
+				q[j] -= 2 * dot / vNormSq * v[j]
+			}
+		}
+		if withinLimits(q, s.mins, s.spans) {
+			break
+		}
+	}
+	return clampToLimits(q, s.mins, s.spans)
+}
+
+// drawSample draws a configuration for a motion from start to goal with the sampler of the planner, or uniformly if it has none.
+func (mp *planner) drawSample(start, goal []referenceframe.Input, bestCost float64) []referenceframe.Input {
+	if mp.sampler == nil {
+		return referenceframe.RandomFrameInputs(mp.frame, mp.randseed)
+	}
+	return mp.sampler.Sample(mp.randseed, &SampleRequest{Start: start, Goal: goal, BestCost: bestCost, Valid: mp.checkInputs})
+}
@@ -0,0 +1,137 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	frame "go.viam.com/rdk/referenceframe"
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestSamplers(t *testing.T) {
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	//nolint: gosec
+	randseed := rand.New(rand.NewSource(1))
+	// a wall across most of the space, with a narrow gap through it
+	inGap := func(q []float64) bool { return math.Abs(q[0]) < 3 && math.Abs(q[1]) < 80 }
+	req := &SampleRequest{
+		Start:    frame.FloatsToInputs([]float64{-50, 0}),
+		Goal:     frame.FloatsToInputs([]float64{50, 0}),
+		BestCost: math.Inf(1),
+		Valid: func(inputs []frame.Input) bool {
+			q := frame.InputsToFloats(inputs)
+			return math.Abs(q[1]) >= 80 || inGap(q)
+		},
+	}
+	samples := func(name string, attrs config.AttributeMap) [][]float64 {
+		constructor, err := samplerFor(name)
+		test.That(t, err, test.ShouldBeNil)
+		sampler, err := constructor(model, attrs)
+		test.That(t, err, test.ShouldBeNil)
+		qs := make([][]float64, 0, 200)
+		for i := 0; i < 200; i++ {
+			q := frame.InputsToFloats(sampler.Sample(randseed, req))
+			test.That(t, q[0], test.ShouldBeBetweenOrEqual, -100, 100)
+			test.That(t, q[1], test.ShouldBeBetweenOrEqual, -100, 100)
+			qs = append(qs, q)
+		}
+		return qs
+	}
+	countInGap := func(qs [][]float64) int {
+		count := 0
+		for _, q := range qs {
+			if inGap(q) {
+				count++
+			}
+		}
+		return count
+	}
+
+	uniformInGap := countInGap(samples("uniform", nil))
+
+	// gaussian samples stay near the line from the start to the goal
+	for _, q := range samples("gaussian", config.AttributeMap{"sampler_std_dev": 0.01}) {
+		test.That(t, q[0], test.ShouldBeBetween, -60, 60)
+		test.That(t, q[1], test.ShouldBeBetween, -10, 10)
+	}
+
+	// bridge samples fall in the gap far more often than uniform samples do
+	test.That(t, countInGap(samples("bridge", nil)), test.ShouldBeGreaterThan, 5*uniformInGap)
+
+	// informed samples could all be on a path shorter than the best found
+	req.BestCost = 120
+	for _, q := range samples("informed", nil) {
+		test.That(t, distance(q, []float64{-50, 0})+distance(q, []float64{50, 0}), test.ShouldBeLessThanOrEqualTo, 120+1e-6)
+	}
+
+	test.That(t, func() { RegisterSampler("bridge", newBridgeSampler) }, test.ShouldPanic)
+	test.That(t, func() { RegisterSampler("test_nil", nil) }, test.ShouldPanic)
+	_, err = samplerFor("test_missing")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = newGaussianSampler(model, config.AttributeMap{"sampler_std_dev": -1})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPlanWithSampler(t *testing.T) {
+	geometry, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	wall, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 10, Y: 100, Z: 10}, "wall")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{Obstacles: []*frame.GeometriesInFrame{
+		frame.NewGeometriesInFrame(frame.World, map[string]spatial.Geometry{"wall": wall}),
+	}}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-50, 0})}
+	goal := frame.NewPoseInFrame(frame.World, spatial.NewPoseFromPoint(r3.Vector{X: 50}))
+
+	// planners draw the samples they plan through from the sampler named in the options
+	sampled := 0
+	RegisterSampler("test_counting", func(f frame.Frame, attrs config.AttributeMap) (Sampler, error) {
+		uniform, err := newUniformSampler(f, attrs)
+		if err != nil {
+			return nil, err
+		}
+		return samplerFunc(func(randseed *rand.Rand, req *SampleRequest) []frame.Input {
+			sampled++
+			return uniform.Sample(randseed, req)
+		}), nil
+	})
+	opts := map[string]interface{}{"planning_alg": "rrtstar", "sampler": "test_counting", "rseed": 1, "num_threads": 1}
+	plan, err := PlanMotion(context.Background(), logger.Sugar(), goal, model, seedMap, fs, worldState, opts)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(plan), test.ShouldBeGreaterThan, 2)
+	test.That(t, sampled, test.ShouldBeGreaterThan, 0)
+
+	frameList, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, frameList, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	for _, badOpts := range []map[string]interface{}{
+		{"sampler": "test_missing"},
+		{"sampler": "gaussian", "sampler_std_dev": 0},
+		{"sampler": "gaussian", "planning_alg": "hybridastar"},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatial.NewZeroPose(), goal.Pose(), seedMap, worldState, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+type samplerFunc func(randseed *rand.Rand, req *SampleRequest) []frame.Input
+
+func (f samplerFunc) Sample(randseed *rand.Rand, req *SampleRequest) []frame.Input {
+	return f(randseed, req)
+}
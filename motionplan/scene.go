@@ -0,0 +1,96 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// scene is a plan together with the world it is planned in, as written by ExportScene.
+type scene struct {
+	// Obstacles of the world state, in the world frame
+	Obstacles []spatialmath.Geometry `json:"obstacles"`
+	Waypoints []sceneWaypoint        `json:"waypoints"`
+	// Poses in the world frame of each frame moved by the plan at each waypoint, by frame name
+	Paths map[string][]scenePose `json:"paths"`
+}
+
+type sceneWaypoint struct {
+	Inputs map[string][]float64 `json:"inputs"`
+	// Geometries of each frame at this waypoint, in the world frame, by frame name
+	Geometries map[string][]spatialmath.Geometry `json:"geometries"`
+}
+
+type scenePose struct {
+	Point       r3.Vector                             `json:"point"`
+	Orientation *spatialmath.OrientationVectorDegrees `json:"orientation"`
+}
+
+// ExportScene returns a plan and the world it was planned in as JSON, so that the plan can be inspected in external tools before it
+// is run. The scene has the obstacles of the world state, the geometries of every frame at each waypoint, and the path through the
+// world of each frame the plan moves, such as the end effector of an arm. Geometries are written as geometry configs in the world
+// frame, and orientations as orientation vectors in degrees.
+func ExportScene(
+	plan []map[string][]referenceframe.Input,
+	fs referenceframe.FrameSystem,
+	worldState *referenceframe.WorldState,
+) ([]byte, error) {
+	if len(plan) == 0 {
+		return nil, errors.New("cannot export a scene for an empty plan")
+	}
+	// obstacles may be placed relative to frames which move, so are placed where they are at the start of the plan
+	worldState, err := worldState.ToWorldFrame(fs, plan[0])
+	if err != nil {
+		return nil, err
+	}
+	// can use zeroth element of worldState.Obstacles because ToWorldFrame returns only one GeometriesInFrame
+	s := &scene{
+		Obstacles: sortedGeometries(worldState.Obstacles[0].Geometries()),
+		Paths:     map[string][]scenePose{},
+	}
+
+	for _, step := range plan {
+		waypoint := sceneWaypoint{Inputs: map[string][]float64{}, Geometries: map[string][]spatialmath.Geometry{}}
+		for name, inputs := range step {
+			if len(inputs) == 0 {
+				continue
+			}
+			waypoint.Inputs[name] = referenceframe.InputsToFloats(inputs)
+			tf, err := fs.Transform(step, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), referenceframe.World)
+			if err != nil {
+				return nil, err
+			}
+			pose := tf.(*referenceframe.PoseInFrame).Pose()
+			s.Paths[name] = append(s.Paths[name], scenePose{pose.Point(), pose.Orientation().OrientationVectorDegrees()})
+		}
+		frameGeometries, err := referenceframe.FrameSystemGeometries(fs, step, golog.Global())
+		if err != nil {
+			return nil, err
+		}
+		for name, geometries := range frameGeometries {
+			waypoint.Geometries[name] = sortedGeometries(geometries.Geometries())
+		}
+		s.Waypoints = append(s.Waypoints, waypoint)
+	}
+	return json.Marshal(s)
+}
+
+// sortedGeometries returns geometries ordered by name, so that the same scene is always written the same way.
+func sortedGeometries(geometries map[string]spatialmath.Geometry) []spatialmath.Geometry {
+	names := make([]string, 0, len(geometries))
+	for name := range geometries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sorted := make([]spatialmath.Geometry, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, geometries[name])
+	}
+	return sorted
+}
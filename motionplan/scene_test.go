@@ -0,0 +1,66 @@
+package motionplan
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestExportScene(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 4, Y: 4, Z: 4}, "body")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 10}), r3.Vector{X: 2, Y: 2, Z: 2}, "b")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	plan := []map[string][]frame.Input{
+		{"base": frame.FloatsToInputs([]float64{0, 0}), frame.World: {}},
+		{"base": frame.FloatsToInputs([]float64{0, 20}), frame.World: {}},
+		{"base": frame.FloatsToInputs([]float64{20, 20}), frame.World: {}},
+	}
+
+	_, err = ExportScene(nil, fs, worldState)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	sceneJSON, err := ExportScene(plan, fs, worldState)
+	test.That(t, err, test.ShouldBeNil)
+	var exported struct {
+		Obstacles []spatialmath.GeometryConfig `json:"obstacles"`
+		Waypoints []struct {
+			Inputs     map[string][]float64                    `json:"inputs"`
+			Geometries map[string][]spatialmath.GeometryConfig `json:"geometries"`
+		} `json:"waypoints"`
+		Paths map[string][]struct {
+			Point r3.Vector `json:"point"`
+		} `json:"paths"`
+	}
+	test.That(t, json.Unmarshal(sceneJSON, &exported), test.ShouldBeNil)
+
+	test.That(t, exported.Obstacles, test.ShouldHaveLength, 1)
+	test.That(t, exported.Obstacles[0].Type, test.ShouldEqual, spatialmath.BoxType)
+	test.That(t, exported.Obstacles[0].TranslationOffset, test.ShouldResemble, r3.Vector{X: 10})
+	test.That(t, exported.Waypoints, test.ShouldHaveLength, len(plan))
+	test.That(t, exported.Paths, test.ShouldHaveLength, 1)
+	test.That(t, exported.Paths["base"], test.ShouldHaveLength, len(plan))
+	for i, step := range plan {
+		q := frame.InputsToFloats(step["base"])
+		waypoint := exported.Waypoints[i]
+		test.That(t, waypoint.Inputs["base"], test.ShouldResemble, q)
+		test.That(t, waypoint.Geometries["base"], test.ShouldHaveLength, 1)
+		test.That(t, waypoint.Geometries["base"][0].TranslationOffset.X, test.ShouldAlmostEqual, q[0])
+		test.That(t, waypoint.Geometries["base"][0].TranslationOffset.Y, test.ShouldAlmostEqual, q[1])
+		test.That(t, exported.Paths["base"][i].Point.X, test.ShouldAlmostEqual, q[0])
+		test.That(t, exported.Paths["base"][i].Point.Y, test.ShouldAlmostEqual, q[1])
+	}
+}
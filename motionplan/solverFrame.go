@@ -2,6 +2,8 @@ package motionplan
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	"go.uber.org/multierr"
 	pb "go.viam.com/api/component/arm/v1"
@@ -24,6 +26,8 @@ type solverFrame struct {
 	// TODO(pl): explore allowing this to be frames other than world
 	worldRooted bool
 	origSeed    map[string][]frame.Input // stores starting locations of all frames in fss that are NOT in `frames`
+	parts       []*solverFrame           // the solver frames of each kinematic chain, if this combines several of them
+	limits      []frame.Limit            // limits narrower than those of the frames the inputs are planned within, if any
 }
 
 func newSolverFrame(
@@ -153,16 +157,81 @@ func newSolverFrame(
 	}, nil
 }
 
+// newMultiSolverFrame combines the solver frames of several kinematic chains, such as the two arms of a dual-arm handover, into one
+// solverFrame whose inputs are those of every chain, so that they can be planned for in a single configuration space. As the geometries
+// of every chain move with it, its self collision checks also keep the chains from colliding with each other.
+// The chains may not share frames with degrees of freedom, as they could then not be solved for independently of each other.
+func newMultiSolverFrame(
+	fss frame.FrameSystem,
+	parts []*solverFrame,
+	seedMap map[string][]frame.Input,
+) (*solverFrame, error) {
+	if len(parts) == 0 {
+		return nil, errors.New("no solver frames to combine")
+	}
+	var frames []frame.Frame
+	var movingGeom []string
+	names := make([]string, 0, len(parts))
+	seenFrames := map[string]bool{}
+	seenGeom := map[string]bool{}
+	for _, part := range parts {
+		names = append(names, part.name)
+		for _, f := range part.frames {
+			if seenFrames[f.Name()] {
+				if len(f.DoF()) != 0 {
+					return nil, fmt.Errorf("cannot plan for frames that share the moving frame %q", f.Name())
+				}
+				continue
+			}
+			seenFrames[f.Name()] = true
+			frames = append(frames, f)
+		}
+		for _, name := range part.movingGeom {
+			if !seenGeom[name] {
+				seenGeom[name] = true
+				movingGeom = append(movingGeom, name)
+			}
+		}
+	}
+
+	origSeed := map[string][]frame.Input{}
+	for k, v := range seedMap {
+		origSeed[k] = v
+	}
+	for _, frame := range frames {
+		delete(origSeed, frame.Name())
+	}
+
+	return &solverFrame{
+		name:       strings.Join(names, "+"),
+		fss:        fss,
+		movingGeom: movingGeom,
+		frames:     frames,
+		solveFrame: parts[0].solveFrame,
+		goalFrame:  parts[0].goalFrame,
+		origSeed:   origSeed,
+		parts:      parts,
+	}, nil
+}
+
 // Name returns the name of the solver referenceframe.
 func (sf *solverFrame) Name() string {
 	return sf.name
 }
 
 // Transform returns the pose between the two frames of this solver for a given set of inputs.
+// For a solver frame combining several chains this is the pose of the first of them.
 func (sf *solverFrame) Transform(inputs []frame.Input) (spatial.Pose, error) {
 	if len(inputs) != len(sf.DoF()) {
 		return nil, frame.NewIncorrectInputLengthError(len(inputs), len(sf.DoF()))
 	}
+	if len(sf.parts) > 0 {
+		partInputs, err := sf.parts[0].mapToSlice(sf.sliceToMap(inputs))
+		if err != nil {
+			return nil, err
+		}
+		return sf.parts[0].Transform(partInputs)
+	}
 	pf := frame.NewPoseInFrame(sf.solveFrame.Name(), spatial.NewZeroPose())
 	solveName := sf.goalFrame.Name()
 	if sf.worldRooted {
@@ -237,8 +306,18 @@ func (sf *solverFrame) Geometries(inputs []frame.Input) (*frame.GeometriesInFram
 	return frame.NewGeometriesInFrame(frame.World, sfGeometries), errAll
 }
 
-// DoF returns the summed DoF of all frames between the two solver frames.
+// withLimits returns a copy of the solver frame whose inputs are planned within the given limits rather than those of its frames.
+func (sf *solverFrame) withLimits(limits []frame.Limit) *solverFrame {
+	limited := *sf
+	limited.limits = limits
+	return &limited
+}
+
+// DoF returns the summed DoF of all frames between the two solver frames, or the limits they are planned within if narrower.
 func (sf *solverFrame) DoF() []frame.Limit {
+	if sf.limits != nil {
+		return append([]frame.Limit{}, sf.limits...)
+	}
 	var limits []frame.Limit
 	for _, frame := range sf.frames {
 		limits = append(limits, frame.DoF()...)
@@ -246,6 +325,29 @@ func (sf *solverFrame) DoF() []frame.Limit {
 	return limits
 }
 
+// drivesWithHeading returns whether the only inputs planned for are the position and heading of a mobile 2D frame, as those of a
+// base that can only drive in the direction it is facing are.
+func (sf *solverFrame) drivesWithHeading() bool {
+	if len(sf.DoF()) != 3 {
+		return false
+	}
+	for _, f := range sf.frames {
+		if frame.IsMobile2DFrameWithHeading(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// DynamicLimits returns how fast each degree of freedom of all frames between the two solver frames can move, in the same order as DoF.
+func (sf *solverFrame) DynamicLimits() []frame.DynamicLimit {
+	var dynamics []frame.DynamicLimit
+	for _, f := range sf.frames {
+		dynamics = append(dynamics, frame.DynamicLimitsOf(f)...)
+	}
+	return dynamics
+}
+
 // mapToSlice will flatten a map of inputs into a slice suitable for input to inverse kinematics, by concatenating
 // the inputs together in the order of the frames in sf.frames.
 func (sf *solverFrame) mapToSlice(inputMap map[string][]frame.Input) ([]frame.Input, error) {
@@ -0,0 +1,155 @@
+package motionplan
+
+import (
+	"context"
+	"math"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// default number of steps a path is resampled into when fitting a spline through it.
+	defaultSplineSteps = 20
+
+	// number of times the bend of a spline between two waypoints is halved to meet constraints, before the straight line between the
+	// waypoints is used instead.
+	splineBlendTries = 5
+)
+
+// splineSmoothPath fits a natural cubic spline through the waypoints of a path, parameterized by the distance along the path in input
+// space, and resamples it into roughly SplineSteps steps. Unlike shortcutting, which leaves a path piecewise linear, the spline has
+// continuous velocity and acceleration through every waypoint, so the path need not stop at its corners.
+//
+// The spline still passes through every waypoint, so where its bend between two waypoints breaks a constraint or leaves the limits of
+// the frame, it is blended back towards the straight line between them until it does not. The straight line is part of the path given,
+// so the smoothed path is always at least as valid as the one given, if not as smooth at those waypoints.
+func splineSmoothPath(ctx context.Context, mp motionPlanner, frame referenceframe.Frame, path []node) []node {
+	opt := mp.opt()
+	if opt.SmoothMethod != SplineSmoothMethod {
+		return path
+	}
+	knots := [][]float64{referenceframe.InputsToFloats(path[0].Q())}
+	for _, step := range path[1:] {
+		q := referenceframe.InputsToFloats(step.Q())
+		if distance(knots[len(knots)-1], q) > 0 {
+			knots = append(knots, q)
+		}
+	}
+	if len(knots) < 3 {
+		return path
+	}
+	ts := make([]float64, len(knots))
+	for i := 1; i < len(knots); i++ {
+		ts[i] = ts[i-1] + distance(knots[i-1], knots[i])
+	}
+	spline := newCubicSpline(ts, knots)
+	limits := frame.DoF()
+
+	qs := [][]float64{knots[0]}
+	for k := 1; k < len(knots); k++ {
+		select {
+		case <-ctx.Done():
+			return path
+		default:
+		}
+		pieces := int(math.Ceil((ts[k] - ts[k-1]) / ts[len(ts)-1] * float64(opt.SplineSteps)))
+		blend := 1.
+		var segment [][]float64
+		for try := 0; try <= splineBlendTries; try++ {
+			if try == splineBlendTries {
+				blend = 0
+			}
+			segment = make([][]float64, 0, pieces)
+			for p := 1; p <= pieces; p++ {
+				frac := float64(p) / float64(pieces)
+				curved := spline.at(ts[k-1] + frac*(ts[k]-ts[k-1]))
+				q := make([]float64, len(curved))
+				for j := range q {
+					straight := knots[k-1][j] + frac*(knots[k][j]-knots[k-1][j])
+					q[j] = straight + blend*(curved[j]-straight)
+					q[j] = math.Max(limits[j].Min, math.Min(limits[j].Max, q[j]))
+				}
+				segment = append(segment, q)
+			}
+			// the last sample is the waypoint itself, so the segment ends exactly on it whatever the blend
+			segment[len(segment)-1] = knots[k]
+			if blend == 0 || splineSegmentValid(mp, qs[len(qs)-1], segment) {
+				break
+			}
+			blend /= 2
+		}
+		qs = append(qs, segment...)
+	}
+	return floatsToNodes(qs)
+}
+
+// splineSegmentValid returns whether the path from a waypoint through each of the samples of a segment meets every constraint.
+func splineSegmentValid(mp motionPlanner, from []float64, segment [][]float64) bool {
+	for _, q := range segment {
+		if !mp.checkPath(referenceframe.FloatsToInputs(from), referenceframe.FloatsToInputs(q)) {
+			return false
+		}
+		from = q
+	}
+	return true
+}
+
+// cubicSpline is a natural cubic spline through a set of knots, one per input, with zero second derivative at either end.
+type cubicSpline struct {
+	ts    []float64
+	knots [][]float64
+	// second derivative of each input at each knot
+	accels [][]float64
+}
+
+func newCubicSpline(ts []float64, knots [][]float64) *cubicSpline {
+	n := len(ts)
+	dof := len(knots[0])
+	accels := make([][]float64, n)
+	for i := range accels {
+		accels[i] = make([]float64, dof)
+	}
+	// the second derivatives at the inner knots solve a tridiagonal system, eliminated forward then substituted back (Thomas algorithm)
+	diag := make([]float64, n)
+	rhs := make([][]float64, n)
+	for i := 1; i < n-1; i++ {
+		hPrev := ts[i] - ts[i-1]
+		hNext := ts[i+1] - ts[i]
+		diag[i] = 2 * (hPrev + hNext)
+		rhs[i] = make([]float64, dof)
+		for j := 0; j < dof; j++ {
+			rhs[i][j] = 6 * ((knots[i+1][j]-knots[i][j])/hNext - (knots[i][j]-knots[i-1][j])/hPrev)
+		}
+		if i > 1 {
+			factor := hPrev / diag[i-1]
+			diag[i] -= factor * hPrev
+			for j := 0; j < dof; j++ {
+				rhs[i][j] -= factor * rhs[i-1][j]
+			}
+		}
+	}
+	for i := n - 2; i >= 1; i-- {
+		hNext := ts[i+1] - ts[i]
+		for j := 0; j < dof; j++ {
+			accels[i][j] = (rhs[i][j] - hNext*accels[i+1][j]) / diag[i]
+		}
+	}
+	return &cubicSpline{ts: ts, knots: knots, accels: accels}
+}
+
+// at returns the value of the spline at t, which must be within the knots.
+func (s *cubicSpline) at(t float64) []float64 {
+	k := 1
+	for k < len(s.ts)-1 && t > s.ts[k] {
+		k++
+	}
+	h := s.ts[k] - s.ts[k-1]
+	a := (s.ts[k] - t) / h
+	b := (t - s.ts[k-1]) / h
+	q := make([]float64, len(s.knots[k]))
+	for j := range q {
+		q[j] = a*s.knots[k-1][j] + b*s.knots[k][j] +
+			((a*a*a-a)*s.accels[k-1][j]+(b*b*b-b)*s.accels[k][j])*h*h/6
+	}
+	return q
+}
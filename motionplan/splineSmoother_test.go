@@ -0,0 +1,117 @@
+package motionplan
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestCubicSpline(t *testing.T) {
+	ts := []float64{0, 1, 3, 4}
+	knots := [][]float64{{0, 0}, {1, 2}, {3, -1}, {4, 0}}
+	spline := newCubicSpline(ts, knots)
+	for i, knot := range knots {
+		q := spline.at(ts[i])
+		test.That(t, q[0], test.ShouldAlmostEqual, knot[0])
+		test.That(t, q[1], test.ShouldAlmostEqual, knot[1])
+	}
+	// velocity is continuous through the inner knots
+	const h = 1e-6
+	for _, tk := range ts[1:3] {
+		before := spline.at(tk - h)
+		after := spline.at(tk + h)
+		at := spline.at(tk)
+		for j := range at {
+			test.That(t, (at[j]-before[j])/h, test.ShouldAlmostEqual, (after[j]-at[j])/h, 1e-4)
+		}
+	}
+}
+
+func TestSplineSmoothPath(t *testing.T) {
+	limits := []frame.Limit{{Min: -100, Max: 100}, {Min: -100, Max: 100}}
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := frame.NewMobile2DFrame("base", limits, geometry)
+	test.That(t, err, test.ShouldBeNil)
+	fs := frame.NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(model, fs.World()), test.ShouldBeNil)
+	// the corner of the path wraps tightly around the obstacle, so a spline cutting the corner would hit it
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 20, Y: -20}), r3.Vector{X: 20, Y: 20, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	worldState := &frame.WorldState{
+		Obstacles: []*frame.GeometriesInFrame{frame.NewGeometriesInFrame(frame.World, map[string]spatialmath.Geometry{"b": obstacle})},
+	}
+	seedMap := map[string][]frame.Input{"base": frame.FloatsToInputs([]float64{-60, 0})}
+
+	opt := newBasicPlannerOptions()
+	collisionConstraint, err := newObstacleConstraint(model, fs, worldState, seedMap, nil, false, false, defaultCollisionBufferMM)
+	test.That(t, err, test.ShouldBeNil)
+	opt.AddConstraint(defaultObstacleConstraintName, collisionConstraint)
+	mp, err := newCBiRRTMotionPlanner(model, rand.New(rand.NewSource(1)), logger.Sugar(), opt)
+	test.That(t, err, test.ShouldBeNil)
+
+	path := stepsToNodes([][]frame.Input{
+		frame.FloatsToInputs([]float64{-60, 0}),
+		frame.FloatsToInputs([]float64{0, 0}),
+		frame.FloatsToInputs([]float64{0, -60}),
+		frame.FloatsToInputs([]float64{60, -60}),
+	})
+	for i := 1; i < len(path); i++ {
+		test.That(t, mp.checkPath(path[i-1].Q(), path[i].Q()), test.ShouldBeTrue)
+	}
+	test.That(t, splineSmoothPath(context.Background(), mp, model, path), test.ShouldResemble, path)
+
+	opt.SmoothMethod = SplineSmoothMethod
+	smoothed := splineSmoothPath(context.Background(), mp, model, path)
+	test.That(t, len(smoothed), test.ShouldBeGreaterThanOrEqualTo, opt.SplineSteps)
+	test.That(t, smoothed[0].Q(), test.ShouldResemble, path[0].Q())
+	test.That(t, smoothed[len(smoothed)-1].Q(), test.ShouldResemble, path[len(path)-1].Q())
+	// the smoothed path still passes through every waypoint, and never hits the obstacle
+	passes := map[[2]float64]bool{}
+	for i, step := range smoothed {
+		q := frame.InputsToFloats(step.Q())
+		passes[[2]float64{q[0], q[1]}] = true
+		if i > 0 {
+			test.That(t, mp.checkPath(smoothed[i-1].Q(), step.Q()), test.ShouldBeTrue)
+		}
+	}
+	for _, waypoint := range path {
+		q := frame.InputsToFloats(waypoint.Q())
+		test.That(t, passes[[2]float64{q[0], q[1]}], test.ShouldBeTrue)
+	}
+	// away from the obstacle the path curves rather than following its straight lines
+	curved := false
+	for _, step := range smoothed {
+		q := frame.InputsToFloats(step.Q())
+		if q[0] < 0 && q[1] != 0 {
+			curved = true
+		}
+	}
+	test.That(t, curved, test.ShouldBeTrue)
+
+	sFrames, err := fs.TracebackFrame(model)
+	test.That(t, err, test.ShouldBeNil)
+	sf, err := newSolverFrame(fs, sFrames, frame.World, seedMap)
+	test.That(t, err, test.ShouldBeNil)
+	pm, err := newPlanManager(sf, fs, logger.Sugar(), 1)
+	test.That(t, err, test.ShouldBeNil)
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 60, Y: -60})
+	_, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal, seedMap, worldState, map[string]interface{}{
+		"smooth_method": SplineSmoothMethod,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	for _, badOpts := range []map[string]interface{}{
+		{"smooth_method": "bezier"},
+		{"smooth_method": SplineSmoothMethod, "spline_steps": 1},
+		{"smooth_method": SplineSmoothMethod, "planning_alg": "hybridastar"},
+	} {
+		_, err = pm.plannerSetupFromMoveRequest(spatialmath.NewZeroPose(), goal, seedMap, worldState, badOpts)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
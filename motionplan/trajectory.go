@@ -0,0 +1,240 @@
+package motionplan
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// Directions of consecutive path segments whose dot product is at least this are considered the same, so the path need not
+// stop between them.
+const collinearThreshold = 1 - 1e-6
+
+// TimedWaypoint is a waypoint of a trajectory: the inputs of a frame at a time, and how fast each input is moving then, in input
+// units (radians or mm) per second.
+type TimedWaypoint struct {
+	Time       time.Duration
+	Inputs     []referenceframe.Input
+	Velocities []float64
+}
+
+// Trajectory is a path with timing. Between consecutive waypoints every input moves with constant acceleration, so waypoints are
+// placed wherever an input starts or stops accelerating. An input without an acceleration limit changes velocity instantly, which
+// shows up as two waypoints at the same time with different velocities.
+type Trajectory []TimedWaypoint
+
+// Duration returns how long the trajectory takes to run.
+func (traj Trajectory) Duration() time.Duration {
+	if len(traj) == 0 {
+		return 0
+	}
+	return traj[len(traj)-1].Time
+}
+
+// At returns the inputs and their velocities at a time into the trajectory. Times outside of the trajectory are clamped to it.
+func (traj Trajectory) At(t time.Duration) ([]referenceframe.Input, []float64) {
+	if len(traj) == 0 {
+		return nil, nil
+	}
+	next := sort.Search(len(traj), func(i int) bool {
+		return traj[i].Time > t
+	})
+	if next == 0 {
+		return traj[0].Inputs, traj[0].Velocities
+	}
+	if next == len(traj) {
+		last := traj[len(traj)-1]
+		return last.Inputs, last.Velocities
+	}
+	from, to := traj[next-1], traj[next]
+	span := (to.Time - from.Time).Seconds()
+	elapsed := (t - from.Time).Seconds()
+	inputs := make([]referenceframe.Input, len(from.Inputs))
+	velocities := make([]float64, len(from.Velocities))
+	for i, in := range from.Inputs {
+		acc := (to.Velocities[i] - from.Velocities[i]) / span
+		inputs[i] = referenceframe.Input{Value: in.Value + from.Velocities[i]*elapsed + acc*elapsed*elapsed/2}
+		velocities[i] = from.Velocities[i] + acc*elapsed
+	}
+	return inputs, velocities
+}
+
+// TimeParameterizeFramePath times a path of a frame as fast as the dynamic limits of the frame allow. See TimeParameterize.
+func TimeParameterizeFramePath(f referenceframe.Frame, path [][]referenceframe.Input) (Trajectory, error) {
+	return TimeParameterize(path, referenceframe.DynamicLimitsOf(f))
+}
+
+// TimeParameterize times a path so that it is followed exactly, as fast as the velocity and acceleration limit of each input
+// allows. The path starts and ends at rest, and also comes to rest at each waypoint where it changes direction, as the velocity of
+// some input would otherwise have to change instantly. Limits of zero mean the input is not limited, but every segment of the path
+// must move at least one limited input.
+//
+// This is a time-optimal path parameterization in the style of TOPP: it finds the fastest speed along the path that keeps within
+// the limits, by bounding the speed reachable accelerating forward from the start and decelerating backward from the end.
+func TimeParameterize(path [][]referenceframe.Input, limits []referenceframe.DynamicLimit) (Trajectory, error) {
+	if len(path) == 0 {
+		return nil, errors.New("cannot time an empty path")
+	}
+	// drop repeated waypoints, which would be segments of no length
+	waypoints := [][]float64{referenceframe.InputsToFloats(path[0])}
+	for _, step := range path {
+		if len(step) != len(limits) {
+			return nil, referenceframe.NewIncorrectInputLengthError(len(step), len(limits))
+		}
+		q := referenceframe.InputsToFloats(step)
+		if distance(waypoints[len(waypoints)-1], q) > defaultEpsilon {
+			waypoints = append(waypoints, q)
+		}
+	}
+
+	if len(waypoints) == 1 {
+		return Trajectory{{Inputs: path[0], Velocities: make([]float64, len(limits))}}, nil
+	}
+
+	segments := make([]*pathSegment, 0, len(waypoints)-1)
+	for i := 0; i < len(waypoints)-1; i++ {
+		seg, err := newPathSegment(waypoints[i], waypoints[i+1], limits)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot time segment %d of the path", i)
+		}
+		segments = append(segments, seg)
+	}
+
+	// the highest squared speed along the path at each waypoint, first from the limits of the segments either side of it
+	maxSpeedSq := make([]float64, len(waypoints))
+	for i := 1; i < len(waypoints)-1; i++ {
+		if floatsDot(segments[i-1].dir, segments[i].dir) >= collinearThreshold {
+			maxSpeedSq[i] = math.Pow(math.Min(segments[i-1].maxSpeed, segments[i].maxSpeed), 2)
+		}
+	}
+	// then from how fast it can be reached accelerating from the start, and how fast it can be left decelerating to the end
+	for i := 1; i < len(waypoints); i++ {
+		maxSpeedSq[i] = math.Min(maxSpeedSq[i], maxSpeedSq[i-1]+2*segments[i-1].maxAccel*segments[i-1].length)
+	}
+	for i := len(waypoints) - 2; i >= 0; i-- {
+		maxSpeedSq[i] = math.Min(maxSpeedSq[i], maxSpeedSq[i+1]+2*segments[i].maxAccel*segments[i].length)
+	}
+
+	traj := Trajectory{newTimedWaypoint(0, waypoints[0], segments[0].dir, 0, 0)}
+	var elapsed float64
+	for i, seg := range segments {
+		pos := 0.
+		for _, ph := range seg.phases(math.Sqrt(maxSpeedSq[i]), math.Sqrt(maxSpeedSq[i+1])) {
+			lastSpeed := speedAlong(traj[len(traj)-1].Velocities, seg.dir)
+			if math.Abs(ph.startSpeed-lastSpeed) > 1e-6*math.Max(1, ph.startSpeed) {
+				// the speed changes instantly, for lack of acceleration limits
+				traj = append(traj, newTimedWaypoint(elapsed, waypoints[i], seg.dir, pos, ph.startSpeed))
+			}
+			elapsed += ph.duration
+			pos += ph.length
+			traj = append(traj, newTimedWaypoint(elapsed, waypoints[i], seg.dir, pos, ph.endSpeed))
+		}
+	}
+	// the path ends exactly at its last waypoint, and at rest
+	last := &traj[len(traj)-1]
+	last.Inputs = referenceframe.FloatsToInputs(waypoints[len(waypoints)-1])
+	if floatsDot(last.Velocities, last.Velocities) > 0 {
+		// the speed drops instantly, for lack of acceleration limits
+		traj = append(traj, TimedWaypoint{Time: last.Time, Inputs: last.Inputs, Velocities: make([]float64, len(limits))})
+	}
+	return traj, nil
+}
+
+// pathSegment is a straight line between waypoints. Speeds and accelerations along it are of the distance travelled in input space.
+type pathSegment struct {
+	dir      []float64
+	length   float64
+	maxSpeed float64
+	maxAccel float64
+}
+
+func newPathSegment(from, to []float64, limits []referenceframe.DynamicLimit) (*pathSegment, error) {
+	length := distance(from, to)
+	seg := &pathSegment{
+		dir:      make([]float64, len(from)),
+		length:   length,
+		maxSpeed: math.Inf(1),
+		maxAccel: math.Inf(1),
+	}
+	for i := range from {
+		seg.dir[i] = (to[i] - from[i]) / length
+		// each input bounds the speed along the segment by how much of the segment's motion it makes
+		share := math.Abs(seg.dir[i])
+		if share == 0 {
+			continue
+		}
+		if limits[i].MaxVelocity > 0 {
+			seg.maxSpeed = math.Min(seg.maxSpeed, limits[i].MaxVelocity/share)
+		}
+		if limits[i].MaxAcceleration > 0 {
+			seg.maxAccel = math.Min(seg.maxAccel, limits[i].MaxAcceleration/share)
+		}
+	}
+	if math.IsInf(seg.maxSpeed, 1) && math.IsInf(seg.maxAccel, 1) {
+		return nil, errors.New("none of the inputs it moves have velocity or acceleration limits")
+	}
+	return seg, nil
+}
+
+type phase struct {
+	duration, length, startSpeed, endSpeed float64
+}
+
+// phases returns the fastest way along the segment from a start speed to an end speed: accelerating to the highest speed allowed,
+// cruising if that speed is reached, then decelerating.
+func (seg *pathSegment) phases(startSpeed, endSpeed float64) []phase {
+	if math.IsInf(seg.maxAccel, 1) {
+		return []phase{{seg.length / seg.maxSpeed, seg.length, seg.maxSpeed, seg.maxSpeed}}
+	}
+	peak := math.Min(seg.maxSpeed, math.Sqrt((2*seg.maxAccel*seg.length+startSpeed*startSpeed+endSpeed*endSpeed)/2))
+	peak = math.Max(peak, math.Max(startSpeed, endSpeed))
+	accelLength := (peak*peak - startSpeed*startSpeed) / (2 * seg.maxAccel)
+	decelLength := (peak*peak - endSpeed*endSpeed) / (2 * seg.maxAccel)
+	cruiseLength := seg.length - accelLength - decelLength
+
+	var phases []phase
+	if accelLength > 0 {
+		phases = append(phases, phase{(peak - startSpeed) / seg.maxAccel, accelLength, startSpeed, peak})
+	}
+	if cruiseLength > defaultEpsilon*defaultEpsilon {
+		phases = append(phases, phase{cruiseLength / peak, cruiseLength, peak, peak})
+	}
+	if decelLength > 0 {
+		phases = append(phases, phase{(peak - endSpeed) / seg.maxAccel, decelLength, peak, endSpeed})
+	}
+	return phases
+}
+
+func newTimedWaypoint(seconds float64, from, dir []float64, pos, speed float64) TimedWaypoint {
+	inputs := make([]referenceframe.Input, len(from))
+	velocities := make([]float64, len(from))
+	for i := range from {
+		inputs[i] = referenceframe.Input{Value: from[i] + dir[i]*pos}
+		velocities[i] = dir[i] * speed
+	}
+	return TimedWaypoint{Time: time.Duration(seconds * float64(time.Second)), Inputs: inputs, Velocities: velocities}
+}
+
+func speedAlong(velocities, dir []float64) float64 {
+	return floatsDot(velocities, dir)
+}
+
+func floatsDot(a, b []float64) float64 {
+	var dot float64
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return dot
+}
+
+func distance(a, b []float64) float64 {
+	var sq float64
+	for i := range a {
+		sq += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return math.Sqrt(sq)
+}
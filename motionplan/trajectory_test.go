@@ -0,0 +1,119 @@
+package motionplan
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	frame "go.viam.com/rdk/referenceframe"
+)
+
+func TestTimeParameterizeSegment(t *testing.T) {
+	limits := []frame.DynamicLimit{{MaxVelocity: 2, MaxAcceleration: 1}, {MaxVelocity: 2, MaxAcceleration: 1}}
+	path := [][]frame.Input{frame.FloatsToInputs([]float64{0, 0}), frame.FloatsToInputs([]float64{8, 0})}
+	traj, err := TimeParameterize(path, limits)
+	test.That(t, err, test.ShouldBeNil)
+
+	// 2s to accelerate over 2 units, 2s to cruise over 4, and 2s to decelerate over 2
+	test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 6, 1e-6)
+	test.That(t, len(traj), test.ShouldEqual, 4)
+	inputs, velocities := traj.At(time.Second)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, 0.5, 1e-6)
+	test.That(t, velocities[0], test.ShouldAlmostEqual, 1, 1e-6)
+	inputs, velocities = traj.At(traj.Duration() / 2)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, 4, 1e-6)
+	test.That(t, velocities[0], test.ShouldAlmostEqual, 2, 1e-6)
+	test.That(t, velocities[1], test.ShouldEqual, 0)
+
+	inputs, velocities = traj.At(traj.Duration() + time.Second)
+	test.That(t, inputs, test.ShouldResemble, path[1])
+	test.That(t, velocities, test.ShouldResemble, []float64{0, 0})
+
+	// the input that moves least is slowed to keep pace with the other
+	path[1] = frame.FloatsToInputs([]float64{8, 4})
+	traj, err = TimeParameterize(path, limits)
+	test.That(t, err, test.ShouldBeNil)
+	_, velocities = traj.At(traj.Duration() / 2)
+	test.That(t, velocities[0], test.ShouldAlmostEqual, 2, 1e-6)
+	test.That(t, velocities[1], test.ShouldAlmostEqual, 1, 1e-6)
+}
+
+func TestTimeParameterizeCorners(t *testing.T) {
+	limits := []frame.DynamicLimit{{MaxVelocity: 1, MaxAcceleration: 1}, {MaxVelocity: 1, MaxAcceleration: 1}}
+
+	// a waypoint along a straight line does not slow the path down
+	straight := [][]frame.Input{
+		frame.FloatsToInputs([]float64{0, 0}),
+		frame.FloatsToInputs([]float64{2, 0}),
+		frame.FloatsToInputs([]float64{2, 0}),
+		frame.FloatsToInputs([]float64{4, 0}),
+	}
+	traj, err := TimeParameterize(straight, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 5, 1e-6)
+	inputs, velocities := traj.At(traj.Duration() / 2)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, 2, 1e-6)
+	test.That(t, velocities[0], test.ShouldAlmostEqual, 1, 1e-6)
+
+	// but the path stops where it turns
+	corner := [][]frame.Input{
+		frame.FloatsToInputs([]float64{0, 0}),
+		frame.FloatsToInputs([]float64{2, 0}),
+		frame.FloatsToInputs([]float64{2, 2}),
+	}
+	traj, err = TimeParameterize(corner, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 6, 1e-6)
+	inputs, velocities = traj.At(traj.Duration() / 2)
+	test.That(t, inputs, test.ShouldResemble, corner[1])
+	test.That(t, velocities, test.ShouldResemble, []float64{0, 0})
+}
+
+func TestTimeParameterizeVelocityLimitsOnly(t *testing.T) {
+	limits := []frame.DynamicLimit{{MaxVelocity: 2}}
+	path := [][]frame.Input{frame.FloatsToInputs([]float64{0}), frame.FloatsToInputs([]float64{4})}
+	traj, err := TimeParameterize(path, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 2, 1e-6)
+
+	// the input jumps to full speed at the start and stops dead at the end
+	test.That(t, len(traj), test.ShouldEqual, 4)
+	test.That(t, traj[0].Time, test.ShouldEqual, traj[1].Time)
+	test.That(t, traj[2].Time, test.ShouldEqual, traj[3].Time)
+	inputs, velocities := traj.At(500 * time.Millisecond)
+	test.That(t, inputs[0].Value, test.ShouldAlmostEqual, 1, 1e-6)
+	test.That(t, velocities[0], test.ShouldAlmostEqual, 2, 1e-6)
+	_, velocities = traj.At(traj.Duration())
+	test.That(t, velocities[0], test.ShouldEqual, 0)
+}
+
+func TestTimeParameterizeErrors(t *testing.T) {
+	_, err := TimeParameterize(nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	limits := []frame.DynamicLimit{{MaxVelocity: 1}, {}}
+	_, err = TimeParameterize([][]frame.Input{frame.FloatsToInputs([]float64{0})}, limits)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// an input without limits may only move alongside one with limits
+	traj, err := TimeParameterize([][]frame.Input{
+		frame.FloatsToInputs([]float64{0, 0}),
+		frame.FloatsToInputs([]float64{1, 1}),
+	}, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, traj.Duration().Seconds(), test.ShouldAlmostEqual, 1, 1e-6)
+	_, err = TimeParameterize([][]frame.Input{
+		frame.FloatsToInputs([]float64{0, 0}),
+		frame.FloatsToInputs([]float64{0, 1}),
+	}, limits)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a path that goes nowhere takes no time
+	traj, err = TimeParameterize([][]frame.Input{
+		frame.FloatsToInputs([]float64{1, 1}),
+		frame.FloatsToInputs([]float64{1, 1}),
+	}, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, traj.Duration(), test.ShouldEqual, 0)
+}
@@ -2,6 +2,7 @@ package motionplan
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 
@@ -116,14 +117,23 @@ func stepsToNodes(steps [][]referenceframe.Input) []node {
 	return nodes
 }
 
+func nodesToInputs(nodes []node) [][]referenceframe.Input {
+	steps := make([][]referenceframe.Input, 0, len(nodes))
+	for _, n := range nodes {
+		steps = append(steps, n.Q())
+	}
+	return steps
+}
+
 type resultPromise struct {
 	steps  [][]referenceframe.Input
+	err    error
 	future chan *rrtPlanReturn
 }
 
 func (r *resultPromise) result(ctx context.Context) ([][]referenceframe.Input, error) {
 	if r.steps != nil && len(r.steps) > 0 {
-		return r.steps, nil
+		return r.steps, r.err
 	}
 	// wait for a context cancel or a valid channel result
 	for {
@@ -134,6 +144,9 @@ func (r *resultPromise) result(ctx context.Context) ([][]referenceframe.Input, e
 		}
 		select {
 		case planReturn := <-r.future:
+			if errors.Is(planReturn.err(), ErrPartialPlan) {
+				return planReturn.toInputs(), planReturn.err()
+			}
 			if planReturn.err() != nil {
 				return nil, planReturn.err()
 			}
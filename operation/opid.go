@@ -3,12 +3,14 @@ package operation
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/google/uuid"
+	"go.opencensus.io/trace"
 
 	"go.viam.com/rdk/session"
 )
@@ -25,17 +27,29 @@ var methodPrefixesToFilter = [...]string{
 
 // Operation is an operation happening on the server.
 type Operation struct {
-	ID        uuid.UUID
-	SessionID uuid.UUID
-	Method    string
-	Arguments interface{}
-	Started   time.Time
+	ID        uuid.UUID   `json:"id"`
+	SessionID uuid.UUID   `json:"session_id"`
+	Method    string      `json:"method"`
+	Arguments interface{} `json:"arguments,omitempty"`
+	Started   time.Time   `json:"started"`
+	// ParentID is the operation that started this one, possibly on another robot, or uuid.Nil.
+	ParentID uuid.UUID `json:"parent_id"`
+	// TraceID is the trace the operation's span belongs to, shared by all operations of a tree
+	// even across gRPC hops.
+	TraceID string `json:"trace_id"`
 
 	myManager *Manager
 	cancel    context.CancelFunc
+	span      *trace.Span
 	labels    []string
 }
 
+// A Node is an operation along with the operations it started.
+type Node struct {
+	*Operation
+	Children []*Node `json:"children,omitempty"`
+}
+
 // Cancel cancel the context associated with an operation.
 func (o *Operation) Cancel() {
 	o.cancel()
@@ -67,6 +81,7 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 }
 
 func (o *Operation) cleanup() {
+	o.span.End()
 	o.myManager.remove(o.ID)
 }
 
@@ -119,12 +134,68 @@ func (m *Manager) FindString(id string) *Operation {
 	return m.ops[id]
 }
 
+// Children returns the running operations started by an operation.
+func (m *Manager) Children(id uuid.UUID) []*Operation {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var children []*Operation
+	for _, o := range m.ops {
+		if o.ParentID == id {
+			children = append(children, o)
+		}
+	}
+	return children
+}
+
+// Tree returns the running operations arranged by which started which. Operations whose parent
+// is not running here, such as ones started by another robot, are roots of the tree. Siblings
+// are ordered by when they started.
+func (m *Manager) Tree() []*Node {
+	return BuildTree(m.All())
+}
+
+// BuildTree arranges operations by which started which, the same way as Manager.Tree, such as
+// ones described by another robot.
+func BuildTree(ops []*Operation) []*Node {
+	nodes := make(map[uuid.UUID]*Node, len(ops))
+	for _, o := range ops {
+		nodes[o.ID] = &Node{Operation: o}
+	}
+	var roots []*Node
+	for _, o := range ops {
+		if parent, ok := nodes[o.ParentID]; ok && o.ParentID != o.ID {
+			parent.Children = append(parent.Children, nodes[o.ID])
+		} else {
+			roots = append(roots, nodes[o.ID])
+		}
+	}
+	sortNodes(roots)
+	return roots
+}
+
+func sortNodes(nodes []*Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Started.Before(nodes[j].Started)
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
 // Create puts an operation on this context.
 func (m *Manager) Create(ctx context.Context, method string, args interface{}) (context.Context, func()) {
-	return m.createWithID(ctx, uuid.New(), method, args)
+	return m.createWithID(ctx, uuid.New(), uuid.Nil, nil, method, args)
 }
 
-func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string, args interface{}) (context.Context, func()) {
+// createWithID creates an operation with the given id. The parent operation and span of an
+// operation received over gRPC are those of its sender.
+func (m *Manager) createWithID(
+	ctx context.Context,
+	id, parentID uuid.UUID,
+	remoteSpan *trace.SpanContext,
+	method string,
+	args interface{},
+) (context.Context, func()) {
 	if ctx.Value(opidKey) != nil {
 		panic("operations cannot be nested")
 	}
@@ -145,11 +216,18 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 		Method:    method,
 		Arguments: args,
 		Started:   time.Now(),
+		ParentID:  parentID,
 		myManager: m,
 	}
 	if sess, ok := session.FromContext(ctx); ok {
 		op.SessionID = sess.ID()
 	}
+	if remoteSpan != nil {
+		ctx, op.span = trace.StartSpanWithRemoteParent(ctx, method, *remoteSpan)
+	} else {
+		ctx, op.span = trace.StartSpan(ctx, method)
+	}
+	op.TraceID = op.span.SpanContext().TraceID.String()
 	ctx = context.WithValue(ctx, opidKey, op)
 	ctx, op.cancel = context.WithCancel(ctx)
 	m.add(op)
@@ -157,6 +235,32 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 	return ctx, func() { op.cleanup() }
 }
 
+// CreateChild puts an operation on this context that is a child of the current Operation, such
+// as a call a service makes to a component to carry out its own operation. The child is
+// canceled along with its parent. If no Operation is set, the context is returned as is.
+func CreateChild(ctx context.Context, method string, args interface{}) (context.Context, func()) {
+	parent := Get(ctx)
+	if parent == nil {
+		return ctx, func() {}
+	}
+	op := &Operation{
+		ID:        uuid.New(),
+		SessionID: parent.SessionID,
+		Method:    method,
+		Arguments: args,
+		Started:   time.Now(),
+		ParentID:  parent.ID,
+		myManager: parent.myManager,
+	}
+	ctx, op.span = trace.StartSpan(ctx, method)
+	op.TraceID = op.span.SpanContext().TraceID.String()
+	ctx = context.WithValue(ctx, opidKey, op)
+	ctx, op.cancel = context.WithCancel(ctx)
+	op.myManager.add(op)
+
+	return ctx, func() { op.cleanup() }
+}
+
 // Get returns the current Operation. This can be nil.
 func Get(ctx context.Context) *Operation {
 	o := ctx.Value(opidKey)
@@ -89,3 +89,52 @@ func TestCreateWithSession(t *testing.T) {
 	test.That(t, op2.SessionID, test.ShouldEqual, sess1.ID())
 	cleanup()
 }
+
+func TestCreateChild(t *testing.T) {
+	ctx := context.Background()
+
+	logger := golog.NewTestLogger(t)
+	manager := NewManager(logger)
+
+	noOpCtx, cleanup := CreateChild(ctx, "child", nil)
+	test.That(t, Get(noOpCtx), test.ShouldBeNil)
+	cleanup()
+	test.That(t, manager.All(), test.ShouldHaveLength, 0)
+
+	sess := session.New("someone", nil, 0, nil)
+	parentCtx, cleanupParent := manager.Create(session.ToContext(ctx, sess), "parent", nil)
+	defer cleanupParent()
+	parent := Get(parentCtx)
+
+	childCtx, cleanupChild := CreateChild(parentCtx, "child", nil)
+	child := Get(childCtx)
+	test.That(t, child.ID, test.ShouldNotEqual, parent.ID)
+	test.That(t, child.ParentID, test.ShouldEqual, parent.ID)
+	test.That(t, child.SessionID, test.ShouldEqual, sess.ID())
+	test.That(t, child.TraceID, test.ShouldEqual, parent.TraceID)
+
+	grandchildCtx, cleanupGrandchild := CreateChild(childCtx, "grandchild", nil)
+	grandchild := Get(grandchildCtx)
+	test.That(t, grandchild.ParentID, test.ShouldEqual, child.ID)
+
+	test.That(t, manager.All(), test.ShouldHaveLength, 3)
+	test.That(t, manager.Children(parent.ID), test.ShouldResemble, []*Operation{child})
+
+	tree := manager.Tree()
+	test.That(t, tree, test.ShouldHaveLength, 1)
+	test.That(t, tree[0].Operation, test.ShouldEqual, parent)
+	test.That(t, tree[0].Children, test.ShouldHaveLength, 1)
+	test.That(t, tree[0].Children[0].Operation, test.ShouldEqual, child)
+	test.That(t, tree[0].Children[0].Children, test.ShouldHaveLength, 1)
+	test.That(t, tree[0].Children[0].Children[0].Operation, test.ShouldEqual, grandchild)
+
+	// canceling the parent cancels its children
+	parent.Cancel()
+	test.That(t, childCtx.Err(), test.ShouldNotBeNil)
+	test.That(t, grandchildCtx.Err(), test.ShouldNotBeNil)
+
+	cleanupGrandchild()
+	cleanupChild()
+	test.That(t, manager.All(), test.ShouldHaveLength, 1)
+	test.That(t, manager.Children(parent.ID), test.ShouldHaveLength, 0)
+}
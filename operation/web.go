@@ -5,15 +5,22 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"go.opencensus.io/trace/propagation"
 	"go.viam.com/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
-const opidMetadataKey = "opid"
+const (
+	opidMetadataKey       = "opid"
+	parentOpidMetadataKey = "opid-parent"
+	// traceMetadataKey is where opencensus propagates span contexts over gRPC.
+	traceMetadataKey = "grpc-trace-bin"
+)
 
 // UnaryClientInterceptor adds the operation id from the current context (if any) to the
-// outgoing unary RPC metadata.
+// outgoing unary RPC metadata, along with its parent and trace.
 func UnaryClientInterceptor(
 	ctx context.Context,
 	method string,
@@ -22,14 +29,11 @@ func UnaryClientInterceptor(
 	invoker grpc.UnaryInvoker,
 	opts ...grpc.CallOption,
 ) error {
-	if op := Get(ctx); op != nil && op.ID.String() != "" {
-		ctx = metadata.AppendToOutgoingContext(ctx, opidMetadataKey, op.ID.String())
-	}
-	return invoker(ctx, method, req, reply, cc, opts...)
+	return invoker(appendToOutgoingContext(ctx), method, req, reply, cc, opts...)
 }
 
 // StreamClientInterceptor adds the operation id from the current context (if any) to the
-// outgoing streaming RPC metadata.
+// outgoing streaming RPC metadata, along with its parent and trace.
 func StreamClientInterceptor(
 	ctx context.Context,
 	desc *grpc.StreamDesc,
@@ -38,10 +42,20 @@ func StreamClientInterceptor(
 	streamer grpc.Streamer,
 	opts ...grpc.CallOption,
 ) (grpc.ClientStream, error) {
+	return streamer(appendToOutgoingContext(ctx), desc, cc, method, opts...)
+}
+
+func appendToOutgoingContext(ctx context.Context) context.Context {
 	if op := Get(ctx); op != nil && op.ID.String() != "" {
 		ctx = metadata.AppendToOutgoingContext(ctx, opidMetadataKey, op.ID.String())
+		if op.ParentID != uuid.Nil {
+			ctx = metadata.AppendToOutgoingContext(ctx, parentOpidMetadataKey, op.ParentID.String())
+		}
+	}
+	if span := trace.FromContext(ctx); span != nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, traceMetadataKey, string(propagation.Binary(span.SpanContext())))
 	}
-	return streamer(ctx, desc, cc, method, opts...)
+	return ctx
 }
 
 // UnaryServerInterceptor creates a new operation in the current context before passing
@@ -78,7 +92,8 @@ func (m *Manager) StreamServerInterceptor(
 	return handler(srv, &ssStreamContextWrapper{ss, ctx})
 }
 
-// CreateFromIncomingContext creates a new operation from an incoming context.
+// CreateFromIncomingContext creates a new operation from an incoming context. The operation
+// keeps the parent and continues the trace of the sender's operation, if any.
 func (m *Manager) CreateFromIncomingContext(ctx context.Context, method string) (context.Context, func()) {
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -90,7 +105,19 @@ func (m *Manager) CreateFromIncomingContext(ctx context.Context, method string)
 		m.logger.Warnw("failed to create operation id from metadata", "error", err)
 		return m.Create(ctx, method, nil)
 	}
-	return m.createWithID(ctx, opid, method, nil)
+	var parentID uuid.UUID
+	if values := meta.Get(parentOpidMetadataKey); len(values) == 1 {
+		if parentID, err = uuid.Parse(values[0]); err != nil {
+			m.logger.Warnw("failed to parse parent operation id from metadata", "error", err)
+		}
+	}
+	var remoteSpan *trace.SpanContext
+	if values := meta.Get(traceMetadataKey); len(values) == 1 {
+		if sc, ok := propagation.FromBinary([]byte(values[0])); ok {
+			remoteSpan = &sc
+		}
+	}
+	return m.createWithID(ctx, opid, parentID, remoteSpan, method, nil)
 }
 
 // GetOrCreateFromMetadata returns an operation id from metadata, or generates a random
@@ -6,7 +6,9 @@ import (
 
 	"github.com/edaniels/golog"
 	"github.com/google/uuid"
+	"go.opencensus.io/trace"
 	"go.viam.com/test"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -36,3 +38,31 @@ func TestCreateFromIncomingContextWithOpid(t *testing.T) {
 	test.That(t, ops, test.ShouldHaveLength, 1)
 	test.That(t, ops[0].ID.String(), test.ShouldEqual, opid.String())
 }
+
+func TestOperationPropagatesAcrossGRPC(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	client := NewManager(logger)
+	server := NewManager(logger)
+
+	ctx, done := client.Create(context.Background(), "motion", nil)
+	defer done()
+	ctx, childDone := CreateChild(ctx, "arm", nil)
+	defer childDone()
+	child := Get(ctx)
+
+	var sent metadata.MD
+	err := UnaryClientInterceptor(ctx, "method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			sent, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+	test.That(t, err, test.ShouldBeNil)
+
+	remoteCtx, remoteDone := server.CreateFromIncomingContext(metadata.NewIncomingContext(context.Background(), sent), "arm")
+	defer remoteDone()
+	remote := Get(remoteCtx)
+	test.That(t, remote.ID, test.ShouldEqual, child.ID)
+	test.That(t, remote.ParentID, test.ShouldEqual, child.ParentID)
+	test.That(t, remote.TraceID, test.ShouldEqual, child.TraceID)
+	test.That(t, trace.FromContext(remoteCtx).SpanContext().TraceID, test.ShouldEqual, trace.FromContext(ctx).SpanContext().TraceID)
+}
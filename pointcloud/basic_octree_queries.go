@@ -0,0 +1,279 @@
+package pointcloud
+
+import (
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r3"
+)
+
+// A Frustum is a convex volume, such as the one a camera sees, bounded by planes. Each plane is
+// an equation a*x + b*y + c*z + d = 0 whose normal (a, b, c) points into the volume.
+type Frustum [][4]float64
+
+// NewCameraFrustum returns the frustum seen by a pinhole camera at the origin looking down +Z,
+// given its horizontal and vertical fields of view in radians and the near and far distances
+// in mm between which points are kept.
+func NewCameraFrustum(fovX, fovY, near, far float64) Frustum {
+	tanX, tanY := math.Tan(fovX/2), math.Tan(fovY/2)
+	return Frustum{
+		{1, 0, tanX, 0},
+		{-1, 0, tanX, 0},
+		{0, 1, tanY, 0},
+		{0, -1, tanY, 0},
+		{0, 0, 1, -near},
+		{0, 0, -1, far},
+	}
+}
+
+// Contains returns whether a point is inside the frustum or on its boundary.
+func (f Frustum) Contains(p r3.Vector) bool {
+	for _, plane := range f {
+		if plane[0]*p.X+plane[1]*p.Y+plane[2]*p.Z+plane[3] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// classifyBox returns whether an axis aligned box is entirely outside or entirely inside the
+// frustum. A box that is neither intersects its boundary.
+func (f Frustum) classifyBox(minPt, maxPt r3.Vector) (outside, inside bool) {
+	inside = true
+	for _, plane := range f {
+		// the corners of the box farthest along and against the normal of the plane
+		far, near := maxPt, minPt
+		if plane[0] < 0 {
+			far.X, near.X = minPt.X, maxPt.X
+		}
+		if plane[1] < 0 {
+			far.Y, near.Y = minPt.Y, maxPt.Y
+		}
+		if plane[2] < 0 {
+			far.Z, near.Z = minPt.Z, maxPt.Z
+		}
+		if plane[0]*far.X+plane[1]*far.Y+plane[2]*far.Z+plane[3] < 0 {
+			return true, false
+		}
+		if plane[0]*near.X+plane[1]*near.Y+plane[2]*near.Z+plane[3] < 0 {
+			inside = false
+		}
+	}
+	return false, inside
+}
+
+// NearestNeighbor returns the nearest point and its distance from the input point.
+func (octree *BasicOctree) NearestNeighbor(p r3.Vector) (r3.Vector, Data, float64, bool) {
+	nearest := octree.KNearestNeighbors(p, 1, true)
+	if len(nearest) == 0 {
+		return r3.Vector{}, nil, 0.0, false
+	}
+	return nearest[0].P, nearest[0].D, nearest[0].P.Distance(p), true
+}
+
+// KNearestNeighbors returns the k nearest points ordered by distance. If includeSelf is true and if the
+// point p is in the octree, point p will also be returned in the slice as the first element with distance 0.
+func (octree *BasicOctree) KNearestNeighbors(p r3.Vector, k int, includeSelf bool) []*PointAndData {
+	if k <= 0 {
+		return []*PointAndData{}
+	}
+	s := &neighborSearch{p: p, k: k, radius: math.Inf(1), includeSelf: includeSelf}
+	octree.helperNearest(s)
+	return s.points()
+}
+
+// RadiusNearestNeighbors returns the nearest points within a radius r (inclusive) ordered by distance.
+// If includeSelf is true and if the point p is in the octree, point p will also be returned in the slice
+// as the first element with distance 0.
+func (octree *BasicOctree) RadiusNearestNeighbors(p r3.Vector, r float64, includeSelf bool) []*PointAndData {
+	s := &neighborSearch{p: p, k: math.MaxInt, radius: r, includeSelf: includeSelf}
+	octree.helperNearest(s)
+	return s.points()
+}
+
+// PointsInBox returns the points of the octree inside the axis aligned box between two corners.
+func (octree *BasicOctree) PointsInBox(minPt, maxPt r3.Vector) []PointAndData {
+	box := Frustum{
+		{1, 0, 0, -minPt.X},
+		{-1, 0, 0, maxPt.X},
+		{0, 1, 0, -minPt.Y},
+		{0, -1, 0, maxPt.Y},
+		{0, 0, 1, -minPt.Z},
+		{0, 0, -1, maxPt.Z},
+	}
+	return octree.PointsInFrustum(box)
+}
+
+// PointsInFrustum returns the points of the octree inside a frustum. Octants entirely outside of
+// it are skipped without looking at their points.
+func (octree *BasicOctree) PointsInFrustum(f Frustum) []PointAndData {
+	points := []PointAndData{}
+	octree.helperCull(f, func(pd PointAndData) {
+		points = append(points, pd)
+	})
+	return points
+}
+
+// LevelOfDetail returns a coarser version of the octree with a point for each non-empty octant
+// at the given depth, 0 being the whole octree. Each point is the centroid of the points of its
+// octant and has the data of one of them. Octants that are leaves above that depth keep their
+// point as is.
+func (octree *BasicOctree) LevelOfDetail(depth int) (PointCloud, error) {
+	pc := New()
+	if err := octree.helperLevelOfDetail(depth, pc); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+// neighborSearch keeps the nearest points found so far by a search, ordered by distance.
+type neighborSearch struct {
+	p           r3.Vector
+	k           int
+	radius      float64
+	includeSelf bool
+	found       []octreeNeighbor
+}
+
+type octreeNeighbor struct {
+	point PointAndData
+	dist  float64
+}
+
+// bound is the distance beyond which points cannot be among the nearest anymore.
+func (s *neighborSearch) bound() float64 {
+	if len(s.found) < s.k {
+		return s.radius
+	}
+	return s.found[len(s.found)-1].dist
+}
+
+func (s *neighborSearch) add(point PointAndData) {
+	if !s.includeSelf && s.p.ApproxEqual(point.P) {
+		return
+	}
+	dist := point.P.Distance(s.p)
+	if dist > s.bound() || (len(s.found) == s.k && dist == s.bound()) {
+		return
+	}
+	i := sort.Search(len(s.found), func(i int) bool { return s.found[i].dist > dist })
+	s.found = append(s.found, octreeNeighbor{})
+	copy(s.found[i+1:], s.found[i:])
+	s.found[i] = octreeNeighbor{point, dist}
+	if len(s.found) > s.k {
+		s.found = s.found[:s.k]
+	}
+}
+
+func (s *neighborSearch) points() []*PointAndData {
+	points := make([]*PointAndData, 0, len(s.found))
+	for i := range s.found {
+		points = append(points, &s.found[i].point)
+	}
+	return points
+}
+
+// helperNearest looks for the nearest points of a search, visiting the octants closest to the
+// point first and skipping those farther away than the nearest points found so far.
+func (octree *BasicOctree) helperNearest(s *neighborSearch) {
+	switch octree.node.nodeType {
+	case internalNode:
+		children := make([]*BasicOctree, 0, len(octree.node.children))
+		dists := make(map[*BasicOctree]float64, len(octree.node.children))
+		for _, child := range octree.node.children {
+			if child.size == 0 {
+				continue
+			}
+			children = append(children, child)
+			dists[child] = child.distanceTo(s.p)
+		}
+		sort.Slice(children, func(i, j int) bool { return dists[children[i]] < dists[children[j]] })
+		for _, child := range children {
+			if dists[child] > s.bound() {
+				break
+			}
+			child.helperNearest(s)
+		}
+
+	case leafNodeFilled:
+		s.add(octree.node.point)
+
+	case leafNodeEmpty:
+	}
+}
+
+// helperCull calls fn with the points of the octree inside a frustum.
+func (octree *BasicOctree) helperCull(f Frustum, fn func(pd PointAndData)) {
+	if octree.size == 0 {
+		return
+	}
+	half := r3.Vector{X: octree.sideLength / 2, Y: octree.sideLength / 2, Z: octree.sideLength / 2}
+	outside, inside := f.classifyBox(octree.center.Sub(half), octree.center.Add(half))
+	if outside {
+		return
+	}
+	if inside {
+		octree.helperIterate(0, octree.size, 0, func(p r3.Vector, d Data) bool {
+			fn(PointAndData{P: p, D: d})
+			return true
+		})
+		return
+	}
+
+	switch octree.node.nodeType {
+	case internalNode:
+		for _, child := range octree.node.children {
+			child.helperCull(f, fn)
+		}
+
+	case leafNodeFilled:
+		if f.Contains(octree.node.point.P) {
+			fn(octree.node.point)
+		}
+
+	case leafNodeEmpty:
+	}
+}
+
+// helperLevelOfDetail adds the points of the octree at the given depth below it to a pointcloud.
+func (octree *BasicOctree) helperLevelOfDetail(depth int, pc PointCloud) error {
+	switch octree.node.nodeType {
+	case internalNode:
+		if depth > 0 {
+			for _, child := range octree.node.children {
+				if err := child.helperLevelOfDetail(depth-1, pc); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		var d Data
+		octree.helperIterate(0, 1, 0, func(p r3.Vector, data Data) bool {
+			d = data
+			return false
+		})
+		centroid := r3.Vector{
+			X: octree.meta.TotalX() / float64(octree.size),
+			Y: octree.meta.TotalY() / float64(octree.size),
+			Z: octree.meta.TotalZ() / float64(octree.size),
+		}
+		return pc.Set(centroid, d)
+
+	case leafNodeFilled:
+		return pc.Set(octree.node.point.P, octree.node.point.D)
+
+	case leafNodeEmpty:
+	}
+	return nil
+}
+
+// distanceTo returns the distance from a point to the nearest point of the octant of the octree,
+// which is 0 for points inside it.
+func (octree *BasicOctree) distanceTo(p r3.Vector) float64 {
+	half := octree.sideLength / 2
+	return r3.Vector{
+		X: math.Max(math.Abs(p.X-octree.center.X)-half, 0),
+		Y: math.Max(math.Abs(p.Y-octree.center.Y)-half, 0),
+		Z: math.Max(math.Abs(p.Z-octree.center.Z)-half, 0),
+	}.Norm()
+}
@@ -0,0 +1,164 @@
+package pointcloud
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+// Helper function that makes a basic octree of random points in a 100mm cube centered on the origin.
+func makeRandomOctree(t *testing.T, numPoints int) (*BasicOctree, []PointAndData) {
+	t.Helper()
+	basicOct, err := createNewOctree(r3.Vector{}, 100)
+	test.That(t, err, test.ShouldBeNil)
+
+	//nolint:gosec
+	r := rand.New(rand.NewSource(1))
+	pointsAndData := make([]PointAndData, 0, numPoints)
+	for i := 0; i < numPoints; i++ {
+		pointsAndData = append(pointsAndData, PointAndData{
+			P: r3.Vector{X: r.Float64()*100 - 50, Y: r.Float64()*100 - 50, Z: r.Float64()*100 - 50},
+			D: NewValueData(i),
+		})
+	}
+	test.That(t, addPoints(basicOct, pointsAndData), test.ShouldBeNil)
+	return basicOct, pointsAndData
+}
+
+func sortedDistances(points []*PointAndData, p r3.Vector) []float64 {
+	dists := make([]float64, 0, len(points))
+	for _, pd := range points {
+		dists = append(dists, pd.P.Distance(p))
+	}
+	return dists
+}
+
+func TestBasicOctreeNearestNeighbors(t *testing.T) {
+	basicOct, pointsAndData := makeRandomOctree(t, 1000)
+	query := r3.Vector{X: 3, Y: -7, Z: 12}
+
+	var bruteForce []float64
+	for _, pd := range pointsAndData {
+		bruteForce = append(bruteForce, pd.P.Distance(query))
+	}
+	sort.Float64s(bruteForce)
+
+	t.Run("k nearest", func(t *testing.T) {
+		nearest := basicOct.KNearestNeighbors(query, 10, true)
+		test.That(t, nearest, test.ShouldHaveLength, 10)
+		test.That(t, sortedDistances(nearest, query), test.ShouldResemble, bruteForce[:10])
+
+		test.That(t, basicOct.KNearestNeighbors(query, 0, true), test.ShouldBeEmpty)
+		test.That(t, basicOct.KNearestNeighbors(query, 2000, true), test.ShouldHaveLength, 1000)
+	})
+
+	t.Run("nearest", func(t *testing.T) {
+		p, d, dist, ok := basicOct.NearestNeighbor(query)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, dist, test.ShouldEqual, bruteForce[0])
+		test.That(t, p.Distance(query), test.ShouldEqual, dist)
+		test.That(t, d.HasValue(), test.ShouldBeTrue)
+
+		empty, err := createNewOctree(r3.Vector{}, 100)
+		test.That(t, err, test.ShouldBeNil)
+		_, _, _, ok = empty.NearestNeighbor(query)
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		self := pointsAndData[42].P
+		nearest := basicOct.KNearestNeighbors(self, 3, true)
+		test.That(t, nearest[0].P, test.ShouldResemble, self)
+		nearest = basicOct.KNearestNeighbors(self, 3, false)
+		test.That(t, nearest, test.ShouldHaveLength, 3)
+		test.That(t, nearest[0].P, test.ShouldNotResemble, self)
+	})
+
+	t.Run("radius", func(t *testing.T) {
+		nearest := basicOct.RadiusNearestNeighbors(query, 15, true)
+		n := sort.SearchFloat64s(bruteForce, math.Nextafter(15, math.Inf(1)))
+		test.That(t, n, test.ShouldBeGreaterThan, 0)
+		test.That(t, sortedDistances(nearest, query), test.ShouldResemble, bruteForce[:n])
+	})
+}
+
+func TestBasicOctreeCulling(t *testing.T) {
+	basicOct, pointsAndData := makeRandomOctree(t, 1000)
+
+	countInside := func(contains func(p r3.Vector) bool) int {
+		var count int
+		for _, pd := range pointsAndData {
+			if contains(pd.P) {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("box", func(t *testing.T) {
+		minPt, maxPt := r3.Vector{X: -10, Y: -20, Z: 0}, r3.Vector{X: 30, Y: 5, Z: 50}
+		points := basicOct.PointsInBox(minPt, maxPt)
+		for _, pd := range points {
+			test.That(t, pd.P.X, test.ShouldBeBetweenOrEqual, minPt.X, maxPt.X)
+			test.That(t, pd.P.Y, test.ShouldBeBetweenOrEqual, minPt.Y, maxPt.Y)
+			test.That(t, pd.P.Z, test.ShouldBeBetweenOrEqual, minPt.Z, maxPt.Z)
+		}
+		test.That(t, points, test.ShouldHaveLength, countInside(func(p r3.Vector) bool {
+			return p.X >= minPt.X && p.X <= maxPt.X && p.Y >= minPt.Y && p.Y <= maxPt.Y && p.Z >= minPt.Z && p.Z <= maxPt.Z
+		}))
+
+		test.That(t, basicOct.PointsInBox(r3.Vector{X: -100, Y: -100, Z: -100}, r3.Vector{X: 100, Y: 100, Z: 100}),
+			test.ShouldHaveLength, 1000)
+		test.That(t, basicOct.PointsInBox(r3.Vector{X: 60, Y: 60, Z: 60}, r3.Vector{X: 100, Y: 100, Z: 100}),
+			test.ShouldBeEmpty)
+	})
+
+	t.Run("frustum", func(t *testing.T) {
+		frustum := NewCameraFrustum(math.Pi/2, math.Pi/3, 5, 40)
+		test.That(t, frustum.Contains(r3.Vector{Z: 10}), test.ShouldBeTrue)
+		test.That(t, frustum.Contains(r3.Vector{X: 9, Z: 10}), test.ShouldBeTrue)
+		test.That(t, frustum.Contains(r3.Vector{X: 11, Z: 10}), test.ShouldBeFalse)
+		test.That(t, frustum.Contains(r3.Vector{Y: 6, Z: 10}), test.ShouldBeFalse)
+		test.That(t, frustum.Contains(r3.Vector{Z: 4}), test.ShouldBeFalse)
+		test.That(t, frustum.Contains(r3.Vector{Z: 41}), test.ShouldBeFalse)
+
+		points := basicOct.PointsInFrustum(frustum)
+		test.That(t, points, test.ShouldNotBeEmpty)
+		for _, pd := range points {
+			test.That(t, frustum.Contains(pd.P), test.ShouldBeTrue)
+		}
+		test.That(t, points, test.ShouldHaveLength, countInside(frustum.Contains))
+	})
+}
+
+func TestBasicOctreeLevelOfDetail(t *testing.T) {
+	basicOct, _ := makeRandomOctree(t, 1000)
+
+	pc, err := basicOct.LevelOfDetail(0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pc.Size(), test.ShouldEqual, 1)
+	centroid := CloudCentroid(basicOct)
+	_, ok := pc.At(centroid.X, centroid.Y, centroid.Z)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	pc, err = basicOct.LevelOfDetail(1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pc.Size(), test.ShouldEqual, 8)
+
+	previous := pc.Size()
+	for depth := 2; depth < 6; depth++ {
+		pc, err = basicOct.LevelOfDetail(depth)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pc.Size(), test.ShouldBeGreaterThan, previous)
+		test.That(t, pc.Size(), test.ShouldBeLessThanOrEqualTo, 1000)
+		previous = pc.Size()
+	}
+
+	pc, err = basicOct.LevelOfDetail(maxRecursionDepth)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pc.Size(), test.ShouldEqual, 1000)
+}
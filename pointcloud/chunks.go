@@ -0,0 +1,79 @@
+package pointcloud
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// DefaultChunkSize is the number of points per chunk used when a chunk size of 0 is given.
+const DefaultChunkSize = 1 << 16
+
+var errStopChunks = errors.New("stop reading chunks")
+
+// IterateChunks calls fn with the points of a cloud in chunks of up to chunkSize points, so
+// that they can be processed in batches without copying the whole cloud. The chunk is reused
+// between calls, so fn must copy any points it keeps. If fn returns false, iteration stops.
+func IterateChunks(pc PointCloud, chunkSize int, fn func(chunk []PointAndData) bool) {
+	chunk := newChunk(pc.Size(), chunkSize)
+	stopped := false
+	pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		chunk = append(chunk, PointAndData{P: p, D: d})
+		if len(chunk) < cap(chunk) {
+			return true
+		}
+		stopped = !fn(chunk)
+		chunk = chunk[:0]
+		return !stopped
+	})
+	if !stopped && len(chunk) > 0 {
+		fn(chunk)
+	}
+}
+
+// StreamPCD reads PCD data and calls fn with its points in chunks of up to chunkSize points,
+// without ever holding more than one chunk in memory. Unlike ReadPCD, duplicate points are not
+// merged. The chunk is reused between calls, so fn must copy any points it keeps. If fn returns
+// false, reading stops.
+func StreamPCD(inRaw io.Reader, chunkSize int, fn func(chunk []PointAndData) bool) error {
+	in := bufio.NewReader(inRaw)
+	header, err := readPCDHeader(in)
+	if err != nil {
+		return err
+	}
+	chunk := newChunk(int(header.points), chunkSize)
+	err = readPCDPoints(in, header, func(pd PointAndData) error {
+		chunk = append(chunk, pd)
+		if len(chunk) < cap(chunk) {
+			return nil
+		}
+		if !fn(chunk) {
+			return errStopChunks
+		}
+		chunk = chunk[:0]
+		return nil
+	})
+	if errors.Is(err, errStopChunks) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(chunk) > 0 {
+		fn(chunk)
+	}
+	return nil
+}
+
+// newChunk allocates a chunk for a cloud of the given size, no bigger than the cloud.
+func newChunk(size, chunkSize int) []PointAndData {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	if size > 0 && size < chunkSize {
+		chunkSize = size
+	}
+	return make([]PointAndData, 0, chunkSize)
+}
@@ -0,0 +1,145 @@
+package pointcloud
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestIterateChunks(t *testing.T) {
+	cloud := New()
+	for i := 0; i < 10; i++ {
+		test.That(t, cloud.Set(NewVector(float64(i), 0, 0), nil), test.ShouldBeNil)
+	}
+
+	var sizes []int
+	seen := map[float64]bool{}
+	IterateChunks(cloud, 4, func(chunk []PointAndData) bool {
+		sizes = append(sizes, len(chunk))
+		for _, pd := range chunk {
+			seen[pd.P.X] = true
+		}
+		return true
+	})
+	test.That(t, sizes, test.ShouldResemble, []int{4, 4, 2})
+	test.That(t, seen, test.ShouldHaveLength, 10)
+
+	var calls int
+	IterateChunks(cloud, 4, func(chunk []PointAndData) bool {
+		calls++
+		return false
+	})
+	test.That(t, calls, test.ShouldEqual, 1)
+
+	sizes = nil
+	IterateChunks(cloud, 0, func(chunk []PointAndData) bool {
+		sizes = append(sizes, len(chunk))
+		return true
+	})
+	test.That(t, sizes, test.ShouldResemble, []int{10})
+}
+
+func TestStreamPCD(t *testing.T) {
+	cloud := newBigPC()
+	for _, pcdType := range []PCDType{PCDAscii, PCDBinary} {
+		var buf bytes.Buffer
+		test.That(t, ToPCD(cloud, &buf, pcdType), test.ShouldBeNil)
+		pcd := buf.Bytes()
+
+		var total int
+		meta := NewMetaData()
+		err := StreamPCD(bytes.NewReader(pcd), 1000, func(chunk []PointAndData) bool {
+			test.That(t, len(chunk), test.ShouldBeLessThanOrEqualTo, 1000)
+			total += len(chunk)
+			for _, pd := range chunk {
+				meta.Merge(pd.P, pd.D)
+			}
+			return true
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, total, test.ShouldEqual, cloud.Size())
+		test.That(t, meta.HasColor, test.ShouldBeTrue)
+		test.That(t, meta.MinX, test.ShouldAlmostEqual, cloud.MetaData().MinX, 1e-3)
+		test.That(t, meta.MaxZ, test.ShouldAlmostEqual, cloud.MetaData().MaxZ, 1e-3)
+
+		total = 0
+		err = StreamPCD(bytes.NewReader(pcd), 1000, func(chunk []PointAndData) bool {
+			total += len(chunk)
+			return false
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, total, test.ShouldEqual, 1000)
+	}
+
+	err := StreamPCD(bytes.NewReader([]byte("VERSION .7\n")), 0, func(chunk []PointAndData) bool {
+		return true
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+// slamMapSize is about the number of points of a SLAM map.
+const slamMapSize = 2000000
+
+// newSLAMMapPCD returns a binary PCD of a cloud of slamMapSize points.
+func newSLAMMapPCD(b *testing.B) []byte {
+	b.Helper()
+	cloud := NewWithPrealloc(slamMapSize)
+	for x := 0.0; x < 200; x++ {
+		for y := 0.0; y < 100; y++ {
+			for z := 0.0; z < 100; z++ {
+				test.That(b, cloud.Set(NewVector(x*10, y*10, z*10), NewBasicData()), test.ShouldBeNil)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	test.That(b, ToPCD(cloud, &buf, PCDBinary), test.ShouldBeNil)
+	return buf.Bytes()
+}
+
+func BenchmarkSLAMMapReadPCD(b *testing.B) {
+	pcd := newSLAMMapPCD(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cloud, err := ReadPCD(bytes.NewReader(pcd))
+		test.That(b, err, test.ShouldBeNil)
+		var total int
+		cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			total++
+			return true
+		})
+		test.That(b, total, test.ShouldEqual, slamMapSize)
+	}
+}
+
+func BenchmarkSLAMMapStreamPCD(b *testing.B) {
+	pcd := newSLAMMapPCD(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var total int
+		err := StreamPCD(bytes.NewReader(pcd), 0, func(chunk []PointAndData) bool {
+			total += len(chunk)
+			return true
+		})
+		test.That(b, err, test.ShouldBeNil)
+		test.That(b, total, test.ShouldEqual, slamMapSize)
+	}
+}
+
+func BenchmarkSLAMMapIterateChunks(b *testing.B) {
+	cloud, err := ReadPCD(bytes.NewReader(newSLAMMapPCD(b)))
+	test.That(b, err, test.ShouldBeNil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var total int
+		IterateChunks(cloud, 0, func(chunk []PointAndData) bool {
+			total += len(chunk)
+			return true
+		})
+		test.That(b, total, test.ShouldEqual, slamMapSize)
+	}
+}
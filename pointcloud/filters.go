@@ -0,0 +1,212 @@
+package pointcloud
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// A Filter returns a filtered copy of a point cloud. Filters made by StatisticalOutlierFilter,
+// RadiusOutlierFilter, VoxelGridFilter and PassthroughFilter can be chained with ComposeFilters.
+type Filter func(PointCloud) (PointCloud, error)
+
+// ComposeFilters returns a filter that applies the given filters in order.
+func ComposeFilters(filters ...Filter) Filter {
+	return func(pc PointCloud) (PointCloud, error) {
+		var err error
+		for _, filter := range filters {
+			pc, err = filter(pc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return pc, nil
+	}
+}
+
+// RadiusOutlierFilter returns a filter that removes the points that have fewer than minNeighbors
+// other points within radius of them, as isolated points are usually noise.
+// https://pcl.readthedocs.io/projects/tutorials/en/latest/remove_outliers.html
+func RadiusOutlierFilter(radius float64, minNeighbors int) (Filter, error) {
+	if radius <= 0 {
+		return nil, errors.Errorf("argument radius must be a positive float, got %.2f", radius)
+	}
+	if minNeighbors <= 0 {
+		return nil, errors.Errorf("argument minNeighbors must be a positive int, got %d", minNeighbors)
+	}
+	return func(pc PointCloud) (PointCloud, error) {
+		kd, ok := pc.(*KDTree)
+		if !ok {
+			kd = ToKDTree(pc)
+		}
+		filteredCloud := New()
+		var err error
+		kd.Iterate(0, 0, func(v r3.Vector, d Data) bool {
+			if len(kd.RadiusNearestNeighbors(v, radius, false)) < minNeighbors {
+				return true
+			}
+			err = filteredCloud.Set(v, d)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return filteredCloud, nil
+	}, nil
+}
+
+// VoxelGridFilter returns a filter that downsamples point clouds by replacing the points in each
+// cube of the grid of the given side length, in mm, by their centroid. When points are colored,
+// the centroid has their average color; otherwise it has the data of one of them.
+func VoxelGridFilter(voxelSize float64) (Filter, error) {
+	if voxelSize <= 0 {
+		return nil, errors.Errorf("argument voxelSize must be a positive float, got %.2f", voxelSize)
+	}
+	type voxel struct {
+		sum      r3.Vector
+		count    int
+		r, g, b  int
+		colored  int
+		firstPtD Data
+	}
+	return func(pc PointCloud) (PointCloud, error) {
+		voxels := map[[3]int64]*voxel{}
+		pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			key := [3]int64{
+				int64(math.Floor(p.X / voxelSize)),
+				int64(math.Floor(p.Y / voxelSize)),
+				int64(math.Floor(p.Z / voxelSize)),
+			}
+			vox, ok := voxels[key]
+			if !ok {
+				vox = &voxel{firstPtD: d}
+				voxels[key] = vox
+			}
+			vox.sum = vox.sum.Add(p)
+			vox.count++
+			if d != nil && d.HasColor() {
+				r, g, b := d.RGB255()
+				vox.r += int(r)
+				vox.g += int(g)
+				vox.b += int(b)
+				vox.colored++
+			}
+			return true
+		})
+
+		filteredCloud := NewWithPrealloc(len(voxels))
+		for _, vox := range voxels {
+			d := vox.firstPtD
+			if vox.colored > 0 {
+				c := color.NRGBA{
+					uint8(vox.r / vox.colored),
+					uint8(vox.g / vox.colored),
+					uint8(vox.b / vox.colored),
+					255,
+				}
+				d = NewColoredData(c)
+				if vox.firstPtD != nil && vox.firstPtD.HasValue() {
+					d.SetValue(vox.firstPtD.Value())
+				}
+			}
+			if err := filteredCloud.Set(vox.sum.Mul(1/float64(vox.count)), d); err != nil {
+				return nil, err
+			}
+		}
+		return filteredCloud, nil
+	}, nil
+}
+
+// PassthroughFilter returns a filter that keeps the points whose coordinate along an axis, "x",
+// "y" or "z", is between minimum and maximum inclusive, for example to crop a depth range.
+func PassthroughFilter(axis string, minimum, maximum float64) (Filter, error) {
+	var coordinate func(p r3.Vector) float64
+	switch axis {
+	case "x":
+		coordinate = func(p r3.Vector) float64 { return p.X }
+	case "y":
+		coordinate = func(p r3.Vector) float64 { return p.Y }
+	case "z":
+		coordinate = func(p r3.Vector) float64 { return p.Z }
+	default:
+		return nil, errors.Errorf("argument axis must be x, y or z, got %q", axis)
+	}
+	if minimum > maximum {
+		return nil, errors.Errorf("argument minimum %.2f must not be greater than maximum %.2f", minimum, maximum)
+	}
+	return func(pc PointCloud) (PointCloud, error) {
+		filteredCloud := New()
+		var err error
+		pc.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+			if c := coordinate(p); c < minimum || c > maximum {
+				return true
+			}
+			err = filteredCloud.Set(p, d)
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return filteredCloud, nil
+	}, nil
+}
+
+// The types of FilterConfig.
+const (
+	FilterTypeVoxelGrid          = "voxel_grid"
+	FilterTypeStatisticalOutlier = "statistical_outlier"
+	FilterTypeRadiusOutlier      = "radius_outlier"
+	FilterTypePassthrough        = "passthrough"
+)
+
+// FilterConfig describes a filter in the attributes of components and services that filter
+// point clouds, such as camera transform pipelines and segmenters. Only the fields of its type
+// are used.
+type FilterConfig struct {
+	Type string `json:"type"`
+	// VoxelSizeMm is the side length of the cubes of voxel_grid filters.
+	VoxelSizeMm float64 `json:"voxel_size_mm,omitempty"`
+	// MeanK and StdDevThreshold are the number of neighbors to look at and how many standard
+	// deviations away from the mean distance to them is too far, for statistical_outlier filters.
+	MeanK           int     `json:"mean_k,omitempty"`
+	StdDevThreshold float64 `json:"std_dev_threshold,omitempty"`
+	// RadiusMm and MinNeighbors are how many neighbors points need within a radius to be kept by
+	// radius_outlier filters.
+	RadiusMm     float64 `json:"radius_mm,omitempty"`
+	MinNeighbors int     `json:"min_neighbors,omitempty"`
+	// Axis, MinMm and MaxMm are the range of coordinates kept by passthrough filters.
+	Axis  string  `json:"axis,omitempty"`
+	MinMm float64 `json:"min_mm,omitempty"`
+	MaxMm float64 `json:"max_mm,omitempty"`
+}
+
+// Filter returns the filter described by the config.
+func (cfg FilterConfig) Filter() (Filter, error) {
+	switch cfg.Type {
+	case FilterTypeVoxelGrid:
+		return VoxelGridFilter(cfg.VoxelSizeMm)
+	case FilterTypeStatisticalOutlier:
+		return StatisticalOutlierFilter(cfg.MeanK, cfg.StdDevThreshold)
+	case FilterTypeRadiusOutlier:
+		return RadiusOutlierFilter(cfg.RadiusMm, cfg.MinNeighbors)
+	case FilterTypePassthrough:
+		return PassthroughFilter(cfg.Axis, cfg.MinMm, cfg.MaxMm)
+	default:
+		return nil, errors.Errorf("unknown point cloud filter type %q", cfg.Type)
+	}
+}
+
+// FiltersFromConfigs returns a filter applying the filters described by configs in order.
+func FiltersFromConfigs(configs []FilterConfig) (Filter, error) {
+	filters := make([]Filter, 0, len(configs))
+	for i, cfg := range configs {
+		filter, err := cfg.Filter()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid point cloud filter %d", i)
+		}
+		filters = append(filters, filter)
+	}
+	return ComposeFilters(filters...), nil
+}
@@ -0,0 +1,121 @@
+package pointcloud
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestVoxelGridFilter(t *testing.T) {
+	_, err := VoxelGridFilter(0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cloud := New()
+	test.That(t, cloud.Set(NewVector(1, 1, 1), NewColoredData(color.NRGBA{100, 0, 0, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(3, 3, 3), NewColoredData(color.NRGBA{200, 0, 50, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(-1, 1, 1), NewColoredData(color.NRGBA{0, 10, 0, 255})), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(15, 15, 15), NewColoredData(color.NRGBA{0, 0, 0, 255})), test.ShouldBeNil)
+
+	filter, err := VoxelGridFilter(10)
+	test.That(t, err, test.ShouldBeNil)
+	filtered, err := filter(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered.Size(), test.ShouldEqual, 3)
+	data, ok := filtered.At(2, 2, 2)
+	test.That(t, ok, test.ShouldBeTrue)
+	r, g, b := data.RGB255()
+	test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{150, 0, 25})
+	test.That(t, CloudContains(filtered, -1, 1, 1), test.ShouldBeTrue)
+	test.That(t, CloudContains(filtered, 15, 15, 15), test.ShouldBeTrue)
+
+	uncolored := New()
+	for x := 0.; x < 100; x++ {
+		test.That(t, uncolored.Set(NewVector(x, 0, 0), NewBasicData()), test.ShouldBeNil)
+	}
+	filtered, err = filter(uncolored)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered.Size(), test.ShouldEqual, 10)
+	test.That(t, filtered.MetaData().HasColor, test.ShouldBeFalse)
+	test.That(t, CloudContains(filtered, 4.5, 0, 0), test.ShouldBeTrue)
+}
+
+func TestRadiusOutlierFilter(t *testing.T) {
+	_, err := RadiusOutlierFilter(0, 2)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = RadiusOutlierFilter(1, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cloud := New()
+	for x := 0.; x < 10; x++ {
+		test.That(t, cloud.Set(NewVector(x, 0, 0), NewBasicData()), test.ShouldBeNil)
+	}
+	test.That(t, cloud.Set(NewVector(100, 0, 0), NewBasicData()), test.ShouldBeNil)
+	test.That(t, cloud.Set(NewVector(100, 1, 0), NewBasicData()), test.ShouldBeNil)
+
+	filter, err := RadiusOutlierFilter(1.5, 2)
+	test.That(t, err, test.ShouldBeNil)
+	filtered, err := filter(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	// the ends of the line only have one neighbor, and the pair away from it one each
+	test.That(t, filtered.Size(), test.ShouldEqual, 8)
+	test.That(t, CloudContains(filtered, 0, 0, 0), test.ShouldBeFalse)
+	test.That(t, CloudContains(filtered, 5, 0, 0), test.ShouldBeTrue)
+	test.That(t, CloudContains(filtered, 100, 0, 0), test.ShouldBeFalse)
+}
+
+func TestPassthroughFilter(t *testing.T) {
+	_, err := PassthroughFilter("w", 0, 1)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = PassthroughFilter("x", 1, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	cloud := New()
+	for i := 0.; i < 10; i++ {
+		test.That(t, cloud.Set(NewVector(i, 2*i, 3*i), NewBasicData()), test.ShouldBeNil)
+	}
+	for axis, expected := range map[string]int{"x": 4, "y": 2, "z": 2} {
+		filter, err := PassthroughFilter(axis, 3, 6)
+		test.That(t, err, test.ShouldBeNil)
+		filtered, err := filter(cloud)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, filtered.Size(), test.ShouldEqual, expected)
+	}
+}
+
+func TestFiltersFromConfigs(t *testing.T) {
+	cloud := New()
+	for x := 0.; x < 100; x++ {
+		for y := 0.; y < 10; y++ {
+			test.That(t, cloud.Set(NewVector(x, y, 0), NewBasicData()), test.ShouldBeNil)
+		}
+	}
+	test.That(t, cloud.Set(NewVector(50, 500, 0), NewBasicData()), test.ShouldBeNil)
+
+	filter, err := FiltersFromConfigs([]FilterConfig{
+		{Type: FilterTypePassthrough, Axis: "x", MinMm: 0, MaxMm: 50.5},
+		{Type: FilterTypeRadiusOutlier, RadiusMm: 1.5, MinNeighbors: 1},
+		{Type: FilterTypeStatisticalOutlier, MeanK: 4, StdDevThreshold: 3},
+		{Type: FilterTypeVoxelGrid, VoxelSizeMm: 5},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	filtered, err := filter(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered.Size(), test.ShouldEqual, 22)
+	filtered.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		test.That(t, p.X, test.ShouldBeLessThanOrEqualTo, 50)
+		test.That(t, p.Y, test.ShouldBeLessThan, 10)
+		return true
+	})
+
+	identity, err := FiltersFromConfigs(nil)
+	test.That(t, err, test.ShouldBeNil)
+	filtered, err = identity(cloud)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filtered, test.ShouldEqual, cloud)
+
+	_, err = FiltersFromConfigs([]FilterConfig{{Type: FilterTypeVoxelGrid, VoxelSizeMm: 5}, {Type: "bogus"}})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid point cloud filter 1")
+}
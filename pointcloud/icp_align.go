@@ -0,0 +1,298 @@
+package pointcloud
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ICPMethod is the error that ICP alignment minimizes.
+type ICPMethod int
+
+const (
+	// ICPPointToPoint minimizes the distances between source points and their nearest target points.
+	ICPPointToPoint ICPMethod = iota
+	// ICPPointToPlane minimizes the distances between source points and the planes tangent to the
+	// target at their nearest target points. It usually converges in fewer iterations on surfaces.
+	ICPPointToPlane
+)
+
+// Defaults of ICPOptions.
+const (
+	defaultICPMaxIterations   = 50
+	defaultICPTolerance       = 1e-6
+	defaultICPNormalNeighbors = 10
+)
+
+// ICPOptions configure AlignICP.
+type ICPOptions struct {
+	Method ICPMethod
+	// InitialGuess is the pose of the source in the frame of the target to start from. If nil,
+	// the clouds are assumed to be roughly aligned already.
+	InitialGuess spatialmath.Pose
+	// MaxIterations bounds the number of iterations, 50 by default.
+	MaxIterations int
+	// MaxCorrespondenceDistance, in mm, ignores source points farther than it from the target,
+	// which makes alignment robust to clouds that only partly overlap. 0 means no limit.
+	MaxCorrespondenceDistance float64
+	// Tolerance is the change in RMSE, in mm, between iterations below which alignment has
+	// converged, 1e-6 by default.
+	Tolerance float64
+	// NormalNeighbors is the number of target points used to estimate the normals of the target
+	// for ICPPointToPlane, 10 by default.
+	NormalNeighbors int
+}
+
+// ICPResult is the outcome of aligning point clouds with AlignICP.
+type ICPResult struct {
+	// Pose is the pose of the source in the frame of the target: it transforms source points
+	// onto the target.
+	Pose spatialmath.Pose
+	// Iterations is the number of iterations that were run.
+	Iterations int
+	// Converged is whether the RMSE stopped changing before MaxIterations.
+	Converged bool
+	// RMSE is the root mean square distance, in mm, between the transformed source points and
+	// their nearest target points.
+	RMSE float64
+	// Fitness is the fraction of source points that have a target point within
+	// MaxCorrespondenceDistance.
+	Fitness float64
+	// History is the RMSE before each iteration, to diagnose how alignment converged.
+	History []float64
+}
+
+type icpCorrespondence struct {
+	source r3.Vector
+	target r3.Vector
+}
+
+// AlignICP aligns a source point cloud to a target point cloud with ICP (Iterative Closest Point).
+// Each iteration pairs every source point with its nearest target point and moves the source to
+// minimize the error of the method between pairs, in closed form. Unlike RegisterPointCloudICP,
+// it returns the pose that aligns the clouds rather than the aligned cloud.
+func AlignICP(source PointCloud, target *KDTree, opts ICPOptions) (ICPResult, error) {
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = defaultICPMaxIterations
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = defaultICPTolerance
+	}
+	if opts.NormalNeighbors <= 0 {
+		opts.NormalNeighbors = defaultICPNormalNeighbors
+	}
+	if opts.Method != ICPPointToPoint && opts.Method != ICPPointToPlane {
+		return ICPResult{}, errors.Errorf("unknown ICP method %d", opts.Method)
+	}
+	if source.Size() == 0 || target.Size() == 0 {
+		return ICPResult{}, errors.New("cannot align empty point clouds")
+	}
+
+	sourcePoints := make([]r3.Vector, 0, source.Size())
+	source.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		sourcePoints = append(sourcePoints, p)
+		return true
+	})
+	pose := opts.InitialGuess
+	if pose == nil {
+		pose = spatialmath.NewZeroPose()
+	}
+	normals := &targetNormals{target: target, neighbors: opts.NormalNeighbors, normals: map[r3.Vector]r3.Vector{}}
+
+	result := ICPResult{}
+	prevRMSE := math.Inf(1)
+	for result.Iterations < opts.MaxIterations {
+		pairs, rmse := icpCorrespondences(sourcePoints, target, pose, opts.MaxCorrespondenceDistance)
+		if len(pairs) < 3 {
+			return ICPResult{}, errors.Errorf("only %d source points are close enough to the target to align", len(pairs))
+		}
+		result.History = append(result.History, rmse)
+		if math.Abs(prevRMSE-rmse) < opts.Tolerance {
+			result.Converged = true
+			break
+		}
+		prevRMSE = rmse
+
+		var step spatialmath.Pose
+		var err error
+		switch opts.Method {
+		case ICPPointToPlane:
+			step, err = pointToPlaneStep(pairs, normals)
+		default:
+			step, err = pointToPointStep(pairs)
+		}
+		if err != nil {
+			return ICPResult{}, err
+		}
+		pose = spatialmath.Compose(step, pose)
+		result.Iterations++
+	}
+
+	pairs, rmse := icpCorrespondences(sourcePoints, target, pose, opts.MaxCorrespondenceDistance)
+	result.Pose = pose
+	result.RMSE = rmse
+	result.Fitness = float64(len(pairs)) / float64(len(sourcePoints))
+	return result, nil
+}
+
+// icpCorrespondences pairs the source points moved by a pose with their nearest target points,
+// and returns the pairs along with their root mean square distance.
+func icpCorrespondences(
+	sourcePoints []r3.Vector,
+	target *KDTree,
+	pose spatialmath.Pose,
+	maxDistance float64,
+) ([]icpCorrespondence, float64) {
+	pairs := make([]icpCorrespondence, 0, len(sourcePoints))
+	sumSquares := 0.
+	for _, p := range sourcePoints {
+		moved := spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(p)).Point()
+		nearest, _, dist, ok := target.NearestNeighbor(moved)
+		if !ok || (maxDistance > 0 && dist > maxDistance) {
+			continue
+		}
+		pairs = append(pairs, icpCorrespondence{source: moved, target: nearest})
+		sumSquares += dist * dist
+	}
+	if len(pairs) == 0 {
+		return pairs, math.Inf(1)
+	}
+	return pairs, math.Sqrt(sumSquares / float64(len(pairs)))
+}
+
+// pointToPointStep returns the rigid transform that best moves the sources of pairs onto their
+// targets, using the SVD of their cross covariance (the Kabsch algorithm).
+func pointToPointStep(pairs []icpCorrespondence) (spatialmath.Pose, error) {
+	var sourceCentroid, targetCentroid r3.Vector
+	for _, pair := range pairs {
+		sourceCentroid = sourceCentroid.Add(pair.source)
+		targetCentroid = targetCentroid.Add(pair.target)
+	}
+	sourceCentroid = sourceCentroid.Mul(1 / float64(len(pairs)))
+	targetCentroid = targetCentroid.Mul(1 / float64(len(pairs)))
+
+	covariance := mat.NewDense(3, 3, nil)
+	for _, pair := range pairs {
+		s := pair.source.Sub(sourceCentroid)
+		t := pair.target.Sub(targetCentroid)
+		sv, tv := [3]float64{s.X, s.Y, s.Z}, [3]float64{t.X, t.Y, t.Z}
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				covariance.Set(i, j, covariance.At(i, j)+sv[i]*tv[j])
+			}
+		}
+	}
+
+	var svd mat.SVD
+	if !svd.Factorize(covariance, mat.SVDFull) {
+		return nil, errors.New("ICP failed to factorize the covariance of correspondences")
+	}
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	var r mat.Dense
+	r.Mul(&v, u.T())
+	if mat.Det(&r) < 0 {
+		// a reflection, rather than a rotation, fits best; flip the least significant axis
+		for i := 0; i < 3; i++ {
+			v.Set(i, 2, -v.At(i, 2))
+		}
+		r.Mul(&v, u.T())
+	}
+
+	// r moves sources onto targets, while a pose made from a RotationMatrix rotates points by its
+	// transpose, so the pose is built from the transpose of r
+	var rt mat.Dense
+	rt.CloneFrom(r.T())
+	rotation, err := spatialmath.NewRotationMatrix(rt.RawMatrix().Data)
+	if err != nil {
+		return nil, err
+	}
+	rotated := spatialmath.Compose(spatialmath.NewPoseFromOrientation(rotation), spatialmath.NewPoseFromPoint(sourceCentroid)).Point()
+	return spatialmath.NewPose(targetCentroid.Sub(rotated), rotation), nil
+}
+
+// pointToPlaneStep returns the rigid transform that best moves the sources of pairs onto the
+// planes tangent to the target at their targets, linearized for small rotations.
+func pointToPlaneStep(pairs []icpCorrespondence, normals *targetNormals) (spatialmath.Pose, error) {
+	a := mat.NewSymDense(6, nil)
+	b := mat.NewVecDense(6, nil)
+	for _, pair := range pairs {
+		n, err := normals.at(pair.target)
+		if err != nil {
+			return nil, err
+		}
+		c := pair.source.Cross(n)
+		row := [6]float64{c.X, c.Y, c.Z, n.X, n.Y, n.Z}
+		residual := -pair.source.Sub(pair.target).Dot(n)
+		for i := 0; i < 6; i++ {
+			for j := i; j < 6; j++ {
+				a.SetSym(i, j, a.At(i, j)+row[i]*row[j])
+			}
+			b.SetVec(i, b.AtVec(i)+row[i]*residual)
+		}
+	}
+
+	var chol mat.Cholesky
+	if !chol.Factorize(a) {
+		return nil, errors.New("ICP point to plane is degenerate, the target may be a single plane; try point to point")
+	}
+	var x mat.VecDense
+	if err := chol.SolveVecTo(&x, b); err != nil {
+		return nil, err
+	}
+	translation := r3.Vector{X: x.AtVec(3), Y: x.AtVec(4), Z: x.AtVec(5)}
+	rotationVector := r3.Vector{X: x.AtVec(0), Y: x.AtVec(1), Z: x.AtVec(2)}
+	if rotationVector.Norm() == 0 {
+		return spatialmath.NewPoseFromPoint(translation), nil
+	}
+	return spatialmath.NewPose(translation, spatialmath.R3ToR4(rotationVector)), nil
+}
+
+// targetNormals estimates the normals of a target cloud where they are needed, from the
+// covariance of the nearest points.
+type targetNormals struct {
+	target    *KDTree
+	neighbors int
+	normals   map[r3.Vector]r3.Vector
+}
+
+func (tn *targetNormals) at(p r3.Vector) (r3.Vector, error) {
+	if n, ok := tn.normals[p]; ok {
+		return n, nil
+	}
+	neighbors := tn.target.KNearestNeighbors(p, tn.neighbors, true)
+	if len(neighbors) < 3 {
+		return r3.Vector{}, errors.New("ICP point to plane needs at least 3 target points to estimate normals")
+	}
+	var centroid r3.Vector
+	for _, neighbor := range neighbors {
+		centroid = centroid.Add(neighbor.P)
+	}
+	centroid = centroid.Mul(1 / float64(len(neighbors)))
+	covariance := mat.NewSymDense(3, nil)
+	for _, neighbor := range neighbors {
+		d := neighbor.P.Sub(centroid)
+		dv := [3]float64{d.X, d.Y, d.Z}
+		for i := 0; i < 3; i++ {
+			for j := i; j < 3; j++ {
+				covariance.SetSym(i, j, covariance.At(i, j)+dv[i]*dv[j])
+			}
+		}
+	}
+
+	var eigen mat.EigenSym
+	if !eigen.Factorize(covariance, true) {
+		return r3.Vector{}, errors.New("ICP failed to estimate the normal of the target")
+	}
+	var vectors mat.Dense
+	eigen.VectorsTo(&vectors)
+	// eigenvalues are in ascending order, and the normal is the direction of least variance
+	n := r3.Vector{X: vectors.At(0, 0), Y: vectors.At(1, 0), Z: vectors.At(2, 0)}.Normalize()
+	tn.normals[p] = n
+	return n, nil
+}
@@ -0,0 +1,99 @@
+package pointcloud
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// bumpySurface returns a cloud sampling a surface that is curved in both directions, which ICP
+// can align without ambiguity.
+func bumpySurface(t *testing.T, pose spatialmath.Pose) PointCloud {
+	t.Helper()
+	cloud := New()
+	for x := -100.; x <= 100; x += 5 {
+		for y := -100.; y <= 100; y += 5 {
+			p := r3.Vector{X: x, Y: y, Z: 20*math.Sin(x/30)*math.Cos(y/40) + x*y/500}
+			p = spatialmath.Compose(pose, spatialmath.NewPoseFromPoint(p)).Point()
+			test.That(t, cloud.Set(p, NewBasicData()), test.ShouldBeNil)
+		}
+	}
+	return cloud
+}
+
+func TestAlignICP(t *testing.T) {
+	expected := spatialmath.NewPose(
+		r3.Vector{X: 1, Y: -0.8, Z: 0.5},
+		&spatialmath.EulerAngles{Roll: 0.005, Pitch: -0.004, Yaw: 0.01},
+	)
+	target := ToKDTree(bumpySurface(t, spatialmath.NewZeroPose()))
+	source := bumpySurface(t, spatialmath.PoseInverse(expected))
+
+	checkPose := func(t *testing.T, result ICPResult) {
+		t.Helper()
+		delta := spatialmath.PoseDelta(result.Pose, expected)
+		test.That(t, delta.Point().Norm(), test.ShouldBeLessThan, 0.5)
+		test.That(t, delta.Orientation().AxisAngles().Theta, test.ShouldBeLessThan, 1e-3)
+		test.That(t, result.Converged, test.ShouldBeTrue)
+		test.That(t, result.RMSE, test.ShouldBeLessThan, 0.1)
+		test.That(t, result.Fitness, test.ShouldEqual, 1)
+		test.That(t, result.History, test.ShouldHaveLength, result.Iterations+1)
+		test.That(t, result.History[len(result.History)-1], test.ShouldBeLessThan, result.History[0])
+	}
+
+	t.Run("point to point", func(t *testing.T) {
+		result, err := AlignICP(source, target, ICPOptions{Method: ICPPointToPoint})
+		test.That(t, err, test.ShouldBeNil)
+		checkPose(t, result)
+	})
+
+	t.Run("point to plane", func(t *testing.T) {
+		result, err := AlignICP(source, target, ICPOptions{Method: ICPPointToPlane})
+		test.That(t, err, test.ShouldBeNil)
+		checkPose(t, result)
+	})
+
+	t.Run("initial guess", func(t *testing.T) {
+		farExpected := spatialmath.Compose(spatialmath.NewPoseFromPoint(r3.Vector{X: 150}), expected)
+		farSource := bumpySurface(t, spatialmath.PoseInverse(farExpected))
+		guess := spatialmath.NewPoseFromPoint(farExpected.Point())
+		result, err := AlignICP(farSource, target, ICPOptions{InitialGuess: guess})
+		test.That(t, err, test.ShouldBeNil)
+		delta := spatialmath.PoseDelta(result.Pose, farExpected)
+		test.That(t, delta.Point().Norm(), test.ShouldBeLessThan, 0.5)
+	})
+
+	t.Run("max iterations", func(t *testing.T) {
+		result, err := AlignICP(source, target, ICPOptions{MaxIterations: 1})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result.Iterations, test.ShouldEqual, 1)
+		test.That(t, result.Converged, test.ShouldBeFalse)
+		test.That(t, result.RMSE, test.ShouldBeLessThan, result.History[0])
+	})
+
+	t.Run("partial overlap", func(t *testing.T) {
+		withOutliers := bumpySurface(t, spatialmath.PoseInverse(expected))
+		for x := 0.; x < 100; x++ {
+			test.That(t, withOutliers.Set(r3.Vector{X: x, Z: 500}, NewBasicData()), test.ShouldBeNil)
+		}
+		result, err := AlignICP(withOutliers, target, ICPOptions{MaxCorrespondenceDistance: 10})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result.Fitness, test.ShouldAlmostEqual, float64(source.Size())/float64(withOutliers.Size()))
+		test.That(t, result.RMSE, test.ShouldBeLessThan, 0.1)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := AlignICP(New(), target, ICPOptions{})
+		test.That(t, err, test.ShouldNotBeNil)
+		_, err = AlignICP(source, target, ICPOptions{Method: ICPMethod(7)})
+		test.That(t, err, test.ShouldNotBeNil)
+		far := bumpySurface(t, spatialmath.NewPoseFromPoint(r3.Vector{Z: 1000}))
+		_, err = AlignICP(far, target, ICPOptions{MaxCorrespondenceDistance: 10})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "close enough")
+	})
+}
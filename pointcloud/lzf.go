@@ -0,0 +1,118 @@
+package pointcloud
+
+import (
+	"github.com/pkg/errors"
+)
+
+// LZF is the compression of binary_compressed PCD files. Compressed data is a sequence of
+// literal runs and back references, each starting with a control byte: values below 32 are
+// followed by that many plus one literal bytes; other values hold in their top 3 bits the length
+// of a back reference minus 2 (7 meaning that the next byte adds to it) and in their low 5 bits
+// the high bits of its offset minus 1, whose low bits are in the following byte.
+const (
+	lzfMaxLiteral = 1 << 5
+	lzfMaxOffset  = 1 << 13
+	lzfMaxMatch   = 7 + 255 + 2
+	lzfHashBits   = 14
+)
+
+func lzfHash(in []byte, i int) int {
+	v := uint32(in[i])<<16 | uint32(in[i+1])<<8 | uint32(in[i+2])
+	return int((v * 2654435761) >> (32 - lzfHashBits))
+}
+
+// lzfCompress compresses data with LZF. It always succeeds, at worst growing the data by one
+// byte every 32.
+func lzfCompress(in []byte) []byte {
+	out := make([]byte, 0, len(in)+len(in)/lzfMaxLiteral+1)
+	appendLiterals := func(literals []byte) {
+		for len(literals) > 0 {
+			n := len(literals)
+			if n > lzfMaxLiteral {
+				n = lzfMaxLiteral
+			}
+			out = append(out, byte(n-1))
+			out = append(out, literals[:n]...)
+			literals = literals[n:]
+		}
+	}
+
+	// positions plus one of the last sequences of 3 bytes with each hash
+	var table [1 << lzfHashBits]int
+	literalStart := 0
+	for i := 0; i+2 < len(in); {
+		h := lzfHash(in, i)
+		ref := table[h] - 1
+		table[h] = i + 1
+		if ref < 0 || i-ref > lzfMaxOffset || in[ref] != in[i] || in[ref+1] != in[i+1] || in[ref+2] != in[i+2] {
+			i++
+			continue
+		}
+
+		n := 3
+		for n < lzfMaxMatch && i+n < len(in) && in[ref+n] == in[i+n] {
+			n++
+		}
+		appendLiterals(in[literalStart:i])
+		offset, length := i-ref-1, n-2
+		if length < 7 {
+			out = append(out, byte(length<<5|offset>>8), byte(offset))
+		} else {
+			out = append(out, byte(7<<5|offset>>8), byte(length-7), byte(offset))
+		}
+		i += n
+		literalStart = i
+	}
+	appendLiterals(in[literalStart:])
+	return out
+}
+
+// lzfDecompress decompresses LZF data that is expected to decompress to exactly outLen bytes.
+func lzfDecompress(in []byte, outLen int) ([]byte, error) {
+	out := make([]byte, 0, outLen)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+		if ctrl < lzfMaxLiteral {
+			n := ctrl + 1
+			if i+n > len(in) {
+				return nil, errors.New("invalid LZF data: literal run past the end of the data")
+			}
+			if len(out)+n > outLen {
+				return nil, errors.Errorf("invalid LZF data: decompresses to more than %d bytes", outLen)
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+
+		n := ctrl >> 5
+		if n == 7 {
+			if i >= len(in) {
+				return nil, errors.New("invalid LZF data: back reference past the end of the data")
+			}
+			n += int(in[i])
+			i++
+		}
+		n += 2
+		if i >= len(in) {
+			return nil, errors.New("invalid LZF data: back reference past the end of the data")
+		}
+		ref := len(out) - (ctrl&0x1f)<<8 - int(in[i]) - 1
+		i++
+		if ref < 0 {
+			return nil, errors.New("invalid LZF data: back reference before the start of the data")
+		}
+		if len(out)+n > outLen {
+			return nil, errors.Errorf("invalid LZF data: decompresses to more than %d bytes", outLen)
+		}
+		// references may overlap what they produce, so they are copied byte by byte
+		for j := 0; j < n; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if len(out) != outLen {
+		return nil, errors.Errorf("invalid LZF data: decompresses to %d bytes instead of %d", len(out), outLen)
+	}
+	return out, nil
+}
@@ -0,0 +1,43 @@
+package pointcloud
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestLZF(t *testing.T) {
+	//nolint:gosec
+	r := rand.New(rand.NewSource(1))
+	random := make([]byte, 10000)
+	r.Read(random)
+
+	for _, data := range [][]byte{
+		{},
+		[]byte("a"),
+		[]byte("abc"),
+		bytes.Repeat([]byte("a"), 1000),
+		bytes.Repeat([]byte("point cloud "), 1000),
+		random,
+		append(bytes.Repeat([]byte{0}, 20000), random...),
+	} {
+		compressed := lzfCompress(data)
+		test.That(t, len(compressed), test.ShouldBeLessThanOrEqualTo, len(data)+len(data)/32+1)
+		decompressed, err := lzfDecompress(compressed, len(data))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, decompressed, test.ShouldResemble, data)
+	}
+
+	compressed := lzfCompress(bytes.Repeat([]byte("a"), 1000))
+	test.That(t, len(compressed), test.ShouldBeLessThan, 20)
+	_, err := lzfDecompress(compressed, 999)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = lzfDecompress(compressed, 1001)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = lzfDecompress(compressed[:len(compressed)-1], 1000)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = lzfDecompress([]byte{1 << 5, 0}, 3)
+	test.That(t, err, test.ShouldNotBeNil)
+}
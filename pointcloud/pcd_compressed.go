@@ -0,0 +1,127 @@
+package pointcloud
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// binary_compressed PCD data is the size of the compressed data and the size of the data once
+// decompressed, as little endian uint32s, followed by the LZF compressed data. Unlike binary data,
+// it is laid out by field rather than by point: all the x values, then all the y values, and so on.
+
+// maxPCDCompressedDataSize bounds the size of decompressed binary_compressed PCD data, so that
+// a corrupt or hostile header cannot make reading allocate without limit.
+const maxPCDCompressedDataSize = 1 << 30
+
+// readPCDCompressed calls fn with every point of binary_compressed PCD data following a header.
+func readPCDCompressed(in *bufio.Reader, header pcdHeader, fn func(pd PointAndData) error) error {
+	var sizes [8]byte
+	if _, err := io.ReadFull(in, sizes[:]); err != nil {
+		return errors.Wrap(err, "error reading compressed pcd sizes")
+	}
+	compressedSize := binary.LittleEndian.Uint32(sizes[:4])
+	uncompressedSize := binary.LittleEndian.Uint32(sizes[4:])
+
+	if uncompressedSize > maxPCDCompressedDataSize {
+		return errors.Errorf("compressed pcd data of %d bytes is larger than the maximum of %d", uncompressedSize, maxPCDCompressedDataSize)
+	}
+	// LZF grows data by at most one byte every 32
+	if maxCompressedSize := uncompressedSize + uncompressedSize/lzfMaxLiteral + 1; compressedSize > maxCompressedSize {
+		return errors.Errorf("compressed pcd data of %d bytes cannot decompress to %d bytes", compressedSize, uncompressedSize)
+	}
+
+	for _, size := range header.size {
+		if size != 4 {
+			return errors.Errorf("unsupported compressed pcd field size %d", size)
+		}
+	}
+	if header.points > maxPCDCompressedDataSize/uint64(4*len(header.size)) {
+		return errors.Errorf("compressed pcd data of %d points is larger than the maximum of %d bytes", header.points, maxPCDCompressedDataSize)
+	}
+	numPoints := int(header.points)
+	fieldStarts := make([]int, len(header.size))
+	expectedSize := 0
+	for i, size := range header.size {
+		fieldStarts[i] = expectedSize
+		expectedSize += int(size) * numPoints
+	}
+	if int(uncompressedSize) != expectedSize {
+		return errors.Errorf("compressed pcd data is %d bytes but %d points need %d", uncompressedSize, numPoints, expectedSize)
+	}
+
+	// read through a limit so that truncated data fails without first allocating its claimed size
+	compressed, err := io.ReadAll(io.LimitReader(in, int64(compressedSize)))
+	if err != nil {
+		return errors.Wrap(err, "error reading compressed pcd data")
+	}
+	if len(compressed) != int(compressedSize) {
+		return errors.Wrap(io.ErrUnexpectedEOF, "error reading compressed pcd data")
+	}
+	data, err := lzfDecompress(compressed, int(uncompressedSize))
+	if err != nil {
+		return err
+	}
+
+	field := func(field, point int) uint32 {
+		start := fieldStarts[field] + 4*point
+		return binary.LittleEndian.Uint32(data[start : start+4])
+	}
+	for i := 0; i < numPoints; i++ {
+		// Converts PCD units (meters) to millimeters for RDK
+		point := r3.Vector{
+			X: 1000. * readFloat(field(0, i)),
+			Y: 1000. * readFloat(field(1, i)),
+			Z: 1000. * readFloat(field(2, i)),
+		}
+		d := NewBasicData()
+		if header.fields == pcdPointColor {
+			d = NewColoredData(_pcdIntToColor(int(field(3, i))))
+		}
+		if err := fn(PointAndData{P: point, D: d}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePCDCompressed writes the points of a cloud as binary_compressed PCD data.
+func writePCDCompressed(cloud PointCloud, out io.Writer) error {
+	numPoints := cloud.Size()
+	hasColor := cloud.MetaData().HasColor
+	numFields := int(pcdPointOnly)
+	if hasColor {
+		numFields = int(pcdPointColor)
+	}
+
+	data := make([]byte, 4*numFields*numPoints)
+	putField := func(field, point int, v uint32) {
+		binary.LittleEndian.PutUint32(data[4*(field*numPoints+point):], v)
+	}
+	i := 0
+	cloud.Iterate(0, 0, func(pos r3.Vector, d Data) bool {
+		// Converts RDK units (millimeters) to meters for PCD
+		putField(0, i, math.Float32bits(float32(pos.X/1000.)))
+		putField(1, i, math.Float32bits(float32(pos.Y/1000.)))
+		putField(2, i, math.Float32bits(float32(pos.Z/1000.)))
+		if hasColor {
+			putField(3, i, uint32(_colorToPCDInt(d)))
+		}
+		i++
+		return i < numPoints
+	})
+
+	compressed := lzfCompress(data)
+	var sizes [8]byte
+	binary.LittleEndian.PutUint32(sizes[:4], uint32(len(compressed)))
+	binary.LittleEndian.PutUint32(sizes[4:], uint32(len(data)))
+	if _, err := out.Write(sizes[:]); err != nil {
+		return err
+	}
+	_, err := out.Write(compressed)
+	return err
+}
@@ -0,0 +1,327 @@
+package pointcloud
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// PLYType is the format of a ply file.
+type PLYType int
+
+const (
+	// PLYAscii ascii format for ply.
+	PLYAscii PLYType = 0
+	// PLYBinary little endian binary format for ply.
+	PLYBinary PLYType = 1
+)
+
+// plyTypeSizes are the sizes in bytes of the scalar types of ply properties, by all their names.
+var plyTypeSizes = map[string]int{
+	"char": 1, "int8": 1, "uchar": 1, "uint8": 1,
+	"short": 2, "int16": 2, "ushort": 2, "uint16": 2,
+	"int": 4, "int32": 4, "uint": 4, "uint32": 4,
+	"float": 4, "float32": 4, "double": 8, "float64": 8,
+}
+
+// plyColorChannels are the indexes of the color properties of vertices in an RGB triple.
+var plyColorChannels = map[string]int{"red": 0, "green": 1, "blue": 2}
+
+type plyProperty struct {
+	name string
+	typ  string
+	// countType is the type of the length of list properties, and empty for scalar properties.
+	countType string
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+type plyHeader struct {
+	format   string
+	elements []plyElement
+}
+
+// ToPLY writes out a point cloud to a ply file of the specified type. Like in PCD files, positions
+// are written in meters.
+func ToPLY(cloud PointCloud, out io.Writer, outputType PLYType) error {
+	format := "ascii"
+	if outputType == PLYBinary {
+		format = "binary_little_endian"
+	} else if outputType != PLYAscii {
+		return errors.Errorf("unsupported ply type %d", outputType)
+	}
+	hasColor := cloud.MetaData().HasColor
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "ply\nformat %s 1.0\nelement vertex %d\n", format, cloud.Size())
+	header.WriteString("property float x\nproperty float y\nproperty float z\n")
+	if hasColor {
+		header.WriteString("property uchar red\nproperty uchar green\nproperty uchar blue\n")
+	}
+	header.WriteString("end_header\n")
+	w := bufio.NewWriter(out)
+	_, err := w.WriteString(header.String())
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 15)
+	cloud.Iterate(0, 0, func(pos r3.Vector, d Data) bool {
+		// Converts RDK units (millimeters) to meters
+		x, y, z := float32(pos.X/1000.), float32(pos.Y/1000.), float32(pos.Z/1000.)
+		var r, g, b uint8 = 255, 255, 255
+		if d != nil && d.HasColor() {
+			r, g, b = d.RGB255()
+		}
+		switch outputType {
+		case PLYBinary:
+			binary.LittleEndian.PutUint32(buf, math.Float32bits(x))
+			binary.LittleEndian.PutUint32(buf[4:], math.Float32bits(y))
+			binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(z))
+			n := 12
+			if hasColor {
+				buf[12], buf[13], buf[14] = r, g, b
+				n = 15
+			}
+			_, err = w.Write(buf[:n])
+		case PLYAscii:
+			if hasColor {
+				_, err = fmt.Fprintf(w, "%f %f %f %d %d %d\n", x, y, z, r, g, b)
+			} else {
+				_, err = fmt.Fprintf(w, "%f %f %f\n", x, y, z)
+			}
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// ReadPLY reads a ply file into a pointcloud. Ascii, little endian and big endian binary files
+// are supported. Only the positions and colors of vertices are read; other elements, such as the
+// faces of meshes, and other properties are skipped.
+func ReadPLY(inRaw io.Reader) (PointCloud, error) {
+	in := bufio.NewReader(inRaw)
+	header, err := readPLYHeader(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var values plyValueReader
+	switch header.format {
+	case "ascii":
+		scanner := bufio.NewScanner(in)
+		scanner.Split(bufio.ScanWords)
+		values = &plyASCIIReader{scanner: scanner}
+	case "binary_little_endian":
+		values = &plyBinaryReader{in: in, order: binary.LittleEndian}
+	case "binary_big_endian":
+		values = &plyBinaryReader{in: in, order: binary.BigEndian}
+	default:
+		return nil, errors.Errorf("unsupported ply format %q", header.format)
+	}
+
+	pc := New()
+	for _, element := range header.elements {
+		if element.name == "vertex" {
+			if err := readPLYVertices(values, element, pc); err != nil {
+				return nil, err
+			}
+			// whatever follows the vertices does not matter
+			return pc, nil
+		}
+		for i := 0; i < element.count; i++ {
+			for _, prop := range element.properties {
+				if _, err := readPLYProperty(values, prop); err != nil {
+					return nil, errors.Wrapf(err, "error reading ply element %s", element.name)
+				}
+			}
+		}
+	}
+	return pc, nil
+}
+
+func readPLYHeader(in *bufio.Reader) (plyHeader, error) {
+	header := plyHeader{}
+	magic, err := in.ReadString('\n')
+	if err != nil {
+		return plyHeader{}, errors.Wrap(err, "error reading ply header")
+	}
+	if strings.TrimSpace(magic) != "ply" {
+		return plyHeader{}, errors.New("not a ply file")
+	}
+	for {
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return plyHeader{}, errors.Wrap(err, "error reading ply header")
+		}
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch tokens[0] {
+		case "end_header":
+			if header.format == "" {
+				return plyHeader{}, errors.New("ply header has no format")
+			}
+			return header, nil
+		case "comment", "obj_info":
+		case "format":
+			if len(tokens) != 3 {
+				return plyHeader{}, errors.Errorf("invalid ply format line %q", line)
+			}
+			header.format = tokens[1]
+		case "element":
+			if len(tokens) != 3 {
+				return plyHeader{}, errors.Errorf("invalid ply element line %q", line)
+			}
+			count, err := strconv.Atoi(tokens[2])
+			if err != nil || count < 0 {
+				return plyHeader{}, errors.Errorf("invalid ply element count %q", tokens[2])
+			}
+			header.elements = append(header.elements, plyElement{name: tokens[1], count: count})
+		case "property":
+			if len(header.elements) == 0 {
+				return plyHeader{}, errors.Errorf("ply property %q is not part of an element", line)
+			}
+			var prop plyProperty
+			switch {
+			case len(tokens) == 5 && tokens[1] == "list":
+				prop = plyProperty{name: tokens[4], typ: tokens[3], countType: tokens[2]}
+			case len(tokens) == 3:
+				prop = plyProperty{name: tokens[2], typ: tokens[1]}
+			default:
+				return plyHeader{}, errors.Errorf("invalid ply property line %q", line)
+			}
+			for _, typ := range []string{prop.typ, prop.countType} {
+				if _, ok := plyTypeSizes[typ]; typ != "" && !ok {
+					return plyHeader{}, errors.Errorf("unsupported ply property type %q", typ)
+				}
+			}
+			element := &header.elements[len(header.elements)-1]
+			element.properties = append(element.properties, prop)
+		default:
+			return plyHeader{}, errors.Errorf("unexpected ply header line %q", line)
+		}
+	}
+}
+
+func readPLYVertices(values plyValueReader, element plyElement, pc PointCloud) error {
+	for i := 0; i < element.count; i++ {
+		var pos [3]float64
+		var rgb [3]float64
+		hasColor := false
+		for _, prop := range element.properties {
+			v, err := readPLYProperty(values, prop)
+			if err != nil {
+				return errors.Wrapf(err, "error reading ply vertex %d", i)
+			}
+			switch prop.name {
+			case "x":
+				pos[0] = v
+			case "y":
+				pos[1] = v
+			case "z":
+				pos[2] = v
+			case "red", "green", "blue":
+				hasColor = true
+				if prop.typ == "float" || prop.typ == "float32" || prop.typ == "double" || prop.typ == "float64" {
+					v *= 255
+				}
+				rgb[plyColorChannels[prop.name]] = math.Max(0, math.Min(v, 255))
+			}
+		}
+		d := NewBasicData()
+		if hasColor {
+			d = NewColoredData(color.NRGBA{uint8(rgb[0]), uint8(rgb[1]), uint8(rgb[2]), 255})
+		}
+		// Converts meters to millimeters for RDK
+		if err := pc.Set(r3.Vector{X: 1000. * pos[0], Y: 1000. * pos[1], Z: 1000. * pos[2]}, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPLYProperty reads a property of an element, returning its value if it is not a list.
+func readPLYProperty(values plyValueReader, prop plyProperty) (float64, error) {
+	if prop.countType == "" {
+		return values.read(prop.typ)
+	}
+	count, err := values.read(prop.countType)
+	if err != nil {
+		return 0, err
+	}
+	for j := 0; j < int(count); j++ {
+		if _, err := values.read(prop.typ); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// plyValueReader reads the values of the elements of a ply file.
+type plyValueReader interface {
+	read(typ string) (float64, error)
+}
+
+type plyASCIIReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *plyASCIIReader) read(typ string) (float64, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.ErrUnexpectedEOF
+	}
+	return strconv.ParseFloat(r.scanner.Text(), 64)
+}
+
+type plyBinaryReader struct {
+	in    io.Reader
+	order binary.ByteOrder
+	buf   [8]byte
+}
+
+func (r *plyBinaryReader) read(typ string) (float64, error) {
+	buf := r.buf[:plyTypeSizes[typ]]
+	if _, err := io.ReadFull(r.in, buf); err != nil {
+		return 0, err
+	}
+	switch typ {
+	case "char", "int8":
+		return float64(int8(buf[0])), nil
+	case "uchar", "uint8":
+		return float64(buf[0]), nil
+	case "short", "int16":
+		return float64(int16(r.order.Uint16(buf))), nil
+	case "ushort", "uint16":
+		return float64(r.order.Uint16(buf)), nil
+	case "int", "int32":
+		return float64(int32(r.order.Uint32(buf))), nil
+	case "uint", "uint32":
+		return float64(r.order.Uint32(buf)), nil
+	case "float", "float32":
+		return readFloat(r.order.Uint32(buf)), nil
+	case "double", "float64":
+		return math.Float64frombits(r.order.Uint64(buf)), nil
+	default:
+		return 0, errors.Errorf("unsupported ply property type %q", typ)
+	}
+}
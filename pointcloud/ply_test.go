@@ -0,0 +1,141 @@
+package pointcloud
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"math"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestPLYRoundTrip(t *testing.T) {
+	colored := New()
+	test.That(t, colored.Set(NewVector(-1, -2, 5), NewColoredData(color.NRGBA{255, 1, 2, 255})), test.ShouldBeNil)
+	test.That(t, colored.Set(NewVector(582, 12, 0), NewColoredData(color.NRGBA{3, 4, 5, 255})), test.ShouldBeNil)
+	test.That(t, colored.Set(NewVector(7, 6, 1), NewColoredData(color.NRGBA{255, 1, 2, 255})), test.ShouldBeNil)
+
+	uncolored := New()
+	test.That(t, uncolored.Set(NewVector(-1, -2, 5), NewBasicData()), test.ShouldBeNil)
+	test.That(t, uncolored.Set(NewVector(582, 12, 0), NewBasicData()), test.ShouldBeNil)
+	test.That(t, uncolored.Set(NewVector(7, 6, 1), NewBasicData()), test.ShouldBeNil)
+
+	for _, plyType := range []PLYType{PLYAscii, PLYBinary} {
+		for _, cloud := range []PointCloud{colored, uncolored} {
+			var buf bytes.Buffer
+			test.That(t, ToPLY(cloud, &buf, plyType), test.ShouldBeNil)
+			test.That(t, buf.String(), test.ShouldContainSubstring, "element vertex 3\n")
+			if plyType == PLYAscii {
+				test.That(t, buf.String(), test.ShouldContainSubstring, "format ascii 1.0\n")
+				test.That(t, buf.String(), test.ShouldContainSubstring, "-0.001000 -0.002000 0.005000")
+			} else {
+				test.That(t, buf.String(), test.ShouldContainSubstring, "format binary_little_endian 1.0\n")
+			}
+
+			cloud2, err := ReadPLY(&buf)
+			test.That(t, err, test.ShouldBeNil)
+			testPCDOutput(t, cloud2)
+			data, ok := cloud2.At(582, 12, 0)
+			test.That(t, ok, test.ShouldBeTrue)
+			test.That(t, data.HasColor(), test.ShouldEqual, cloud.MetaData().HasColor)
+			if data.HasColor() {
+				r, g, b := data.RGB255()
+				test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{3, 4, 5})
+			}
+		}
+	}
+
+	test.That(t, ToPLY(colored, &bytes.Buffer{}, PLYType(5)), test.ShouldNotBeNil)
+}
+
+func TestReadPLY(t *testing.T) {
+	t.Run("big endian mesh", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.WriteString("ply\n" +
+			"format binary_big_endian 1.0\n" +
+			"comment made by hand\n" +
+			"element material 1\n" +
+			"property list uchar int ids\n" +
+			"element vertex 2\n" +
+			"property double x\n" +
+			"property double y\n" +
+			"property double z\n" +
+			"property float nx\n" +
+			"property float red\n" +
+			"property float green\n" +
+			"property float blue\n" +
+			"element face 1\n" +
+			"property list uchar int vertex_indices\n" +
+			"end_header\n")
+		// material with 2 ids
+		buf.WriteByte(2)
+		test.That(t, binary.Write(&buf, binary.BigEndian, []int32{7, 8}), test.ShouldBeNil)
+		for _, v := range [][]float64{{0.001, 0.002, 0.003}, {-0.5, 0, 1.25}} {
+			test.That(t, binary.Write(&buf, binary.BigEndian, v), test.ShouldBeNil)
+			test.That(t, binary.Write(&buf, binary.BigEndian, []float32{1, 1, 0.5, 0}), test.ShouldBeNil)
+		}
+		// face, which is skipped
+		buf.WriteByte(2)
+		test.That(t, binary.Write(&buf, binary.BigEndian, []int32{0, 1}), test.ShouldBeNil)
+
+		cloud, err := ReadPLY(&buf)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cloud.Size(), test.ShouldEqual, 2)
+		data, ok := cloud.At(1, 2, 3)
+		test.That(t, ok, test.ShouldBeTrue)
+		r, g, b := data.RGB255()
+		test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{255, 127, 0})
+		test.That(t, CloudContains(cloud, -500, 0, 1250), test.ShouldBeTrue)
+	})
+
+	t.Run("ascii", func(t *testing.T) {
+		cloud, err := ReadPLY(strings.NewReader("ply\n" +
+			"format ascii 1.0\n" +
+			"element vertex 2\n" +
+			"property float x\n" +
+			"property float y\n" +
+			"property float z\n" +
+			"property uchar intensity\n" +
+			"end_header\n" +
+			"0.001 0.002 0.003 9\n" +
+			"0.004 0.005 0.006 10\n"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cloud.Size(), test.ShouldEqual, 2)
+		test.That(t, cloud.MetaData().HasColor, test.ShouldBeFalse)
+		test.That(t, cloud.MetaData().MaxZ, test.ShouldAlmostEqual, 6)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, ply := range []string{
+			"",
+			"pcd\n",
+			"ply\nelement vertex 1\nend_header\n",
+			"ply\nformat ascii 1.0\nproperty float x\nend_header\n",
+			"ply\nformat ascii 1.0\nelement vertex 1\nproperty quad x\nend_header\n",
+			"ply\nformat ascii 1.0\nelement vertex -1\nend_header\n",
+			"ply\nformat ascii 1.0\nbogus\nend_header\n",
+			"ply\nformat ascii 1.0\nelement vertex 2\nproperty float x\nend_header\n1\n",
+			"ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nend_header\nnot_a_number\n",
+			"ply\nformat binary_little_endian 1.0\nelement vertex 1\nproperty double x\nend_header\n1234",
+			"ply\nformat binary_middle_endian 1.0\nend_header\n",
+		} {
+			_, err := ReadPLY(strings.NewReader(ply))
+			test.That(t, err, test.ShouldNotBeNil)
+		}
+	})
+}
+
+func TestPLYBinarySmallerThanASCII(t *testing.T) {
+	cloud := newBigPC()
+	var asciiBuf, binaryBuf bytes.Buffer
+	test.That(t, ToPLY(cloud, &asciiBuf, PLYAscii), test.ShouldBeNil)
+	test.That(t, ToPLY(cloud, &binaryBuf, PLYBinary), test.ShouldBeNil)
+	test.That(t, binaryBuf.Len(), test.ShouldBeLessThan, asciiBuf.Len())
+
+	cloud2, err := ReadPLY(&binaryBuf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cloud2.Size(), test.ShouldEqual, cloud.Size())
+	test.That(t, math.Abs(cloud2.MetaData().MaxX-cloud.MetaData().MaxX), test.ShouldBeLessThan, 1e-3)
+}
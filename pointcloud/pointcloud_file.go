@@ -8,6 +8,7 @@ import (
 	"image/color"
 	"io"
 	"math"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -35,16 +36,37 @@ const (
 	PCDCompressed PCDType = 2
 )
 
-// NewFromFile returns a pointcloud read in from the given file.
+// ErrLAZUnsupported is returned when reading a LAZ file. LAZ import is out of scope as there is no
+// pure Go LAZ decoder; decompress such files to LAS first, for example with laszip.
+var ErrLAZUnsupported = errors.New("LAZ files are not supported, decompress them to LAS first")
+
+// NewFromFile returns a pointcloud read in from the given LAS, PCD or PLY file.
 func NewFromFile(fn string, logger golog.Logger) (PointCloud, error) {
 	switch filepath.Ext(fn) {
 	case ".las":
 		return NewFromLASFile(fn, logger)
+	case ".laz":
+		return nil, errors.Wrapf(ErrLAZUnsupported, "cannot read %q", fn)
+	case ".pcd":
+		return newFromReaderFile(fn, ReadPCD)
+	case ".ply":
+		return newFromReaderFile(fn, ReadPLY)
 	default:
 		return nil, errors.Errorf("do not know how to read file %q", fn)
 	}
 }
 
+// newFromReaderFile reads a point cloud from a file with a function reading from readers.
+func newFromReaderFile(fn string, read func(in io.Reader) (PointCloud, error)) (PointCloud, error) {
+	//nolint:gosec
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	return read(f)
+}
+
 // pointValueDataTag encodes if the point has value data.
 const pointValueDataTag = "rc|pv"
 
@@ -285,11 +307,11 @@ func ToPCD(cloud PointCloud, out io.Writer, outputType PCDType) error {
 			return err
 		}
 	case PCDCompressed:
-		// _, err = fmt.Fprintf(out, "DATA binary_compressed\n")
-		// if err != nil {
-		// 	return err
-		// }
-		return errors.New("compressed PCD not yet implemented")
+		_, err = fmt.Fprintf(out, "DATA binary_compressed\n")
+		if err != nil {
+			return err
+		}
+		return writePCDCompressed(cloud, out)
 	}
 	err = writePCDData(cloud, out, outputType)
 	if err != nil {
@@ -516,28 +538,33 @@ func ReadPCDToBasicOctree(inRaw io.Reader) (*BasicOctree, error) {
 	return basicOct, nil
 }
 
-func readPCDHelper(inRaw io.Reader, pctype PCType) (PointCloud, error) {
+func readPCDHeader(in *bufio.Reader) (pcdHeader, error) {
 	header := pcdHeader{}
-	in := bufio.NewReader(inRaw)
-	var line string
-	var err error
 	headerLineCount := 0
 	for headerLineCount < len(pcdHeaderFields) {
-		line, err = in.ReadString('\n')
+		line, err := in.ReadString('\n')
 		if err != nil {
-			return nil, fmt.Errorf("error reading header line %d: %w", headerLineCount, err)
+			return pcdHeader{}, fmt.Errorf("error reading header line %d: %w", headerLineCount, err)
 		}
 		line, _, _ = strings.Cut(line, pcdCommentChar)
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
-		err := parsePCDHeaderLine(line, headerLineCount, &header)
-		if err != nil {
-			return nil, err
+		if err := parsePCDHeaderLine(line, headerLineCount, &header); err != nil {
+			return pcdHeader{}, err
 		}
 		headerLineCount++
 	}
+	return header, nil
+}
+
+func readPCDHelper(inRaw io.Reader, pctype PCType) (PointCloud, error) {
+	in := bufio.NewReader(inRaw)
+	header, err := readPCDHeader(in)
+	if err != nil {
+		return nil, err
+	}
 	var pc PointCloud
 	switch pctype {
 	case BasicType:
@@ -557,16 +584,25 @@ func readPCDHelper(inRaw io.Reader, pctype PCType) (PointCloud, error) {
 	default:
 		return nil, fmt.Errorf("unsupported point cloud type %d", pctype)
 	}
+	if err := readPCDPoints(in, header, func(pd PointAndData) error {
+		return pc.Set(pd.P, pd.D)
+	}); err != nil {
+		return nil, err
+	}
+	return pc, nil
+}
+
+// readPCDPoints calls fn with every point of the PCD data following a header, one at a time.
+func readPCDPoints(in *bufio.Reader, header pcdHeader, fn func(pd PointAndData) error) error {
 	switch header.data {
 	case PCDAscii:
-		return readPCDASCII(in, header, pc)
+		return readPCDASCII(in, header, fn)
 	case PCDBinary:
-		return readPCDBinary(in, header, pc)
+		return readPCDBinary(in, header, fn)
 	case PCDCompressed:
-		// return readPCDCompressed(in, header)
-		return nil, errors.New("compressed pcd not yet supported")
+		return readPCDCompressed(in, header, fn)
 	default:
-		return nil, fmt.Errorf("unsupported pcd data type %v", header.data)
+		return fmt.Errorf("unsupported pcd data type %v", header.data)
 	}
 }
 
@@ -595,18 +631,17 @@ func extractPCDPointASCII(in *bufio.Reader, header pcdHeader, i int) (PointAndDa
 	return PointAndData{P: pcPoint, D: data}, nil
 }
 
-func readPCDASCII(in *bufio.Reader, header pcdHeader, pc PointCloud) (PointCloud, error) {
+func readPCDASCII(in *bufio.Reader, header pcdHeader, fn func(pd PointAndData) error) error {
 	for i := 0; i < int(header.points); i++ {
 		pd, err := extractPCDPointASCII(in, header, i)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		err = pc.Set(pd.P, pd.D)
-		if err != nil {
-			return nil, err
+		if err := fn(pd); err != nil {
+			return err
 		}
 	}
-	return pc, nil
+	return nil
 }
 
 func extractPCDPointBinary(in *bufio.Reader, header pcdHeader) (PointAndData, error) {
@@ -639,21 +674,20 @@ func extractPCDPointBinary(in *bufio.Reader, header pcdHeader) (PointAndData, er
 	return PointAndData{P: point, D: colorData}, nil
 }
 
-func readPCDBinary(in *bufio.Reader, header pcdHeader, pc PointCloud) (PointCloud, error) {
+func readPCDBinary(in *bufio.Reader, header pcdHeader, fn func(pd PointAndData) error) error {
 	for i := 0; i < int(header.points); i++ {
 		pd, err := extractPCDPointBinary(in, header)
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
-			return nil, err
+			return err
 		}
-		err = pc.Set(pd.P, pd.D)
-		if err != nil {
-			return nil, err
+		if err := fn(pd); err != nil {
+			return err
 		}
 	}
-	return pc, nil
+	return nil
 }
 
 func getPCDMetaData(in bufio.Reader, header pcdHeader) (MetaData, error) {
@@ -677,8 +711,12 @@ func getPCDMetaData(in bufio.Reader, header pcdHeader) (MetaData, error) {
 			meta.Merge(pd.P, pd.D)
 		}
 	case PCDCompressed:
-		// return readPCDCompressed(in, header)
-		return MetaData{}, errors.New("compressed pcd not yet supported")
+		if err := readPCDCompressed(&in, header, func(pd PointAndData) error {
+			meta.Merge(pd.P, pd.D)
+			return nil
+		}); err != nil {
+			return MetaData{}, err
+		}
 	default:
 		return MetaData{}, fmt.Errorf("unsupported pcd data type %v", header.data)
 	}
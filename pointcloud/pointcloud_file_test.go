@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"encoding/binary"
 	"image/color"
+	"io"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
 	"go.viam.com/test"
 	"go.viam.com/utils/artifact"
 )
@@ -39,6 +42,24 @@ func TestNewFromFile(t *testing.T) {
 	nextCloud, err := NewFromFile(temp.Name(), logger)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, nextCloud, test.ShouldResemble, cloud)
+
+	dir := t.TempDir()
+	smallCloud := New()
+	test.That(t, smallCloud.Set(NewVector(-1, -2, 5), NewBasicData()), test.ShouldBeNil)
+	var buf bytes.Buffer
+	test.That(t, ToPCD(smallCloud, &buf, PCDCompressed), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "cloud.pcd"), buf.Bytes(), 0o600), test.ShouldBeNil)
+	buf.Reset()
+	test.That(t, ToPLY(smallCloud, &buf, PLYBinary), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "cloud.ply"), buf.Bytes(), 0o600), test.ShouldBeNil)
+	for _, name := range []string{"cloud.pcd", "cloud.ply"} {
+		nextCloud, err := NewFromFile(filepath.Join(dir, name), logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, CloudContains(nextCloud, -1, -2, 5), test.ShouldBeTrue)
+	}
+
+	_, err = NewFromFile(filepath.Join(dir, "cloud.laz"), logger)
+	test.That(t, errors.Is(err, ErrLAZUnsupported), test.ShouldBeTrue)
 }
 
 func TestPCD(t *testing.T) {
@@ -66,6 +87,7 @@ func TestPCD(t *testing.T) {
 	testPCDHeaders(t)
 	testASCIIRoundTrip(t, cloud)
 	testBinaryRoundTrip(t, cloud)
+	testCompressedRoundTrip(t, cloud)
 }
 
 func testPCDHeaders(t *testing.T) {
@@ -158,6 +180,7 @@ func TestPCDNoColor(t *testing.T) {
 
 	testNoColorASCIIRoundTrip(t, cloud)
 	testNoColorBinaryRoundTrip(t, cloud)
+	testCompressedRoundTrip(t, cloud)
 	testLargeBinaryNoError(t)
 }
 
@@ -254,6 +277,42 @@ func testBinaryRoundTrip(t *testing.T, cloud PointCloud) {
 	test.That(t, b, test.ShouldEqual, 2)
 }
 
+func testCompressedRoundTrip(t *testing.T, cloud PointCloud) {
+	t.Helper()
+	var buf bytes.Buffer
+	err := ToPCD(cloud, &buf, PCDCompressed)
+	test.That(t, err, test.ShouldBeNil)
+	gotPCD := buf.String()
+	test.That(t, gotPCD, test.ShouldContainSubstring, "POINTS 3\n")
+	test.That(t, gotPCD, test.ShouldContainSubstring, "DATA binary_compressed\n")
+
+	cloud2, err := ReadPCD(strings.NewReader(gotPCD))
+	test.That(t, err, test.ShouldBeNil)
+	testPCDOutput(t, cloud2)
+	data, dataFlag := cloud2.At(-1, -2, 5)
+	test.That(t, dataFlag, test.ShouldBeTrue)
+	test.That(t, data.HasColor(), test.ShouldEqual, cloud.MetaData().HasColor)
+	if data.HasColor() {
+		r, g, b := data.RGB255()
+		test.That(t, []uint8{r, g, b}, test.ShouldResemble, []uint8{255, 1, 2})
+	}
+
+	octree, err := ReadPCDToBasicOctree(strings.NewReader(gotPCD))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, octree.Size(), test.ShouldEqual, 3)
+
+	largeCloud := newBigPC()
+	buf.Reset()
+	test.That(t, ToPCD(largeCloud, &buf, PCDCompressed), test.ShouldBeNil)
+	var binaryBuf bytes.Buffer
+	test.That(t, ToPCD(largeCloud, &binaryBuf, PCDBinary), test.ShouldBeNil)
+	test.That(t, buf.Len(), test.ShouldBeLessThan, binaryBuf.Len())
+	readPointCloud, err := ReadPCD(&buf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readPointCloud.Size(), test.ShouldEqual, largeCloud.Size())
+	test.That(t, CloudContains(readPointCloud, 10, 20, 30), test.ShouldBeTrue)
+}
+
 func testLargeBinaryNoError(t *testing.T) {
 	// This tests whether large pointclouds that exceed the usual buffered page size for a file error on reads
 	t.Helper()
@@ -295,6 +354,24 @@ func TestRoundTripFileWithColorFloat(t *testing.T) {
 	nextCloud, err := NewFromFile(temp.Name(), logger)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, nextCloud, test.ShouldResemble, cloud)
+
+	dir := t.TempDir()
+	smallCloud := New()
+	test.That(t, smallCloud.Set(NewVector(-1, -2, 5), NewBasicData()), test.ShouldBeNil)
+	var buf bytes.Buffer
+	test.That(t, ToPCD(smallCloud, &buf, PCDCompressed), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "cloud.pcd"), buf.Bytes(), 0o600), test.ShouldBeNil)
+	buf.Reset()
+	test.That(t, ToPLY(smallCloud, &buf, PLYBinary), test.ShouldBeNil)
+	test.That(t, os.WriteFile(filepath.Join(dir, "cloud.ply"), buf.Bytes(), 0o600), test.ShouldBeNil)
+	for _, name := range []string{"cloud.pcd", "cloud.ply"} {
+		nextCloud, err := NewFromFile(filepath.Join(dir, name), logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, CloudContains(nextCloud, -1, -2, 5), test.ShouldBeTrue)
+	}
+
+	_, err = NewFromFile(filepath.Join(dir, "cloud.laz"), logger)
+	test.That(t, errors.Is(err, ErrLAZUnsupported), test.ShouldBeTrue)
 }
 
 func createNewPCD(t *testing.T) string {
@@ -348,6 +425,35 @@ func TestPCDColor(t *testing.T) {
 	test.That(t, c, test.ShouldResemble, c2)
 }
 
+func TestPCDCompressedSizes(t *testing.T) {
+	cloud := New()
+	test.That(t, cloud.Set(NewVector(-1, -2, 5), NewBasicData()), test.ShouldBeNil)
+	var buf bytes.Buffer
+	test.That(t, ToPCD(cloud, &buf, PCDCompressed), test.ShouldBeNil)
+	good := buf.Bytes()
+	sizesStart := bytes.Index(good, []byte("DATA binary_compressed\n")) + len("DATA binary_compressed\n")
+
+	withSizes := func(compressedSize, uncompressedSize uint32) []byte {
+		data := append([]byte{}, good...)
+		binary.LittleEndian.PutUint32(data[sizesStart:], compressedSize)
+		binary.LittleEndian.PutUint32(data[sizesStart+4:], uncompressedSize)
+		return data
+	}
+	uncompressedSize := binary.LittleEndian.Uint32(good[sizesStart+4:])
+
+	_, err := ReadPCD(bytes.NewReader(withSizes(math.MaxUint32, uncompressedSize)))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot decompress")
+
+	_, err = ReadPCD(bytes.NewReader(withSizes(math.MaxUint32, math.MaxUint32)))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "larger than the maximum")
+
+	_, err = ReadPCD(bytes.NewReader(good[:len(good)-1]))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, errors.Is(err, io.ErrUnexpectedEOF), test.ShouldBeTrue)
+}
+
 func newBigPC() PointCloud {
 	cloud := New()
 	for x := 10.0; x <= 50; x++ {
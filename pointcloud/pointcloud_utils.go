@@ -40,6 +40,17 @@ func BoundingBoxFromPointCloudWithLabel(cloud PointCloud, label string) (spatial
 	return spatialmath.NewBox(spatialmath.NewPoseFromPoint(mean), dims, label)
 }
 
+// VoxelGridFromPointCloud returns a Geometry made up of the cubes with the given side length which are occupied by the points of the given
+// point cloud, aligned to its axes, so that the point cloud can be used as an obstacle.
+func VoxelGridFromPointCloud(cloud PointCloud, voxelSize float64, label string) (spatialmath.Geometry, error) {
+	points := make([]r3.Vector, 0, cloud.Size())
+	cloud.Iterate(0, 0, func(p r3.Vector, d Data) bool {
+		points = append(points, p)
+		return true
+	})
+	return spatialmath.NewVoxelGrid(spatialmath.NewZeroPose(), points, voxelSize, label)
+}
+
 // PrunePointClouds removes point clouds from a slice if the point cloud has less than nMin points.
 func PrunePointClouds(clouds []PointCloud, nMin int) []PointCloud {
 	pruned := make([]PointCloud, 0, len(clouds))
@@ -55,7 +66,7 @@ func PrunePointClouds(clouds []PointCloud, nMin int) []PointCloud {
 // https://pcl.readthedocs.io/projects/tutorials/en/latest/statistical_outlier.html
 // This returns a function that can be used to filter on point clouds.
 // NOTE(bh): Returns a new point cloud, but could be modified to filter and change the original point cloud.
-func StatisticalOutlierFilter(meanK int, stdDevThresh float64) (func(PointCloud) (PointCloud, error), error) {
+func StatisticalOutlierFilter(meanK int, stdDevThresh float64) (Filter, error) {
 	if meanK <= 0 {
 		return nil, errors.Errorf("argument meanK must be a positive int, got %d", meanK)
 	}
@@ -59,6 +59,27 @@ func TestBoundingBoxFromPointCloud(t *testing.T) {
 	}
 }
 
+func TestVoxelGridFromPointCloud(t *testing.T) {
+	clouds := makeClouds(t)
+	grid, err := VoxelGridFromPointCloud(clouds[1], 1, "cloud")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Label(), test.ShouldEqual, "cloud")
+	test.That(t, grid.ToPoints(0), test.ShouldHaveLength, 5)
+
+	// the voxel grid collides where the points are, and nowhere else
+	probe, err := spatialmath.NewSphere(spatialmath.NewPoseFromPoint(r3.Vector{X: 28.5, Y: 0.5, Z: 0.5}), 0.1, "")
+	test.That(t, err, test.ShouldBeNil)
+	collides, err := grid.CollidesWith(probe)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+	distance, err := probe.Transform(spatialmath.NewPoseFromPoint(r3.Vector{X: 1})).DistanceFrom(grid)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, distance, test.ShouldAlmostEqual, 0.4)
+
+	_, err = VoxelGridFromPointCloud(New(), 1, "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
 func TestPrune(t *testing.T) {
 	clouds := makeClouds(t)
 	// before prune
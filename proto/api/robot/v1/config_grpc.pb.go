@@ -0,0 +1,149 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: proto/api/robot/v1/config.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RobotConfigServiceClient is the client API for RobotConfigService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RobotConfigServiceClient interface {
+	// ValidateConfig validates a robot config without applying it and responds with every problem
+	// found, so that tooling can check a config before deploying it.
+	ValidateConfig(ctx context.Context, in *ValidateConfigRequest, opts ...grpc.CallOption) (*ValidateConfigResponse, error)
+	// PlanReconfiguration reports what reconfiguring the robot with a config would do, without
+	// doing it, along with every problem with the config. A config with problems is not planned for.
+	PlanReconfiguration(ctx context.Context, in *PlanReconfigurationRequest, opts ...grpc.CallOption) (*PlanReconfigurationResponse, error)
+}
+
+type robotConfigServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRobotConfigServiceClient(cc grpc.ClientConnInterface) RobotConfigServiceClient {
+	return &robotConfigServiceClient{cc}
+}
+
+func (c *robotConfigServiceClient) ValidateConfig(ctx context.Context, in *ValidateConfigRequest, opts ...grpc.CallOption) (*ValidateConfigResponse, error) {
+	out := new(ValidateConfigResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotConfigService/ValidateConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotConfigServiceClient) PlanReconfiguration(ctx context.Context, in *PlanReconfigurationRequest, opts ...grpc.CallOption) (*PlanReconfigurationResponse, error) {
+	out := new(PlanReconfigurationResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotConfigService/PlanReconfiguration", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RobotConfigServiceServer is the server API for RobotConfigService service.
+// All implementations must embed UnimplementedRobotConfigServiceServer
+// for forward compatibility
+type RobotConfigServiceServer interface {
+	// ValidateConfig validates a robot config without applying it and responds with every problem
+	// found, so that tooling can check a config before deploying it.
+	ValidateConfig(context.Context, *ValidateConfigRequest) (*ValidateConfigResponse, error)
+	// PlanReconfiguration reports what reconfiguring the robot with a config would do, without
+	// doing it, along with every problem with the config. A config with problems is not planned for.
+	PlanReconfiguration(context.Context, *PlanReconfigurationRequest) (*PlanReconfigurationResponse, error)
+	mustEmbedUnimplementedRobotConfigServiceServer()
+}
+
+// UnimplementedRobotConfigServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRobotConfigServiceServer struct {
+}
+
+func (UnimplementedRobotConfigServiceServer) ValidateConfig(context.Context, *ValidateConfigRequest) (*ValidateConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateConfig not implemented")
+}
+func (UnimplementedRobotConfigServiceServer) PlanReconfiguration(context.Context, *PlanReconfigurationRequest) (*PlanReconfigurationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PlanReconfiguration not implemented")
+}
+func (UnimplementedRobotConfigServiceServer) mustEmbedUnimplementedRobotConfigServiceServer() {}
+
+// UnsafeRobotConfigServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RobotConfigServiceServer will
+// result in compilation errors.
+type UnsafeRobotConfigServiceServer interface {
+	mustEmbedUnimplementedRobotConfigServiceServer()
+}
+
+func RegisterRobotConfigServiceServer(s grpc.ServiceRegistrar, srv RobotConfigServiceServer) {
+	s.RegisterService(&RobotConfigService_ServiceDesc, srv)
+}
+
+func _RobotConfigService_ValidateConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotConfigServiceServer).ValidateConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotConfigService/ValidateConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotConfigServiceServer).ValidateConfig(ctx, req.(*ValidateConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotConfigService_PlanReconfiguration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PlanReconfigurationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotConfigServiceServer).PlanReconfiguration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotConfigService/PlanReconfiguration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotConfigServiceServer).PlanReconfiguration(ctx, req.(*PlanReconfigurationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RobotConfigService_ServiceDesc is the grpc.ServiceDesc for RobotConfigService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RobotConfigService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.api.robot.v1.RobotConfigService",
+	HandlerType: (*RobotConfigServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateConfig",
+			Handler:    _RobotConfigService_ValidateConfig_Handler,
+		},
+		{
+			MethodName: "PlanReconfiguration",
+			Handler:    _RobotConfigService_PlanReconfiguration_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/api/robot/v1/config.proto",
+}
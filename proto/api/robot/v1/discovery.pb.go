@@ -0,0 +1,871 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        (unknown)
+// source: proto/api/robot/v1/discovery.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetConfigTemplatesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetConfigTemplatesRequest) Reset() {
+	*x = GetConfigTemplatesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigTemplatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigTemplatesRequest) ProtoMessage() {}
+
+func (x *GetConfigTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{0}
+}
+
+type GetConfigTemplatesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Templates are ordered by subtype and model.
+	Templates []*ConfigTemplate `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+}
+
+func (x *GetConfigTemplatesResponse) Reset() {
+	*x = GetConfigTemplatesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetConfigTemplatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigTemplatesResponse) ProtoMessage() {}
+
+func (x *GetConfigTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*GetConfigTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetConfigTemplatesResponse) GetTemplates() []*ConfigTemplate {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+// ConfigTemplate describes the attributes a model is configured with.
+type ConfigTemplate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subtype string `protobuf:"bytes,1,opt,name=subtype,proto3" json:"subtype,omitempty"`
+	Model   string `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// Required attributes come first.
+	Attributes []*AttributeTemplate `protobuf:"bytes,3,rep,name=attributes,proto3" json:"attributes,omitempty"`
+}
+
+func (x *ConfigTemplate) Reset() {
+	*x = ConfigTemplate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigTemplate) ProtoMessage() {}
+
+func (x *ConfigTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigTemplate.ProtoReflect.Descriptor instead.
+func (*ConfigTemplate) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ConfigTemplate) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *ConfigTemplate) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ConfigTemplate) GetAttributes() []*AttributeTemplate {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+// AttributeTemplate describes one attribute of a model's config.
+type AttributeTemplate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// One of "string", "number", "bool", "list" or "object".
+	Type     string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Required bool   `protobuf:"varint,3,opt,name=required,proto3" json:"required,omitempty"`
+	// The value used when the attribute is not set, if it is not the zero value of its type.
+	Default *structpb.Value `protobuf:"bytes,4,opt,name=default,proto3" json:"default,omitempty"`
+}
+
+func (x *AttributeTemplate) Reset() {
+	*x = AttributeTemplate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AttributeTemplate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttributeTemplate) ProtoMessage() {}
+
+func (x *AttributeTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttributeTemplate.ProtoReflect.Descriptor instead.
+func (*AttributeTemplate) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AttributeTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AttributeTemplate) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AttributeTemplate) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *AttributeTemplate) GetDefault() *structpb.Value {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
+type DetectHardwareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Reading from I2C devices can change their state, so the I2C buses of the robot's boards are
+	// only scanned if set, and then only at the addresses registered models expect devices on.
+	ScanI2C bool `protobuf:"varint,1,opt,name=scan_i2c,json=scanI2c,proto3" json:"scan_i2c,omitempty"`
+}
+
+func (x *DetectHardwareRequest) Reset() {
+	*x = DetectHardwareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DetectHardwareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectHardwareRequest) ProtoMessage() {}
+
+func (x *DetectHardwareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectHardwareRequest.ProtoReflect.Descriptor instead.
+func (*DetectHardwareRequest) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DetectHardwareRequest) GetScanI2C() bool {
+	if x != nil {
+		return x.ScanI2C
+	}
+	return false
+}
+
+type DetectHardwareResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UsbDevices []*USBDevice      `protobuf:"bytes,1,rep,name=usb_devices,json=usbDevices,proto3" json:"usb_devices,omitempty"`
+	I2CDevices []*I2CDevice      `protobuf:"bytes,2,rep,name=i2c_devices,json=i2cDevices,proto3" json:"i2c_devices,omitempty"`
+	Proposals  []*ConfigProposal `protobuf:"bytes,3,rep,name=proposals,proto3" json:"proposals,omitempty"`
+}
+
+func (x *DetectHardwareResponse) Reset() {
+	*x = DetectHardwareResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DetectHardwareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetectHardwareResponse) ProtoMessage() {}
+
+func (x *DetectHardwareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetectHardwareResponse.ProtoReflect.Descriptor instead.
+func (*DetectHardwareResponse) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DetectHardwareResponse) GetUsbDevices() []*USBDevice {
+	if x != nil {
+		return x.UsbDevices
+	}
+	return nil
+}
+
+func (x *DetectHardwareResponse) GetI2CDevices() []*I2CDevice {
+	if x != nil {
+		return x.I2CDevices
+	}
+	return nil
+}
+
+func (x *DetectHardwareResponse) GetProposals() []*ConfigProposal {
+	if x != nil {
+		return x.Proposals
+	}
+	return nil
+}
+
+// USBDevice is a device plugged into a USB port of the robot's machine.
+type USBDevice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VendorId     uint32 `protobuf:"varint,1,opt,name=vendor_id,json=vendorId,proto3" json:"vendor_id,omitempty"`
+	ProductId    uint32 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Manufacturer string `protobuf:"bytes,3,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	Product      string `protobuf:"bytes,4,opt,name=product,proto3" json:"product,omitempty"`
+	Serial       string `protobuf:"bytes,5,opt,name=serial,proto3" json:"serial,omitempty"`
+	// The serial ports of the device, such as /dev/ttyUSB0.
+	Ttys []string `protobuf:"bytes,6,rep,name=ttys,proto3" json:"ttys,omitempty"`
+}
+
+func (x *USBDevice) Reset() {
+	*x = USBDevice{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *USBDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*USBDevice) ProtoMessage() {}
+
+func (x *USBDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use USBDevice.ProtoReflect.Descriptor instead.
+func (*USBDevice) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *USBDevice) GetVendorId() uint32 {
+	if x != nil {
+		return x.VendorId
+	}
+	return 0
+}
+
+func (x *USBDevice) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *USBDevice) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *USBDevice) GetProduct() string {
+	if x != nil {
+		return x.Product
+	}
+	return ""
+}
+
+func (x *USBDevice) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *USBDevice) GetTtys() []string {
+	if x != nil {
+		return x.Ttys
+	}
+	return nil
+}
+
+// I2CDevice is an address that responded on the I2C bus of a board.
+type I2CDevice struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Board   string `protobuf:"bytes,1,opt,name=board,proto3" json:"board,omitempty"`
+	Bus     string `protobuf:"bytes,2,opt,name=bus,proto3" json:"bus,omitempty"`
+	Address uint32 `protobuf:"varint,3,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *I2CDevice) Reset() {
+	*x = I2CDevice{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *I2CDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*I2CDevice) ProtoMessage() {}
+
+func (x *I2CDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use I2CDevice.ProtoReflect.Descriptor instead.
+func (*I2CDevice) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *I2CDevice) GetBoard() string {
+	if x != nil {
+		return x.Board
+	}
+	return ""
+}
+
+func (x *I2CDevice) GetBus() string {
+	if x != nil {
+		return x.Bus
+	}
+	return ""
+}
+
+func (x *I2CDevice) GetAddress() uint32 {
+	if x != nil {
+		return x.Address
+	}
+	return 0
+}
+
+// ConfigProposal is a component config proposed for detected hardware.
+type ConfigProposal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Subtype    string           `protobuf:"bytes,1,opt,name=subtype,proto3" json:"subtype,omitempty"`
+	Model      string           `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Name       string           `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Attributes *structpb.Struct `protobuf:"bytes,4,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	// The hardware the proposal was made for.
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ConfigProposal) Reset() {
+	*x = ConfigProposal{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ConfigProposal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigProposal) ProtoMessage() {}
+
+func (x *ConfigProposal) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_api_robot_v1_discovery_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigProposal.ProtoReflect.Descriptor instead.
+func (*ConfigProposal) Descriptor() ([]byte, []int) {
+	return file_proto_api_robot_v1_discovery_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ConfigProposal) GetSubtype() string {
+	if x != nil {
+		return x.Subtype
+	}
+	return ""
+}
+
+func (x *ConfigProposal) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ConfigProposal) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ConfigProposal) GetAttributes() *structpb.Struct {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ConfigProposal) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_proto_api_robot_v1_discovery_proto protoreflect.FileDescriptor
+
+var file_proto_api_robot_v1_discovery_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x72, 0x6f, 0x62, 0x6f,
+	0x74, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1b, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x5e, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x40, 0x0a, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61,
+	0x74, 0x65, 0x73, 0x22, 0x87, 0x01, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x65,
+	0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x75, 0x62, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x45, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e,
+	0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x22, 0x89, 0x01,
+	0x0a, 0x11, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72,
+	0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65,
+	0x52, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x22, 0x32, 0x0a, 0x15, 0x44, 0x65, 0x74,
+	0x65, 0x63, 0x74, 0x48, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69, 0x32, 0x63, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x63, 0x61, 0x6e, 0x49, 0x32, 0x63, 0x22, 0xda, 0x01,
+	0x0a, 0x16, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x48, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0b, 0x75, 0x73, 0x62, 0x5f,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x53, 0x42, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x0a, 0x75, 0x73,
+	0x62, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0b, 0x69, 0x32, 0x63, 0x5f,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e,
+	0x76, 0x31, 0x2e, 0x49, 0x32, 0x43, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x0a, 0x69, 0x32,
+	0x63, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x70,
+	0x6f, 0x73, 0x61, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x52,
+	0x09, 0x70, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c, 0x73, 0x22, 0xb1, 0x01, 0x0a, 0x09, 0x55,
+	0x53, 0x42, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x76, 0x65, 0x6e, 0x64,
+	0x6f, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x76, 0x65, 0x6e,
+	0x64, 0x6f, 0x72, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74,
+	0x75, 0x72, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x61, 0x6e, 0x75,
+	0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x64,
+	0x75, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75,
+	0x63, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x74,
+	0x79, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x74, 0x79, 0x73, 0x22, 0x4d,
+	0x0a, 0x09, 0x49, 0x32, 0x43, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x62,
+	0x6f, 0x61, 0x72, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x6f, 0x61, 0x72,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x62, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0xa5, 0x01,
+	0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x73, 0x61, 0x6c,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x73, 0x75, 0x62, 0x74, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x32, 0xf5, 0x01, 0x0a, 0x15, 0x52, 0x6f, 0x62, 0x6f, 0x74, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x73, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x54, 0x65, 0x6d, 0x70,
+	0x6c, 0x61, 0x74, 0x65, 0x73, 0x12, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x67, 0x0a, 0x0e, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x48, 0x61,
+	0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x12, 0x29, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x72, 0x6f, 0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x74, 0x65,
+	0x63, 0x74, 0x48, 0x61, 0x72, 0x64, 0x77, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x72, 0x6f,
+	0x62, 0x6f, 0x74, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x48, 0x61, 0x72,
+	0x64, 0x77, 0x61, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x24, 0x5a,
+	0x22, 0x67, 0x6f, 0x2e, 0x76, 0x69, 0x61, 0x6d, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x64, 0x6b,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x72, 0x6f, 0x62, 0x6f, 0x74,
+	0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_proto_api_robot_v1_discovery_proto_rawDescOnce sync.Once
+	file_proto_api_robot_v1_discovery_proto_rawDescData = file_proto_api_robot_v1_discovery_proto_rawDesc
+)
+
+func file_proto_api_robot_v1_discovery_proto_rawDescGZIP() []byte {
+	file_proto_api_robot_v1_discovery_proto_rawDescOnce.Do(func() {
+		file_proto_api_robot_v1_discovery_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_api_robot_v1_discovery_proto_rawDescData)
+	})
+	return file_proto_api_robot_v1_discovery_proto_rawDescData
+}
+
+var file_proto_api_robot_v1_discovery_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_api_robot_v1_discovery_proto_goTypes = []interface{}{
+	(*GetConfigTemplatesRequest)(nil),  // 0: proto.api.robot.v1.GetConfigTemplatesRequest
+	(*GetConfigTemplatesResponse)(nil), // 1: proto.api.robot.v1.GetConfigTemplatesResponse
+	(*ConfigTemplate)(nil),             // 2: proto.api.robot.v1.ConfigTemplate
+	(*AttributeTemplate)(nil),          // 3: proto.api.robot.v1.AttributeTemplate
+	(*DetectHardwareRequest)(nil),      // 4: proto.api.robot.v1.DetectHardwareRequest
+	(*DetectHardwareResponse)(nil),     // 5: proto.api.robot.v1.DetectHardwareResponse
+	(*USBDevice)(nil),                  // 6: proto.api.robot.v1.USBDevice
+	(*I2CDevice)(nil),                  // 7: proto.api.robot.v1.I2CDevice
+	(*ConfigProposal)(nil),             // 8: proto.api.robot.v1.ConfigProposal
+	(*structpb.Value)(nil),             // 9: google.protobuf.Value
+	(*structpb.Struct)(nil),            // 10: google.protobuf.Struct
+}
+var file_proto_api_robot_v1_discovery_proto_depIdxs = []int32{
+	2,  // 0: proto.api.robot.v1.GetConfigTemplatesResponse.templates:type_name -> proto.api.robot.v1.ConfigTemplate
+	3,  // 1: proto.api.robot.v1.ConfigTemplate.attributes:type_name -> proto.api.robot.v1.AttributeTemplate
+	9,  // 2: proto.api.robot.v1.AttributeTemplate.default:type_name -> google.protobuf.Value
+	6,  // 3: proto.api.robot.v1.DetectHardwareResponse.usb_devices:type_name -> proto.api.robot.v1.USBDevice
+	7,  // 4: proto.api.robot.v1.DetectHardwareResponse.i2c_devices:type_name -> proto.api.robot.v1.I2CDevice
+	8,  // 5: proto.api.robot.v1.DetectHardwareResponse.proposals:type_name -> proto.api.robot.v1.ConfigProposal
+	10, // 6: proto.api.robot.v1.ConfigProposal.attributes:type_name -> google.protobuf.Struct
+	0,  // 7: proto.api.robot.v1.RobotDiscoveryService.GetConfigTemplates:input_type -> proto.api.robot.v1.GetConfigTemplatesRequest
+	4,  // 8: proto.api.robot.v1.RobotDiscoveryService.DetectHardware:input_type -> proto.api.robot.v1.DetectHardwareRequest
+	1,  // 9: proto.api.robot.v1.RobotDiscoveryService.GetConfigTemplates:output_type -> proto.api.robot.v1.GetConfigTemplatesResponse
+	5,  // 10: proto.api.robot.v1.RobotDiscoveryService.DetectHardware:output_type -> proto.api.robot.v1.DetectHardwareResponse
+	9,  // [9:11] is the sub-list for method output_type
+	7,  // [7:9] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_proto_api_robot_v1_discovery_proto_init() }
+func file_proto_api_robot_v1_discovery_proto_init() {
+	if File_proto_api_robot_v1_discovery_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_proto_api_robot_v1_discovery_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigTemplatesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetConfigTemplatesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigTemplate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AttributeTemplate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DetectHardwareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DetectHardwareResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*USBDevice); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*I2CDevice); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_api_robot_v1_discovery_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ConfigProposal); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_proto_api_robot_v1_discovery_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_api_robot_v1_discovery_proto_goTypes,
+		DependencyIndexes: file_proto_api_robot_v1_discovery_proto_depIdxs,
+		MessageInfos:      file_proto_api_robot_v1_discovery_proto_msgTypes,
+	}.Build()
+	File_proto_api_robot_v1_discovery_proto = out.File
+	file_proto_api_robot_v1_discovery_proto_rawDesc = nil
+	file_proto_api_robot_v1_discovery_proto_goTypes = nil
+	file_proto_api_robot_v1_discovery_proto_depIdxs = nil
+}
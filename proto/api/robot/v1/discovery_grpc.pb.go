@@ -0,0 +1,149 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: proto/api/robot/v1/discovery.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RobotDiscoveryServiceClient is the client API for RobotDiscoveryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RobotDiscoveryServiceClient interface {
+	// GetConfigTemplates returns the attributes every registered component and service model is
+	// configured with.
+	GetConfigTemplates(ctx context.Context, in *GetConfigTemplatesRequest, opts ...grpc.CallOption) (*GetConfigTemplatesResponse, error)
+	// DetectHardware returns the hardware attached to the robot along with the component configs
+	// registered models propose for it.
+	DetectHardware(ctx context.Context, in *DetectHardwareRequest, opts ...grpc.CallOption) (*DetectHardwareResponse, error)
+}
+
+type robotDiscoveryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRobotDiscoveryServiceClient(cc grpc.ClientConnInterface) RobotDiscoveryServiceClient {
+	return &robotDiscoveryServiceClient{cc}
+}
+
+func (c *robotDiscoveryServiceClient) GetConfigTemplates(ctx context.Context, in *GetConfigTemplatesRequest, opts ...grpc.CallOption) (*GetConfigTemplatesResponse, error) {
+	out := new(GetConfigTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotDiscoveryService/GetConfigTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotDiscoveryServiceClient) DetectHardware(ctx context.Context, in *DetectHardwareRequest, opts ...grpc.CallOption) (*DetectHardwareResponse, error) {
+	out := new(DetectHardwareResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotDiscoveryService/DetectHardware", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RobotDiscoveryServiceServer is the server API for RobotDiscoveryService service.
+// All implementations must embed UnimplementedRobotDiscoveryServiceServer
+// for forward compatibility
+type RobotDiscoveryServiceServer interface {
+	// GetConfigTemplates returns the attributes every registered component and service model is
+	// configured with.
+	GetConfigTemplates(context.Context, *GetConfigTemplatesRequest) (*GetConfigTemplatesResponse, error)
+	// DetectHardware returns the hardware attached to the robot along with the component configs
+	// registered models propose for it.
+	DetectHardware(context.Context, *DetectHardwareRequest) (*DetectHardwareResponse, error)
+	mustEmbedUnimplementedRobotDiscoveryServiceServer()
+}
+
+// UnimplementedRobotDiscoveryServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRobotDiscoveryServiceServer struct {
+}
+
+func (UnimplementedRobotDiscoveryServiceServer) GetConfigTemplates(context.Context, *GetConfigTemplatesRequest) (*GetConfigTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfigTemplates not implemented")
+}
+func (UnimplementedRobotDiscoveryServiceServer) DetectHardware(context.Context, *DetectHardwareRequest) (*DetectHardwareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetectHardware not implemented")
+}
+func (UnimplementedRobotDiscoveryServiceServer) mustEmbedUnimplementedRobotDiscoveryServiceServer() {}
+
+// UnsafeRobotDiscoveryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RobotDiscoveryServiceServer will
+// result in compilation errors.
+type UnsafeRobotDiscoveryServiceServer interface {
+	mustEmbedUnimplementedRobotDiscoveryServiceServer()
+}
+
+func RegisterRobotDiscoveryServiceServer(s grpc.ServiceRegistrar, srv RobotDiscoveryServiceServer) {
+	s.RegisterService(&RobotDiscoveryService_ServiceDesc, srv)
+}
+
+func _RobotDiscoveryService_GetConfigTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotDiscoveryServiceServer).GetConfigTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotDiscoveryService/GetConfigTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotDiscoveryServiceServer).GetConfigTemplates(ctx, req.(*GetConfigTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotDiscoveryService_DetectHardware_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectHardwareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotDiscoveryServiceServer).DetectHardware(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotDiscoveryService/DetectHardware",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotDiscoveryServiceServer).DetectHardware(ctx, req.(*DetectHardwareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RobotDiscoveryService_ServiceDesc is the grpc.ServiceDesc for RobotDiscoveryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RobotDiscoveryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.api.robot.v1.RobotDiscoveryService",
+	HandlerType: (*RobotDiscoveryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetConfigTemplates",
+			Handler:    _RobotDiscoveryService_GetConfigTemplates_Handler,
+		},
+		{
+			MethodName: "DetectHardware",
+			Handler:    _RobotDiscoveryService_DetectHardware_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/api/robot/v1/discovery.proto",
+}
@@ -0,0 +1,190 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: proto/api/robot/v1/introspection.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RobotIntrospectionServiceClient is the client API for RobotIntrospectionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RobotIntrospectionServiceClient interface {
+	// GetResourceGraph returns the dependency graph between the resources of the robot, along with
+	// the part of the config each resource came from and the state it is in.
+	GetResourceGraph(ctx context.Context, in *GetResourceGraphRequest, opts ...grpc.CallOption) (*GetResourceGraphResponse, error)
+	// GetFrameSystemSnapshot returns the current world pose and geometries of every frame in the
+	// frame system of the robot, using the present inputs of its components.
+	GetFrameSystemSnapshot(ctx context.Context, in *GetFrameSystemSnapshotRequest, opts ...grpc.CallOption) (*GetFrameSystemSnapshotResponse, error)
+	// GetOperations returns the running operations of the robot along with the operations that
+	// started them, so that they can be arranged into a tree.
+	GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error)
+}
+
+type robotIntrospectionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRobotIntrospectionServiceClient(cc grpc.ClientConnInterface) RobotIntrospectionServiceClient {
+	return &robotIntrospectionServiceClient{cc}
+}
+
+func (c *robotIntrospectionServiceClient) GetResourceGraph(ctx context.Context, in *GetResourceGraphRequest, opts ...grpc.CallOption) (*GetResourceGraphResponse, error) {
+	out := new(GetResourceGraphResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotIntrospectionService/GetResourceGraph", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotIntrospectionServiceClient) GetFrameSystemSnapshot(ctx context.Context, in *GetFrameSystemSnapshotRequest, opts ...grpc.CallOption) (*GetFrameSystemSnapshotResponse, error) {
+	out := new(GetFrameSystemSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotIntrospectionService/GetFrameSystemSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *robotIntrospectionServiceClient) GetOperations(ctx context.Context, in *GetOperationsRequest, opts ...grpc.CallOption) (*GetOperationsResponse, error) {
+	out := new(GetOperationsResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.robot.v1.RobotIntrospectionService/GetOperations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RobotIntrospectionServiceServer is the server API for RobotIntrospectionService service.
+// All implementations must embed UnimplementedRobotIntrospectionServiceServer
+// for forward compatibility
+type RobotIntrospectionServiceServer interface {
+	// GetResourceGraph returns the dependency graph between the resources of the robot, along with
+	// the part of the config each resource came from and the state it is in.
+	GetResourceGraph(context.Context, *GetResourceGraphRequest) (*GetResourceGraphResponse, error)
+	// GetFrameSystemSnapshot returns the current world pose and geometries of every frame in the
+	// frame system of the robot, using the present inputs of its components.
+	GetFrameSystemSnapshot(context.Context, *GetFrameSystemSnapshotRequest) (*GetFrameSystemSnapshotResponse, error)
+	// GetOperations returns the running operations of the robot along with the operations that
+	// started them, so that they can be arranged into a tree.
+	GetOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error)
+	mustEmbedUnimplementedRobotIntrospectionServiceServer()
+}
+
+// UnimplementedRobotIntrospectionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedRobotIntrospectionServiceServer struct {
+}
+
+func (UnimplementedRobotIntrospectionServiceServer) GetResourceGraph(context.Context, *GetResourceGraphRequest) (*GetResourceGraphResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResourceGraph not implemented")
+}
+func (UnimplementedRobotIntrospectionServiceServer) GetFrameSystemSnapshot(context.Context, *GetFrameSystemSnapshotRequest) (*GetFrameSystemSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFrameSystemSnapshot not implemented")
+}
+func (UnimplementedRobotIntrospectionServiceServer) GetOperations(context.Context, *GetOperationsRequest) (*GetOperationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperations not implemented")
+}
+func (UnimplementedRobotIntrospectionServiceServer) mustEmbedUnimplementedRobotIntrospectionServiceServer() {
+}
+
+// UnsafeRobotIntrospectionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RobotIntrospectionServiceServer will
+// result in compilation errors.
+type UnsafeRobotIntrospectionServiceServer interface {
+	mustEmbedUnimplementedRobotIntrospectionServiceServer()
+}
+
+func RegisterRobotIntrospectionServiceServer(s grpc.ServiceRegistrar, srv RobotIntrospectionServiceServer) {
+	s.RegisterService(&RobotIntrospectionService_ServiceDesc, srv)
+}
+
+func _RobotIntrospectionService_GetResourceGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetResourceGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotIntrospectionServiceServer).GetResourceGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotIntrospectionService/GetResourceGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotIntrospectionServiceServer).GetResourceGraph(ctx, req.(*GetResourceGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotIntrospectionService_GetFrameSystemSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFrameSystemSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotIntrospectionServiceServer).GetFrameSystemSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotIntrospectionService/GetFrameSystemSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotIntrospectionServiceServer).GetFrameSystemSnapshot(ctx, req.(*GetFrameSystemSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RobotIntrospectionService_GetOperations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RobotIntrospectionServiceServer).GetOperations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.robot.v1.RobotIntrospectionService/GetOperations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RobotIntrospectionServiceServer).GetOperations(ctx, req.(*GetOperationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RobotIntrospectionService_ServiceDesc is the grpc.ServiceDesc for RobotIntrospectionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RobotIntrospectionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.api.robot.v1.RobotIntrospectionService",
+	HandlerType: (*RobotIntrospectionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetResourceGraph",
+			Handler:    _RobotIntrospectionService_GetResourceGraph_Handler,
+		},
+		{
+			MethodName: "GetFrameSystemSnapshot",
+			Handler:    _RobotIntrospectionService_GetFrameSystemSnapshot_Handler,
+		},
+		{
+			MethodName: "GetOperations",
+			Handler:    _RobotIntrospectionService_GetOperations_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/api/robot/v1/introspection.proto",
+}
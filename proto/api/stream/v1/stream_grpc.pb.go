@@ -0,0 +1,231 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: proto/api/stream/v1/stream.proto
+
+package v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// StreamControlServiceClient is the client API for StreamControlService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StreamControlServiceClient interface {
+	// SetStreamOptions changes the options the named video stream is encoded at for all of its
+	// subscribers.
+	SetStreamOptions(ctx context.Context, in *SetStreamOptionsRequest, opts ...grpc.CallOption) (*SetStreamOptionsResponse, error)
+	// AddStreamVariant adds a variant of the named video stream with its own options for the
+	// caller, which is the session of the request or else the subscriber named in it, and responds
+	// with the name to subscribe to it by.
+	AddStreamVariant(ctx context.Context, in *AddStreamVariantRequest, opts ...grpc.CallOption) (*AddStreamVariantResponse, error)
+	// StartRecording starts recording the named video stream to segmented files on the robot. The
+	// recording continues until it is stopped, one of its limits is reached or the robot shuts down.
+	StartRecording(ctx context.Context, in *StartRecordingRequest, opts ...grpc.CallOption) (*StartRecordingResponse, error)
+	// StopRecording stops recording the named video stream and responds with the segment files
+	// written.
+	StopRecording(ctx context.Context, in *StopRecordingRequest, opts ...grpc.CallOption) (*StopRecordingResponse, error)
+}
+
+type streamControlServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStreamControlServiceClient(cc grpc.ClientConnInterface) StreamControlServiceClient {
+	return &streamControlServiceClient{cc}
+}
+
+func (c *streamControlServiceClient) SetStreamOptions(ctx context.Context, in *SetStreamOptionsRequest, opts ...grpc.CallOption) (*SetStreamOptionsResponse, error) {
+	out := new(SetStreamOptionsResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.stream.v1.StreamControlService/SetStreamOptions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamControlServiceClient) AddStreamVariant(ctx context.Context, in *AddStreamVariantRequest, opts ...grpc.CallOption) (*AddStreamVariantResponse, error) {
+	out := new(AddStreamVariantResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.stream.v1.StreamControlService/AddStreamVariant", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamControlServiceClient) StartRecording(ctx context.Context, in *StartRecordingRequest, opts ...grpc.CallOption) (*StartRecordingResponse, error) {
+	out := new(StartRecordingResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.stream.v1.StreamControlService/StartRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamControlServiceClient) StopRecording(ctx context.Context, in *StopRecordingRequest, opts ...grpc.CallOption) (*StopRecordingResponse, error) {
+	out := new(StopRecordingResponse)
+	err := c.cc.Invoke(ctx, "/proto.api.stream.v1.StreamControlService/StopRecording", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StreamControlServiceServer is the server API for StreamControlService service.
+// All implementations must embed UnimplementedStreamControlServiceServer
+// for forward compatibility
+type StreamControlServiceServer interface {
+	// SetStreamOptions changes the options the named video stream is encoded at for all of its
+	// subscribers.
+	SetStreamOptions(context.Context, *SetStreamOptionsRequest) (*SetStreamOptionsResponse, error)
+	// AddStreamVariant adds a variant of the named video stream with its own options for the
+	// caller, which is the session of the request or else the subscriber named in it, and responds
+	// with the name to subscribe to it by.
+	AddStreamVariant(context.Context, *AddStreamVariantRequest) (*AddStreamVariantResponse, error)
+	// StartRecording starts recording the named video stream to segmented files on the robot. The
+	// recording continues until it is stopped, one of its limits is reached or the robot shuts down.
+	StartRecording(context.Context, *StartRecordingRequest) (*StartRecordingResponse, error)
+	// StopRecording stops recording the named video stream and responds with the segment files
+	// written.
+	StopRecording(context.Context, *StopRecordingRequest) (*StopRecordingResponse, error)
+	mustEmbedUnimplementedStreamControlServiceServer()
+}
+
+// UnimplementedStreamControlServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedStreamControlServiceServer struct {
+}
+
+func (UnimplementedStreamControlServiceServer) SetStreamOptions(context.Context, *SetStreamOptionsRequest) (*SetStreamOptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStreamOptions not implemented")
+}
+func (UnimplementedStreamControlServiceServer) AddStreamVariant(context.Context, *AddStreamVariantRequest) (*AddStreamVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddStreamVariant not implemented")
+}
+func (UnimplementedStreamControlServiceServer) StartRecording(context.Context, *StartRecordingRequest) (*StartRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRecording not implemented")
+}
+func (UnimplementedStreamControlServiceServer) StopRecording(context.Context, *StopRecordingRequest) (*StopRecordingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopRecording not implemented")
+}
+func (UnimplementedStreamControlServiceServer) mustEmbedUnimplementedStreamControlServiceServer() {}
+
+// UnsafeStreamControlServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StreamControlServiceServer will
+// result in compilation errors.
+type UnsafeStreamControlServiceServer interface {
+	mustEmbedUnimplementedStreamControlServiceServer()
+}
+
+func RegisterStreamControlServiceServer(s grpc.ServiceRegistrar, srv StreamControlServiceServer) {
+	s.RegisterService(&StreamControlService_ServiceDesc, srv)
+}
+
+func _StreamControlService_SetStreamOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStreamOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamControlServiceServer).SetStreamOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.stream.v1.StreamControlService/SetStreamOptions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamControlServiceServer).SetStreamOptions(ctx, req.(*SetStreamOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamControlService_AddStreamVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddStreamVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamControlServiceServer).AddStreamVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.stream.v1.StreamControlService/AddStreamVariant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamControlServiceServer).AddStreamVariant(ctx, req.(*AddStreamVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamControlService_StartRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamControlServiceServer).StartRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.stream.v1.StreamControlService/StartRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamControlServiceServer).StartRecording(ctx, req.(*StartRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamControlService_StopRecording_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRecordingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamControlServiceServer).StopRecording(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.api.stream.v1.StreamControlService/StopRecording",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamControlServiceServer).StopRecording(ctx, req.(*StopRecordingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StreamControlService_ServiceDesc is the grpc.ServiceDesc for StreamControlService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StreamControlService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.api.stream.v1.StreamControlService",
+	HandlerType: (*StreamControlServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetStreamOptions",
+			Handler:    _StreamControlService_SetStreamOptions_Handler,
+		},
+		{
+			MethodName: "AddStreamVariant",
+			Handler:    _StreamControlService_AddStreamVariant_Handler,
+		},
+		{
+			MethodName: "StartRecording",
+			Handler:    _StreamControlService_StartRecording_Handler,
+		},
+		{
+			MethodName: "StopRecording",
+			Handler:    _StreamControlService_StopRecording_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/api/stream/v1/stream.proto",
+}
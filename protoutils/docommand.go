@@ -0,0 +1,358 @@
+package protoutils
+
+import (
+	"context"
+	"encoding"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// docommandTag is the struct tag holding DoCommand specific options. The only option
+// currently supported is "required", which makes decoding fail if the field is missing.
+const docommandTag = "docommand"
+
+// A DoCommander is anything that accepts DoCommand payloads, such as a resource or its client.
+type DoCommander interface {
+	DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error)
+}
+
+// A Validator checks a decoded DoCommand payload for consistency.
+type Validator interface {
+	Validate() error
+}
+
+var errNilDoCommand = errors.New("cannot convert nil to a DoCommand payload")
+
+// StructToDoCommand converts a struct (or pointer to one) into a DoCommand map. Field names
+// come from `json` tags, honoring "-" and "omitempty". Nested structs, slices and maps are
+// converted recursively and types implementing encoding.TextMarshaler (such as enums and
+// time.Time) are converted to their text form. Protobuf enums become their names,
+// time.Duration values strings like "1.5s", byte slices base64 strings, and values of other
+// named types their underlying bool, number or string.
+func StructToDoCommand(v interface{}) (map[string]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, errNilDoCommand
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, errNilDoCommand
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.Errorf("expected a struct but got %s", rv.Type())
+	}
+	return structToMap(rv)
+}
+
+// DoCommandToStruct decodes a DoCommand map into the struct pointed to by out, using the
+// same field naming as StructToDoCommand. Numbers are converted to the field's type, enums
+// are parsed with encoding.TextUnmarshaler, and the string forms StructToDoCommand produces
+// for protobuf enums, durations and byte slices are parsed back. Fields tagged
+// `docommand:"required"` must be present, and if out implements Validator it is validated
+// once decoded.
+func DoCommandToStruct(m map[string]interface{}, out interface{}) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("expected a pointer to a struct but got %T", out)
+	}
+	if err := checkRequired(m, rv.Elem().Type(), ""); err != nil {
+		return err
+	}
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName: "json",
+		Result:  out,
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.TextUnmarshallerHookFunc(),
+			mapstructure.StringToTimeDurationHookFunc(),
+			stringToBytesHookFunc,
+			stringToProtoEnumHookFunc,
+		),
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(m); err != nil {
+		return err
+	}
+	if validator, ok := out.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// DoTypedCommand sends req to the given DoCommander and decodes the response into a Resp.
+func DoTypedCommand[Resp, Req any](ctx context.Context, dc DoCommander, req Req) (Resp, error) {
+	var resp Resp
+	cmd, err := StructToDoCommand(req)
+	if err != nil {
+		return resp, err
+	}
+	ret, err := dc.DoCommand(ctx, cmd)
+	if err != nil {
+		return resp, err
+	}
+	if err := DoCommandToStruct(ret, &resp); err != nil {
+		return resp, errors.Wrap(err, "error decoding DoCommand response")
+	}
+	return resp, nil
+}
+
+type fieldInfo struct {
+	name      string
+	omitEmpty bool
+	required  bool
+}
+
+func parseField(f reflect.StructField) (fieldInfo, bool) {
+	if !f.IsExported() {
+		return fieldInfo{}, false
+	}
+	info := fieldInfo{name: f.Name}
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		if tag == "-" {
+			return fieldInfo{}, false
+		}
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			info.name = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				info.omitEmpty = true
+			}
+		}
+	}
+	for _, opt := range strings.Split(f.Tag.Get(docommandTag), ",") {
+		if opt == "required" {
+			info.required = true
+		}
+	}
+	return info, true
+}
+
+func structToMap(rv reflect.Value) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for i := 0; i < rv.NumField(); i++ {
+		info, ok := parseField(rv.Type().Field(i))
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		if info.omitEmpty && fv.IsZero() {
+			continue
+		}
+		v, err := valueToInterface(fv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %q", info.name)
+		}
+		m[info.name] = v
+	}
+	return m, nil
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	protoEnumType     = reflect.TypeOf((*protoreflect.Enum)(nil)).Elem()
+	durationType      = reflect.TypeOf(time.Duration(0))
+)
+
+// basicTypes are the unnamed types values of named types are converted to, so that they can
+// be put in a structpb.Struct.
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int:     reflect.TypeOf(0),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.String:  reflect.TypeOf(""),
+}
+
+func valueToInterface(rv reflect.Value) (interface{}, error) {
+	switch {
+	case rv.Type() == durationType:
+		return time.Duration(rv.Int()).String(), nil
+	case rv.Type().Implements(protoEnumType) && rv.Kind() != reflect.Pointer:
+		//nolint:forcetypeassert
+		return protoEnumName(rv.Interface().(protoreflect.Enum)), nil
+	}
+
+	if rv.Type().Implements(textMarshalerType) {
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, nil
+		}
+		//nolint:forcetypeassert
+		text, err := rv.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return string(text), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return valueToInterface(rv.Elem())
+	case reflect.Struct:
+		return structToMap(rv)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(rv.Bytes()), nil
+		}
+		out := make([]interface{}, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			v, err := valueToInterface(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, errors.Errorf("map keys must be strings but got %s", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		out := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			v, err := valueToInterface(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			out[iter.Key().String()] = v
+		}
+		return out, nil
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+		return nil, errors.Errorf("unsupported type %s", rv.Type())
+	default:
+		if basic, ok := basicTypes[rv.Kind()]; ok && rv.Type() != basic {
+			return rv.Convert(basic).Interface(), nil
+		}
+		return rv.Interface(), nil
+	}
+}
+
+// normalizeValue converts the values in a map or list that structpb does not support. Structs
+// other than text marshalers are left to be encoded as json.
+func normalizeValue(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, elem := range x {
+			n, err := normalizeValue(elem)
+			if err != nil {
+				return nil, errors.Wrapf(err, "key %q", k)
+			}
+			out[k] = n
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(x))
+		for i, elem := range x {
+			n, err := normalizeValue(elem)
+			if err != nil {
+				return nil, errors.Wrapf(err, "index %d", i)
+			}
+			out = append(out, n)
+		}
+		return out, nil
+	}
+	rv := reflect.ValueOf(v)
+	t := rv.Type()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && !rv.Type().Implements(textMarshalerType) {
+		return v, nil
+	}
+	return valueToInterface(rv)
+}
+
+// protoEnumName returns the name of a protobuf enum value, or its number if it has none.
+func protoEnumName(e protoreflect.Enum) interface{} {
+	if v := e.Descriptor().Values().ByNumber(e.Number()); v != nil {
+		return string(v.Name())
+	}
+	return int32(e.Number())
+}
+
+// stringToBytesHookFunc decodes base64 strings into byte slices.
+func stringToBytesHookFunc(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Slice || to.Elem().Kind() != reflect.Uint8 {
+		return data, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(reflect.ValueOf(data).String())
+	if err != nil {
+		return nil, errors.Wrap(err, "expected a base64 string")
+	}
+	return reflect.ValueOf(b).Convert(to).Interface(), nil
+}
+
+// stringToProtoEnumHookFunc decodes the names of protobuf enum values.
+func stringToProtoEnumHookFunc(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() == reflect.Pointer || !to.Implements(protoEnumType) {
+		return data, nil
+	}
+	//nolint:forcetypeassert
+	enum := reflect.Zero(to).Interface().(protoreflect.Enum)
+	name := reflect.ValueOf(data).String()
+	v := enum.Descriptor().Values().ByName(protoreflect.Name(name))
+	if v == nil {
+		return nil, errors.Errorf("unknown %s value %q", enum.Descriptor().FullName(), name)
+	}
+	return reflect.ValueOf(v.Number()).Convert(to).Interface(), nil
+}
+
+// checkRequired makes sure every field of t tagged as required is present in m, descending
+// into nested structs that are present.
+func checkRequired(m map[string]interface{}, t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		info, ok := parseField(f)
+		if !ok {
+			continue
+		}
+		v, present := m[info.name]
+		if info.required && (!present || v == nil) {
+			return errors.Errorf("missing required field %q", path+info.name)
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			if err := checkRequired(nested, f.Type, path+info.name+"."); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
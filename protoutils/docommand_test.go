@@ -0,0 +1,225 @@
+package protoutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	navpb "go.viam.com/api/service/navigation/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type testMode int
+
+const (
+	testModeSlow testMode = iota
+	testModeFast
+)
+
+func (m testMode) MarshalText() ([]byte, error) {
+	switch m {
+	case testModeSlow:
+		return []byte("slow"), nil
+	case testModeFast:
+		return []byte("fast"), nil
+	default:
+		return nil, errors.Errorf("unknown mode %d", m)
+	}
+}
+
+func (m *testMode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "slow":
+		*m = testModeSlow
+	case "fast":
+		*m = testModeFast
+	default:
+		return errors.Errorf("unknown mode %q", text)
+	}
+	return nil
+}
+
+type testTarget struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type testMoveCmd struct {
+	Command string            `json:"command" docommand:"required"`
+	Speed   int               `json:"speed"`
+	Mode    testMode          `json:"mode"`
+	Target  *testTarget       `json:"target,omitempty"`
+	Path    []testTarget      `json:"path,omitempty"`
+	Extra   map[string]string `json:"extra,omitempty"`
+	skipped bool
+}
+
+func (c *testMoveCmd) Validate() error {
+	if c.Speed < 0 {
+		return errors.New("speed must be positive")
+	}
+	return nil
+}
+
+type testDoCommander struct {
+	lastCmd map[string]interface{}
+}
+
+func (dc *testDoCommander) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	dc.lastCmd = cmd
+	return map[string]interface{}{"command": "done", "speed": float64(3), "mode": "fast"}, nil
+}
+
+func TestDoCommandRoundtrip(t *testing.T) {
+	cmd := testMoveCmd{
+		Command: "move",
+		Speed:   10,
+		Mode:    testModeFast,
+		Target:  &testTarget{X: 1, Y: 2},
+		Path:    []testTarget{{X: 3, Y: 4}},
+		Extra:   map[string]string{"a": "b"},
+		skipped: true,
+	}
+	m, err := StructToDoCommand(&cmd)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m, test.ShouldResemble, map[string]interface{}{
+		"command": "move",
+		"speed":   10,
+		"mode":    "fast",
+		"target":  map[string]interface{}{"x": 1.0, "y": 2.0},
+		"path":    []interface{}{map[string]interface{}{"x": 3.0, "y": 4.0}},
+		"extra":   map[string]interface{}{"a": "b"},
+	})
+
+	// go through proto like a real DoCommand so numbers become float64
+	pb, err := structpb.NewStruct(m)
+	test.That(t, err, test.ShouldBeNil)
+
+	var decoded testMoveCmd
+	test.That(t, DoCommandToStruct(pb.AsMap(), &decoded), test.ShouldBeNil)
+	cmd.skipped = false
+	test.That(t, decoded, test.ShouldResemble, cmd)
+
+	m, err = StructToDoCommand(testMoveCmd{Command: "stop"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m, test.ShouldResemble, map[string]interface{}{"command": "stop", "speed": 0, "mode": "slow"})
+}
+
+func TestDoCommandErrors(t *testing.T) {
+	_, err := StructToDoCommand(nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = StructToDoCommand((*testMoveCmd)(nil))
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = StructToDoCommand(5)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = StructToDoCommand(testMoveCmd{Mode: testMode(7)})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown mode")
+
+	var decoded testMoveCmd
+	err = DoCommandToStruct(map[string]interface{}{"speed": 1}, &decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `missing required field "command"`)
+
+	err = DoCommandToStruct(map[string]interface{}{"command": "move", "mode": "medium"}, &decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	err = DoCommandToStruct(map[string]interface{}{"command": "move", "speed": -1}, &decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "speed must be positive")
+
+	err = DoCommandToStruct(map[string]interface{}{"command": "move"}, decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestDoTypedCommand(t *testing.T) {
+	dc := &testDoCommander{}
+	resp, err := DoTypedCommand[testMoveCmd](context.Background(), dc, testMoveCmd{Command: "move", Speed: 2})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, dc.lastCmd["command"], test.ShouldEqual, "move")
+	test.That(t, resp, test.ShouldResemble, testMoveCmd{Command: "done", Speed: 3, Mode: testModeFast})
+}
+
+type testLevel int
+
+type testRichCmd struct {
+	At       time.Time     `json:"at"`
+	Timeout  time.Duration `json:"timeout"`
+	Data     []byte        `json:"data"`
+	Level    testLevel     `json:"level"`
+	NavMode  navpb.Mode    `json:"nav_mode"`
+	NavModes []navpb.Mode  `json:"nav_modes"`
+}
+
+func TestDoCommandRichTypes(t *testing.T) {
+	cmd := testRichCmd{
+		At:       time.Date(2023, 3, 1, 12, 30, 0, 500, time.UTC),
+		Timeout:  1500 * time.Millisecond,
+		Data:     []byte{0, 1, 2, 255},
+		Level:    3,
+		NavMode:  navpb.Mode_MODE_WAYPOINT,
+		NavModes: []navpb.Mode{navpb.Mode_MODE_MANUAL},
+	}
+	m, err := StructToDoCommand(cmd)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m, test.ShouldResemble, map[string]interface{}{
+		"at":        "2023-03-01T12:30:00.0000005Z",
+		"timeout":   "1.5s",
+		"data":      "AAEC/w==",
+		"level":     3,
+		"nav_mode":  "MODE_WAYPOINT",
+		"nav_modes": []interface{}{"MODE_MANUAL"},
+	})
+
+	pb, err := structpb.NewStruct(m)
+	test.That(t, err, test.ShouldBeNil)
+	var decoded testRichCmd
+	test.That(t, DoCommandToStruct(pb.AsMap(), &decoded), test.ShouldBeNil)
+	test.That(t, decoded.At.Equal(cmd.At), test.ShouldBeTrue)
+	decoded.At = cmd.At
+	test.That(t, decoded, test.ShouldResemble, cmd)
+
+	// durations and enums may also be given as numbers
+	test.That(t, DoCommandToStruct(map[string]interface{}{"timeout": float64(time.Second), "nav_mode": float64(1)}, &decoded),
+		test.ShouldBeNil)
+	test.That(t, decoded.Timeout, test.ShouldEqual, time.Second)
+	test.That(t, decoded.NavMode, test.ShouldEqual, navpb.Mode_MODE_MANUAL)
+
+	err = DoCommandToStruct(map[string]interface{}{"nav_mode": "MODE_FLYING"}, &decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "MODE_FLYING")
+	err = DoCommandToStruct(map[string]interface{}{"data": "not base64!"}, &decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+	err = DoCommandToStruct(map[string]interface{}{"timeout": "soon"}, &decoded)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestMapToStructPb(t *testing.T) {
+	at := time.Date(2023, 3, 1, 12, 30, 0, 0, time.UTC)
+	pb, err := MapToStructPb(map[string]interface{}{
+		"at":      at,
+		"timeout": 2 * time.Second,
+		"data":    []byte("hi"),
+		"level":   testLevel(2),
+		"mode":    navpb.Mode_MODE_MANUAL,
+		"list":    []interface{}{testLevel(1), time.Minute},
+		"nested":  map[string]interface{}{"mode": testModeFast},
+		"target":  testTarget{X: 1, Y: 2},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pb.AsMap(), test.ShouldResemble, map[string]interface{}{
+		"at":      "2023-03-01T12:30:00Z",
+		"timeout": "2s",
+		"data":    "aGk=",
+		"level":   2.0,
+		"mode":    "MODE_MANUAL",
+		"list":    []interface{}{1.0, "1m0s"},
+		"nested":  map[string]interface{}{"mode": "fast"},
+		"target":  map[string]interface{}{"x": 1.0, "y": 2.0},
+	})
+
+	_, err = MapToStructPb(map[string]interface{}{"mode": testMode(7)})
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -14,6 +14,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"go.viam.com/rdk/resource"
@@ -133,7 +134,7 @@ type ClientDoCommander interface {
 func DoFromResourceClient(ctx context.Context, svc ClientDoCommander, name string,
 	cmd map[string]interface{},
 ) (map[string]interface{}, error) {
-	command, err := protoutils.StructToStructPb(cmd)
+	command, err := MapToStructPb(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -155,9 +156,21 @@ func DoFromResourceServer(ctx context.Context, resource resource.Generic,
 	if err != nil {
 		return nil, err
 	}
-	pbRes, err := protoutils.StructToStructPb(res)
+	pbRes, err := MapToStructPb(res)
 	if err != nil {
 		return nil, err
 	}
 	return &commonpb.DoCommandResponse{Result: pbRes}, nil
 }
+
+// MapToStructPb converts a DoCommand payload or extra parameters into a structpb.Struct. Values
+// structpb does not support, such as times, durations, protobuf enums, byte slices and values of
+// named types, are converted like StructToDoCommand converts them so that DoCommandToStruct can
+// decode them again. Structs are converted by their json encoding.
+func MapToStructPb(m map[string]interface{}) (*structpb.Struct, error) {
+	normalized, err := normalizeValue(m)
+	if err != nil {
+		return nil, err
+	}
+	return protoutils.StructToStructPb(normalized)
+}
@@ -0,0 +1,44 @@
+package referenceframe
+
+// AllowedCollision is a pair of geometries, by name, which may touch without motion planning treating it as a collision, e.g. a custom
+// end effector mounted flush against the wrist of an arm. Geometries are named as in the GeometriesInFrame of their frames, such as
+// "arm:wrist_link" for a link of an arm model, or "gripper_origin" for the geometry given in the frame config of a gripper.
+type AllowedCollision struct {
+	Geometry1 string `json:"geometry1"`
+	Geometry2 string `json:"geometry2"`
+}
+
+// CollisionAllowingFrame is a frame which declares pairs of geometries that may touch.
+type CollisionAllowingFrame interface {
+	Frame
+
+	// AllowedCollisions returns the pairs of geometries which may touch.
+	AllowedCollisions() []AllowedCollision
+}
+
+// AllowedCollisionsOf returns the pairs of geometries a frame declares may touch, which is none for frames that do not declare any.
+func AllowedCollisionsOf(f Frame) []AllowedCollision {
+	if cf, ok := f.(CollisionAllowingFrame); ok {
+		return cf.AllowedCollisions()
+	}
+	return nil
+}
+
+// FrameSystemAllowedCollisions returns the pairs of geometries which the frames of a frame system declare may touch.
+func FrameSystemAllowedCollisions(fs FrameSystem) []AllowedCollision {
+	var allowed []AllowedCollision
+	for _, name := range fs.FrameNames() {
+		allowed = append(allowed, AllowedCollisionsOf(fs.Frame(name))...)
+	}
+	return allowed
+}
+
+// collisionAllowingFrame wraps a frame to declare pairs of geometries that may touch.
+type collisionAllowingFrame struct {
+	Frame
+	allowed []AllowedCollision
+}
+
+func (f *collisionAllowingFrame) AllowedCollisions() []AllowedCollision {
+	return append([]AllowedCollision{}, f.allowed...)
+}
@@ -0,0 +1,50 @@
+package referenceframe
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+)
+
+func TestAllowedCollisions(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	var lc LinkConfig
+	err := json.Unmarshal([]byte(`{
+		"id": "gripper",
+		"parent": "world",
+		"geometry": {"type": "box", "x": 10, "y": 10, "z": 10},
+		"allowed_collisions": [{"geometry1": "gripper_origin", "geometry2": "arm:wrist_link"}]
+	}`), &lc)
+	test.That(t, err, test.ShouldBeNil)
+	allowed := []AllowedCollision{{Geometry1: "gripper_origin", Geometry2: "arm:wrist_link"}}
+	test.That(t, lc.AllowedCollisions, test.ShouldResemble, allowed)
+	lif, err := lc.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lif.AllowedCollisions(), test.ShouldResemble, allowed)
+
+	// the frame system declares the allowed collisions of its parts
+	modelFrame, originFrame, err := CreateFramesFromPart(&FrameSystemPart{FrameConfig: lif}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, AllowedCollisionsOf(originFrame), test.ShouldResemble, allowed)
+	test.That(t, AllowedCollisionsOf(modelFrame), test.ShouldBeNil)
+	fs := NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(originFrame, fs.World()), test.ShouldBeNil)
+	test.That(t, fs.AddFrame(modelFrame, originFrame), test.ShouldBeNil)
+	test.That(t, FrameSystemAllowedCollisions(fs), test.ShouldResemble, allowed)
+
+	// geometries of the part are unchanged
+	geometries, err := originFrame.Geometries([]Input{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometries.Geometries(), test.ShouldContainKey, "gripper_origin")
+
+	// parts not declaring any are not wrapped
+	lc.AllowedCollisions = nil
+	lif, err = lc.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	_, originFrame, err = CreateFramesFromPart(&FrameSystemPart{FrameConfig: lif}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	_, ok := originFrame.(*tailGeometryStaticFrame)
+	test.That(t, ok, test.ShouldBeTrue)
+}
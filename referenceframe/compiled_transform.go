@@ -0,0 +1,170 @@
+package referenceframe
+
+import (
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// compiledLink is one step of a CompiledTransform, either a frame whose pose depends on its inputs or the time, or the pose of a
+// run of static frames composed together ahead of time.
+type compiledLink struct {
+	frame Frame
+	pose  spatial.Pose
+}
+
+// CompiledTransform is the transform from one frame of a frame system to another, with the chain of frames between them resolved
+// once when it is compiled rather than on every call as FrameSystem.Transform does, and runs of frames without degrees of freedom
+// composed ahead of time. It is for consumers transforming many poses or points, such as every point of a point cloud, between the
+// same two frames at a high rate. A CompiledTransform does not see changes made to its frame system after it is compiled, so should
+// be compiled again when frames are added, removed or replaced.
+type CompiledTransform struct {
+	src, dst string
+	// links from the source up to the nearest frame it shares with the destination, and likewise from the destination
+	srcLinks, dstLinks []compiledLink
+}
+
+// CompileTransform resolves the chain of frames between the frames named src and dst in a frame system, returning a
+// CompiledTransform of poses and points from the first to the second.
+func CompileTransform(fs FrameSystem, src, dst string) (*CompiledTransform, error) {
+	srcFrame := fs.Frame(src)
+	if srcFrame == nil {
+		return nil, NewFrameMissingError(src)
+	}
+	dstFrame := fs.Frame(dst)
+	if dstFrame == nil {
+		return nil, NewFrameMissingError(dst)
+	}
+	srcChain, err := fs.TracebackFrame(srcFrame)
+	if err != nil {
+		return nil, err
+	}
+	dstChain, err := fs.TracebackFrame(dstFrame)
+	if err != nil {
+		return nil, err
+	}
+
+	// both chains end at the world, so drop the frames they share from the end of each
+	for len(srcChain) > 0 && len(dstChain) > 0 && srcChain[len(srcChain)-1] == dstChain[len(dstChain)-1] {
+		srcChain, dstChain = srcChain[:len(srcChain)-1], dstChain[:len(dstChain)-1]
+	}
+	srcLinks, err := compileLinks(srcChain)
+	if err != nil {
+		return nil, err
+	}
+	dstLinks, err := compileLinks(dstChain)
+	if err != nil {
+		return nil, err
+	}
+	return &CompiledTransform{src: src, dst: dst, srcLinks: srcLinks, dstLinks: dstLinks}, nil
+}
+
+// compileLinks turns a chain of frames, ordered from child to parent, into links, composing the poses of runs of static frames.
+func compileLinks(chain []Frame) ([]compiledLink, error) {
+	var links []compiledLink
+	var static spatial.Pose
+	for _, frame := range chain {
+		if _, ok := frame.(TimeVaryingFrame); ok || len(frame.DoF()) > 0 {
+			if static != nil {
+				links = append(links, compiledLink{pose: static})
+				static = nil
+			}
+			links = append(links, compiledLink{frame: frame})
+			continue
+		}
+		pose, err := frame.Transform([]Input{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot compile the transform of frame %q", frame.Name())
+		}
+		if static == nil {
+			static = pose
+		} else {
+			static = spatial.Compose(pose, static)
+		}
+	}
+	if static != nil {
+		links = append(links, compiledLink{pose: static})
+	}
+	return links, nil
+}
+
+// Source returns the name of the frame the CompiledTransform transforms from.
+func (ct *CompiledTransform) Source() string {
+	return ct.src
+}
+
+// Destination returns the name of the frame the CompiledTransform transforms to.
+func (ct *CompiledTransform) Destination() string {
+	return ct.dst
+}
+
+// Pose returns the pose of the source frame in the destination frame, given the inputs of any frames with degrees of freedom
+// between them. As with FrameSystem.Transform, any TimeVaryingFrames are placed as they are at the current time.
+func (ct *CompiledTransform) Pose(positions map[string][]Input) (spatial.Pose, error) {
+	return ct.PoseAt(positions, time.Now())
+}
+
+// PoseAt is as Pose, with any TimeVaryingFrames placed as they were at the given time.
+func (ct *CompiledTransform) PoseAt(positions map[string][]Input, timestamp time.Time) (spatial.Pose, error) {
+	srcToCommon, err := composeLinks(ct.srcLinks, positions, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	dstToCommon, err := composeLinks(ct.dstLinks, positions, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return spatial.Compose(spatial.PoseInverse(dstToCommon), srcToCommon), nil
+}
+
+// TransformPoses returns poses in the source frame transformed into the destination frame.
+func (ct *CompiledTransform) TransformPoses(positions map[string][]Input, poses []spatial.Pose) ([]spatial.Pose, error) {
+	tf, err := ct.Pose(positions)
+	if err != nil {
+		return nil, err
+	}
+	transformed := make([]spatial.Pose, 0, len(poses))
+	for _, pose := range poses {
+		transformed = append(transformed, spatial.Compose(tf, pose))
+	}
+	return transformed, nil
+}
+
+// TransformPoints returns points in the source frame transformed into the destination frame. The transform is resolved once, and
+// applied to each point as a rotation and a translation.
+func (ct *CompiledTransform) TransformPoints(positions map[string][]Input, points []r3.Vector) ([]r3.Vector, error) {
+	tf, err := ct.Pose(positions)
+	if err != nil {
+		return nil, err
+	}
+	// the rows of the rotation matrix of an orientation are where it rotates each axis to
+	rotation := tf.Orientation().RotationMatrix()
+	x, y, z := rotation.Row(0), rotation.Row(1), rotation.Row(2)
+	translation := tf.Point()
+	transformed := make([]r3.Vector, 0, len(points))
+	for _, pt := range points {
+		transformed = append(transformed, x.Mul(pt.X).Add(y.Mul(pt.Y)).Add(z.Mul(pt.Z)).Add(translation))
+	}
+	return transformed, nil
+}
+
+// composeLinks returns the pose of the child end of a chain of links in the frame at its parent end. As with FrameSystem.Transform,
+// inputs outside of the limits of a frame are not an error so long as it can still be placed by them.
+func composeLinks(links []compiledLink, positions map[string][]Input, timestamp time.Time) (spatial.Pose, error) {
+	q := spatial.NewZeroPose()
+	for _, link := range links {
+		pose := link.pose
+		if link.frame != nil {
+			var err error
+			pose, err = poseFromPositions(link.frame, positions, timestamp)
+			if pose == nil {
+				return nil, err
+			}
+		}
+		q = spatial.Compose(pose, q)
+	}
+	return q, nil
+}
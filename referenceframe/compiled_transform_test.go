@@ -0,0 +1,161 @@
+package referenceframe
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// makeCompiledTransformTestFrameSystem returns a frame system with an arm on a rail, a camera on the end of the arm and a fixture
+// on another branch of the world.
+func makeCompiledTransformTestFrameSystem(tb testing.TB) FrameSystem {
+	tb.Helper()
+	fs := NewEmptySimpleFrameSystem("test")
+	rail, err := NewTranslationalFrame("rail", r3.Vector{X: 1}, Limit{Min: -1000, Max: 1000})
+	test.That(tb, err, test.ShouldBeNil)
+	test.That(tb, fs.AddFrame(rail, fs.World()), test.ShouldBeNil)
+	mount, err := NewStaticFrame("mount", spatial.NewPose(r3.Vector{Z: 200}, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 90}))
+	test.That(tb, err, test.ShouldBeNil)
+	test.That(tb, fs.AddFrame(mount, rail), test.ShouldBeNil)
+	arm, err := ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "arm")
+	test.That(tb, err, test.ShouldBeNil)
+	test.That(tb, fs.AddFrame(arm, mount), test.ShouldBeNil)
+	bracket, err := NewStaticFrame("bracket", spatial.NewPoseFromPoint(r3.Vector{Z: 30}))
+	test.That(tb, err, test.ShouldBeNil)
+	test.That(tb, fs.AddFrame(bracket, arm), test.ShouldBeNil)
+	camera, err := NewStaticFrame("camera", spatial.NewPose(r3.Vector{X: 20}, &spatial.OrientationVectorDegrees{OX: 1, Theta: 45}))
+	test.That(tb, err, test.ShouldBeNil)
+	test.That(tb, fs.AddFrame(camera, bracket), test.ShouldBeNil)
+	fixture, err := NewStaticFrame("fixture", spatial.NewPoseFromPoint(r3.Vector{X: 500, Y: -300}))
+	test.That(tb, err, test.ShouldBeNil)
+	test.That(tb, fs.AddFrame(fixture, fs.World()), test.ShouldBeNil)
+	return fs
+}
+
+func TestCompiledTransform(t *testing.T) {
+	fs := makeCompiledTransformTestFrameSystem(t)
+	randSeed := rand.New(rand.NewSource(1))
+	points := make([]r3.Vector, 0, 20)
+	for i := 0; i < 20; i++ {
+		points = append(points, r3.Vector{randSeed.Float64() * 100, randSeed.Float64() * 100, randSeed.Float64() * 100})
+	}
+
+	for _, pair := range [][2]string{
+		{"camera", World},
+		{World, "camera"},
+		{"camera", "fixture"},
+		{"camera", "mount"},
+		{"mount", "camera"},
+		{"camera", "camera"},
+		{"fixture", World},
+	} {
+		ct, err := CompileTransform(fs, pair[0], pair[1])
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ct.Source(), test.ShouldEqual, pair[0])
+		test.That(t, ct.Destination(), test.ShouldEqual, pair[1])
+		for i := 0; i < 5; i++ {
+			positions := StartPositions(fs)
+			positions["rail"] = FloatsToInputs([]float64{randSeed.Float64()*2000 - 1000})
+			positions["arm"] = FloatsToInputs(GenerateRandomConfiguration(fs.Frame("arm").(Model), randSeed))
+
+			// the compiled transform agrees with the frame system
+			pose, err := ct.Pose(positions)
+			test.That(t, err, test.ShouldBeNil)
+			expected, err := fs.Transform(positions, NewPoseInFrame(pair[0], spatial.NewZeroPose()), pair[1])
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, spatial.PoseAlmostEqualEps(pose, expected.(*PoseInFrame).Pose(), 1e-6), test.ShouldBeTrue)
+
+			transformed, err := ct.TransformPoints(positions, points)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(transformed), test.ShouldEqual, len(points))
+			poses := make([]spatial.Pose, 0, len(points))
+			for j, pt := range points {
+				expected, err := fs.Transform(positions, NewPoseInFrame(pair[0], spatial.NewPoseFromPoint(pt)), pair[1])
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, spatial.R3VectorAlmostEqual(transformed[j], expected.(*PoseInFrame).Pose().Point(), 1e-6), test.ShouldBeTrue)
+				poses = append(poses, spatial.NewPoseFromPoint(pt))
+			}
+			transformedPoses, err := ct.TransformPoses(positions, poses)
+			test.That(t, err, test.ShouldBeNil)
+			for j, p := range transformedPoses {
+				test.That(t, spatial.R3VectorAlmostEqual(p.Point(), transformed[j], 1e-6), test.ShouldBeTrue)
+			}
+		}
+	}
+
+	// the static frames between the camera and the arm are composed into a single link
+	ct, err := CompileTransform(fs, "camera", World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(ct.srcLinks), test.ShouldEqual, 4)
+	test.That(t, len(ct.dstLinks), test.ShouldEqual, 0)
+
+	// inputs are still required for the frames with degrees of freedom
+	_, err = ct.Pose(map[string][]Input{"rail": {{0}}})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = CompileTransform(fs, "missing", World)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = CompileTransform(fs, "camera", "missing")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCompiledTransformTimeVarying(t *testing.T) {
+	fs := NewEmptySimpleFrameSystem("test")
+	start := time.Now()
+	conveyor, err := NewCallbackFrame("conveyor", func(timestamp time.Time) (spatial.Pose, error) {
+		return spatial.NewPoseFromPoint(r3.Vector{X: timestamp.Sub(start).Seconds() * 100}), nil
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(conveyor, fs.World()), test.ShouldBeNil)
+
+	ct, err := CompileTransform(fs, "conveyor", World)
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := ct.PoseAt(nil, start.Add(2*time.Second))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostCoincident(pose, spatial.NewPoseFromPoint(r3.Vector{X: 200})), test.ShouldBeTrue)
+}
+
+func benchmarkTransformPoints(b *testing.B) (FrameSystem, map[string][]Input, []r3.Vector) {
+	b.Helper()
+	fs := makeCompiledTransformTestFrameSystem(b)
+	randSeed := rand.New(rand.NewSource(1))
+	positions := StartPositions(fs)
+	positions["arm"] = FloatsToInputs(GenerateRandomConfiguration(fs.Frame("arm").(Model), randSeed))
+	points := make([]r3.Vector, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		points = append(points, r3.Vector{randSeed.Float64() * 1000, randSeed.Float64() * 1000, randSeed.Float64() * 1000})
+	}
+	return fs, positions, points
+}
+
+func BenchmarkFrameSystemTransformPoints(b *testing.B) {
+	fs, positions, points := benchmarkTransformPoints(b)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, pt := range points {
+			if _, err := fs.Transform(positions, NewPoseInFrame("camera", spatial.NewPoseFromPoint(pt)), "fixture"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkCompiledTransformPoints(b *testing.B) {
+	fs, positions, points := benchmarkTransformPoints(b)
+	ct, err := CompileTransform(fs, "camera", "fixture")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := ct.TransformPoints(positions, points); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package referenceframe
+
+import (
+	"fmt"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// DHConvention is the convention a table of Denavit-Hartenberg parameters is given in.
+type DHConvention string
+
+// The following are the conventions tables of Denavit-Hartenberg parameters are commonly given in.
+const (
+	// StandardDH is the original convention, in which each row rotates by theta and translates by d about and along the z axis of its
+	// joint, then translates by a and rotates by alpha along and about the resulting x axis to reach the next joint.
+	StandardDH = DHConvention("standard")
+	// ModifiedDH is Craig's convention, in which each row translates by a and rotates by alpha along and about the x axis of the
+	// previous joint to reach its joint, then rotates by theta and translates by d about and along the z axis of its joint.
+	ModifiedDH = DHConvention("modified")
+)
+
+// DHParameters is one row of a table of Denavit-Hartenberg parameters, describing a joint which rotates about, or for a prismatic
+// joint translates along, its z axis, and the link to the next joint.
+type DHParameters struct {
+	A         float64      // in mm
+	D         float64      // in mm, the offset of the position of a prismatic joint
+	Alpha     float64      // in radians
+	Theta     float64      // in radians, the offset of the position of a revolute joint
+	Prismatic bool         // whether the joint translates along z rather than rotating about it
+	Limit     Limit        // in radians, or mm for prismatic joints
+	Dynamics  DynamicLimit // in radians/s and radians/s^2, or mm/s and mm/s^2 for prismatic joints, unlimited if zero
+}
+
+// NewModelFromDH constructs a serial chain Model from a table of Denavit-Hartenberg parameters, as published on the datasheets of many
+// arms. The joints and links of the model are named "joint1", "link1", "joint2" and so on after the rows of the table, and in the
+// modified convention the link leading to each joint is named after it, such as "joint1_origin". The positions of the joints are
+// measured from the offsets in the table, and the end of the model is the frame of the last row.
+func NewModelFromDH(name string, convention DHConvention, params []DHParameters) (Model, error) {
+	if convention != StandardDH && convention != ModifiedDH {
+		return nil, errors.Errorf("unsupported DH convention: %s, supported conventions are %s and %s", convention, StandardDH, ModifiedDH)
+	}
+	if len(params) == 0 {
+		return nil, errors.New("need at least one row of DH parameters")
+	}
+
+	cfg := &ModelConfig{Name: name, KinParamType: "SVA"}
+	parent := World
+	addLink := func(id string, pose spatial.Pose) error {
+		orientation, err := spatial.NewOrientationConfig(pose.Orientation())
+		if err != nil {
+			return err
+		}
+		cfg.Links = append(cfg.Links, LinkConfig{ID: id, Translation: pose.Point(), Orientation: orientation, Parent: parent})
+		parent = id
+		return nil
+	}
+	for i, row := range params {
+		jointID := fmt.Sprintf("joint%d", i+1)
+		if convention == ModifiedDH {
+			if err := addLink(jointID+"_origin", spatial.NewPoseFromDH(row.A, 0, row.Alpha)); err != nil {
+				return nil, err
+			}
+		}
+
+		joint := JointConfig{ID: jointID, Type: RevoluteJoint, Parent: parent, Axis: spatial.AxisConfig{Z: 1}}
+		if row.Prismatic {
+			joint.Type = PrismaticJoint
+			joint.Min, joint.Max = row.Limit.Min, row.Limit.Max
+			joint.MaxVel, joint.MaxAcc = row.Dynamics.MaxVelocity, row.Dynamics.MaxAcceleration
+		} else {
+			joint.Min, joint.Max = utils.RadToDeg(row.Limit.Min), utils.RadToDeg(row.Limit.Max)
+			joint.MaxVel, joint.MaxAcc = utils.RadToDeg(row.Dynamics.MaxVelocity), utils.RadToDeg(row.Dynamics.MaxAcceleration)
+		}
+		cfg.Joints = append(cfg.Joints, joint)
+		parent = jointID
+
+		// the offset about z commutes with the motion of the joint about or along it, so it is applied by the link after the joint
+		offset := spatial.NewPoseFromOrientation(&spatial.R4AA{Theta: row.Theta, RZ: 1})
+		link := spatial.NewPoseFromPoint(r3.Vector{Z: row.D})
+		if convention == StandardDH {
+			link = spatial.NewPoseFromDH(row.A, row.D, row.Alpha)
+		}
+		if err := addLink(fmt.Sprintf("link%d", i+1), spatial.Compose(offset, link)); err != nil {
+			return nil, err
+		}
+	}
+	return cfg.ParseConfig(name)
+}
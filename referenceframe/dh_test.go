@@ -0,0 +1,83 @@
+package referenceframe
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestNewModelFromDH(t *testing.T) {
+	// the table of referenceframe/testjson/ur5eDH.json
+	table := []struct{ a, d, alpha float64 }{
+		{0, 162.5, math.Pi / 2},
+		{-425, 0, 0},
+		{-392.2, 0, 0},
+		{0, 133.3, math.Pi / 2},
+		{0, 99.7, -math.Pi / 2},
+		{0, 99.6, 0},
+	}
+	standard := make([]DHParameters, 0, len(table))
+	modified := make([]DHParameters, 0, len(table))
+	for i, row := range table {
+		limit := Limit{Min: -2 * math.Pi, Max: 2 * math.Pi}
+		standard = append(standard, DHParameters{A: row.a, D: row.d, Alpha: row.alpha, Limit: limit})
+		// in the modified convention each row carries the a and alpha of the row before it in the standard one
+		previous := DHParameters{}
+		if i > 0 {
+			previous = standard[i-1]
+		}
+		modified = append(modified, DHParameters{A: previous.A, D: row.d, Alpha: previous.Alpha, Limit: limit})
+	}
+	fromJSON, err := ParseModelJSONFile(utils.ResolveFile("referenceframe/testjson/ur5eDH.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fromStandard, err := NewModelFromDH("ur5e", StandardDH, standard)
+	test.That(t, err, test.ShouldBeNil)
+	fromModified, err := NewModelFromDH("ur5e", ModifiedDH, modified)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(fromStandard.DoF()), test.ShouldEqual, 6)
+	test.That(t, len(fromModified.DoF()), test.ShouldEqual, 6)
+
+	randSeed := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		inputs := FloatsToInputs(GenerateRandomConfiguration(fromJSON, randSeed))
+		expected, err := fromJSON.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		pose, err := fromStandard.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.PoseAlmostEqual(pose, expected), test.ShouldBeTrue)
+		pose, err = fromModified.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.PoseAlmostEqual(pose, expected), test.ShouldBeTrue)
+	}
+
+	// models built from DH parameters serialize like any other
+	data, err := fromModified.MarshalJSON()
+	test.That(t, err, test.ShouldBeNil)
+	model, err := UnmarshalModelJSON(data, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model.AlmostEquals(fromModified), test.ShouldBeTrue)
+
+	// positions are measured from the offsets in the table
+	offsets := []DHParameters{
+		{A: 100, Theta: math.Pi / 2, Limit: Limit{Min: -math.Pi, Max: math.Pi}},
+		{D: 10, Prismatic: true, Limit: Limit{Min: 0, Max: 100}, Dynamics: DynamicLimit{MaxVelocity: 50}},
+	}
+	model, err = NewModelFromDH("offsets", StandardDH, offsets)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, model.DoF(), test.ShouldResemble, []Limit{{Min: -math.Pi, Max: math.Pi}, {Min: 0, Max: 100}})
+	pose, err := model.Transform(FloatsToInputs([]float64{0, 50}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(pose.Point(), r3.Vector{Y: 100, Z: 60}, 1e-8), test.ShouldBeTrue)
+	test.That(t, DynamicLimitsOf(model.(*SimpleModel).OrdTransforms[2]), test.ShouldResemble, []DynamicLimit{{MaxVelocity: 50}})
+
+	_, err = NewModelFromDH("bad", DHConvention("sideways"), standard)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewModelFromDH("bad", StandardDH, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,24 @@
+package referenceframe
+
+// DynamicLimit represents how fast a degree of freedom of a frame can move, in input units (radians or mm) per second, and per
+// second squared. Zero means there is no limit.
+type DynamicLimit struct {
+	MaxVelocity     float64
+	MaxAcceleration float64
+}
+
+// DynamicFrame is a frame that knows how fast its degrees of freedom can move.
+type DynamicFrame interface {
+	Frame
+
+	// DynamicLimits returns a slice with the same length and order as DoF, describing how fast each degree of freedom can move.
+	DynamicLimits() []DynamicLimit
+}
+
+// DynamicLimitsOf returns how fast each degree of freedom of a frame can move, which is unlimited for frames that do not know.
+func DynamicLimitsOf(f Frame) []DynamicLimit {
+	if df, ok := f.(DynamicFrame); ok {
+		return df.DynamicLimits()
+	}
+	return make([]DynamicLimit, len(f.DoF()))
+}
@@ -126,8 +126,9 @@ type Frame interface {
 
 // baseFrame contains all the data and methods common to all frames, notably it does not implement the Frame interface itself.
 type baseFrame struct {
-	name   string
-	limits []Limit
+	name     string
+	limits   []Limit
+	dynamics []DynamicLimit
 }
 
 // Name returns the name of the referenceframe.
@@ -140,6 +141,14 @@ func (bf *baseFrame) DoF() []Limit {
 	return bf.limits
 }
 
+// DynamicLimits returns how fast each degree of freedom can move, which is unlimited unless the frame was given dynamic limits.
+func (bf *baseFrame) DynamicLimits() []DynamicLimit {
+	if len(bf.dynamics) == len(bf.limits) {
+		return bf.dynamics
+	}
+	return make([]DynamicLimit, len(bf.limits))
+}
+
 // validInputs checks whether the given array of joint positions violates any joint limits.
 func (bf *baseFrame) validInputs(inputs []Input) error {
 	var errAll error
@@ -201,12 +210,12 @@ func NewStaticFrame(name string, pose spatial.Pose) (Frame, error) {
 	if pose == nil {
 		return nil, errors.New("pose is not allowed to be nil")
 	}
-	return &staticFrame{&baseFrame{name, []Limit{}}, pose, nil}, nil
+	return &staticFrame{&baseFrame{name: name, limits: []Limit{}}, pose, nil}, nil
 }
 
 // NewZeroStaticFrame creates a frame with no translation or orientation changes.
 func NewZeroStaticFrame(name string) Frame {
-	return &staticFrame{&baseFrame{name, []Limit{}}, spatial.NewZeroPose(), nil}
+	return &staticFrame{&baseFrame{name: name, limits: []Limit{}}, spatial.NewZeroPose(), nil}
 }
 
 // NewStaticFrameWithGeometry creates a frame given a pose relative to its parent.  The pose is fixed for all time.
@@ -215,7 +224,7 @@ func NewStaticFrameWithGeometry(name string, pose spatial.Pose, geometry spatial
 	if pose == nil {
 		return nil, errors.New("pose is not allowed to be nil")
 	}
-	return &staticFrame{&baseFrame{name, []Limit{}}, pose, geometry}, nil
+	return &staticFrame{&baseFrame{name: name, limits: []Limit{}}, pose, geometry}, nil
 }
 
 // NewStaticFrameFromFrame creates a frame given a pose relative to its parent.  The pose is fixed for all time.
@@ -376,6 +385,9 @@ func (pf *translationalFrame) MarshalJSON() ([]byte, error) {
 		Max:  pf.limits[0].Max,
 		Min:  pf.limits[0].Min,
 	}
+	if dynamics := pf.DynamicLimits(); len(dynamics) == 1 {
+		temp.MaxVel, temp.MaxAcc = dynamics[0].MaxVelocity, dynamics[0].MaxAcceleration
+	}
 	if pf.geometry != nil {
 		var err error
 		temp.Geometry, err = spatial.NewGeometryConfig(pf.geometry)
@@ -400,6 +412,9 @@ type rotationalFrame struct {
 // NewRotationalFrame creates a new rotationalFrame struct.
 // A standard revolute joint will have 1 DoF.
 func NewRotationalFrame(name string, axis spatial.R4AA, limit Limit) (Frame, error) {
+	if spatial.R3VectorAlmostEqual(r3.Vector{}, r3.Vector{axis.RX, axis.RY, axis.RZ}, 1e-8) {
+		return nil, errors.New("cannot use zero vector as rotation axis")
+	}
 	axis.Normalize()
 	return &rotationalFrame{
 		baseFrame: &baseFrame{name: name, limits: []Limit{limit}},
@@ -459,6 +474,9 @@ func (rf *rotationalFrame) MarshalJSON() ([]byte, error) {
 		Max:  utils.RadToDeg(rf.limits[0].Max),
 		Min:  utils.RadToDeg(rf.limits[0].Min),
 	}
+	if dynamics := rf.DynamicLimits(); len(dynamics) == 1 {
+		temp.MaxVel, temp.MaxAcc = utils.RadToDeg(dynamics[0].MaxVelocity), utils.RadToDeg(dynamics[0].MaxAcceleration)
+	}
 
 	return json.Marshal(temp)
 }
@@ -476,20 +494,33 @@ type mobile2DFrame struct {
 // NewMobile2DFrame instantiates a frame that can translate in the x and y dimensions and will always remain on the plane Z=0
 // This frame will have a name, limits (representing the bounds the frame is allowed to translate within) and a geometry
 // defined by the arguments passed into this function.
+// If a third limit is given, the frame can also turn about the Z axis, and its third input is its heading in radians, measured
+// counterclockwise from the X axis. This is needed to describe bases that can only drive in the direction they are facing.
 func NewMobile2DFrame(name string, limits []Limit, geometry spatial.Geometry) (Frame, error) {
-	if len(limits) != 2 {
-		return nil, fmt.Errorf("cannot create a %d dof mobile frame, only support 2 dimensions currently", len(limits))
+	if len(limits) != 2 && len(limits) != 3 {
+		return nil, fmt.Errorf("cannot create a %d dof mobile frame, only support 2 dimensions and a heading currently", len(limits))
 	}
 	return &mobile2DFrame{baseFrame: &baseFrame{name: name, limits: limits}, geometry: geometry}, nil
 }
 
+// IsMobile2DFrameWithHeading returns whether a frame is a mobile 2D frame that can also turn, whose inputs are its position and its
+// heading, as created by NewMobile2DFrame with three limits.
+func IsMobile2DFrameWithHeading(frame Frame) bool {
+	mf, ok := frame.(*mobile2DFrame)
+	return ok && len(mf.limits) == 3
+}
+
 func (mf *mobile2DFrame) Transform(input []Input) (spatial.Pose, error) {
 	err := mf.validInputs(input)
 	// We allow out-of-bounds calculations, but will return a non-nil error
 	if err != nil && !strings.Contains(err.Error(), OOBErrString) {
 		return nil, err
 	}
-	return spatial.NewPoseFromPoint(r3.Vector{input[0].Value, input[1].Value, 0}), err
+	pt := r3.Vector{input[0].Value, input[1].Value, 0}
+	if len(input) == 3 {
+		return spatial.NewPose(pt, &spatial.EulerAngles{Yaw: input[2].Value}), err
+	}
+	return spatial.NewPoseFromPoint(pt), err
 }
 
 // InputFromProtobuf converts pb.JointPosition to inputs.
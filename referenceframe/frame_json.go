@@ -13,6 +13,7 @@ const (
 	ContinuousJoint = "continuous"
 	PrismaticJoint  = "prismatic"
 	RevoluteJoint   = "revolute"
+	PlanarJoint     = "planar"
 )
 
 // LinkConfig is a StaticFrame that also has a specified parent.
@@ -22,6 +23,13 @@ type LinkConfig struct {
 	Orientation *spatial.OrientationConfig `json:"orientation"`
 	Geometry    *spatial.GeometryConfig    `json:"geometry,omitempty"`
 	Parent      string                     `json:"parent,omitempty"`
+
+	// pairs of geometries which may touch without motion planning treating it as a collision
+	AllowedCollisions []AllowedCollision `json:"allowed_collisions,omitempty"`
+
+	// tags and metadata describing the frame, such as "camera", by which services can find it in the frame system
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // JointConfig is a frame with nonzero DOF. Supports rotational or translational.
@@ -32,6 +40,8 @@ type JointConfig struct {
 	Axis     spatial.AxisConfig      `json:"axis"`
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
+	MaxVel   float64                 `json:"max_vel,omitempty"`  // in mm/s or degs/s, unlimited if 0
+	MaxAcc   float64                 `json:"max_acc,omitempty"`  // in mm/s^2 or degs/s^2, unlimited if 0
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
 }
 
@@ -42,8 +52,10 @@ type DHParamConfig struct {
 	A        float64                 `json:"a"`
 	D        float64                 `json:"d"`
 	Alpha    float64                 `json:"alpha"`
-	Max      float64                 `json:"max"` // in mm or degs
-	Min      float64                 `json:"min"` // in mm or degs
+	Max      float64                 `json:"max"`               // in mm or degs
+	Min      float64                 `json:"min"`               // in mm or degs
+	MaxVel   float64                 `json:"max_vel,omitempty"` // in degs/s, unlimited if 0
+	MaxAcc   float64                 `json:"max_acc,omitempty"` // in degs/s^2, unlimited if 0
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"`
 }
 
@@ -81,7 +93,11 @@ func (cfg *LinkConfig) ParseConfig() (*LinkInFrame, error) {
 			return nil, err
 		}
 	}
-	return NewLinkInFrame(cfg.Parent, pose, cfg.ID, geom), nil
+	link := NewLinkInFrame(cfg.Parent, pose, cfg.ID, geom)
+	link.allowedCollisions = cfg.AllowedCollisions
+	link.tags = cfg.Tags
+	link.metadata = cfg.Metadata
+	return link, nil
 }
 
 // Pose will parse out the Pose of a LinkConfig and return it if it is valid.
@@ -101,16 +117,34 @@ func (cfg *LinkConfig) Pose() (spatial.Pose, error) {
 func (cfg *JointConfig) ToFrame() (Frame, error) {
 	switch cfg.Type {
 	case RevoluteJoint:
-		return NewRotationalFrame(cfg.ID, cfg.Axis.ParseConfig(),
+		f, err := NewRotationalFrame(cfg.ID, cfg.Axis.ParseConfig(),
 			Limit{Min: utils.DegToRad(cfg.Min), Max: utils.DegToRad(cfg.Max)})
+		if err != nil {
+			return nil, err
+		}
+		f.(*rotationalFrame).dynamics = dynamicLimitsFromConfig(utils.DegToRad(cfg.MaxVel), utils.DegToRad(cfg.MaxAcc))
+		return f, nil
 	case PrismaticJoint:
-		return NewTranslationalFrame(cfg.ID, r3.Vector(cfg.Axis),
+		f, err := NewTranslationalFrame(cfg.ID, r3.Vector(cfg.Axis),
 			Limit{Min: cfg.Min, Max: cfg.Max})
+		if err != nil {
+			return nil, err
+		}
+		f.(*translationalFrame).dynamics = dynamicLimitsFromConfig(cfg.MaxVel, cfg.MaxAcc)
+		return f, nil
 	default:
 		return nil, NewUnsupportedJointTypeError(cfg.Type)
 	}
 }
 
+// dynamicLimitsFromConfig returns the dynamic limits of a single joint, or nil if the config does not limit it.
+func dynamicLimitsFromConfig(maxVel, maxAcc float64) []DynamicLimit {
+	if maxVel == 0 && maxAcc == 0 {
+		return nil
+	}
+	return []DynamicLimit{{MaxVelocity: maxVel, MaxAcceleration: maxAcc}}
+}
+
 // ToDHFrames converts a DHParamConfig into a joint frame and a link frame.
 func (cfg *DHParamConfig) ToDHFrames() (Frame, Frame, error) {
 	jointID := cfg.ID + "_j"
@@ -119,6 +153,7 @@ func (cfg *DHParamConfig) ToDHFrames() (Frame, Frame, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	rFrame.(*rotationalFrame).dynamics = dynamicLimitsFromConfig(utils.DegToRad(cfg.MaxVel), utils.DegToRad(cfg.MaxAcc))
 
 	// Link part of DH param
 	linkID := cfg.ID
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/golang/geo/r3"
@@ -51,6 +52,10 @@ type FrameSystem interface {
 	// is a map of inputs for any frames with non-zero DOF, with slices of inputs keyed to the frame name.
 	Transform(positions map[string][]Input, object Transformable, dst string) (Transformable, error)
 
+	// TransformAt is as Transform, with any TimeVaryingFrames in the frame system placed as they were at the given time rather than at
+	// the current time, so that the world can be queried at a specific instant.
+	TransformAt(positions map[string][]Input, object Transformable, dst string, timestamp time.Time) (Transformable, error)
+
 	// FrameSystemSubset will take a frame system and a frame in that system, and return a new frame system rooted
 	// at the given frame and containing all descendents of it. The original frame system is unchanged.
 	FrameSystemSubset(newRoot Frame) (FrameSystem, error)
@@ -62,6 +67,21 @@ type FrameSystem interface {
 
 	// MergeFrameSystem combines two frame systems together, placing the world of systemToMerge at the attachTo frame in the frame system
 	MergeFrameSystem(systemToMerge FrameSystem, attachTo Frame) error
+
+	// SetFrameTags replaces the tags of the named frame, arbitrary strings such as ToolTag describing what it is
+	SetFrameTags(name string, tags ...string) error
+
+	// FrameTags returns the sorted tags of the named frame
+	FrameTags(name string) []string
+
+	// FramesWithTag returns the sorted names of the frames with the given tag, so that frames can be found by what they are
+	FramesWithTag(tag string) []string
+
+	// SetFrameMetadata replaces the metadata of the named frame, arbitrary key value pairs describing it
+	SetFrameMetadata(name string, metadata map[string]string) error
+
+	// FrameMetadata returns the metadata of the named frame
+	FrameMetadata(name string) map[string]string
 }
 
 // FrameSystemPart is used to collect all the info need from a named robot part to build the frame node in a frame system.
@@ -78,12 +98,16 @@ type simpleFrameSystem struct {
 	world   Frame // separate from the map of frames so it can be detached easily
 	frames  map[string]Frame
 	parents map[Frame]Frame
+
+	// tags and metadata of frames, by name
+	tags     map[string][]string
+	metadata map[string]map[string]string
 }
 
 // NewEmptySimpleFrameSystem creates a graph of Frames that have.
 func NewEmptySimpleFrameSystem(name string) FrameSystem {
 	worldFrame := NewZeroStaticFrame(World)
-	return &simpleFrameSystem{name, worldFrame, map[string]Frame{}, map[Frame]Frame{}}
+	return &simpleFrameSystem{name: name, world: worldFrame, frames: map[string]Frame{}, parents: map[Frame]Frame{}}
 }
 
 // World returns the base world referenceframe.
@@ -117,6 +141,8 @@ func (sfs *simpleFrameSystem) frameExists(name string) bool {
 func (sfs *simpleFrameSystem) RemoveFrame(frame Frame) {
 	delete(sfs.frames, frame.Name())
 	delete(sfs.parents, frame)
+	delete(sfs.tags, frame.Name())
+	delete(sfs.metadata, frame.Name())
 
 	// Remove all descendents
 	for f, parent := range sfs.parents {
@@ -185,8 +211,20 @@ func (sfs *simpleFrameSystem) AddFrame(frame, parent Frame) error {
 }
 
 // Transform takes in a Transformable object and destination frame, and returns the pose from the first to the second. Positions
-// is a map of inputs for any frames with non-zero DOF, with slices of inputs keyed to the frame name.
+// is a map of inputs for any frames with non-zero DOF, with slices of inputs keyed to the frame name. Any TimeVaryingFrames are all
+// placed as they are at the same, current, time.
 func (sfs *simpleFrameSystem) Transform(positions map[string][]Input, object Transformable, dst string) (Transformable, error) {
+	return sfs.TransformAt(positions, object, dst, time.Now())
+}
+
+// TransformAt takes in a Transformable object and destination frame, and returns the pose from the first to the second, with any
+// TimeVaryingFrames placed as they were at the given time.
+func (sfs *simpleFrameSystem) TransformAt(
+	positions map[string][]Input,
+	object Transformable,
+	dst string,
+	timestamp time.Time,
+) (Transformable, error) {
 	src := object.Parent()
 	if src == dst {
 		return object, nil
@@ -207,9 +245,9 @@ func (sfs *simpleFrameSystem) Transform(positions map[string][]Input, object Tra
 		// A frame is assigned a pose and a geometry and the two are not coupled together. This way you do can define everything relative
 		// to the parent frame. So geometries are tied to the frame they are assigned to but we do not want to actually transform them
 		// along the final transformation.
-		tfParent, err = sfs.transformFromParent(positions, sfs.parents[srcFrame], sfs.Frame(dst))
+		tfParent, err = sfs.transformFromParent(positions, sfs.parents[srcFrame], sfs.Frame(dst), timestamp)
 	} else {
-		tfParent, err = sfs.transformFromParent(positions, srcFrame, sfs.Frame(dst))
+		tfParent, err = sfs.transformFromParent(positions, srcFrame, sfs.Frame(dst), timestamp)
 	}
 	if err != nil {
 		return nil, err
@@ -268,14 +306,14 @@ func (sfs *simpleFrameSystem) MergeFrameSystem(systemToMerge FrameSystem, attach
 			}
 		}
 	}
-	return nil
+	return copyFrameTagsAndMetadata(systemToMerge, sfs, systemToMerge.FrameNames())
 }
 
 // FrameSystemSubset will take a frame system and a frame in that system, and return a new frame system rooted
 // at the given frame and containing all descendents of it. The original frame system is unchanged.
 func (sfs *simpleFrameSystem) FrameSystemSubset(newRoot Frame) (FrameSystem, error) {
 	newWorld := NewZeroStaticFrame(World)
-	newFS := &simpleFrameSystem{newRoot.Name() + "_FS", newWorld, map[string]Frame{}, map[Frame]Frame{}}
+	newFS := &simpleFrameSystem{name: newRoot.Name() + "_FS", world: newWorld, frames: map[string]Frame{}, parents: map[Frame]Frame{}}
 
 	rootFrame := sfs.Frame(newRoot.Name())
 	if rootFrame == nil {
@@ -309,6 +347,9 @@ func (sfs *simpleFrameSystem) FrameSystemSubset(newRoot Frame) (FrameSystem, err
 			newFS.parents[frame] = parent
 		}
 	}
+	if err := copyFrameTagsAndMetadata(sfs, newFS, newFS.FrameNames()); err != nil {
+		return nil, err
+	}
 
 	return newFS, nil
 }
@@ -384,7 +425,7 @@ func (sfs *simpleFrameSystem) DivideFrameSystem(newRoot Frame) (FrameSystem, err
 	return newFS, nil
 }
 
-func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Input, src Frame) (spatial.Pose, error) {
+func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Input, src Frame, timestamp time.Time) (spatial.Pose, error) {
 	if !sfs.frameExists(src.Name()) {
 		return nil, NewFrameMissingError(src.Name())
 	}
@@ -393,7 +434,7 @@ func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Inp
 	var err error
 	srcToWorld := spatial.NewZeroPose()
 	if src != nil {
-		srcToWorld, err = sfs.composeTransforms(src, inputMap)
+		srcToWorld, err = sfs.composeTransforms(src, inputMap, timestamp)
 		if err != nil && srcToWorld == nil {
 			return nil, err
 		}
@@ -402,12 +443,12 @@ func (sfs *simpleFrameSystem) getFrameToWorldTransform(inputMap map[string][]Inp
 }
 
 // Returns the relative pose between the parent and the destination frame.
-func (sfs *simpleFrameSystem) transformFromParent(inputMap map[string][]Input, src, dst Frame) (*PoseInFrame, error) {
+func (sfs *simpleFrameSystem) transformFromParent(inputMap map[string][]Input, src, dst Frame, timestamp time.Time) (*PoseInFrame, error) {
 	// catch all errors together to allow for hypothetical calculations that result in errors
 	var errAll error
-	dstToWorld, err := sfs.getFrameToWorldTransform(inputMap, dst)
+	dstToWorld, err := sfs.getFrameToWorldTransform(inputMap, dst, timestamp)
 	multierr.AppendInto(&errAll, err)
-	srcToWorld, err := sfs.getFrameToWorldTransform(inputMap, src)
+	srcToWorld, err := sfs.getFrameToWorldTransform(inputMap, src, timestamp)
 	multierr.AppendInto(&errAll, err)
 	if errAll != nil && (dstToWorld == nil || srcToWorld == nil) {
 		return nil, errAll
@@ -418,12 +459,12 @@ func (sfs *simpleFrameSystem) transformFromParent(inputMap map[string][]Input, s
 }
 
 // compose the quaternions from the input frame to the world referenceframe.
-func (sfs *simpleFrameSystem) composeTransforms(frame Frame, inputMap map[string][]Input) (spatial.Pose, error) {
+func (sfs *simpleFrameSystem) composeTransforms(frame Frame, inputMap map[string][]Input, timestamp time.Time) (spatial.Pose, error) {
 	q := spatial.NewZeroPose() // empty initial dualquat
 	var errAll error
 	for sfs.parents[frame] != nil { // stop once you reach world node
 		// Transform() gives FROM q TO parent. Add new transforms to the left.
-		pose, err := poseFromPositions(frame, inputMap)
+		pose, err := poseFromPositions(frame, inputMap, timestamp)
 		if err != nil && pose == nil {
 			return nil, err
 		}
@@ -554,10 +595,18 @@ func CreateFramesFromPart(part *FrameSystemPart, logger golog.Logger) (Frame, Fr
 
 	// Since the geometry of a frame system part is intended to be located at the origin of the model frame, we place it post-transform
 	// in the "_origin" static frame
-	return modelFrame, &tailGeometryStaticFrame{staticOriginFrame}, nil
+	staticOffsetFrame := Frame(&tailGeometryStaticFrame{staticOriginFrame})
+	if allowed := part.FrameConfig.AllowedCollisions(); len(allowed) > 0 {
+		// the frame system declares the allowed collisions of the part, so that motion planning finds them among its frames
+		staticOffsetFrame = &collisionAllowingFrame{Frame: staticOffsetFrame, allowed: allowed}
+	}
+	return modelFrame, staticOffsetFrame, nil
 }
 
-func poseFromPositions(frame Frame, positions map[string][]Input) (spatial.Pose, error) {
+func poseFromPositions(frame Frame, positions map[string][]Input, timestamp time.Time) (spatial.Pose, error) {
+	if tvf, ok := frame.(TimeVaryingFrame); ok {
+		return tvf.TransformAt(timestamp)
+	}
 	inputs, err := GetFrameInputs(frame, positions)
 	if err != nil {
 		return nil, err
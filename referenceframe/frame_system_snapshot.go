@@ -0,0 +1,305 @@
+package referenceframe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/golang/geo/r3"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// The following are the ways a frame can differ between two frame systems.
+const (
+	FrameAdded    = "added"
+	FrameRemoved  = "removed"
+	FrameModified = "modified"
+)
+
+// FrameSystemSnapshot is a canonical description of a frame system, which can be serialized to JSON to audit changes to the
+// configuration of a robot or to check a frame setup into version control. Its frames are ordered by name.
+type FrameSystemSnapshot struct {
+	Name   string          `json:"name"`
+	Frames []FrameSnapshot `json:"frames"`
+}
+
+// FrameSnapshot describes a frame of a frame system: where it is attached, how it is placed and can move relative to its parent, and
+// the geometries it occupies. Its pose and geometries are those at zero inputs, relative to its parent.
+type FrameSnapshot struct {
+	Name              string                             `json:"name"`
+	Parent            string                             `json:"parent"`
+	Type              string                             `json:"type"`
+	Translation       r3.Vector                          `json:"translation"`
+	Orientation       *spatial.OrientationConfig         `json:"orientation"`
+	Limits            []LimitSnapshot                    `json:"limits,omitempty"`
+	Geometries        map[string]*spatial.GeometryConfig `json:"geometries,omitempty"`
+	AllowedCollisions []AllowedCollision                 `json:"allowed_collisions,omitempty"`
+	Tags              []string                           `json:"tags,omitempty"`
+	Metadata          map[string]string                  `json:"metadata,omitempty"`
+	// Kinematics is the kinematics JSON of a model, describing the frames within it
+	Kinematics json.RawMessage `json:"kinematics,omitempty"`
+}
+
+// LimitSnapshot is the limit of an input of a frame, with unbounded sides left out as JSON has no infinities.
+type LimitSnapshot struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// FrameDiff describes how a frame differs between two frame systems.
+type FrameDiff struct {
+	Name string `json:"name"`
+	// Change is one of FrameAdded, FrameRemoved or FrameModified
+	Change string `json:"change"`
+	// Fields are the JSON names of the fields of the FrameSnapshot of a modified frame which differ
+	Fields []string `json:"fields,omitempty"`
+}
+
+// String returns a human readable description of the difference.
+func (d FrameDiff) String() string {
+	if d.Change == FrameModified {
+		return fmt.Sprintf("%s %s: %v", d.Change, d.Name, d.Fields)
+	}
+	return fmt.Sprintf("%s %s", d.Change, d.Name)
+}
+
+// NewFrameSystemSnapshot returns the canonical description of a frame system.
+func NewFrameSystemSnapshot(fs FrameSystem) (*FrameSystemSnapshot, error) {
+	names := fs.FrameNames()
+	sort.Strings(names)
+	snapshot := &FrameSystemSnapshot{Name: fs.Name(), Frames: make([]FrameSnapshot, 0, len(names))}
+	for _, name := range names {
+		frameSnapshot, err := newFrameSnapshot(fs, fs.Frame(name))
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Frames = append(snapshot.Frames, *frameSnapshot)
+	}
+	return snapshot, nil
+}
+
+// FrameSystemToJSON serializes a frame system to the indented JSON of its canonical description, so that the same frame system always
+// serializes to the same bytes.
+func FrameSystemToJSON(fs FrameSystem) ([]byte, error) {
+	snapshot, err := NewFrameSystemSnapshot(fs)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(snapshot, "", "  ")
+}
+
+// DiffFrameSystems returns how the frames of two frame systems differ, going from the first to the second.
+func DiffFrameSystems(from, to FrameSystem) ([]FrameDiff, error) {
+	fromSnapshot, err := NewFrameSystemSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+	toSnapshot, err := NewFrameSystemSnapshot(to)
+	if err != nil {
+		return nil, err
+	}
+	return fromSnapshot.Diff(toSnapshot), nil
+}
+
+// Diff returns how the frames of two snapshots differ, going from this one to the other, ordered by name. Poses and geometries which
+// differ only by floating point imprecision are treated as the same.
+func (s *FrameSystemSnapshot) Diff(other *FrameSystemSnapshot) []FrameDiff {
+	fromFrames := make(map[string]*FrameSnapshot, len(s.Frames))
+	for i := range s.Frames {
+		fromFrames[s.Frames[i].Name] = &s.Frames[i]
+	}
+	toFrames := make(map[string]*FrameSnapshot, len(other.Frames))
+	for i := range other.Frames {
+		toFrames[other.Frames[i].Name] = &other.Frames[i]
+	}
+
+	var diffs []FrameDiff
+	for name, from := range fromFrames {
+		to, ok := toFrames[name]
+		if !ok {
+			diffs = append(diffs, FrameDiff{Name: name, Change: FrameRemoved})
+			continue
+		}
+		if fields := from.diff(to); len(fields) > 0 {
+			diffs = append(diffs, FrameDiff{Name: name, Change: FrameModified, Fields: fields})
+		}
+	}
+	for name := range toFrames {
+		if _, ok := fromFrames[name]; !ok {
+			diffs = append(diffs, FrameDiff{Name: name, Change: FrameAdded})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// diff returns the JSON names of the fields of two snapshots of a frame which differ.
+func (f *FrameSnapshot) diff(other *FrameSnapshot) []string {
+	var fields []string
+	if f.Parent != other.Parent {
+		fields = append(fields, "parent")
+	}
+	if f.Type != other.Type {
+		fields = append(fields, "type")
+	}
+	pose, err := f.pose()
+	otherPose, otherErr := other.pose()
+	if err != nil || otherErr != nil {
+		if f.Translation != other.Translation || !reflect.DeepEqual(f.Orientation, other.Orientation) {
+			fields = append(fields, "pose")
+		}
+	} else if !spatial.PoseAlmostEqual(pose, otherPose) {
+		fields = append(fields, "pose")
+	}
+	if !reflect.DeepEqual(f.Limits, other.Limits) {
+		fields = append(fields, "limits")
+	}
+	if !geometryConfigsAlmostEqual(f.Geometries, other.Geometries) {
+		fields = append(fields, "geometries")
+	}
+	if !reflect.DeepEqual(f.AllowedCollisions, other.AllowedCollisions) {
+		fields = append(fields, "allowed_collisions")
+	}
+	if !reflect.DeepEqual(f.Tags, other.Tags) {
+		fields = append(fields, "tags")
+	}
+	if !reflect.DeepEqual(f.Metadata, other.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	if !jsonEqual(f.Kinematics, other.Kinematics) {
+		fields = append(fields, "kinematics")
+	}
+	return fields
+}
+
+func (f *FrameSnapshot) pose() (spatial.Pose, error) {
+	if f.Orientation == nil {
+		return spatial.NewPoseFromPoint(f.Translation), nil
+	}
+	orientation, err := f.Orientation.ParseConfig()
+	if err != nil {
+		return nil, err
+	}
+	return spatial.NewPose(f.Translation, orientation), nil
+}
+
+func newFrameSnapshot(fs FrameSystem, f Frame) (*FrameSnapshot, error) {
+	parent, err := fs.Parent(f)
+	if err != nil {
+		return nil, err
+	}
+	inputs := make([]Input, len(f.DoF()))
+	pose, err := f.Transform(inputs)
+	if pose == nil {
+		return nil, err
+	}
+	orientation, err := spatial.NewOrientationConfig(pose.Orientation())
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &FrameSnapshot{
+		Name:              f.Name(),
+		Parent:            parent.Name(),
+		Type:              frameType(f),
+		Translation:       pose.Point(),
+		Orientation:       orientation,
+		AllowedCollisions: AllowedCollisionsOf(f),
+		Tags:              fs.FrameTags(f.Name()),
+		Metadata:          fs.FrameMetadata(f.Name()),
+	}
+	for _, limit := range f.DoF() {
+		snapshot.Limits = append(snapshot.Limits, newLimitSnapshot(limit))
+	}
+
+	// frames without geometries may return an error rather than an empty set of them
+	geometries, _ := f.Geometries(inputs)
+	if geometries != nil && len(geometries.Geometries()) > 0 {
+		snapshot.Geometries = make(map[string]*spatial.GeometryConfig, len(geometries.Geometries()))
+		for name, geometry := range geometries.Geometries() {
+			snapshot.Geometries[name], err = spatial.NewGeometryConfig(geometry)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if model, ok := f.(*SimpleModel); ok && model.ModelConfig() != nil {
+		snapshot.Kinematics, err = model.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// frameType returns the kind of a frame, by the name of the joint moving it if it is a single joint.
+func frameType(f Frame) string {
+	if wrapped, ok := f.(*collisionAllowingFrame); ok {
+		f = wrapped.Frame
+	}
+	switch f.(type) {
+	case *SimpleModel:
+		return "model"
+	case *rotationalFrame:
+		return RevoluteJoint
+	case *translationalFrame:
+		return PrismaticJoint
+	case *mobile2DFrame:
+		return PlanarJoint
+	default:
+		if len(f.DoF()) == 0 {
+			return FixedJoint
+		}
+		return fmt.Sprintf("%T", f)
+	}
+}
+
+func newLimitSnapshot(limit Limit) LimitSnapshot {
+	var snapshot LimitSnapshot
+	if !math.IsInf(limit.Min, 0) {
+		snapshot.Min = &limit.Min
+	}
+	if !math.IsInf(limit.Max, 0) {
+		snapshot.Max = &limit.Max
+	}
+	return snapshot
+}
+
+// geometryConfigsAlmostEqual returns whether two sets of geometry configs describe the same geometries, up to floating point
+// imprecision. Configs which cannot be parsed are compared exactly.
+func geometryConfigsAlmostEqual(a, b map[string]*spatial.GeometryConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, aConfig := range a {
+		bConfig, ok := b[name]
+		if !ok {
+			return false
+		}
+		aGeometry, aErr := aConfig.ParseConfig()
+		bGeometry, bErr := bConfig.ParseConfig()
+		if aErr != nil || bErr != nil {
+			if !reflect.DeepEqual(aConfig, bConfig) {
+				return false
+			}
+			continue
+		}
+		if !aGeometry.AlmostEqual(bGeometry) {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonEqual returns whether two JSON documents are the same, regardless of their whitespace.
+func jsonEqual(a, b json.RawMessage) bool {
+	var aCompact, bCompact bytes.Buffer
+	if json.Compact(&aCompact, a) != nil || json.Compact(&bCompact, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(aCompact.Bytes(), bCompact.Bytes())
+}
@@ -0,0 +1,76 @@
+package referenceframe
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestFrameSystemSnapshot(t *testing.T) {
+	makeFrameSystem := func(baseZ, markerSize float64, withCamera bool) FrameSystem {
+		fs := NewEmptySimpleFrameSystem("test")
+		box, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 100, Y: 100, Z: 100}, "")
+		test.That(t, err, test.ShouldBeNil)
+		base, err := NewMobile2DFrame("base", []Limit{{-1000, 1000}, {-1000, 1000}, {math.Inf(-1), math.Inf(1)}}, box)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(base, fs.World()), test.ShouldBeNil)
+		mount, err := NewStaticFrame("mount", spatial.NewPoseFromPoint(r3.Vector{Z: baseZ}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(mount, base), test.ShouldBeNil)
+		arm, err := ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "arm")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(arm, mount), test.ShouldBeNil)
+		markerBox, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: markerSize, Y: markerSize, Z: markerSize}, "")
+		test.That(t, err, test.ShouldBeNil)
+		marker, err := NewStaticFrameWithGeometry("marker", spatial.NewPoseFromPoint(r3.Vector{X: 50}), markerBox)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(marker, arm), test.ShouldBeNil)
+		if withCamera {
+			camera, err := NewStaticFrame("camera", spatial.NewPoseFromPoint(r3.Vector{Y: 20}))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, fs.AddFrame(camera, arm), test.ShouldBeNil)
+		}
+		return fs
+	}
+
+	fs := makeFrameSystem(200, 10, false)
+	data, err := FrameSystemToJSON(fs)
+	test.That(t, err, test.ShouldBeNil)
+	again, err := FrameSystemToJSON(makeFrameSystem(200, 10, false))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(again), test.ShouldEqual, string(data))
+
+	var snapshot FrameSystemSnapshot
+	test.That(t, json.Unmarshal(data, &snapshot), test.ShouldBeNil)
+	test.That(t, snapshot.Name, test.ShouldEqual, "test")
+	test.That(t, len(snapshot.Frames), test.ShouldEqual, 4)
+	test.That(t, snapshot.Frames[1].Name, test.ShouldEqual, "base")
+	test.That(t, snapshot.Frames[1].Type, test.ShouldEqual, PlanarJoint)
+	test.That(t, snapshot.Frames[1].Limits[2], test.ShouldResemble, LimitSnapshot{})
+	test.That(t, snapshot.Frames[0].Type, test.ShouldEqual, "model")
+	test.That(t, snapshot.Frames[0].Kinematics, test.ShouldNotBeEmpty)
+	test.That(t, snapshot.Frames[2].Geometries, test.ShouldContainKey, "marker")
+
+	// a snapshot read back from its JSON is the same as the frame system it was taken of
+	current, err := NewFrameSystemSnapshot(fs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, snapshot.Diff(current), test.ShouldBeEmpty)
+
+	diffs, err := DiffFrameSystems(fs, makeFrameSystem(250, 20, true))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diffs, test.ShouldResemble, []FrameDiff{
+		{Name: "camera", Change: FrameAdded},
+		{Name: "marker", Change: FrameModified, Fields: []string{"geometries"}},
+		{Name: "mount", Change: FrameModified, Fields: []string{"pose"}},
+	})
+	test.That(t, diffs[2].String(), test.ShouldEqual, "modified mount: [pose]")
+	diffs, err = DiffFrameSystems(makeFrameSystem(200, 10, true), fs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diffs, test.ShouldResemble, []FrameDiff{{Name: "camera", Change: FrameRemoved}})
+}
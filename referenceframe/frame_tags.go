@@ -0,0 +1,110 @@
+package referenceframe
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Tags describing common roles of frames, so that services can find the frames relevant to them without knowing their names. Any
+// other string may be used as a tag as well.
+const (
+	// ToolTag marks frames at the working end of an arm, such as the tip of a gripper or a tool mounted to it.
+	ToolTag = "tool"
+	// CameraTag marks frames at the optical center of a camera.
+	CameraTag = "camera"
+	// SafetyCriticalTag marks frames whose motion must be watched closely, such as ones carrying sharp or hot tools.
+	SafetyCriticalTag = "safety-critical"
+)
+
+// SetFrameTags replaces the tags of the named frame. Tags are arbitrary, non-empty strings such as ToolTag describing what a frame
+// is, and duplicates are dropped. Setting no tags removes those the frame had.
+func (sfs *simpleFrameSystem) SetFrameTags(name string, tags ...string) error {
+	if !sfs.frameExists(name) {
+		return NewFrameMissingError(name)
+	}
+	if len(tags) == 0 {
+		delete(sfs.tags, name)
+		return nil
+	}
+	set := make(map[string]bool, len(tags))
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag == "" {
+			return errors.Errorf("tags of frame %q cannot be empty", name)
+		}
+		if !set[tag] {
+			set[tag] = true
+			unique = append(unique, tag)
+		}
+	}
+	sort.Strings(unique)
+	if sfs.tags == nil {
+		sfs.tags = map[string][]string{}
+	}
+	sfs.tags[name] = unique
+	return nil
+}
+
+// FrameTags returns the sorted tags of the named frame, which is none for frames which are not in the frame system.
+func (sfs *simpleFrameSystem) FrameTags(name string) []string {
+	return append([]string(nil), sfs.tags[name]...)
+}
+
+// FramesWithTag returns the sorted names of the frames with the given tag.
+func (sfs *simpleFrameSystem) FramesWithTag(tag string) []string {
+	var names []string
+	for name, tags := range sfs.tags {
+		if i := sort.SearchStrings(tags, tag); i < len(tags) && tags[i] == tag {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetFrameMetadata replaces the metadata of the named frame, arbitrary key value pairs such as the model of a camera or the
+// serial number of a tool. Setting empty metadata removes that the frame had.
+func (sfs *simpleFrameSystem) SetFrameMetadata(name string, metadata map[string]string) error {
+	if !sfs.frameExists(name) {
+		return NewFrameMissingError(name)
+	}
+	if len(metadata) == 0 {
+		delete(sfs.metadata, name)
+		return nil
+	}
+	if sfs.metadata == nil {
+		sfs.metadata = map[string]map[string]string{}
+	}
+	sfs.metadata[name] = copyFrameMetadata(metadata)
+	return nil
+}
+
+// FrameMetadata returns a copy of the metadata of the named frame, which is nil if it has none.
+func (sfs *simpleFrameSystem) FrameMetadata(name string) map[string]string {
+	return copyFrameMetadata(sfs.metadata[name])
+}
+
+// copyFrameTagsAndMetadata sets the tags and metadata of frames in a frame system to those of the frames of the same names in another.
+func copyFrameTagsAndMetadata(from FrameSystem, to *simpleFrameSystem, names []string) error {
+	for _, name := range names {
+		if err := to.SetFrameTags(name, from.FrameTags(name)...); err != nil {
+			return err
+		}
+		if err := to.SetFrameMetadata(name, from.FrameMetadata(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFrameMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,91 @@
+package referenceframe
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestFrameTags(t *testing.T) {
+	fs := NewEmptySimpleFrameSystem("test")
+	addFrame := func(name string, parent Frame) Frame {
+		frame, err := NewStaticFrame(name, spatial.NewPoseFromPoint(r3.Vector{Z: 10}))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fs.AddFrame(frame, parent), test.ShouldBeNil)
+		return frame
+	}
+	base := addFrame("base", fs.World())
+	gripper := addFrame("gripper", base)
+	wristCam := addFrame("wrist_cam", gripper)
+	addFrame("overhead_cam", fs.World())
+
+	test.That(t, fs.SetFrameTags("gripper", ToolTag, SafetyCriticalTag, ToolTag), test.ShouldBeNil)
+	test.That(t, fs.SetFrameTags("wrist_cam", CameraTag), test.ShouldBeNil)
+	test.That(t, fs.SetFrameTags("overhead_cam", CameraTag, "fixed"), test.ShouldBeNil)
+	test.That(t, fs.FrameTags("gripper"), test.ShouldResemble, []string{SafetyCriticalTag, ToolTag})
+	test.That(t, fs.FrameTags("base"), test.ShouldBeEmpty)
+	test.That(t, fs.FramesWithTag(CameraTag), test.ShouldResemble, []string{"overhead_cam", "wrist_cam"})
+	test.That(t, fs.FramesWithTag("missing"), test.ShouldBeEmpty)
+
+	// tags returned are copies
+	fs.FrameTags("gripper")[0] = "changed"
+	test.That(t, fs.FramesWithTag(SafetyCriticalTag), test.ShouldResemble, []string{"gripper"})
+
+	test.That(t, fs.SetFrameTags("missing", ToolTag), test.ShouldNotBeNil)
+	test.That(t, fs.SetFrameTags("base", ""), test.ShouldNotBeNil)
+
+	metadata := map[string]string{"model": "d435", "serial": "1234"}
+	test.That(t, fs.SetFrameMetadata("wrist_cam", metadata), test.ShouldBeNil)
+	metadata["serial"] = "changed"
+	test.That(t, fs.FrameMetadata("wrist_cam"), test.ShouldResemble, map[string]string{"model": "d435", "serial": "1234"})
+	test.That(t, fs.FrameMetadata("base"), test.ShouldBeNil)
+	test.That(t, fs.SetFrameMetadata("missing", metadata), test.ShouldNotBeNil)
+
+	// tags and metadata are in snapshots of the frame system
+	snapshot, err := NewFrameSystemSnapshot(fs)
+	test.That(t, err, test.ShouldBeNil)
+	for _, frame := range snapshot.Frames {
+		test.That(t, frame.Tags, test.ShouldResemble, fs.FrameTags(frame.Name))
+	}
+
+	// tags move with frames into subsets and merged frame systems
+	subset, err := fs.FrameSystemSubset(gripper)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, subset.FramesWithTag(CameraTag), test.ShouldResemble, []string{"wrist_cam"})
+	test.That(t, subset.FrameMetadata("wrist_cam"), test.ShouldResemble, fs.FrameMetadata("wrist_cam"))
+	other := NewEmptySimpleFrameSystem("other")
+	rail, err := NewStaticFrame("rail", spatial.NewZeroPose())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, other.AddFrame(rail, other.World()), test.ShouldBeNil)
+	test.That(t, other.MergeFrameSystem(subset, rail), test.ShouldBeNil)
+	test.That(t, other.FrameTags("gripper"), test.ShouldResemble, []string{SafetyCriticalTag, ToolTag})
+	test.That(t, other.FrameMetadata("wrist_cam"), test.ShouldResemble, fs.FrameMetadata("wrist_cam"))
+
+	// removing frames removes their tags and metadata
+	fs.RemoveFrame(gripper)
+	test.That(t, fs.FramesWithTag(CameraTag), test.ShouldResemble, []string{"overhead_cam"})
+	test.That(t, fs.FrameMetadata(wristCam.Name()), test.ShouldBeNil)
+
+	// clearing tags removes them
+	test.That(t, fs.SetFrameTags("overhead_cam"), test.ShouldBeNil)
+	test.That(t, fs.FramesWithTag(CameraTag), test.ShouldBeEmpty)
+}
+
+func TestFrameTagsConfig(t *testing.T) {
+	var lc LinkConfig
+	err := json.Unmarshal([]byte(`{
+		"id": "cam",
+		"parent": "world",
+		"tags": ["camera"],
+		"metadata": {"model": "d435"}
+	}`), &lc)
+	test.That(t, err, test.ShouldBeNil)
+	lif, err := lc.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lif.Tags(), test.ShouldResemble, []string{CameraTag})
+	test.That(t, lif.Metadata(), test.ShouldResemble, map[string]string{"model": "d435"})
+}
@@ -84,8 +84,8 @@ func TestPrismaticFrame(t *testing.T) {
 }
 
 func TestRevoluteFrame(t *testing.T) {
-	axis := r3.Vector{1, 0, 0}                                                                // axis of rotation is x axis
-	frame := &rotationalFrame{&baseFrame{"test", []Limit{{-math.Pi / 2, math.Pi / 2}}}, axis} // limits between -90 and 90 degrees
+	axis := r3.Vector{1, 0, 0}                                                                              // axis of rotation is x axis
+	frame := &rotationalFrame{&baseFrame{name: "test", limits: []Limit{{-math.Pi / 2, math.Pi / 2}}}, axis} // limits between -90 and 90 degrees
 	// expected output
 	expPose := spatial.NewPoseFromOrientation(&spatial.R4AA{math.Pi / 4, 1, 0, 0}) // 45 degrees
 	// get expected transform back
@@ -116,7 +116,7 @@ func TestRevoluteFrame(t *testing.T) {
 
 func TestMobile2DFrame(t *testing.T) {
 	expLimit := []Limit{{-10, 10}, {-10, 10}}
-	frame := &mobile2DFrame{&baseFrame{"test", expLimit}, nil}
+	frame := &mobile2DFrame{&baseFrame{name: "test", limits: expLimit}, nil}
 	// expected output
 	expPose := spatial.NewPoseFromPoint(r3.Vector{3, 5, 0})
 	// get expected transform back
@@ -135,6 +135,18 @@ func TestMobile2DFrame(t *testing.T) {
 	// gets the correct limits back
 	limit := frame.DoF()
 	test.That(t, limit[0], test.ShouldResemble, expLimit[0])
+
+	// a third input turns the frame about the Z axis
+	headingFrame, err := NewMobile2DFrame("test", []Limit{{-10, 10}, {-10, 10}, {-math.Pi, math.Pi}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(headingFrame.DoF()), test.ShouldEqual, 3)
+	pose, err = headingFrame.Transform(FloatsToInputs([]float64{3, 5, math.Pi / 2}))
+	test.That(t, err, test.ShouldBeNil)
+	// facing along the Y axis, moving forward moves along the Y axis
+	forward := spatial.Compose(pose, spatial.NewPoseFromPoint(r3.Vector{X: 1})).Point()
+	test.That(t, spatial.R3VectorAlmostEqual(forward, r3.Vector{3, 6, 0}, 1e-8), test.ShouldBeTrue)
+	_, err = NewMobile2DFrame("test", []Limit{{-10, 10}}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
 }
 
 func TestGeometries(t *testing.T) {
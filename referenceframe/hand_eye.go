@@ -0,0 +1,121 @@
+package referenceframe
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// handEyeMinAxisSpread is the smallest ratio of the second to the first singular value of the correlation of the rotation axes of
+// the motions between samples, below which they are too close to parallel to determine the rotation of the camera about them.
+const handEyeMinAxisSpread = 1e-3
+
+// HandEyeSample is one observation for hand-eye calibration of a camera mounted on the end of an arm: the pose of the end effector,
+// or flange, of the arm relative to its base, and the pose of a calibration target fixed in the world, such as a checkerboard or a
+// fiducial marker, as observed by the camera at the same time.
+type HandEyeSample struct {
+	EndEffector spatial.Pose
+	Target      spatial.Pose
+}
+
+// CalibrateHandEye finds the pose of a camera mounted on the end effector of an arm, in the frame of the end effector, from samples
+// taken with the arm in different poses. Between each pair of samples the end effector moves by A and the camera by B, and the pose X
+// of the camera is found as the solution of AX = XB, with the rotation of X found from the axes of rotation of the motions as in
+// Park and Martin, and its translation then by least squares. The arm must turn about at least two non-parallel axes across the
+// samples, and the more the samples and the more varied the motions between them, the better the calibration.
+//
+// The result is returned as the config of a frame named camera with the end effector frame as its parent, ready to be used as the
+// frame of the camera in the config of a robot.
+func CalibrateHandEye(samples []HandEyeSample, camera, endEffector string) (*LinkConfig, error) {
+	if len(samples) < 3 {
+		return nil, errors.Errorf("hand-eye calibration needs at least 3 samples, got %d", len(samples))
+	}
+	for i, sample := range samples {
+		if sample.EndEffector == nil || sample.Target == nil {
+			return nil, errors.Errorf("sample %d is missing a pose", i)
+		}
+	}
+	// as the target is fixed, between every two samples the end effector moving by A and the camera by B satisfy AX = XB
+	type motion struct {
+		a, b spatial.Pose
+	}
+	var motions []motion
+	for i, first := range samples {
+		for _, second := range samples[i+1:] {
+			motions = append(motions, motion{
+				a: spatial.Compose(spatial.PoseInverse(second.EndEffector), first.EndEffector),
+				b: spatial.Compose(second.Target, spatial.PoseInverse(first.Target)),
+			})
+		}
+	}
+
+	// the rotation takes the axes of rotation of the motions of the camera to those of the end effector
+	correlation := mat.NewDense(3, 3, nil)
+	for _, m := range motions {
+		alpha := spatial.QuatToR3AA(m.a.Orientation().Quaternion())
+		beta := spatial.QuatToR3AA(m.b.Orientation().Quaternion())
+		correlation.RankOne(correlation, 1, mat.NewVecDense(3, []float64{beta.X, beta.Y, beta.Z}),
+			mat.NewVecDense(3, []float64{alpha.X, alpha.Y, alpha.Z}))
+	}
+	var svd mat.SVD
+	if !svd.Factorize(correlation, mat.SVDFull) {
+		return nil, errors.New("could not solve for the rotation of the camera")
+	}
+	values := svd.Values(nil)
+	if values[0] == 0 || values[1]/values[0] < handEyeMinAxisSpread {
+		return nil, errors.New("the samples must rotate the end effector about at least two non-parallel axes")
+	}
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	// correct a reflection into a rotation
+	sign := mat.NewDiagDense(3, []float64{1, 1, mat.Det(&v) * mat.Det(&u)})
+	var rotation mat.Dense
+	rotation.Product(&v, sign, u.T())
+
+	// the translation then solves (Ra - I)t = R tb - ta for every motion
+	lhs := mat.NewDense(3*len(motions), 3, nil)
+	rhs := mat.NewVecDense(3*len(motions), nil)
+	for i, m := range motions {
+		aRotation := m.a.Orientation().RotationMatrix()
+		ta, tb := m.a.Point(), m.b.Point()
+		rotated := mat.NewVecDense(3, nil)
+		rotated.MulVec(&rotation, mat.NewVecDense(3, []float64{tb.X, tb.Y, tb.Z}))
+		for r := 0; r < 3; r++ {
+			// the rows of a RotationMatrix are the columns of the rotation
+			for c := 0; c < 3; c++ {
+				lhs.Set(3*i+r, c, aRotation.At(c, r))
+			}
+			lhs.Set(3*i+r, r, lhs.At(3*i+r, r)-1)
+		}
+		rhs.SetVec(3*i, rotated.AtVec(0)-ta.X)
+		rhs.SetVec(3*i+1, rotated.AtVec(1)-ta.Y)
+		rhs.SetVec(3*i+2, rotated.AtVec(2)-ta.Z)
+	}
+	var translation mat.VecDense
+	if err := translation.SolveVec(lhs, rhs); err != nil {
+		return nil, errors.Wrap(err, "could not solve for the translation of the camera")
+	}
+
+	orientation, err := spatial.NewRotationMatrix([]float64{
+		rotation.At(0, 0), rotation.At(1, 0), rotation.At(2, 0),
+		rotation.At(0, 1), rotation.At(1, 1), rotation.At(2, 1),
+		rotation.At(0, 2), rotation.At(1, 2), rotation.At(2, 2),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// orientation vectors in degrees are how frame configs are usually written
+	orientationConfig, err := spatial.NewOrientationConfig(orientation.OrientationVectorDegrees())
+	if err != nil {
+		return nil, err
+	}
+	return &LinkConfig{
+		ID:          camera,
+		Translation: r3.Vector{X: translation.AtVec(0), Y: translation.AtVec(1), Z: translation.AtVec(2)},
+		Orientation: orientationConfig,
+		Parent:      endEffector,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package referenceframe
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestCalibrateHandEye(t *testing.T) {
+	randSeed := rand.New(rand.NewSource(1))
+	randPose := func(scale float64) spatial.Pose {
+		return spatial.NewPose(
+			r3.Vector{(randSeed.Float64() - 0.5) * scale, (randSeed.Float64() - 0.5) * scale, (randSeed.Float64() - 0.5) * scale},
+			&spatial.R4AA{Theta: randSeed.Float64(), RX: randSeed.NormFloat64(), RY: randSeed.NormFloat64(), RZ: randSeed.NormFloat64()},
+		)
+	}
+	camera := spatial.NewPose(r3.Vector{X: 30, Y: -10, Z: 60}, &spatial.OrientationVectorDegrees{OX: 1, OY: 0.2, OZ: 0.5, Theta: 40})
+	target := spatial.NewPose(r3.Vector{X: 400, Y: 100, Z: 0}, &spatial.OrientationVectorDegrees{OZ: -1, Theta: 10})
+	// the camera sees the target where it is relative to the camera, as placed by the arm
+	observe := func(endEffector spatial.Pose) spatial.Pose {
+		return spatial.Compose(spatial.PoseInverse(spatial.Compose(endEffector, camera)), target)
+	}
+	samples := make([]HandEyeSample, 0, 10)
+	for i := 0; i < 10; i++ {
+		endEffector := spatial.Compose(spatial.NewPoseFromPoint(r3.Vector{X: 300, Z: 300}), randPose(200))
+		samples = append(samples, HandEyeSample{EndEffector: endEffector, Target: observe(endEffector)})
+	}
+
+	cfg, err := CalibrateHandEye(samples, "cam", "arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.ID, test.ShouldEqual, "cam")
+	test.That(t, cfg.Parent, test.ShouldEqual, "arm")
+	pose, err := cfg.Pose()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostEqualEps(pose, camera, 1e-6), test.ShouldBeTrue)
+
+	// the config is a frame config stanza which reads back as the same frame
+	data, err := json.Marshal(cfg)
+	test.That(t, err, test.ShouldBeNil)
+	var parsed LinkConfig
+	test.That(t, json.Unmarshal(data, &parsed), test.ShouldBeNil)
+	parsedPose, err := parsed.Pose()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostEqualEps(parsedPose, camera, 1e-6), test.ShouldBeTrue)
+
+	// noisy observations give a close calibration
+	noisy := make([]HandEyeSample, 0, len(samples))
+	for _, sample := range samples {
+		noise := spatial.NewPose(
+			r3.Vector{randSeed.NormFloat64() * 0.1, randSeed.NormFloat64() * 0.1, randSeed.NormFloat64() * 0.1},
+			&spatial.R4AA{Theta: 0.001, RX: randSeed.NormFloat64(), RY: randSeed.NormFloat64(), RZ: randSeed.NormFloat64()},
+		)
+		noisy = append(noisy, HandEyeSample{EndEffector: sample.EndEffector, Target: spatial.Compose(sample.Target, noise)})
+	}
+	cfg, err = CalibrateHandEye(noisy, "cam", "arm")
+	test.That(t, err, test.ShouldBeNil)
+	pose, err = cfg.Pose()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point().Distance(camera.Point()), test.ShouldBeLessThan, 1)
+	test.That(t, spatial.OrientationBetween(pose.Orientation(), camera.Orientation()).AxisAngles().Theta, test.ShouldBeLessThan, 0.01)
+
+	// rotating about a single axis leaves the rotation of the camera about it unknown
+	parallel := make([]HandEyeSample, 0, 5)
+	for i := 0; i < 5; i++ {
+		endEffector := spatial.NewPose(r3.Vector{X: 300, Y: float64(i) * 20, Z: 300}, &spatial.R4AA{Theta: float64(i) * 0.3, RZ: 1})
+		parallel = append(parallel, HandEyeSample{EndEffector: endEffector, Target: observe(endEffector)})
+	}
+	_, err = CalibrateHandEye(parallel, "cam", "arm")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = CalibrateHandEye(samples[:2], "cam", "arm")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = CalibrateHandEye(append([]HandEyeSample{{EndEffector: samples[0].EndEffector}}, samples...), "cam", "arm")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,190 @@
+package referenceframe
+
+import (
+	"math"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// the step in each input used to differentiate the transforms of frames whose jacobian is not known in closed form.
+const jacobianStep = 1e-6
+
+// ManipulabilityEllipsoid describes how readily the end of a frame moves in each direction at some inputs. Its axes are the velocities
+// of the end for each of a set of orthogonal unit input velocities, so they are long in directions the end moves readily in, and
+// shrink to nothing in directions it cannot move in at all, as at a singularity.
+type ManipulabilityEllipsoid struct {
+	// LinearAxes are the principal semi-axes of the ellipsoid of linear velocities of the end, longest first, in mm per unit input.
+	LinearAxes []r3.Vector
+	// AngularAxes are the principal semi-axes of the ellipsoid of angular velocities of the end, longest first, in radians per unit input.
+	AngularAxes []r3.Vector
+	// Measure is Yoshikawa's manipulability, the product of the singular values of the jacobian, which is zero at singularities.
+	Measure float64
+}
+
+// Jacobian returns the geometric jacobian of a frame at the given inputs, the 6 x len(inputs) matrix mapping input velocities to the
+// velocity of the end of the frame, expressed in the frame's parent. Its first three rows are the linear velocity of the end in mm per
+// unit input, and its last three rows its angular velocity in radians per unit input, so that revolute joints contribute radians per
+// radian and prismatic joints mm per mm. The columns of the revolute and prismatic joints of a model are computed in closed form, and
+// those of any other frame by differentiating its transform.
+func Jacobian(f Frame, inputs []Input) (*mat.Dense, error) {
+	if len(inputs) != len(f.DoF()) {
+		return nil, NewIncorrectInputLengthError(len(inputs), len(f.DoF()))
+	}
+	if len(inputs) == 0 {
+		return nil, errors.Errorf("frame %q has no inputs to take the jacobian with respect to", f.Name())
+	}
+	frames := []Frame{f}
+	if m, ok := f.(*SimpleModel); ok {
+		frames = m.OrdTransforms
+	}
+
+	// the pose of the origin of each frame, from which it moves, relative to the parent of f
+	origins := make([]spatial.Pose, 0, len(frames))
+	end := spatial.NewZeroPose()
+	posIdx := 0
+	for _, frame := range frames {
+		dof := len(frame.DoF()) + posIdx
+		origins = append(origins, end)
+		pose, err := frame.Transform(inputs[posIdx:dof])
+		// allow computing the jacobian at out-of-bounds inputs, as Transform does
+		if pose == nil || (err != nil && !strings.Contains(err.Error(), OOBErrString)) {
+			return nil, err
+		}
+		end = spatial.Compose(end, pose)
+		posIdx = dof
+	}
+
+	jacobian := mat.NewDense(6, len(inputs), nil)
+	setColumn := func(col int, linear, angular r3.Vector) {
+		jacobian.SetCol(col, []float64{linear.X, linear.Y, linear.Z, angular.X, angular.Y, angular.Z})
+	}
+	posIdx = 0
+	for i, frame := range frames {
+		// the direction of an axis of the frame in the parent of f
+		direction := func(axis r3.Vector) r3.Vector {
+			return spatial.Compose(origins[i], spatial.NewPoseFromPoint(axis.Normalize())).Point().Sub(origins[i].Point())
+		}
+		switch jf := frame.(type) {
+		case *rotationalFrame:
+			axis := direction(jf.rotAxis)
+			setColumn(posIdx, axis.Cross(end.Point().Sub(origins[i].Point())), axis)
+		case *translationalFrame:
+			setColumn(posIdx, direction(jf.transAxis), r3.Vector{})
+		default:
+			for j := range frame.DoF() {
+				linear, angular, err := differentiateTransform(f, inputs, posIdx+j)
+				if err != nil {
+					return nil, err
+				}
+				setColumn(posIdx+j, linear, angular)
+			}
+		}
+		posIdx += len(frame.DoF())
+	}
+	return jacobian, nil
+}
+
+// JacobianConditionNumber returns the condition number of the jacobian of a frame at the given inputs, the ratio of its largest to its
+// smallest singular value. It grows without bound as the frame nears a singularity, where it is infinite. As the jacobian mixes mm
+// and radians, the condition number is only meaningful compared to that of the same frame at other inputs.
+func JacobianConditionNumber(f Frame, inputs []Input) (float64, error) {
+	jacobian, err := Jacobian(f, inputs)
+	if err != nil {
+		return 0, err
+	}
+	values, _, err := jacobianSVD(jacobian)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 || values[len(values)-1] == 0 {
+		return math.Inf(1), nil
+	}
+	return values[0] / values[len(values)-1], nil
+}
+
+// Manipulability returns the manipulability ellipsoid of a frame at the given inputs.
+func Manipulability(f Frame, inputs []Input) (*ManipulabilityEllipsoid, error) {
+	jacobian, err := Jacobian(f, inputs)
+	if err != nil {
+		return nil, err
+	}
+	values, _, err := jacobianSVD(jacobian)
+	if err != nil {
+		return nil, err
+	}
+	measure := 1.
+	for _, value := range values {
+		measure *= value
+	}
+	linearAxes, err := ellipsoidAxes(jacobian.Slice(0, 3, 0, len(inputs)))
+	if err != nil {
+		return nil, err
+	}
+	angularAxes, err := ellipsoidAxes(jacobian.Slice(3, 6, 0, len(inputs)))
+	if err != nil {
+		return nil, err
+	}
+	return &ManipulabilityEllipsoid{LinearAxes: linearAxes, AngularAxes: angularAxes, Measure: measure}, nil
+}
+
+// jacobianSVD returns the singular values of a matrix, largest first, along with its left singular vectors.
+func jacobianSVD(m mat.Matrix) ([]float64, *mat.Dense, error) {
+	var svd mat.SVD
+	if ok := svd.Factorize(m, mat.SVDThin); !ok {
+		return nil, nil, errors.New("failed to factorize jacobian")
+	}
+	var u mat.Dense
+	svd.UTo(&u)
+	return svd.Values(nil), &u, nil
+}
+
+// ellipsoidAxes returns the principal semi-axes of the image of the unit ball under a 3 row matrix, longest first.
+func ellipsoidAxes(m mat.Matrix) ([]r3.Vector, error) {
+	values, u, err := jacobianSVD(m)
+	if err != nil {
+		return nil, err
+	}
+	axes := make([]r3.Vector, 0, len(values))
+	for i, value := range values {
+		axes = append(axes, r3.Vector{X: u.At(0, i), Y: u.At(1, i), Z: u.At(2, i)}.Mul(value))
+	}
+	return axes, nil
+}
+
+// differentiateTransform returns the linear and angular velocity of the end of a frame per unit velocity of one of its inputs, by
+// central differences of its transform.
+func differentiateTransform(f Frame, inputs []Input, idx int) (r3.Vector, r3.Vector, error) {
+	stepped := func(step float64) (spatial.Pose, error) {
+		q := make([]Input, len(inputs))
+		copy(q, inputs)
+		q[idx].Value += step
+		pose, err := f.Transform(q)
+		if pose == nil || (err != nil && !strings.Contains(err.Error(), OOBErrString)) {
+			return nil, err
+		}
+		return pose, nil
+	}
+	before, err := stepped(-jacobianStep)
+	if err != nil {
+		return r3.Vector{}, r3.Vector{}, err
+	}
+	after, err := stepped(jacobianStep)
+	if err != nil {
+		return r3.Vector{}, r3.Vector{}, err
+	}
+	linear := after.Point().Sub(before.Point()).Mul(1 / (2 * jacobianStep))
+	// the rotation from before to after, in the parent of the frame, is by the angular velocity times both steps, and the vector part
+	// of its quaternion is the sine of half of that
+	delta := quat.Mul(after.Orientation().Quaternion(), quat.Conj(before.Orientation().Quaternion()))
+	if delta.Real < 0 {
+		delta = quat.Scale(-1, delta)
+	}
+	angular := r3.Vector{X: delta.Imag, Y: delta.Jmag, Z: delta.Kmag}.Mul(1 / jacobianStep)
+	return linear, angular, nil
+}
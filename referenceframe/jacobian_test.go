@@ -0,0 +1,90 @@
+package referenceframe
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestJacobian(t *testing.T) {
+	// a planar arm of two 100mm links turning about z
+	joint1, err := NewRotationalFrame("joint1", spatial.R4AA{RZ: 1}, Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	link1, err := NewStaticFrame("link1", spatial.NewPoseFromPoint(r3.Vector{X: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	joint2, err := NewRotationalFrame("joint2", spatial.R4AA{RZ: 1}, Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	link2, err := NewStaticFrame("link2", spatial.NewPoseFromPoint(r3.Vector{X: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	m := NewSimpleModel("planar")
+	m.OrdTransforms = []Frame{joint1, link1, joint2, link2}
+
+	jacobian, err := Jacobian(m, FloatsToInputs([]float64{0, math.Pi / 2}))
+	test.That(t, err, test.ShouldBeNil)
+	expected := [][]float64{{-100, -100}, {100, 0}, {0, 0}, {0, 0}, {0, 0}, {1, 1}}
+	for i, row := range expected {
+		for j, value := range row {
+			test.That(t, jacobian.At(i, j), test.ShouldAlmostEqual, value)
+		}
+	}
+
+	// stretched out straight, the end of the arm cannot move along it
+	bent, err := Manipulability(m, FloatsToInputs([]float64{0, math.Pi / 2}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(bent.LinearAxes), test.ShouldEqual, 2)
+	straight, err := Manipulability(m, FloatsToInputs([]float64{0, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bent.LinearAxes[1].Norm(), test.ShouldBeGreaterThan, 50)
+	test.That(t, straight.LinearAxes[1].Norm(), test.ShouldAlmostEqual, 0)
+	test.That(t, math.Abs(straight.LinearAxes[0].Y), test.ShouldAlmostEqual, straight.LinearAxes[0].Norm())
+	test.That(t, straight.Measure, test.ShouldBeLessThan, bent.Measure)
+	test.That(t, bent.AngularAxes[0].Norm(), test.ShouldAlmostEqual, math.Sqrt(2))
+
+	// the closed form jacobian of a model agrees with differentiating its transform
+	ur5e, err := ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	inputs := FloatsToInputs(GenerateRandomConfiguration(ur5e, rand.New(rand.NewSource(1))))
+	jacobian, err = Jacobian(ur5e, inputs)
+	test.That(t, err, test.ShouldBeNil)
+	for j := range inputs {
+		linear, angular, err := differentiateTransform(ur5e, inputs, j)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.R3VectorAlmostEqual(linear, r3.Vector{X: jacobian.At(0, j), Y: jacobian.At(1, j), Z: jacobian.At(2, j)}, 1e-3),
+			test.ShouldBeTrue)
+		test.That(t, spatial.R3VectorAlmostEqual(angular, r3.Vector{X: jacobian.At(3, j), Y: jacobian.At(4, j), Z: jacobian.At(5, j)}, 1e-6),
+			test.ShouldBeTrue)
+	}
+	cond, err := JacobianConditionNumber(ur5e, inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cond, test.ShouldBeGreaterThan, 1)
+	// with its wrist folded so that the axes of its first and last wrist joints line up, it is singular
+	cond, err = JacobianConditionNumber(ur5e, FloatsToInputs([]float64{0, -math.Pi / 2, math.Pi / 2, 0, 0, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cond, test.ShouldBeGreaterThan, 1e6)
+
+	// frames without a closed form jacobian are differentiated
+	base, err := NewMobile2DFrame("base", []Limit{{-100, 100}, {-100, 100}, {-math.Pi, math.Pi}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	jacobian, err = Jacobian(base, FloatsToInputs([]float64{10, 20, math.Pi / 3}))
+	test.That(t, err, test.ShouldBeNil)
+	expected = [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 0}, {0, 0, 0}, {0, 0, 0}, {0, 0, 1}}
+	for i, row := range expected {
+		for j, value := range row {
+			test.That(t, jacobian.At(i, j), test.ShouldAlmostEqual, value, 1e-6)
+		}
+	}
+	cond, err = JacobianConditionNumber(base, FloatsToInputs([]float64{10, 20, math.Pi / 3}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cond, test.ShouldAlmostEqual, 1, 1e-6)
+
+	_, err = Jacobian(link1, []Input{})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = Jacobian(m, FloatsToInputs([]float64{0}))
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -28,6 +28,8 @@ type Model interface {
 	Frame
 	ModelConfig() *ModelConfig
 	ChangeName(string)
+	// Validate checks the kinematics of the model, returning any problems found with them.
+	Validate() []ModelFinding
 }
 
 // SimpleModel TODO.
@@ -172,6 +174,15 @@ func (m *SimpleModel) DoF() []Limit {
 	return limits
 }
 
+// DynamicLimits returns how fast each degree of freedom within a model can move, in the same order as DoF.
+func (m *SimpleModel) DynamicLimits() []DynamicLimit {
+	dynamics := make([]DynamicLimit, 0, len(m.OrdTransforms))
+	for _, transform := range m.OrdTransforms {
+		dynamics = append(dynamics, DynamicLimitsOf(transform)...)
+	}
+	return dynamics
+}
+
 // MarshalJSON serializes a Model.
 func (m *SimpleModel) MarshalJSON() ([]byte, error) {
 	return json.Marshal(m.modelConfig)
@@ -236,7 +247,7 @@ func (m *SimpleModel) inputsToFrames(inputs []Input, collectAll bool) ([]*static
 		composedTransformation = spatialmath.Compose(composedTransformation, pose)
 	}
 	// TODO(rb) as written this will return one too many frames, no need to return zeroth frame
-	poses = append(poses, &staticFrame{&baseFrame{"", []Limit{}}, composedTransformation, nil})
+	poses = append(poses, &staticFrame{&baseFrame{name: "", limits: []Limit{}}, composedTransformation, nil})
 	return poses, err
 }
 
@@ -23,6 +23,11 @@ func (cfg *ModelConfig) ParseConfig(modelName string) (Model, error) {
 		modelName = cfg.Name
 	}
 
+	// frames sharing a name would be dropped below, leaving the model misshapen before it could be validated
+	if findings := cfg.duplicateNameFindings(); len(findings) > 0 {
+		return nil, &ModelValidationError{Model: modelName, Findings: findings}
+	}
+
 	model := NewSimpleModel(modelName)
 	model.modelConfig = cfg
 	transforms := map[string]Frame{}
@@ -115,6 +120,10 @@ func (cfg *ModelConfig) ParseConfig(modelName string) (Model, error) {
 		return nil, err
 	}
 
+	// bad kinematics should fail loudly here rather than as planning which mysteriously never succeeds
+	if findings := model.Validate(); len(findings) > 0 {
+		return nil, &ModelValidationError{Model: modelName, Findings: findings}
+	}
 	return model, nil
 }
 
@@ -1,10 +1,13 @@
 package referenceframe
 
 import (
+	"math"
 	"testing"
 
 	"go.viam.com/test"
 
+	spatial "go.viam.com/rdk/spatialmath"
+
 	"go.viam.com/rdk/utils"
 )
 
@@ -51,3 +54,23 @@ func TestParseJSONFile(t *testing.T) {
 		})
 	}
 }
+
+func TestJointDynamicLimits(t *testing.T) {
+	revolute := &JointConfig{ID: "a", Type: RevoluteJoint, Axis: spatial.AxisConfig{Z: 1}, Min: -180, Max: 180, MaxVel: 90, MaxAcc: 180}
+	f, err := revolute.ToFrame()
+	test.That(t, err, test.ShouldBeNil)
+	limits := DynamicLimitsOf(f)
+	test.That(t, len(limits), test.ShouldEqual, 1)
+	test.That(t, limits[0].MaxVelocity, test.ShouldAlmostEqual, math.Pi/2)
+	test.That(t, limits[0].MaxAcceleration, test.ShouldAlmostEqual, math.Pi)
+
+	prismatic := &JointConfig{ID: "b", Type: PrismaticJoint, Axis: spatial.AxisConfig{X: 1}, Min: 0, Max: 100, MaxVel: 50}
+	f, err = prismatic.ToFrame()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, DynamicLimitsOf(f), test.ShouldResemble, []DynamicLimit{{MaxVelocity: 50}})
+
+	// frames that do not know their limits are unlimited
+	f, err = NewStaticFrame("c", spatial.NewZeroPose())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, DynamicLimitsOf(f), test.ShouldBeEmpty)
+}
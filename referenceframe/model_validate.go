@@ -0,0 +1,224 @@
+package referenceframe
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/golang/geo/r3"
+)
+
+// The checks Model.Validate makes of the kinematics of a model, by which its findings are grouped.
+const (
+	// ZeroLengthAxisCheck finds joints whose axis has no direction to move along or about.
+	ZeroLengthAxisCheck = "zero-length-axis"
+	// LimitRangeCheck finds joints whose limits leave no position they can be at.
+	LimitRangeCheck = "limit-range"
+	// DuplicateFrameNameCheck finds frames sharing a name, of which all but one would be silently dropped.
+	DuplicateFrameNameCheck = "duplicate-frame-name"
+	// SelfCollisionCheck finds pairs of geometries of the model which collide however its joints are placed.
+	SelfCollisionCheck = "self-collision"
+)
+
+// selfCollisionSamples is how many random configurations of a model, besides the one with all inputs at zero, two of its geometries
+// must collide in to be found to always collide.
+const selfCollisionSamples = 20
+
+// ModelFinding is a problem with the kinematics of a model found by Model.Validate.
+type ModelFinding struct {
+	// Check is which of the checks, such as SelfCollisionCheck, found the problem.
+	Check string `json:"check"`
+	// Frames are the names of the frames, or for SelfCollisionCheck the geometries, with the problem.
+	Frames  []string `json:"frames"`
+	Message string   `json:"message"`
+}
+
+func (f ModelFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Check, f.Message)
+}
+
+// ModelValidationError is returned when loading a model whose kinematics have problems, with the findings of Model.Validate.
+type ModelValidationError struct {
+	Model    string
+	Findings []ModelFinding
+}
+
+func (e *ModelValidationError) Error() string {
+	messages := make([]string, 0, len(e.Findings))
+	for _, finding := range e.Findings {
+		messages = append(messages, finding.String())
+	}
+	return fmt.Sprintf("invalid kinematics for model %q: %s", e.Model, strings.Join(messages, "; "))
+}
+
+// Validate checks the kinematics of the model for joints with zero-length axes or limits they cannot be within, frames sharing a
+// name, and geometries which collide with each other in every configuration, other than those of neighboring links which usually
+// touch where they are joined. It returns what it finds, which is nothing for a model which is sound.
+func (m *SimpleModel) Validate() []ModelFinding {
+	var findings []ModelFinding
+	if m.modelConfig != nil {
+		findings = append(findings, m.modelConfig.duplicateNameFindings()...)
+	}
+	seen := map[string]bool{}
+	for _, transform := range m.OrdTransforms {
+		name := transform.Name()
+		if seen[name] {
+			findings = append(findings, duplicateNameFinding(name))
+		}
+		seen[name] = true
+
+		if axis, ok := jointAxis(transform); ok && !(axis.Norm() > 1e-8) {
+			findings = append(findings, ModelFinding{
+				Check:   ZeroLengthAxisCheck,
+				Frames:  []string{name},
+				Message: fmt.Sprintf("joint %q has a zero-length axis", name),
+			})
+		}
+		for _, limit := range transform.DoF() {
+			if math.IsNaN(limit.Min) || math.IsNaN(limit.Max) || limit.Min > limit.Max {
+				findings = append(findings, ModelFinding{
+					Check:   LimitRangeCheck,
+					Frames:  []string{name},
+					Message: fmt.Sprintf("joint %q has an empty range of limits from %v to %v", name, limit.Min, limit.Max),
+				})
+			}
+		}
+	}
+	// the configurations of a model without any reachable ones cannot be sampled for self-collisions
+	if len(findings) > 0 {
+		return findings
+	}
+	return append(findings, m.selfCollisionFindings()...)
+}
+
+// jointAxis returns the axis a frame of a single joint moves along or about, if it is one.
+func jointAxis(f Frame) (r3.Vector, bool) {
+	switch joint := f.(type) {
+	case *rotationalFrame:
+		return joint.rotAxis, true
+	case *translationalFrame:
+		return joint.transAxis, true
+	default:
+		return r3.Vector{}, false
+	}
+}
+
+// duplicateNameFindings finds the names shared by more than one link, joint or set of DH parameters in a model config, which are
+// otherwise silently dropped when it is parsed.
+func (cfg *ModelConfig) duplicateNameFindings() []ModelFinding {
+	var names []string
+	for _, link := range cfg.Links {
+		names = append(names, link.ID)
+	}
+	for _, joint := range cfg.Joints {
+		names = append(names, joint.ID)
+	}
+	for _, dh := range cfg.DHParams {
+		names = append(names, dh.ID, dh.ID+"_j")
+	}
+	counts := map[string]int{}
+	for _, name := range names {
+		counts[name]++
+	}
+	var findings []ModelFinding
+	for _, name := range names {
+		if counts[name] > 1 {
+			findings = append(findings, duplicateNameFinding(name))
+			// report each name once
+			counts[name] = 0
+		}
+	}
+	return findings
+}
+
+func duplicateNameFinding(name string) ModelFinding {
+	return ModelFinding{
+		Check:   DuplicateFrameNameCheck,
+		Frames:  []string{name},
+		Message: fmt.Sprintf("more than one frame is named %q", name),
+	}
+}
+
+// selfCollisionFindings finds the pairs of geometries of the model which collide at every one of a fixed set of sampled
+// configurations. Geometries on neighboring links of the chain are left out, as they commonly overlap where they are joined.
+func (m *SimpleModel) selfCollisionFindings() []ModelFinding {
+	// the position along the chain of each frame, to tell which geometries are on neighboring links
+	order := map[string]int{}
+	for i, transform := range m.OrdTransforms {
+		order[m.name+":"+transform.Name()] = i
+	}
+
+	//nolint:gosec
+	randSeed := rand.New(rand.NewSource(1))
+	limits := m.DoF()
+	var colliding map[[2]string]bool
+	for sample := 0; sample <= selfCollisionSamples; sample++ {
+		inputs := make([]Input, 0, len(limits))
+		for _, limit := range limits {
+			inputs = append(inputs, Input{sampleWithinLimit(limit, sample, randSeed)})
+		}
+		geometries, err := m.Geometries(inputs)
+		if geometries == nil || err != nil {
+			return nil
+		}
+		named := geometries.Geometries()
+		names := make([]string, 0, len(named))
+		for name := range named {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return order[names[i]] < order[names[j]] })
+
+		found := map[[2]string]bool{}
+		for i, first := range names {
+			// neighboring geometries are next to each other once sorted along the chain
+			for j := i + 2; j < len(names); j++ {
+				second := names[j]
+				if colliding != nil && !colliding[[2]string{first, second}] {
+					continue
+				}
+				if collides, err := named[first].CollidesWith(named[second]); err == nil && collides {
+					found[[2]string{first, second}] = true
+				}
+			}
+		}
+		colliding = found
+		if len(colliding) == 0 {
+			return nil
+		}
+	}
+
+	pairs := make([][2]string, 0, len(colliding))
+	for pair := range colliding {
+		pairs = append(pairs, pair)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || (pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1])
+	})
+	findings := make([]ModelFinding, 0, len(pairs))
+	for _, pair := range pairs {
+		findings = append(findings, ModelFinding{
+			Check:   SelfCollisionCheck,
+			Frames:  []string{pair[0], pair[1]},
+			Message: fmt.Sprintf("geometries %q and %q collide in every configuration", pair[0], pair[1]),
+		})
+	}
+	return findings
+}
+
+// sampleWithinLimit returns zero, or the nearest value to it within the limit, for the first sample, and a random value within the
+// limit for the rest. Unbounded limits, such as those of continuous joints, are sampled over a single turn.
+func sampleWithinLimit(limit Limit, sample int, randSeed *rand.Rand) float64 {
+	low, high := limit.Min, limit.Max
+	if math.IsInf(low, -1) {
+		low = math.Min(high, math.Pi) - 2*math.Pi
+	}
+	if math.IsInf(high, 1) {
+		high = low + 2*math.Pi
+	}
+	if sample == 0 {
+		return math.Min(math.Max(0, low), high)
+	}
+	return low + randSeed.Float64()*(high-low)
+}
@@ -0,0 +1,107 @@
+package referenceframe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestModelValidate(t *testing.T) {
+	for _, f := range []string{
+		"components/arm/universalrobots/ur5e.json",
+		"components/arm/xarm/xarm6_kinematics.json",
+		"referenceframe/testjson/ur5eDH.json",
+		"referenceframe/testurdf/ur5_viam.urdf",
+	} {
+		model, err := ModelFromPath(utils.ResolveFile(f), "")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, model.Validate(), test.ShouldBeEmpty)
+	}
+
+	// base and wrist are both centered on the axis the joints turn about, however they turn
+	cfg := func() *ModelConfig {
+		return &ModelConfig{
+			Name: "test",
+			Links: []LinkConfig{
+				{ID: "base", Parent: World, Geometry: &spatial.GeometryConfig{Type: spatial.BoxType, X: 100, Y: 100, Z: 100}},
+				{
+					ID: "upper", Parent: "shoulder", Translation: r3.Vector{Z: 500},
+					Geometry: &spatial.GeometryConfig{Type: spatial.BoxType, X: 10, Y: 10, Z: 10},
+				},
+				{
+					ID: "wrist", Parent: "elbow",
+					Geometry: &spatial.GeometryConfig{Type: spatial.BoxType, X: 10, Y: 10, Z: 10, TranslationOffset: r3.Vector{Z: -500}},
+				},
+			},
+			Joints: []JointConfig{
+				{ID: "shoulder", Type: RevoluteJoint, Parent: "base", Axis: spatial.AxisConfig{Z: 1}, Min: -180, Max: 180},
+				{ID: "elbow", Type: RevoluteJoint, Parent: "upper", Axis: spatial.AxisConfig{Z: 1}, Min: -180, Max: 180},
+			},
+		}
+	}
+
+	t.Run("self collision", func(t *testing.T) {
+		_, err := cfg().ParseConfig("")
+		var validationErr *ModelValidationError
+		test.That(t, errors.As(err, &validationErr), test.ShouldBeTrue)
+		test.That(t, validationErr.Findings, test.ShouldResemble, []ModelFinding{{
+			Check:   SelfCollisionCheck,
+			Frames:  []string{"test:base", "test:wrist"},
+			Message: `geometries "test:base" and "test:wrist" collide in every configuration`,
+		}})
+
+		// moving the wrist clear of the base in some configurations is fine
+		clear := cfg()
+		clear.Links[2].Geometry.TranslationOffset = r3.Vector{X: 300, Z: -500}
+		model, err := clear.ParseConfig("")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, model.Validate(), test.ShouldBeEmpty)
+	})
+
+	t.Run("duplicate names", func(t *testing.T) {
+		duplicate := cfg()
+		duplicate.Links[2].Geometry.TranslationOffset = r3.Vector{X: 300, Z: -500}
+		duplicate.Links = append(duplicate.Links, LinkConfig{ID: "upper", Parent: "base"})
+		_, err := duplicate.ParseConfig("")
+		var validationErr *ModelValidationError
+		test.That(t, errors.As(err, &validationErr), test.ShouldBeTrue)
+		test.That(t, len(validationErr.Findings), test.ShouldEqual, 1)
+		test.That(t, validationErr.Findings[0].Check, test.ShouldEqual, DuplicateFrameNameCheck)
+		test.That(t, validationErr.Findings[0].Frames, test.ShouldResemble, []string{"upper"})
+	})
+
+	t.Run("limit range", func(t *testing.T) {
+		inverted := cfg()
+		inverted.Joints[1].Min, inverted.Joints[1].Max = 90, -90
+		_, err := inverted.ParseConfig("")
+		var validationErr *ModelValidationError
+		test.That(t, errors.As(err, &validationErr), test.ShouldBeTrue)
+		test.That(t, len(validationErr.Findings), test.ShouldEqual, 1)
+		test.That(t, validationErr.Findings[0].Check, test.ShouldEqual, LimitRangeCheck)
+		test.That(t, validationErr.Findings[0].Frames, test.ShouldResemble, []string{"elbow"})
+		test.That(t, err.Error(), test.ShouldContainSubstring, "elbow")
+	})
+
+	t.Run("zero-length axis", func(t *testing.T) {
+		zero := cfg()
+		zero.Joints[0].Axis = spatial.AxisConfig{}
+		_, err := zero.ParseConfig("")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		// a frame with an axis which is not a direction is found by validating a model built by hand
+		model := NewSimpleModel("test")
+		model.OrdTransforms = []Frame{&rotationalFrame{
+			baseFrame: &baseFrame{name: "joint", limits: []Limit{{-math.Pi, math.Pi}}},
+			rotAxis:   r3.Vector{X: math.NaN()},
+		}}
+		findings := model.Validate()
+		test.That(t, len(findings), test.ShouldEqual, 1)
+		test.That(t, findings[0].Check, test.ShouldEqual, ZeroLengthAxisCheck)
+	})
+}
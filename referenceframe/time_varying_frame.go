@@ -0,0 +1,173 @@
+package referenceframe
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// TimeVaryingFrame is a frame whose pose relative to its parent changes over time without any inputs, such as a tool whose pose is
+// tracked by a motion capture system, or a turntable. Its Transform and Geometries place it as it is at the current time.
+type TimeVaryingFrame interface {
+	Frame
+
+	// TransformAt returns the pose of the frame relative to its parent at the given time.
+	TransformAt(timestamp time.Time) (spatial.Pose, error)
+
+	// GeometriesAt returns the geometries of the frame as they are placed at the given time.
+	GeometriesAt(timestamp time.Time) (*GeometriesInFrame, error)
+}
+
+// timeVaryingFrame is a TimeVaryingFrame whose pose at each time is supplied by a function.
+type timeVaryingFrame struct {
+	*baseFrame
+	poseAt   func(timestamp time.Time) (spatial.Pose, error)
+	geometry spatial.Geometry
+}
+
+// NewCallbackFrame creates a TimeVaryingFrame whose pose relative to its parent at each time is given by a callback, such as one
+// computing the pose of a turntable from its known speed. Its geometry, which may be nil, moves with it.
+func NewCallbackFrame(
+	name string,
+	poseAt func(timestamp time.Time) (spatial.Pose, error),
+	geometry spatial.Geometry,
+) (TimeVaryingFrame, error) {
+	if poseAt == nil {
+		return nil, errors.New("callback giving the pose of a time varying frame is not allowed to be nil")
+	}
+	return &timeVaryingFrame{&baseFrame{name: name, limits: []Limit{}}, poseAt, geometry}, nil
+}
+
+// Transform returns the pose of the frame at the current time.
+func (tf *timeVaryingFrame) Transform(input []Input) (spatial.Pose, error) {
+	if len(input) != 0 {
+		return nil, NewIncorrectInputLengthError(len(input), 0)
+	}
+	return tf.TransformAt(time.Now())
+}
+
+// TransformAt returns the pose of the frame at the given time.
+func (tf *timeVaryingFrame) TransformAt(timestamp time.Time) (spatial.Pose, error) {
+	return tf.poseAt(timestamp)
+}
+
+// Geometries returns the geometries of the frame as they are placed at the current time.
+func (tf *timeVaryingFrame) Geometries(input []Input) (*GeometriesInFrame, error) {
+	if len(input) != 0 {
+		return nil, NewIncorrectInputLengthError(len(input), 0)
+	}
+	return tf.GeometriesAt(time.Now())
+}
+
+// GeometriesAt returns the geometries of the frame as they are placed at the given time.
+func (tf *timeVaryingFrame) GeometriesAt(timestamp time.Time) (*GeometriesInFrame, error) {
+	if tf.geometry == nil {
+		return NewGeometriesInFrame(tf.Name(), nil), nil
+	}
+	pose, err := tf.TransformAt(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeometriesInFrame(tf.Name(), map[string]spatial.Geometry{tf.Name(): tf.geometry.Transform(pose)}), nil
+}
+
+// InputFromProtobuf converts pb.JointPosition to inputs.
+func (tf *timeVaryingFrame) InputFromProtobuf(jp *pb.JointPositions) []Input {
+	return []Input{}
+}
+
+// ProtobufFromInput converts inputs to pb.JointPosition.
+func (tf *timeVaryingFrame) ProtobufFromInput(input []Input) *pb.JointPositions {
+	return &pb.JointPositions{}
+}
+
+func (tf *timeVaryingFrame) MarshalJSON() ([]byte, error) {
+	return nil, fmt.Errorf("MarshalJSON not implemented for type %T", tf)
+}
+
+// AlmostEquals returns whether another frame is the same time varying frame, as the poses they will take cannot be compared.
+func (tf *timeVaryingFrame) AlmostEquals(otherFrame Frame) bool {
+	return otherFrame == tf
+}
+
+// poseSample is the pose of a frame at a time.
+type poseSample struct {
+	timestamp time.Time
+	pose      spatial.Pose
+}
+
+// SampledFrame is a TimeVaryingFrame whose pose is interpolated between timestamped samples, such as those reported by a pose
+// tracker. Only the most recent samples are kept. Before the earliest of them its pose is unknown, and after the latest of them it is
+// held at the latest.
+type SampledFrame struct {
+	*timeVaryingFrame
+	maxSamples int
+
+	mu      sync.RWMutex
+	samples []poseSample // ordered by time
+}
+
+// NewSampledFrame creates a SampledFrame keeping up to maxSamples samples of its pose. Its geometry, which may be nil, moves with it.
+func NewSampledFrame(name string, maxSamples int, geometry spatial.Geometry) (*SampledFrame, error) {
+	if maxSamples < 1 {
+		return nil, errors.Errorf("a sampled frame must keep at least one sample, got %d", maxSamples)
+	}
+	sf := &SampledFrame{maxSamples: maxSamples}
+	sf.timeVaryingFrame = &timeVaryingFrame{&baseFrame{name: name, limits: []Limit{}}, sf.interpolate, geometry}
+	return sf, nil
+}
+
+// AddSample records the pose of the frame relative to its parent at a time, dropping the oldest sample if too many are kept. Samples
+// may be added out of order, and replace any sample at the same time.
+func (sf *SampledFrame) AddSample(timestamp time.Time, pose spatial.Pose) error {
+	if pose == nil {
+		return errors.New("pose is not allowed to be nil")
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	idx := sort.Search(len(sf.samples), func(i int) bool { return !sf.samples[i].timestamp.Before(timestamp) })
+	sample := poseSample{timestamp, pose}
+	if idx < len(sf.samples) && sf.samples[idx].timestamp.Equal(timestamp) {
+		sf.samples[idx] = sample
+	} else {
+		sf.samples = append(sf.samples, poseSample{})
+		copy(sf.samples[idx+1:], sf.samples[idx:])
+		sf.samples[idx] = sample
+	}
+	if len(sf.samples) > sf.maxSamples {
+		sf.samples = sf.samples[len(sf.samples)-sf.maxSamples:]
+	}
+	return nil
+}
+
+// AlmostEquals returns whether another frame is the same sampled frame.
+func (sf *SampledFrame) AlmostEquals(otherFrame Frame) bool {
+	return otherFrame == sf
+}
+
+// interpolate returns the pose of the frame at a time, interpolated between the samples either side of it.
+func (sf *SampledFrame) interpolate(timestamp time.Time) (spatial.Pose, error) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+	if len(sf.samples) == 0 {
+		return nil, errors.Errorf("no pose samples of frame %q", sf.Name())
+	}
+	idx := sort.Search(len(sf.samples), func(i int) bool { return !sf.samples[i].timestamp.Before(timestamp) })
+	switch {
+	case idx == len(sf.samples):
+		return sf.samples[idx-1].pose, nil
+	case sf.samples[idx].timestamp.Equal(timestamp):
+		return sf.samples[idx].pose, nil
+	case idx == 0:
+		return nil, errors.Errorf("no pose samples of frame %q at or before %v", sf.Name(), timestamp)
+	}
+	before, after := sf.samples[idx-1], sf.samples[idx]
+	by := float64(timestamp.Sub(before.timestamp)) / float64(after.timestamp.Sub(before.timestamp))
+	return spatial.Interpolate(before.pose, after.pose, by), nil
+}
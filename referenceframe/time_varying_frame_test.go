@@ -0,0 +1,73 @@
+package referenceframe
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestTimeVaryingFrames(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// a turntable turning a quarter turn per second
+	turntable, err := NewCallbackFrame("turntable", func(timestamp time.Time) (spatial.Pose, error) {
+		angle := timestamp.Sub(start).Seconds() * math.Pi / 2
+		return spatial.NewPoseFromOrientation(&spatial.R4AA{Theta: angle, RZ: 1}), nil
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, turntable.DoF(), test.ShouldBeEmpty)
+	_, err = NewCallbackFrame("bad", nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a tool whose pose is tracked
+	box, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+	test.That(t, err, test.ShouldBeNil)
+	tool, err := NewSampledFrame("tool", 2, box)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = tool.Transform([]Input{})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, tool.AddSample(start.Add(time.Second), spatial.NewPoseFromPoint(r3.Vector{X: 100})), test.ShouldBeNil)
+	test.That(t, tool.AddSample(start, spatial.NewPoseFromPoint(r3.Vector{X: 0})), test.ShouldBeNil)
+	pose, err := tool.TransformAt(start.Add(250 * time.Millisecond))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(pose.Point(), r3.Vector{X: 25}, 1e-8), test.ShouldBeTrue)
+	pose, err = tool.Transform([]Input{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(pose.Point(), r3.Vector{X: 100}, 1e-8), test.ShouldBeTrue)
+	geometries, err := tool.GeometriesAt(start.Add(500 * time.Millisecond))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(geometries.Geometries()["tool"].Pose().Point(), r3.Vector{X: 50}, 1e-8), test.ShouldBeTrue)
+	// only the most recent samples are kept
+	test.That(t, tool.AddSample(start.Add(2*time.Second), spatial.NewPoseFromPoint(r3.Vector{X: 100, Y: 100})), test.ShouldBeNil)
+	_, err = tool.TransformAt(start.Add(500 * time.Millisecond))
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewSampledFrame("bad", 0, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	fs := NewEmptySimpleFrameSystem("test")
+	test.That(t, fs.AddFrame(turntable, fs.World()), test.ShouldBeNil)
+	test.That(t, fs.AddFrame(tool, turntable), test.ShouldBeNil)
+
+	// the frame system places time varying frames as they were at the time asked for
+	toolOrigin := NewPoseInFrame("tool", spatial.NewZeroPose())
+	transformed, err := fs.TransformAt(map[string][]Input{}, toolOrigin, World, start.Add(time.Second))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(transformed.(*PoseInFrame).Pose().Point(), r3.Vector{Y: 100}, 1e-8),
+		test.ShouldBeTrue)
+	transformed, err = fs.TransformAt(map[string][]Input{}, toolOrigin, World, start.Add(2*time.Second))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(transformed.(*PoseInFrame).Pose().Point(), r3.Vector{X: -100, Y: -100}, 1e-8),
+		test.ShouldBeTrue)
+	_, err = fs.TransformAt(map[string][]Input{}, toolOrigin, World, start)
+	test.That(t, err, test.ShouldNotBeNil)
+	// and without a time as they are now
+	transformed, err = fs.Transform(map[string][]Input{}, toolOrigin, "turntable")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(transformed.(*PoseInFrame).Pose().Point(), r3.Vector{X: 100, Y: 100}, 1e-8),
+		test.ShouldBeTrue)
+}
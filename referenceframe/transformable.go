@@ -26,7 +26,10 @@ type PoseInFrame struct {
 // LinkInFrame is a PoseInFrame plus a Geometry.
 type LinkInFrame struct {
 	*PoseInFrame
-	geometry spatialmath.Geometry
+	geometry          spatialmath.Geometry
+	allowedCollisions []AllowedCollision
+	tags              []string
+	metadata          map[string]string
 }
 
 // Geometry returns the Geometry of the LinkInFrame.
@@ -34,6 +37,21 @@ func (lF *LinkInFrame) Geometry() spatialmath.Geometry {
 	return lF.geometry
 }
 
+// AllowedCollisions returns the pairs of geometries which may touch, as declared in the config of the LinkInFrame.
+func (lF *LinkInFrame) AllowedCollisions() []AllowedCollision {
+	return lF.allowedCollisions
+}
+
+// Tags returns the tags of the frame, as declared in the config of the LinkInFrame.
+func (lF *LinkInFrame) Tags() []string {
+	return lF.tags
+}
+
+// Metadata returns the metadata of the frame, as declared in the config of the LinkInFrame.
+func (lF *LinkInFrame) Metadata() map[string]string {
+	return lF.metadata
+}
+
 // ToStaticFrame converts a LinkInFrame into a staticFrame with a new name.
 func (lF *LinkInFrame) ToStaticFrame(name string) (Frame, error) {
 	if name == "" {
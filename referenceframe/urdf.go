@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"math"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -24,41 +25,69 @@ type URDFConfig struct {
 
 // URDFLink is a struct which details the XML used in a URDF link element.
 type URDFLink struct {
-	XMLName   xml.Name `xml:"link"`
-	Name      string   `xml:"name,attr"`
-	Collision []struct {
-		XMLName xml.Name `xml:"collision"`
-		Name    string   `xml:"name,attr"`
-		Origin  struct {
-			XMLName xml.Name `xml:"origin"`
-			RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
-			XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-		} `xml:"origin"`
-		Geometry struct {
-			XMLName xml.Name `xml:"geometry"`
-			Box     struct {
-				XMLName xml.Name `xml:"box"`
-				Size    string   `xml:"size,attr"` // "x y z" format, in meters
-			} `xml:"box"`
-			Sphere struct {
-				XMLName xml.Name `xml:"sphere"`
-				Radius  float64  `xml:"radius,attr"` // in meters
-			} `xml:"sphere"`
-		} `xml:"geometry"`
-	} `xml:"collision"`
+	XMLName   xml.Name        `xml:"link"`
+	Name      string          `xml:"name,attr"`
+	Visual    []URDFVisual    `xml:"visual"`
+	Collision []URDFCollision `xml:"collision"`
+}
+
+// URDFCollision is a struct which details the XML used in a URDF collision element.
+type URDFCollision struct {
+	XMLName  xml.Name     `xml:"collision"`
+	Name     string       `xml:"name,attr"`
+	Origin   URDFOrigin   `xml:"origin"`
+	Geometry URDFGeometry `xml:"geometry"`
+}
+
+// URDFVisual is a struct which details the XML used in a URDF visual element, which is laid out as a collision element is.
+type URDFVisual struct {
+	XMLName  xml.Name     `xml:"visual"`
+	Name     string       `xml:"name,attr"`
+	Origin   URDFOrigin   `xml:"origin"`
+	Geometry URDFGeometry `xml:"geometry"`
+}
+
+// URDFOrigin is a struct which details the XML used in a URDF origin element.
+type URDFOrigin struct {
+	XMLName xml.Name `xml:"origin"`
+	RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
+	XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
+}
+
+// URDFGeometry is a struct which details the XML used in a URDF geometry element, of which only one shape is set.
+type URDFGeometry struct {
+	XMLName  xml.Name      `xml:"geometry"`
+	Box      *URDFBox      `xml:"box"`
+	Sphere   *URDFSphere   `xml:"sphere"`
+	Cylinder *URDFCylinder `xml:"cylinder"`
+}
+
+// URDFBox is a struct which details the XML used in a URDF box element.
+type URDFBox struct {
+	XMLName xml.Name `xml:"box"`
+	Size    string   `xml:"size,attr"` // "x y z" format, in meters
+}
+
+// URDFSphere is a struct which details the XML used in a URDF sphere element.
+type URDFSphere struct {
+	XMLName xml.Name `xml:"sphere"`
+	Radius  float64  `xml:"radius,attr"` // in meters
+}
+
+// URDFCylinder is a struct which details the XML used in a URDF cylinder element, whose axis is the z axis of its origin.
+type URDFCylinder struct {
+	XMLName xml.Name `xml:"cylinder"`
+	Radius  float64  `xml:"radius,attr"` // in meters
+	Length  float64  `xml:"length,attr"` // in meters
 }
 
 // URDFJoint is a struct which details the XML used in a URDF joint element.
 type URDFJoint struct {
-	XMLName xml.Name `xml:"joint"`
-	Name    string   `xml:"name,attr"`
-	Type    string   `xml:"type,attr"`
-	Origin  struct {
-		XMLName xml.Name `xml:"origin"`
-		RPY     string   `xml:"rpy,attr"` // Fixed frame angle "r p y" format, in radians
-		XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-	} `xml:"origin"`
-	Parent struct {
+	XMLName xml.Name   `xml:"joint"`
+	Name    string     `xml:"name,attr"`
+	Type    string     `xml:"type,attr"`
+	Origin  URDFOrigin `xml:"origin"`
+	Parent  struct {
 		XMLName xml.Name `xml:"parent"`
 		Link    string   `xml:"link,attr"`
 	} `xml:"parent"`
@@ -66,15 +95,23 @@ type URDFJoint struct {
 		XMLName xml.Name `xml:"child"`
 		Link    string   `xml:"link,attr"`
 	} `xml:"child"`
-	Axis struct {
-		XMLName xml.Name `xml:"axis"`
-		XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
-	} `xml:"axis"`
-	Limit struct {
-		XMLName xml.Name `xml:"limit"`
-		Lower   float64  `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
-		Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
-	} `xml:"limit"`
+	Axis  *URDFAxis  `xml:"axis"`
+	Limit *URDFLimit `xml:"limit"`
+}
+
+// URDFAxis is a struct which details the XML used in a URDF axis element.
+type URDFAxis struct {
+	XMLName xml.Name `xml:"axis"`
+	XYZ     string   `xml:"xyz,attr"` // "x y z" format, in meters
+}
+
+// URDFLimit is a struct which details the XML used in a URDF limit element.
+type URDFLimit struct {
+	XMLName  xml.Name `xml:"limit"`
+	Lower    float64  `xml:"lower,attr"`    // translation limits are in meters, revolute limits are in radians
+	Upper    float64  `xml:"upper,attr"`    // translation limits are in meters, revolute limits are in radians
+	Effort   float64  `xml:"effort,attr"`   // in N or Nm
+	Velocity float64  `xml:"velocity,attr"` // in meters/s or radians/s
 }
 
 // ParseURDFFile will read a given file and parse the contained URDF XML data into an equivalent ModelConfig struct.
@@ -137,7 +174,15 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 		switch jointElem.Type {
 		case ContinuousJoint, RevoluteJoint, PrismaticJoint:
 			// Parse important details about each joint, including axes and limits
-			jointAxes := convStringAttrToFloats(jointElem.Axis.XYZ)
+			// Per the URDF specification, joints without an axis element move along or about the x axis
+			jointAxes := []float64{1, 0, 0}
+			if jointElem.Axis != nil {
+				jointAxes = convStringAttrToFloats(jointElem.Axis.XYZ)
+			}
+			limit := URDFLimit{}
+			if jointElem.Limit != nil {
+				limit = *jointElem.Limit
+			}
 			thisJoint := JointConfig{
 				ID:     jointElem.Name,
 				Type:   jointElem.Type,
@@ -150,10 +195,13 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 			case ContinuousJoint:
 				thisJoint.Type = RevoluteJoint // Currently, we treate a continuous joint as a special case of a revolute joint
 				thisJoint.Min, thisJoint.Max = math.Inf(-1), math.Inf(1)
+				thisJoint.MaxVel = utils.RadToDeg(limit.Velocity)
 			case PrismaticJoint:
-				thisJoint.Min, thisJoint.Max = metersToMM(jointElem.Limit.Lower), metersToMM(jointElem.Limit.Upper)
+				thisJoint.Min, thisJoint.Max = metersToMM(limit.Lower), metersToMM(limit.Upper)
+				thisJoint.MaxVel = metersToMM(limit.Velocity)
 			case RevoluteJoint:
-				thisJoint.Min, thisJoint.Max = utils.RadToDeg(jointElem.Limit.Lower), utils.RadToDeg(jointElem.Limit.Upper)
+				thisJoint.Min, thisJoint.Max = utils.RadToDeg(limit.Lower), utils.RadToDeg(limit.Upper)
+				thisJoint.MaxVel = utils.RadToDeg(limit.Velocity)
 			default:
 				return nil, err
 			}
@@ -161,19 +209,7 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 			mc.Joints = append(mc.Joints, thisJoint)
 
 			// Generate child link translation and orientation data, which is held by this joint per the URDF design
-			childXYZ := convStringAttrToFloats(jointElem.Origin.XYZ)
-			childRPY := convStringAttrToFloats(jointElem.Origin.RPY)
-			childEA := spatial.EulerAngles{Roll: childRPY[0], Pitch: childRPY[1], Yaw: childRPY[2]}
-			childOrient, err := spatial.NewOrientationConfig(childEA.AxisAngles())
-
-			// Note the conversion from meters to mm
-			childLink.Translation = r3.Vector{
-				metersToMM(childXYZ[0]),
-				metersToMM(childXYZ[1]),
-				metersToMM(childXYZ[2]),
-			}
-			childLink.Orientation = childOrient
-
+			childLink.Translation, childLink.Orientation, err = parseURDFOrigin(jointElem.Origin)
 			if err != nil {
 				return nil, err
 			}
@@ -181,19 +217,7 @@ func ConvertURDFToConfig(xmlData []byte, modelName string) (*ModelConfig, error)
 			// Handle fixed joint -> static link conversion instead of adding to Joints[]
 			thisLink := LinkConfig{ID: jointElem.Name, Parent: jointElem.Parent.Link}
 
-			linkXYZ := convStringAttrToFloats(jointElem.Origin.XYZ)
-			linkRPY := convStringAttrToFloats(jointElem.Origin.RPY)
-			linkEA := spatial.EulerAngles{Roll: linkRPY[0], Pitch: linkRPY[1], Yaw: linkRPY[2]}
-			linkOrient, err := spatial.NewOrientationConfig(linkEA.AxisAngles())
-
-			// Note the conversion from meters to mm
-			thisLink.Translation = r3.Vector{
-				metersToMM(linkXYZ[0]),
-				metersToMM(linkXYZ[1]),
-				metersToMM(linkXYZ[2]),
-			}
-			thisLink.Orientation = linkOrient
-
+			thisLink.Translation, thisLink.Orientation, err = parseURDFOrigin(jointElem.Origin)
 			if err != nil {
 				return nil, err
 			}
@@ -264,29 +288,66 @@ func convStringAttrToFloats(attr string) []float64 {
 	return converted
 }
 
-// Convenience method to simplify creating geometry configs from URDF XML that has a collision element specified.
+// Convenience method to convert a URDF origin element, in meters and radians, to a translation in mm and an orientation. Per the URDF
+// specification, missing attributes are zero.
+func parseURDFOrigin(origin URDFOrigin) (r3.Vector, *spatial.OrientationConfig, error) {
+	xyz, rpy := []float64{0, 0, 0}, []float64{0, 0, 0}
+	if origin.XYZ != "" {
+		xyz = convStringAttrToFloats(origin.XYZ)
+	}
+	if origin.RPY != "" {
+		rpy = convStringAttrToFloats(origin.RPY)
+	}
+	if len(xyz) != 3 || len(rpy) != 3 {
+		return r3.Vector{}, nil, errors.Errorf("URDF origin needs three xyz and three rpy values, got xyz %q and rpy %q", origin.XYZ, origin.RPY)
+	}
+	ea := spatial.EulerAngles{Roll: rpy[0], Pitch: rpy[1], Yaw: rpy[2]}
+	orientation, err := spatial.NewOrientationConfig(ea.AxisAngles())
+	if err != nil {
+		return r3.Vector{}, nil, err
+	}
+	return r3.Vector{metersToMM(xyz[0]), metersToMM(xyz[1]), metersToMM(xyz[2])}, orientation, nil
+}
+
+// Convenience method to simplify creating geometry configs from URDF XML that has collision elements specified. A link holds one
+// geometry, which is made up of all of its collision elements. As URDF has no capsules, a capsule is made up of a cylinder capped by two
+// spheres, all of the same name.
 func createConfigFromCollision(link URDFLink) (spatial.GeometryConfig, error) {
-	var geoCfg spatial.GeometryConfig
-	boxGeometry := link.Collision[0].Geometry.Box
-	sphereGeometry := link.Collision[0].Geometry.Sphere
-
-	// Offset for the geometry origin from the reference link origin
-	geomXYZ := convStringAttrToFloats(link.Collision[0].Origin.XYZ)
-	geomTx := r3.Vector{geomXYZ[0], geomXYZ[1], geomXYZ[2]}
-	geomRPY := convStringAttrToFloats(link.Collision[0].Origin.RPY)
-	geomEA := spatial.EulerAngles{
-		Roll:  utils.RadToDeg(geomRPY[0]),
-		Pitch: utils.RadToDeg(geomRPY[1]),
-		Yaw:   utils.RadToDeg(geomRPY[2]),
+	configs := make([]spatial.GeometryConfig, 0, len(link.Collision))
+	for _, collision := range link.Collision {
+		geoCfg, err := createConfigFromCollisionElement(collision)
+		if err != nil {
+			return spatial.GeometryConfig{}, err
+		}
+		configs = append(configs, geoCfg)
+	}
+	if len(configs) == 1 {
+		return configs[0], nil
 	}
-	geomOx, err := spatial.NewOrientationConfig(geomEA.AxisAngles())
+	if capsuleCfg, ok := capsuleConfigFromCollisions(link.Collision, configs); ok {
+		return capsuleCfg, nil
+	}
+	return spatial.GeometryConfig{}, errors.Errorf(
+		"[ %v ] link has %d collision elements, which do not make up a single geometry", link.Name, len(link.Collision),
+	)
+}
+
+// Convenience method to create the geometry config of a single URDF collision element.
+func createConfigFromCollisionElement(collision URDFCollision) (spatial.GeometryConfig, error) {
+	var geoCfg spatial.GeometryConfig
+	boxGeometry := collision.Geometry.Box
+	sphereGeometry := collision.Geometry.Sphere
+	cylinderGeometry := collision.Geometry.Cylinder
+
+	// Offset for the geometry origin from the reference link origin, which is in meters and radians as the origins of joints are
+	geomTx, geomOx, err := parseURDFOrigin(collision.Origin)
 	if err != nil {
 		return spatial.GeometryConfig{}, err
 	}
 
 	// Logic specific to the geometry type
 	switch {
-	case len(boxGeometry.Size) > 0:
+	case boxGeometry != nil && len(boxGeometry.Size) > 0:
 		boxDims := convStringAttrToFloats(boxGeometry.Size)
 		geoCfg = spatial.GeometryConfig{
 			Type:              "box",
@@ -297,7 +358,7 @@ func createConfigFromCollision(link URDFLink) (spatial.GeometryConfig, error) {
 			OrientationOffset: *geomOx,
 			Label:             "box",
 		}
-	case sphereGeometry.Radius > 0:
+	case sphereGeometry != nil && sphereGeometry.Radius > 0:
 		sphereRadius := metersToMM(sphereGeometry.Radius)
 		geoCfg = spatial.GeometryConfig{
 			Type:              "sphere",
@@ -306,14 +367,216 @@ func createConfigFromCollision(link URDFLink) (spatial.GeometryConfig, error) {
 			OrientationOffset: *geomOx,
 			Label:             "sphere",
 		}
+	case cylinderGeometry != nil && cylinderGeometry.Radius > 0 && cylinderGeometry.Length > 0:
+		geoCfg = spatial.GeometryConfig{
+			Type:              spatial.CylinderType,
+			R:                 metersToMM(cylinderGeometry.Radius),
+			L:                 metersToMM(cylinderGeometry.Length),
+			TranslationOffset: geomTx,
+			OrientationOffset: *geomOx,
+			Label:             "cylinder",
+		}
 	default:
-		return spatial.GeometryConfig{}, errors.Errorf("Unsupported collision geometry type detected for [ %v ] link", link.Collision[0].Name)
+		return spatial.GeometryConfig{}, errors.Errorf("Unsupported collision geometry type detected for [ %v ] link", collision.Name)
 	}
 
 	return geoCfg, nil
 }
 
+// capsuleConfigFromCollisions returns the config of the capsule made up of the given collision elements and their configs, if they are
+// a cylinder capped at either end by spheres of its radius, all of the same name.
+func capsuleConfigFromCollisions(collisions []URDFCollision, configs []spatial.GeometryConfig) (spatial.GeometryConfig, bool) {
+	// origins are exported to the nanometer, so the spheres capping a capsule are found to within a micrometer
+	const capTolerance = 1e-3
+	if len(configs) != 3 || configs[0].Type != spatial.CylinderType {
+		return spatial.GeometryConfig{}, false
+	}
+	cylinder, err := configs[0].ParseConfig()
+	if err != nil {
+		return spatial.GeometryConfig{}, false
+	}
+	for i, end := range []float64{-1, 1} {
+		capCfg := configs[i+1]
+		if capCfg.Type != spatial.SphereType || collisions[i+1].Name != collisions[0].Name ||
+			math.Abs(capCfg.R-configs[0].R) > capTolerance {
+			return spatial.GeometryConfig{}, false
+		}
+		capCenter := spatial.Compose(cylinder.Pose(), spatial.NewPoseFromPoint(r3.Vector{Z: end * configs[0].L / 2})).Point()
+		if capCenter.Sub(capCfg.TranslationOffset).Norm() > capTolerance {
+			return spatial.GeometryConfig{}, false
+		}
+	}
+	capsuleCfg := configs[0]
+	capsuleCfg.Type = spatial.CapsuleType
+	capsuleCfg.L = configs[0].L + 2*configs[0].R
+	capsuleCfg.Label = "capsule"
+	return capsuleCfg, true
+}
+
 // Convenience function to change engineering unit scale for the given input.
 func metersToMM(valMeters float64) float64 {
 	return valMeters * 1000
 }
+
+// Convenience function to change engineering unit scale for the given input.
+func mmToMeters(valMM float64) float64 {
+	return valMM / 1000
+}
+
+// Convenience method to join up values into space-delimited fields in URDFs, such as xyz or rpy attributes. Values are written to the
+// nanometer or nanoradian, dropping the noise left by floating point arithmetic.
+func convFloatsToStringAttr(values ...float64) string {
+	attrSlice := make([]string, 0, len(values))
+	for _, value := range values {
+		attr := strings.TrimRight(strings.TrimRight(strconv.FormatFloat(value, 'f', 9, 64), "0"), ".")
+		if attr == "-0" {
+			attr = "0"
+		}
+		attrSlice = append(attrSlice, attr)
+	}
+	return strings.Join(attrSlice, " ")
+}
+
+// FrameSystemToURDF serializes a frame system, including the geometries of its frames and the transforms between them, into URDF XML
+// so that the layout of a robot can be visualized and verified in standard tools. Each frame becomes a link of the same name, attached
+// to the link of its parent frame by a joint named after it with a "_joint" suffix. Static frames are attached by fixed joints,
+// rotational and translational frames by revolute, continuous or prismatic joints, and mobile 2D frames by planar joints. The frames
+// of a model become links named as its geometries are, such as "arm:wrist_link", with the link named after the model at its end.
+func FrameSystemToURDF(fs FrameSystem) ([]byte, error) {
+	urdf := &URDFConfig{Name: fs.Name(), Links: []URDFLink{{Name: World}}}
+	names := fs.FrameNames()
+	sort.Strings(names)
+	for _, name := range names {
+		f := fs.Frame(name)
+		parent, err := fs.Parent(f)
+		if err != nil {
+			return nil, err
+		}
+		parentLink := parent.Name()
+		if model, ok := f.(*SimpleModel); ok {
+			for _, transform := range model.OrdTransforms {
+				linkName := model.Name() + ":" + transform.Name()
+				if err := urdf.addFrame(transform, parentLink, linkName, model.Name()+":"); err != nil {
+					return nil, err
+				}
+				parentLink = linkName
+			}
+			f = NewZeroStaticFrame(model.Name())
+		}
+		if err := urdf.addFrame(f, parentLink, name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return xml.MarshalIndent(urdf, "", "  ")
+}
+
+// addFrame adds a link representing a frame to the URDF, along with the joint attaching it to its parent link. The geometries of the
+// frame, which are positioned relative to its parent, are positioned relative to the link, with names prefixed by geometryPrefix.
+func (urdf *URDFConfig) addFrame(f Frame, parentLink, linkName, geometryPrefix string) error {
+	inputs := make([]Input, len(f.DoF()))
+	pose, err := f.Transform(inputs)
+	if pose == nil {
+		return err
+	}
+
+	joint := URDFJoint{Name: linkName + "_joint", Origin: urdfOriginFromPose(spatial.NewZeroPose())}
+	joint.Parent.Link = parentLink
+	joint.Child.Link = linkName
+	limits := f.DoF()
+	dynamics := DynamicLimitsOf(f)
+	switch jf := f.(type) {
+	case *rotationalFrame:
+		joint.Type = RevoluteJoint
+		joint.Axis = &URDFAxis{XYZ: convFloatsToStringAttr(jf.rotAxis.X, jf.rotAxis.Y, jf.rotAxis.Z)}
+		joint.Limit = &URDFLimit{Lower: limits[0].Min, Upper: limits[0].Max, Velocity: dynamics[0].MaxVelocity}
+		if math.IsInf(limits[0].Min, -1) || math.IsInf(limits[0].Max, 1) {
+			joint.Type = ContinuousJoint
+			joint.Limit = &URDFLimit{Velocity: dynamics[0].MaxVelocity}
+		}
+	case *translationalFrame:
+		joint.Type = PrismaticJoint
+		joint.Axis = &URDFAxis{XYZ: convFloatsToStringAttr(jf.transAxis.X, jf.transAxis.Y, jf.transAxis.Z)}
+		joint.Limit = &URDFLimit{
+			Lower:    mmToMeters(limits[0].Min),
+			Upper:    mmToMeters(limits[0].Max),
+			Velocity: mmToMeters(dynamics[0].MaxVelocity),
+		}
+	case *mobile2DFrame:
+		// planar joints move in the plane normal to their axis, and also turn about it
+		joint.Type = PlanarJoint
+		joint.Axis = &URDFAxis{XYZ: convFloatsToStringAttr(0, 0, 1)}
+	default:
+		if len(limits) > 0 {
+			return errors.Errorf("cannot serialize frame %q of type %T to URDF", f.Name(), f)
+		}
+		joint.Type = FixedJoint
+		joint.Origin = urdfOriginFromPose(pose)
+	}
+	urdf.Joints = append(urdf.Joints, joint)
+
+	link := URDFLink{Name: linkName}
+	// frames without geometries may return an error rather than an empty set of them
+	geometries, _ := f.Geometries(inputs)
+	if geometries != nil {
+		geometryNames := make([]string, 0, len(geometries.Geometries()))
+		for geometryName := range geometries.Geometries() {
+			geometryNames = append(geometryNames, geometryName)
+		}
+		sort.Strings(geometryNames)
+		for _, geometryName := range geometryNames {
+			geometry := geometries.Geometries()[geometryName].Transform(spatial.PoseInverse(pose))
+			collisions, err := urdfCollisionsFromGeometry(geometry, geometryPrefix+geometryName)
+			if err != nil {
+				return err
+			}
+			for _, collision := range collisions {
+				link.Visual = append(link.Visual, URDFVisual(collision))
+			}
+			link.Collision = append(link.Collision, collisions...)
+		}
+	}
+	urdf.Links = append(urdf.Links, link)
+	return nil
+}
+
+// urdfCollisionsFromGeometry returns the URDF collision elements making up a geometry. URDF has no capsules, so a capsule is made up of
+// a cylinder capped by two spheres.
+func urdfCollisionsFromGeometry(geometry spatial.Geometry, name string) ([]URDFCollision, error) {
+	cfg, err := spatial.NewGeometryConfig(geometry)
+	if err != nil {
+		return nil, err
+	}
+	pose := geometry.Pose()
+	collision := URDFCollision{Name: name, Origin: urdfOriginFromPose(pose)}
+	switch cfg.Type {
+	case spatial.BoxType:
+		collision.Geometry.Box = &URDFBox{Size: convFloatsToStringAttr(mmToMeters(cfg.X), mmToMeters(cfg.Y), mmToMeters(cfg.Z))}
+	case spatial.SphereType:
+		collision.Geometry.Sphere = &URDFSphere{Radius: mmToMeters(cfg.R)}
+	case spatial.CylinderType:
+		collision.Geometry.Cylinder = &URDFCylinder{Radius: mmToMeters(cfg.R), Length: mmToMeters(cfg.L)}
+	case spatial.CapsuleType:
+		collision.Geometry.Cylinder = &URDFCylinder{Radius: mmToMeters(cfg.R), Length: mmToMeters(cfg.L - 2*cfg.R)}
+		collisions := []URDFCollision{collision}
+		for _, end := range []float64{-1, 1} {
+			capPose := spatial.Compose(pose, spatial.NewPoseFromPoint(r3.Vector{Z: end * (cfg.L/2 - cfg.R)}))
+			capCollision := URDFCollision{Name: name, Origin: urdfOriginFromPose(capPose)}
+			capCollision.Geometry.Sphere = &URDFSphere{Radius: mmToMeters(cfg.R)}
+			collisions = append(collisions, capCollision)
+		}
+		return collisions, nil
+	default:
+		return nil, errors.Errorf("cannot serialize geometry %q of type %s to URDF", name, cfg.Type)
+	}
+	return []URDFCollision{collision}, nil
+}
+
+// urdfOriginFromPose returns the URDF origin element positioned at a pose, note the conversion from mm to meters.
+func urdfOriginFromPose(pose spatial.Pose) URDFOrigin {
+	pt := pose.Point()
+	ea := pose.Orientation().EulerAngles()
+	return URDFOrigin{
+		RPY: convFloatsToStringAttr(ea.Roll, ea.Pitch, ea.Yaw),
+		XYZ: convFloatsToStringAttr(mmToMeters(pt.X), mmToMeters(pt.Y), mmToMeters(pt.Z)),
+	}
+}
@@ -1,9 +1,13 @@
 package referenceframe
 
 import (
+	"encoding/xml"
+	"math"
 	"math/rand"
 	"testing"
 
+	"github.com/edaniels/golog"
+	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 
 	spatial "go.viam.com/rdk/spatialmath"
@@ -86,3 +90,168 @@ func TestURDFGeometries(t *testing.T) {
 	modelGeo, _ = ur5ViamModel.Geometries(inputs)
 	test.That(t, len(modelGeo.geometries), test.ShouldEqual, 5)
 }
+
+func TestURDFCollisionOrigin(t *testing.T) {
+	xmlData := []byte(`<robot name="test">
+  <link name="base_link"/>
+  <joint name="base_joint" type="fixed">
+    <origin rpy="0 0 0" xyz="0 0 0.5"/>
+    <parent link="base_link"/>
+    <child link="tool_link"/>
+  </joint>
+  <link name="tool_link">
+    <collision name="tool">
+      <origin rpy="0 0 1.5707963267948966" xyz="0.1 0.2 0.3"/>
+      <geometry><box size="0.1 0.2 0.3"/></geometry>
+    </collision>
+  </link>
+</robot>`)
+	mc, err := ConvertURDFToConfig(xmlData, "test")
+	test.That(t, err, test.ShouldBeNil)
+	var geometryCfg *spatial.GeometryConfig
+	for _, link := range mc.Links {
+		if link.ID == "tool_link" {
+			geometryCfg = link.Geometry
+		}
+	}
+	test.That(t, geometryCfg, test.ShouldNotBeNil)
+
+	// collision origins are in meters and radians, as joint origins are
+	geometry, err := geometryCfg.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	expected := spatial.NewPose(r3.Vector{X: 100, Y: 200, Z: 300}, &spatial.EulerAngles{Yaw: math.Pi / 2})
+	test.That(t, spatial.PoseAlmostEqual(geometry.Pose(), expected), test.ShouldBeTrue)
+
+	// origins may be left out, in which case they are at the link origin
+	xmlData = []byte(`<robot name="test">
+  <link name="tool_link">
+    <collision name="tool"><geometry><sphere radius="0.1"/></geometry></collision>
+  </link>
+</robot>`)
+	mc, err = ConvertURDFToConfig(xmlData, "test")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mc.Links, test.ShouldHaveLength, 1)
+	geometry, err = mc.Links[0].Geometry.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostEqual(geometry.Pose(), spatial.NewZeroPose()), test.ShouldBeTrue)
+}
+
+func TestFrameSystemToURDF(t *testing.T) {
+	fs := NewEmptySimpleFrameSystem("test")
+	offsetBox, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{Z: -50}), r3.Vector{X: 200, Y: 100, Z: 100}, "")
+	test.That(t, err, test.ShouldBeNil)
+	offset, err := NewStaticFrameWithGeometry(
+		"offset",
+		spatial.NewPose(r3.Vector{X: 100, Y: -200, Z: 300}, &spatial.OrientationVectorDegrees{OY: 1, Theta: 30}),
+		offsetBox,
+	)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(offset, fs.World()), test.ShouldBeNil)
+	railBox, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 50, Y: 50, Z: 50}, "")
+	test.That(t, err, test.ShouldBeNil)
+	rail, err := NewTranslationalFrameWithGeometry("rail", r3.Vector{X: 1}, Limit{Min: 0, Max: 1000}, railBox)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(rail, offset), test.ShouldBeNil)
+	arm, err := ParseURDFFile(utils.ResolveFile("referenceframe/testurdf/ur5_viam.urdf"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(arm, rail), test.ShouldBeNil)
+	gripperSphere, err := spatial.NewSphere(spatial.NewPoseFromPoint(r3.Vector{Z: 40}), 30, "")
+	test.That(t, err, test.ShouldBeNil)
+	gripper, err := NewStaticFrameWithGeometry("gripper", spatial.NewPoseFromPoint(r3.Vector{Z: 80}), gripperSphere)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gripper, arm), test.ShouldBeNil)
+
+	xmlData, err := FrameSystemToURDF(fs)
+	test.That(t, err, test.ShouldBeNil)
+	urdf := &URDFConfig{}
+	test.That(t, xml.Unmarshal(xmlData, urdf), test.ShouldBeNil)
+	test.That(t, urdf.Name, test.ShouldEqual, "test")
+	test.That(t, len(urdf.Joints), test.ShouldEqual, len(urdf.Links)-1)
+
+	// parsing the URDF back gives a model moving and occupying space as the frame system does, to the precision it is written to
+	mc, err := ConvertURDFToConfig(xmlData, "exported")
+	test.That(t, err, test.ShouldBeNil)
+	model, err := mc.ParseConfig("exported")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(model.DoF()), test.ShouldEqual, 7)
+	randSeed := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		inputs := FloatsToInputs(GenerateRandomConfiguration(model, randSeed))
+		fsInputs := map[string][]Input{"rail": inputs[:1], "arm": inputs[1:]}
+		pose, err := model.Transform(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		fsPose, err := fs.Transform(fsInputs, NewPoseInFrame("gripper", spatial.NewZeroPose()), World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.PoseAlmostCoincidentEps(pose, fsPose.(*PoseInFrame).Pose(), 1e-3), test.ShouldBeTrue)
+
+		geometries, err := model.Geometries(inputs)
+		test.That(t, err, test.ShouldBeNil)
+		fsGeometries, err := FrameSystemGeometries(fs, fsInputs, golog.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		count := 0
+		for _, gif := range fsGeometries {
+			for name, fsGeometry := range gif.Geometries() {
+				geometry, ok := geometries.Geometries()["exported:"+name]
+				test.That(t, ok, test.ShouldBeTrue)
+				test.That(t, spatial.PoseAlmostCoincidentEps(geometry.Pose(), fsGeometry.Pose(), 1e-3), test.ShouldBeTrue)
+				count++
+			}
+		}
+		test.That(t, count, test.ShouldEqual, len(geometries.Geometries()))
+	}
+
+	// capsules are made up of a cylinder and two spheres
+	capsule, err := spatial.NewCapsule(spatial.NewZeroPose(), 10, 100, "")
+	test.That(t, err, test.ShouldBeNil)
+	collisions, err := urdfCollisionsFromGeometry(capsule, "capsule")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(collisions), test.ShouldEqual, 3)
+	test.That(t, collisions[0].Geometry.Cylinder, test.ShouldResemble, &URDFCylinder{Radius: 0.01, Length: 0.08})
+	test.That(t, collisions[1].Origin.XYZ, test.ShouldEqual, "0 0 -0.04")
+	test.That(t, collisions[2].Geometry.Sphere, test.ShouldResemble, &URDFSphere{Radius: 0.01})
+
+	// and are parsed back as a whole
+	capsulePose := spatial.NewPose(r3.Vector{X: 10, Y: 20}, &spatial.OrientationVectorDegrees{OX: 1, Theta: 30})
+	capsule, err = spatial.NewCapsule(capsulePose, 10, 100, "")
+	test.That(t, err, test.ShouldBeNil)
+	collisions, err = urdfCollisionsFromGeometry(capsule, "capsule")
+	test.That(t, err, test.ShouldBeNil)
+	geometryCfg, err := createConfigFromCollision(URDFLink{Name: "capsule_link", Collision: collisions})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometryCfg.Type, test.ShouldEqual, spatial.CapsuleType)
+	parsedCapsule, err := geometryCfg.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parsedCapsule.AlmostEqual(capsule), test.ShouldBeTrue)
+
+	// collision elements which do not make up one geometry are rejected rather than dropped
+	collisions[2].Name = "other"
+	_, err = createConfigFromCollision(URDFLink{Name: "capsule_link", Collision: collisions})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "capsule_link")
+
+	// cylinders are read back as they are written
+	cylinder, err := spatial.NewCylinder(spatial.NewPoseFromPoint(r3.Vector{X: 10}), 10, 100, "cylinder")
+	test.That(t, err, test.ShouldBeNil)
+	collisions, err = urdfCollisionsFromGeometry(cylinder, "cylinder")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(collisions), test.ShouldEqual, 1)
+	test.That(t, collisions[0].Geometry.Cylinder, test.ShouldResemble, &URDFCylinder{Radius: 0.01, Length: 0.1})
+	cfg, err := createConfigFromCollision(URDFLink{Collision: collisions})
+	test.That(t, err, test.ShouldBeNil)
+	parsed, err := cfg.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parsed.AlmostEqual(cylinder), test.ShouldBeTrue)
+
+	// frames which cannot be represented in URDF are rejected
+	test.That(t, fs.AddFrame(&simpleFrameWithUnknownDoF{NewZeroStaticFrame("unknown")}, fs.World()), test.ShouldBeNil)
+	_, err = FrameSystemToURDF(fs)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+type simpleFrameWithUnknownDoF struct {
+	Frame
+}
+
+func (f *simpleFrameWithUnknownDoF) DoF() []Limit {
+	return []Limit{{Min: -1, Max: 1}}
+}
@@ -0,0 +1,98 @@
+package referenceframe
+
+import (
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+// singularValueTolerance is the fraction of the largest singular value of a jacobian below which the others are taken to be zero.
+const singularValueTolerance = 1e-10
+
+// InputWithVelocity is the input to a mutable frame along with how fast it is changing, in radians per second for revolute inputs and
+// mm per second for prismatic ones, for velocity level kinematics such as servoing.
+type InputWithVelocity struct {
+	Input
+	Velocity float64
+}
+
+// InputsWithVelocities pairs each input with its velocity.
+func InputsWithVelocities(inputs []Input, velocities []float64) ([]InputWithVelocity, error) {
+	if len(velocities) != len(inputs) {
+		return nil, NewIncorrectInputLengthError(len(velocities), len(inputs))
+	}
+	paired := make([]InputWithVelocity, 0, len(inputs))
+	for i, input := range inputs {
+		paired = append(paired, InputWithVelocity{Input: input, Velocity: velocities[i]})
+	}
+	return paired, nil
+}
+
+// SplitInputsWithVelocities returns the inputs and the velocities of inputs with velocities.
+func SplitInputsWithVelocities(paired []InputWithVelocity) ([]Input, []float64) {
+	inputs := make([]Input, 0, len(paired))
+	velocities := make([]float64, 0, len(paired))
+	for _, p := range paired {
+		inputs = append(inputs, p.Input)
+		velocities = append(velocities, p.Velocity)
+	}
+	return inputs, velocities
+}
+
+// FrameTwist returns the twist of the end of a frame moving at the given inputs and input velocities, expressed in the frame's parent.
+func FrameTwist(f Frame, inputs []InputWithVelocity) (spatial.Twist, error) {
+	positions, velocities := SplitInputsWithVelocities(inputs)
+	jacobian, err := Jacobian(f, positions)
+	if err != nil {
+		return spatial.Twist{}, err
+	}
+	var twist mat.VecDense
+	twist.MulVec(jacobian, mat.NewVecDense(len(velocities), velocities))
+	return spatial.Twist{
+		Linear:  r3.Vector{X: twist.AtVec(0), Y: twist.AtVec(1), Z: twist.AtVec(2)},
+		Angular: r3.Vector{X: twist.AtVec(3), Y: twist.AtVec(4), Z: twist.AtVec(5)},
+	}, nil
+}
+
+// InputVelocities returns the input velocities of a frame at the given inputs which best move the end of the frame at a twist,
+// expressed in the frame's parent, found by damped least squares. Without damping this is the least squares solution, which is exact
+// wherever the frame can move at the twist, and otherwise the closest it can come, but which grows without bound near singularities.
+// Damping trades accuracy near singularities for keeping the velocities bounded, scaling down the motion in directions the frame can
+// move in less readily than the damping. As the jacobian mixes mm and radians, damping is in the same mixed units as its singular
+// values, see JacobianConditionNumber.
+func InputVelocities(f Frame, inputs []Input, twist spatial.Twist, damping float64) ([]float64, error) {
+	if damping < 0 {
+		return nil, errors.New("damping cannot be negative")
+	}
+	jacobian, err := Jacobian(f, inputs)
+	if err != nil {
+		return nil, err
+	}
+	var svd mat.SVD
+	if ok := svd.Factorize(jacobian, mat.SVDThin); !ok {
+		return nil, errors.New("failed to factorize jacobian")
+	}
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	target := mat.NewVecDense(6, []float64{
+		twist.Linear.X, twist.Linear.Y, twist.Linear.Z,
+		twist.Angular.X, twist.Angular.Y, twist.Angular.Z,
+	})
+
+	// the damped pseudoinverse scales each singular direction by s / (s^2 + damping^2) in place of 1 / s
+	velocities := mat.NewVecDense(len(inputs), nil)
+	values := svd.Values(nil)
+	for i, value := range values {
+		// without damping, directions the frame cannot move in at all are left out, rather than moved in without bound
+		if damping == 0 && value <= singularValueTolerance*values[0] {
+			continue
+		}
+		denominator := value*value + damping*damping
+		along := mat.Dot(u.ColView(i), target) * value / denominator
+		velocities.AddScaledVec(velocities, along, v.ColView(i))
+	}
+	return velocities.RawVector().Data, nil
+}
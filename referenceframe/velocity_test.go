@@ -0,0 +1,89 @@
+package referenceframe
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestInputVelocities(t *testing.T) {
+	ur5e, err := ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	randSeed := rand.New(rand.NewSource(1))
+	inputs := FloatsToInputs(GenerateRandomConfiguration(ur5e, randSeed))
+	velocities := []float64{0.1, -0.2, 0.3, 0.05, -0.1, 0.2}
+
+	paired, err := InputsWithVelocities(inputs, velocities)
+	test.That(t, err, test.ShouldBeNil)
+	splitInputs, splitVelocities := SplitInputsWithVelocities(paired)
+	test.That(t, splitInputs, test.ShouldResemble, inputs)
+	test.That(t, splitVelocities, test.ShouldResemble, velocities)
+	_, err = InputsWithVelocities(inputs, velocities[:2])
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the twist is how the end of the arm moves over a short time at the input velocities
+	twist, err := FrameTwist(ur5e, paired)
+	test.That(t, err, test.ShouldBeNil)
+	dt := 1e-6
+	moved := make([]Input, 0, len(inputs))
+	for i, input := range inputs {
+		moved = append(moved, Input{Value: input.Value + velocities[i]*dt})
+	}
+	before, err := ur5e.Transform(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	after, err := ur5e.Transform(moved)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.R3VectorAlmostEqual(after.Point().Sub(before.Point()).Mul(1/dt), twist.Linear, 1e-3), test.ShouldBeTrue)
+	// the vector part of the quaternion of the rotation from before to after, in the parent of the arm, is the sine of half its angle
+	turned := quat.Mul(after.Orientation().Quaternion(), quat.Conj(before.Orientation().Quaternion()))
+	test.That(t, spatial.R3VectorAlmostEqual(r3.Vector{X: turned.Imag, Y: turned.Jmag, Z: turned.Kmag}.Mul(2/dt), twist.Angular, 1e-3),
+		test.ShouldBeTrue)
+
+	// away from singularities, the input velocities moving at a twist are recovered without damping, and nearly so with a little
+	found, err := InputVelocities(ur5e, inputs, twist, 0)
+	test.That(t, err, test.ShouldBeNil)
+	for i, velocity := range found {
+		test.That(t, velocity, test.ShouldAlmostEqual, velocities[i], 1e-6)
+	}
+	damped, err := InputVelocities(ur5e, inputs, twist, 0.1)
+	test.That(t, err, test.ShouldBeNil)
+	for i, velocity := range damped {
+		test.That(t, velocity, test.ShouldAlmostEqual, velocities[i], 1e-2)
+	}
+	_, err = InputVelocities(ur5e, inputs, twist, -1)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// with its wrist straight the arm is at a singularity, where there is a twist it cannot move at, and damping keeps its velocities
+	// bounded near there
+	inputs[4] = Input{}
+	singular, err := Jacobian(ur5e, inputs)
+	test.That(t, err, test.ShouldBeNil)
+	var svd mat.SVD
+	test.That(t, svd.Factorize(singular, mat.SVDThin), test.ShouldBeTrue)
+	values := svd.Values(nil)
+	test.That(t, values[len(values)-1]/values[0], test.ShouldBeLessThan, 1e-10)
+	var u mat.Dense
+	svd.UTo(&u)
+	stuck := spatial.Twist{
+		Linear:  r3.Vector{X: u.At(0, 5), Y: u.At(1, 5), Z: u.At(2, 5)},
+		Angular: r3.Vector{X: u.At(3, 5), Y: u.At(4, 5), Z: u.At(5, 5)},
+	}
+	found, err = InputVelocities(ur5e, inputs, stuck, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mat.Norm(mat.NewVecDense(6, found), 2), test.ShouldAlmostEqual, 0)
+
+	inputs[4] = Input{Value: 1e-4}
+	undamped, err := InputVelocities(ur5e, inputs, stuck, 0)
+	test.That(t, err, test.ShouldBeNil)
+	damped, err = InputVelocities(ur5e, inputs, stuck, 0.1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mat.Norm(mat.NewVecDense(6, undamped), 2), test.ShouldBeGreaterThan, 1000)
+	test.That(t, mat.Norm(mat.NewVecDense(6, damped), 2), test.ShouldBeLessThan, 10)
+}
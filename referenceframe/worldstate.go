@@ -2,7 +2,9 @@ package referenceframe
 
 import (
 	"strconv"
+	"time"
 
+	"github.com/golang/geo/r3"
 	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 
@@ -13,6 +15,153 @@ import (
 type WorldState struct {
 	Obstacles  []*GeometriesInFrame
 	Transforms []*LinkInFrame
+	// Obstacles which move while a motion is carried out, such as objects on a conveyor belt or other robots. They are not part of the
+	// protobuf definition of a WorldState, so are lost converting to and from it.
+	MovingObstacles []*MovingObstacle
+}
+
+// MovingObstacle is an obstacle whose geometries move over time, either at a constant velocity or along a trajectory. Times are
+// measured from the start of a motion, at which the geometries are where they are given.
+type MovingObstacle struct {
+	Geometries *GeometriesInFrame
+	// Velocity of the geometries in mm/s, in the frame they are given in. It is ignored if the obstacle has a trajectory.
+	Velocity r3.Vector
+	// Poses the geometries are moved by at given times, in order of time, in the frame they are given in. Between two poses the
+	// geometries move along the interpolation between them, and they stay at the first and last pose before and after the trajectory.
+	Trajectory []TimedPose
+}
+
+// TimedPose is a pose at a time since the start of a motion.
+type TimedPose struct {
+	Time time.Duration
+	Pose spatial.Pose
+}
+
+// OffsetAt returns the pose the geometries of the obstacle are moved by at a time, in the frame they are given in.
+func (mo *MovingObstacle) OffsetAt(t time.Duration) spatial.Pose {
+	traj := mo.Trajectory
+	if len(traj) == 0 {
+		return spatial.NewPoseFromPoint(mo.Velocity.Mul(t.Seconds()))
+	}
+	if t <= traj[0].Time {
+		return traj[0].Pose
+	}
+	for i := 1; i < len(traj); i++ {
+		if t <= traj[i].Time {
+			by := float64(t-traj[i-1].Time) / float64(traj[i].Time-traj[i-1].Time)
+			return spatial.Interpolate(traj[i-1].Pose, traj[i].Pose, by)
+		}
+	}
+	return traj[len(traj)-1].Pose
+}
+
+// GeometriesAt returns the geometries of the obstacle where they are at a time.
+func (mo *MovingObstacle) GeometriesAt(t time.Duration) *GeometriesInFrame {
+	offset := mo.OffsetAt(t)
+	geometries := make(map[string]spatial.Geometry, len(mo.Geometries.Geometries()))
+	for name, geometry := range mo.Geometries.Geometries() {
+		geometries[name] = geometry.Transform(offset)
+	}
+	return NewGeometriesInFrame(mo.Geometries.Parent(), geometries)
+}
+
+// validate returns an error if the trajectory of the obstacle goes back in time.
+func (mo *MovingObstacle) validate() error {
+	if mo.Geometries == nil {
+		return errors.New("moving obstacle has no geometries")
+	}
+	for i := 1; i < len(mo.Trajectory); i++ {
+		if mo.Trajectory[i].Time <= mo.Trajectory[i-1].Time {
+			return errors.New("the poses of a moving obstacle's trajectory must be in order of time")
+		}
+	}
+	return nil
+}
+
+// Merge returns a WorldState with the obstacles and transforms of this WorldState followed by those of the others, which may be nil.
+// Geometries with a label are de-duplicated by their frame and label, with those of later WorldStates replacing those with the same frame
+// and label before them, so that an obstacle reported by several sources is only avoided where the latest of them places it. Geometries
+// without a label are all kept, as their names, such as those given by their index converting from protobuf, do not identify them.
+// Transforms are de-duplicated by name in the same way.
+func (ws *WorldState) Merge(others ...*WorldState) *WorldState {
+	states := append([]*WorldState{ws}, others...)
+
+	// find the last set of geometries with each frame and label, and the last transform with each name, counting them across all the
+	// WorldStates
+	type geometryKey struct{ frame, label string }
+	lastGeometries := map[geometryKey]int{}
+	lastTransforms := map[string]int{}
+	geometriesIdx, transformIdx := 0, 0
+	countGeometries := func(gf *GeometriesInFrame) {
+		if gf != nil {
+			for _, geometry := range gf.Geometries() {
+				if geometry.Label() != "" {
+					lastGeometries[geometryKey{gf.Parent(), geometry.Label()}] = geometriesIdx
+				}
+			}
+		}
+		geometriesIdx++
+	}
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		for _, obstacle := range state.Obstacles {
+			countGeometries(obstacle)
+		}
+		for _, mo := range state.MovingObstacles {
+			countGeometries(mo.Geometries)
+		}
+		for _, transform := range state.Transforms {
+			lastTransforms[transform.Name()] = transformIdx
+			transformIdx++
+		}
+	}
+
+	merged := &WorldState{}
+	geometriesIdx, transformIdx = 0, 0
+	keptGeometries := func(gf *GeometriesInFrame) *GeometriesInFrame {
+		defer func() { geometriesIdx++ }()
+		if gf == nil {
+			return nil
+		}
+		kept := map[string]spatial.Geometry{}
+		for name, geometry := range gf.Geometries() {
+			if geometry.Label() == "" || lastGeometries[geometryKey{gf.Parent(), geometry.Label()}] == geometriesIdx {
+				kept[name] = geometry
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return NewGeometriesInFrame(gf.Parent(), kept)
+	}
+	for _, state := range states {
+		if state == nil {
+			continue
+		}
+		for _, obstacle := range state.Obstacles {
+			if kept := keptGeometries(obstacle); kept != nil {
+				merged.Obstacles = append(merged.Obstacles, kept)
+			}
+		}
+		for _, mo := range state.MovingObstacles {
+			if kept := keptGeometries(mo.Geometries); kept != nil {
+				merged.MovingObstacles = append(merged.MovingObstacles, &MovingObstacle{
+					Geometries: kept,
+					Velocity:   mo.Velocity,
+					Trajectory: mo.Trajectory,
+				})
+			}
+		}
+		for _, transform := range state.Transforms {
+			if transform.Name() == "" || lastTransforms[transform.Name()] == transformIdx {
+				merged.Transforms = append(merged.Transforms, transform)
+			}
+			transformIdx++
+		}
+	}
+	return merged
 }
 
 // WorldStateFromProtobuf takes the protobuf definition of a WorldState and converts it to a rdk defined WorldState.
@@ -67,6 +216,33 @@ func WorldStateToProtobuf(worldState *WorldState) (*commonpb.WorldState, error)
 	}, nil
 }
 
+// worldFrameObstacleName returns the name ToWorldFrame gives a geometry of the obstacles, by the index of the GeometriesInFrame it is in
+// and its name there, which keeps geometries of the same name in different GeometriesInFrame apart.
+func worldFrameObstacleName(index int, name string) string {
+	return strconv.Itoa(index) + "_" + name
+}
+
+// ObstacleWorldFrameNames returns the names ToWorldFrame gives the geometries of the obstacles, keyed by the labels of the geometries and
+// by those labels qualified by the frames the geometries are given in, as "frame:label". Geometries without labels are left out.
+func (ws *WorldState) ObstacleWorldFrameNames() map[string][]string {
+	names := map[string][]string{}
+	if ws == nil {
+		return names
+	}
+	for i, gf := range ws.Obstacles {
+		for name, g := range gf.Geometries() {
+			if g.Label() == "" {
+				continue
+			}
+			worldName := worldFrameObstacleName(i, name)
+			names[g.Label()] = append(names[g.Label()], worldName)
+			qualified := gf.Parent() + ":" + g.Label()
+			names[qualified] = append(names[qualified], worldName)
+		}
+	}
+	return names
+}
+
 // ToWorldFrame takes a frame system and a set of inputs for that frame system and converts all the geometries
 // in the WorldState such that they are in the frame system's World reference frame.
 func (ws *WorldState) ToWorldFrame(fs FrameSystem, inputs map[string][]Input) (*WorldState, error) {
@@ -78,7 +254,7 @@ func (ws *WorldState) ToWorldFrame(fs FrameSystem, inputs map[string][]Input) (*
 				return nil, err
 			}
 			for name2, g := range tf.(*GeometriesInFrame).Geometries() {
-				geomName := strconv.Itoa(name1) + "_" + name2
+				geomName := worldFrameObstacleName(name1, name2)
 				if _, present := allGeometries[geomName]; present {
 					return nil, errors.New("multiple geometries with the same name")
 				}
@@ -95,7 +271,50 @@ func (ws *WorldState) ToWorldFrame(fs FrameSystem, inputs map[string][]Input) (*
 	if err != nil {
 		return nil, err
 	}
+
+	// moving obstacles keep their own geometries, with their motion expressed in the world frame
+	movingObstacles := make([]*MovingObstacle, 0, len(ws.MovingObstacles))
+	for _, mo := range ws.MovingObstacles {
+		if err := mo.validate(); err != nil {
+			return nil, err
+		}
+		// geometries in a frame are transformed from the parent of the frame, see Transform, so their motion is as well
+		motionFrame := mo.Geometries.Parent()
+		if motionFrame != World {
+			f := fs.Frame(motionFrame)
+			if f == nil {
+				return nil, NewFrameMissingError(motionFrame)
+			}
+			parent, err := fs.Parent(f)
+			if err != nil {
+				return nil, err
+			}
+			motionFrame = parent.Name()
+		}
+		tf, err := fs.Transform(inputs, NewPoseInFrame(motionFrame, spatial.NewZeroPose()), World)
+		if err != nil {
+			return nil, err
+		}
+		toWorld := tf.(*PoseInFrame).Pose()
+		geometries, err := fs.Transform(inputs, mo.Geometries, World)
+		if err != nil {
+			return nil, err
+		}
+		rotation := spatial.NewPoseFromOrientation(toWorld.Orientation())
+		worldObstacle := &MovingObstacle{
+			Geometries: geometries.(*GeometriesInFrame),
+			Velocity:   spatial.Compose(rotation, spatial.NewPoseFromPoint(mo.Velocity)).Point(),
+		}
+		for _, timedPose := range mo.Trajectory {
+			worldObstacle.Trajectory = append(worldObstacle.Trajectory, TimedPose{
+				Time: timedPose.Time,
+				Pose: spatial.Compose(spatial.Compose(toWorld, timedPose.Pose), spatial.PoseInverse(toWorld)),
+			})
+		}
+		movingObstacles = append(movingObstacles, worldObstacle)
+	}
 	return &WorldState{
-		Obstacles: []*GeometriesInFrame{obstacles},
+		Obstacles:       []*GeometriesInFrame{obstacles},
+		MovingObstacles: movingObstacles,
 	}, nil
 }
@@ -0,0 +1,71 @@
+package referenceframe
+
+import "sync"
+
+// WorldStateSources composes a WorldState from those reported by several sources, such as a vision service, the static config of a
+// robot and a SLAM map, each labelled by the name of its source. Setting the WorldState of a source replaces everything it reported
+// before at once, so that no stale obstacles of it are left behind. It is safe for concurrent use.
+type WorldStateSources struct {
+	mu      sync.RWMutex
+	sources []string // in the order they were first set
+	states  map[string]*WorldState
+}
+
+// NewWorldStateSources creates a WorldStateSources with no sources.
+func NewWorldStateSources() *WorldStateSources {
+	return &WorldStateSources{states: map[string]*WorldState{}}
+}
+
+// Set replaces the WorldState reported by a source, which must not be modified afterwards. Sources are merged in the order they were
+// first set, so where they report geometries of the same frame and label, or transforms of the same name, those of the source set first
+// are replaced.
+func (s *WorldStateSources) Set(source string, ws *WorldState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.states[source]; !ok {
+		s.sources = append(s.sources, source)
+	}
+	s.states[source] = ws
+}
+
+// Remove removes everything reported by a source.
+func (s *WorldStateSources) Remove(source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.states[source]; !ok {
+		return
+	}
+	delete(s.states, source)
+	for i, name := range s.sources {
+		if name == source {
+			s.sources = append(s.sources[:i], s.sources[i+1:]...)
+			break
+		}
+	}
+}
+
+// Get returns the WorldState reported by a source, and whether it has reported one.
+func (s *WorldStateSources) Get(source string) (*WorldState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ws, ok := s.states[source]
+	return ws, ok
+}
+
+// Sources returns the names of the sources, in the order they were first set.
+func (s *WorldStateSources) Sources() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string{}, s.sources...)
+}
+
+// WorldState returns the merge of the WorldStates reported by all the sources, see WorldState.Merge.
+func (s *WorldStateSources) WorldState() *WorldState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	states := make([]*WorldState, 0, len(s.sources))
+	for _, source := range s.sources {
+		states = append(states, s.states[source])
+	}
+	return (&WorldState{}).Merge(states...)
+}
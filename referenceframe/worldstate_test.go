@@ -0,0 +1,183 @@
+package referenceframe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+)
+
+func TestMovingObstacle(t *testing.T) {
+	box, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: 10}), r3.Vector{X: 2, Y: 2, Z: 2}, "")
+	test.That(t, err, test.ShouldBeNil)
+	geometries := NewGeometriesInFrame("belt", map[string]spatial.Geometry{"box": box})
+
+	// an obstacle with a velocity moves at it forever
+	mo := &MovingObstacle{Geometries: geometries, Velocity: r3.Vector{Y: 100}}
+	test.That(t, spatial.PoseAlmostCoincident(mo.OffsetAt(0), spatial.NewZeroPose()), test.ShouldBeTrue)
+	moved := mo.GeometriesAt(1500 * time.Millisecond)
+	test.That(t, moved.Parent(), test.ShouldEqual, "belt")
+	test.That(t, spatial.R3VectorAlmostEqual(moved.Geometries()["box"].Pose().Point(), r3.Vector{X: 10, Y: 150}, 1e-9), test.ShouldBeTrue)
+
+	// one with a trajectory follows it, staying at its ends before and after
+	mo.Trajectory = []TimedPose{
+		{Time: time.Second, Pose: spatial.NewZeroPose()},
+		{Time: 3 * time.Second, Pose: spatial.NewPoseFromPoint(r3.Vector{Z: 40})},
+	}
+	test.That(t, spatial.PoseAlmostCoincident(mo.OffsetAt(0), spatial.NewZeroPose()), test.ShouldBeTrue)
+	test.That(t, spatial.R3VectorAlmostEqual(mo.OffsetAt(2*time.Second).Point(), r3.Vector{Z: 20}, 1e-9), test.ShouldBeTrue)
+	test.That(t, spatial.R3VectorAlmostEqual(mo.OffsetAt(time.Minute).Point(), r3.Vector{Z: 40}, 1e-9), test.ShouldBeTrue)
+
+	// converted to the world frame, obstacles move the same way through the world
+	fs := NewEmptySimpleFrameSystem("test")
+	conveyor, err := NewStaticFrame("conveyor", spatial.NewPose(r3.Vector{X: 100}, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 90}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(conveyor, fs.World()), test.ShouldBeNil)
+	belt, err := NewStaticFrame("belt", spatial.NewPoseFromPoint(r3.Vector{Z: 10}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(belt, conveyor), test.ShouldBeNil)
+	for _, mo := range []*MovingObstacle{
+		{Geometries: geometries, Velocity: r3.Vector{Y: 100}},
+		{Geometries: geometries, Trajectory: []TimedPose{
+			{Time: 0, Pose: spatial.NewZeroPose()},
+			{Time: 2 * time.Second, Pose: spatial.NewPoseFromPoint(r3.Vector{Y: 200})},
+		}},
+	} {
+		ws, err := (&WorldState{MovingObstacles: []*MovingObstacle{mo}}).ToWorldFrame(fs, StartPositions(fs))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, ws.MovingObstacles, test.ShouldHaveLength, 1)
+		worldObstacle := ws.MovingObstacles[0]
+		test.That(t, worldObstacle.Geometries.Parent(), test.ShouldEqual, World)
+		for _, tm := range []time.Duration{0, time.Second, 2 * time.Second} {
+			expected, err := fs.Transform(StartPositions(fs), mo.GeometriesAt(tm), World)
+			test.That(t, err, test.ShouldBeNil)
+			expectedPose := expected.(*GeometriesInFrame).Geometries()["box"].Pose()
+			actualPose := worldObstacle.GeometriesAt(tm).Geometries()["box"].Pose()
+			test.That(t, spatial.PoseAlmostCoincident(actualPose, expectedPose), test.ShouldBeTrue)
+		}
+	}
+
+	// trajectories must go forward in time
+	mo.Trajectory = []TimedPose{{Time: time.Second, Pose: spatial.NewZeroPose()}, {Time: time.Second, Pose: spatial.NewZeroPose()}}
+	_, err = (&WorldState{MovingObstacles: []*MovingObstacle{mo}}).ToWorldFrame(fs, StartPositions(fs))
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestWorldStateMerge(t *testing.T) {
+	box := func(x float64, label string) spatial.Geometry {
+		geometry, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: x}), r3.Vector{X: 1, Y: 1, Z: 1}, label)
+		test.That(t, err, test.ShouldBeNil)
+		return geometry
+	}
+	config := &WorldState{
+		Obstacles: []*GeometriesInFrame{
+			NewGeometriesInFrame(World, map[string]spatial.Geometry{"table": box(0, "table"), "cup": box(1, "cup")}),
+		},
+		Transforms: []*LinkInFrame{NewLinkInFrame(World, spatial.NewZeroPose(), "camera", nil)},
+	}
+	vision := &WorldState{
+		Obstacles: []*GeometriesInFrame{
+			NewGeometriesInFrame(World, map[string]spatial.Geometry{"cup": box(2, "cup")}),
+			// the same label in another frame is another geometry
+			NewGeometriesInFrame("camera", map[string]spatial.Geometry{"table": box(4, "table")}),
+		},
+		MovingObstacles: []*MovingObstacle{{Geometries: NewGeometriesInFrame(World, map[string]spatial.Geometry{"table": box(3, "table")})}},
+		Transforms:      []*LinkInFrame{NewLinkInFrame(World, spatial.NewPoseFromPoint(r3.Vector{Z: 5}), "camera", nil)},
+	}
+
+	// labelled geometries and transforms of later WorldStates replace those of the same frame and label, or name
+	merged := config.Merge(nil, vision)
+	test.That(t, merged.Obstacles, test.ShouldHaveLength, 2)
+	test.That(t, merged.Obstacles[0].Parent(), test.ShouldEqual, World)
+	test.That(t, merged.Obstacles[0].Geometries()["cup"].AlmostEqual(box(2, "cup")), test.ShouldBeTrue)
+	test.That(t, merged.Obstacles[1].Parent(), test.ShouldEqual, "camera")
+	test.That(t, merged.MovingObstacles, test.ShouldHaveLength, 1)
+	test.That(t, merged.MovingObstacles[0].Geometries.Geometries()["table"].AlmostEqual(box(3, "table")), test.ShouldBeTrue)
+	test.That(t, merged.Transforms, test.ShouldResemble, vision.Transforms)
+	merged = vision.Merge(config)
+	test.That(t, merged.Obstacles, test.ShouldHaveLength, 2)
+	test.That(t, merged.Obstacles[0].Parent(), test.ShouldEqual, "camera")
+	test.That(t, merged.Obstacles[1].Geometries(), test.ShouldHaveLength, 2)
+	test.That(t, merged.MovingObstacles, test.ShouldBeEmpty)
+	test.That(t, merged.Transforms, test.ShouldResemble, config.Transforms)
+
+	// geometries without labels are all kept, even though they are named the same by their index converting from protobuf
+	fromProto := func(xs ...float64) *WorldState {
+		t.Helper()
+		proto := &commonpb.WorldState{}
+		for _, x := range xs {
+			proto.Obstacles = append(proto.Obstacles, GeometriesInFrameToProtobuf(
+				NewGeometriesInFrame(World, map[string]spatial.Geometry{"box": box(x, "")}),
+			))
+		}
+		ws, err := WorldStateFromProtobuf(proto)
+		test.That(t, err, test.ShouldBeNil)
+		return ws
+	}
+	merged = fromProto(10).Merge(fromProto(20))
+	test.That(t, merged.Obstacles, test.ShouldHaveLength, 2)
+	test.That(t, merged.Obstacles[0].Geometries()["0"].AlmostEqual(box(10, "")), test.ShouldBeTrue)
+	test.That(t, merged.Obstacles[1].Geometries()["0"].AlmostEqual(box(20, "")), test.ShouldBeTrue)
+	merged = fromProto(10, 20).Merge()
+	test.That(t, merged.Obstacles, test.ShouldHaveLength, 2)
+
+	// replacing a source replaces everything it reported
+	sources := NewWorldStateSources()
+	sources.Set("config", config)
+	sources.Set("vision", vision)
+	test.That(t, sources.Sources(), test.ShouldResemble, []string{"config", "vision"})
+	test.That(t, sources.WorldState().Obstacles[0].Geometries()["cup"].AlmostEqual(box(2, "cup")), test.ShouldBeTrue)
+	sources.Set("vision", &WorldState{})
+	test.That(t, sources.WorldState().Obstacles[0].Geometries(), test.ShouldHaveLength, 2)
+	sources.Set("config", nil)
+	test.That(t, sources.WorldState().Obstacles, test.ShouldBeEmpty)
+	test.That(t, sources.Sources(), test.ShouldResemble, []string{"config", "vision"})
+	sources.Remove("config")
+	_, ok := sources.Get("config")
+	test.That(t, ok, test.ShouldBeFalse)
+	ws, ok := sources.Get("vision")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, ws, test.ShouldResemble, &WorldState{})
+
+	// unlabelled obstacles of different sources are all kept
+	sources.Set("vision", fromProto(10))
+	sources.Set("slam", fromProto(20))
+	test.That(t, sources.WorldState().Obstacles, test.ShouldHaveLength, 2)
+}
+
+func TestObstacleWorldFrameNames(t *testing.T) {
+	box := func(x float64, label string) spatial.Geometry {
+		geometry, err := spatial.NewBox(spatial.NewPoseFromPoint(r3.Vector{X: x}), r3.Vector{X: 1, Y: 1, Z: 1}, label)
+		test.That(t, err, test.ShouldBeNil)
+		return geometry
+	}
+	fs := NewEmptySimpleFrameSystem("test")
+	camera, err := NewStaticFrame("camera", spatial.NewPoseFromPoint(r3.Vector{Z: 5}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(camera, fs.World()), test.ShouldBeNil)
+	ws := &WorldState{Obstacles: []*GeometriesInFrame{
+		NewGeometriesInFrame(World, map[string]spatial.Geometry{"1_table": box(0, "1_table"), "": box(1, "")}),
+		NewGeometriesInFrame("camera", map[string]spatial.Geometry{"cup": box(2, "cup"), "table": box(3, "1_table")}),
+	}}
+
+	// obstacles are found by their labels, whatever they look like, and by their labels qualified by their frames
+	names := ws.ObstacleWorldFrameNames()
+	test.That(t, names, test.ShouldHaveLength, 5)
+	test.That(t, names["1_table"], test.ShouldHaveLength, 2)
+	test.That(t, names["world:1_table"], test.ShouldHaveLength, 1)
+	test.That(t, names["camera:1_table"], test.ShouldHaveLength, 1)
+	test.That(t, names["camera:cup"], test.ShouldResemble, names["cup"])
+	worldState, err := ws.ToWorldFrame(fs, StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	for _, worldNames := range names {
+		for _, name := range worldNames {
+			_, ok := worldState.Obstacles[0].Geometries()[name]
+			test.That(t, ok, test.ShouldBeTrue)
+		}
+	}
+	test.That(t, (*WorldState)(nil).ObstacleWorldFrameNames(), test.ShouldBeEmpty)
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 
@@ -58,9 +59,13 @@ func getCallerName() string {
 
 // RegisterService registers a service type to a registration.
 func RegisterService(subtype resource.Subtype, model resource.Model, creator Service) {
+	registerService(subtype, model, creator, getCallerName())
+}
+
+func registerService(subtype resource.Subtype, model resource.Model, creator Service, registrarLoc string) {
 	registryMu.Lock()
 	defer registryMu.Unlock()
-	creator.RegistrarLoc = getCallerName()
+	creator.RegistrarLoc = registrarLoc
 	qName := fmt.Sprintf("%s/%s", subtype, model)
 	_, old := serviceRegistry[qName]
 	if old {
@@ -83,8 +88,6 @@ func DeregisterService(subtype resource.Subtype, model resource.Model) {
 // ServiceLookup looks up a service registration by the given type. nil is returned if
 // there is no registration.
 func ServiceLookup(subtype resource.Subtype, model resource.Model) *Service {
-	registryMu.RLock()
-	defer registryMu.RUnlock()
 	qName := fmt.Sprintf("%s/%s", subtype, model)
 	if registration, ok := RegisteredServices()[qName]; ok {
 		return &registration
@@ -92,6 +95,43 @@ func ServiceLookup(subtype resource.Subtype, model resource.Model) *Service {
 	return nil
 }
 
+// OverrideService registers a service model in place of any existing registration, such as
+// a module replacing a builtin model or a test replacing a real implementation with a fake.
+// The attribute map converter of a replaced model is removed too, since it converts to the
+// config type of the replaced implementation. The returned function restores the previous
+// registration and converter, if any.
+func OverrideService(subtype resource.Subtype, model resource.Model, creator Service) (restore func()) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	creator.RegistrarLoc = getCallerName()
+	if creator.Constructor == nil && creator.RobotConstructor == nil {
+		panic(errors.Errorf("cannot register a nil constructor for subtype: %s", subtype))
+	}
+	qName := fmt.Sprintf("%s/%s", subtype, model)
+	prev, hadPrev := serviceRegistry[qName]
+	var prevConvs []config.ServiceAttributeMapConverterRegistration
+	for _, conv := range config.RegisteredServiceAttributeMapConverters() {
+		if conv.SvcType == subtype && conv.Model == model {
+			prevConvs = append(prevConvs, conv)
+		}
+	}
+	config.DeregisterServiceAttributeMapConverter(subtype, model)
+	serviceRegistry[qName] = creator
+
+	return func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(serviceRegistry, qName)
+		if hadPrev {
+			serviceRegistry[qName] = prev
+		}
+		config.DeregisterServiceAttributeMapConverter(subtype, model)
+		for _, conv := range prevConvs {
+			config.RegisterServiceAttributeMapConverter(conv.SvcType, conv.Model, conv.Conv, conv.RetType)
+		}
+	}
+}
+
 type (
 	// Dependencies is a map of resources that a component requires for creation.
 	Dependencies map[resource.Name]interface{}
@@ -128,6 +168,20 @@ func (e *DependencyNotReadyError) Error() string {
 	return fmt.Sprintf("dependency %q has not been registered yet", e.Name)
 }
 
+// An OptionalDependencyBinder is a resource that can be given the optional dependencies it was
+// built without once they are built. Optional dependencies that are built when the resource is
+// constructed are passed to its constructor along with its other dependencies instead.
+type OptionalDependencyBinder interface {
+	BindOptionalDependency(ctx context.Context, name resource.Name, dep interface{}) error
+}
+
+// An OptionalDependencyUnbinder is an OptionalDependencyBinder that can also stop using an
+// optional dependency once it is removed. It is bound again if the dependency is built again.
+type OptionalDependencyUnbinder interface {
+	OptionalDependencyBinder
+	UnbindOptionalDependency(ctx context.Context, name resource.Name) error
+}
+
 // Component stores a resource constructor (mandatory) and a Frame building function (optional).
 type Component struct {
 	RegDebugInfo
@@ -163,9 +217,13 @@ var (
 
 // RegisterComponent register a creator to its corresponding component and model.
 func RegisterComponent(subtype resource.Subtype, model resource.Model, creator Component) {
+	registerComponent(subtype, model, creator, getCallerName())
+}
+
+func registerComponent(subtype resource.Subtype, model resource.Model, creator Component, registrarLoc string) {
 	registryMu.Lock()
 	defer registryMu.Unlock()
-	creator.RegistrarLoc = getCallerName()
+	creator.RegistrarLoc = registrarLoc
 	qName := fmt.Sprintf("%s/%s", subtype.String(), model.String())
 
 	_, old := componentRegistry[qName]
@@ -197,6 +255,92 @@ func ComponentLookup(subtype resource.Subtype, model resource.Model) *Component
 	return nil
 }
 
+// OverrideComponent registers a component model in place of any existing registration, such as
+// a module replacing a builtin model or a test replacing a real implementation with a fake.
+// The attribute converters of a replaced model are removed too, since they convert to the
+// config type of the replaced implementation. The returned function restores the previous
+// registration and converters, if any.
+func OverrideComponent(subtype resource.Subtype, model resource.Model, creator Component) (restore func()) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	creator.RegistrarLoc = getCallerName()
+	if creator.Constructor == nil && creator.RobotConstructor == nil {
+		panic(errors.Errorf("cannot register a nil constructor for subtype:%s, model:%s", subtype, model))
+	}
+	qName := fmt.Sprintf("%s/%s", subtype.String(), model.String())
+	prev, hadPrev := componentRegistry[qName]
+	var prevAttrConvs []config.ComponentAttributeConverterRegistration
+	for _, conv := range config.RegisteredComponentAttributeConverters() {
+		if conv.Subtype == subtype && conv.Model == model {
+			prevAttrConvs = append(prevAttrConvs, conv)
+		}
+	}
+	var prevMapConvs []config.ComponentAttributeMapConverterRegistration
+	for _, conv := range config.RegisteredComponentAttributeMapConverters() {
+		if conv.Subtype == subtype && conv.Model == model {
+			prevMapConvs = append(prevMapConvs, conv)
+		}
+	}
+	config.DeregisterComponentAttributeConverters(subtype, model)
+	componentRegistry[qName] = creator
+
+	return func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(componentRegistry, qName)
+		if hadPrev {
+			componentRegistry[qName] = prev
+		}
+		config.DeregisterComponentAttributeConverters(subtype, model)
+		for _, conv := range prevAttrConvs {
+			config.RegisterComponentAttributeConverter(conv.Subtype, conv.Model, conv.Attr, conv.Conv)
+		}
+		for _, conv := range prevMapConvs {
+			config.RegisterComponentAttributeMapConverter(conv.Subtype, conv.Model, conv.Conv, conv.RetType)
+		}
+	}
+}
+
+// RegisteredModels returns the models registered for a component or service subtype, sorted
+// by their string form.
+func RegisteredModels(subtype resource.Subtype) []resource.Model {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var models []resource.Model
+	addModels := func(qNames []string) {
+		prefix := subtype.String() + "/"
+		for _, qName := range qNames {
+			if !strings.HasPrefix(qName, prefix) {
+				continue
+			}
+			model, err := resource.NewModelFromString(strings.TrimPrefix(qName, prefix))
+			if err != nil {
+				utils.UncheckedError(err)
+				continue
+			}
+			models = append(models, model)
+		}
+	}
+	switch subtype.ResourceType {
+	case resource.ResourceTypeComponent:
+		addModels(mapKeys(componentRegistry))
+	case resource.ResourceTypeService:
+		addModels(mapKeys(serviceRegistry))
+	}
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].String() < models[j].String()
+	})
+	return models
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 // RegisterResourceSubtype register a ResourceSubtype to its corresponding component subtype.
 func RegisterResourceSubtype(subtype resource.Subtype, creator ResourceSubtype) {
 	registryMu.Lock()
@@ -289,12 +433,94 @@ func RegisterDiscoveryFunction(q discovery.Query, discover discovery.Discover) {
 	if !ok {
 		panic(errors.Errorf("trying to register discovery function for unregistered subtype %q", q.API))
 	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	if _, ok := discoveryFunctions[q]; ok {
 		panic(errors.Errorf("trying to register two discovery functions for subtype %q and model %q", q.API, q.Model))
 	}
 	discoveryFunctions[q] = discover
 }
 
+var (
+	configProposers        = map[discovery.Query]discovery.Propose{}
+	configProposerI2CAddrs = map[discovery.Query][]byte{}
+)
+
+// RegisterConfigProposer registers a function proposing configs of a model for detected hardware.
+// i2cAddrs are the I2C addresses the model's devices answer on, which are the only addresses
+// scanned when detecting hardware.
+func RegisterConfigProposer(q discovery.Query, propose discovery.Propose, i2cAddrs ...byte) {
+	_, ok := RegisteredResourceSubtypes()[q.API]
+	if !ok {
+		panic(errors.Errorf("trying to register config proposer for unregistered subtype %q", q.API))
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := configProposers[q]; ok {
+		panic(errors.Errorf("trying to register two config proposers for subtype %q and model %q", q.API, q.Model))
+	}
+	configProposers[q] = propose
+	configProposerI2CAddrs[q] = append([]byte(nil), i2cAddrs...)
+}
+
+// RegisteredConfigProposers returns a copy of the registered config proposers.
+func RegisteredConfigProposers() map[discovery.Query]discovery.Propose {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	toCopy := make(map[discovery.Query]discovery.Propose, len(configProposers))
+	for k, v := range configProposers {
+		toCopy[k] = v
+	}
+	return toCopy
+}
+
+// ConfigProposerI2CAddresses returns the sorted I2C addresses registered config proposers
+// expect devices on.
+func ConfigProposerI2CAddresses() []byte {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	seen := map[byte]bool{}
+	var addrs []byte
+	for _, qAddrs := range configProposerI2CAddrs {
+		for _, addr := range qAddrs {
+			if !seen[addr] {
+				seen[addr] = true
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+	return addrs
+}
+
+// ConfigTemplates returns the config templates of every registered component and service model,
+// sorted by subtype and model. The attributes of a template are read from the type the model
+// registered as the result of its attribute map converter; models without one have none.
+func ConfigTemplates() []discovery.ConfigTemplate {
+	retTypes := map[discovery.Query]interface{}{}
+	for _, reg := range config.RegisteredComponentAttributeMapConverters() {
+		retTypes[discovery.NewQuery(reg.Subtype, reg.Model)] = reg.RetType
+	}
+	for _, reg := range config.RegisteredServiceAttributeMapConverters() {
+		retTypes[discovery.NewQuery(reg.SvcType, reg.Model)] = reg.RetType
+	}
+
+	var templates []discovery.ConfigTemplate
+	for subtype := range RegisteredResourceSubtypes() {
+		for _, model := range RegisteredModels(subtype) {
+			q := discovery.NewQuery(subtype, model)
+			templates = append(templates, discovery.NewConfigTemplate(q, retTypes[q]))
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Query.API.String() != templates[j].Query.API.String() {
+			return templates[i].Query.API.String() < templates[j].Query.API.String()
+		}
+		return templates[i].Query.Model.String() < templates[j].Query.Model.String()
+	})
+	return templates
+}
+
 // FindValidServiceModels returns a list of valid models for a specified service.
 func FindValidServiceModels(rName resource.Name) []resource.Model {
 	validModels := make([]resource.Model, 0)
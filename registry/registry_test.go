@@ -2,6 +2,8 @@ package registry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/edaniels/golog"
@@ -144,3 +146,140 @@ func TestFindValidServiceModels(t *testing.T) {
 	test.That(t, modelList, test.ShouldContain, resource.NewDefaultModel("testModel1"))
 	test.That(t, modelList, test.ShouldContain, resource.NewDefaultModel("testModel2"))
 }
+
+type typedTestAttributes struct {
+	Pin string `json:"pin"`
+}
+
+type typedTestResource struct {
+	pin string
+}
+
+func TestTypedComponentRegistry(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	modelName := resource.Model{Name: "typed"}
+	RegisterTypedComponent(acme.Subtype, modelName, func(
+		ctx context.Context, deps Dependencies, conf config.Component, attrs *typedTestAttributes, logger golog.Logger,
+	) (*typedTestResource, error) {
+		if attrs.Pin == "" {
+			return nil, errors.New("no pin")
+		}
+		return &typedTestResource{pin: attrs.Pin}, nil
+	})
+	defer DeregisterComponent(acme.Subtype, modelName)
+
+	creator := ComponentLookup(acme.Subtype, modelName)
+	test.That(t, creator, test.ShouldNotBeNil)
+	test.That(t, creator.RegistrarLoc, test.ShouldContainSubstring, "TestTypedComponentRegistry")
+
+	var conv config.AttributeMapConverter
+	for _, reg := range config.RegisteredComponentAttributeMapConverters() {
+		if reg.Subtype == acme.Subtype && reg.Model == modelName {
+			conv = reg.Conv
+			test.That(t, reg.RetType, test.ShouldResemble, &typedTestAttributes{})
+		}
+	}
+	test.That(t, conv, test.ShouldNotBeNil)
+	converted, err := conv(config.AttributeMap{"pin": "7"})
+	test.That(t, err, test.ShouldBeNil)
+
+	res, err := creator.Constructor(context.Background(), nil, config.Component{ConvertedAttributes: converted}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldResemble, &typedTestResource{pin: "7"})
+
+	// unconverted attributes are converted on the fly
+	res, err = creator.Constructor(context.Background(), nil, config.Component{Attributes: config.AttributeMap{"pin": "8"}}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldResemble, &typedTestResource{pin: "8"})
+
+	res, err = creator.Constructor(context.Background(), nil, config.Component{}, logger)
+	test.That(t, err, test.ShouldBeError, errors.New("no pin"))
+	test.That(t, res, test.ShouldBeNil)
+
+	_, err = creator.Constructor(context.Background(), nil, config.Component{ConvertedAttributes: &struct{}{}}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "expected *registry.typedTestAttributes")
+}
+
+func TestResourceFromDependencies(t *testing.T) {
+	deps := Dependencies{acme: &typedTestResource{pin: "7"}}
+	res, err := ResourceFromDependencies[*typedTestResource](deps, acme)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res.pin, test.ShouldEqual, "7")
+
+	_, err = ResourceFromDependencies[*typedTestResource](deps, testService)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "missing from dependencies")
+
+	_, err = ResourceFromDependencies[fmt.Stringer](deps, acme)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "expected implementation of fmt.Stringer")
+}
+
+func TestOverrideComponent(t *testing.T) {
+	builtin := func(ctx context.Context, deps Dependencies, config config.Component, logger golog.Logger) (interface{}, error) {
+		return "builtin", nil
+	}
+	override := func(ctx context.Context, deps Dependencies, config config.Component, logger golog.Logger) (interface{}, error) {
+		return "override", nil
+	}
+	model := resource.NewDefaultModel("overridden")
+	RegisterComponent(acme.Subtype, model, Component{Constructor: builtin})
+	defer DeregisterComponent(acme.Subtype, model)
+	config.RegisterComponentAttributeMapConverter(acme.Subtype, model, func(attributes config.AttributeMap) (interface{}, error) {
+		return &typedTestAttributes{}, nil
+	}, &typedTestAttributes{})
+	defer config.DeregisterComponentAttributeConverters(acme.Subtype, model)
+
+	hasConverter := func() bool {
+		for _, conv := range config.RegisteredComponentAttributeMapConverters() {
+			if conv.Subtype == acme.Subtype && conv.Model == model {
+				return true
+			}
+		}
+		return false
+	}
+
+	test.That(t, func() { OverrideComponent(acme.Subtype, model, Component{}) }, test.ShouldPanic)
+	restore := OverrideComponent(acme.Subtype, model, Component{Constructor: override})
+	res, err := ComponentLookup(acme.Subtype, model).Constructor(context.Background(), nil, config.Component{}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldEqual, "override")
+	test.That(t, hasConverter(), test.ShouldBeFalse)
+
+	restore()
+	res, err = ComponentLookup(acme.Subtype, model).Constructor(context.Background(), nil, config.Component{}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldEqual, "builtin")
+	test.That(t, hasConverter(), test.ShouldBeTrue)
+
+	// overriding a model that does not exist registers it until restored
+	newModel := resource.NewDefaultModel("brand_new")
+	restore = OverrideComponent(acme.Subtype, newModel, Component{Constructor: override})
+	test.That(t, RegisteredModels(acme.Subtype), test.ShouldContain, newModel)
+	restore()
+	test.That(t, ComponentLookup(acme.Subtype, newModel), test.ShouldBeNil)
+	test.That(t, RegisteredModels(acme.Subtype), test.ShouldNotContain, newModel)
+}
+
+func TestOverrideService(t *testing.T) {
+	builtin := func(ctx context.Context, deps Dependencies, config config.Service, logger golog.Logger) (interface{}, error) {
+		return "builtin", nil
+	}
+	override := func(ctx context.Context, deps Dependencies, config config.Service, logger golog.Logger) (interface{}, error) {
+		return "override", nil
+	}
+	model := resource.NewDefaultModel("overridden")
+	RegisterService(testService.Subtype, model, Service{Constructor: builtin})
+	defer DeregisterService(testService.Subtype, model)
+
+	restore := OverrideService(testService.Subtype, model, Service{Constructor: override})
+	res, err := ServiceLookup(testService.Subtype, model).Constructor(context.Background(), nil, config.Service{}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldEqual, "override")
+
+	restore()
+	res, err = ServiceLookup(testService.Subtype, model).Constructor(context.Background(), nil, config.Service{}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldEqual, "builtin")
+}
@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"context"
+
+	"github.com/edaniels/golog"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
+)
+
+type (
+	// A CreateTypedComponent creates a component of type R from its attributes converted to a T.
+	CreateTypedComponent[T, R any] func(
+		ctx context.Context, deps Dependencies, conf config.Component, attrs *T, logger golog.Logger,
+	) (R, error)
+
+	// A CreateTypedService creates a service of type R from its attributes converted to a T.
+	CreateTypedService[T, R any] func(
+		ctx context.Context, deps Dependencies, conf config.Service, attrs *T, logger golog.Logger,
+	) (R, error)
+)
+
+// RegisterTypedComponent registers a component model whose attributes are the json fields of T
+// and whose constructor returns an R. It registers both the attribute map converter and the
+// constructor, so the constructor receives its attributes already typed instead of asserting
+// them from config.Component.ConvertedAttributes.
+func RegisterTypedComponent[T, R any](subtype resource.Subtype, model resource.Model, constructor CreateTypedComponent[T, R]) {
+	registerComponent(subtype, model, Component{Constructor: TypedComponentConstructor(constructor)}, getCallerName())
+	config.RegisterComponentAttributeMapConverter(subtype, model, TypedAttributeMapConverter[T](), new(T))
+}
+
+// RegisterTypedService registers a service model whose attributes are the json fields of T and
+// whose constructor returns an R. See RegisterTypedComponent.
+func RegisterTypedService[T, R any](subtype resource.Subtype, model resource.Model, constructor CreateTypedService[T, R]) {
+	registerService(subtype, model, Service{Constructor: TypedServiceConstructor(constructor)}, getCallerName())
+	config.RegisterServiceAttributeMapConverter(subtype, model, TypedAttributeMapConverter[T](), new(T))
+}
+
+// TypedAttributeMapConverter returns an attribute map converter that converts attributes to a *T
+// by their json field names.
+func TypedAttributeMapConverter[T any]() config.AttributeMapConverter {
+	return func(attributes config.AttributeMap) (interface{}, error) {
+		return config.TransformAttributeMapToStruct(new(T), attributes)
+	}
+}
+
+// TypedComponentConstructor adapts a typed constructor to a CreateComponent. Attributes that
+// were not converted yet are converted to a *T first.
+func TypedComponentConstructor[T, R any](constructor CreateTypedComponent[T, R]) CreateComponent {
+	return func(ctx context.Context, deps Dependencies, conf config.Component, logger golog.Logger) (interface{}, error) {
+		attrs, err := typedAttributes[T](conf.ConvertedAttributes, conf.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		res, err := constructor(ctx, deps, conf, attrs, logger)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+// TypedServiceConstructor adapts a typed constructor to a CreateService. Attributes that were
+// not converted yet are converted to a *T first.
+func TypedServiceConstructor[T, R any](constructor CreateTypedService[T, R]) CreateService {
+	return func(ctx context.Context, deps Dependencies, conf config.Service, logger golog.Logger) (interface{}, error) {
+		attrs, err := typedAttributes[T](conf.ConvertedAttributes, conf.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		res, err := constructor(ctx, deps, conf, attrs, logger)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+func typedAttributes[T any](converted interface{}, attributes config.AttributeMap) (*T, error) {
+	if converted == nil {
+		attrs := new(T)
+		if _, err := config.TransformAttributeMapToStruct(attrs, attributes); err != nil {
+			return nil, err
+		}
+		return attrs, nil
+	}
+	attrs, ok := converted.(*T)
+	if !ok {
+		return nil, rutils.NewUnexpectedTypeError(attrs, converted)
+	}
+	return attrs, nil
+}
+
+// ResourceFromDependencies returns the dependency with the given name as a T.
+func ResourceFromDependencies[T any](deps Dependencies, name resource.Name) (T, error) {
+	var zero T
+	res, ok := deps[name]
+	if !ok {
+		return zero, rutils.DependencyNotFoundError(name.String())
+	}
+	part, ok := res.(T)
+	if !ok {
+		return zero, rutils.NewUnimplementedInterfaceError((*T)(nil), res)
+	}
+	return part, nil
+}
@@ -0,0 +1,126 @@
+package resource
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// A HealthState is the coarse state of a resource's health.
+type HealthState string
+
+// The states a resource's health can be in.
+const (
+	// HealthStateUnknown is used when a resource has not determined its state yet.
+	HealthStateUnknown HealthState = "unknown"
+	// HealthStateReady is used when a resource is fully functional.
+	HealthStateReady HealthState = "ready"
+	// HealthStateDegraded is used when a resource works but with reduced functionality, for
+	// example while reconnecting to its device.
+	HealthStateDegraded HealthState = "degraded"
+	// HealthStateFailed is used when a resource no longer works.
+	HealthStateFailed HealthState = "failed"
+)
+
+// Health describes the health of a resource in the same terms for every kind of resource.
+type Health struct {
+	State HealthState
+	// LastError is the most recent error the resource ran into, if any.
+	LastError error
+	// Uptime is how long the resource has been running.
+	Uptime time.Duration
+	// Details holds driver specific information such as firmware versions or temperatures.
+	// Values should be primitives, lists or maps with string keys.
+	Details map[string]interface{}
+}
+
+// ToMap returns the health as a map that can be part of a resource's status.
+func (h Health) ToMap() map[string]interface{} {
+	state := h.State
+	if state == "" {
+		state = HealthStateUnknown
+	}
+	m := map[string]interface{}{"state": string(state)}
+	if h.LastError != nil {
+		m["last_error"] = h.LastError.Error()
+	}
+	if h.Uptime != 0 {
+		m["uptime_secs"] = h.Uptime.Seconds()
+	}
+	if len(h.Details) != 0 {
+		m["details"] = h.Details
+	}
+	return m
+}
+
+// HealthReporter is implemented when a resource can describe its own health. The health of
+// such resources is part of their status, and a robot rebuilds those that report
+// HealthStateFailed.
+type HealthReporter interface {
+	// Health returns the current health of the resource.
+	Health(context.Context) (Health, error)
+}
+
+// A HealthTracker keeps track of the health of a resource so that drivers can implement
+// HealthReporter by embedding one and updating it as they run. The zero value reports
+// HealthStateUnknown until a state is set, and uptime counts from when the first state is set.
+type HealthTracker struct {
+	mu        sync.Mutex
+	started   time.Time
+	state     HealthState
+	lastError error
+	details   map[string]interface{}
+}
+
+// NewHealthTracker returns a HealthTracker in the ready state whose uptime starts now.
+func NewHealthTracker() *HealthTracker {
+	t := &HealthTracker{}
+	t.SetState(HealthStateReady, nil)
+	return t
+}
+
+// SetState records the state of the resource along with the error that caused it, if any.
+// A nil error keeps the previous last error.
+func (t *HealthTracker) SetState(state HealthState, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started.IsZero() {
+		t.started = time.Now()
+	}
+	t.state = state
+	if err != nil {
+		t.lastError = err
+	}
+}
+
+// SetDetail records a driver specific detail.
+func (t *HealthTracker) SetDetail(key string, value interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.details == nil {
+		t.details = map[string]interface{}{}
+	}
+	t.details[key] = value
+}
+
+// Health returns the tracked health.
+func (t *HealthTracker) Health(ctx context.Context) (Health, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	details := make(map[string]interface{}, len(t.details))
+	for k, v := range t.details {
+		details[k] = v
+	}
+	health := Health{
+		State:     t.state,
+		LastError: t.lastError,
+		Details:   details,
+	}
+	if health.State == "" {
+		health.State = HealthStateUnknown
+	}
+	if !t.started.IsZero() {
+		health.Uptime = time.Since(t.started)
+	}
+	return health, nil
+}
@@ -0,0 +1,42 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+func TestHealthTracker(t *testing.T) {
+	tracker := resource.NewHealthTracker()
+	health, err := tracker.Health(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health.State, test.ShouldEqual, resource.HealthStateReady)
+	test.That(t, health.LastError, test.ShouldBeNil)
+	test.That(t, health.Uptime, test.ShouldBeGreaterThan, 0)
+
+	tracker.SetState(resource.HealthStateDegraded, errors.New("serial timeout"))
+	tracker.SetState(resource.HealthStateReady, nil)
+	tracker.SetDetail("firmware", "1.2.3")
+	health, err = tracker.Health(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health.State, test.ShouldEqual, resource.HealthStateReady)
+	test.That(t, health.LastError, test.ShouldBeError, errors.New("serial timeout"))
+
+	m := health.ToMap()
+	test.That(t, m["state"], test.ShouldEqual, "ready")
+	test.That(t, m["last_error"], test.ShouldEqual, "serial timeout")
+	test.That(t, m["details"], test.ShouldResemble, map[string]interface{}{"firmware": "1.2.3"})
+	test.That(t, m["uptime_secs"], test.ShouldBeGreaterThan, 0)
+
+	test.That(t, resource.Health{}.ToMap(), test.ShouldResemble, map[string]interface{}{"state": "unknown"})
+
+	var zero resource.HealthTracker
+	health, err = zero.Health(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, health.State, test.ShouldEqual, resource.HealthStateUnknown)
+	test.That(t, health.Uptime, test.ShouldEqual, 0)
+}
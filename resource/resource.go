@@ -375,6 +375,14 @@ type MovingCheckable interface {
 	IsMoving(context.Context) (bool, error)
 }
 
+// HealthChecker is implemented when a resource can report whether it is still working. A
+// robot periodically checks the health of such resources and rebuilds those that fail or
+// do not answer in time.
+type HealthChecker interface {
+	// CheckHealth returns an error if the resource is no longer functional.
+	CheckHealth(context.Context) error
+}
+
 // Stoppable is implemented when a resource of a robot can stop its movement.
 type Stoppable interface {
 	// Stop stops all movement for the resource
@@ -388,6 +396,14 @@ type OldStoppable interface {
 	Stop(context.Context) error
 }
 
+// SafeStoppable is implemented when an actuator can bring itself into a defined safe state
+// (e.g. parked or braked) rather than simply halting wherever it happens to be.
+type SafeStoppable interface {
+	// SafeStop moves the resource into its safe state. It is called once before the
+	// robot shuts down and is expected to respect the deadline of the given context.
+	SafeStop(ctx context.Context) error
+}
+
 // StopResource attempts to stops the given resource.
 func StopResource(ctx context.Context, res interface{}, extra map[string]interface{}) error {
 	sr, ok := res.(Stoppable)
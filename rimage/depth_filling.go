@@ -45,8 +45,93 @@ func FillDepthMap(dm *DepthMap, img *Image) (*DepthMap, error) {
 	return iwd.Depth, nil
 }
 
+// FillDepthHoles fills the holes of at most maxHoleSize pixels, regions of connected missing data, without needing a
+// color image. Holes are filled from their border inwards, each missing pixel taking the farthest depth of its filled
+// neighbors, since holes in stereo and time of flight depth maps are mostly occluded background at the edges of objects.
+// It visits every pixel a constant number of times, so is fast enough to run on every frame.
+func FillDepthHoles(dm *DepthMap, maxHoleSize int) *DepthMap {
+	out := dm.Clone()
+	if maxHoleSize <= 0 {
+		return out
+	}
+	width, height := dm.Width(), dm.Height()
+	visited := make([]bool, width*height)
+	queued := make([]bool, width*height)
+	hole := make([]image.Point, 0)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if visited[y*width+x] || dm.GetDepth(x, y) != 0 {
+				continue
+			}
+			// breadth-first search for the rest of the hole
+			hole = append(hole[:0], image.Point{x, y})
+			visited[y*width+x] = true
+			for i := 0; i < len(hole); i++ {
+				for _, dir := range fourPoints {
+					n := hole[i].Add(dir)
+					if dm.Contains(n.X, n.Y) && !visited[n.Y*width+n.X] && dm.GetDepth(n.X, n.Y) == 0 {
+						visited[n.Y*width+n.X] = true
+						hole = append(hole, n)
+					}
+				}
+			}
+			if len(hole) <= maxHoleSize {
+				fillHole(out, hole, queued)
+			}
+		}
+	}
+	return out
+}
+
+// fillHole fills a hole one layer of pixels at a time, starting with the pixels next to valid data.
+func fillHole(dm *DepthMap, hole []image.Point, queued []bool) {
+	width := dm.Width()
+	layer := make([]image.Point, 0, len(hole))
+	for _, p := range hole {
+		if farthestNeighborDepth(dm, p) != 0 {
+			layer = append(layer, p)
+			queued[p.Y*width+p.X] = true
+		}
+	}
+	depths := make([]Depth, 0, len(layer))
+	for len(layer) > 0 {
+		// set the whole layer at once so that pixels of a layer do not fill each other
+		depths = depths[:0]
+		for _, p := range layer {
+			depths = append(depths, farthestNeighborDepth(dm, p))
+		}
+		for i, p := range layer {
+			dm.Set(p.X, p.Y, depths[i])
+		}
+		next := make([]image.Point, 0, len(layer))
+		for _, p := range layer {
+			for _, dir := range fourPoints {
+				n := p.Add(dir)
+				if dm.Contains(n.X, n.Y) && !queued[n.Y*width+n.X] && dm.GetDepth(n.X, n.Y) == 0 {
+					queued[n.Y*width+n.X] = true
+					next = append(next, n)
+				}
+			}
+		}
+		layer = next
+	}
+}
+
+// farthestNeighborDepth returns the largest depth of the 4 neighbors of a point, or 0 if they are all missing.
+func farthestNeighborDepth(dm *DepthMap, p image.Point) Depth {
+	farthest := Depth(0)
+	for _, dir := range fourPoints {
+		n := p.Add(dir)
+		if dm.Contains(n.X, n.Y) && dm.GetDepth(n.X, n.Y) > farthest {
+			farthest = dm.GetDepth(n.X, n.Y)
+		}
+	}
+	return farthest
+}
+
 // directions for ray-marching.
 var (
+	fourPoints    = []image.Point{{0, 1}, {0, -1}, {-1, 0}, {1, 0}}
 	sixteenPoints = []image.Point{
 		{0, 2},
 		{0, -2},
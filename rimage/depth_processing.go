@@ -200,6 +200,90 @@ func JointBilateralSmoothing(dm *DepthMap, spatialSigma, depthSigma float64) (*D
 	return outDM, nil
 }
 
+// GuidedSmoothing smoothes a depth map affected by noise with a guided filter that uses the depth map as its own guide,
+// as described in "Guided Image Filtering" by He et al. 2013. Like JointBilateralSmoothing, it does not smooth across edges
+// between objects, since windows with a depth variance much larger than epsilon (in mm^2) are left mostly unchanged, but
+// it takes the same time whatever the radius of its windows. Missing data is ignored, and stays missing.
+func GuidedSmoothing(dm *DepthMap, radius int, epsilon float64) (*DepthMap, error) {
+	if radius <= 0 || epsilon <= 0. {
+		return dm.Clone(), nil
+	}
+	width, height := dm.Width(), dm.Height()
+	outDM := NewEmptyDepthMap(width, height)
+	// center the depths around their mean, so that the sums of squares stay precise
+	offset, count := 0., 0.
+	for _, d := range dm.data {
+		if d != 0 {
+			offset += float64(d)
+			count++
+		}
+	}
+	if count == 0 {
+		return outDM, nil
+	}
+	offset /= count
+	valid := make([]float64, len(dm.data))
+	depths := make([]float64, len(dm.data))
+	squares := make([]float64, len(dm.data))
+	for i, d := range dm.data {
+		if d == 0 {
+			continue
+		}
+		valid[i] = 1
+		depths[i] = float64(d) - offset
+		squares[i] = depths[i] * depths[i]
+	}
+	counts := boxSums(valid, width, height, radius)
+	sums := boxSums(depths, width, height, radius)
+	sumSquares := boxSums(squares, width, height, radius)
+	// fit depth = a*depth + b in the window around every valid point
+	a := make([]float64, len(dm.data))
+	b := make([]float64, len(dm.data))
+	for i := range dm.data {
+		if valid[i] == 0 {
+			continue
+		}
+		mean := sums[i] / counts[i]
+		variance := math.Max(0, sumSquares[i]/counts[i]-mean*mean)
+		a[i] = variance / (variance + epsilon)
+		b[i] = (1 - a[i]) * mean
+	}
+	// and average the fits of the windows that every valid point is in
+	sumA := boxSums(a, width, height, radius)
+	sumB := boxSums(b, width, height, radius)
+	for i := range dm.data {
+		if valid[i] == 0 {
+			continue
+		}
+		val := sumA[i]/counts[i]*depths[i] + sumB[i]/counts[i] + offset
+		outDM.data[i] = Depth(math.Min(float64(MaxDepth), math.Max(1, math.Round(val))))
+	}
+	return outDM, nil
+}
+
+// boxSums returns the sums of values, in row-major order, over the square windows of a radius around every pixel, clipped
+// to the image, using an integral image.
+func boxSums(values []float64, width, height, radius int) []float64 {
+	integral := make([]float64, (width+1)*(height+1))
+	for y := 0; y < height; y++ {
+		rowSum := 0.
+		for x := 0; x < width; x++ {
+			rowSum += values[y*width+x]
+			integral[(y+1)*(width+1)+x+1] = integral[y*(width+1)+x+1] + rowSum
+		}
+	}
+	sums := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		y0, y1 := utils.MaxInt(0, y-radius), utils.MinInt(height, y+radius+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := utils.MaxInt(0, x-radius), utils.MinInt(width, x+radius+1)
+			sums[y*width+x] = integral[y1*(width+1)+x1] - integral[y0*(width+1)+x1] -
+				integral[y1*(width+1)+x0] + integral[y0*(width+1)+x0]
+		}
+	}
+	return sums
+}
+
 // expandDepthMapForConvolution pads an input depth map on every side with a mirror image of the data. This is so evaluation
 // of the convolution at the borders will happen smoothly and will not created artifacts of sharp edges.
 func expandDepthMapForConvolution(dm *DepthMap, radius int) *DepthMap {
@@ -1,6 +1,7 @@
 package rimage
 
 import (
+	"math"
 	"testing"
 
 	"github.com/golang/geo/r2"
@@ -71,3 +72,65 @@ func TestInterpolations(t *testing.T) {
 	d = BilinearInterpolationDepth(pt, dm)
 	test.That(t, d, test.ShouldBeNil)
 }
+
+func TestFillDepthHoles(t *testing.T) {
+	dm := NewEmptyDepthMap(5, 5)
+	for i := 0; i < 5; i++ {
+		dm.Set(i, 0, 100)
+		dm.Set(i, 4, 100)
+		dm.Set(0, i, 100)
+		dm.Set(4, i, 100)
+	}
+	dm.Set(0, 2, 300)
+	// the hole is 9 pixels
+	test.That(t, FillDepthHoles(dm, 8), test.ShouldResemble, dm)
+	filled := FillDepthHoles(dm, 9)
+	test.That(t, dm.GetDepth(2, 2), test.ShouldEqual, 0)
+	test.That(t, filled.GetDepth(1, 2), test.ShouldEqual, 300)
+	test.That(t, filled.GetDepth(1, 1), test.ShouldEqual, 100)
+	test.That(t, filled.GetDepth(3, 3), test.ShouldEqual, 100)
+	// the center is filled from the farthest of the first layer
+	test.That(t, filled.GetDepth(2, 2), test.ShouldEqual, 300)
+
+	empty := NewEmptyDepthMap(3, 3)
+	test.That(t, FillDepthHoles(empty, 100), test.ShouldResemble, empty)
+}
+
+func TestGuidedSmoothing(t *testing.T) {
+	width, height := 20, 10
+	dm := NewEmptyDepthMap(width, height)
+	base := func(x int) float64 {
+		if x < width/2 {
+			return 1000
+		}
+		return 3000
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			noise := 10.
+			if (x+y)%2 == 0 {
+				noise = -10
+			}
+			dm.Set(x, y, Depth(base(x)+noise))
+		}
+	}
+	dm.Set(3, 3, 0)
+
+	same, err := GuidedSmoothing(dm, 0, 100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, same, test.ShouldResemble, dm)
+
+	smoothed, err := GuidedSmoothing(dm, 2, 100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, smoothed.GetDepth(3, 3), test.ShouldEqual, 0)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x == 3 && y == 3 {
+				continue
+			}
+			// the noise is smoothed out, without blurring the edge between the two halves
+			test.That(t, math.Abs(float64(smoothed.GetDepth(x, y))-base(x)), test.ShouldBeLessThan, 10)
+		}
+	}
+	test.That(t, math.Abs(float64(smoothed.GetDepth(5, 5))-base(5)), test.ShouldBeLessThan, 8)
+}
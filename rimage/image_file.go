@@ -309,8 +309,13 @@ func EncodeImage(ctx context.Context, img image.Image, mimeType string) ([]byte,
 		binary.BigEndian.PutUint32(heightBytes, uint32(bounds.Dy()))
 		buf.Write(widthBytes)
 		buf.Write(heightBytes)
-		imgStruct := image.NewNRGBA(bounds)
-		draw.Draw(imgStruct, bounds, img, bounds.Min, draw.Src)
+		var imgStruct *image.NRGBA
+		if ycbcr, ok := img.(*image.YCbCr); ok {
+			imgStruct = ycbcrToNRGBA(ycbcr)
+		} else {
+			imgStruct = image.NewNRGBA(bounds)
+			draw.Draw(imgStruct, bounds, img, bounds.Min, draw.Src)
+		}
 		buf.Write(imgStruct.Pix)
 	case ut.MimeTypePNG:
 		if err := png.Encode(&buf, img); err != nil {
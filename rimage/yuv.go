@@ -0,0 +1,126 @@
+package rimage
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/pkg/errors"
+)
+
+// Webcams mostly produce YUV frames, which go holds as *image.YCbCr. Video encoders take those as they are, so
+// camera pipelines carry them through as they are, and only convert them to RGB when an RGB image is required.
+
+// NV12ToYCbCr converts a raw NV12 frame, a plane of luma followed by a plane of interleaved 2x2 subsampled
+// chroma, to a 4:2:0 image.YCbCr without converting it to RGB.
+func NV12ToYCbCr(data []byte, width, height int) (*image.YCbCr, error) {
+	cw, ch := (width+1)/2, (height+1)/2
+	if width <= 0 || height <= 0 || len(data) != width*height+2*cw*ch {
+		return nil, errors.Errorf("%d bytes is not an NV12 frame of %dx%d", len(data), width, height)
+	}
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio420)
+	copy(img.Y, data[:width*height])
+	uv := data[width*height:]
+	for i := 0; i < cw*ch; i++ {
+		img.Cb[i] = uv[2*i]
+		img.Cr[i] = uv[2*i+1]
+	}
+	return img, nil
+}
+
+// YUYVToYCbCr converts a raw YUYV (also known as YUY2) frame, with pixels in pairs of Y0 U Y1 V, to a 4:2:2
+// image.YCbCr without converting it to RGB.
+func YUYVToYCbCr(data []byte, width, height int) (*image.YCbCr, error) {
+	if width <= 0 || height <= 0 || width%2 != 0 || len(data) != 2*width*height {
+		return nil, errors.Errorf("%d bytes is not a YUYV frame of %dx%d", len(data), width, height)
+	}
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio422)
+	for i := 0; i < width*height/2; i++ {
+		img.Y[2*i] = data[4*i]
+		img.Cb[i] = data[4*i+1]
+		img.Y[2*i+1] = data[4*i+2]
+		img.Cr[i] = data[4*i+3]
+	}
+	return img, nil
+}
+
+// RotateYCbCr180 rotates a YCbCr image by 180 degrees without converting it to RGB.
+func RotateYCbCr180(img *image.YCbCr) *image.YCbCr {
+	b := img.Bounds()
+	return mapYCbCr(img, b.Dx(), b.Dy(), func(x, y int) (int, int) {
+		return b.Max.X - 1 - x, b.Max.Y - 1 - y
+	})
+}
+
+// ResizeYCbCr resizes a YCbCr image with nearest neighbor sampling without converting it to RGB.
+func ResizeYCbCr(img *image.YCbCr, width, height int) *image.YCbCr {
+	b := img.Bounds()
+	return mapYCbCr(img, width, height, func(x, y int) (int, int) {
+		return b.Min.X + x*b.Dx()/width, b.Min.Y + y*b.Dy()/height
+	})
+}
+
+// mapYCbCr makes a YCbCr image of the given size, with the subsample ratio of src, where every pixel, and every
+// chroma sample from the top left pixel of its block, is taken from the pixel of src that srcPixel maps it to.
+func mapYCbCr(src *image.YCbCr, width, height int, srcPixel func(x, y int) (int, int)) *image.YCbCr {
+	dst := image.NewYCbCr(image.Rect(0, 0, width, height), src.SubsampleRatio)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sx, sy := srcPixel(x, y)
+			dst.Y[y*dst.YStride+x] = src.Y[src.YOffset(sx, sy)]
+		}
+	}
+	blockW, blockH := chromaBlockSize(src.SubsampleRatio)
+	chromaH := len(dst.Cb) / dst.CStride
+	for cy := 0; cy < chromaH; cy++ {
+		for cx := 0; cx < dst.CStride; cx++ {
+			x, y := cx*blockW, cy*blockH
+			if x >= width {
+				x = width - 1
+			}
+			if y >= height {
+				y = height - 1
+			}
+			sx, sy := srcPixel(x, y)
+			ci := src.COffset(sx, sy)
+			dst.Cb[cy*dst.CStride+cx] = src.Cb[ci]
+			dst.Cr[cy*dst.CStride+cx] = src.Cr[ci]
+		}
+	}
+	return dst
+}
+
+// chromaBlockSize returns the width and height of the blocks of pixels that share chroma samples.
+func chromaBlockSize(ratio image.YCbCrSubsampleRatio) (int, int) {
+	switch ratio {
+	case image.YCbCrSubsampleRatio422:
+		return 2, 1
+	case image.YCbCrSubsampleRatio420:
+		return 2, 2
+	case image.YCbCrSubsampleRatio440:
+		return 1, 2
+	case image.YCbCrSubsampleRatio411:
+		return 4, 1
+	case image.YCbCrSubsampleRatio410:
+		return 4, 2
+	default:
+		// 4:4:4 has a chroma sample for every pixel
+		return 1, 1
+	}
+}
+
+// ycbcrToNRGBA converts a YCbCr image to RGB a row at a time, which is much faster than drawing it
+// onto an image.NRGBA.
+func ycbcrToNRGBA(src *image.YCbCr) *image.NRGBA {
+	b := src.Bounds()
+	dst := image.NewNRGBA(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := dst.Pix[(y-b.Min.Y)*dst.Stride:]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			ci := src.COffset(x, y)
+			r, g, bl := color.YCbCrToRGB(src.Y[src.YOffset(x, y)], src.Cb[ci], src.Cr[ci])
+			i := 4 * (x - b.Min.X)
+			row[i], row[i+1], row[i+2], row[i+3] = r, g, bl, 255
+		}
+	}
+	return dst
+}
@@ -0,0 +1,105 @@
+package rimage
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/utils"
+)
+
+// testYCbCr makes a YCbCr image whose every pixel has a different color.
+func testYCbCr(width, height int, ratio image.YCbCrSubsampleRatio) *image.YCbCr {
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), ratio)
+	for i := range img.Y {
+		img.Y[i] = uint8(16 + i%220)
+	}
+	for i := range img.Cb {
+		img.Cb[i] = uint8(30 + 7*i%200)
+		img.Cr[i] = uint8(220 - 3*i%200)
+	}
+	return img
+}
+
+func TestNV12ToYCbCr(t *testing.T) {
+	// 3x2 pixels, with 2x1 chroma samples
+	data := []byte{1, 2, 3, 4, 5, 6, 10, 20, 11, 21}
+	img, err := NV12ToYCbCr(data, 3, 2)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.SubsampleRatio, test.ShouldEqual, image.YCbCrSubsampleRatio420)
+	test.That(t, img.YCbCrAt(1, 1), test.ShouldResemble, color.YCbCr{5, 10, 20})
+	test.That(t, img.YCbCrAt(2, 0), test.ShouldResemble, color.YCbCr{3, 11, 21})
+
+	_, err = NV12ToYCbCr(data[:9], 3, 2)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestYUYVToYCbCr(t *testing.T) {
+	data := []byte{1, 10, 2, 20, 3, 11, 4, 21}
+	img, err := YUYVToYCbCr(data, 2, 2)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, img.SubsampleRatio, test.ShouldEqual, image.YCbCrSubsampleRatio422)
+	test.That(t, img.YCbCrAt(1, 0), test.ShouldResemble, color.YCbCr{2, 10, 20})
+	test.That(t, img.YCbCrAt(0, 1), test.ShouldResemble, color.YCbCr{3, 11, 21})
+
+	_, err = YUYVToYCbCr(data, 1, 4)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = YUYVToYCbCr(data[:6], 2, 2)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestRotateYCbCr180(t *testing.T) {
+	for _, ratio := range []image.YCbCrSubsampleRatio{
+		image.YCbCrSubsampleRatio444,
+		image.YCbCrSubsampleRatio422,
+		image.YCbCrSubsampleRatio420,
+	} {
+		img := testYCbCr(6, 4, ratio)
+		rotated := RotateYCbCr180(img)
+		test.That(t, rotated.SubsampleRatio, test.ShouldEqual, ratio)
+		test.That(t, rotated.Bounds(), test.ShouldResemble, img.Bounds())
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 6; x++ {
+				test.That(t, rotated.YCbCrAt(5-x, 3-y).Y, test.ShouldEqual, img.YCbCrAt(x, y).Y)
+			}
+		}
+		if ratio == image.YCbCrSubsampleRatio444 {
+			test.That(t, rotated.YCbCrAt(5, 3), test.ShouldResemble, img.YCbCrAt(0, 0))
+		}
+		test.That(t, RotateYCbCr180(rotated), test.ShouldResemble, img)
+	}
+}
+
+func TestResizeYCbCr(t *testing.T) {
+	img := testYCbCr(8, 6, image.YCbCrSubsampleRatio420)
+	resized := ResizeYCbCr(img, 4, 3)
+	test.That(t, resized.Bounds(), test.ShouldResemble, image.Rect(0, 0, 4, 3))
+	test.That(t, resized.SubsampleRatio, test.ShouldEqual, image.YCbCrSubsampleRatio420)
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			test.That(t, resized.YCbCrAt(x, y).Y, test.ShouldEqual, img.YCbCrAt(2*x, 2*y).Y)
+		}
+	}
+	test.That(t, resized.YCbCrAt(2, 2), test.ShouldResemble, img.YCbCrAt(4, 4))
+
+	// odd sizes have partial chroma blocks
+	enlarged := ResizeYCbCr(img, 11, 7)
+	test.That(t, enlarged.Bounds(), test.ShouldResemble, image.Rect(0, 0, 11, 7))
+	test.That(t, enlarged.YCbCrAt(10, 6).Y, test.ShouldEqual, img.YCbCrAt(7, 5).Y)
+}
+
+func TestEncodeYCbCrRawRGBA(t *testing.T) {
+	img := testYCbCr(7, 5, image.YCbCrSubsampleRatio420)
+	expected := image.NewNRGBA(img.Bounds())
+	draw.Draw(expected, img.Bounds(), img, image.Point{}, draw.Src)
+	test.That(t, ycbcrToNRGBA(img), test.ShouldResemble, expected)
+
+	data, err := EncodeImage(context.Background(), img, utils.MimeTypeRawRGBA)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bytes.HasSuffix(data, expected.Pix), test.ShouldBeTrue)
+}
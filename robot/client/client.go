@@ -7,10 +7,12 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/fullstorydev/grpcurl"
+	"github.com/google/uuid"
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/grpcreflect"
@@ -26,18 +28,23 @@ import (
 	"google.golang.org/grpc/codes"
 	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"go.viam.com/rdk/discovery"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/pointcloud"
+	configpb "go.viam.com/rdk/proto/api/robot/v1"
+	streamcontrolpb "go.viam.com/rdk/proto/api/stream/v1"
 	rprotoutils "go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
 	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
 	"go.viam.com/rdk/robot/packages"
+	webstream "go.viam.com/rdk/robot/web/stream"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/spatialmath"
 	rutils "go.viam.com/rdk/utils"
@@ -53,6 +60,9 @@ var (
 
 	// resourcesTimeout is the default timeout for getting resources.
 	resourcesTimeout = 5 * time.Second
+
+	// lazyConnectTimeout bounds each attempt to connect a lazily connected client.
+	lazyConnectTimeout = 2 * time.Second
 )
 
 // RobotClient satisfies the robot.Robot interface through a gRPC based
@@ -61,7 +71,7 @@ type RobotClient struct {
 	remoteName      string
 	address         string
 	conn            rpc.ClientConn
-	client          pb.RobotServiceClient
+	client          atomic.Value // pb.RobotServiceClient, read by calls without holding mu
 	refClient       *grpcreflect.Client
 	dialOptions     []rpc.DialOption
 	resourceClients map[resource.Name]interface{}
@@ -71,9 +81,25 @@ type RobotClient struct {
 	resourceNames       []resource.Name
 	resourceRPCSubtypes []resource.RPCSubtype
 
-	connected  bool
+	// connected is checked by the interceptors of calls made while mu is held, so is atomic.
+	connected  atomic.Bool
 	changeChan chan bool
 
+	connPool           *grpc.SharedConnPool
+	connPoolKey        string
+	unaryInterceptors  []googlegrpc.UnaryClientInterceptor
+	streamInterceptors []googlegrpc.StreamClientInterceptor
+
+	lazyConnect bool
+	// reconnects is whether a background loop keeps trying to connect. A lazily connected
+	// client without one instead connects on first use.
+	reconnects bool
+	// connectMu serializes connecting in the background with connecting on first use.
+	connectMu         sync.Mutex
+	lastConnected     time.Time
+	lastConnectErr    error
+	reconnectAttempts int
+
 	activeBackgroundWorkers *sync.WaitGroup
 	cancelBackgroundWorkers func()
 	logger                  golog.Logger
@@ -208,31 +234,47 @@ func New(ctx context.Context, address string, logger golog.Logger, opts ...Robot
 		resourceClients:         make(map[resource.Name]interface{}),
 		remoteNameMap:           make(map[resource.Name]resource.Name),
 		sessionsDisabled:        rOpts.disableSessions,
+		lazyConnect:             rOpts.lazyConnect,
+		connPool:                rOpts.connPool,
+		connPoolKey:             rOpts.connPoolKey,
 	}
+	reconnectMaxInterval := rOpts.reconnectMaxInterval
 
 	// interceptors are applied in order from first to last
-	rc.dialOptions = append(
-		rc.dialOptions,
+	rc.unaryInterceptors = []googlegrpc.UnaryClientInterceptor{
 		// error handling
-		rpc.WithUnaryClientInterceptor(rc.handleUnaryDisconnect),
-		rpc.WithStreamClientInterceptor(rc.handleStreamDisconnect),
+		rc.handleUnaryDisconnect,
 		// sessions
-		rpc.WithUnaryClientInterceptor(grpc_retry.UnaryClientInterceptor()),
-		rpc.WithStreamClientInterceptor(grpc_retry.StreamClientInterceptor()),
-		rpc.WithUnaryClientInterceptor(rc.sessionUnaryClientInterceptor),
-		rpc.WithStreamClientInterceptor(rc.sessionStreamClientInterceptor),
+		grpc_retry.UnaryClientInterceptor(),
+		rc.sessionUnaryClientInterceptor,
 		// operations
-		rpc.WithUnaryClientInterceptor(operation.UnaryClientInterceptor),
-		rpc.WithStreamClientInterceptor(operation.StreamClientInterceptor),
-	)
-
-	if err := rc.connect(ctx); err != nil {
-		return nil, err
+		operation.UnaryClientInterceptor,
+	}
+	rc.streamInterceptors = []googlegrpc.StreamClientInterceptor{
+		rc.handleStreamDisconnect,
+		grpc_retry.StreamClientInterceptor(),
+		rc.sessionStreamClientInterceptor,
+		operation.StreamClientInterceptor,
+	}
+	if rc.connPool == nil {
+		// a shared connection is used by other clients too, so our interceptors are
+		// instead applied on top of it when connecting.
+		for _, interceptor := range rc.unaryInterceptors {
+			rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(interceptor))
+		}
+		for _, interceptor := range rc.streamInterceptors {
+			rc.dialOptions = append(rc.dialOptions, rpc.WithStreamClientInterceptor(interceptor))
+		}
 	}
 
-	// refresh once to hydrate the robot.
-	if err := rc.Refresh(ctx); err != nil {
-		return nil, multierr.Combine(err, rc.conn.Close())
+	if !rOpts.lazyConnect {
+		if err := rc.connect(ctx); err != nil {
+			return nil, err
+		}
+		// refresh once to hydrate the robot.
+		if err := rc.Refresh(ctx); err != nil {
+			return nil, multierr.Combine(err, rc.conn.Close())
+		}
 	}
 
 	var refreshTime time.Duration
@@ -254,11 +296,22 @@ func New(ctx context.Context, address string, logger golog.Logger, opts ...Robot
 		reconnectTime = *rOpts.reconnectEvery
 	}
 
-	if checkConnectedTime > 0 && reconnectTime > 0 {
+	rc.reconnects = checkConnectedTime > 0 && reconnectTime > 0
+	if rOpts.lazyConnect {
+		// connect in the background so that a robot that cannot be reached does not hold up the caller.
+		// Until then, calls made on the client fail as unavailable or connect it on first use.
+		rc.activeBackgroundWorkers.Add(1)
+		utils.ManagedGo(func() {
+			if err := rc.connectIfDisconnected(closeCtx); err != nil {
+				rc.logger.Infow("remote not reachable yet; will keep trying to connect", "address", address, "error", err)
+			}
+		}, rc.activeBackgroundWorkers.Done)
+	}
+	if rc.reconnects {
 		refresh := checkConnectedTime == refreshTime
 		rc.activeBackgroundWorkers.Add(1)
 		utils.ManagedGo(func() {
-			rc.checkConnection(closeCtx, checkConnectedTime, reconnectTime, refresh)
+			rc.checkConnection(closeCtx, checkConnectedTime, reconnectTime, reconnectMaxInterval, refresh)
 		}, rc.activeBackgroundWorkers.Done)
 
 		// If checkConnection() is running refresh, there is no need to create a separate
@@ -289,7 +342,39 @@ func (rc *RobotClient) SetParentNotifier(f func()) {
 func (rc *RobotClient) Connected() bool {
 	rc.mu.RLock()
 	defer rc.mu.RUnlock()
-	return rc.connected
+	return rc.connected.Load()
+}
+
+// ConnectionStatus describes the state of a robot client's connection to its robot.
+type ConnectionStatus struct {
+	Address           string    `json:"address"`
+	Connected         bool      `json:"connected"`
+	LastConnected     time.Time `json:"last_connected,omitempty"`
+	LastError         string    `json:"last_error,omitempty"`
+	ReconnectAttempts int       `json:"reconnect_attempts"`
+}
+
+// ConnectionStatus returns the current state of the connection to the remote.
+func (rc *RobotClient) ConnectionStatus() ConnectionStatus {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	status := ConnectionStatus{
+		Address:           rc.address,
+		Connected:         rc.connected.Load(),
+		LastConnected:     rc.lastConnected,
+		ReconnectAttempts: rc.reconnectAttempts,
+	}
+	if rc.lastConnectErr != nil {
+		status.LastError = rc.lastConnectErr.Error()
+	}
+	return status
+}
+
+func (rc *RobotClient) setConnectErr(err error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.lastConnectErr = err
+	rc.reconnectAttempts++
 }
 
 // Changed watches for whether the remote has changed.
@@ -305,11 +390,16 @@ func (rc *RobotClient) Changed() <-chan bool {
 
 func (rc *RobotClient) connect(ctx context.Context) error {
 	if rc.conn != nil {
+		if rc.connPool != nil {
+			// we are reconnecting because the connection stopped working, so do not
+			// pick the same one up again.
+			rc.connPool.Invalidate(rc.conn)
+		}
 		if err := rc.conn.Close(); err != nil {
 			return err
 		}
 	}
-	conn, err := grpc.Dial(ctx, rc.address, rc.logger, rc.dialOptions...)
+	conn, err := rc.dial(ctx)
 	if err != nil {
 		return err
 	}
@@ -322,10 +412,14 @@ func (rc *RobotClient) connect(ctx context.Context) error {
 	refClient := grpcreflect.NewClient(rc.closeContext, reflectpb.NewServerReflectionClient(conn))
 
 	rc.conn = conn
-	rc.client = client
+	rc.client.Store(client)
 	rc.refClient = refClient
-	rc.connected = true
-	if len(rc.resourceClients) != 0 {
+	rc.connected.Store(true)
+	rc.lastConnected = time.Now()
+	rc.lastConnectErr = nil
+	rc.reconnectAttempts = 0
+	// a lazily connected client has never been hydrated, so fetch its resources now.
+	if len(rc.resourceClients) != 0 || (rc.lazyConnect && rc.resourceNames == nil) {
 		if err := rc.updateResources(ctx, updateReasonReconnect); err != nil {
 			return err
 		}
@@ -340,6 +434,18 @@ func (rc *RobotClient) connect(ctx context.Context) error {
 	return nil
 }
 
+// dial connects to the robot, sharing the connection of the client's pool if it has one.
+func (rc *RobotClient) dial(ctx context.Context) (rpc.ClientConn, error) {
+	if rc.connPool == nil {
+		return grpc.Dial(ctx, rc.address, rc.logger, rc.dialOptions...)
+	}
+	conn, err := rc.connPool.Dial(ctx, rc.connPoolKey, rc.address, rc.dialOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return grpc.NewInterceptedClientConn(conn, rc.unaryInterceptors, rc.streamInterceptors), nil
+}
+
 type updateReason byte
 
 const (
@@ -386,14 +492,20 @@ func (rc *RobotClient) updateResourceClients(ctx context.Context, reason updateR
 }
 
 // checkConnection either checks if the client is still connected, or attempts to reconnect to the remote.
-func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnectEvery time.Duration, refresh bool) {
+func (rc *RobotClient) checkConnection(
+	ctx context.Context,
+	checkEvery, reconnectEvery, reconnectMaxInterval time.Duration,
+	refresh bool,
+) {
+	reconnectWait := reconnectEvery
 	for {
 		var waitTime time.Duration
-		if rc.connected {
+		if rc.Connected() {
 			waitTime = checkEvery
+			reconnectWait = reconnectEvery
 		} else {
 			if reconnectEvery != 0 {
-				waitTime = reconnectEvery
+				waitTime = reconnectWait
 			} else {
 				// if reconnectEvery is unset, we will not attempt to reconnect
 				return
@@ -402,10 +514,16 @@ func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnec
 		if !utils.SelectContextOrWait(ctx, waitTime) {
 			return
 		}
-		if !rc.connected {
+		if !rc.Connected() {
 			rc.Logger().Debugw("trying to reconnect to remote at address", "address", rc.address)
-			if err := rc.connect(ctx); err != nil {
+			if err := rc.connectIfDisconnected(ctx); err != nil {
 				rc.Logger().Debugw("failed to reconnect remote", "error", err, "address", rc.address)
+				if reconnectMaxInterval > reconnectWait {
+					reconnectWait *= 2
+					if reconnectWait > reconnectMaxInterval {
+						reconnectWait = reconnectMaxInterval
+					}
+				}
 				continue
 			}
 			rc.Logger().Debugw("successfully reconnected remote at address", "address", rc.address)
@@ -447,7 +565,8 @@ func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnec
 					"reconnect_interval", reconnectEvery.Seconds(),
 				)
 				rc.mu.Lock()
-				rc.connected = false
+				rc.connected.Store(false)
+				rc.lastConnectErr = outerError
 				if rc.changeChan != nil {
 					rc.changeChan <- true
 				}
@@ -470,12 +589,88 @@ func (rc *RobotClient) Close(ctx context.Context) error {
 		close(rc.changeChan)
 		rc.changeChan = nil
 	}
-	rc.refClient.Reset()
+	if rc.refClient != nil {
+		rc.refClient.Reset()
+	}
+	if rc.conn == nil {
+		return nil
+	}
 	return rc.conn.Close()
 }
 
+// robotServiceClient returns the robot service client, or an unavailable error if
+// the robot has not been connected to yet. A lazily connected client that has no
+// background loop to keep trying to connect tries to connect here instead.
+func (rc *RobotClient) robotServiceClient(ctx context.Context) (pb.RobotServiceClient, error) {
+	if client := rc.loadClient(); client != nil {
+		return client, nil
+	}
+	if rc.lazyConnect && !rc.reconnects {
+		if err := rc.connectOnFirstUse(ctx); err != nil {
+			return nil, status.Error(codes.Unavailable, errors.Wrap(err, rc.notConnectedToRemoteError().Error()).Error())
+		}
+		if client := rc.loadClient(); client != nil {
+			return client, nil
+		}
+	}
+	return nil, status.Error(codes.Unavailable, rc.notConnectedToRemoteError().Error())
+}
+
+// clientConn returns the connection to the robot for the services rdk robots serve alongside the robot
+// service, connecting it like robotServiceClient does.
+func (rc *RobotClient) clientConn(ctx context.Context) (rpc.ClientConn, error) {
+	if _, err := rc.robotServiceClient(ctx); err != nil {
+		return nil, err
+	}
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.conn, nil
+}
+
+// loadClient returns the robot service client, or nil if the robot has not been connected to yet.
+func (rc *RobotClient) loadClient() pb.RobotServiceClient {
+	client, _ := rc.client.Load().(pb.RobotServiceClient)
+	return client
+}
+
+// connectOnFirstUse connects a lazily connected client that no background loop connects,
+// unless it has already been connected.
+func (rc *RobotClient) connectOnFirstUse(ctx context.Context) error {
+	rc.connectMu.Lock()
+	defer rc.connectMu.Unlock()
+	if rc.loadClient() != nil {
+		return nil
+	}
+	return rc.connectWithTimeout(ctx)
+}
+
+// connectIfDisconnected connects the client unless another attempt to connect already has.
+func (rc *RobotClient) connectIfDisconnected(ctx context.Context) error {
+	rc.connectMu.Lock()
+	defer rc.connectMu.Unlock()
+	if rc.connected.Load() {
+		return nil
+	}
+	return rc.connectWithTimeout(ctx)
+}
+
+// connectWithTimeout connects the client, giving up on a lazily connected one after lazyConnectTimeout
+// so that it does not wait for a robot that cannot be reached.
+func (rc *RobotClient) connectWithTimeout(ctx context.Context) error {
+	if rc.lazyConnect {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, lazyConnectTimeout)
+		defer cancel()
+	}
+	if err := rc.connect(ctx); err != nil {
+		rc.setConnectErr(err)
+		return err
+	}
+	return nil
+}
+
 func (rc *RobotClient) checkConnected() error {
-	if !rc.connected {
+	if !rc.connected.Load() {
 		return rc.notConnectedToRemoteError()
 	}
 	return nil
@@ -569,13 +764,17 @@ func (rc *RobotClient) createClient(name resource.Name) (interface{}, error) {
 func (rc *RobotClient) resources(ctx context.Context) ([]resource.Name, []resource.RPCSubtype, error) {
 	ctx, cancel := context.WithTimeout(ctx, resourcesTimeout)
 	defer cancel()
-	resp, err := rc.client.ResourceNames(ctx, &pb.ResourceNamesRequest{})
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := client.ResourceNames(ctx, &pb.ResourceNamesRequest{})
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var resTypes []resource.RPCSubtype
-	typesResp, err := rc.client.ResourceRPCSubtypes(ctx, &pb.ResourceRPCSubtypesRequest{})
+	typesResp, err := client.ResourceRPCSubtypes(ctx, &pb.ResourceRPCSubtypesRequest{})
 	if err == nil {
 		reflSource := grpcurl.DescriptorSourceFromServer(ctx, rc.refClient)
 
@@ -745,7 +944,11 @@ func (rc *RobotClient) DiscoverComponents(ctx context.Context, qs []discovery.Qu
 		)
 	}
 
-	resp, err := rc.client.DiscoverComponents(ctx, &pb.DiscoverComponentsRequest{Queries: pbQueries})
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.DiscoverComponents(ctx, &pb.DiscoverComponentsRequest{Queries: pbQueries})
 	if err != nil {
 		return nil, err
 	}
@@ -782,7 +985,11 @@ func (rc *RobotClient) FrameSystemConfig(
 	if err != nil {
 		return nil, err
 	}
-	resp, err := rc.client.FrameSystemConfig(ctx, &pb.FrameSystemConfigRequest{SupplementalTransforms: transforms})
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.FrameSystemConfig(ctx, &pb.FrameSystemConfigRequest{SupplementalTransforms: transforms})
 	if err != nil {
 		return nil, err
 	}
@@ -809,7 +1016,11 @@ func (rc *RobotClient) TransformPose(
 	if err != nil {
 		return nil, err
 	}
-	resp, err := rc.client.TransformPose(ctx, &pb.TransformPoseRequest{
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.TransformPose(ctx, &pb.TransformPoseRequest{
 		Destination:            destination,
 		Source:                 referenceframe.PoseInFrameToProtobuf(query),
 		SupplementalTransforms: transforms,
@@ -835,8 +1046,12 @@ func (rc *RobotClient) TransformPointCloud(ctx context.Context, srcpc pointcloud
 		return nil, errors.New("srcName cannot be empty, must provide name of point cloud origin")
 	}
 	// get the offset pose from a TransformPose request
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
 	sourceFrameZero := referenceframe.NewPoseInFrame(srcName, spatialmath.NewZeroPose())
-	resp, err := rc.client.TransformPose(ctx, &pb.TransformPoseRequest{
+	resp, err := client.TransformPose(ctx, &pb.TransformPoseRequest{
 		Destination:            dstName,
 		Source:                 referenceframe.PoseInFrameToProtobuf(sourceFrameZero),
 		SupplementalTransforms: []*commonpb.Transform{},
@@ -855,7 +1070,11 @@ func (rc *RobotClient) Status(ctx context.Context, resourceNames []resource.Name
 		names = append(names, rprotoutils.ResourceNameToProto(name))
 	}
 
-	resp, err := rc.client.GetStatus(ctx, &pb.GetStatusRequest{ResourceNames: names})
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetStatus(ctx, &pb.GetStatusRequest{ResourceNames: names})
 	if err != nil {
 		return nil, err
 	}
@@ -871,6 +1090,55 @@ func (rc *RobotClient) Status(ctx context.Context, resourceNames []resource.Name
 	return statuses, nil
 }
 
+// StreamStatus calls onStatus with the statuses of the given resources, or all resources if none
+// are given, whenever they change. The robot checks for changes at most once every minInterval,
+// or at its default interval if minInterval is zero. The first call includes every status and later
+// calls only the ones that changed; resources that are gone are included once with a nil status.
+// It blocks until ctx is done or the stream fails.
+func (rc *RobotClient) StreamStatus(
+	ctx context.Context,
+	resourceNames []resource.Name,
+	minInterval time.Duration,
+	onStatus func([]robot.Status),
+) error {
+	names := make([]*commonpb.ResourceName, 0, len(resourceNames))
+	for _, name := range resourceNames {
+		names = append(names, rprotoutils.ResourceNameToProto(name))
+	}
+
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+	req := &pb.StreamStatusRequest{ResourceNames: names}
+	if minInterval != 0 {
+		req.Every = durationpb.New(minInterval)
+	}
+	stream, err := client.StreamStatus(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if len(resp.Status) == 0 {
+			// nothing changed
+			continue
+		}
+		statuses := make([]robot.Status, 0, len(resp.Status))
+		for _, status := range resp.Status {
+			st := robot.Status{Name: rprotoutils.ResourceNameFromProto(status.Name)}
+			if status.Status != nil {
+				st.Status = status.Status.AsMap()
+			}
+			statuses = append(statuses, st)
+		}
+		onStatus(statuses)
+	}
+}
+
 // StopAll cancels all current and outstanding operations for the robot and stops all actuators and movement.
 func (rc *RobotClient) StopAll(ctx context.Context, extra map[resource.Name]map[string]interface{}) error {
 	e := []*pb.StopExtraParameters{}
@@ -886,6 +1154,166 @@ func (rc *RobotClient) StopAll(ctx context.Context, extra map[resource.Name]map[
 		}
 		e = append(e, p)
 	}
-	_, err := rc.client.StopAll(ctx, &pb.StopAllRequest{Extra: e})
+	client, err := rc.robotServiceClient(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = client.StopAll(ctx, &pb.StopAllRequest{Extra: e})
 	return err
 }
+
+// ResourceGraph returns the dependency graph between the resources of the robot, along with the part of
+// the config each resource came from and the state it is in.
+func (rc *RobotClient) ResourceGraph(ctx context.Context) (robot.ResourceGraph, error) {
+	conn, err := rc.clientConn(ctx)
+	if err != nil {
+		return robot.ResourceGraph{}, err
+	}
+	resp, err := configpb.NewRobotIntrospectionServiceClient(conn).GetResourceGraph(ctx, &configpb.GetResourceGraphRequest{})
+	if err != nil {
+		return robot.ResourceGraph{}, err
+	}
+	var graph robot.ResourceGraph
+	for _, node := range resp.Nodes {
+		name, err := resource.NewFromString(node.Name)
+		if err != nil {
+			return robot.ResourceGraph{}, err
+		}
+		graph.Nodes = append(graph.Nodes, robot.ResourceGraphNode{
+			Name:      name,
+			Model:     node.Model,
+			Source:    node.Source,
+			DependsOn: node.DependsOn,
+			State:     node.State,
+			Error:     node.Error,
+		})
+	}
+	for _, edge := range resp.Edges {
+		from, err := resource.NewFromString(edge.From)
+		if err != nil {
+			return robot.ResourceGraph{}, err
+		}
+		to, err := resource.NewFromString(edge.To)
+		if err != nil {
+			return robot.ResourceGraph{}, err
+		}
+		graph.Edges = append(graph.Edges, robot.ResourceGraphEdge{From: from, To: to})
+	}
+	return graph, nil
+}
+
+// FrameSystemSnapshot returns the current world pose and geometries of every frame in the
+// robot's frame system, ordered by frame name.
+func (rc *RobotClient) FrameSystemSnapshot(
+	ctx context.Context,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) ([]framesystem.FrameSnapshot, error) {
+	conn, err := rc.clientConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transforms, err := referenceframe.LinkInFramesToTransformsProtobuf(additionalTransforms)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := configpb.NewRobotIntrospectionServiceClient(conn).GetFrameSystemSnapshot(
+		ctx,
+		&configpb.GetFrameSystemSnapshotRequest{SupplementalTransforms: transforms},
+	)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make([]framesystem.FrameSnapshot, 0, len(resp.Frames))
+	for _, frame := range resp.Frames {
+		snapshot := framesystem.FrameSnapshot{
+			Name:   frame.Name,
+			Parent: frame.Parent,
+			Pose:   spatialmath.NewPoseFromProtobuf(frame.Pose),
+		}
+		for _, g := range frame.Geometries {
+			geometry, err := spatialmath.NewGeometryFromProto(g)
+			if err != nil {
+				return nil, err
+			}
+			snapshot.Geometries = append(snapshot.Geometries, geometry)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// OperationTree returns the running operations of the robot arranged by which started which.
+// The operations only describe those of the robot; they cannot be canceled through them.
+func (rc *RobotClient) OperationTree(ctx context.Context) ([]*operation.Node, error) {
+	conn, err := rc.clientConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := configpb.NewRobotIntrospectionServiceClient(conn).GetOperations(ctx, &configpb.GetOperationsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	ops := make([]*operation.Operation, 0, len(resp.Operations))
+	for _, pbOp := range resp.Operations {
+		id, err := uuid.Parse(pbOp.Id)
+		if err != nil {
+			return nil, err
+		}
+		op := &operation.Operation{
+			ID:      id,
+			Method:  pbOp.Method,
+			Started: pbOp.Started.AsTime(),
+			TraceID: pbOp.TraceId,
+		}
+		if args := pbOp.Arguments.AsMap(); len(args) > 0 {
+			op.Arguments = args
+		}
+		if pbOp.SessionId != nil {
+			if op.SessionID, err = uuid.Parse(*pbOp.SessionId); err != nil {
+				return nil, err
+			}
+		}
+		if pbOp.ParentId != nil {
+			if op.ParentID, err = uuid.Parse(*pbOp.ParentId); err != nil {
+				return nil, err
+			}
+		}
+		ops = append(ops, op)
+	}
+	return operation.BuildTree(ops), nil
+}
+
+// StartRecording starts recording the named camera stream to segmented files on the robot. The
+// recording continues until StopRecording is called, a limit in opts is reached or the robot shuts down.
+func (rc *RobotClient) StartRecording(ctx context.Context, name string, opts webstream.RecordingOptions) error {
+	conn, err := rc.clientConn(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = streamcontrolpb.NewStreamControlServiceClient(conn).StartRecording(ctx, &streamcontrolpb.StartRecordingRequest{
+		Name: name,
+		Options: &streamcontrolpb.RecordingOptions{
+			Dir:             opts.Dir,
+			SegmentDuration: durationpb.New(opts.SegmentDuration),
+			SegmentMaxBytes: opts.SegmentMaxBytes,
+			MaxDuration:     durationpb.New(opts.MaxDuration),
+			MaxBytes:        opts.MaxBytes,
+			FrameRate:       opts.FrameRate,
+		},
+	})
+	return err
+}
+
+// StopRecording stops recording the named camera stream and returns the paths of the segment files
+// written on the robot.
+func (rc *RobotClient) StopRecording(ctx context.Context, name string) ([]string, error) {
+	conn, err := rc.clientConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := streamcontrolpb.NewStreamControlServiceClient(conn).StopRecording(ctx, &streamcontrolpb.StopRecordingRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Segments, nil
+}
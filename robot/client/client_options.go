@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/grpc"
 )
 
 // robotClientOpts configure a Dial call. robotClientOpts are set by the RobotClientOption
@@ -24,6 +26,14 @@ type robotClientOpts struct {
 	// it will automatically refresh every 1s
 	reconnectEvery *time.Duration
 
+	// reconnectMaxInterval, if set, causes the time between failed reconnect
+	// attempts to double, starting from reconnectEvery, up to this interval.
+	reconnectMaxInterval time.Duration
+
+	// lazyConnect allows the client to be constructed even when the robot
+	// cannot be reached yet; the connection is then established in the background.
+	lazyConnect bool
+
 	// dialOptions are options using for clients dialing gRPC servers.
 	dialOptions []rpc.DialOption
 
@@ -32,6 +42,11 @@ type robotClientOpts struct {
 
 	// controls whether or not sessions are disabled.
 	disableSessions bool
+
+	// connPool, if set, is used to share the connection with other clients dialing
+	// under the same connPoolKey.
+	connPool    *grpc.SharedConnPool
+	connPoolKey string
 }
 
 // RobotClientOption configures how we set up the connection.
@@ -78,6 +93,24 @@ func WithReconnectEvery(reconnectEvery time.Duration) RobotClientOption {
 	})
 }
 
+// WithReconnectBackoff returns a RobotClientOption which makes reconnect attempts back off
+// exponentially, starting at the reconnect interval, up to the given maximum interval.
+func WithReconnectBackoff(maxInterval time.Duration) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.reconnectMaxInterval = maxInterval
+	})
+}
+
+// WithLazyConnect returns a RobotClientOption which allows the client to be returned
+// without waiting for a connection to the robot, which is instead established in the background.
+// Until connected, the client reports itself as disconnected and keeps trying to reconnect in the
+// background.
+func WithLazyConnect() RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.lazyConnect = true
+	})
+}
+
 // WithRemoteName returns a RobotClientOption setting the name of the remote robot.
 func WithRemoteName(remoteName string) RobotClientOption {
 	return newFuncRobotClientOption(func(o *robotClientOpts) {
@@ -92,6 +125,16 @@ func WithDisableSessions() RobotClientOption {
 	})
 }
 
+// WithSharedConnPool returns a RobotClientOption which makes the client share its
+// connection with every other client of the pool using the same key. The key must
+// distinguish anything the dial options would, such as the credentials used.
+func WithSharedConnPool(pool *grpc.SharedConnPool, key string) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.connPool = pool
+		o.connPoolKey = key
+	})
+}
+
 // WithDialOptions returns a RobotClientOption which sets the options for making
 // gRPC connections to other servers.
 func WithDialOptions(opts ...rpc.DialOption) RobotClientOption {
@@ -66,7 +66,11 @@ func (rc *RobotClient) heartbeatLoop() {
 			sendReq := &pb.SendSessionHeartbeatRequest{
 				Id: sessID,
 			}
-			if _, err := rc.client.SendSessionHeartbeat(rc.closeContext, sendReq); err != nil {
+			client := rc.loadClient()
+			if client == nil {
+				return
+			}
+			if _, err := client.SendSessionHeartbeat(rc.closeContext, sendReq); err != nil {
 				if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
 					rc.sessionReset()
 					return
@@ -119,7 +123,11 @@ func (rc *RobotClient) sessionMetadata(ctx context.Context, method string) (cont
 		startReq.Resume = rc.currentSessionID
 	}
 
-	startResp, err := rc.client.StartSession(
+	client := rc.loadClient()
+	if client == nil {
+		return nil, status.Error(codes.Unavailable, rc.notConnectedToRemoteError().Error())
+	}
+	startResp, err := client.StartSession(
 		reqCtx,
 		&startReq,
 		grpc_retry.WithMax(5),
@@ -662,6 +662,120 @@ func TestClientRefresh(t *testing.T) {
 	})
 }
 
+func TestClientLazyConnect(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	addr := listener.Addr().String()
+	test.That(t, listener.Close(), test.ShouldBeNil)
+
+	dur := 50 * time.Millisecond
+	client, err := New(
+		context.Background(),
+		addr,
+		logger,
+		WithLazyConnect(),
+		WithCheckConnectedEvery(dur),
+		WithReconnectEvery(dur),
+		WithReconnectBackoff(4*dur),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(context.Background(), client), test.ShouldBeNil)
+	}()
+
+	test.That(t, client.Connected(), test.ShouldBeFalse)
+	test.That(t, client.ResourceNames(), test.ShouldBeEmpty)
+	gotestutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		connStatus := client.ConnectionStatus()
+		test.That(tb, connStatus.Connected, test.ShouldBeFalse)
+		test.That(tb, connStatus.LastError, test.ShouldNotBeEmpty)
+		test.That(tb, connStatus.ReconnectAttempts, test.ShouldBeGreaterThanOrEqualTo, 1)
+	})
+
+	_, err = client.Status(context.Background(), []resource.Name{})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+
+	listener, err = net.Listen("tcp", addr)
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+	injectRobot := &inject.Robot{}
+	pb.RegisterRobotServiceServer(gServer, server.New(injectRobot))
+	injectRobot.ResourceRPCSubtypesFunc = func() []resource.RPCSubtype { return nil }
+	injectRobot.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{arm.Named("arm1")}
+	}
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	gotestutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, client.Connected(), test.ShouldBeTrue)
+		test.That(tb, client.ResourceNames(), test.ShouldHaveLength, 1)
+	})
+	connStatus := client.ConnectionStatus()
+	test.That(t, connStatus.LastError, test.ShouldBeEmpty)
+	test.That(t, connStatus.ReconnectAttempts, test.ShouldEqual, 0)
+	test.That(t, connStatus.LastConnected.IsZero(), test.ShouldBeFalse)
+}
+
+func TestClientLazyConnectDoesNotWait(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	// a robot that accepts connections but never answers
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, listener.Close(), test.ShouldBeNil)
+	}()
+
+	start := time.Now()
+	client, err := New(context.Background(), listener.Addr().String(), logger, WithLazyConnect())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, lazyConnectTimeout/2)
+	test.That(t, client.Connected(), test.ShouldBeFalse)
+	test.That(t, utils.TryClose(context.Background(), client), test.ShouldBeNil)
+}
+
+func TestClientLazyConnectOnFirstUse(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	addr := listener.Addr().String()
+	test.That(t, listener.Close(), test.ShouldBeNil)
+
+	// without a reconnect loop, nothing keeps trying to connect the client in the background
+	client, err := New(context.Background(), addr, logger, WithLazyConnect(), WithReconnectEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(context.Background(), client), test.ShouldBeNil)
+	}()
+	test.That(t, client.Connected(), test.ShouldBeFalse)
+	_, err = client.Status(context.Background(), []resource.Name{})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+
+	listener, err = net.Listen("tcp", addr)
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+	injectRobot := &inject.Robot{}
+	pb.RegisterRobotServiceServer(gServer, server.New(injectRobot))
+	injectRobot.ResourceRPCSubtypesFunc = func() []resource.RPCSubtype { return nil }
+	injectRobot.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{arm.Named("arm1")}
+	}
+	injectRobot.StatusFunc = func(ctx context.Context, resourceNames []resource.Name) ([]robot.Status, error) {
+		return []robot.Status{}, nil
+	}
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	// so the first call connects it
+	_, err = client.Status(context.Background(), []resource.Name{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, client.Connected(), test.ShouldBeTrue)
+	test.That(t, client.ResourceNames(), test.ShouldHaveLength, 1)
+}
+
 func TestClientDisconnect(t *testing.T) {
 	logger := golog.NewTestLogger(t)
 	listener, err := net.Listen("tcp", "localhost:0")
@@ -762,12 +876,12 @@ func TestClientUnaryDisconnectHandler(t *testing.T) {
 	t.Run("unary call to connected remote", func(t *testing.T) {
 		t.Helper()
 
-		client.connected = false
+		client.connected.Store(false)
 		_, err = client.Status(context.Background(), []resource.Name{})
 		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
 		test.That(t, err.Error(), test.ShouldContainSubstring, fmt.Sprintf("not connected to remote robot at %s", listener.Addr().String()))
 		test.That(t, unaryStatusCallReceived, test.ShouldBeFalse)
-		client.connected = true
+		client.connected.Store(true)
 	})
 
 	t.Run("unary call to disconnected remote", func(t *testing.T) {
@@ -836,18 +950,18 @@ func TestClientStreamDisconnectHandler(t *testing.T) {
 	t.Run("stream call to disconnected remote", func(t *testing.T) {
 		t.Helper()
 
-		client.connected = false
-		_, err = client.client.StreamStatus(context.Background(), &pb.StreamStatusRequest{})
+		client.connected.Store(false)
+		_, err = client.loadClient().StreamStatus(context.Background(), &pb.StreamStatusRequest{})
 		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
 		test.That(t, err.Error(), test.ShouldContainSubstring, fmt.Sprintf("not connected to remote robot at %s", listener.Addr().String()))
 		test.That(t, streamStatusCallReceived, test.ShouldBeFalse)
-		client.connected = true
+		client.connected.Store(true)
 	})
 
 	t.Run("stream call to connected remote", func(t *testing.T) {
 		t.Helper()
 
-		ssc, err := client.client.StreamStatus(context.Background(), &pb.StreamStatusRequest{})
+		ssc, err := client.loadClient().StreamStatus(context.Background(), &pb.StreamStatusRequest{})
 		test.That(t, err, test.ShouldBeNil)
 		ssc.Recv()
 		test.That(t, streamStatusCallReceived, test.ShouldBeTrue)
@@ -856,14 +970,14 @@ func TestClientStreamDisconnectHandler(t *testing.T) {
 	t.Run("receive call from stream of disconnected remote", func(t *testing.T) {
 		t.Helper()
 
-		ssc, err := client.client.StreamStatus(context.Background(), &pb.StreamStatusRequest{})
+		ssc, err := client.loadClient().StreamStatus(context.Background(), &pb.StreamStatusRequest{})
 		test.That(t, err, test.ShouldBeNil)
 
-		client.connected = false
+		client.connected.Store(false)
 		_, err = ssc.Recv()
 		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
 		test.That(t, err.Error(), test.ShouldContainSubstring, fmt.Sprintf("not connected to remote robot at %s", listener.Addr().String()))
-		client.connected = true
+		client.connected.Store(true)
 	})
 
 	defer func() {
@@ -1783,3 +1897,47 @@ func TestGetUnknownResource(t *testing.T) {
 	err = client.Close(context.Background())
 	test.That(t, err, test.ShouldBeNil)
 }
+
+func TestClientStreamStatus(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	listener, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	gServer := grpc.NewServer()
+
+	injectRobot := &inject.Robot{
+		ResourceNamesFunc:       func() []resource.Name { return []resource.Name{arm.Named("arm1")} },
+		ResourceRPCSubtypesFunc: func() []resource.RPCSubtype { return nil },
+	}
+	var calls int32
+	injectRobot.StatusFunc = func(ctx context.Context, rs []resource.Name) ([]robot.Status, error) {
+		// arm1 is removed after the first call
+		if atomic.AddInt32(&calls, 1) > 1 {
+			return []robot.Status{}, nil
+		}
+		return []robot.Status{{Name: arm.Named("arm1"), Status: map[string]interface{}{"is_moving": true}}}, nil
+	}
+	pb.RegisterRobotServiceServer(gServer, server.New(injectRobot))
+	go gServer.Serve(listener)
+	defer gServer.Stop()
+
+	client, err := New(context.Background(), listener.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(context.Background(), client), test.ShouldBeNil)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var received [][]robot.Status
+	err = client.StreamStatus(ctx, nil, 10*time.Millisecond, func(statuses []robot.Status) {
+		received = append(received, statuses)
+		if len(received) == 2 {
+			cancel()
+		}
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Canceled)
+	test.That(t, received, test.ShouldResemble, [][]robot.Status{
+		{{Name: arm.Named("arm1"), Status: map[string]interface{}{"is_moving": true}}},
+		{{Name: arm.Named("arm1")}},
+	})
+}
@@ -259,6 +259,10 @@ func (svc *frameSystemService) updateLocalParts(ctx context.Context) error {
 			Orientation: c.Frame.Orientation,
 			Geometry:    c.Frame.Geometry,
 			Parent:      c.Frame.Parent,
+
+			AllowedCollisions: c.Frame.AllowedCollisions,
+			Tags:              c.Frame.Tags,
+			Metadata:          c.Frame.Metadata,
 		}
 		if cfgCopy.ID == "" {
 			cfgCopy.ID = c.Name
@@ -2,6 +2,7 @@ package framesystem_test
 
 import (
 	"context"
+	"encoding/json"
 	"math"
 	"testing"
 
@@ -119,3 +120,57 @@ func TestNewFrameSystemFromPartsBadConfig(t *testing.T) {
 	test.That(t, err.Error(), test.ShouldContainSubstring, "there are no robot parts that connect to a 'world' node.")
 	test.That(t, fs, test.ShouldBeNil)
 }
+
+func TestSnapshot(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	o1 := &spatialmath.R4AA{Theta: math.Pi / 2, RZ: 1}
+	o1Cfg, err := spatialmath.NewOrientationConfig(o1)
+	test.That(t, err, test.ShouldBeNil)
+
+	l1 := &referenceframe.LinkConfig{
+		ID:          "frame1",
+		Parent:      referenceframe.World,
+		Translation: r3.Vector{X: 1, Y: 2, Z: 3},
+		Orientation: o1Cfg,
+		Geometry:    &spatialmath.GeometryConfig{Type: "box", X: 1, Y: 2, Z: 1},
+	}
+	lif1, err := l1.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+
+	l2 := &referenceframe.LinkConfig{
+		ID:          "frame2",
+		Parent:      "frame1",
+		Translation: r3.Vector{X: 1, Y: 2, Z: 3},
+	}
+	lif2, err := l2.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+
+	frameSys, err := framesystem.NewFrameSystemFromParts("", "", []*referenceframe.FrameSystemPart{
+		{FrameConfig: lif1},
+		{FrameConfig: lif2},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	snapshots, err := framesystem.Snapshot(frameSys, referenceframe.StartPositions(frameSys))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, snapshots, test.ShouldHaveLength, len(frameSys.FrameNames()))
+
+	byName := map[string]framesystem.FrameSnapshot{}
+	numGeometries := 0
+	for _, snapshot := range snapshots {
+		byName[snapshot.Name] = snapshot
+		numGeometries += len(snapshot.Geometries)
+	}
+	test.That(t, numGeometries, test.ShouldEqual, 1)
+	test.That(t, byName["frame2"].Parent, test.ShouldEqual, "frame2_origin")
+	test.That(t, byName["frame1_origin"].Parent, test.ShouldEqual, referenceframe.World)
+	test.That(t, spatialmath.R3VectorAlmostEqual(byName["frame1"].Pose.Point(), r3.Vector{X: 1, Y: 2, Z: 3}, 1e-8), test.ShouldBeTrue)
+	// frame2 is offset from frame1, which is rotated a quarter turn about Z
+	test.That(t, spatialmath.R3VectorAlmostEqual(byName["frame2"].Pose.Point(), r3.Vector{X: -1, Y: 3, Z: 6}, 1e-8), test.ShouldBeTrue)
+
+	out, err := json.Marshal(byName["frame2"])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(out), test.ShouldContainSubstring, `"name":"frame2"`)
+	test.That(t, string(out), test.ShouldContainSubstring, `"parent":"frame2_origin"`)
+}
@@ -92,6 +92,13 @@ func NewFrameSystemFromParts(
 		if err != nil {
 			return nil, err
 		}
+		// the tags and metadata of a part describe the frame named for it
+		if err := fs.SetFrameTags(modelFrame.Name(), part.FrameConfig.Tags()...); err != nil {
+			return nil, err
+		}
+		if err := fs.SetFrameMetadata(modelFrame.Name(), part.FrameConfig.Metadata()); err != nil {
+			return nil, err
+		}
 	}
 	logger.Debugf("frames in robot frame system are: %v", frameNamesWithDof(fs))
 	return fs, nil
@@ -0,0 +1,119 @@
+package framesystem
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"go.opencensus.io/trace"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// A FrameSnapshot is the pose of a single frame with respect to the world, along with its
+// geometries, at the moment the snapshot was taken.
+type FrameSnapshot struct {
+	Name   string
+	Parent string
+	// Pose is the pose of the frame in the world frame.
+	Pose spatialmath.Pose
+	// Geometries are the geometries of the frame, posed in the world frame.
+	Geometries []spatialmath.Geometry
+}
+
+// MarshalJSON encodes the snapshot using the JSON form of the common protobuf pose and
+// geometry messages.
+func (fs FrameSnapshot) MarshalJSON() ([]byte, error) {
+	pose, err := protojson.Marshal(spatialmath.PoseToProtobuf(fs.Pose))
+	if err != nil {
+		return nil, err
+	}
+	geometries := make([]json.RawMessage, 0, len(fs.Geometries))
+	for _, g := range fs.Geometries {
+		geometry, err := protojson.Marshal(g.ToProtobuf())
+		if err != nil {
+			return nil, err
+		}
+		geometries = append(geometries, geometry)
+	}
+	return json.Marshal(struct {
+		Name       string            `json:"name"`
+		Parent     string            `json:"parent"`
+		Pose       json.RawMessage   `json:"pose"`
+		Geometries []json.RawMessage `json:"geometries"`
+	}{fs.Name, fs.Parent, pose, geometries})
+}
+
+// Snapshot returns the world pose and world-posed geometries of every frame in the given
+// frame system for the given inputs, ordered by frame name.
+func Snapshot(fs referenceframe.FrameSystem, inputs map[string][]referenceframe.Input) ([]FrameSnapshot, error) {
+	names := fs.FrameNames()
+	sort.Strings(names)
+	snapshots := make([]FrameSnapshot, 0, len(names))
+	for _, name := range names {
+		frame := fs.Frame(name)
+		snapshot := FrameSnapshot{Name: name}
+		if parent, err := fs.Parent(frame); err == nil && parent != nil {
+			snapshot.Parent = parent.Name()
+		}
+
+		tf, err := fs.Transform(inputs, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), referenceframe.World)
+		if err != nil {
+			return nil, err
+		}
+		//nolint:forcetypeassert
+		snapshot.Pose = tf.(*referenceframe.PoseInFrame).Pose()
+
+		input := inputs[name]
+		if input == nil {
+			input = []referenceframe.Input{}
+		}
+		geometries, err := frame.Geometries(input)
+		if err != nil {
+			return nil, err
+		}
+		if len(geometries.Geometries()) > 0 {
+			tf, err := fs.Transform(inputs, geometries, referenceframe.World)
+			if err != nil {
+				return nil, err
+			}
+			//nolint:forcetypeassert
+			posed := tf.(*referenceframe.GeometriesInFrame).Geometries()
+			// geometries are ordered by label, so that snapshots of the same frames come out the same
+			labels := make([]string, 0, len(posed))
+			for label := range posed {
+				labels = append(labels, label)
+			}
+			sort.Strings(labels)
+			snapshot.Geometries = make([]spatialmath.Geometry, 0, len(labels))
+			for _, label := range labels {
+				snapshot.Geometries = append(snapshot.Geometries, posed[label])
+			}
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// RobotSnapshot returns, in one call, the current world pose and geometries of every frame
+// in the robot's frame system, using the present inputs of its components.
+func RobotSnapshot(
+	ctx context.Context,
+	r robot.Robot,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) ([]FrameSnapshot, error) {
+	ctx, span := trace.StartSpan(ctx, "services::framesystem::RobotSnapshot")
+	defer span.End()
+	fs, err := RobotFrameSystem(ctx, r, additionalTransforms)
+	if err != nil {
+		return nil, err
+	}
+	inputs, _, err := RobotFsCurrentInputs(ctx, r, fs)
+	if err != nil {
+		return nil, err
+	}
+	return Snapshot(fs, inputs)
+}
@@ -0,0 +1,16 @@
+package robot
+
+import (
+	"context"
+
+	"go.viam.com/rdk/discovery"
+)
+
+// A HardwareDetector proposes component configs for the hardware attached to a robot.
+type HardwareDetector interface {
+	// DetectHardware returns the USB devices of the robot's machine along with the component
+	// configs registered models propose for them. Reading from I2C devices can change their
+	// state, so the I2C buses of the robot's boards are only scanned if scanI2C is set, and
+	// then only at the addresses registered models expect devices on.
+	DetectHardware(ctx context.Context, scanI2C bool) (discovery.Hardware, []discovery.Proposal, error)
+}
@@ -0,0 +1,107 @@
+package robotimpl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/discovery"
+	"go.viam.com/rdk/registry"
+)
+
+// DetectHardware returns the hardware attached to the robot and the component configs proposed
+// for it by every registered model. The I2C buses of the robot's boards are scanned only if
+// scanI2C is set.
+func (r *localRobot) DetectHardware(
+	ctx context.Context,
+	scanI2C bool,
+) (discovery.Hardware, []discovery.Proposal, error) {
+	var hw discovery.Hardware
+	usb, err := discovery.USBDevices()
+	if err != nil {
+		return hw, nil, errors.Wrap(err, "failed to list USB devices")
+	}
+	hw.USB = usb
+
+	i2cAddrs := registry.ConfigProposerI2CAddresses()
+	taken := map[string]bool{}
+	for _, name := range r.ResourceNames() {
+		taken[name.Name] = true
+		if !scanI2C || len(i2cAddrs) == 0 || name.Subtype != board.Subtype {
+			continue
+		}
+		res, err := r.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		b, ok := res.(board.LocalBoard)
+		if !ok {
+			continue
+		}
+		for _, busName := range b.I2CNames() {
+			bus, ok := b.I2CByName(busName)
+			if !ok {
+				continue
+			}
+			addrs, err := board.ScanI2C(ctx, bus, i2cAddrs)
+			if err != nil {
+				return hw, nil, errors.Wrapf(err, "failed to scan I2C bus %q of board %q", busName, name.Name)
+			}
+			for _, addr := range addrs {
+				hw.I2C = append(hw.I2C, discovery.I2CDevice{Board: name.Name, Bus: busName, Address: addr})
+			}
+		}
+	}
+
+	proposals := proposeConfigs(ctx, hw, registry.RegisteredConfigProposers(), taken, r.logger.Warnw)
+	return hw, proposals, nil
+}
+
+// proposeConfigs asks every proposer for configs for the hardware and names the proposals that
+// were not named, or whose name is taken, after their subtype. Proposers that fail are logged
+// and skipped so that one broken model does not hide the proposals of the others.
+func proposeConfigs(
+	ctx context.Context,
+	hw discovery.Hardware,
+	proposers map[discovery.Query]discovery.Propose,
+	taken map[string]bool,
+	warnw func(msg string, keysAndValues ...interface{}),
+) []discovery.Proposal {
+	queries := make([]discovery.Query, 0, len(proposers))
+	for q := range proposers {
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].API.String() != queries[j].API.String() {
+			return queries[i].API.String() < queries[j].API.String()
+		}
+		return queries[i].Model.String() < queries[j].Model.String()
+	})
+
+	var proposals []discovery.Proposal
+	for _, q := range queries {
+		proposed, err := proposers[q](ctx, hw)
+		if err != nil {
+			warnw("failed to propose configs", "subtype", q.API, "model", q.Model, "error", err)
+			continue
+		}
+		for _, p := range proposed {
+			p.Query = q
+			if p.Name == "" || taken[p.Name] {
+				for idx := 1; ; idx++ {
+					name := fmt.Sprintf("%s%d", q.API.ResourceSubtype, idx)
+					if !taken[name] {
+						p.Name = name
+						break
+					}
+				}
+			}
+			taken[p.Name] = true
+			proposals = append(proposals, p)
+		}
+	}
+	return proposals
+}
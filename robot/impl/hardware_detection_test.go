@@ -0,0 +1,46 @@
+package robotimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/discovery"
+	"go.viam.com/rdk/resource"
+)
+
+func TestProposeConfigs(t *testing.T) {
+	hw := discovery.Hardware{I2C: []discovery.I2CDevice{{Board: "board1", Bus: "main", Address: 0x68}}}
+	imu := discovery.NewQuery(movementsensor.Subtype, resource.NewDefaultModel("imu"))
+	gps := discovery.NewQuery(movementsensor.Subtype, resource.NewDefaultModel("gps"))
+	broken := discovery.NewQuery(movementsensor.Subtype, resource.NewDefaultModel("broken"))
+	proposers := map[discovery.Query]discovery.Propose{
+		imu: func(ctx context.Context, hw discovery.Hardware) ([]discovery.Proposal, error) {
+			return []discovery.Proposal{{Attributes: map[string]interface{}{"i2c_bus": hw.I2C[0].Bus}}}, nil
+		},
+		gps: func(ctx context.Context, hw discovery.Hardware) ([]discovery.Proposal, error) {
+			return []discovery.Proposal{{Name: "board1"}, {Name: "gps"}}, nil
+		},
+		broken: func(ctx context.Context, hw discovery.Hardware) ([]discovery.Proposal, error) {
+			return nil, errors.New("broken")
+		},
+	}
+
+	var warnings int
+	warnw := func(msg string, keysAndValues ...interface{}) { warnings++ }
+	taken := map[string]bool{"board1": true, "movement_sensor1": true}
+	proposals := proposeConfigs(context.Background(), hw, proposers, taken, warnw)
+	test.That(t, warnings, test.ShouldEqual, 1)
+	test.That(t, proposals, test.ShouldHaveLength, 3)
+
+	// proposals are ordered by model and names that are taken are replaced
+	test.That(t, proposals[0].Query, test.ShouldResemble, gps)
+	test.That(t, proposals[0].Name, test.ShouldEqual, "movement_sensor2")
+	test.That(t, proposals[1].Name, test.ShouldEqual, "gps")
+	test.That(t, proposals[2].Query, test.ShouldResemble, imu)
+	test.That(t, proposals[2].Name, test.ShouldEqual, "movement_sensor3")
+	test.That(t, proposals[2].Attributes, test.ShouldResemble, map[string]interface{}{"i2c_bus": "main"})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/edaniels/golog"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
 	pb "go.viam.com/api/app/packages/v1"
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/pexec"
@@ -20,6 +21,7 @@ import (
 
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/discovery"
+	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/module/modmanager"
 	modif "go.viam.com/rdk/module/modmaninterface"
 	"go.viam.com/rdk/operation"
@@ -46,12 +48,28 @@ const (
 	packageManagerName internalServiceName = "packagemanager"
 )
 
-var _ = robot.LocalRobot(&localRobot{})
+// defaultSafeStopTimeout is how long each actuator is given to reach its safe state during shutdown.
+const defaultSafeStopTimeout = 5 * time.Second
+
+// moduleReloadInterval is how often module executables are checked for changes.
+var moduleReloadInterval = 5 * time.Second
+
+var (
+	_ = robot.LocalRobot(&localRobot{})
+	_ = robot.ResourceGraphProvider(&localRobot{})
+	_ = robot.ResourceHealthWatcher(&localRobot{})
+	_ = robot.ResourceLogLevelSetter(&localRobot{})
+	_ = robot.HardwareDetector(&localRobot{})
+	_ = robot.ReconfigurationPlanner(&localRobot{})
+)
 
 // localRobot satisfies robot.LocalRobot and defers most
 // logic to its manager.
 type localRobot struct {
-	mu             sync.Mutex
+	mu sync.Mutex
+	// reconfigureMu serializes reconfigurations, and keeps plans from reading a config and
+	// resource graph that are halfway through one.
+	reconfigureMu  sync.Mutex
 	manager        *resourceManager
 	config         *config.Config
 	operations     *operation.Manager
@@ -73,6 +91,14 @@ type localRobot struct {
 	triggerConfig              chan bool
 	configTimer                *time.Ticker
 	revealSensitiveConfigDiffs bool
+	safeStopTimeout            time.Duration
+
+	// moduleConfigs are the most recently configured modules, checked for changes periodically.
+	moduleConfigs []config.Module
+
+	watchdog *resourceWatchdog
+
+	logLevels *resourceLogLevels
 }
 
 // webService returns the localRobot's web service. Raises if the service has not been initialized.
@@ -161,6 +187,7 @@ func (r *localRobot) Close(ctx context.Context) error {
 	if err == nil {
 		web.Stop()
 	}
+	r.safeStopAll(ctx)
 	for s, svc := range r.internalServices {
 		if s == webName {
 			continue
@@ -220,6 +247,68 @@ func (r *localRobot) StopAll(ctx context.Context, extra map[resource.Name]map[st
 	return nil
 }
 
+// ResourceGraph returns a snapshot of the robot's resource dependency graph.
+func (r *localRobot) ResourceGraph() robot.ResourceGraph {
+	return r.manager.resourceGraph(r.config)
+}
+
+// safeStopAll cancels all outstanding operations and then brings every local actuator
+// into a defined state before the robot's resources are closed. Resources implementing
+// resource.SafeStoppable are given the chance to park themselves and are stopped if they
+// fail to; all others are stopped. Each of these calls is bounded by the robot's safe stop
+// timeout so that a single unresponsive driver cannot hold up shutdown.
+func (r *localRobot) safeStopAll(ctx context.Context) {
+	for _, op := range r.operations.All() {
+		op.Cancel()
+	}
+
+	timeout := r.safeStopTimeout
+	if timeout <= 0 {
+		timeout = defaultSafeStopTimeout
+	}
+	for _, name := range r.manager.resources.TopologicalSort() {
+		if name.ContainsRemoteNames() {
+			continue
+		}
+		if name.ResourceType != resource.ResourceTypeComponent {
+			continue
+		}
+		res, err := r.manager.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		if ss, ok := utils.UnwrapProxy(res).(resource.SafeStoppable); ok {
+			err := callWithTimeout(ctx, timeout, ss.SafeStop)
+			if err == nil {
+				continue
+			}
+			r.logger.Errorw("failed to bring resource to a safe state before shutdown; stopping it", "resource", name, "error", err)
+		}
+		if err := callWithTimeout(ctx, timeout, func(ctx context.Context) error {
+			return resource.StopResource(ctx, res, nil)
+		}); err != nil {
+			r.logger.Errorw("failed to stop resource before shutdown", "resource", name, "error", err)
+		}
+	}
+}
+
+// callWithTimeout calls fn with a context bounded by the given timeout. The call is
+// abandoned once the context is done, even if fn does not respect it.
+func callWithTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	goutils.PanicCapturingGo(func() {
+		errCh <- fn(ctx)
+	})
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Config returns the config used to construct the robot. Only local resources are returned.
 // This is allowed to be partial or empty.
 func (r *localRobot) Config(ctx context.Context) (*config.Config, error) {
@@ -355,22 +444,23 @@ func (r *localRobot) Status(ctx context.Context, resourceNames []resource.Name)
 	}
 	statuses := make([]robot.Status, 0, len(deduped))
 	for name := range deduped {
+		if name.Subtype == remoteSubtype {
+			status, err := r.manager.remoteConnectionStatus(name.Name)
+			if err != nil {
+				return nil, err
+			}
+			statuses = append(statuses, robot.Status{Name: name, Status: status})
+			continue
+		}
 		resourceStatus, ok := remoteStatuses[name]
 		if !ok {
 			resource, ok := resources[name]
 			if !ok {
 				return nil, utils.NewResourceNotFoundError(name)
 			}
-			// if resource subtype has an associated CreateStatus method, use that
-			// otherwise return an empty status
-			var status interface{} = map[string]interface{}{}
-			var err error
-			subtype := registry.ResourceSubtypeLookup(name.Subtype)
-			if subtype != nil && subtype.Status != nil {
-				status, err = subtype.Status(ctx, resource)
-				if err != nil {
-					return nil, errors.Wrapf(err, "failed to get status from %q", name)
-				}
+			status, err := localResourceStatus(ctx, name, resource)
+			if err != nil {
+				return nil, err
 			}
 			resourceStatus = robot.Status{Name: name, Status: status}
 		}
@@ -394,19 +484,30 @@ func (r *localRobot) updateDefaultServiceNames(cfg *config.Config) *config.Confi
 		}
 	}
 	// default services added if they are not already defined in the config
+	cfg.Services = append(cfg.Services, missingDefaultServices(cfg.Services)...)
+	return cfg
+}
+
+// missingDefaultServices returns the configs of the default services whose subtypes none of
+// the given services have.
+func missingDefaultServices(services []config.Service) []config.Service {
+	seen := make(map[resource.Subtype]bool)
+	for _, val := range services {
+		seen[val.ResourceName().Subtype] = true
+	}
+	var missing []config.Service
 	for _, name := range resource.DefaultServices {
 		if seen[name.Subtype] {
 			continue
 		}
-		svcCfg := config.Service{
+		missing = append(missing, config.Service{
 			Name:      name.Name,
 			Model:     resource.DefaultServiceModel,
 			Namespace: name.Namespace,
 			Type:      name.ResourceSubtype,
-		}
-		cfg.Services = append(cfg.Services, svcCfg)
+		})
 	}
-	return cfg
+	return missing
 }
 
 func newWithResources(
@@ -443,6 +544,8 @@ func newWithResources(
 		triggerConfig:              make(chan bool),
 		configTimer:                nil,
 		revealSensitiveConfigDiffs: rOpts.revealSensitiveConfigDiffs,
+		safeStopTimeout:            rOpts.safeStopTimeout,
+		logLevels:                  newResourceLogLevels(),
 	}
 	var heartbeatWindow time.Duration
 	if cfg.Network.Sessions.HeartbeatWindow == 0 {
@@ -482,6 +585,8 @@ func newWithResources(
 		r.logger.Debug("Using no-op PackageManager when Cloud config is not available")
 		r.packageManager = packages.NewNoopManager()
 	}
+	// packages that do not come from the package service are synced no matter the connection.
+	r.packageManager = packages.NewSourceManager(r.packageManager, cfg.PackagePath, logger)
 
 	// start process manager early
 	if err := r.manager.processManager.Start(ctx); err != nil {
@@ -562,7 +667,9 @@ func newWithResources(
 				}
 				if rr, ok := r.manager.RemoteByName(n); ok {
 					rn := fromRemoteNameToRemoteNodeName(n)
-					r.manager.updateRemoteResourceNames(ctx, rn, rr, r)
+					if r.manager.updateRemoteResourceNames(ctx, rn, rr, r) {
+						r.manager.updateOptionalDependencies(ctx)
+					}
 					r.updateDefaultServices(ctx)
 				}
 			}
@@ -588,11 +695,53 @@ func newWithResources(
 				r.updateDefaultServices(ctx)
 			}
 			if r.manager.updateRemotesResourceNames(ctx, r) {
+				r.manager.updateOptionalDependencies(ctx)
 				r.updateDefaultServices(ctx)
 			}
 		}
 	}, r.activeBackgroundWorkers.Done)
 
+	r.activeBackgroundWorkers.Add(1)
+	// this goroutine restarts modules whose executable changed so that module development
+	// does not require restarting the robot
+	goutils.ManagedGo(func() {
+		ticker := time.NewTicker(moduleReloadInterval)
+		defer ticker.Stop()
+		for {
+			if closeCtx.Err() != nil {
+				return
+			}
+			select {
+			case <-closeCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			r.mu.Lock()
+			mods := r.moduleConfigs
+			r.mu.Unlock()
+			r.reloadModules(closeCtx, mods)
+		}
+	}, r.activeBackgroundWorkers.Done)
+
+	r.watchdog = newResourceWatchdog(r, logger)
+	r.activeBackgroundWorkers.Add(1)
+	// this goroutine checks the health of resources and restarts the ones that stopped working
+	goutils.ManagedGo(func() {
+		ticker := time.NewTicker(watchdogInterval)
+		defer ticker.Stop()
+		for {
+			if closeCtx.Err() != nil {
+				return
+			}
+			select {
+			case <-closeCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			r.watchdog.check(closeCtx)
+		}
+	}, r.activeBackgroundWorkers.Done)
+
 	r.config = &config.Config{}
 
 	r.Reconfigure(ctx, cfg)
@@ -634,7 +783,7 @@ func (r *localRobot) newService(ctx context.Context, config config.Service) (res
 			rName.Subtype, config.Model, validModels)
 	}
 
-	deps, err := r.getDependencies(rName)
+	deps, err := r.getDependencies(rName, config.OptionalDependencies())
 	if err != nil {
 		return nil, err
 	}
@@ -647,13 +796,14 @@ func (r *localRobot) newService(ctx context.Context, config config.Service) (res
 		}
 	}
 	var svc interface{}
+	logger := r.logLevels.logger(r.logger, rName, config.Model)
 	if f.Constructor != nil {
-		svc, err = f.Constructor(ctx, deps, config, r.logger)
+		svc, err = f.Constructor(ctx, deps, config, logger)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		svc, err = f.RobotConstructor(ctx, r, config, r.logger)
+		svc, err = f.RobotConstructor(ctx, r, config, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -667,8 +817,9 @@ func (r *localRobot) newService(ctx context.Context, config config.Service) (res
 
 // getDependencies derives a collection of dependencies from a robot for a given
 // component's name. We don't use the resource manager for this information since
-// it is not be constructed at this point.
-func (r *localRobot) getDependencies(rName resource.Name) (registry.Dependencies, error) {
+// it is not be constructed at this point. Optional dependencies are included if they
+// are built.
+func (r *localRobot) getDependencies(rName resource.Name, optional []string) (registry.Dependencies, error) {
 	deps := make(registry.Dependencies)
 	for _, dep := range r.manager.resources.GetAllParentsOf(rName) {
 		r, err := r.ResourceByName(dep)
@@ -677,6 +828,7 @@ func (r *localRobot) getDependencies(rName resource.Name) (registry.Dependencies
 		}
 		deps[dep] = r
 	}
+	r.manager.addOptionalDependencies(rName, optional, deps)
 
 	return deps, nil
 }
@@ -693,17 +845,18 @@ func (r *localRobot) newResource(ctx context.Context, config config.Component) (
 		return nil, errors.Errorf("unknown component type: %s and/or model: %s", rName.Subtype, config.Model)
 	}
 
-	deps, err := r.getDependencies(rName)
+	deps, err := r.getDependencies(rName, config.OptionalDependencies())
 	if err != nil {
 		return nil, err
 	}
 
 	var newResource interface{}
+	logger := r.logLevels.logger(r.logger, rName, config.Model)
 	if f.Constructor != nil {
-		newResource, err = f.Constructor(ctx, deps, config, r.logger)
+		newResource, err = f.Constructor(ctx, deps, config, logger)
 	} else {
 		r.logger.Warnw("using legacy constructor", "subtype", rName.Subtype, "model", config.Model)
-		newResource, err = f.RobotConstructor(ctx, r, config, r.logger)
+		newResource, err = f.RobotConstructor(ctx, r, config, logger)
 	}
 
 	if err != nil {
@@ -869,10 +1022,14 @@ func (r *localRobot) DiscoverComponents(ctx context.Context, qs []discovery.Quer
 func dialRobotClient(
 	ctx context.Context,
 	config config.Remote,
+	connPool *grpc.SharedConnPool,
 	logger golog.Logger,
 	dialOpts ...rpc.DialOption,
 ) (*client.RobotClient, error) {
 	rOpts := []client.RobotClientOption{client.WithDialOptions(dialOpts...), client.WithRemoteName(config.Name)}
+	if connPool != nil {
+		rOpts = append(rOpts, client.WithSharedConnPool(connPool, remoteConnKey(config)))
+	}
 
 	if config.ConnectionCheckInterval != 0 {
 		rOpts = append(rOpts, client.WithCheckConnectedEvery(config.ConnectionCheckInterval))
@@ -880,6 +1037,12 @@ func dialRobotClient(
 	if config.ReconnectInterval != 0 {
 		rOpts = append(rOpts, client.WithReconnectEvery(config.ReconnectInterval))
 	}
+	if config.ReconnectMaxInterval != 0 {
+		rOpts = append(rOpts, client.WithReconnectBackoff(config.ReconnectMaxInterval))
+	}
+	if config.LazyConnect {
+		rOpts = append(rOpts, client.WithLazyConnect())
+	}
 
 	robotClient, err := client.New(
 		ctx,
@@ -897,6 +1060,8 @@ func dialRobotClient(
 // a best effort to remove no longer in use parts, but if it fails to do so, they could
 // possibly leak resources.
 func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config) {
+	r.reconfigureMu.Lock()
+	defer r.reconfigureMu.Unlock()
 	var allErrs error
 
 	newConfig = r.updateDefaultServiceNames(newConfig)
@@ -905,6 +1070,16 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 		r.logger.Errorw("error diffing the configs", "error", err)
 		return
 	}
+
+	// Log levels apply to running resources, so they never require rebuilding any.
+	r.logLevels.setConfig(newConfig.Log)
+
+	// Modules are not part of the resource diff, so pick up version changes here.
+	r.reloadModules(ctx, newConfig.Modules)
+	r.mu.Lock()
+	r.moduleConfigs = newConfig.Modules
+	r.mu.Unlock()
+
 	if diff.ResourcesEqual {
 		return
 	}
@@ -915,6 +1090,7 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 
 	// Sync Packages before reconfiguring rest of robot.
 	// TODO(RSDK-1849): Make this non-blocking so other resources that do not require packages can run before package sync finishes.
+	changed := changedPackages(r.config.Packages, newConfig.Packages)
 	err = r.packageManager.Sync(ctx, newConfig.Packages)
 	if err != nil {
 		allErrs = multierr.Combine(allErrs, err)
@@ -963,6 +1139,7 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 	allErrs = multierr.Combine(allErrs, filtered.Close(ctx, r))
 	// Third we attempt to complete the config (see function for details)
 	r.manager.completeConfig(ctx, r)
+	allErrs = multierr.Combine(allErrs, r.rollbackFailedPackages(ctx, changed))
 	r.updateDefaultServices(ctx)
 
 	// cleanup unused packages after all old resources have been closed above. This ensures
@@ -974,6 +1151,33 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 	}
 }
 
+// SetResourceLogLevel overrides the log level of a resource until it is called again for the resource.
+func (r *localRobot) SetResourceLogLevel(name resource.Name, level *zapcore.Level) {
+	r.logLevels.setOverride(name, level)
+}
+
+// SubscribeResourceHealth returns a channel of events from the robot's resource watchdog.
+func (r *localRobot) SubscribeResourceHealth(buffer int) (<-chan robot.ResourceHealthEvent, func()) {
+	return r.watchdog.subscribe(buffer)
+}
+
+// reloadModules restarts any of the given modules that changed since they were started.
+func (r *localRobot) reloadModules(ctx context.Context, mods []config.Module) {
+	if r.modules == nil {
+		return
+	}
+	for _, mod := range mods {
+		reloaded, err := r.modules.Reload(ctx, mod)
+		if err != nil {
+			r.logger.Errorw("error reloading module", "module", mod.Name, "error", err)
+			continue
+		}
+		if reloaded {
+			r.logger.Infow("reloaded module", "module", mod.Name)
+		}
+	}
+}
+
 // checkMaxInstance checks to see if the local robot has reached the maximum number of a specific service type that are local.
 func (r *localRobot) checkMaxInstance(subtype resource.Subtype, max int) error {
 	maxInstance := 0
@@ -36,6 +36,7 @@ import (
 	"go.viam.com/rdk/components/audioinput"
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/board"
+	fakeboard "go.viam.com/rdk/components/board/fake"
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/gripper"
 	"go.viam.com/rdk/components/movementsensor"
@@ -47,6 +48,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/framesystem"
 	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/robot/packages"
@@ -101,6 +103,128 @@ func TestConfigFake(t *testing.T) {
 	test.That(t, r.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestResourceGraph(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	cfg := &config.Config{
+		Components: []config.Component{
+			{
+				Name:                "board1",
+				Namespace:           resource.ResourceNamespaceRDK,
+				Type:                board.SubtypeName,
+				Model:               fakeModel,
+				ConvertedAttributes: &fakeboard.Config{},
+			},
+			{
+				Name:      "arm1",
+				Namespace: resource.ResourceNamespaceRDK,
+				Type:      arm.SubtypeName,
+				Model:     fakeModel,
+				DependsOn: []string{"board1"},
+				ConvertedAttributes: &fake.AttrConfig{
+					ModelFilePath: "../../components/arm/fake/fake_model.json",
+				},
+			},
+			{
+				Name:      "gripper1",
+				Namespace: resource.ResourceNamespaceRDK,
+				Type:      gripper.SubtypeName,
+				Model:     resource.NewDefaultModel("does-not-exist"),
+			},
+		},
+	}
+	r, err := robotimpl.New(context.Background(), cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	provider, ok := r.(robot.ResourceGraphProvider)
+	test.That(t, ok, test.ShouldBeTrue)
+	graph := provider.ResourceGraph()
+
+	nodes := map[resource.Name]robot.ResourceGraphNode{}
+	for _, n := range graph.Nodes {
+		nodes[n.Name] = n
+	}
+	armNode, ok := nodes[arm.Named("arm1")]
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, armNode.Source, test.ShouldEqual, "component")
+	test.That(t, armNode.Model, test.ShouldEqual, fakeModel.String())
+	test.That(t, armNode.DependsOn, test.ShouldResemble, []string{"board1"})
+	test.That(t, armNode.State, test.ShouldEqual, robot.ResourceStateReady)
+
+	gripperNode, ok := nodes[gripper.Named("gripper1")]
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, gripperNode.State, test.ShouldEqual, robot.ResourceStateFailed)
+	test.That(t, gripperNode.Error, test.ShouldNotBeEmpty)
+
+	test.That(t, graph.Edges, test.ShouldContain, robot.ResourceGraphEdge{From: arm.Named("arm1"), To: board.Named("board1")})
+	test.That(t, graph.DOT(), test.ShouldContainSubstring, `"rdk:component:arm/arm1" -> "rdk:component:board/board1";`)
+
+	// the graph is served over the robot introspection service
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(context.Background(), options), test.ShouldBeNil)
+	rc, err := client.New(context.Background(), addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rc.Close(context.Background()), test.ShouldBeNil)
+	}()
+	remoteGraph, err := rc.ResourceGraph(context.Background())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, remoteGraph.Nodes, test.ShouldHaveLength, len(graph.Nodes))
+	for _, n := range remoteGraph.Nodes {
+		test.That(t, n.State, test.ShouldEqual, nodes[n.Name].State)
+		test.That(t, n.Error, test.ShouldEqual, nodes[n.Name].Error)
+	}
+	test.That(t, remoteGraph.Edges, test.ShouldResemble, graph.Edges)
+}
+
+func TestFrameSystemSnapshot(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	cfg, err := config.Read(context.Background(), "data/fake.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+	r, err := robotimpl.New(context.Background(), cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(context.Background(), options), test.ShouldBeNil)
+	rc, err := client.New(context.Background(), addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rc.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	local, err := framesystem.RobotSnapshot(context.Background(), r, nil)
+	test.That(t, err, test.ShouldBeNil)
+	snapshots, err := rc.FrameSystemSnapshot(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, snapshots, test.ShouldHaveLength, len(local))
+	for i, snapshot := range snapshots {
+		test.That(t, snapshot.Name, test.ShouldEqual, local[i].Name)
+		test.That(t, snapshot.Parent, test.ShouldEqual, local[i].Parent)
+		test.That(t, spatialmath.PoseAlmostEqual(snapshot.Pose, local[i].Pose), test.ShouldBeTrue)
+		test.That(t, snapshot.Geometries, test.ShouldHaveLength, len(local[i].Geometries))
+	}
+
+	// supplemental transforms are posed along with the frames of the robot
+	marker := referenceframe.NewLinkInFrame("cameraOver", spatialmath.NewPoseFromPoint(r3.Vector{Z: 10}), "marker", nil)
+	snapshots, err = rc.FrameSystemSnapshot(context.Background(), []*referenceframe.LinkInFrame{marker})
+	test.That(t, err, test.ShouldBeNil)
+	// the link adds both the marker frame and its origin
+	test.That(t, snapshots, test.ShouldHaveLength, len(local)+2)
+	byName := map[string]framesystem.FrameSnapshot{}
+	for _, snapshot := range snapshots {
+		byName[snapshot.Name] = snapshot
+	}
+	test.That(t, byName["marker_origin"].Parent, test.ShouldEqual, "cameraOver")
+	// the camera is only rotated about Z, so the marker sits directly above it
+	expected := byName["cameraOver"].Pose.Point().Add(r3.Vector{Z: 10})
+	test.That(t, spatialmath.R3VectorAlmostEqual(byName["marker"].Pose.Point(), expected, 1e-8), test.ShouldBeTrue)
+}
+
 func TestConfigRemote(t *testing.T) {
 	logger := golog.NewTestLogger(t)
 	cfg, err := config.Read(context.Background(), "data/fake.json", logger)
@@ -847,6 +971,104 @@ func TestStopAll(t *testing.T) {
 	test.That(t, stopAllErr, test.ShouldBeNil)
 }
 
+type safeStopArm struct {
+	dummyArm
+	safeStopCount int
+	block         bool
+}
+
+func (sa *safeStopArm) SafeStop(ctx context.Context) error {
+	sa.safeStopCount++
+	if sa.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestCloseSafeStop(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+
+	modelName := resource.NewDefaultModel(resource.ModelName(utils.RandomAlphaString(8)))
+	parkingArm := &safeStopArm{}
+	stuckArm := &safeStopArm{block: true}
+	plainArm := &dummyArm{}
+	registry.RegisterComponent(
+		arm.Subtype,
+		modelName,
+		registry.Component{Constructor: func(
+			ctx context.Context,
+			deps registry.Dependencies,
+			config config.Component,
+			logger golog.Logger,
+		) (interface{}, error) {
+			switch config.Name {
+			case "arm1":
+				return parkingArm, nil
+			case "arm2":
+				return stuckArm, nil
+			default:
+				return plainArm, nil
+			}
+		}})
+
+	armConfig := fmt.Sprintf(`{
+		"components": [
+			{
+				"model": "%[1]s",
+				"name": "arm1",
+				"type": "arm"
+			},
+			{
+				"model": "%[1]s",
+				"name": "arm2",
+				"type": "arm"
+			},
+			{
+				"model": "%[1]s",
+				"name": "arm3",
+				"type": "arm"
+			},
+			{
+				"model": "fake",
+				"name": "arm4",
+				"type": "arm",
+				"attributes": {
+					"model-path": "../../components/arm/fake/fake_model.json"
+				}
+			}
+		]
+	}
+	`, modelName.String())
+	cfg, err := config.FromReader(context.Background(), "", strings.NewReader(armConfig), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	ctx := context.Background()
+	r, err := robotimpl.New(ctx, cfg, logger, robotimpl.WithSafeStopTimeout(50*time.Millisecond))
+	test.That(t, err, test.ShouldBeNil)
+
+	fakeArm, err := arm.FromRobot(r, "arm4")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fakeArm.MoveToJointPositions(ctx, &armpb.JointPositions{Values: []float64{10}}, nil), test.ShouldBeNil)
+
+	start := time.Now()
+	test.That(t, r.Close(ctx), test.ShouldBeNil)
+	// only the stuck arm takes up its timeout before it is stopped instead
+	elapsed := time.Since(start)
+	test.That(t, elapsed, test.ShouldBeGreaterThanOrEqualTo, 50*time.Millisecond)
+	test.That(t, elapsed, test.ShouldBeLessThan, 500*time.Millisecond)
+
+	test.That(t, parkingArm.safeStopCount, test.ShouldEqual, 1)
+	test.That(t, parkingArm.stopCount, test.ShouldEqual, 0)
+	test.That(t, stuckArm.stopCount, test.ShouldEqual, 1)
+	test.That(t, plainArm.stopCount, test.ShouldEqual, 1)
+
+	// the fake arm parks itself
+	joints, err := fakeArm.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints.Values, test.ShouldResemble, []float64{0})
+}
+
 type dummyBoard struct {
 	board.LocalBoard
 	closeCount int
@@ -0,0 +1,108 @@
+package robotimpl
+
+import (
+	"context"
+
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// optionalDependencies are the optional dependencies of a resource and which of them it has
+// been given, either when it was constructed or bound later, by the resource they resolved to.
+type optionalDependencies struct {
+	names []string
+	bound map[string]resource.Name
+}
+
+// optionalDependency returns a built local resource by name. Optional dependencies are not part
+// of the resource graph, so ones that are missing or failed to build are simply not returned.
+func (manager *resourceManager) optionalDependency(dep string) (resource.Name, interface{}, bool) {
+	name, ok := manager.resources.FindNodeByName(dep)
+	if !ok {
+		return resource.Name{}, nil, false
+	}
+	// resources that are not built yet are placeholders, which may not have failed
+	res, ok := manager.resources.Node(*name)
+	if !ok || res == nil {
+		return resource.Name{}, nil, false
+	}
+	if _, ok := res.(*resourcePlaceholder); ok {
+		return resource.Name{}, nil, false
+	}
+	return *name, res, true
+}
+
+// addOptionalDependencies adds the optional dependencies of a resource that are built to deps
+// and records them as given to the resource.
+func (manager *resourceManager) addOptionalDependencies(rName resource.Name, optional []string, deps registry.Dependencies) {
+	if len(optional) == 0 {
+		delete(manager.optional, rName)
+		return
+	}
+	opt := &optionalDependencies{names: optional, bound: map[string]resource.Name{}}
+	for _, dep := range optional {
+		name, res, ok := manager.optionalDependency(dep)
+		if !ok {
+			continue
+		}
+		deps[name] = res
+		opt.bound[dep] = name
+	}
+	manager.optional[rName] = opt
+}
+
+// bindOptionalDependencies gives built resources the optional dependencies that were built
+// since they were constructed, and takes back those that were removed since from resources
+// that can unbind them. Resources that cannot bind dependencies get them the next time they
+// are constructed. It runs whenever resources are added or removed, with the config lock held.
+func (manager *resourceManager) bindOptionalDependencies(ctx context.Context) {
+	for rName, opt := range manager.optional {
+		iface, ok := manager.resources.Node(rName)
+		if !ok {
+			delete(manager.optional, rName)
+			continue
+		}
+		if _, ok := iface.(*resourcePlaceholder); ok || iface == nil {
+			continue
+		}
+		binder, ok := rutils.UnwrapProxy(iface).(registry.OptionalDependencyBinder)
+		if !ok {
+			continue
+		}
+		for _, dep := range opt.names {
+			name, res, ok := manager.optionalDependency(dep)
+			if boundName, bound := opt.bound[dep]; bound {
+				if ok && name == boundName {
+					continue
+				}
+				// the dependency was removed, so it is bound again once built again
+				delete(opt.bound, dep)
+				if unbinder, canUnbind := binder.(registry.OptionalDependencyUnbinder); canUnbind {
+					if err := unbinder.UnbindOptionalDependency(ctx, boundName); err != nil {
+						manager.logger.Errorw("error unbinding optional dependency", "resource", rName, "dependency", boundName, "error", err)
+					} else {
+						manager.logger.Debugw("unbound optional dependency", "resource", rName, "dependency", boundName)
+					}
+				}
+			}
+			if !ok {
+				continue
+			}
+			if err := binder.BindOptionalDependency(ctx, name, res); err != nil {
+				manager.logger.Errorw("error binding optional dependency", "resource", rName, "dependency", name, "error", err)
+				continue
+			}
+			manager.logger.Debugw("bound optional dependency", "resource", rName, "dependency", name)
+			opt.bound[dep] = name
+		}
+	}
+}
+
+// updateOptionalDependencies binds and unbinds optional dependencies after resources were added
+// or removed outside of completeConfig, which does so itself.
+func (manager *resourceManager) updateOptionalDependencies(ctx context.Context) {
+	manager.configLock.Lock()
+	defer manager.configLock.Unlock()
+	manager.bindOptionalDependencies(ctx)
+}
@@ -0,0 +1,73 @@
+package robotimpl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+)
+
+type bindingResource struct {
+	bound map[resource.Name]interface{}
+}
+
+func (r *bindingResource) BindOptionalDependency(ctx context.Context, name resource.Name, dep interface{}) error {
+	r.bound[name] = dep
+	return nil
+}
+
+func (r *bindingResource) UnbindOptionalDependency(ctx context.Context, name resource.Name) error {
+	delete(r.bound, name)
+	return nil
+}
+
+func TestOptionalDependencies(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	manager := newResourceManager(resourceManagerOptions{}, logger)
+
+	arm1 := arm.Named("arm1")
+	cam1 := camera.Named("cam1")
+	manager.resources.AddNode(arm1, "arm")
+	manager.resources.AddNode(cam1, &resourcePlaceholder{config: "cam"})
+
+	// only optional dependencies that are built are given at construction
+	res := &bindingResource{bound: map[resource.Name]interface{}{}}
+	rName := resource.NameFromSubtype(arm.Subtype, "user")
+	deps := registry.Dependencies{}
+	manager.addOptionalDependencies(rName, []string{"arm1", "cam1", "missing"}, deps)
+	test.That(t, deps, test.ShouldResemble, registry.Dependencies{arm1: "arm"})
+	manager.resources.AddNode(rName, res)
+
+	manager.bindOptionalDependencies(context.Background())
+	test.That(t, res.bound, test.ShouldBeEmpty)
+
+	// the others are bound once they are built
+	manager.resources.AddNode(cam1, "cam")
+	manager.bindOptionalDependencies(context.Background())
+	test.That(t, res.bound, test.ShouldResemble, map[resource.Name]interface{}{cam1: "cam"})
+
+	manager.bindOptionalDependencies(context.Background())
+	test.That(t, res.bound, test.ShouldHaveLength, 1)
+
+	// removed dependencies are unbound
+	manager.resources.Remove(cam1)
+	manager.resources.Remove(arm1)
+	manager.bindOptionalDependencies(context.Background())
+	test.That(t, res.bound, test.ShouldBeEmpty)
+
+	// and bound again once built again
+	manager.resources.AddNode(arm1, "arm")
+	manager.bindOptionalDependencies(context.Background())
+	test.That(t, res.bound, test.ShouldResemble, map[resource.Name]interface{}{arm1: "arm"})
+
+	// removed resources are no longer tracked
+	manager.resources.Remove(rName)
+	manager.bindOptionalDependencies(context.Background())
+	test.That(t, manager.optional, test.ShouldBeEmpty)
+}
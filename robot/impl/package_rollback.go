@@ -0,0 +1,100 @@
+package robotimpl
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/robot/packages"
+)
+
+// changedPackages returns the names of the packages whose contents differ between two configs.
+// Packages that were added are not included since there is nothing to roll them back to.
+func changedPackages(oldPackages, newPackages []config.PackageConfig) map[string]bool {
+	old := make(map[string]config.PackageConfig, len(oldPackages))
+	for _, p := range oldPackages {
+		old[p.Name] = p
+	}
+	changed := map[string]bool{}
+	for _, p := range newPackages {
+		if o, ok := old[p.Name]; ok && o != p {
+			changed[p.Name] = true
+		}
+	}
+	return changed
+}
+
+// rollbackFailedPackages rolls back the changed packages referred to by resources that failed to
+// build, and then tries to build those resources again with the previous package versions.
+func (r *localRobot) rollbackFailedPackages(ctx context.Context, changed map[string]bool) error {
+	if len(changed) == 0 {
+		return nil
+	}
+
+	var allErrs error
+	var rolledBack bool
+	for name := range r.manager.packagesOfFailedResources() {
+		if !changed[name] {
+			continue
+		}
+		if err := r.packageManager.Rollback(ctx, packages.PackageName(name)); err != nil {
+			if !errors.Is(err, packages.ErrNoPreviousVersion) {
+				allErrs = multierr.Combine(allErrs, errors.Wrapf(err, "failed rolling back package %s", name))
+			}
+			continue
+		}
+		r.logger.Warnw("rolled back package since resources using it failed to build", "package", name)
+		rolledBack = true
+	}
+	if rolledBack {
+		r.manager.completeConfig(ctx, r)
+	}
+	return allErrs
+}
+
+// packagesOfFailedResources returns the names of the packages referred to by the attributes of
+// resources that failed to build.
+func (manager *resourceManager) packagesOfFailedResources() map[string]bool {
+	refs := map[string]bool{}
+	for _, name := range manager.resources.Names() {
+		iface, ok := manager.resources.Node(name)
+		if !ok {
+			continue
+		}
+		wrap, ok := iface.(*resourcePlaceholder)
+		if !ok || wrap.err == nil {
+			continue
+		}
+		switch c := wrap.config.(type) {
+		case config.Component:
+			addPackageReferences(refs, c.Attributes)
+		case config.Service:
+			addPackageReferences(refs, c.Attributes)
+		}
+	}
+	return refs
+}
+
+// addPackageReferences adds the packages referred to anywhere in an attribute value to refs.
+func addPackageReferences(refs map[string]bool, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if ref := config.GetPackageReference(v); ref != nil {
+			refs[ref.Package] = true
+		}
+	case config.AttributeMap:
+		for _, item := range v {
+			addPackageReferences(refs, item)
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			addPackageReferences(refs, item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			addPackageReferences(refs, item)
+		}
+	}
+}
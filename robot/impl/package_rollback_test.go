@@ -0,0 +1,35 @@
+package robotimpl
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+)
+
+func TestChangedPackages(t *testing.T) {
+	oldPackages := []config.PackageConfig{
+		{Name: "model", Package: "org1/model", Version: "v1"},
+		{Name: "same", Package: "org1/same", Version: "v1"},
+		{Name: "removed", Package: "org1/removed", Version: "v1"},
+	}
+	newPackages := []config.PackageConfig{
+		{Name: "model", Package: "org1/model", Version: "v2"},
+		{Name: "same", Package: "org1/same", Version: "v1"},
+		{Name: "added", Package: "org1/added", Version: "v1"},
+	}
+	test.That(t, changedPackages(oldPackages, newPackages), test.ShouldResemble, map[string]bool{"model": true})
+}
+
+func TestAddPackageReferences(t *testing.T) {
+	refs := map[string]bool{}
+	addPackageReferences(refs, config.AttributeMap{
+		"model_path": "${packages.model}/model.tflite",
+		"labels":     []interface{}{"${packages.labels}/labels.txt", "plain"},
+		"nested":     map[string]interface{}{"path": "${packages.nested}"},
+		"other":      "/opt/model.tflite",
+		"count":      3,
+	})
+	test.That(t, refs, test.ShouldResemble, map[string]bool{"model": true, "labels": true, "nested": true})
+}
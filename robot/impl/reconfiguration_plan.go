@@ -0,0 +1,173 @@
+package robotimpl
+
+import (
+	"context"
+	"fmt"
+
+	"go.viam.com/utils/pexec"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// PlanReconfiguration returns what Reconfigure would do with the given config, without doing it.
+// It follows the same rules Reconfigure does: removed resources are closed along with everything
+// depending on them, modified components are updated or rebuilt as their models decide and the
+// resources depending on them are rebuilt, and modified services are updated behind their proxies.
+// The given config is processed as one sent to the robot by a client before it is compared, so that
+// nothing on the robot, such as environment variables, secrets or fragment files, is read into it.
+func (r *localRobot) PlanReconfiguration(ctx context.Context, newConfig *config.Config) (*robot.ReconfigurationPlan, error) {
+	candidate, err := config.ProcessConfigFromRequest(newConfig)
+	if err != nil {
+		return nil, err
+	}
+	candidate.Services = append(candidate.Services, missingDefaultServices(candidate.Services)...)
+
+	r.reconfigureMu.Lock()
+	defer r.reconfigureMu.Unlock()
+	r.manager.configLock.Lock()
+	defer r.manager.configLock.Unlock()
+
+	diff, err := config.DiffConfigs(*r.config, *candidate, false)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &robot.ReconfigurationPlan{Changes: []robot.ReconfigurationChange{}, NetworkChanged: !diff.NetworkEqual}
+	planned := map[string]bool{}
+	add := func(name, kind string, action robot.ReconfigurationAction, reason string) {
+		if planned[kind+" "+name] {
+			return
+		}
+		planned[kind+" "+name] = true
+		plan.Changes = append(plan.Changes, robot.ReconfigurationChange{Name: name, Kind: kind, Action: action, Reason: reason})
+	}
+	dependents := func(rName resource.Name, action robot.ReconfigurationAction, reason string) {
+		for _, dep := range r.manager.dependentsOf(rName) {
+			add(dep.String(), resourceKind(dep), action, fmt.Sprintf(reason, rName))
+		}
+	}
+
+	for _, c := range diff.Removed.Components {
+		add(c.ResourceName().String(), "component", robot.ReconfigurationRemove, "removed from the config")
+		dependents(c.ResourceName(), robot.ReconfigurationRemove, "depends on %s, which is removed")
+	}
+	for _, s := range diff.Removed.Services {
+		add(s.ResourceName().String(), "service", robot.ReconfigurationRemove, "removed from the config")
+		dependents(s.ResourceName(), robot.ReconfigurationRemove, "depends on %s, which is removed")
+	}
+	for _, rc := range diff.Removed.Remotes {
+		add(rc.Name, "remote", robot.ReconfigurationRemove, "removed from the config")
+		dependents(fromRemoteNameToRemoteNodeName(rc.Name), robot.ReconfigurationRemove, "depends on %s, which is removed")
+	}
+	for _, p := range diff.Removed.Processes {
+		add(p.ID, "process", robot.ReconfigurationRemove, "removed from the config")
+	}
+	for _, p := range diff.Removed.Packages {
+		add(p.Name, "package", robot.ReconfigurationRemove, "removed from the config")
+	}
+
+	for _, c := range diff.Added.Components {
+		add(c.ResourceName().String(), "component", robot.ReconfigurationAdd, "added to the config")
+	}
+	for _, c := range diff.Modified.Components {
+		action, reason, rebuildsDependents := r.componentReconfiguration(c)
+		add(c.ResourceName().String(), "component", action, reason)
+		if rebuildsDependents {
+			dependents(c.ResourceName(), robot.ReconfigurationRebuild, "depends on %s, which is rebuilt")
+		}
+	}
+	for _, s := range diff.Added.Services {
+		add(s.ResourceName().String(), "service", robot.ReconfigurationAdd, "added to the config")
+	}
+	for _, s := range diff.Modified.Services {
+		if r.ModuleManager().Provides(config.ServiceConfigToShared(s)) {
+			add(s.ResourceName().String(), "service", robot.ReconfigurationUpdate, reasonModule)
+			continue
+		}
+		add(s.ResourceName().String(), "service", robot.ReconfigurationUpdate, reasonReconfigure)
+	}
+	for _, rc := range diff.Added.Remotes {
+		add(rc.Name, "remote", robot.ReconfigurationAdd, "added to the config")
+	}
+	for _, rc := range diff.Modified.Remotes {
+		add(rc.Name, "remote", robot.ReconfigurationRebuild, "the connection to the remote is made again")
+	}
+	addProcesses := func(processes []pexec.ProcessConfig, action robot.ReconfigurationAction, reason string) {
+		for _, p := range processes {
+			add(p.ID, "process", action, reason)
+		}
+	}
+	addProcesses(diff.Added.Processes, robot.ReconfigurationAdd, "added to the config")
+	addProcesses(diff.Modified.Processes, robot.ReconfigurationRebuild, "the process is stopped and started again")
+	for _, p := range diff.Added.Packages {
+		add(p.Name, "package", robot.ReconfigurationAdd, "added to the config")
+	}
+	for _, p := range diff.Modified.Packages {
+		add(p.Name, "package", robot.ReconfigurationRebuild, "the new version is downloaded")
+	}
+	return plan, nil
+}
+
+// Reasons shared by several changes of a plan.
+const (
+	reasonModule      = "the module providing it reconfigures it"
+	reasonReconfigure = "a new instance is built and swapped in behind its existing proxies"
+)
+
+// componentReconfiguration decides how a modified component would be reconfigured, like
+// processComponent does, and whether the resources depending on it would be rebuilt.
+func (r *localRobot) componentReconfiguration(c config.Component) (robot.ReconfigurationAction, string, bool) {
+	var old interface{}
+	if node, ok := r.manager.resources.Node(c.ResourceName()); ok {
+		old = node
+		if wrap, ok := node.(*resourcePlaceholder); ok {
+			old = wrap.real
+		}
+	}
+	if old == nil {
+		return robot.ReconfigurationRebuild, "it is not running, so it is built from the new config", false
+	}
+	if r.ModuleManager().Provides(c) {
+		return robot.ReconfigurationUpdate, reasonModule, false
+	}
+	updater, ok := old.(config.ComponentUpdate)
+	if !ok {
+		return robot.ReconfigurationRebuild, "its model cannot be updated in place", true
+	}
+	switch updater.UpdateAction(&c) {
+	case config.None:
+		return robot.ReconfigurationUpdate, "its model takes the new config as is", false
+	case config.Reconfigure:
+		return robot.ReconfigurationUpdate, reasonReconfigure, true
+	default:
+		return robot.ReconfigurationRebuild, "its model requires rebuilding for the new config", true
+	}
+}
+
+// dependentsOf returns the local resources that depend on a resource, directly or not.
+func (manager *resourceManager) dependentsOf(rName resource.Name) []resource.Name {
+	if _, ok := manager.resources.Node(rName); !ok {
+		return nil
+	}
+	sg, err := manager.resources.SubGraphFrom(rName)
+	if err != nil {
+		return nil
+	}
+	var dependents []resource.Name
+	for _, name := range sg.TopologicalSort() {
+		if name == rName || name.ContainsRemoteNames() || name.Subtype == remoteSubtype {
+			continue
+		}
+		dependents = append(dependents, name)
+	}
+	return dependents
+}
+
+func resourceKind(name resource.Name) string {
+	if name.ResourceType == resource.ResourceTypeService {
+		return "service"
+	}
+	return "component"
+}
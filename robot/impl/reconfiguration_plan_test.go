@@ -0,0 +1,101 @@
+package robotimpl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	robotimpl "go.viam.com/rdk/robot/impl"
+)
+
+func TestPlanReconfiguration(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	boardConf := config.Component{
+		Name:      "board1",
+		Namespace: resource.ResourceNamespaceRDK,
+		Type:      board.SubtypeName,
+		Model:     fakeModel,
+	}
+	armConf := config.Component{
+		Name:      "arm1",
+		Namespace: resource.ResourceNamespaceRDK,
+		Type:      arm.SubtypeName,
+		Model:     fakeModel,
+		DependsOn: []string{"board1"},
+	}
+	baseConf := config.Component{
+		Name:      "base1",
+		Namespace: resource.ResourceNamespaceRDK,
+		Type:      base.SubtypeName,
+		Model:     fakeModel,
+	}
+	// robots run on processed configs, which is what plans compare candidates against
+	processed, err := config.ProcessConfigFromSource(&config.Config{
+		Components: []config.Component{boardConf, armConf, baseConf},
+	}, false)
+	test.That(t, err, test.ShouldBeNil)
+	r, err := robotimpl.New(context.Background(), processed, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	planner, ok := r.(robot.ReconfigurationPlanner)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	plan, err := planner.PlanReconfiguration(context.Background(), &config.Config{
+		Components: []config.Component{boardConf, armConf, baseConf},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, plan.Changes, test.ShouldBeEmpty)
+	test.That(t, plan.NetworkChanged, test.ShouldBeFalse)
+
+	modifiedBoard := boardConf
+	modifiedBoard.Attributes = config.AttributeMap{"analogs": []interface{}{}}
+	cameraConf := config.Component{
+		Name:      "camera1",
+		Namespace: resource.ResourceNamespaceRDK,
+		Type:      camera.SubtypeName,
+		Model:     fakeModel,
+	}
+	plan, err = planner.PlanReconfiguration(context.Background(), &config.Config{
+		Components: []config.Component{modifiedBoard, armConf, cameraConf},
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	changes := map[string]robot.ReconfigurationChange{}
+	for _, c := range plan.Changes {
+		changes[c.Name] = c
+	}
+	test.That(t, changes, test.ShouldHaveLength, 4)
+	test.That(t, plan.Changes[0].Name, test.ShouldEqual, base.Named("base1").String())
+	test.That(t, changes[base.Named("base1").String()].Action, test.ShouldEqual, robot.ReconfigurationRemove)
+	test.That(t, changes[camera.Named("camera1").String()].Action, test.ShouldEqual, robot.ReconfigurationAdd)
+	test.That(t, changes[board.Named("board1").String()].Action, test.ShouldEqual, robot.ReconfigurationRebuild)
+	armChange := changes[arm.Named("arm1").String()]
+	test.That(t, armChange.Kind, test.ShouldEqual, "component")
+	test.That(t, armChange.Action, test.ShouldEqual, robot.ReconfigurationRebuild)
+	test.That(t, armChange.Reason, test.ShouldContainSubstring, "board1")
+
+	_, err = planner.PlanReconfiguration(context.Background(), &config.Config{
+		Components: []config.Component{{Name: "bad name!", Type: board.SubtypeName, Model: fakeModel}},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// planning does not reconfigure
+	_, err = arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = base.FromRobot(r, "base1")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = camera.FromRobot(r, "camera1")
+	test.That(t, err, test.ShouldNotBeNil)
+}
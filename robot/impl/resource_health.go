@@ -0,0 +1,77 @@
+package robotimpl
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	vprotoutils "go.viam.com/utils/protoutils"
+
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// localResourceStatus builds the status of a local resource the same way for every subtype: the
+// status its subtype registers, if any, with the health of resources that report it under the
+// "health" key. A resource failing to report its health is reported as being in an unknown
+// state with that error rather than failing the status of every resource.
+func localResourceStatus(ctx context.Context, name resource.Name, res interface{}) (interface{}, error) {
+	var status interface{} = map[string]interface{}{}
+	subtype := registry.ResourceSubtypeLookup(name.Subtype)
+	if subtype != nil && subtype.Status != nil {
+		var err error
+		status, err = subtype.Status(ctx, res)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get status from %q", name)
+		}
+	}
+
+	reporter, ok := utils.UnwrapProxy(res).(resource.HealthReporter)
+	if !ok {
+		return status, nil
+	}
+	health, err := reporter.Health(ctx)
+	if err != nil {
+		health = resource.Health{State: resource.HealthStateUnknown, LastError: err}
+	}
+	statusMap, err := vprotoutils.InterfaceToMap(status)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add health to the status of %q", name)
+	}
+	if statusMap == nil {
+		statusMap = map[string]interface{}{}
+	}
+	statusMap["health"] = health.ToMap()
+	return statusMap, nil
+}
+
+// healthReporterChecker checks the health of a resource by the state it reports.
+type healthReporterChecker struct {
+	reporter resource.HealthReporter
+}
+
+func (c healthReporterChecker) CheckHealth(ctx context.Context) error {
+	health, err := c.reporter.Health(ctx)
+	if err != nil {
+		return err
+	}
+	if health.State != resource.HealthStateFailed {
+		return nil
+	}
+	if health.LastError != nil {
+		return health.LastError
+	}
+	return errors.New("resource reports that it failed")
+}
+
+// healthCheckerOf returns how to check the health of a resource, if it can be checked.
+func healthCheckerOf(res interface{}) (resource.HealthChecker, bool) {
+	res = utils.UnwrapProxy(res)
+	if checker, ok := res.(resource.HealthChecker); ok {
+		return checker, true
+	}
+	if reporter, ok := res.(resource.HealthReporter); ok {
+		return healthReporterChecker{reporter}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,52 @@
+package robotimpl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+type reportingResource struct {
+	health resource.Health
+	err    error
+}
+
+func (r *reportingResource) Health(ctx context.Context) (resource.Health, error) {
+	return r.health, r.err
+}
+
+func TestLocalResourceStatus(t *testing.T) {
+	ctx := context.Background()
+	name := resource.NameFromSubtype(resource.NewSubtype("acme", resource.ResourceTypeComponent, "status_test"), "res1")
+	status, err := localResourceStatus(ctx, name, struct{}{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status, test.ShouldResemble, map[string]interface{}{})
+
+	res := &reportingResource{health: resource.Health{State: resource.HealthStateDegraded, LastError: errors.New("stalled")}}
+	status, err = localResourceStatus(ctx, name, res)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status, test.ShouldResemble, map[string]interface{}{
+		"health": map[string]interface{}{"state": "degraded", "last_error": "stalled"},
+	})
+
+	// failing to report health is part of the status of the resource
+	failing := &reportingResource{err: errors.New("device unplugged")}
+	status, err = localResourceStatus(ctx, name, failing)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, status, test.ShouldResemble, map[string]interface{}{
+		"health": map[string]interface{}{"state": "unknown", "last_error": "device unplugged"},
+	})
+
+	checker, ok := healthCheckerOf(res)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, checker.CheckHealth(ctx), test.ShouldBeNil)
+	res.health.State = resource.HealthStateFailed
+	test.That(t, checker.CheckHealth(ctx), test.ShouldBeError, errors.New("stalled"))
+
+	_, ok = healthCheckerOf(struct{}{})
+	test.That(t, ok, test.ShouldBeFalse)
+}
@@ -0,0 +1,149 @@
+package robotimpl
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/edaniels/golog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+)
+
+// resourceLogLevels decides the log level of each resource from the log config and runtime
+// overrides, and keeps the loggers resources were constructed with up to date as those change.
+// A nil resourceLogLevels leaves every resource at the level of the robot's logger.
+type resourceLogLevels struct {
+	mu        sync.Mutex
+	byName    map[string]zapcore.Level
+	byModel   map[resource.Model]zapcore.Level
+	overrides map[resource.Name]zapcore.Level
+	levels    map[resource.Name]*resourceLevel
+}
+
+// A resourceLevel is the level shared by the loggers of one resource. A nil level leaves the
+// level up to the robot's logger.
+type resourceLevel struct {
+	model resource.Model
+	level atomic.Pointer[zapcore.Level]
+}
+
+func newResourceLogLevels() *resourceLogLevels {
+	return &resourceLogLevels{
+		byName:    map[string]zapcore.Level{},
+		byModel:   map[resource.Model]zapcore.Level{},
+		overrides: map[resource.Name]zapcore.Level{},
+		levels:    map[resource.Name]*resourceLevel{},
+	}
+}
+
+// logger returns a logger for a resource whose level follows the levels set for it.
+func (l *resourceLogLevels) logger(logger golog.Logger, name resource.Name, model resource.Model) golog.Logger {
+	if l == nil {
+		return logger
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lvl, ok := l.levels[name]
+	if !ok || lvl.model != model {
+		lvl = &resourceLevel{model: model}
+		l.levels[name] = lvl
+	}
+	l.updateLocked(name, lvl)
+	return logger.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, level: lvl}
+	})).Sugar()
+}
+
+// setConfig replaces the levels set by the log config. Invalid entries are ignored since they
+// are reported when the config is validated.
+func (l *resourceLogLevels) setConfig(logConfigs []config.ResourceLogConfig) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.byName = map[string]zapcore.Level{}
+	l.byModel = map[resource.Model]zapcore.Level{}
+	for _, c := range logConfigs {
+		level, err := c.ParsedLevel()
+		if err != nil {
+			continue
+		}
+		if model, ok := c.ParsedModel(); ok {
+			l.byModel[model] = level
+		} else if c.Resource != "" {
+			l.byName[c.Resource] = level
+		}
+	}
+	for name, lvl := range l.levels {
+		l.updateLocked(name, lvl)
+	}
+}
+
+// setOverride sets or, given a nil level, removes the runtime override of a resource's level.
+func (l *resourceLogLevels) setOverride(name resource.Name, level *zapcore.Level) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if level == nil {
+		delete(l.overrides, name)
+	} else {
+		l.overrides[name] = *level
+	}
+	if lvl, ok := l.levels[name]; ok {
+		l.updateLocked(name, lvl)
+	}
+}
+
+func (l *resourceLogLevels) updateLocked(name resource.Name, lvl *resourceLevel) {
+	if level, ok := l.overrides[name]; ok {
+		lvl.level.Store(&level)
+		return
+	}
+	if level, ok := l.byName[name.Name]; ok {
+		lvl.level.Store(&level)
+		return
+	}
+	if level, ok := l.byModel[lvl.model]; ok {
+		lvl.level.Store(&level)
+		return
+	}
+	lvl.level.Store(nil)
+}
+
+// A levelCore logs at the level of a resource while one is set, even below the level of the
+// core it wraps.
+type levelCore struct {
+	zapcore.Core
+	level *resourceLevel
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	if lvl := c.level.level.Load(); lvl != nil {
+		return lvl.Enabled(level)
+	}
+	return c.Core.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	lvl := c.level.level.Load()
+	if lvl == nil {
+		return c.Core.Check(ent, ce)
+	}
+	if lvl.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
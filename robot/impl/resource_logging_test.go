@@ -0,0 +1,79 @@
+package robotimpl
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+)
+
+func TestResourceLogLevels(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	robotLogger := zap.New(core).Sugar()
+
+	model := resource.NewDefaultModel("fake")
+	arm1 := resource.NameFromSubtype(resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "arm"), "arm1")
+	arm2 := resource.NameFromSubtype(resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "arm"), "arm2")
+
+	levels := newResourceLogLevels()
+	logger1 := levels.logger(robotLogger, arm1, model)
+	logger2 := levels.logger(robotLogger, arm2, model).With("extra", true)
+
+	// without any levels set resources log at the level of the robot
+	logger1.Debug("hidden")
+	logger1.Info("shown")
+	test.That(t, logs.TakeAll(), test.ShouldHaveLength, 1)
+
+	// levels set by model apply to every resource of the model
+	levels.setConfig([]config.ResourceLogConfig{{Model: "rdk:builtin:fake", Level: "debug"}})
+	logger1.Debug("shown")
+	logger2.Debug("shown")
+	test.That(t, logs.TakeAll(), test.ShouldHaveLength, 2)
+
+	// levels set by resource take precedence over those set by model
+	levels.setConfig([]config.ResourceLogConfig{
+		{Model: "rdk:builtin:fake", Level: "debug"},
+		{Resource: "arm2", Level: "error"},
+	})
+	logger1.Debug("shown")
+	logger2.Warn("hidden")
+	entries := logs.TakeAll()
+	test.That(t, entries, test.ShouldHaveLength, 1)
+	test.That(t, entries[0].Message, test.ShouldEqual, "shown")
+
+	// runtime overrides take precedence over the config until removed
+	debug := zapcore.DebugLevel
+	levels.setOverride(arm2, &debug)
+	logger2.Debug("shown")
+	test.That(t, logs.TakeAll(), test.ShouldHaveLength, 1)
+	levels.setOverride(arm2, nil)
+	logger2.Debug("hidden")
+	test.That(t, logs.TakeAll(), test.ShouldBeEmpty)
+
+	// new loggers of a resource pick up the current level
+	levels.setConfig(nil)
+	logger1 = levels.logger(robotLogger, arm1, model)
+	logger1.Debug("hidden")
+	test.That(t, logs.TakeAll(), test.ShouldBeEmpty)
+}
+
+func TestNilResourceLogLevels(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	robotLogger := zap.New(core).Sugar()
+	arm1 := resource.NameFromSubtype(resource.NewSubtype(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, "arm"), "arm1")
+
+	// robots constructed without levels leave resources at the level of the robot
+	var levels *resourceLogLevels
+	levels.setConfig([]config.ResourceLogConfig{{Resource: "arm1", Level: "debug"}})
+	debug := zapcore.DebugLevel
+	levels.setOverride(arm1, &debug)
+	logger := levels.logger(robotLogger, arm1, resource.NewDefaultModel("fake"))
+	logger.Debug("hidden")
+	logger.Info("shown")
+	test.That(t, logs.TakeAll(), test.ShouldHaveLength, 1)
+}
@@ -2,11 +2,15 @@ package robotimpl
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/edaniels/golog"
 	"github.com/jhump/protoreflect/desc"
@@ -17,6 +21,7 @@ import (
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/module/modmanager"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
@@ -53,6 +58,10 @@ type resourceManager struct {
 	opts           resourceManagerOptions
 	logger         golog.Logger
 	configLock     *sync.Mutex
+	// connPool lets remotes on the same host share a single connection.
+	connPool *grpc.SharedConnPool
+	// optional tracks the optional dependencies of resources that have been constructed.
+	optional map[resource.Name]*optionalDependencies
 }
 
 // resourcePlaceholder we use resourcePlaceholder during a reconfiguration
@@ -96,6 +105,8 @@ func newResourceManager(
 		opts:           opts,
 		logger:         logger,
 		configLock:     &sync.Mutex{},
+		connPool:       grpc.NewSharedConnPool(logger),
+		optional:       map[resource.Name]*optionalDependencies{},
 	}
 }
 
@@ -367,6 +378,9 @@ func (manager *resourceManager) Close(ctx context.Context, r *localRobot) error
 			}
 		}
 	}
+	if err := manager.connPool.Close(); err != nil {
+		allErrs = multierr.Combine(allErrs, errors.Wrap(err, "error closing remote connections"))
+	}
 	return allErrs
 }
 
@@ -446,6 +460,7 @@ func (manager *resourceManager) completeConfig(
 			manager.logger.Errorw(err.Error(), "resource", r)
 		}
 	}
+	manager.bindOptionalDependencies(ctx)
 }
 
 // cleanAppImageEnv attempts to revert environment variable changes so
@@ -522,8 +537,15 @@ func (manager *resourceManager) processRemote(ctx context.Context,
 	config config.Remote,
 ) (*client.RobotClient, error) {
 	dialOpts := remoteDialOptions(config, manager.opts)
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.TLSConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid tls settings for remote %q", config.Name)
+		}
+		dialOpts = append(dialOpts, rpc.WithTLSConfig(tlsConfig))
+	}
 	manager.logger.Debugw("connecting now to remote", "remote", config.Name)
-	robotClient, err := dialRobotClient(ctx, config, manager.logger, dialOpts...)
+	robotClient, err := dialRobotClient(ctx, config, manager.connPool, manager.logger, dialOpts...)
 	if err != nil {
 		if errors.Is(err, rpc.ErrInsecureWithCredentials) {
 			if manager.opts.fromCommand {
@@ -556,6 +578,100 @@ func (manager *resourceManager) RemoteByName(name string) (robot.Robot, bool) {
 	return nil, false
 }
 
+// resourceGraph returns a snapshot of the manager's dependency graph. The given config
+// is used to attribute each resource to the part of the config that defined it.
+func (manager *resourceManager) resourceGraph(cfg *config.Config) robot.ResourceGraph {
+	type provenance struct {
+		source    string
+		model     string
+		dependsOn []string
+	}
+	provenances := map[resource.Name]provenance{}
+	if cfg != nil {
+		for _, c := range cfg.Components {
+			provenances[c.ResourceName()] = provenance{"component", c.Model.String(), c.Dependencies()}
+		}
+		for _, s := range cfg.Services {
+			provenances[s.ResourceName()] = provenance{"service", s.Model.String(), s.Dependencies()}
+		}
+		for _, r := range cfg.Remotes {
+			provenances[fromRemoteNameToRemoteNodeName(r.Name)] = provenance{source: "remote"}
+		}
+	}
+
+	var graph robot.ResourceGraph
+	for _, name := range manager.resources.TopologicalSort() {
+		node := robot.ResourceGraphNode{Name: name}
+		if p, ok := provenances[name]; ok {
+			node.Source = p.source
+			node.Model = p.model
+			node.DependsOn = p.dependsOn
+		} else if name.ContainsRemoteNames() {
+			node.Source = "remote_resource"
+		}
+
+		iface, _ := manager.resources.Node(name)
+		switch res := iface.(type) {
+		case nil:
+			node.State = robot.ResourceStateUnresolved
+		case *resourcePlaceholder:
+			node.State = robot.ResourceStateFailed
+			if res.err != nil {
+				node.Error = res.err.Error()
+			}
+		case robot.RemoteRobot:
+			node.State = robot.ResourceStateReady
+			if !res.Connected() {
+				node.State = robot.ResourceStateUnavailable
+			}
+		default:
+			node.State = robot.ResourceStateReady
+		}
+		graph.Nodes = append(graph.Nodes, node)
+
+		for _, dep := range manager.resources.GetAllParentsOf(name) {
+			graph.Edges = append(graph.Edges, robot.ResourceGraphEdge{From: name, To: dep})
+		}
+	}
+	return graph
+}
+
+// remoteConnectionStatus returns the state of the connection to the given remote,
+// suitable for reporting through the robot's status.
+func (manager *resourceManager) remoteConnectionStatus(name string) (map[string]interface{}, error) {
+	rName := fromRemoteNameToRemoteNodeName(name)
+	iface, ok := manager.resources.Node(rName)
+	if !ok {
+		return nil, rutils.NewResourceNotFoundError(rName)
+	}
+	switch remote := iface.(type) {
+	case *client.RobotClient:
+		connStatus := remote.ConnectionStatus()
+		status := map[string]interface{}{
+			"address":            connStatus.Address,
+			"connected":          connStatus.Connected,
+			"reconnect_attempts": connStatus.ReconnectAttempts,
+		}
+		if !connStatus.LastConnected.IsZero() {
+			status["last_connected"] = connStatus.LastConnected.Format(time.RFC3339)
+		}
+		if connStatus.LastError != "" {
+			status["last_error"] = connStatus.LastError
+		}
+		return status, nil
+	case *resourcePlaceholder:
+		status := map[string]interface{}{"connected": false}
+		if remote.err != nil {
+			status["last_error"] = remote.err.Error()
+		}
+		return status, nil
+	case robot.RemoteRobot:
+		return map[string]interface{}{"connected": remote.Connected()}, nil
+	default:
+		return nil, errors.Errorf("%q is not a remote robot", name)
+	}
+}
+
 func (manager *resourceManager) processService(ctx context.Context,
 	c config.Service,
 	old interface{},
@@ -566,7 +682,7 @@ func (manager *resourceManager) processService(ctx context.Context,
 	}
 
 	if robot.ModuleManager().Provides(config.ServiceConfigToShared(c)) {
-		deps, err := robot.getDependencies(c.ResourceName())
+		deps, err := robot.getDependencies(c.ResourceName(), c.OptionalDependencies())
 		if err != nil {
 			return nil, err
 		}
@@ -626,7 +742,7 @@ func (manager *resourceManager) processComponent(ctx context.Context,
 	}
 	res := config.Rebuild
 	if r.ModuleManager().Provides(conf) {
-		deps, err := r.getDependencies(rName)
+		deps, err := r.getDependencies(rName, conf.OptionalDependencies())
 		if err != nil {
 			return nil, err
 		}
@@ -939,6 +1055,28 @@ func (manager *resourceManager) FilterFromConfig(ctx context.Context, conf *conf
 	return filtered, allErrs
 }
 
+// remoteConnKey identifies everything about the connection to a remote that its dial options
+// depend on. Remotes with the same key share a connection.
+func remoteConnKey(remote config.Remote) string {
+	auth := remote.Auth
+	var creds, signalingCreds rpc.Credentials
+	if auth.Credentials != nil {
+		creds = *auth.Credentials
+	}
+	if auth.SignalingCreds != nil {
+		signalingCreds = *auth.SignalingCreds
+	}
+	auth.Credentials, auth.SignalingCreds = nil, nil
+	var remoteTLS config.RemoteTLS
+	if remote.TLS != nil {
+		remoteTLS = *remote.TLS
+	}
+	// hashed so that credentials are not kept around in the clear
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%t|%+v|%+v|%+v|%t|%+v",
+		remote.Address, remote.Insecure, auth, creds, signalingCreds, remote.TLS != nil, remoteTLS)))
+	return hex.EncodeToString(sum[:])
+}
+
 func remoteDialOptions(config config.Remote, opts resourceManagerOptions) []rpc.DialOption {
 	var dialOpts []rpc.DialOption
 	if opts.debug {
@@ -1,6 +1,10 @@
 package robotimpl
 
-import "go.viam.com/rdk/robot/web"
+import (
+	"time"
+
+	"go.viam.com/rdk/robot/web"
+)
 
 // options configures a Robot.
 type options struct {
@@ -10,6 +14,10 @@ type options struct {
 	// revealSensitiveConfigDiffs will display config diffs - which may contain secret
 	// information - in log statements
 	revealSensitiveConfigDiffs bool
+
+	// safeStopTimeout bounds how long each actuator may take to reach its safe state
+	// during shutdown.
+	safeStopTimeout time.Duration
 }
 
 // Option configures how we set up the web service.
@@ -50,3 +58,11 @@ func WithRevealSensitiveConfigDiffs() Option {
 		o.revealSensitiveConfigDiffs = true
 	})
 }
+
+// WithSafeStopTimeout returns an Option which bounds the time each actuator
+// is given to reach its safe state when the robot is closed.
+func WithSafeStopTimeout(timeout time.Duration) Option {
+	return newFuncOption(func(o *options) {
+		o.safeStopTimeout = timeout
+	})
+}
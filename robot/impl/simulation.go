@@ -0,0 +1,138 @@
+package robotimpl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+)
+
+// simulatedModel is the model every component is replaced with when simulating a robot.
+var simulatedModel = resource.NewDefaultModel("fake")
+
+// simulatedModelAttributes name the attribute through which the fake of a component type can
+// mimic a real model, such as the kinematics of a particular arm.
+var simulatedModelAttributes = map[resource.SubtypeName]string{
+	"arm": "arm-model",
+}
+
+// SimulatedConfig returns a copy of the given processed config in which every component is
+// replaced by the fake model of its type. Names, frames and dependencies are kept so that
+// the simulated robot is structured exactly like the real one. Attributes the fake understands
+// are carried over; if the fake rejects them, it is configured without any. Components of a
+// type without a fake are left as they are.
+func SimulatedConfig(cfg *config.Config, logger golog.Logger) (*config.Config, error) {
+	converters := map[resource.Subtype]config.AttributeMapConverter{}
+	for _, reg := range config.RegisteredComponentAttributeMapConverters() {
+		if reg.Model == simulatedModel {
+			converters[reg.Subtype] = reg.Conv
+		}
+	}
+
+	simulated := *cfg
+	simulated.Components = make([]config.Component, 0, len(cfg.Components))
+	for idx, c := range cfg.Components {
+		namespace := c.Namespace
+		if namespace == "" {
+			namespace = resource.ResourceNamespaceRDK
+		}
+		subtype := resource.NewSubtype(namespace, resource.ResourceTypeComponent, c.Type)
+		if c.Model == simulatedModel || registry.ComponentLookup(subtype, simulatedModel) == nil {
+			if c.Model != simulatedModel {
+				logger.Warnw("no fake to simulate component with; keeping it as is", "name", c.Name, "type", subtype)
+			}
+			simulated.Components = append(simulated.Components, c)
+			continue
+		}
+
+		attrs, err := simulationAttributes(c)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error simulating component %q", c.Name)
+		}
+		candidates := []config.AttributeMap{attrs, {}}
+		if attr, ok := simulatedModelAttributes[c.Type]; ok {
+			withModel := config.AttributeMap{attr: c.Model.Name}
+			for k, v := range attrs {
+				withModel[k] = v
+			}
+			candidates = append([]config.AttributeMap{withModel}, candidates...)
+		}
+
+		var fake config.Component
+		for _, candidate := range candidates {
+			fake, err = simulatedComponent(c, candidate, converters[subtype], fmt.Sprintf("components.%d", idx))
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error simulating component %q", c.Name)
+		}
+		logger.Debugw("simulating component", "name", c.Name, "model", c.Model)
+		simulated.Components = append(simulated.Components, fake)
+	}
+	return &simulated, nil
+}
+
+// simulationAttributes returns the raw attributes of a component. Attributes of processed
+// configs have already been converted, so they are recovered from their JSON form.
+func simulationAttributes(c config.Component) (config.AttributeMap, error) {
+	attrs := config.AttributeMap{}
+	for k, v := range c.Attributes {
+		attrs[k] = v
+	}
+	if c.ConvertedAttributes == nil {
+		return attrs, nil
+	}
+	data, err := json.Marshal(c.ConvertedAttributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return nil, err
+	}
+	return attrs, nil
+}
+
+// simulatedComponent returns the fake counterpart of c with the given attributes, failing if
+// the fake does not accept them.
+func simulatedComponent(
+	c config.Component,
+	attrs config.AttributeMap,
+	conv config.AttributeMapConverter,
+	path string,
+) (config.Component, error) {
+	fake := c
+	fake.Model = simulatedModel
+	fake.Attributes = attrs
+	fake.ConvertedAttributes = nil
+	if conv != nil {
+		converted, err := conv(attrs)
+		if err != nil {
+			return config.Component{}, err
+		}
+		fake.Attributes = nil
+		fake.ConvertedAttributes = converted
+	}
+	deps, err := fake.Validate(path)
+	if err != nil {
+		return config.Component{}, err
+	}
+	// the real model's dependencies are kept so that the fake is built in the same order, even
+	// when the fake itself does not need them all
+	seen := goutils.NewStringSet(c.ImplicitDependsOn...)
+	fake.ImplicitDependsOn = append([]string{}, c.ImplicitDependsOn...)
+	for _, dep := range deps {
+		if _, ok := seen[dep]; !ok {
+			seen[dep] = struct{}{}
+			fake.ImplicitDependsOn = append(fake.ImplicitDependsOn, dep)
+		}
+	}
+	return fake, nil
+}
@@ -0,0 +1,107 @@
+package robotimpl_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	fakearm "go.viam.com/rdk/components/arm/fake"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	robotimpl "go.viam.com/rdk/robot/impl"
+)
+
+func TestSimulatedConfig(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg, err := config.FromReader(ctx, "", strings.NewReader(`{
+		"components": [
+			{
+				"name": "arm1",
+				"type": "arm",
+				"model": "xArm6",
+				"attributes": {"host": "10.0.0.2"},
+				"frame": {"parent": "world", "translation": {"x": 1, "y": 2, "z": 3}}
+			},
+			{
+				"name": "sensor1",
+				"type": "sensor",
+				"model": "fake"
+			}
+		]
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	simulated, err := robotimpl.SimulatedConfig(cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, simulated.Components, test.ShouldHaveLength, 2)
+	test.That(t, simulated.Components[0].Name, test.ShouldEqual, "arm1")
+	test.That(t, simulated.Components[0].Model, test.ShouldResemble, fakearm.ModelName)
+	test.That(t, simulated.Components[0].Frame, test.ShouldResemble, cfg.Components[0].Frame)
+	test.That(t, simulated.Components[0].ConvertedAttributes, test.ShouldResemble, &fakearm.AttrConfig{ArmModel: "xArm6"})
+	test.That(t, simulated.Components[1], test.ShouldResemble, cfg.Components[1])
+	// the original config is left alone
+	test.That(t, cfg.Components[0].Model.Name, test.ShouldEqual, "xArm6")
+
+	r, err := robotimpl.New(ctx, simulated, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	a, err := arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, a.ModelFrame().DoF(), test.ShouldHaveLength, 6)
+	_, err = sensor.FromRobot(r, "sensor1")
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestSimulatedConfigDependencies(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ctx := context.Background()
+
+	// the fake motor does not ask for its encoder and the fake encoder does not ask for a board,
+	// but the fakes must still be built in the order of the real models
+	cfg, err := config.FromReader(ctx, "", strings.NewReader(`{
+		"components": [
+			{
+				"name": "board1",
+				"type": "board",
+				"model": "fake"
+			},
+			{
+				"name": "encoder1",
+				"type": "encoder",
+				"model": "incremental",
+				"attributes": {"board": "board1", "pins": {"a": "11", "b": "13"}}
+			},
+			{
+				"name": "motor1",
+				"type": "motor",
+				"model": "gpio",
+				"attributes": {
+					"board": "board1",
+					"encoder": "encoder1",
+					"pins": {"pwm": "5", "dir": "6"},
+					"ticks_per_rotation": 100
+				}
+			}
+		]
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	simulated, err := robotimpl.SimulatedConfig(cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, simulated.Components, test.ShouldHaveLength, 3)
+	for i, c := range simulated.Components {
+		test.That(t, c.Name, test.ShouldEqual, cfg.Components[i].Name)
+		test.That(t, c.Dependencies(), test.ShouldResemble, cfg.Components[i].Dependencies())
+	}
+	test.That(t, simulated.Components[2].Model, test.ShouldResemble, resource.NewDefaultModel("fake"))
+}
@@ -0,0 +1,225 @@
+package robotimpl
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	rutils "go.viam.com/rdk/utils"
+)
+
+var (
+	// watchdogInterval is how often the health of resources is checked.
+	watchdogInterval = 5 * time.Second
+	// healthCheckTimeout is how long a resource has to answer a health check before it is
+	// considered hung.
+	healthCheckTimeout = 5 * time.Second
+	// restartBackoffBase is how long the watchdog waits before restarting a resource again.
+	// It doubles with every restart up to restartBackoffMax.
+	restartBackoffBase = time.Second
+	// restartBackoffMax bounds the wait between restarts. A resource that stays healthy this
+	// long has its backoff reset.
+	restartBackoffMax = time.Minute
+)
+
+// resourceWatchdog checks the health of local components that implement resource.HealthChecker
+// or resource.HealthReporter and rebuilds those that fail, backing off exponentially when they keep failing.
+type resourceWatchdog struct {
+	r      *localRobot
+	logger golog.Logger
+
+	mu          sync.Mutex
+	states      map[resource.Name]*watchedResource
+	subscribers map[int]chan robot.ResourceHealthEvent
+	nextSubID   int
+}
+
+type watchedResource struct {
+	unhealthy    bool
+	attempts     int
+	nextRestart  time.Time
+	healthySince time.Time
+}
+
+func newResourceWatchdog(r *localRobot, logger golog.Logger) *resourceWatchdog {
+	return &resourceWatchdog{
+		r:           r,
+		logger:      logger,
+		states:      map[resource.Name]*watchedResource{},
+		subscribers: map[int]chan robot.ResourceHealthEvent{},
+	}
+}
+
+func (w *resourceWatchdog) subscribe(buffer int) (<-chan robot.ResourceHealthEvent, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextSubID
+	w.nextSubID++
+	ch := make(chan robot.ResourceHealthEvent, buffer)
+	w.subscribers[id] = ch
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+func (w *resourceWatchdog) emit(event robot.ResourceHealthEvent) {
+	switch event.Type {
+	case robot.ResourceUnhealthy, robot.ResourceRestartFailed:
+		w.logger.Warnw("resource watchdog", "resource", event.Name, "event", event.Type, "attempt", event.Attempt, "error", event.Err)
+	case robot.ResourceRestarted:
+		w.logger.Infow("resource watchdog", "resource", event.Name, "event", event.Type, "attempt", event.Attempt)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, ch := range w.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// restartBackoff returns how long to wait after the given number of restarts.
+func restartBackoff(attempts int) time.Duration {
+	return rutils.Backoff{Initial: restartBackoffBase, Max: restartBackoffMax}.Delay(attempts)
+}
+
+// checkHealth runs a resource's health check, treating a check that outlives
+// healthCheckTimeout as a hang.
+func checkHealth(ctx context.Context, checker resource.HealthChecker) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	errCh := make(chan error, 1)
+	goutils.PanicCapturingGo(func() {
+		errCh <- checker.CheckHealth(ctx)
+	})
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return errors.Errorf("health check did not finish within %s", healthCheckTimeout)
+	}
+}
+
+// check probes every watched resource once and restarts those found unhealthy whose
+// backoff has elapsed.
+func (w *resourceWatchdog) check(ctx context.Context) {
+	checkers := map[resource.Name]resource.HealthChecker{}
+	for _, name := range w.r.manager.resources.Names() {
+		if name.ResourceType != resource.ResourceTypeComponent || name.ContainsRemoteNames() {
+			continue
+		}
+		iface, ok := w.r.manager.resources.Node(name)
+		if !ok {
+			continue
+		}
+		if checker, ok := healthCheckerOf(iface); ok {
+			checkers[name] = checker
+		}
+	}
+
+	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	results := make(map[resource.Name]error, len(checkers))
+	for name, checker := range checkers {
+		name, checker := name, checker
+		wg.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer wg.Done()
+			err := checkHealth(ctx, checker)
+			resultsMu.Lock()
+			results[name] = err
+			resultsMu.Unlock()
+		})
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	now := time.Now()
+	var toRestart []resource.Name
+	w.mu.Lock()
+	for name := range w.states {
+		if _, ok := results[name]; !ok {
+			delete(w.states, name)
+		}
+	}
+	var events []robot.ResourceHealthEvent
+	for name, err := range results {
+		state, ok := w.states[name]
+		if !ok {
+			state = &watchedResource{healthySince: now}
+			w.states[name] = state
+		}
+		if err == nil {
+			if state.unhealthy {
+				state.unhealthy = false
+				state.healthySince = now
+			}
+			if state.attempts != 0 && now.Sub(state.healthySince) >= restartBackoffMax {
+				state.attempts = 0
+			}
+			continue
+		}
+		if !state.unhealthy {
+			state.unhealthy = true
+			events = append(events, robot.ResourceHealthEvent{
+				Name: name, Type: robot.ResourceUnhealthy, Err: err, Time: now, Attempt: state.attempts,
+			})
+		}
+		if now.Before(state.nextRestart) {
+			continue
+		}
+		state.attempts++
+		state.nextRestart = now.Add(restartBackoff(state.attempts))
+		toRestart = append(toRestart, name)
+	}
+	w.mu.Unlock()
+	for _, event := range events {
+		w.emit(event)
+	}
+
+	for _, name := range toRestart {
+		w.mu.Lock()
+		attempt := w.states[name].attempts
+		w.mu.Unlock()
+		event := robot.ResourceHealthEvent{Name: name, Type: robot.ResourceRestarted, Attempt: attempt}
+		if err := w.restart(ctx, name); err != nil {
+			event.Type = robot.ResourceRestartFailed
+			event.Err = err
+		}
+		event.Time = time.Now()
+		w.emit(event)
+	}
+}
+
+// restart tears down the resource along with everything depending on it and builds them
+// again from their current config.
+func (w *resourceWatchdog) restart(ctx context.Context, name resource.Name) error {
+	w.r.manager.configLock.Lock()
+	err := w.r.manager.markChildrenForUpdate(ctx, name, w.r)
+	w.r.manager.configLock.Unlock()
+	if err != nil {
+		return err
+	}
+	w.r.manager.completeConfig(ctx, w.r)
+	w.r.updateDefaultServices(ctx)
+
+	iface, ok := w.r.manager.resources.Node(name)
+	if !ok {
+		return errors.Errorf("resource %q is gone", name)
+	}
+	if ph, ok := iface.(*resourcePlaceholder); ok {
+		return ph.err
+	}
+	return nil
+}
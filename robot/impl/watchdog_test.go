@@ -0,0 +1,105 @@
+package robotimpl
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+type watchedComponent struct {
+	healthy bool
+	// the component only reports being broken once this is set, so that no events are missed before subscribing to them
+	watched *int32
+}
+
+func (c *watchedComponent) CheckHealth(ctx context.Context) error {
+	if !c.healthy && atomic.LoadInt32(c.watched) == 1 {
+		return errors.New("lost connection to device")
+	}
+	return nil
+}
+
+func TestRestartBackoff(t *testing.T) {
+	test.That(t, restartBackoff(1), test.ShouldEqual, restartBackoffBase)
+	test.That(t, restartBackoff(2), test.ShouldEqual, 2*restartBackoffBase)
+	test.That(t, restartBackoff(3), test.ShouldEqual, 4*restartBackoffBase)
+	test.That(t, restartBackoff(100), test.ShouldEqual, restartBackoffMax)
+}
+
+func TestResourceWatchdog(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ctx := context.Background()
+
+	origInterval, origBase := watchdogInterval, restartBackoffBase
+	watchdogInterval, restartBackoffBase = 10*time.Millisecond, 10*time.Millisecond
+	defer func() {
+		watchdogInterval, restartBackoffBase = origInterval, origBase
+	}()
+
+	subtype := resource.NewSubtype("acme", resource.ResourceTypeComponent, "watched")
+	model := resource.NewModel("acme", "watched", "flaky")
+	var builds, watched int32
+	registry.RegisterResourceSubtype(subtype, registry.ResourceSubtype{ReflectRPCServiceDesc: &desc.ServiceDescriptor{}})
+	registry.RegisterComponent(subtype, model, registry.Component{
+		Constructor: func(ctx context.Context, deps registry.Dependencies, cfg config.Component, logger golog.Logger) (interface{}, error) {
+			// only the first instance is broken
+			return &watchedComponent{healthy: atomic.AddInt32(&builds, 1) > 1, watched: &watched}, nil
+		},
+	})
+	defer func() {
+		registry.DeregisterComponent(subtype, model)
+		registry.DeregisterResourceSubtype(subtype)
+	}()
+
+	cfg := &config.Config{Components: []config.Component{{Name: "thing", Namespace: "acme", Type: "watched", Model: model}}}
+	r, err := New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	//nolint:forcetypeassert
+	events, unsubscribe := r.(robot.ResourceHealthWatcher).SubscribeResourceHealth(10)
+	defer unsubscribe()
+	atomic.StoreInt32(&watched, 1)
+	nextEvent := func() robot.ResourceHealthEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a resource health event")
+			return robot.ResourceHealthEvent{}
+		}
+	}
+
+	name := resource.NameFromSubtype(subtype, "thing")
+	event := nextEvent()
+	test.That(t, event.Name, test.ShouldResemble, name)
+	test.That(t, event.Type, test.ShouldEqual, robot.ResourceUnhealthy)
+	test.That(t, event.Err, test.ShouldNotBeNil)
+	event = nextEvent()
+	test.That(t, event.Type, test.ShouldEqual, robot.ResourceRestarted)
+	test.That(t, event.Attempt, test.ShouldEqual, 1)
+
+	res, err := r.ResourceByName(name)
+	test.That(t, err, test.ShouldBeNil)
+	//nolint:forcetypeassert
+	test.That(t, res.(*watchedComponent).healthy, test.ShouldBeTrue)
+	test.That(t, atomic.LoadInt32(&builds), test.ShouldEqual, 2)
+
+	// a healthy resource is left alone
+	time.Sleep(10 * watchdogInterval)
+	test.That(t, atomic.LoadInt32(&builds), test.ShouldEqual, 2)
+}
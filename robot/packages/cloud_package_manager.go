@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 	"hash"
@@ -25,12 +26,38 @@ import (
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/config"
+	rutils "go.viam.com/rdk/utils"
 )
 
 const (
 	allowedContentType = "application/x-gzip"
 )
 
+// downloadRetryOptions returns how package downloads are retried when the network or the server
+// fails. Client errors and archives that fail verification are not retried.
+func downloadRetryOptions(logger golog.Logger) rutils.RetryOptions {
+	return rutils.RetryOptions{
+		Backoff:     rutils.Backoff{Initial: 500 * time.Millisecond, Max: 5 * time.Second, Jitter: 0.2},
+		MaxAttempts: 3,
+		Retryable: func(err error) bool {
+			return !errors.Is(err, ErrPackageIntegrity)
+		},
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			logger.Warnw("package download failed, retrying", "attempt", attempt, "delay", delay, "error", err)
+		},
+	}
+}
+
+// downloadStatusError returns the error for an unsuccessful download response, which is not
+// worth retrying for client errors.
+func downloadStatusError(statusCode int) error {
+	err := fmt.Errorf("invalid status code %d", statusCode)
+	if statusCode >= 400 && statusCode < 500 {
+		return rutils.Permanent(err)
+	}
+	return err
+}
+
 var (
 	_ Manager       = (*cloudManager)(nil)
 	_ ManagerSyncer = (*cloudManager)(nil)
@@ -39,6 +66,11 @@ var (
 type managedPackage struct {
 	thePackage config.PackageConfig
 	modtime    time.Time
+	// previous is the version that was in use before the last sync changed the package. It is
+	// kept on disk so that the package can be rolled back to it.
+	previous *config.PackageConfig
+	// rolledBack is the version the package was rolled back from, which syncs do not switch to again.
+	rolledBack *config.PackageConfig
 }
 
 type cloudManager struct {
@@ -84,24 +116,16 @@ func (m *cloudManager) PackagePath(name PackageName) (string, error) {
 	if !ok {
 		return "", ErrPackageMissing
 	}
+	// the contents may have changed since they were synced, so they are checked every time they are handed out.
+	if err := verifyPackageDir(p.thePackage, m.localDataPath(p.thePackage)); err != nil {
+		return "", errors.Wrapf(err, "package %s", name)
+	}
 
 	return m.localNamedPath(p.thePackage), nil
 }
 
 func (m *cloudManager) RefPath(refPath string) (string, error) {
-	ref := config.GetPackageReference(refPath)
-
-	// If no reference just return original path.
-	if ref == nil {
-		return refPath, nil
-	}
-
-	packagePath, err := m.PackagePath(PackageName(ref.Package))
-	if err != nil {
-		return "", err
-	}
-
-	return path.Join(packagePath, path.Clean(ref.PathInPackage)), nil
+	return packageRefPath(m, refPath)
 }
 
 // Close manager.
@@ -136,10 +160,24 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 
 		// Package exists in known cache.
 		existing, ok := m.managedPackages[PackageName(p.Name)]
+		sameVersion := ok && samePackageVersion(existing.thePackage, p)
 		if ok {
-			if existing.thePackage.Package == p.Package && existing.thePackage.Version == p.Version {
+			// managed packages are kept only while their contents are unchanged since they were synced.
+			verifyErr := verifyPackageDir(existing.thePackage, m.localDataPath(existing.thePackage))
+			switch {
+			case verifyErr != nil:
+				m.logger.Warnf("  Package %s:%s changed since it was synced, syncing it again: %s",
+					existing.thePackage.Package, existing.thePackage.Version, verifyErr)
+			case sameVersion:
 				m.logger.Debug("  Package already managed, skipping")
 
+				newManagedPackages[PackageName(p.Name)] = existing
+				delete(m.managedPackages, PackageName(p.Name))
+				continue
+			case existing.rolledBack != nil && samePackageVersion(*existing.rolledBack, p):
+				m.logger.Warnf("  Package was rolled back from %s:%s, keeping %s:%s",
+					p.Package, p.Version, existing.thePackage.Package, existing.thePackage.Version)
+
 				newManagedPackages[PackageName(p.Name)] = existing
 				delete(m.managedPackages, PackageName(p.Name))
 				continue
@@ -167,7 +205,9 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 			continue
 		}
 
-		err = linkFile(m.localDataPath(p), m.localNamedPath(p))
+		// the link is switched only once the new version is in place, so the previous version stays
+		// usable until then.
+		err = switchLink(m.localDataPath(p), m.localNamedPath(p))
 		if err != nil {
 			m.logger.Errorf("Failed linking package %s:%s, %s", p.Package, p.Version, err)
 			outErr = multierr.Append(outErr, err)
@@ -175,7 +215,15 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 		}
 
 		// add to managed packages
-		newManagedPackages[PackageName(p.Name)] = &managedPackage{thePackage: p, modtime: time.Now()}
+		managed := &managedPackage{thePackage: p, modtime: time.Now()}
+		switch {
+		case sameVersion:
+			managed.previous = existing.previous
+		case ok:
+			previous := existing.thePackage
+			managed.previous = &previous
+		}
+		newManagedPackages[PackageName(p.Name)] = managed
 
 		m.logger.Debugf("  Sync complete after %dms", time.Since(start).Milliseconds())
 	}
@@ -186,7 +234,37 @@ func (m *cloudManager) Sync(ctx context.Context, packages []config.PackageConfig
 	return outErr
 }
 
-// Cleanup removes all unknown packages from the working directory.
+// Rollback points a package back at the version it used before the last sync changed it. Later
+// syncs keep the previous version until the package is configured with a different version.
+func (m *cloudManager) Rollback(ctx context.Context, name PackageName) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	managed, ok := m.managedPackages[name]
+	if !ok {
+		return ErrPackageMissing
+	}
+	if managed.previous == nil {
+		return ErrNoPreviousVersion
+	}
+
+	previous := *managed.previous
+	if err := verifyPackageDir(previous, m.localDataPath(previous)); err != nil {
+		return errors.Wrapf(err, "cannot roll back to %s:%s", previous.Package, previous.Version)
+	}
+	if err := switchLink(m.localDataPath(previous), m.localNamedPath(previous)); err != nil {
+		return err
+	}
+
+	m.logger.Infof("Rolled back package %s from %s:%s to %s:%s",
+		name, managed.thePackage.Package, managed.thePackage.Version, previous.Package, previous.Version)
+	failed := managed.thePackage
+	m.managedPackages[name] = &managedPackage{thePackage: previous, modtime: time.Now(), rolledBack: &failed}
+	return nil
+}
+
+// Cleanup removes all unknown packages from the working directory. The version of each package
+// used before the last sync is kept so that the package can be rolled back.
 func (m *cloudManager) Cleanup(ctx context.Context) error {
 	m.logger.Debug("Starting package cleanup")
 
@@ -205,13 +283,18 @@ func (m *cloudManager) Cleanup(ctx context.Context) error {
 
 	// keep track of known packages by their hashed name from the package id and version.
 	knownPackages := make(map[string]bool)
+	for _, p := range m.managedPackages {
+		knownPackages[hashName(p.thePackage)] = true
+		if p.previous != nil {
+			knownPackages[hashName(*p.previous)] = true
+		}
+	}
 
 	// first remove all symlinks to the packages themself.
 	for _, f := range files {
 		if f.Type()&os.ModeSymlink == os.ModeSymlink {
 			// if managed skip removing package
-			if p, ok := m.managedPackages[PackageName(f.Name())]; ok {
-				knownPackages[hashName(p.thePackage)] = true
+			if _, ok := m.managedPackages[PackageName(f.Name())]; ok {
 				continue
 			}
 
@@ -234,7 +317,7 @@ func (m *cloudManager) Cleanup(ctx context.Context) error {
 	// remove any remaining files in the .data dir that should not be there.
 	for _, f := range files {
 		// if managed skip removing package
-		if _, ok := knownPackages[f.Name()]; ok {
+		if _, ok := knownPackages[strings.TrimSuffix(f.Name(), manifestSuffix)]; ok {
 			continue
 		}
 
@@ -259,18 +342,26 @@ func sanitizeURLForLogs(u string) string {
 }
 
 func (m *cloudManager) loadFile(ctx context.Context, url string, p config.PackageConfig) error {
-	// TODO(): validate integrity of directory.
 	if dirExists(m.localDataPath(p)) {
-		m.logger.Debug("  Package already downloaded, skipping.")
-		return nil
+		err := verifyPackageDir(p, m.localDataPath(p))
+		if err == nil {
+			m.logger.Debug("  Package already downloaded, skipping.")
+			return nil
+		}
+		m.logger.Warnf("  Downloading package again: %s", err)
 	}
 
 	// Force redownload of package archive.
 	utils.UncheckedError(m.cleanup(p))
-	utils.UncheckedError(os.Remove(m.localNamedPath(p)))
 
 	// Download from GCS
-	_, contentType, err := m.downloadFileFromGCSURL(ctx, url, p)
+	var contentType string
+	var digest []byte
+	err := rutils.Retry(ctx, downloadRetryOptions(m.logger), func(ctx context.Context) error {
+		var err error
+		contentType, digest, err = m.downloadFileFromGCSURL(ctx, url, p)
+		return err
+	})
 	if err != nil {
 		return err
 	}
@@ -293,7 +384,7 @@ func (m *cloudManager) loadFile(ctx context.Context, url string, p config.Packag
 	}()
 
 	// unzip archive.
-	err = m.unpackFile(ctx, m.localDownloadPath(p), tmpDataPath)
+	err = unpackFile(ctx, m.localDownloadPath(p), tmpDataPath)
 	if err != nil {
 		utils.UncheckedError(m.cleanup(p))
 		return err
@@ -305,6 +396,11 @@ func (m *cloudManager) loadFile(ctx context.Context, url string, p config.Packag
 		return err
 	}
 
+	if err := writeManifest(m.localDataPath(p), digest); err != nil {
+		utils.UncheckedError(m.cleanup(p))
+		return err
+	}
+
 	return nil
 }
 
@@ -312,26 +408,28 @@ func (m *cloudManager) cleanup(p config.PackageConfig) error {
 	return multierr.Combine(
 		os.RemoveAll(m.localDataPath(p)),
 		os.Remove(m.localDownloadPath(p)),
+		os.Remove(m.localDataPath(p)+manifestSuffix),
 	)
 }
 
-func (m *cloudManager) downloadFileFromGCSURL(ctx context.Context, url string, p config.PackageConfig) (string, string, error) {
+// downloadFileFromGCSURL downloads the package archive and returns its content type and SHA-256 digest.
+func (m *cloudManager) downloadFileFromGCSURL(ctx context.Context, url string, p config.PackageConfig) (string, []byte, error) {
 	downloadPath := m.localDownloadPath(p)
 
 	getReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 
 	//nolint:bodyclose /// closed in UncheckedErrorFunc
 	resp, err := m.httpClient.Do(getReq)
 	if err != nil {
-		return "", "", err
+		return "", nil, err
 	}
 	defer utils.UncheckedErrorFunc(resp.Body.Close)
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("invalid status code %d", resp.StatusCode)
+		return "", nil, downloadStatusError(resp.StatusCode)
 	}
 
 	contentType := resp.Header.Get("Content-Type")
@@ -340,29 +438,35 @@ func (m *cloudManager) downloadFileFromGCSURL(ctx context.Context, url string, p
 	//nolint:gosec // safe
 	out, err := os.Create(downloadPath)
 	if err != nil {
-		return checksum, contentType, err
+		return contentType, nil, err
 	}
 	defer utils.UncheckedErrorFunc(out.Close)
 
 	hash := crc32Hash()
-	w := io.MultiWriter(out, hash)
+	digest := sha256.New()
+	w := io.MultiWriter(out, hash, digest)
 
 	_, err = io.CopyN(w, resp.Body, maxPackageSize)
 	if err != nil && !errors.Is(err, io.EOF) {
 		utils.UncheckedError(os.Remove(downloadPath))
-		return checksum, contentType, err
+		return contentType, nil, err
 	}
 
 	outHash := base64.URLEncoding.EncodeToString(hash.Sum(nil))
 	if outHash != checksum {
 		utils.UncheckedError(os.Remove(downloadPath))
-		return checksum, contentType, errors.Errorf("download did not match expected hash %s != %s", outHash, checksum)
+		return contentType, nil, errors.Errorf("download did not match expected hash %s != %s", outHash, checksum)
 	}
 
-	return checksum, contentType, nil
+	if err := verifyArchive(p, digest.Sum(nil)); err != nil {
+		utils.UncheckedError(os.Remove(downloadPath))
+		return contentType, nil, err
+	}
+
+	return contentType, digest.Sum(nil), nil
 }
 
-func (m *cloudManager) unpackFile(ctx context.Context, fromFile, toDir string) error {
+func unpackFile(ctx context.Context, fromFile, toDir string) error {
 	if err := os.MkdirAll(toDir, 0o700); err != nil {
 		return err
 	}
@@ -552,6 +656,26 @@ func linkFile(from, to string) error {
 	return os.Symlink(from, to)
 }
 
+// switchLink atomically points the symlink at link to target by renaming a new symlink over it.
+func switchLink(target, link string) error {
+	tmp := link + ".tmp"
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		utils.UncheckedError(os.Remove(tmp))
+		return err
+	}
+	return nil
+}
+
+func samePackageVersion(a, b config.PackageConfig) bool {
+	return a.Package == b.Package && a.Version == b.Version
+}
+
 func dirExists(dir string) bool {
 	info, err := os.Stat(dir)
 	if err != nil {
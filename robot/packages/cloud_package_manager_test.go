@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"github.com/edaniels/golog"
@@ -168,6 +169,7 @@ func TestCloud(t *testing.T) {
 
 		validatePackageDir(t, packageDir, input)
 
+		previous := input[0]
 		input[0].Version = "v2"
 		fakeServer.StorePackage(input...)
 
@@ -177,7 +179,54 @@ func TestCloud(t *testing.T) {
 		err = pm.Cleanup(ctx)
 		test.That(t, err, test.ShouldBeNil)
 
-		validatePackageDir(t, packageDir, input)
+		// the previous version is kept for rollbacks
+		validatePackageDir(t, packageDir, input, previous)
+	})
+
+	t.Run("rollback to previous version", func(t *testing.T) {
+		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
+		defer utils.UncheckedErrorFunc(pm.Close)
+
+		v1 := []config.PackageConfig{{Name: "some-name-1", Package: "org1/test-model", Version: "v1"}}
+		v2 := []config.PackageConfig{{Name: "some-name-1", Package: "org1/test-model", Version: "v2"}}
+		v3 := []config.PackageConfig{{Name: "some-name-1", Package: "org1/test-model", Version: "v3"}}
+		fakeServer.StorePackage(v1[0], v2[0], v3[0])
+
+		err = pm.Sync(ctx, v1)
+		test.That(t, err, test.ShouldBeNil)
+		err = pm.Rollback(ctx, "some-name-1")
+		test.That(t, errors.Is(err, ErrNoPreviousVersion), test.ShouldBeTrue)
+
+		// the previous version is kept by cleanup
+		err = pm.Sync(ctx, v2)
+		test.That(t, err, test.ShouldBeNil)
+		err = pm.Cleanup(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = os.Stat(path.Join(packageDir, ".data", hashName(v1[0])))
+		test.That(t, err, test.ShouldBeNil)
+
+		err = pm.Rollback(ctx, "some-name-1")
+		test.That(t, err, test.ShouldBeNil)
+		err = pm.Cleanup(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		validatePackageDir(t, packageDir, v1)
+		putils.ValidateContentsOfPPackage(t, path.Join(packageDir, "some-name-1"))
+
+		// syncing the version rolled back from keeps the previous version
+		err = pm.Sync(ctx, v2)
+		test.That(t, err, test.ShouldBeNil)
+		err = pm.Cleanup(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		validatePackageDir(t, packageDir, v1)
+
+		err = pm.Sync(ctx, v3)
+		test.That(t, err, test.ShouldBeNil)
+		err = pm.Cleanup(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		validatePackageDir(t, packageDir, v3, v1[0])
+
+		err = pm.Rollback(ctx, "missing")
+		test.That(t, errors.Is(err, ErrPackageMissing), test.ShouldBeTrue)
 	})
 
 	t.Run("invalid checksum", func(t *testing.T) {
@@ -198,6 +247,51 @@ func TestCloud(t *testing.T) {
 		validatePackageDir(t, packageDir, []config.PackageConfig{})
 	})
 
+	t.Run("mismatched sha256", func(t *testing.T) {
+		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
+		defer utils.UncheckedErrorFunc(pm.Close)
+
+		input := []config.PackageConfig{
+			{Name: "some-name-1", Package: "org1/test-model", Version: "v1", SHA256: strings.Repeat("0", 64)},
+		}
+		fakeServer.StorePackage(input...)
+
+		err = pm.Sync(ctx, input)
+		test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+		validatePackageDir(t, packageDir, []config.PackageConfig{})
+	})
+
+	t.Run("changed package is downloaded again", func(t *testing.T) {
+		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
+		defer utils.UncheckedErrorFunc(pm.Close)
+
+		input := []config.PackageConfig{
+			{Name: "some-name-1", Package: "org1/test-model", Version: "v1"},
+		}
+		fakeServer.StorePackage(input...)
+
+		err = pm.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		p, err := pm.RefPath("${packages.some-name-1}/sub-dir/sub-file.txt")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, os.WriteFile(p, []byte("tampered"), 0o600), test.ShouldBeNil)
+
+		_, err = pm.PackagePath(PackageName(input[0].Name))
+		test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+		err = pm.Sync(ctx, input)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, downloadCount := fakeServer.RequestCounts()
+		test.That(t, downloadCount, test.ShouldEqual, 2)
+		validatePackageDir(t, packageDir, input)
+		putils.ValidateContentsOfPPackage(t, path.Join(packageDir, input[0].Name))
+		_, err = pm.PackagePath(PackageName(input[0].Name))
+		test.That(t, err, test.ShouldBeNil)
+	})
+
 	t.Run("invalid gcs download", func(t *testing.T) {
 		packageDir, pm := newPackageManager(t, client, fakeServer, logger)
 		defer utils.UncheckedErrorFunc(pm.Close)
@@ -235,7 +329,9 @@ func TestCloud(t *testing.T) {
 	})
 }
 
-func validatePackageDir(t *testing.T, dir string, input []config.PackageConfig) {
+// validatePackageDir checks that dir holds exactly the given packages, along with the data of the
+// previous versions kept for rollbacks.
+func validatePackageDir(t *testing.T, dir string, input []config.PackageConfig, previous ...config.PackageConfig) {
 	t.Helper()
 
 	// create maps to make lookups easier.
@@ -246,6 +342,10 @@ func validatePackageDir(t *testing.T, dir string, input []config.PackageConfig)
 		byPackageHash[hashName(p)] = &p
 		byLogicalName[p.Name] = &p
 	}
+	for _, pI := range previous {
+		p := pI
+		byPackageHash[hashName(p)] = &p
+	}
 
 	// check all known packages exist and are linked to the correct package dir.
 	for _, p := range input {
@@ -294,7 +394,7 @@ func validatePackageDir(t *testing.T, dir string, input []config.PackageConfig)
 	test.That(t, err, test.ShouldBeNil)
 
 	for _, f := range files {
-		if _, ok := byPackageHash[f.Name()]; ok {
+		if _, ok := byPackageHash[strings.TrimSuffix(f.Name(), manifestSuffix)]; ok {
 			continue
 		}
 		t.Errorf("found unknown file in package data dir %s", f.Name())
@@ -0,0 +1,144 @@
+package packages
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+)
+
+// manifestSuffix is appended to the directory of an unpacked package to name the file holding
+// the digest of its contents.
+const manifestSuffix = ".manifest"
+
+// ErrPackageIntegrity is returned when a package does not match its checksum or signature.
+var ErrPackageIntegrity = errors.New("package failed integrity check")
+
+// verifyArchive checks the SHA-256 digest of a downloaded package archive against the checksum
+// and signature in its config, if any.
+func verifyArchive(p config.PackageConfig, digest []byte) error {
+	if p.SHA256 != "" {
+		if sum := hex.EncodeToString(digest); !strings.EqualFold(sum, p.SHA256) {
+			return errors.Wrapf(ErrPackageIntegrity, "download did not match expected hash %s != %s", sum, p.SHA256)
+		}
+	}
+	if p.Signature == "" {
+		return nil
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(p.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return errors.Wrap(ErrPackageIntegrity, "invalid public key")
+	}
+	signature, err := base64.StdEncoding.DecodeString(p.Signature)
+	if err != nil {
+		return errors.Wrap(ErrPackageIntegrity, "invalid signature")
+	}
+	if !ed25519.Verify(publicKey, digest, signature) {
+		return errors.Wrap(ErrPackageIntegrity, "signature does not match download")
+	}
+	return nil
+}
+
+// dirDigest returns a digest over the names, types and contents of everything in dir.
+func dirDigest(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00", filepath.ToSlash(rel), d.Type())
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "%s\x00", target)
+		case d.Type().IsRegular():
+			//nolint:gosec
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer utils.UncheckedErrorFunc(f.Close)
+			n, err := io.Copy(hash, f)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hash, "\x00%d\x00", n)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeManifest records the digest of an unpacked package, along with the SHA-256 digest of the
+// archive it was unpacked from if any, so that later uses of the package can detect changes to it.
+func writeManifest(dir string, archiveDigest []byte) error {
+	digest, err := dirDigest(dir)
+	if err != nil {
+		return err
+	}
+	manifest := fmt.Sprintf("%s\n%s\n", digest, hex.EncodeToString(archiveDigest))
+	return os.WriteFile(dir+manifestSuffix, []byte(manifest), 0o600)
+}
+
+// verifyManifest checks that an unpacked package still matches the digest recorded for it and
+// returns the recorded archive digest.
+func verifyManifest(dir string) ([]byte, error) {
+	//nolint:gosec
+	manifest, err := os.ReadFile(dir + manifestSuffix)
+	if err != nil {
+		return nil, errors.Wrap(ErrPackageIntegrity, "package has no manifest")
+	}
+	lines := strings.Split(strings.TrimSpace(string(manifest)), "\n")
+	digest, err := dirDigest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if digest != lines[0] {
+		return nil, errors.Wrap(ErrPackageIntegrity, "package contents changed since it was unpacked")
+	}
+	if len(lines) < 2 {
+		return nil, nil
+	}
+	archiveDigest, err := hex.DecodeString(lines[1])
+	if err != nil {
+		return nil, errors.Wrap(ErrPackageIntegrity, "invalid manifest")
+	}
+	return archiveDigest, nil
+}
+
+// verifyPackageDir checks that a previously unpacked package is unchanged and that the archive
+// it came from satisfies the checksum and signature currently configured for the package.
+func verifyPackageDir(p config.PackageConfig, dir string) error {
+	archiveDigest, err := verifyManifest(dir)
+	if err != nil {
+		return err
+	}
+	if p.SHA256 == "" && p.Signature == "" {
+		return nil
+	}
+	if len(archiveDigest) == 0 {
+		return errors.Wrap(ErrPackageIntegrity, "package has no recorded archive digest")
+	}
+	return verifyArchive(p, archiveDigest)
+}
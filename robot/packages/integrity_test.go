@@ -0,0 +1,133 @@
+package packages
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+)
+
+func TestVerifyArchive(t *testing.T) {
+	digest := sha256.Sum256([]byte("package"))
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, digest[:]))
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	test.That(t, verifyArchive(config.PackageConfig{}, digest[:]), test.ShouldBeNil)
+	test.That(t, verifyArchive(config.PackageConfig{Signature: signature, PublicKey: encodedKey}, digest[:]), test.ShouldBeNil)
+
+	otherDigest := sha256.Sum256([]byte("tampered"))
+	err = verifyArchive(config.PackageConfig{Signature: signature, PublicKey: encodedKey}, otherDigest[:])
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "signature does not match")
+
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	err = verifyArchive(config.PackageConfig{Signature: signature, PublicKey: base64.StdEncoding.EncodeToString(otherKey)}, digest[:])
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+	err = verifyArchive(config.PackageConfig{SHA256: "00"}, digest[:])
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "did not match expected hash")
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	test.That(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("contents"), 0o600), test.ShouldBeNil)
+	archiveDigest := sha256.Sum256([]byte("archive"))
+
+	_, err := verifyManifest(dir)
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+	test.That(t, writeManifest(dir, archiveDigest[:]), test.ShouldBeNil)
+	recorded, err := verifyManifest(dir)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, recorded, test.ShouldResemble, archiveDigest[:])
+
+	// the recorded archive digest is checked against the configured checksum
+	test.That(t, verifyPackageDir(config.PackageConfig{SHA256: hex.EncodeToString(archiveDigest[:])}, dir), test.ShouldBeNil)
+	err = verifyPackageDir(config.PackageConfig{SHA256: strings.Repeat("0", 64)}, dir)
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+	test.That(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("content"), 0o600), test.ShouldBeNil)
+	_, err = verifyManifest(dir)
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+	test.That(t, writeManifest(dir, nil), test.ShouldBeNil)
+	test.That(t, verifyPackageDir(config.PackageConfig{}, dir), test.ShouldBeNil)
+	err = verifyPackageDir(config.PackageConfig{SHA256: hex.EncodeToString(archiveDigest[:])}, dir)
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+
+	test.That(t, os.WriteFile(filepath.Join(dir, "extra.txt"), nil, 0o600), test.ShouldBeNil)
+	_, err = verifyManifest(dir)
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+}
+
+func TestSourceManagerIntegrity(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+
+	tarball, err := os.ReadFile(filepath.Join("testutils", "example.tar.gz"))
+	test.That(t, err, test.ShouldBeNil)
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, err := w.Write(tarball)
+		utils.UncheckedError(err)
+	}))
+	defer server.Close()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	digest := sha256.Sum256(tarball)
+	input := []config.PackageConfig{{
+		Name:      "tarball",
+		Package:   server.URL + "/model.tar.gz",
+		Source:    config.PackageSourceHTTP,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, digest[:])),
+		PublicKey: base64.StdEncoding.EncodeToString(publicKey),
+	}}
+
+	pm := NewSourceManager(NewNoopManager(), t.TempDir(), logger)
+	defer utils.UncheckedErrorFunc(pm.Close)
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+	test.That(t, downloads, test.ShouldEqual, 1)
+	dir, err := pm.PackagePath("tarball")
+	test.That(t, err, test.ShouldBeNil)
+
+	// a package changed on disk is fetched again
+	p, err := pm.RefPath("${packages.tarball}/sub-dir/sub-file.txt")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, os.WriteFile(p, []byte("tampered"), 0o600), test.ShouldBeNil)
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+	test.That(t, downloads, test.ShouldEqual, 2)
+	contents, err := os.ReadFile(p)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents), test.ShouldNotEqual, "tampered")
+
+	// a package signed with another key is not exposed
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	test.That(t, err, test.ShouldBeNil)
+	input[0].PublicKey = base64.StdEncoding.EncodeToString(otherKey)
+	err = pm.Sync(ctx, input)
+	test.That(t, errors.Is(err, ErrPackageIntegrity), test.ShouldBeTrue)
+	p, err = pm.PackagePath("tarball")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldNotEqual, dir)
+}
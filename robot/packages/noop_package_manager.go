@@ -41,3 +41,8 @@ func (m *noopManager) Sync(ctx context.Context, packages []config.PackageConfig)
 func (m *noopManager) Cleanup(ctx context.Context) error {
 	return nil
 }
+
+// Rollback does nothing since there are no package versions to roll back to.
+func (m *noopManager) Rollback(ctx context.Context, name PackageName) error {
+	return ErrNoPreviousVersion
+}
@@ -3,6 +3,7 @@ package packages
 
 import (
 	"context"
+	"path"
 
 	"github.com/docker/go-units"
 	"github.com/pkg/errors"
@@ -27,6 +28,9 @@ var ErrPackageMissing = errors.New("package missing")
 // ErrInvalidPackageRef is an error when a invalid package reference syntax.
 var ErrInvalidPackageRef = errors.New("invalid package reference")
 
+// ErrNoPreviousVersion is an error when a package has no previous version to roll back to.
+var ErrNoPreviousVersion = errors.New("no previous package version")
+
 // Manager provides a managed interface for looking up package paths. This is separated from ManagerSyncer to avoid passing
 // the full sync interface to all components.
 type Manager interface {
@@ -55,6 +59,29 @@ type ManagerSyncer interface {
 	Sync(ctx context.Context, packages []config.PackageConfig) error
 
 	// Cleanup removes any unused packages known to the Manager that are no longer used. It removes the packages from the file system.
+	// The version of each package used before the last sync that changed it is kept for Rollback.
 	// Returns any errors during the cleanup process.
 	Cleanup(ctx context.Context) error
+
+	// Rollback switches a package back to the version it used before the last sync changed it, for example when the new version
+	// fails to load. Later syncs keep the previous version until the package is configured with another version.
+	// Returns ErrNoPreviousVersion if there is no version to roll back to.
+	Rollback(ctx context.Context, name PackageName) error
+}
+
+// packageRefPath resolves a path with a package reference using the package paths of m.
+func packageRefPath(m Manager, refPath string) (string, error) {
+	ref := config.GetPackageReference(refPath)
+
+	// If no reference just return original path.
+	if ref == nil {
+		return refPath, nil
+	}
+
+	packagePath, err := m.PackagePath(PackageName(ref.Package))
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(packagePath, path.Clean(ref.PathInPackage)), nil
 }
@@ -0,0 +1,424 @@
+package packages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	rutils "go.viam.com/rdk/utils"
+)
+
+var (
+	_ Manager       = (*sourceManager)(nil)
+	_ ManagerSyncer = (*sourceManager)(nil)
+)
+
+// sourcesDirName is the directory within the packages directory that packages fetched from
+// sources other than the package service are kept in.
+const sourcesDirName = ".sources"
+
+type sourceManager struct {
+	cloud      ManagerSyncer
+	sourcesDir string
+	httpClient http.Client
+
+	// packages holds the packages not handled by the cloud manager.
+	packages map[PackageName]*sourcePackage
+	mu       sync.RWMutex
+
+	logger golog.Logger
+}
+
+// A sourcePackage is the directory a package is in, along with the directory it was in before
+// the last sync changed it.
+type sourcePackage struct {
+	dir      string
+	previous string
+	// rolledBack is the directory the package was rolled back from, which syncs do not switch to again.
+	rolledBack string
+}
+
+// NewSourceManager returns a manager that syncs packages from every config.PackageSource.
+// Packages from the package service are synced by the given cloud manager, which may be a noop
+// manager when the robot is offline. Local packages are used in place, and packages from HTTP
+// and git sources are fetched into packagesDir once per source, package and version, where the
+// version of a git package is the commit its ref points at. Without a packagesDir, only local
+// packages are supported.
+func NewSourceManager(cloud ManagerSyncer, packagesDir string, logger golog.Logger) ManagerSyncer {
+	var sourcesDir string
+	if packagesDir != "" {
+		sourcesDir = filepath.Join(packagesDir, sourcesDirName)
+	}
+	return &sourceManager{
+		cloud:      cloud,
+		sourcesDir: sourcesDir,
+		httpClient: http.Client{Timeout: time.Minute * 30},
+		packages:   make(map[PackageName]*sourcePackage),
+		logger:     logger.Named("package_manager"),
+	}
+}
+
+// PackagePath returns the package if it exists and already download. If it does not exist it returns a ErrPackageMissing error.
+func (m *sourceManager) PackagePath(name PackageName) (string, error) {
+	m.mu.RLock()
+	p, ok := m.packages[name]
+	m.mu.RUnlock()
+	if !ok {
+		return m.cloud.PackagePath(name)
+	}
+	// fetched packages may have changed since they were synced, so they are checked every time they
+	// are handed out. Local packages are used in place.
+	if m.isFetched(p.dir) {
+		if _, err := verifyManifest(p.dir); err != nil {
+			return "", errors.Wrapf(err, "package %s", name)
+		}
+	}
+	return p.dir, nil
+}
+
+func (m *sourceManager) RefPath(refPath string) (string, error) {
+	return packageRefPath(m, refPath)
+}
+
+// Close manager.
+func (m *sourceManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.httpClient.CloseIdleConnections()
+	return m.cloud.Close()
+}
+
+// Sync syncs all given packages, passing those from the package service on to the cloud manager.
+func (m *sourceManager) Sync(ctx context.Context, packages []config.PackageConfig) error {
+	var outErr error
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var cloudPackages []config.PackageConfig
+	newPackages := make(map[PackageName]*sourcePackage, len(packages))
+	for _, p := range packages {
+		if p.Source == config.PackageSourceCloud {
+			cloudPackages = append(cloudPackages, p)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return multierr.Append(outErr, err)
+		}
+
+		existing := m.packages[PackageName(p.Name)]
+		if p.Source == config.PackageSourceGit {
+			resolved, err := resolveGitRef(ctx, p)
+			if err != nil && existing != nil {
+				m.logger.Warnf("Failed resolving git package %s, keeping %s: %s", p.Name, existing.dir, err)
+				newPackages[PackageName(p.Name)] = existing
+				continue
+			}
+			if err != nil {
+				m.logger.Errorf("Failed resolving git package %s, %s", p.Name, err)
+				outErr = multierr.Append(outErr, errors.Wrapf(err, "failed syncing %s package %s", p.Source, p.Name))
+				continue
+			}
+			p = resolved
+		}
+		if existing != nil && existing.rolledBack != "" && existing.rolledBack == m.packageDir(p) {
+			m.logger.Warnf("Package %s was rolled back from %s, keeping %s", p.Name, existing.rolledBack, existing.dir)
+			newPackages[PackageName(p.Name)] = existing
+			continue
+		}
+
+		start := time.Now()
+		m.logger.Debugf("Starting %s package sync %s", p.Source, p.Name)
+		dir, err := m.syncPackage(ctx, p)
+		if err != nil {
+			m.logger.Errorf("Failed syncing %s package %s, %s", p.Source, p.Name, err)
+			outErr = multierr.Append(outErr, errors.Wrapf(err, "failed syncing %s package %s", p.Source, p.Name))
+			continue
+		}
+		switch {
+		case existing == nil:
+			newPackages[PackageName(p.Name)] = &sourcePackage{dir: dir}
+		case existing.dir == dir:
+			newPackages[PackageName(p.Name)] = existing
+		default:
+			newPackages[PackageName(p.Name)] = &sourcePackage{dir: dir, previous: existing.dir}
+		}
+		m.logger.Debugf("  Sync complete after %dms", time.Since(start).Milliseconds())
+	}
+	m.packages = newPackages
+
+	return multierr.Combine(outErr, m.cloud.Sync(ctx, cloudPackages))
+}
+
+// Rollback switches a package back to the directory it was in before the last sync changed it.
+// Packages from the package service are rolled back by the cloud manager.
+func (m *sourceManager) Rollback(ctx context.Context, name PackageName) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.packages[name]
+	if !ok {
+		return m.cloud.Rollback(ctx, name)
+	}
+	if p.previous == "" {
+		return ErrNoPreviousVersion
+	}
+	// fetched packages must still match their manifest, local ones only have to exist.
+	if m.isFetched(p.previous) {
+		if _, err := verifyManifest(p.previous); err != nil {
+			return errors.Wrapf(err, "cannot roll back to %s", p.previous)
+		}
+	} else if !dirExists(p.previous) {
+		return errors.Errorf("cannot roll back to %q, it is not a directory", p.previous)
+	}
+	m.logger.Infof("Rolled back package %s from %s to %s", name, p.dir, p.previous)
+	m.packages[name] = &sourcePackage{dir: p.previous, rolledBack: p.dir}
+	return nil
+}
+
+// Cleanup removes all fetched packages that are no longer used, except for the directory each
+// package was in before the last sync changed it.
+func (m *sourceManager) Cleanup(ctx context.Context) error {
+	allErrors := m.cloud.Cleanup(ctx)
+	if m.sourcesDir == "" {
+		return allErrors
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	files, err := os.ReadDir(m.sourcesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return allErrors
+		}
+		return multierr.Combine(allErrors, err)
+	}
+	inUse := make(map[string]bool, len(m.packages))
+	for _, p := range m.packages {
+		inUse[p.dir] = true
+		if p.previous != "" {
+			inUse[p.previous] = true
+		}
+	}
+	for _, f := range files {
+		dir := filepath.Join(m.sourcesDir, f.Name())
+		if inUse[strings.TrimSuffix(dir, manifestSuffix)] {
+			continue
+		}
+		m.logger.Debugf("Cleaning up unused package %s", f.Name())
+		if err := os.RemoveAll(dir); err != nil {
+			allErrors = multierr.Append(allErrors, err)
+		}
+	}
+	return allErrors
+}
+
+// isFetched returns whether dir holds a package fetched into the sources directory.
+func (m *sourceManager) isFetched(dir string) bool {
+	return m.sourcesDir != "" && filepath.Dir(dir) == m.sourcesDir
+}
+
+// syncPackage makes sure the package is available locally and returns its directory.
+func (m *sourceManager) syncPackage(ctx context.Context, p config.PackageConfig) (string, error) {
+	if p.Source == config.PackageSourceLocal {
+		if !dirExists(p.Package) {
+			return "", errors.Errorf("%q is not a directory", p.Package)
+		}
+		return p.Package, nil
+	}
+
+	if m.sourcesDir == "" {
+		return "", errors.New("no package directory to fetch packages into")
+	}
+	dir := m.packageDir(p)
+	if dirExists(dir) {
+		err := verifyPackageDir(p, dir)
+		if err == nil {
+			m.logger.Debug("  Package already fetched, skipping.")
+			return dir, nil
+		}
+		m.logger.Warnf("  Fetching package again: %s", err)
+		if err := multierr.Combine(os.RemoveAll(dir), os.RemoveAll(dir+manifestSuffix)); err != nil {
+			return "", err
+		}
+	}
+	if err := os.MkdirAll(m.sourcesDir, 0o700); err != nil {
+		return "", err
+	}
+
+	// fetch into a temp directory to ensure we do an atomic rename once finished.
+	tmpDir, err := os.MkdirTemp(m.sourcesDir, "*.tmp")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create temp data dir path")
+	}
+	defer func() {
+		utils.UncheckedError(os.RemoveAll(tmpDir))
+	}()
+
+	var archiveDigest []byte
+	switch p.Source {
+	case config.PackageSourceHTTP:
+		archiveDigest, err = rutils.RetryValue(ctx, downloadRetryOptions(m.logger), func(ctx context.Context) ([]byte, error) {
+			return m.fetchHTTP(ctx, p, tmpDir)
+		})
+	case config.PackageSourceGit:
+		err = fetchGit(ctx, p, tmpDir)
+	default:
+		err = errors.Errorf("unknown package source %q", p.Source)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return "", err
+	}
+	if err := writeManifest(dir, archiveDigest); err != nil {
+		utils.UncheckedError(os.RemoveAll(dir))
+		return "", err
+	}
+	return dir, nil
+}
+
+// fetchHTTP downloads a gzipped tarball, unpacks it into toDir and returns its SHA-256 digest.
+func (m *sourceManager) fetchHTTP(ctx context.Context, p config.PackageConfig, toDir string) ([]byte, error) {
+	archive, err := os.CreateTemp(m.sourcesDir, "*.download")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		utils.UncheckedError(archive.Close())
+		utils.UncheckedError(os.Remove(archive.Name()))
+	}()
+
+	getReq, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Package, nil)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:bodyclose /// closed in UncheckedErrorFunc
+	resp, err := m.httpClient.Do(getReq)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(resp.Body.Close)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, downloadStatusError(resp.StatusCode)
+	}
+
+	digest := sha256.New()
+	n, err := io.CopyN(io.MultiWriter(archive, digest), resp.Body, maxPackageSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if resp.ContentLength >= 0 && n != resp.ContentLength {
+		return nil, errors.Wrapf(ErrPackageIntegrity, "download was truncated at %d of %d bytes", n, resp.ContentLength)
+	}
+	if err := verifyArchive(p, digest.Sum(nil)); err != nil {
+		return nil, err
+	}
+
+	// a download that unpacks badly would only do so again
+	if err := unpackFile(ctx, archive.Name(), toDir); err != nil {
+		return nil, rutils.Permanent(err)
+	}
+	return digest.Sum(nil), nil
+}
+
+// gitCommitPattern matches full git commit hashes, which always refer to the same contents.
+var gitCommitPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// resolveGitRef returns the package with its version resolved to the commit its branch, tag or
+// HEAD currently points at, so that a package following a branch is fetched again into a new
+// directory once the branch moves on. Versions that are already full commit hashes are kept.
+func resolveGitRef(ctx context.Context, p config.PackageConfig) (config.PackageConfig, error) {
+	ref := p.Version
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if gitCommitPattern.MatchString(ref) {
+		return p, nil
+	}
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, "git", "ls-remote", "--", p.Package, ref)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return p, errors.Wrapf(err, "git ls-remote: %s", strings.TrimSpace(stderr.String()))
+	}
+	commits := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 {
+			commits[fields[1]] = fields[0]
+		}
+	}
+	// a short ref is looked up in the same order git uses, preferring the commit an annotated tag
+	// points at over the tag itself.
+	for _, name := range []string{
+		ref, "refs/" + ref, "refs/tags/" + ref + "^{}", "refs/tags/" + ref, "refs/heads/" + ref,
+	} {
+		if commit, ok := commits[name]; ok {
+			p.Version = commit
+			return p, nil
+		}
+	}
+	return p, errors.Errorf("no ref %q in %s", ref, p.Package)
+}
+
+// fetchGit checks out the package's commit of a git repository into toDir without its history.
+// The package's version must already be resolved by resolveGitRef.
+func fetchGit(ctx context.Context, p config.PackageConfig, toDir string) error {
+	ref := p.Version
+	if ref == "" {
+		ref = "HEAD"
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", p.Package},
+		{"fetch", "-q", "--depth", "1", "origin", ref},
+		{"checkout", "-q", "FETCH_HEAD"},
+	} {
+		//nolint:gosec
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", toDir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "git %s: %s", args[0], strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// packageDir returns the directory a package is in once synced.
+func (m *sourceManager) packageDir(p config.PackageConfig) string {
+	if p.Source == config.PackageSourceLocal {
+		return p.Package
+	}
+	return filepath.Join(m.sourcesDir, sourceDirName(p))
+}
+
+// sourceDirName names the directory a package is fetched into after everything that determines
+// its contents.
+func sourceDirName(p config.PackageConfig) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		string(p.Source), p.Package, p.Version, strings.ToLower(p.SHA256), p.Signature, p.PublicKey,
+	}, "\x00")))
+	return fmt.Sprintf("%s-%s", p.Name, hex.EncodeToString(sum[:8]))
+}
@@ -0,0 +1,176 @@
+package packages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/edaniels/golog"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+)
+
+func TestSourceManager(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+
+	tarball, err := os.ReadFile(filepath.Join("testutils", "example.tar.gz"))
+	test.That(t, err, test.ShouldBeNil)
+	sum := sha256.Sum256(tarball)
+	var downloads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		_, err := w.Write(tarball)
+		utils.UncheckedError(err)
+	}))
+	defer server.Close()
+
+	packageDir := t.TempDir()
+	pm := NewSourceManager(NewNoopManager(), packageDir, logger)
+	defer utils.UncheckedErrorFunc(pm.Close)
+
+	localDir := t.TempDir()
+	input := []config.PackageConfig{
+		{Name: "local", Package: localDir, Source: config.PackageSourceLocal},
+		{Name: "tarball", Package: server.URL + "/model.tar.gz", Source: config.PackageSourceHTTP, SHA256: hex.EncodeToString(sum[:])},
+		{Name: "cloud", Package: "org1/test-model", Version: "v1"},
+	}
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+
+	p, err := pm.PackagePath("local")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, localDir)
+
+	p, err = pm.RefPath("${packages.tarball}/sub-dir/sub-file.txt")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = os.Stat(p)
+	test.That(t, err, test.ShouldBeNil)
+
+	// cloud packages are left to the cloud manager
+	p, err = pm.PackagePath("cloud")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, "cloud")
+
+	// fetched packages are reused
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+	test.That(t, downloads, test.ShouldEqual, 1)
+
+	// a mismatched checksum fails the package without affecting the others
+	input[1].SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+	err = pm.Sync(ctx, input)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "did not match expected hash")
+	p, err = pm.PackagePath("local")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, localDir)
+
+	// unused fetched packages are removed
+	test.That(t, pm.Cleanup(ctx), test.ShouldBeNil)
+	files, err := os.ReadDir(filepath.Join(packageDir, sourcesDirName))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, files, test.ShouldBeEmpty)
+
+	err = pm.Sync(ctx, []config.PackageConfig{{Name: "missing", Package: filepath.Join(localDir, "nope"), Source: config.PackageSourceLocal}})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "is not a directory")
+}
+
+func TestSourceManagerRollback(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+
+	pm := NewSourceManager(NewNoopManager(), t.TempDir(), logger)
+	defer utils.UncheckedErrorFunc(pm.Close)
+
+	oldDir := t.TempDir()
+	newDir := t.TempDir()
+	oldInput := []config.PackageConfig{{Name: "local", Package: oldDir, Source: config.PackageSourceLocal}}
+	newInput := []config.PackageConfig{{Name: "local", Package: newDir, Source: config.PackageSourceLocal}}
+
+	test.That(t, pm.Sync(ctx, oldInput), test.ShouldBeNil)
+	err := pm.Rollback(ctx, "local")
+	test.That(t, errors.Is(err, ErrNoPreviousVersion), test.ShouldBeTrue)
+
+	test.That(t, pm.Sync(ctx, newInput), test.ShouldBeNil)
+	test.That(t, pm.Rollback(ctx, "local"), test.ShouldBeNil)
+	p, err := pm.PackagePath("local")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, oldDir)
+
+	// syncing the directory rolled back from keeps the previous one
+	test.That(t, pm.Sync(ctx, newInput), test.ShouldBeNil)
+	p, err = pm.PackagePath("local")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, oldDir)
+
+	// packages from the package service are rolled back by the cloud manager
+	err = pm.Rollback(ctx, "cloud")
+	test.That(t, errors.Is(err, ErrNoPreviousVersion), test.ShouldBeTrue)
+}
+
+func TestSourceManagerGit(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+
+	repoDir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repoDir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		test.That(t, string(out), test.ShouldBeEmpty)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	commit := func(contents string) {
+		t.Helper()
+		test.That(t, os.WriteFile(filepath.Join(repoDir, "file.txt"), []byte(contents), 0o600), test.ShouldBeNil)
+		git("add", "file.txt")
+		git("commit", "-q", "-m", contents)
+	}
+	git("init", "-q", "-b", "main")
+	commit("one")
+
+	pm := NewSourceManager(NewNoopManager(), t.TempDir(), logger)
+	defer utils.UncheckedErrorFunc(pm.Close)
+
+	input := []config.PackageConfig{{Name: "repo", Package: repoDir, Source: config.PackageSourceGit, Version: "main"}}
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+	firstDir, err := pm.PackagePath("repo")
+	test.That(t, err, test.ShouldBeNil)
+	contents, err := os.ReadFile(filepath.Join(firstDir, "file.txt"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents), test.ShouldEqual, "one")
+
+	// a branch that moved on is fetched again
+	commit("two")
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+	secondDir, err := pm.PackagePath("repo")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, secondDir, test.ShouldNotEqual, firstDir)
+	contents, err = os.ReadFile(filepath.Join(secondDir, "file.txt"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(contents), test.ShouldEqual, "two")
+	test.That(t, pm.Rollback(ctx, "repo"), test.ShouldBeNil)
+	p, err := pm.PackagePath("repo")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, firstDir)
+
+	// a repository that cannot be reached keeps the package where it is
+	test.That(t, os.RemoveAll(filepath.Join(repoDir, ".git")), test.ShouldBeNil)
+	test.That(t, pm.Sync(ctx, input), test.ShouldBeNil)
+	p, err = pm.PackagePath("repo")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, p, test.ShouldEqual, firstDir)
+
+	err = pm.Sync(ctx, []config.PackageConfig{{Name: "other", Package: repoDir, Source: config.PackageSourceGit}})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "git ls-remote")
+}
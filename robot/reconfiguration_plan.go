@@ -0,0 +1,49 @@
+package robot
+
+import (
+	"context"
+
+	"go.viam.com/rdk/config"
+)
+
+// ReconfigurationAction is what reconfiguring a robot would do to one part of it.
+type ReconfigurationAction string
+
+// The actions of a ReconfigurationPlan.
+const (
+	// ReconfigurationAdd is planned for parts that are not running yet.
+	ReconfigurationAdd ReconfigurationAction = "add"
+	// ReconfigurationRemove is planned for parts that would be closed and not replaced.
+	ReconfigurationRemove ReconfigurationAction = "remove"
+	// ReconfigurationRebuild is planned for parts that would be closed and built again.
+	ReconfigurationRebuild ReconfigurationAction = "rebuild"
+	// ReconfigurationUpdate is planned for parts that would take the new config while keeping
+	// their existing proxies, so that clients holding them are not interrupted.
+	ReconfigurationUpdate ReconfigurationAction = "update"
+)
+
+// A ReconfigurationPlan describes what reconfiguring a robot with a config would do.
+type ReconfigurationPlan struct {
+	// Changes are ordered removals first, then in the order of the config.
+	Changes []ReconfigurationChange `json:"changes"`
+	// NetworkChanged is whether the network settings changed, which only take effect when the
+	// robot is restarted.
+	NetworkChanged bool `json:"network_changed"`
+}
+
+// A ReconfigurationChange is what reconfiguring would do to one component, service, remote,
+// process or package, and why.
+type ReconfigurationChange struct {
+	// Name is the resource name of components and services, and the name or id of the others.
+	Name string `json:"name"`
+	// Kind is one of "component", "service", "remote", "process" or "package".
+	Kind   string                `json:"kind"`
+	Action ReconfigurationAction `json:"action"`
+	Reason string                `json:"reason"`
+}
+
+// A ReconfigurationPlanner can compute what reconfiguring with a config would do without doing it.
+type ReconfigurationPlanner interface {
+	// PlanReconfiguration returns the plan of reconfiguring with the given config.
+	PlanReconfiguration(ctx context.Context, newConfig *config.Config) (*ReconfigurationPlan, error)
+}
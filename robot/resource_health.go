@@ -0,0 +1,38 @@
+package robot
+
+import (
+	"time"
+
+	"go.viam.com/rdk/resource"
+)
+
+// ResourceHealthEventType is the kind of a ResourceHealthEvent.
+type ResourceHealthEventType string
+
+// The kinds of resource health events.
+const (
+	// ResourceUnhealthy is emitted when a resource failed its health check or did not answer in time.
+	ResourceUnhealthy ResourceHealthEventType = "unhealthy"
+	// ResourceRestarted is emitted when an unhealthy resource was rebuilt.
+	ResourceRestarted ResourceHealthEventType = "restarted"
+	// ResourceRestartFailed is emitted when rebuilding an unhealthy resource failed.
+	ResourceRestartFailed ResourceHealthEventType = "restart_failed"
+)
+
+// A ResourceHealthEvent describes something a robot's resource watchdog observed or did.
+type ResourceHealthEvent struct {
+	Name resource.Name
+	Type ResourceHealthEventType
+	// Err is the error that caused the event, if any.
+	Err  error
+	Time time.Time
+	// Attempt counts the restarts of the resource since it was last healthy.
+	Attempt int
+}
+
+// A ResourceHealthWatcher reports changes in the health of a robot's resources.
+type ResourceHealthWatcher interface {
+	// SubscribeResourceHealth returns a channel of health events and a function to unsubscribe.
+	// Events are dropped rather than blocking if the channel is full.
+	SubscribeResourceHealth(buffer int) (<-chan ResourceHealthEvent, func())
+}
@@ -0,0 +1,14 @@
+package robot
+
+import (
+	"go.uber.org/zap/zapcore"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A ResourceLogLevelSetter can change the log levels of a robot's resources while they run.
+type ResourceLogLevelSetter interface {
+	// SetResourceLogLevel overrides the log level of a resource, including any level set for it
+	// in the config, until it is called again for the resource. A nil level removes the override.
+	SetResourceLogLevel(name resource.Name, level *zapcore.Level)
+}
@@ -141,6 +141,76 @@ type RemoteRobot interface {
 	Connected() bool
 }
 
+// Possible states of a node in a ResourceGraph.
+const (
+	ResourceStateReady       = "ready"
+	ResourceStateFailed      = "failed"
+	ResourceStateUnresolved  = "unresolved"
+	ResourceStateUnavailable = "unavailable"
+)
+
+// A ResourceGraph is a snapshot of the dependencies between the resources of a robot.
+type ResourceGraph struct {
+	Nodes []ResourceGraphNode `json:"nodes"`
+	// Edges point from a resource to a resource it depends on.
+	Edges []ResourceGraphEdge `json:"edges"`
+}
+
+// A ResourceGraphNode describes a single resource in a ResourceGraph, where its
+// configuration came from and what state it is currently in.
+type ResourceGraphNode struct {
+	Name resource.Name `json:"name"`
+	// Model is the configured model of the resource, if known.
+	Model string `json:"model,omitempty"`
+	// Source is the part of the config that defined the resource (e.g. "component",
+	// "service", "remote") or "remote_resource" if it was discovered on a remote.
+	Source string `json:"source,omitempty"`
+	// DependsOn are the dependencies as written in the config, before resolution.
+	DependsOn []string `json:"depends_on,omitempty"`
+	State     string   `json:"state"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// A ResourceGraphEdge is a dependency of one resource on another.
+type ResourceGraphEdge struct {
+	From resource.Name `json:"from"`
+	To   resource.Name `json:"to"`
+}
+
+// A ResourceGraphProvider can describe the dependency graph between its resources.
+type ResourceGraphProvider interface {
+	// ResourceGraph returns a snapshot of the robot's resource dependency graph.
+	ResourceGraph() ResourceGraph
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+func (g ResourceGraph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph resources {\n")
+	for _, n := range g.Nodes {
+		color := "black"
+		switch n.State {
+		case ResourceStateFailed:
+			color = "red"
+		case ResourceStateUnresolved, ResourceStateUnavailable:
+			color = "orange"
+		}
+		label := n.Name.String()
+		if n.Model != "" {
+			label += "\n" + n.Model
+		}
+		if n.Error != "" {
+			label += "\n" + n.Error
+		}
+		fmt.Fprintf(&sb, "  %q [label=%q, color=%s];\n", n.Name.String(), label, color)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %q -> %q;\n", e.From.String(), e.To.String())
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
 // Status holds a resource name and its corresponding status. Status is expected to be comprised of string keys
 // and values comprised of primitives, list of primitives, maps with string keys (or at least can be decomposed into one),
 // or lists of the forementioned type of maps. Results with other types of data are not guaranteed.
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/config"
+	configpb "go.viam.com/rdk/proto/api/robot/v1"
+	"go.viam.com/rdk/robot"
+)
+
+// ConfigServer implements the gRPC service checking robot configs without applying them.
+type ConfigServer struct {
+	configpb.UnimplementedRobotConfigServiceServer
+	r robot.Robot
+}
+
+// NewConfigServer constructs a gRPC service server checking robot configs for a Robot.
+func NewConfigServer(r robot.Robot) configpb.RobotConfigServiceServer {
+	return &ConfigServer{r: r}
+}
+
+// ValidateConfig validates the robot config in the request without applying it.
+func (s *ConfigServer) ValidateConfig(
+	ctx context.Context,
+	req *configpb.ValidateConfigRequest,
+) (*configpb.ValidateConfigResponse, error) {
+	cfg, err := configFromStruct(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	problems := validationProblems(cfg)
+	return &configpb.ValidateConfigResponse{Valid: len(problems) == 0, Errors: problems}, nil
+}
+
+// PlanReconfiguration reports what reconfiguring the robot with the config in the request would do.
+// A config with problems is not planned for.
+func (s *ConfigServer) PlanReconfiguration(
+	ctx context.Context,
+	req *configpb.PlanReconfigurationRequest,
+) (*configpb.PlanReconfigurationResponse, error) {
+	planner, ok := s.r.(robot.ReconfigurationPlanner)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "robot cannot plan reconfigurations")
+	}
+	cfg, err := configFromStruct(req.Config)
+	if err != nil {
+		return nil, err
+	}
+	if problems := validationProblems(cfg); len(problems) != 0 {
+		return &configpb.PlanReconfigurationResponse{Valid: false, Errors: problems}, nil
+	}
+	plan, err := planner.PlanReconfiguration(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &configpb.PlanReconfigurationResponse{Valid: true, Plan: reconfigurationPlanToProto(plan)}, nil
+}
+
+// configFromStruct decodes a robot config sent as a struct.
+func configFromStruct(req *structpb.Struct) (*config.Config, error) {
+	data, err := json.Marshal(req.AsMap())
+	if err != nil {
+		return nil, err
+	}
+	var cfg config.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to decode config: %s", err)
+	}
+	return &cfg, nil
+}
+
+// validationProblems returns every problem with a config sent to the robot.
+func validationProblems(cfg *config.Config) []*configpb.ConfigError {
+	problems := []*configpb.ConfigError{}
+	for _, err := range multierr.Errors(config.ValidateConfigFromRequest(cfg)) {
+		var validationErr *config.ValidationError
+		if !errors.As(err, &validationErr) {
+			validationErr = &config.ValidationError{Err: err}
+		}
+		problems = append(problems, &configpb.ConfigError{
+			Path:  validationErr.Path,
+			Name:  validationErr.Name,
+			Error: validationErr.Err.Error(),
+		})
+	}
+	return problems
+}
+
+func reconfigurationPlanToProto(plan *robot.ReconfigurationPlan) *configpb.ReconfigurationPlan {
+	changes := make([]*configpb.ReconfigurationChange, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		changes = append(changes, &configpb.ReconfigurationChange{
+			Name:   change.Name,
+			Kind:   change.Kind,
+			Action: string(change.Action),
+			Reason: change.Reason,
+		})
+	}
+	return &configpb.ReconfigurationPlan{Changes: changes, NetworkChanged: plan.NetworkChanged}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	vprotoutils "go.viam.com/utils/protoutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	configpb "go.viam.com/rdk/proto/api/robot/v1"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/robot"
+)
+
+// DiscoveryServer implements the gRPC service helping write configs for a robot's hardware.
+type DiscoveryServer struct {
+	configpb.UnimplementedRobotDiscoveryServiceServer
+	r robot.Robot
+}
+
+// NewDiscoveryServer constructs a gRPC service server helping write configs for a Robot's hardware.
+func NewDiscoveryServer(r robot.Robot) configpb.RobotDiscoveryServiceServer {
+	return &DiscoveryServer{r: r}
+}
+
+// GetConfigTemplates returns the config templates of every registered model.
+func (s *DiscoveryServer) GetConfigTemplates(
+	ctx context.Context,
+	req *configpb.GetConfigTemplatesRequest,
+) (*configpb.GetConfigTemplatesResponse, error) {
+	templates := registry.ConfigTemplates()
+	pbTemplates := make([]*configpb.ConfigTemplate, 0, len(templates))
+	for _, template := range templates {
+		attrs := make([]*configpb.AttributeTemplate, 0, len(template.Attributes))
+		for _, attr := range template.Attributes {
+			pbAttr := &configpb.AttributeTemplate{Name: attr.Name, Type: attr.Type, Required: attr.Required}
+			if attr.Default != nil {
+				def, err := defaultToProto(attr.Default)
+				if err != nil {
+					return nil, errors.Wrapf(err, "unable to convert the default of %q of %q", attr.Name, template.Query)
+				}
+				pbAttr.Default = def
+			}
+			attrs = append(attrs, pbAttr)
+		}
+		pbTemplates = append(pbTemplates, &configpb.ConfigTemplate{
+			Subtype:    template.Query.API.String(),
+			Model:      template.Query.Model.String(),
+			Attributes: attrs,
+		})
+	}
+	return &configpb.GetConfigTemplatesResponse{Templates: pbTemplates}, nil
+}
+
+// DetectHardware returns the hardware attached to the robot and the configs proposed for it.
+func (s *DiscoveryServer) DetectHardware(
+	ctx context.Context,
+	req *configpb.DetectHardwareRequest,
+) (*configpb.DetectHardwareResponse, error) {
+	detector, ok := s.r.(robot.HardwareDetector)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "robot cannot detect hardware")
+	}
+	hw, proposals, err := detector.DetectHardware(ctx, req.ScanI2C)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &configpb.DetectHardwareResponse{}
+	for _, dev := range hw.USB {
+		resp.UsbDevices = append(resp.UsbDevices, &configpb.USBDevice{
+			VendorId:     uint32(dev.VendorID),
+			ProductId:    uint32(dev.ProductID),
+			Manufacturer: dev.Manufacturer,
+			Product:      dev.Product,
+			Serial:       dev.Serial,
+			Ttys:         dev.TTYs,
+		})
+	}
+	for _, dev := range hw.I2C {
+		resp.I2CDevices = append(resp.I2CDevices, &configpb.I2CDevice{
+			Board:   dev.Board,
+			Bus:     dev.Bus,
+			Address: uint32(dev.Address),
+		})
+	}
+	for _, p := range proposals {
+		attrs, err := vprotoutils.StructToStructPb(p.Attributes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to construct a structpb.Struct from the proposal %q", p.Name)
+		}
+		resp.Proposals = append(resp.Proposals, &configpb.ConfigProposal{
+			Subtype:    p.Query.API.String(),
+			Model:      p.Query.Model.String(),
+			Name:       p.Name,
+			Attributes: attrs,
+			Reason:     p.Reason,
+		})
+	}
+	return resp, nil
+}
+
+// defaultToProto converts the default of an attribute, which may be any value a config decodes
+// into, to a structpb.Value.
+func defaultToProto(def interface{}) (*structpb.Value, error) {
+	wrapped, err := vprotoutils.StructToStructPb(map[string]interface{}{"default": def})
+	if err != nil {
+		return nil, err
+	}
+	return wrapped.Fields["default"], nil
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.viam.com/rdk/operation"
+	configpb "go.viam.com/rdk/proto/api/robot/v1"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// IntrospectionServer implements the gRPC service describing the inner workings of a robot.
+type IntrospectionServer struct {
+	configpb.UnimplementedRobotIntrospectionServiceServer
+	r robot.Robot
+}
+
+// NewIntrospectionServer constructs a gRPC service server describing the inner workings of a Robot.
+func NewIntrospectionServer(r robot.Robot) configpb.RobotIntrospectionServiceServer {
+	return &IntrospectionServer{r: r}
+}
+
+// GetResourceGraph returns the dependency graph between the resources of the robot.
+func (s *IntrospectionServer) GetResourceGraph(
+	ctx context.Context,
+	req *configpb.GetResourceGraphRequest,
+) (*configpb.GetResourceGraphResponse, error) {
+	provider, ok := s.r.(robot.ResourceGraphProvider)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "robot does not expose its resource graph")
+	}
+	graph := provider.ResourceGraph()
+	nodes := make([]*configpb.ResourceGraphNode, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		nodes = append(nodes, &configpb.ResourceGraphNode{
+			Name:      node.Name.String(),
+			Model:     node.Model,
+			Source:    node.Source,
+			DependsOn: node.DependsOn,
+			State:     node.State,
+			Error:     node.Error,
+		})
+	}
+	edges := make([]*configpb.ResourceGraphEdge, 0, len(graph.Edges))
+	for _, edge := range graph.Edges {
+		edges = append(edges, &configpb.ResourceGraphEdge{From: edge.From.String(), To: edge.To.String()})
+	}
+	return &configpb.GetResourceGraphResponse{Nodes: nodes, Edges: edges}, nil
+}
+
+// GetFrameSystemSnapshot returns the current world pose and geometries of every frame in the
+// frame system of the robot.
+func (s *IntrospectionServer) GetFrameSystemSnapshot(
+	ctx context.Context,
+	req *configpb.GetFrameSystemSnapshotRequest,
+) (*configpb.GetFrameSystemSnapshotResponse, error) {
+	transforms, err := referenceframe.LinkInFramesFromTransformsProtobuf(req.GetSupplementalTransforms())
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := framesystem.RobotSnapshot(ctx, s.r, transforms)
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]*configpb.FrameSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		geometries := make([]*commonpb.Geometry, 0, len(snapshot.Geometries))
+		for _, geometry := range snapshot.Geometries {
+			geometries = append(geometries, geometry.ToProtobuf())
+		}
+		frames = append(frames, &configpb.FrameSnapshot{
+			Name:       snapshot.Name,
+			Parent:     snapshot.Parent,
+			Pose:       spatialmath.PoseToProtobuf(snapshot.Pose),
+			Geometries: geometries,
+		})
+	}
+	return &configpb.GetFrameSystemSnapshotResponse{Frames: frames}, nil
+}
+
+// GetOperations returns the running operations of the robot, other than this call itself, along
+// with the operations that started them.
+func (s *IntrospectionServer) GetOperations(
+	ctx context.Context,
+	req *configpb.GetOperationsRequest,
+) (*configpb.GetOperationsResponse, error) {
+	opManager := s.r.OperationManager()
+	if opManager == nil {
+		return nil, status.Error(codes.Unimplemented, "robot does not track operations")
+	}
+	me := operation.Get(ctx)
+	res := &configpb.GetOperationsResponse{}
+	for _, o := range opManager.All() {
+		if o == me {
+			continue
+		}
+		args, err := convertInterfaceToStruct(o.Arguments)
+		if err != nil {
+			return nil, err
+		}
+		pbOp := &configpb.Operation{
+			Id:        o.ID.String(),
+			Method:    o.Method,
+			Arguments: args,
+			Started:   timestamppb.New(o.Started),
+			TraceId:   o.TraceID,
+		}
+		if o.SessionID != uuid.Nil {
+			sid := o.SessionID.String()
+			pbOp.SessionId = &sid
+		}
+		if o.ParentID != uuid.Nil {
+			pid := o.ParentID.String()
+			pbOp.ParentId = &pid
+		}
+		res.Operations = append(res.Operations, pbOp)
+	}
+	return res, nil
+}
@@ -6,6 +6,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"go.viam.com/utils"
 	vprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -311,9 +313,17 @@ func (s *Server) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.G
 	return &pb.GetStatusResponse{Status: statusesP}, nil
 }
 
-const defaultStreamInterval = 1 * time.Second
+const (
+	defaultStreamInterval = 1 * time.Second
+	// streamStatusHeartbeatInterval is the longest StreamStatus goes without sending anything so that
+	// clients can tell an idle stream from a dead one.
+	streamStatusHeartbeatInterval = 5 * time.Second
+)
 
-// StreamStatus periodically sends the status of all statuses requested. An empty request signifies all resources.
+// StreamStatus sends the status of all statuses requested whenever they change, checking at most once
+// every requested interval. The first message contains every status; later messages only contain the
+// statuses that changed, or none at all if nothing changed for a while. A resource that is gone is sent
+// once with no status. An empty request signifies all resources.
 func (s *Server) StreamStatus(req *pb.StreamStatusRequest, streamServer pb.RobotService_StreamStatusServer) error {
 	every := defaultStreamInterval
 	if reqEvery := req.Every.AsDuration(); reqEvery != time.Duration(0) {
@@ -321,7 +331,43 @@ func (s *Server) StreamStatus(req *pb.StreamStatusRequest, streamServer pb.Robot
 	}
 	ticker := time.NewTicker(every)
 	defer ticker.Stop()
+
+	lastSent := map[string]*pb.Status{}
+	var lastSendTime time.Time
 	for {
+		status, err := s.GetStatus(streamServer.Context(), &pb.GetStatusRequest{ResourceNames: req.ResourceNames})
+		if err != nil {
+			return err
+		}
+		changed := make([]*pb.Status, 0, len(status.Status))
+		seen := make(map[string]bool, len(status.Status))
+		for _, st := range status.Status {
+			key := protoutils.ResourceNameFromProto(st.Name).String()
+			seen[key] = true
+			if prev, ok := lastSent[key]; ok && proto.Equal(prev.Status, st.Status) {
+				continue
+			}
+			lastSent[key] = st
+			changed = append(changed, st)
+		}
+		removed := make([]string, 0, len(lastSent)-len(seen))
+		for key := range lastSent {
+			if !seen[key] {
+				removed = append(removed, key)
+			}
+		}
+		sort.Strings(removed)
+		for _, key := range removed {
+			changed = append(changed, &pb.Status{Name: lastSent[key].Name})
+			delete(lastSent, key)
+		}
+		if len(changed) != 0 || lastSendTime.IsZero() || time.Since(lastSendTime) >= streamStatusHeartbeatInterval {
+			if err := streamServer.Send(&pb.StreamStatusResponse{Status: changed}); err != nil {
+				return err
+			}
+			lastSendTime = time.Now()
+		}
+
 		select {
 		case <-streamServer.Context().Done():
 			return streamServer.Context().Err()
@@ -332,13 +378,6 @@ func (s *Server) StreamStatus(req *pb.StreamStatusRequest, streamServer pb.Robot
 			return streamServer.Context().Err()
 		case <-ticker.C:
 		}
-		status, err := s.GetStatus(streamServer.Context(), &pb.GetStatusRequest{ResourceNames: req.ResourceNames})
-		if err != nil {
-			return err
-		}
-		if err := streamServer.Send(&pb.StreamStatusResponse{Status: status.Status}); err != nil {
-			return err
-		}
 	}
 }
 
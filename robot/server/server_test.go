@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -19,14 +20,20 @@ import (
 	"go.viam.com/test"
 	vprotoutils "go.viam.com/utils/protoutils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/discovery"
 	"go.viam.com/rdk/operation"
+	configpb "go.viam.com/rdk/proto/api/robot/v1"
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
@@ -572,8 +579,16 @@ func TestServerGetStatus(t *testing.T) {
 	t.Run("working StreamStatus", func(t *testing.T) {
 		injectRobot := &inject.Robot{}
 		server := server.New(injectRobot)
+		var calls int32
 		injectRobot.StatusFunc = func(ctx context.Context, resourceNames []resource.Name) ([]robot.Status, error) {
-			return []robot.Status{{arm.Named("arm"), struct{}{}}}, nil
+			// the status only changes on the fourth call, when arm2 is removed
+			if atomic.AddInt32(&calls, 1) >= 4 {
+				return []robot.Status{{arm.Named("arm"), map[string]interface{}{"pos": 1}}}, nil
+			}
+			return []robot.Status{
+				{arm.Named("arm"), map[string]interface{}{"pos": 0}},
+				{arm.Named("arm2"), struct{}{}},
+			}, nil
 		}
 
 		cancelCtx, cancel := context.WithCancel(context.Background())
@@ -592,16 +607,27 @@ func TestServerGetStatus(t *testing.T) {
 			streamErr = server.StreamStatus(&pb.StreamStatusRequest{Every: durationpb.New(dur)}, streamServer)
 			close(done)
 		}()
-		expectedStatus, err := vprotoutils.StructToStructPb(map[string]interface{}{})
+		emptyStatus, err := vprotoutils.StructToStructPb(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		pos0, err := vprotoutils.StructToStructPb(map[string]interface{}{"pos": 0})
 		test.That(t, err, test.ShouldBeNil)
+		pos1, err := vprotoutils.StructToStructPb(map[string]interface{}{"pos": 1})
+		test.That(t, err, test.ShouldBeNil)
+
+		// everything is sent at first, then only what changed, with removed resources sent without a status
 		var messages []*pb.StreamStatusResponse
 		messages = append(messages, <-messageCh)
+		test.That(t, time.Since(start), test.ShouldBeLessThan, dur)
 		messages = append(messages, <-messageCh)
-		messages = append(messages, <-messageCh)
-		test.That(t, messages, test.ShouldResemble, []*pb.StreamStatusResponse{
-			{Status: []*pb.Status{{Name: protoutils.ResourceNameToProto(arm.Named("arm")), Status: expectedStatus}}},
-			{Status: []*pb.Status{{Name: protoutils.ResourceNameToProto(arm.Named("arm")), Status: expectedStatus}}},
-			{Status: []*pb.Status{{Name: protoutils.ResourceNameToProto(arm.Named("arm")), Status: expectedStatus}}},
+		test.That(t, messages, test.ShouldResembleProto, []*pb.StreamStatusResponse{
+			{Status: []*pb.Status{
+				{Name: protoutils.ResourceNameToProto(arm.Named("arm")), Status: pos0},
+				{Name: protoutils.ResourceNameToProto(arm.Named("arm2")), Status: emptyStatus},
+			}},
+			{Status: []*pb.Status{
+				{Name: protoutils.ResourceNameToProto(arm.Named("arm")), Status: pos1},
+				{Name: protoutils.ResourceNameToProto(arm.Named("arm2"))},
+			}},
 		})
 		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 3*dur)
 		test.That(t, time.Since(start), test.ShouldBeLessThanOrEqualTo, 6*dur)
@@ -662,3 +688,120 @@ func (mgr *sessionManager) Close() {
 func (mgr *sessionManager) ServerInterceptors() session.ServerInterceptors {
 	panic("unimplemented")
 }
+
+func TestServerValidateConfig(t *testing.T) {
+	srv := server.NewConfigServer(&inject.Robot{})
+
+	cfg, err := structpb.NewStruct(map[string]interface{}{
+		"remotes": []interface{}{map[string]interface{}{"name": "remote1", "address": "localhost:8080"}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err := srv.ValidateConfig(context.Background(), &configpb.ValidateConfigRequest{Config: cfg})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Valid, test.ShouldBeTrue)
+	test.That(t, resp.Errors, test.ShouldBeEmpty)
+
+	cfg, err = structpb.NewStruct(map[string]interface{}{
+		"remotes": []interface{}{map[string]interface{}{"name": "remote1"}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resp, err = srv.ValidateConfig(context.Background(), &configpb.ValidateConfigRequest{Config: cfg})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Valid, test.ShouldBeFalse)
+	test.That(t, resp.Errors, test.ShouldHaveLength, 1)
+	test.That(t, resp.Errors[0].Path, test.ShouldEqual, "remotes.0")
+	test.That(t, resp.Errors[0].Name, test.ShouldEqual, "remote1")
+
+	cfg, err = structpb.NewStruct(map[string]interface{}{"remotes": "not a list"})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = srv.ValidateConfig(context.Background(), &configpb.ValidateConfigRequest{Config: cfg})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestServerPlanReconfiguration(t *testing.T) {
+	srv := server.NewConfigServer(&inject.Robot{})
+	cfg, err := structpb.NewStruct(map[string]interface{}{})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = srv.PlanReconfiguration(context.Background(), &configpb.PlanReconfigurationRequest{Config: cfg})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+}
+
+type hardwareDetectingRobot struct {
+	*inject.Robot
+	scannedI2C bool
+}
+
+func (r *hardwareDetectingRobot) DetectHardware(
+	ctx context.Context,
+	scanI2C bool,
+) (discovery.Hardware, []discovery.Proposal, error) {
+	r.scannedI2C = scanI2C
+	hw := discovery.Hardware{USB: []discovery.USBDevice{{VendorID: 0x1546, ProductID: 0x01a7, TTYs: []string{"/dev/ttyACM0"}}}}
+	proposals := []discovery.Proposal{{
+		Query:      discovery.NewQuery(movementsensor.Subtype, resource.NewDefaultModel("gps-nmea")),
+		Name:       "movement_sensor1",
+		Attributes: map[string]interface{}{"serial_path": "/dev/ttyACM0"},
+		Reason:     "u-blox GPS",
+	}}
+	return hw, proposals, nil
+}
+
+func TestServerDetectHardware(t *testing.T) {
+	_, err := server.NewDiscoveryServer(&inject.Robot{}).DetectHardware(context.Background(), &configpb.DetectHardwareRequest{})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+
+	r := &hardwareDetectingRobot{Robot: &inject.Robot{}}
+	resp, err := server.NewDiscoveryServer(r).DetectHardware(context.Background(), &configpb.DetectHardwareRequest{ScanI2C: true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, r.scannedI2C, test.ShouldBeTrue)
+	test.That(t, resp.UsbDevices, test.ShouldHaveLength, 1)
+	test.That(t, resp.UsbDevices[0].VendorId, test.ShouldEqual, 0x1546)
+	test.That(t, resp.UsbDevices[0].Ttys, test.ShouldResemble, []string{"/dev/ttyACM0"})
+	test.That(t, resp.I2CDevices, test.ShouldBeEmpty)
+	test.That(t, resp.Proposals, test.ShouldHaveLength, 1)
+	test.That(t, resp.Proposals[0].Subtype, test.ShouldEqual, "rdk:component:movement_sensor")
+	test.That(t, resp.Proposals[0].Model, test.ShouldEqual, "rdk:builtin:gps-nmea")
+	test.That(t, resp.Proposals[0].Attributes.AsMap(), test.ShouldResemble, map[string]interface{}{"serial_path": "/dev/ttyACM0"})
+}
+
+type templateTestAttrs struct {
+	Port  string  `json:"port"`
+	Speed float64 `json:"speed,omitempty"`
+}
+
+func TestServerGetConfigTemplates(t *testing.T) {
+	model := resource.NewDefaultModel("template_test")
+	registry.RegisterComponent(arm.Subtype, model, registry.Component{
+		Constructor: func(ctx context.Context, deps registry.Dependencies, cfg config.Component, logger golog.Logger) (interface{}, error) {
+			return nil, errors.New("not constructed")
+		},
+	})
+	defer registry.DeregisterComponent(arm.Subtype, model)
+	config.RegisterComponentAttributeMapConverter(
+		arm.Subtype,
+		model,
+		func(attributes config.AttributeMap) (interface{}, error) {
+			var attrs templateTestAttrs
+			return config.TransformAttributeMapToStruct(&attrs, attributes)
+		},
+		&templateTestAttrs{Speed: 5})
+
+	resp, err := server.NewDiscoveryServer(&inject.Robot{}).GetConfigTemplates(
+		context.Background(), &configpb.GetConfigTemplatesRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	var template *configpb.ConfigTemplate
+	for _, tmpl := range resp.Templates {
+		if tmpl.Model == model.String() {
+			template = tmpl
+		}
+	}
+	test.That(t, template, test.ShouldNotBeNil)
+	test.That(t, template.Subtype, test.ShouldEqual, "rdk:component:arm")
+	test.That(t, template.Attributes, test.ShouldHaveLength, 2)
+	test.That(t, template.Attributes[0], test.ShouldResembleProto, &configpb.AttributeTemplate{
+		Name: "port", Type: "string", Required: true,
+	})
+	test.That(t, template.Attributes[1], test.ShouldResembleProto, &configpb.AttributeTemplate{
+		Name: "speed", Type: "number", Default: structpb.NewNumberValue(5),
+	})
+}
@@ -0,0 +1,289 @@
+package web
+
+import (
+	"context"
+	"strings"
+
+	robotpb "go.viam.com/api/robot/v1"
+	webrtcpb "go.viam.com/utils/proto/rpc/webrtc/v1"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// accessPolicyMetadataKey is the auth metadata key holding the name of the access policy
+// a caller authenticated under.
+const accessPolicyMetadataKey = "rdk_access_policy"
+
+// accessPolicyResourceRobot is used in rules to refer to robot wide methods.
+const accessPolicyResourceRobot = "robot"
+
+// alwaysAllowedMethods are the methods a client needs in order to connect at all.
+var alwaysAllowedMethods = map[string]bool{
+	"/" + robotpb.RobotService_ServiceDesc.ServiceName + "/ResourceNames":             true,
+	"/" + robotpb.RobotService_ServiceDesc.ServiceName + "/ResourceRPCSubtypes":       true,
+	"/" + robotpb.RobotService_ServiceDesc.ServiceName + "/StartSession":              true,
+	"/" + robotpb.RobotService_ServiceDesc.ServiceName + "/SendSessionHeartbeat":      true,
+	"/" + webrtcpb.SignalingService_ServiceDesc.ServiceName + "/OptionalWebRTCConfig": true,
+}
+
+// signalingServiceName is the service establishing WebRTC connections. Calls over WebRTC
+// connections are not authenticated per caller, so callers under an access policy must
+// connect directly instead.
+var signalingServiceName = webrtcpb.SignalingService_ServiceDesc.ServiceName
+
+// accessPolicies enforces the access policies of the web service's auth config on
+// the methods of its subtype services. It does so with gRPC interceptors in front of every
+// service, rather than in the subtype servers themselves.
+type accessPolicies struct {
+	r        robot.Robot
+	policies map[string]config.AccessPolicyConfig
+	keys     map[string]string
+}
+
+func newAccessPolicies(r robot.Robot, cfgs []config.AccessPolicyConfig) *accessPolicies {
+	ap := &accessPolicies{
+		r:        r,
+		policies: make(map[string]config.AccessPolicyConfig, len(cfgs)),
+		keys:     map[string]string{},
+	}
+	for _, cfg := range cfgs {
+		ap.policies[cfg.Name] = cfg
+		for _, key := range cfg.Keys {
+			ap.keys[key] = cfg.Name
+		}
+	}
+	return ap
+}
+
+// wrapAuthHandler records the access policy of scoped API keys in the auth metadata of
+// the credentials they are exchanged for.
+func (ap *accessPolicies) wrapAuthHandler(handler rpc.AuthHandler) rpc.AuthHandler {
+	return &policyAuthHandler{AuthHandler: handler, keys: ap.keys}
+}
+
+type policyAuthHandler struct {
+	rpc.AuthHandler
+	keys map[string]string
+}
+
+func (h *policyAuthHandler) Authenticate(ctx context.Context, entity, payload string) (map[string]string, error) {
+	md, err := h.AuthHandler.Authenticate(ctx, entity, payload)
+	if err != nil {
+		return nil, err
+	}
+	if policy, ok := h.keys[payload]; ok {
+		if md == nil {
+			md = map[string]string{}
+		}
+		md[accessPolicyMetadataKey] = policy
+	}
+	return md, nil
+}
+
+// VerifyEntity marks the auth entity of credentials issued for a scoped API key with its
+// access policy. The claims it reads have been verified by the time it is called.
+func (h *policyAuthHandler) VerifyEntity(ctx context.Context, entity string) (interface{}, error) {
+	info, err := h.AuthHandler.VerifyEntity(ctx, entity)
+	if err != nil {
+		return nil, err
+	}
+	claims := rpc.ContextAuthClaims(ctx)
+	if claims == nil {
+		return info, nil
+	}
+	if policy, ok := claims.Metadata()[accessPolicyMetadataKey]; ok {
+		return &policyAuthEntity{info: info, policy: policy}, nil
+	}
+	return info, nil
+}
+
+// policyAuthEntity is the auth entity of a caller authenticated under an access policy.
+type policyAuthEntity struct {
+	info   interface{}
+	policy string
+}
+
+// policyFromContext returns the access policy the caller authenticated under, if any.
+func (ap *accessPolicies) policyFromContext(ctx context.Context) (config.AccessPolicyConfig, bool) {
+	entity, ok := contextAuthEntity(ctx).(*policyAuthEntity)
+	if !ok {
+		return config.AccessPolicyConfig{}, false
+	}
+	policy, ok := ap.policies[entity.policy]
+	if !ok {
+		// a policy that is no longer configured allows nothing
+		policy = config.AccessPolicyConfig{Name: entity.policy}
+	}
+	return policy, true
+}
+
+// contextAuthEntity returns the auth entity bound to the context when verifying the caller's
+// credentials, or nil for unauthenticated calls.
+func contextAuthEntity(ctx context.Context) (entity interface{}) {
+	defer func() {
+		if recover() != nil {
+			entity = nil
+		}
+	}()
+	return rpc.MustContextAuthEntity(ctx)
+}
+
+// check returns a PermissionDenied error if the policy does not allow calling fullMethod
+// with the given request.
+func (ap *accessPolicies) check(policy config.AccessPolicyConfig, fullMethod string, req interface{}) error {
+	methodName := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if !alwaysAllowedMethods[fullMethod] && strings.HasPrefix(fullMethod, "/"+signalingServiceName+"/") {
+		return status.Errorf(codes.PermissionDenied, "access policy %q does not allow connecting over WebRTC", policy.Name)
+	}
+
+	subtype, _, err := robot.TypeAndMethodDescFromMethod(ap.r, fullMethod)
+	if err != nil {
+		// not a resource method
+		if alwaysAllowedMethods[fullMethod] || policyAllows(policy, nil, methodName) {
+			return nil
+		}
+		return status.Errorf(codes.PermissionDenied, "access policy %q does not allow %s", policy.Name, fullMethod)
+	}
+
+	// rules allowing every resource of the subtype need not know which resource is called, so
+	// they also cover methods whose requests do not name it
+	if policyAllowsSubtype(policy, subtype.Subtype, methodName) {
+		return nil
+	}
+	name, ok := requestResourceName(req)
+	if !ok {
+		return status.Errorf(codes.PermissionDenied, "access policy %q cannot determine the resource of %s", policy.Name, fullMethod)
+	}
+	resName := resource.NameFromSubtype(subtype.Subtype, name)
+	if policyAllows(policy, &resName, methodName) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "access policy %q does not allow %s on %s", policy.Name, methodName, resName)
+}
+
+// policyAllows reports whether a rule of the policy allows method on the resource, or on the
+// robot itself if resName is nil.
+func policyAllows(policy config.AccessPolicyConfig, resName *resource.Name, method string) bool {
+	for _, rule := range policy.Rules {
+		if !matchesAny(rule.Methods, method) {
+			continue
+		}
+		for _, pattern := range rule.Resources {
+			if resName == nil {
+				if matchesPattern(pattern, accessPolicyResourceRobot) {
+					return true
+				}
+				continue
+			}
+			if pattern != accessPolicyResourceRobot && (matchesPattern(pattern, resName.String()) ||
+				matchesPattern(pattern, resName.ShortName()) ||
+				pattern == resName.Subtype.String()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// policyAllowsSubtype reports whether a rule of the policy allows method on every resource of
+// the subtype, such as by naming the subtype itself.
+func policyAllowsSubtype(policy config.AccessPolicyConfig, subtype resource.Subtype, method string) bool {
+	for _, rule := range policy.Rules {
+		if !matchesAny(rule.Methods, method) {
+			continue
+		}
+		for _, pattern := range rule.Resources {
+			if pattern != accessPolicyResourceRobot && matchesPattern(pattern, subtype.String()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		if matchesPattern(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPattern matches s exactly, or by prefix if pattern ends in "*".
+func matchesPattern(pattern, s string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(s, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == s
+}
+
+// requestResourceName returns the value of the conventional "name" field of a request.
+func requestResourceName(req interface{}) (string, bool) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", false
+	}
+	refl := msg.ProtoReflect()
+	field := refl.Descriptor().Fields().ByName("name")
+	if field == nil || field.Kind() != protoreflect.StringKind {
+		return "", false
+	}
+	name := refl.Get(field).String()
+	return name, name != ""
+}
+
+func (ap *accessPolicies) unaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *googlegrpc.UnaryServerInfo,
+	handler googlegrpc.UnaryHandler,
+) (interface{}, error) {
+	if policy, ok := ap.policyFromContext(ctx); ok {
+		if err := ap.check(policy, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+	}
+	return handler(ctx, req)
+}
+
+func (ap *accessPolicies) streamInterceptor(
+	srv interface{},
+	ss googlegrpc.ServerStream,
+	info *googlegrpc.StreamServerInfo,
+	handler googlegrpc.StreamHandler,
+) error {
+	policy, ok := ap.policyFromContext(ss.Context())
+	if !ok {
+		return handler(srv, ss)
+	}
+	return handler(srv, &policyServerStream{ServerStream: ss, ap: ap, policy: policy, method: info.FullMethod})
+}
+
+// policyServerStream checks the first message of a stream, which names the resource.
+type policyServerStream struct {
+	googlegrpc.ServerStream
+	ap      *accessPolicies
+	policy  config.AccessPolicyConfig
+	method  string
+	checked bool
+}
+
+func (s *policyServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.checked {
+		s.checked = true
+		return s.ap.check(s.policy, s.method, m)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package web
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	inputpb "go.viam.com/api/component/inputcontroller/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/input"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/registry"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestAccessPolicies(t *testing.T) {
+	readOnly := config.AccessPolicyConfig{
+		Name: "read-only",
+		Keys: []string{"key2"},
+		Rules: []config.AccessRuleConfig{
+			{Resources: []string{"rdk:component:sensor"}, Methods: []string{"Get*"}},
+			{Resources: []string{"arm1"}, Methods: []string{"GetEndPosition"}},
+			{Resources: []string{"robot"}, Methods: []string{"GetStatus"}},
+		},
+	}
+	sensorName := resource.NameFromSubtype(sensor.Subtype, "temp")
+	armName := resource.NameFromSubtype(arm.Subtype, "arm1")
+	otherArmName := resource.NameFromSubtype(arm.Subtype, "arm2")
+
+	t.Run("rules", func(t *testing.T) {
+		test.That(t, policyAllows(readOnly, &sensorName, "GetReadings"), test.ShouldBeTrue)
+		test.That(t, policyAllows(readOnly, &sensorName, "DoCommand"), test.ShouldBeFalse)
+		test.That(t, policyAllows(readOnly, &armName, "GetEndPosition"), test.ShouldBeTrue)
+		test.That(t, policyAllows(readOnly, &armName, "MoveToPosition"), test.ShouldBeFalse)
+		test.That(t, policyAllows(readOnly, &otherArmName, "GetEndPosition"), test.ShouldBeFalse)
+		test.That(t, policyAllows(readOnly, nil, "GetStatus"), test.ShouldBeTrue)
+		test.That(t, policyAllows(readOnly, nil, "StopAll"), test.ShouldBeFalse)
+
+		everything := config.AccessPolicyConfig{Rules: []config.AccessRuleConfig{{Resources: []string{"*"}, Methods: []string{"*"}}}}
+		test.That(t, policyAllows(everything, &otherArmName, "MoveToPosition"), test.ShouldBeTrue)
+		test.That(t, policyAllows(config.AccessPolicyConfig{}, &sensorName, "GetReadings"), test.ShouldBeFalse)
+	})
+
+	t.Run("stream without a resource name", func(t *testing.T) {
+		injectRobot := &inject.Robot{}
+		injectRobot.ResourceRPCSubtypesFunc = func() []resource.RPCSubtype {
+			return []resource.RPCSubtype{{
+				Subtype: input.Subtype,
+				Desc:    registry.ResourceSubtypeLookup(input.Subtype).ReflectRPCServiceDesc,
+			}}
+		}
+		ap := newAccessPolicies(injectRobot, nil)
+		method := "/" + inputpb.InputControllerService_ServiceDesc.ServiceName + "/StreamEvents"
+		recv := func(policy config.AccessPolicyConfig) error {
+			ss := &policyServerStream{
+				ServerStream: &firstMessageStream{msg: &inputpb.StreamEventsRequest{Controller: "c1"}},
+				ap:           ap,
+				policy:       policy,
+				method:       method,
+			}
+			return ss.RecvMsg(&inputpb.StreamEventsRequest{})
+		}
+
+		// the request names the controller in a field other than "name", so only rules covering
+		// every input controller can allow it
+		bySubtype := config.AccessPolicyConfig{Name: "by-subtype", Rules: []config.AccessRuleConfig{
+			{Resources: []string{"rdk:component:input_controller"}, Methods: []string{"StreamEvents"}},
+		}}
+		test.That(t, recv(bySubtype), test.ShouldBeNil)
+
+		byName := config.AccessPolicyConfig{Name: "by-name", Rules: []config.AccessRuleConfig{
+			{Resources: []string{"c1"}, Methods: []string{"StreamEvents"}},
+		}}
+		err := recv(byName)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot determine the resource")
+
+		otherMethod := config.AccessPolicyConfig{Name: "other-method", Rules: []config.AccessRuleConfig{
+			{Resources: []string{"rdk:component:input_controller"}, Methods: []string{"GetControls"}},
+		}}
+		test.That(t, status.Code(recv(otherMethod)), test.ShouldEqual, codes.PermissionDenied)
+	})
+
+	t.Run("auth handler", func(t *testing.T) {
+		ap := newAccessPolicies(nil, []config.AccessPolicyConfig{readOnly})
+		handler := ap.wrapAuthHandler(rpc.MakeSimpleMultiAuthHandler([]string{"entity"}, []string{"key1", "key2"}))
+
+		md, err := handler.Authenticate(context.Background(), "entity", "key1")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, md[accessPolicyMetadataKey], test.ShouldBeEmpty)
+
+		md, err = handler.Authenticate(context.Background(), "entity", "key2")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, md[accessPolicyMetadataKey], test.ShouldEqual, "read-only")
+
+		_, err = handler.Authenticate(context.Background(), "entity", "key3")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("policy from context", func(t *testing.T) {
+		ap := newAccessPolicies(nil, []config.AccessPolicyConfig{readOnly})
+
+		_, ok := ap.policyFromContext(context.Background())
+		test.That(t, ok, test.ShouldBeFalse)
+		_, ok = ap.policyFromContext(rpc.ContextWithAuthEntity(context.Background(), "entity"))
+		test.That(t, ok, test.ShouldBeFalse)
+
+		policy, ok := ap.policyFromContext(rpc.ContextWithAuthEntity(context.Background(),
+			&policyAuthEntity{info: "entity", policy: "read-only"}))
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, policy.Name, test.ShouldEqual, "read-only")
+
+		policy, ok = ap.policyFromContext(rpc.ContextWithAuthEntity(context.Background(),
+			&policyAuthEntity{info: "entity", policy: "removed"}))
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, policy.Rules, test.ShouldBeEmpty)
+	})
+
+	t.Run("unverified token", func(t *testing.T) {
+		ap := newAccessPolicies(nil, []config.AccessPolicyConfig{readOnly})
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, rpc.JWTClaims{
+			AuthMetadata: map[string]string{accessPolicyMetadataKey: "read-only"},
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		test.That(t, err, test.ShouldBeNil)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+		_, ok := ap.policyFromContext(rpc.ContextWithAuthEntity(ctx, "entity"))
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+}
+
+// firstMessageStream is a server stream that receives a single message.
+type firstMessageStream struct {
+	googlegrpc.ServerStream
+	msg proto.Message
+}
+
+func (s *firstMessageStream) RecvMsg(m interface{}) error {
+	//nolint:forcetypeassert
+	proto.Merge(m.(proto.Message), s.msg)
+	return nil
+}
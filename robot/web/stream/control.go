@@ -0,0 +1,177 @@
+package webstream
+
+import (
+	"context"
+	"image"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edaniels/gostream"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"golang.org/x/image/draw"
+
+	"go.viam.com/rdk/rimage"
+	rutils "go.viam.com/rdk/utils"
+)
+
+const (
+	// encodedBitsPerPixel is roughly how many bits a frame takes per pixel once encoded for streaming.
+	encodedBitsPerPixel = 0.1
+	// assumedFrameRate is the frame rate bitrates are estimated at when none is set.
+	assumedFrameRate = 30
+)
+
+// StreamOptions are the settings of a video stream that can change while it streams, so that subscribers on poor links
+// can trade quality for a stream that keeps up. Zero values keep the setting of the source.
+type StreamOptions struct {
+	// Width and Height bound the resolution frames are encoded at. Frames keep their aspect ratio and are never enlarged.
+	Width  int
+	Height int
+	// FrameRate is the most frames per second that are encoded.
+	FrameRate float64
+	// TargetBitrate is the bits per second the encoded stream should stay under. Encoders cannot change their bitrate
+	// once they have started, so it is met by lowering the resolution further.
+	TargetBitrate int
+}
+
+// Validate ensures all parts of the options are valid.
+func (opts StreamOptions) Validate() error {
+	if opts.Width < 0 || opts.Height < 0 || opts.FrameRate < 0 || opts.TargetBitrate < 0 {
+		return errors.New("stream options cannot be negative")
+	}
+	return nil
+}
+
+// frameSize returns the size a frame of the given size is encoded at.
+func (opts StreamOptions) frameSize(width, height int) (int, int) {
+	scale := 1.
+	if opts.Width > 0 && opts.Width < width {
+		scale = float64(opts.Width) / float64(width)
+	}
+	if opts.Height > 0 && opts.Height < height {
+		scale = math.Min(scale, float64(opts.Height)/float64(height))
+	}
+	if opts.TargetBitrate > 0 {
+		frameRate := opts.FrameRate
+		if frameRate == 0 {
+			frameRate = assumedFrameRate
+		}
+		maxPixels := float64(opts.TargetBitrate) / (frameRate * encodedBitsPerPixel)
+		if pixels := scale * scale * float64(width*height); pixels > maxPixels {
+			scale *= math.Sqrt(maxPixels / pixels)
+		}
+	}
+	if scale == 1 {
+		return width, height
+	}
+	// encoders subsample chroma in 2x2 blocks, so they need even sizes
+	even := func(size int) int {
+		return rutils.MaxInt(2, 2*int(float64(size)*scale/2))
+	}
+	return even(width), even(height)
+}
+
+// StreamPresets are the options of the variants every video stream is also offered in, which the web UI switches
+// between. A preset is only encoded once it is subscribed to, and is shared by all of its subscribers.
+var StreamPresets = map[string]StreamOptions{
+	"medium": {Height: 720, FrameRate: 15, TargetBitrate: 1500000},
+	"low":    {Height: 360, FrameRate: 10, TargetBitrate: 300000},
+}
+
+// variantSeparator separates the name of a stream from the name of a variant of it.
+const variantSeparator = "~"
+
+// VariantName returns the name of a variant of a stream, which subscribers use to receive it.
+func VariantName(name, variant string) string {
+	return name + variantSeparator + variant
+}
+
+// ParseVariantName returns the names of the stream and variant a variant of a stream is named by, and whether
+// it is named as one.
+func ParseVariantName(variantName string) (string, string, bool) {
+	idx := strings.LastIndex(variantName, variantSeparator)
+	if idx < 0 {
+		return "", "", false
+	}
+	return variantName[:idx], variantName[idx+len(variantSeparator):], true
+}
+
+// A StreamController is a video reader that adapts the frames of a video source to StreamOptions, which can change
+// while it streams.
+type StreamController struct {
+	stream gostream.VideoStream
+
+	mu        sync.Mutex
+	opts      StreamOptions
+	lastFrame time.Time
+}
+
+// NewStreamController returns a controller of frames of the given source, which starts with the given options.
+func NewStreamController(source gostream.VideoSource, opts StreamOptions) (*StreamController, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return &StreamController{stream: gostream.NewEmbeddedVideoStream(source), opts: opts}, nil
+}
+
+// Options returns the options frames are currently adapted to.
+func (sc *StreamController) Options() StreamOptions {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.opts
+}
+
+// SetOptions changes the options frames are adapted to, starting with the next frame.
+func (sc *StreamController) SetOptions(opts StreamOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.opts = opts
+	return nil
+}
+
+// Read waits until the next frame is due at the frame rate, and returns it scaled down to the resolution and bitrate.
+func (sc *StreamController) Read(ctx context.Context) (image.Image, func(), error) {
+	sc.mu.Lock()
+	opts, lastFrame := sc.opts, sc.lastFrame
+	sc.mu.Unlock()
+	if opts.FrameRate > 0 {
+		wait := time.Duration(float64(time.Second)/opts.FrameRate) - time.Since(lastFrame)
+		if wait > 0 && !utils.SelectContextOrWait(ctx, wait) {
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	img, release, err := sc.stream.Next(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sc.mu.Lock()
+	sc.lastFrame = time.Now()
+	sc.mu.Unlock()
+
+	bounds := img.Bounds()
+	width, height := opts.frameSize(bounds.Dx(), bounds.Dy())
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return img, release, nil
+	}
+	if release != nil {
+		defer release()
+	}
+	if ycbcr, ok := img.(*image.YCbCr); ok {
+		return rimage.ResizeYCbCr(ycbcr, width, height), func() {}, nil
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst, func() {}, nil
+}
+
+// Close closes the stream of the source.
+func (sc *StreamController) Close(ctx context.Context) error {
+	return sc.stream.Close(ctx)
+}
@@ -0,0 +1,107 @@
+package webstream_test
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/edaniels/gostream"
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+
+	webstream "go.viam.com/rdk/robot/web/stream"
+)
+
+type hdVideoReader struct {
+	ycbcr bool
+}
+
+func (r hdVideoReader) Read(ctx context.Context) (image.Image, func(), error) {
+	if r.ycbcr {
+		return image.NewYCbCr(image.Rect(0, 0, 1280, 720), image.YCbCrSubsampleRatio420), func() {}, nil
+	}
+	return image.NewRGBA(image.Rect(0, 0, 1280, 720)), func() {}, nil
+}
+
+func (hdVideoReader) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestStreamController(t *testing.T) {
+	videoSrc := gostream.NewVideoSource(hdVideoReader{}, prop.Video{})
+	defer func() {
+		test.That(t, videoSrc.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	_, err := webstream.NewStreamController(videoSrc, webstream.StreamOptions{Width: -1})
+	test.That(t, err, test.ShouldNotBeNil)
+	control, err := webstream.NewStreamController(videoSrc, webstream.StreamOptions{})
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, control.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	for _, tc := range []struct {
+		opts     webstream.StreamOptions
+		expected image.Rectangle
+	}{
+		{webstream.StreamOptions{}, image.Rect(0, 0, 1280, 720)},
+		{webstream.StreamOptions{Width: 1920}, image.Rect(0, 0, 1280, 720)},
+		{webstream.StreamOptions{Width: 640}, image.Rect(0, 0, 640, 360)},
+		{webstream.StreamOptions{Width: 640, Height: 180}, image.Rect(0, 0, 320, 180)},
+		// a quarter of the pixels fit in the bitrate at 100 frames per second, which halves both sides
+		{webstream.StreamOptions{FrameRate: 100, TargetBitrate: 2304000}, image.Rect(0, 0, 640, 360)},
+		{webstream.StreamOptions{TargetBitrate: 1}, image.Rect(0, 0, 2, 2)},
+		{webstream.StreamPresets["low"], image.Rect(0, 0, 640, 360)},
+	} {
+		test.That(t, control.SetOptions(tc.opts), test.ShouldBeNil)
+		test.That(t, control.Options(), test.ShouldResemble, tc.opts)
+		img, release, err := control.Read(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, img.Bounds(), test.ShouldResemble, tc.expected)
+		release()
+	}
+	test.That(t, control.SetOptions(webstream.StreamOptions{FrameRate: -1}), test.ShouldNotBeNil)
+
+	t.Run("frame rate", func(t *testing.T) {
+		test.That(t, control.SetOptions(webstream.StreamOptions{FrameRate: 20}), test.ShouldBeNil)
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			_, release, err := control.Read(context.Background())
+			test.That(t, err, test.ShouldBeNil)
+			release()
+		}
+		// frames are due every 50ms
+		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 150*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := control.Read(ctx)
+		test.That(t, err, test.ShouldBeError, context.Canceled)
+	})
+
+	t.Run("yuv", func(t *testing.T) {
+		ycbcrSrc := gostream.NewVideoSource(hdVideoReader{ycbcr: true}, prop.Video{})
+		defer func() {
+			test.That(t, ycbcrSrc.Close(context.Background()), test.ShouldBeNil)
+		}()
+		control, err := webstream.NewStreamController(ycbcrSrc, webstream.StreamOptions{Height: 360})
+		test.That(t, err, test.ShouldBeNil)
+		img, release, err := control.Read(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, img, test.ShouldHaveSameTypeAs, &image.YCbCr{})
+		test.That(t, img.Bounds(), test.ShouldResemble, image.Rect(0, 0, 640, 360))
+		release()
+		test.That(t, control.Close(context.Background()), test.ShouldBeNil)
+	})
+}
+
+func TestVariantName(t *testing.T) {
+	name, variant, ok := webstream.ParseVariantName(webstream.VariantName("camera~1", "low"))
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, name, test.ShouldEqual, "camera~1")
+	test.That(t, variant, test.ShouldEqual, "low")
+	_, _, ok = webstream.ParseVariantName("camera")
+	test.That(t, ok, test.ShouldBeFalse)
+}
@@ -0,0 +1,216 @@
+package webstream
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/edaniels/gostream"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+)
+
+const (
+	defaultRecordingFrameRate       = 10
+	defaultRecordingSegmentDuration = time.Minute
+)
+
+// RecordingOptions configures how a video stream is recorded to disk.
+type RecordingOptions struct {
+	// Dir is the directory segments are written to. It is created if it does not exist.
+	Dir string
+	// SegmentDuration is the longest a single segment file may cover. Defaults to one minute.
+	SegmentDuration time.Duration
+	// SegmentMaxBytes is the largest a single segment file may grow. Zero means unbounded.
+	SegmentMaxBytes int64
+	// MaxDuration stops the recording once reached. Zero records until stopped.
+	MaxDuration time.Duration
+	// MaxBytes stops the recording once this many bytes were written across all segments.
+	// Zero means unbounded.
+	MaxBytes int64
+	// FrameRate is how many frames per second are recorded. Defaults to 10.
+	FrameRate float64
+}
+
+// A Recorder records frames of a video source to segmented motion JPEG files on disk.
+type Recorder struct {
+	name   string
+	source gostream.VideoSource
+	opts   RecordingOptions
+	logger golog.Logger
+
+	cancel func()
+	done   chan struct{}
+
+	mu       sync.Mutex
+	segments []string
+	err      error
+}
+
+// NewRecorder starts recording the named video source according to opts. Recording runs
+// until Stop is called, the context is done or one of the configured limits is reached.
+func NewRecorder(
+	ctx context.Context,
+	name string,
+	source gostream.VideoSource,
+	opts RecordingOptions,
+	logger golog.Logger,
+) (*Recorder, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("recording directory must be set")
+	}
+	if opts.FrameRate < 0 || opts.SegmentDuration < 0 || opts.SegmentMaxBytes < 0 || opts.MaxDuration < 0 || opts.MaxBytes < 0 {
+		return nil, errors.New("recording limits cannot be negative")
+	}
+	if opts.FrameRate == 0 {
+		opts.FrameRate = defaultRecordingFrameRate
+	}
+	if opts.SegmentDuration == 0 {
+		opts.SegmentDuration = defaultRecordingSegmentDuration
+	}
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	rec := &Recorder{
+		name:   name,
+		source: source,
+		opts:   opts,
+		logger: logger,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	utils.PanicCapturingGo(func() {
+		defer close(rec.done)
+		defer cancel()
+		if err := rec.record(cancelCtx); err != nil {
+			rec.mu.Lock()
+			rec.err = err
+			rec.mu.Unlock()
+		}
+	})
+	return rec, nil
+}
+
+// Stop stops the recording, waits for the current segment to be flushed and returns
+// any error that ended the recording early.
+func (rec *Recorder) Stop() error {
+	rec.cancel()
+	<-rec.done
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.err
+}
+
+// Done is closed once the recording has ended.
+func (rec *Recorder) Done() <-chan struct{} {
+	return rec.done
+}
+
+// Segments returns the paths of the segment files written so far.
+func (rec *Recorder) Segments() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]string(nil), rec.segments...)
+}
+
+type segmentFile struct {
+	f       *os.File
+	started time.Time
+	size    int64
+}
+
+func (rec *Recorder) record(ctx context.Context) (err error) {
+	start := time.Now()
+	frameInterval := time.Duration(float64(time.Second) / rec.opts.FrameRate)
+	var total int64
+	var buf bytes.Buffer
+	var seg *segmentFile
+	defer func() {
+		if seg != nil {
+			err = multierr.Combine(err, seg.f.Close())
+		}
+	}()
+
+	for i := 0; ; {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if rec.opts.MaxDuration != 0 && time.Since(start) >= rec.opts.MaxDuration {
+			return nil
+		}
+		frameStart := time.Now()
+
+		img, release, err := gostream.ReadImage(ctx, rec.source)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			rec.logger.Debugw("error reading frame to record", "name", rec.name, "error", err)
+			if !utils.SelectContextOrWait(ctx, frameInterval) {
+				return nil
+			}
+			continue
+		}
+
+		if seg != nil && (time.Since(seg.started) >= rec.opts.SegmentDuration ||
+			(rec.opts.SegmentMaxBytes != 0 && seg.size >= rec.opts.SegmentMaxBytes)) {
+			if err := seg.f.Close(); err != nil {
+				release()
+				seg = nil
+				return err
+			}
+			seg = nil
+		}
+		if seg == nil {
+			seg, err = rec.newSegment(i)
+			if err != nil {
+				release()
+				return err
+			}
+			i++
+		}
+
+		buf.Reset()
+		err = jpeg.Encode(&buf, img, nil)
+		release()
+		if err != nil {
+			return errors.Wrapf(err, "error encoding frame of %q", rec.name)
+		}
+		n, err := seg.f.Write(buf.Bytes())
+		seg.size += int64(n)
+		total += int64(n)
+		if err != nil {
+			return errors.Wrapf(err, "error writing frame of %q", rec.name)
+		}
+		if rec.opts.MaxBytes != 0 && total >= rec.opts.MaxBytes {
+			return nil
+		}
+
+		if wait := frameInterval - time.Since(frameStart); wait > 0 && !utils.SelectContextOrWait(ctx, wait) {
+			return nil
+		}
+	}
+}
+
+func (rec *Recorder) newSegment(index int) (*segmentFile, error) {
+	now := time.Now()
+	path := filepath.Join(rec.opts.Dir, fmt.Sprintf("%s_%s_%04d.mjpeg", rec.name, now.UTC().Format("20060102T150405"), index))
+	//nolint:gosec
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	rec.mu.Lock()
+	rec.segments = append(rec.segments, path)
+	rec.mu.Unlock()
+	return &segmentFile{f: f, started: now}, nil
+}
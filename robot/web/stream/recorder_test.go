@@ -0,0 +1,78 @@
+package webstream_test
+
+import (
+	"context"
+	"image"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/edaniels/golog"
+	"github.com/edaniels/gostream"
+	"github.com/pion/mediadevices/pkg/prop"
+	"go.viam.com/test"
+
+	webstream "go.viam.com/rdk/robot/web/stream"
+)
+
+type staticVideoReader struct{}
+
+func (staticVideoReader) Read(ctx context.Context) (image.Image, func(), error) {
+	return image.NewRGBA(image.Rect(0, 0, 16, 16)), func() {}, nil
+}
+
+func (staticVideoReader) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	videoSrc := gostream.NewVideoSource(staticVideoReader{}, prop.Video{})
+	defer func() {
+		test.That(t, videoSrc.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	_, err := webstream.NewRecorder(context.Background(), "cam", videoSrc, webstream.RecordingOptions{}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = webstream.NewRecorder(context.Background(), "cam", videoSrc, webstream.RecordingOptions{
+		Dir:      t.TempDir(),
+		MaxBytes: -1,
+	}, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("stops at size limit", func(t *testing.T) {
+		// every frame goes into its own segment and recording stops after a few frames
+		rec, err := webstream.NewRecorder(context.Background(), "cam", videoSrc, webstream.RecordingOptions{
+			Dir:             t.TempDir(),
+			FrameRate:       100,
+			SegmentMaxBytes: 1,
+			MaxBytes:        3000,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		select {
+		case <-rec.Done():
+		case <-time.After(10 * time.Second):
+			t.Fatal("recording did not stop at its size limit")
+		}
+		test.That(t, rec.Stop(), test.ShouldBeNil)
+
+		segments := rec.Segments()
+		test.That(t, len(segments), test.ShouldBeGreaterThan, 1)
+		for _, seg := range segments {
+			data, err := os.ReadFile(seg)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, data[:2], test.ShouldResemble, []byte{0xff, 0xd8})
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		rec, err := webstream.NewRecorder(context.Background(), "cam", videoSrc, webstream.RecordingOptions{
+			Dir:       t.TempDir(),
+			FrameRate: 100,
+		}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		time.Sleep(50 * time.Millisecond)
+		test.That(t, rec.Stop(), test.ShouldBeNil)
+		test.That(t, len(rec.Segments()), test.ShouldEqual, 1)
+	})
+}
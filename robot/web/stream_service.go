@@ -0,0 +1,133 @@
+package web
+
+import (
+	"context"
+
+	streampb "github.com/edaniels/gostream/proto/stream/v1"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	streamcontrolpb "go.viam.com/rdk/proto/api/stream/v1"
+	webstream "go.viam.com/rdk/robot/web/stream"
+	"go.viam.com/rdk/session"
+)
+
+// A controlledStream is a video stream whose options can change while it streams.
+type controlledStream struct {
+	// source is the name of the video source streamed.
+	source  string
+	control *webstream.StreamController
+	// preset is true for the preset variants, which are shared by all of their subscribers.
+	preset bool
+	cancel func()
+}
+
+// streamService serves the streams of a web service, starting preset variants as they are subscribed to.
+type streamService struct {
+	streampb.StreamServiceServer
+	svc *webService
+}
+
+// AddStream adds the named stream to the WebRTC connection of the caller, starting it first if it is a preset
+// variant not yet streaming.
+func (s *streamService) AddStream(ctx context.Context, req *streampb.AddStreamRequest) (*streampb.AddStreamResponse, error) {
+	if _, ok := rpc.ContextPeerConnection(ctx); ok {
+		if err := s.svc.startPresetVariant(req.Name); err != nil {
+			return nil, err
+		}
+	}
+	return s.StreamServiceServer.AddStream(ctx, req)
+}
+
+// streamControlService serves the control of the options of the video streams of a web service.
+type streamControlService struct {
+	streamcontrolpb.UnimplementedStreamControlServiceServer
+	svc *webService
+}
+
+// SetStreamOptions changes the options the video stream in the request is encoded at for all of its subscribers.
+func (s *streamControlService) SetStreamOptions(
+	ctx context.Context,
+	req *streamcontrolpb.SetStreamOptionsRequest,
+) (*streamcontrolpb.SetStreamOptionsResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "no stream named in request")
+	}
+	if err := s.svc.SetStreamOptions(req.Name, streamOptionsFromProto(req.Options)); err != nil {
+		return nil, err
+	}
+	return &streamcontrolpb.SetStreamOptionsResponse{}, nil
+}
+
+// AddStreamVariant adds a variant of the video stream in the request for the caller, which is the session
+// of the request or else the subscriber named in it, and responds with the name to subscribe to it by.
+func (s *streamControlService) AddStreamVariant(
+	ctx context.Context,
+	req *streamcontrolpb.AddStreamVariantRequest,
+) (*streamcontrolpb.AddStreamVariantResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "no stream named in request")
+	}
+	subscriber := req.Subscriber
+	if sess, ok := session.FromContext(ctx); ok {
+		subscriber = sess.ID().String()
+	}
+	if subscriber == "" {
+		return nil, status.Error(codes.InvalidArgument, "a variant needs a session or a subscriber")
+	}
+	name, err := s.svc.AddStreamVariant(ctx, req.Name, subscriber, streamOptionsFromProto(req.Options))
+	if err != nil {
+		return nil, err
+	}
+	return &streamcontrolpb.AddStreamVariantResponse{Name: name}, nil
+}
+
+// StartRecording starts recording the video stream in the request to segmented files on the robot.
+func (s *streamControlService) StartRecording(
+	ctx context.Context,
+	req *streamcontrolpb.StartRecordingRequest,
+) (*streamcontrolpb.StartRecordingResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "no stream named in request")
+	}
+	if err := s.svc.StartRecording(ctx, req.Name, recordingOptionsFromProto(req.Options)); err != nil {
+		return nil, err
+	}
+	return &streamcontrolpb.StartRecordingResponse{}, nil
+}
+
+// StopRecording stops recording the video stream in the request and responds with the segment files written.
+func (s *streamControlService) StopRecording(
+	ctx context.Context,
+	req *streamcontrolpb.StopRecordingRequest,
+) (*streamcontrolpb.StopRecordingResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "no stream named in request")
+	}
+	segments, err := s.svc.StopRecording(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &streamcontrolpb.StopRecordingResponse{Segments: segments}, nil
+}
+
+func recordingOptionsFromProto(opts *streamcontrolpb.RecordingOptions) webstream.RecordingOptions {
+	return webstream.RecordingOptions{
+		Dir:             opts.GetDir(),
+		SegmentDuration: opts.GetSegmentDuration().AsDuration(),
+		SegmentMaxBytes: opts.GetSegmentMaxBytes(),
+		MaxDuration:     opts.GetMaxDuration().AsDuration(),
+		MaxBytes:        opts.GetMaxBytes(),
+		FrameRate:       opts.GetFrameRate(),
+	}
+}
+
+func streamOptionsFromProto(opts *streamcontrolpb.StreamOptions) webstream.StreamOptions {
+	return webstream.StreamOptions{
+		Width:         int(opts.GetWidth()),
+		Height:        int(opts.GetHeight()),
+		FrameRate:     opts.GetFrameRate(),
+		TargetBitrate: int(opts.GetTargetBitrate()),
+	}
+}
@@ -3,6 +3,7 @@ package web
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
@@ -26,9 +27,11 @@ import (
 	streampb "github.com/edaniels/gostream/proto/stream/v1"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/jhump/protoreflect/dynamic"
+	"github.com/pion/mediadevices/pkg/prop"
 	"github.com/pkg/errors"
 	"github.com/rs/cors"
 	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
 	pb "go.viam.com/api/robot/v1"
 	"go.viam.com/utils"
 	"go.viam.com/utils/jwks"
@@ -46,9 +49,12 @@ import (
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/module"
+	configpb "go.viam.com/rdk/proto/api/robot/v1"
+	streamcontrolpb "go.viam.com/rdk/proto/api/stream/v1"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/framesystem"
 	grpcserver "go.viam.com/rdk/robot/server"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	webstream "go.viam.com/rdk/robot/web/stream"
@@ -231,6 +237,19 @@ type Service interface {
 	// Returns the unix socket path the module server listens on.
 	ModuleAddress() string
 
+	// StartRecording starts recording the named camera stream to segmented files on disk.
+	StartRecording(ctx context.Context, name string, opts webstream.RecordingOptions) error
+
+	// StopRecording stops recording the named camera stream and returns the segment files written.
+	StopRecording(name string) ([]string, error)
+
+	// SetStreamOptions changes the resolution, frame rate and bitrate the named video stream is encoded at.
+	SetStreamOptions(name string, opts webstream.StreamOptions) error
+
+	// AddStreamVariant adds a variant of the named video stream with its own options for a single subscriber
+	// that needs a different quality, and returns the name to subscribe to it by.
+	AddStreamVariant(ctx context.Context, name, subscriber string, opts webstream.StreamOptions) (string, error)
+
 	// Close closes the web server
 	Close() error
 }
@@ -256,6 +275,9 @@ func New(ctx context.Context, r robot.Robot, logger golog.Logger, opts ...Option
 		streamServer: nil,
 		services:     make(map[resource.Subtype]subtype.Service),
 		opts:         wOpts,
+		recorders:    make(map[string]*webstream.Recorder),
+		controls:     make(map[string]*controlledStream),
+		videoStreams: make(map[string]gostream.Stream),
 	}
 	return webSvc
 }
@@ -270,8 +292,12 @@ type webService struct {
 	opts         options
 	addr         string
 	modAddr      string
+	recorders    map[string]*webstream.Recorder
+	controls     map[string]*controlledStream
+	videoStreams map[string]gostream.Stream
 
 	logger                  golog.Logger
+	cancelCtx               context.Context
 	cancelFunc              func()
 	activeBackgroundWorkers sync.WaitGroup
 }
@@ -284,6 +310,7 @@ func (svc *webService) Start(ctx context.Context, o weboptions.Options) error {
 		return errors.New("web server already started")
 	}
 	cancelCtx, cancelFunc := context.WithCancel(ctx)
+	svc.cancelCtx = cancelCtx
 	svc.cancelFunc = cancelFunc
 
 	if err := svc.runWeb(cancelCtx, o); err != nil {
@@ -415,6 +442,7 @@ func (svc *webService) Update(ctx context.Context, resources map[resource.Name]i
 	if err := svc.updateResources(resources); err != nil {
 		return err
 	}
+	svc.removeOldStreams()
 	return svc.addNewStreams(ctx)
 }
 
@@ -476,10 +504,166 @@ func (svc *webService) Close() error {
 	if svc.modServer != nil {
 		err = svc.modServer.Stop()
 	}
+	for name, rec := range svc.recorders {
+		err = multierr.Combine(err, rec.Stop())
+		delete(svc.recorders, name)
+	}
+	for name, cs := range svc.controls {
+		cs.cancel()
+		delete(svc.controls, name)
+	}
 	svc.activeBackgroundWorkers.Wait()
 	return err
 }
 
+// StartRecording starts recording the named camera stream to disk. Recording continues
+// until StopRecording is called, a limit in opts is reached or the service is closed.
+func (svc *webService) StartRecording(ctx context.Context, name string, opts webstream.RecordingOptions) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if rec, ok := svc.recorders[name]; ok {
+		select {
+		case <-rec.Done():
+			// the previous recording reached its limits; replace it
+			if err := rec.Stop(); err != nil {
+				svc.logger.Warnw("previous recording ended with error", "name", name, "error", err)
+			}
+		default:
+			return errors.Errorf("stream %q is already being recorded", name)
+		}
+	}
+	source, ok := allVideoSourcesToDisplay(svc.r)[name]
+	if !ok {
+		return errors.Errorf("no video stream named %q", name)
+	}
+	// the recording outlives the request that started it
+	rec, err := webstream.NewRecorder(context.Background(), name, source, opts, svc.logger)
+	if err != nil {
+		return err
+	}
+	svc.recorders[name] = rec
+	return nil
+}
+
+// StopRecording stops recording the named camera stream and returns the segment files written.
+func (svc *webService) StopRecording(name string) ([]string, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	rec, ok := svc.recorders[name]
+	if !ok {
+		return nil, errors.Errorf("stream %q is not being recorded", name)
+	}
+	delete(svc.recorders, name)
+	err := rec.Stop()
+	return rec.Segments(), err
+}
+
+// SetStreamOptions changes the resolution, frame rate and bitrate the named video stream, or variant of one,
+// is encoded at for all of its subscribers, starting with its next frame. The presets are shared by every
+// subscriber of them, so they cannot be changed.
+func (svc *webService) SetStreamOptions(name string, opts webstream.StreamOptions) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	cs, ok := svc.controls[name]
+	if !ok {
+		return errors.Errorf("no video stream named %q", name)
+	}
+	if cs.preset {
+		return errors.Errorf("stream %q is a preset and cannot be changed, add a variant of it instead", name)
+	}
+	return cs.control.SetOptions(opts)
+}
+
+// AddStreamVariant adds a variant of the named video stream, which only the given subscriber receives, so that
+// a subscriber on a poor link can lower its quality without lowering it for everyone. If the subscriber already
+// has a variant of the stream its options are changed.
+func (svc *webService) AddStreamVariant(
+	ctx context.Context,
+	name, subscriber string,
+	opts webstream.StreamOptions,
+) (string, error) {
+	if subscriber == "" {
+		return "", errors.New("a variant needs a subscriber")
+	}
+	if _, ok := webstream.StreamPresets[subscriber]; ok {
+		return "", errors.Errorf("subscriber cannot be named after the %q preset", subscriber)
+	}
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	variantName := webstream.VariantName(name, subscriber)
+	if cs, ok := svc.controls[variantName]; ok {
+		return variantName, cs.control.SetOptions(opts)
+	}
+	return variantName, svc.startVideoVariant(name, variantName, opts, false)
+}
+
+// startPresetVariant starts the named preset variant of a video stream if it is one and has not been started,
+// so that presets are only encoded once someone subscribes to them.
+func (svc *webService) startPresetVariant(variantName string) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if _, ok := svc.controls[variantName]; ok {
+		return nil
+	}
+	name, preset, ok := webstream.ParseVariantName(variantName)
+	if !ok {
+		return nil
+	}
+	opts, ok := webstream.StreamPresets[preset]
+	if !ok {
+		return nil
+	}
+	return svc.startVideoVariant(name, variantName, opts, true)
+}
+
+// startVideoVariant starts streaming a variant of the named video source. It must be called with svc.mu held.
+func (svc *webService) startVideoVariant(name, variantName string, opts webstream.StreamOptions, preset bool) error {
+	if !svc.streamInitialized() || svc.opts.streamConfig == nil {
+		return errors.New("streaming is not enabled")
+	}
+	source, ok := allVideoSourcesToDisplay(svc.r)[name]
+	if !ok {
+		return errors.Errorf("no video stream named %q", name)
+	}
+	stream, ok := svc.videoStreams[variantName]
+	if !ok {
+		config := *svc.opts.streamConfig
+		config.Name = variantName
+		config.AudioEncoderFactory = nil
+		var err error
+		stream, err = svc.streamServer.Server.NewStream(config)
+		if err != nil {
+			return err
+		}
+	}
+	svc.streamServer.HasStreams = true
+	// the variant streams until its source is removed or the web service stops, not just for the request
+	// that added it
+	return svc.startImageStream(svc.cancelCtx, name, source, stream, opts, preset)
+}
+
+// removeOldStreams stops the video streams, variants of them and recordings whose sources have been removed.
+func (svc *webService) removeOldStreams() {
+	videoSources := allVideoSourcesToDisplay(svc.r)
+	for name, cs := range svc.controls {
+		if _, ok := videoSources[cs.source]; !ok {
+			cs.cancel()
+			delete(svc.controls, name)
+		}
+	}
+	for name, rec := range svc.recorders {
+		if _, ok := videoSources[name]; !ok {
+			if err := rec.Stop(); err != nil {
+				svc.logger.Warnw("recording of removed stream ended with error", "name", name, "error", err)
+			}
+			delete(svc.recorders, name)
+		}
+	}
+}
+
 func (svc *webService) streamInitialized() bool {
 	return svc.streamServer != nil && svc.streamServer.Server != nil
 }
@@ -518,14 +702,25 @@ func (svc *webService) addNewStreams(ctx context.Context) error {
 	}
 
 	for name, source := range videoSources {
-		stream, alreadyRegistered, err := newStream(name)
-		if err != nil {
-			return err
-		} else if alreadyRegistered {
+		if _, ok := svc.controls[name]; ok {
 			continue
 		}
+		// a stream whose source was removed is started again once a source by its name is back
+		stream, ok := svc.videoStreams[name]
+		if !ok {
+			var alreadyRegistered bool
+			var err error
+			stream, alreadyRegistered, err = newStream(name)
+			if err != nil {
+				return err
+			} else if alreadyRegistered {
+				continue
+			}
+		}
 
-		svc.startImageStream(ctx, source, stream)
+		if err := svc.startImageStream(ctx, name, source, stream, webstream.StreamOptions{}, false); err != nil {
+			return err
+		}
 	}
 
 	for name, source := range audioSources {
@@ -600,7 +795,10 @@ func (svc *webService) makeStreamServer(ctx context.Context) (*StreamServer, err
 
 	for idx, stream := range streams {
 		if streamTypes[idx] {
-			svc.startImageStream(ctx, videoSources[stream.Name()], stream)
+			name := stream.Name()
+			if err := svc.startImageStream(ctx, name, videoSources[name], stream, webstream.StreamOptions{}, false); err != nil {
+				return nil, err
+			}
 		} else {
 			svc.startAudioStream(ctx, audioSources[stream.Name()], stream)
 		}
@@ -629,11 +827,32 @@ func (svc *webService) startStream(streamFunc func(opts *webstream.BackoffTuning
 	<-waitCh
 }
 
-func (svc *webService) startImageStream(ctx context.Context, source gostream.VideoSource, stream gostream.Stream) {
+// startImageStream streams the named video source through a controller of its options, which is kept so that
+// they can be changed while it streams, until the source is removed.
+func (svc *webService) startImageStream(
+	ctx context.Context,
+	sourceName string,
+	source gostream.VideoSource,
+	stream gostream.Stream,
+	streamOpts webstream.StreamOptions,
+	preset bool,
+) error {
+	control, err := webstream.NewStreamController(source, streamOpts)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	svc.controls[stream.Name()] = &controlledStream{source: sourceName, control: control, preset: preset, cancel: cancel}
+	svc.videoStreams[stream.Name()] = stream
+	controlled := gostream.NewVideoSource(control, prop.Video{})
 	ctxWithJPEGHint := gostream.WithMIMETypeHint(ctx, rutils.WithLazyMIMEType(rutils.MimeTypeJPEG))
 	svc.startStream(func(opts *webstream.BackoffTuningOptions) error {
-		return webstream.StreamVideoSource(ctxWithJPEGHint, source, stream, opts)
+		return multierr.Combine(
+			webstream.StreamVideoSource(ctxWithJPEGHint, controlled, stream, opts),
+			controlled.Close(context.Background()),
+		)
 	})
+	return nil
 }
 
 func (svc *webService) startAudioStream(ctx context.Context, source gostream.AudioSource, stream gostream.Stream) {
@@ -721,6 +940,32 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 	); err != nil {
 		return err
 	}
+	if err := svc.rpcServer.RegisterServiceServer(
+		ctx,
+		&configpb.RobotConfigService_ServiceDesc,
+		grpcserver.NewConfigServer(svc.r),
+		configpb.RegisterRobotConfigServiceHandlerFromEndpoint,
+	); err != nil {
+		return err
+	}
+
+	if err := svc.rpcServer.RegisterServiceServer(
+		ctx,
+		&configpb.RobotDiscoveryService_ServiceDesc,
+		grpcserver.NewDiscoveryServer(svc.r),
+		configpb.RegisterRobotDiscoveryServiceHandlerFromEndpoint,
+	); err != nil {
+		return err
+	}
+
+	if err := svc.rpcServer.RegisterServiceServer(
+		ctx,
+		&configpb.RobotIntrospectionService_ServiceDesc,
+		grpcserver.NewIntrospectionServer(svc.r),
+		configpb.RegisterRobotIntrospectionServiceHandlerFromEndpoint,
+	); err != nil {
+		return err
+	}
 
 	if err := svc.initResources(); err != nil {
 		return err
@@ -737,11 +982,19 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 	if err := svc.rpcServer.RegisterServiceServer(
 		ctx,
 		&streampb.StreamService_ServiceDesc,
-		svc.streamServer.Server.ServiceServer(),
+		&streamService{StreamServiceServer: svc.streamServer.Server.ServiceServer(), svc: svc},
 		streampb.RegisterStreamServiceHandlerFromEndpoint,
 	); err != nil {
 		return err
 	}
+	if err := svc.rpcServer.RegisterServiceServer(
+		ctx,
+		&streamcontrolpb.StreamControlService_ServiceDesc,
+		&streamControlService{svc: svc},
+		streamcontrolpb.RegisterStreamControlServiceHandlerFromEndpoint,
+	); err != nil {
+		return err
+	}
 	if svc.streamServer.HasStreams {
 		// force WebRTC template rendering
 		options.WebRTC = true
@@ -868,7 +1121,11 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 		rpcOpts = append(rpcOpts, rpc.WithInternalTLSConfig(options.Network.TLSConfig))
 	}
 
-	authOpts, err := svc.initAuthHandlers(listenerTCPAddr, options)
+	var accessPolicies *accessPolicies
+	if len(options.Auth.AccessPolicies) != 0 {
+		accessPolicies = newAccessPolicies(svc.r, options.Auth.AccessPolicies)
+	}
+	authOpts, err := svc.initAuthHandlers(listenerTCPAddr, options, accessPolicies)
 	if err != nil {
 		return nil, err
 	}
@@ -876,6 +1133,11 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 
 	var streamInterceptors []googlegrpc.StreamServerInterceptor
 
+	if accessPolicies != nil {
+		unaryInterceptors = append(unaryInterceptors, accessPolicies.unaryInterceptor)
+		streamInterceptors = append(streamInterceptors, accessPolicies.streamInterceptor)
+	}
+
 	opManager := svc.r.OperationManager()
 	sessManagerInts := svc.r.SessionManager().ServerInterceptors()
 	if sessManagerInts.UnaryServerInterceptor != nil {
@@ -903,8 +1165,13 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 	return rpcOpts, nil
 }
 
-// Initialize authentication handler options.
-func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options weboptions.Options) ([]rpc.ServerOption, error) {
+// Initialize authentication handler options. API keys scoped by accessPolicies, which may
+// be nil, are marked as such when authenticating.
+func (svc *webService) initAuthHandlers(
+	listenerTCPAddr *net.TCPAddr,
+	options weboptions.Options,
+	accessPolicies *accessPolicies,
+) ([]rpc.ServerOption, error) {
 	rpcOpts := []rpc.ServerOption{}
 
 	if options.Managed && len(options.Auth.Handlers) == 1 {
@@ -953,10 +1220,11 @@ func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options we
 					}
 					apiKeys = []string{apiKey}
 				}
-				rpcOpts = append(rpcOpts, rpc.WithAuthHandler(
-					handler.Type,
-					rpc.MakeSimpleMultiAuthHandler(authEntities, apiKeys),
-				))
+				authHandler := rpc.MakeSimpleMultiAuthHandler(authEntities, apiKeys)
+				if accessPolicies != nil {
+					authHandler = accessPolicies.wrapAuthHandler(authHandler)
+				}
+				rpcOpts = append(rpcOpts, rpc.WithAuthHandler(handler.Type, authHandler))
 			case rutils.CredentialsTypeRobotLocationSecret:
 				locationSecrets := handler.Config.StringSlice("secrets")
 				if len(locationSecrets) == 0 {
@@ -1066,6 +1334,12 @@ func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 		return nil, err
 	}
 
+	if options.Debug {
+		mux.HandleFunc(pat.Get("/debug/resource_graph"), svc.handleResourceGraph)
+		mux.HandleFunc(pat.Get("/debug/frame_system"), svc.handleFrameSystemSnapshot)
+		mux.HandleFunc(pat.Get("/debug/operations"), svc.handleOperationTree)
+	}
+
 	if options.Pprof {
 		mux.HandleFunc(pat.New("/debug/pprof/"), pprof.Index)
 		mux.HandleFunc(pat.New("/debug/pprof/cmdline"), pprof.Cmdline)
@@ -1101,6 +1375,49 @@ func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 	return mux, nil
 }
 
+// handleResourceGraph serves the robot's resource dependency graph as JSON, or as
+// Graphviz DOT when requested with ?format=dot.
+func (svc *webService) handleResourceGraph(w http.ResponseWriter, r *http.Request) {
+	provider, ok := svc.r.(robot.ResourceGraphProvider)
+	if !ok {
+		http.Error(w, "robot does not expose its resource graph", http.StatusNotImplemented)
+		return
+	}
+	graph := provider.ResourceGraph()
+	if r.URL.Query().Get("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		_, err := io.WriteString(w, graph.DOT())
+		utils.UncheckedError(err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	utils.UncheckedError(json.NewEncoder(w).Encode(graph))
+}
+
+// handleFrameSystemSnapshot serves the current world pose and geometries of every frame
+// in the robot's frame system as JSON.
+func (svc *webService) handleFrameSystemSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := framesystem.RobotSnapshot(r.Context(), svc.r, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	utils.UncheckedError(json.NewEncoder(w).Encode(map[string]interface{}{"frames": snapshots}))
+}
+
+// handleOperationTree serves the running operations of the robot as JSON, arranged by which
+// operation started which, for debugging where the time of a call goes.
+func (svc *webService) handleOperationTree(w http.ResponseWriter, r *http.Request) {
+	opManager := svc.r.OperationManager()
+	if opManager == nil {
+		http.Error(w, "robot does not track operations", http.StatusNotImplemented)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	utils.UncheckedError(json.NewEncoder(w).Encode(map[string]interface{}{"operations": opManager.Tree()}))
+}
+
 func (svc *webService) foreignServiceHandler(srv interface{}, stream googlegrpc.ServerStream) error {
 	method, ok := googlegrpc.MethodFromServerStream(stream)
 	if !ok {
@@ -6,7 +6,9 @@ import (
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"image"
 	"net"
+	"os"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/lestrrat-go/jwx/jwk"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	armpb "go.viam.com/api/component/arm/v1"
 	echopb "go.viam.com/api/component/testecho/v1"
 	robotpb "go.viam.com/api/robot/v1"
 	"go.viam.com/test"
@@ -36,13 +39,16 @@ import (
 	"go.viam.com/rdk/config"
 	gizmopb "go.viam.com/rdk/examples/customresources/apis/proto/api/component/gizmo/v1"
 	rgrpc "go.viam.com/rdk/grpc"
+	streamcontrolpb "go.viam.com/rdk/proto/api/stream/v1"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
 	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
+	webstream "go.viam.com/rdk/robot/web/stream"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/subtype"
 	"go.viam.com/rdk/testutils/inject"
@@ -363,6 +369,70 @@ func TestWebWithAuth(t *testing.T) {
 	}
 }
 
+func TestWebWithAccessPolicies(t *testing.T) {
+	logger := golog.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	armDesc, err := grpcreflect.LoadServiceDescriptor(&armpb.ArmService_ServiceDesc)
+	test.That(t, err, test.ShouldBeNil)
+	injectRobot.(*inject.Robot).ResourceRPCSubtypesFunc = func() []resource.RPCSubtype {
+		return []resource.RPCSubtype{{Subtype: arm.Subtype, Desc: armDesc}}
+	}
+
+	svc := web.New(ctx, injectRobot, logger)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.Auth.Handlers = []config.AuthHandlerConfig{
+		{
+			Type: rpc.CredentialsTypeAPIKey,
+			Config: config.AttributeMap{
+				"keys": []string{"fullkey", "scopedkey"},
+			},
+		},
+	}
+	options.Auth.AccessPolicies = []config.AccessPolicyConfig{
+		{
+			Name:  "read-only",
+			Keys:  []string{"scopedkey"},
+			Rules: []config.AccessRuleConfig{{Resources: []string{arm1String}, Methods: []string{"GetEndPosition"}}},
+		},
+	}
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(context.Background(), svc), test.ShouldBeNil)
+	}()
+
+	// calls over WebRTC are not authenticated per caller
+	_, err = rgrpc.Dial(context.Background(), addr, logger,
+		rpc.WithAllowInsecureWithCredentialsDowngrade(),
+		rpc.WithCredentials(rpc.Credentials{Type: rpc.CredentialsTypeAPIKey, Payload: "scopedkey"}),
+	)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not allow connecting over WebRTC")
+
+	for _, key := range []string{"fullkey", "scopedkey"} {
+		conn, err := rgrpc.Dial(context.Background(), addr, logger,
+			rpc.WithAllowInsecureWithCredentialsDowngrade(),
+			rpc.WithCredentials(rpc.Credentials{Type: rpc.CredentialsTypeAPIKey, Payload: key}),
+			rpc.WithWebRTCOptions(rpc.DialWebRTCOptions{Disable: key == "scopedkey"}),
+		)
+		test.That(t, err, test.ShouldBeNil)
+		arm1 := arm.NewClientFromConn(context.Background(), conn, arm1String, logger)
+
+		arm1Position, err := arm1.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, arm1Position, test.ShouldResemble, pos)
+
+		err = arm1.Stop(ctx, nil)
+		if key == "scopedkey" {
+			test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+		} else {
+			test.That(t, status.Code(err), test.ShouldNotEqual, codes.PermissionDenied)
+		}
+
+		test.That(t, utils.TryClose(context.Background(), arm1), test.ShouldBeNil)
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}
+}
+
 func TestWebWithTLSAuth(t *testing.T) {
 	logger := golog.NewTestLogger(t)
 	ctx, injectRobot := setupRobotCtx(t)
@@ -706,6 +776,60 @@ func TestWebWithStreams(t *testing.T) {
 	test.That(t, resp.Names, test.ShouldContain, camera2Key)
 	test.That(t, resp.Names, test.ShouldHaveLength, 2)
 
+	// Presets are only started for WebRTC subscribers
+	grpcConn, err := rgrpc.Dial(context.Background(), addr, logger, rpc.WithWebRTCOptions(rpc.DialWebRTCOptions{Disable: true}))
+	test.That(t, err, test.ShouldBeNil)
+	lowVariant := webstream.VariantName(camera1Key, "low")
+	_, err = streampb.NewStreamServiceClient(grpcConn).AddStream(ctx, &streampb.AddStreamRequest{Name: lowVariant})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, grpcConn.Close(), test.ShouldBeNil)
+	resp, err = streamClient.ListStreams(ctx, &streampb.ListStreamsRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Names, test.ShouldNotContain, lowVariant)
+
+	// Change the options of a stream and add a variant of it for a single subscriber
+	test.That(t, svc.SetStreamOptions(camera1Key, webstream.StreamOptions{FrameRate: 5}), test.ShouldBeNil)
+	test.That(t, svc.SetStreamOptions(camera1Key, webstream.StreamOptions{FrameRate: -5}), test.ShouldNotBeNil)
+	test.That(t, svc.SetStreamOptions("camera3", webstream.StreamOptions{FrameRate: 5}), test.ShouldNotBeNil)
+	variant, err := svc.AddStreamVariant(ctx, camera1Key, "operator", webstream.StreamOptions{Width: 320, TargetBitrate: 100000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, variant, test.ShouldEqual, webstream.VariantName(camera1Key, "operator"))
+	_, err = svc.AddStreamVariant(ctx, camera1Key, "operator", webstream.StreamOptions{Width: 160})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, svc.SetStreamOptions(variant, webstream.StreamOptions{}), test.ShouldBeNil)
+	_, err = svc.AddStreamVariant(ctx, "camera3", "operator", webstream.StreamOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = svc.AddStreamVariant(ctx, camera1Key, "low", webstream.StreamOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// The same over the stream control service, where subscribers without a session name themselves
+	controlClient := streamcontrolpb.NewStreamControlServiceClient(conn)
+	opts := &streamcontrolpb.StreamOptions{FrameRate: 5}
+	_, err = controlClient.SetStreamOptions(ctx, &streamcontrolpb.SetStreamOptionsRequest{Name: camera2Key, Options: opts})
+	test.That(t, err, test.ShouldBeNil)
+	_, err = controlClient.AddStreamVariant(ctx, &streamcontrolpb.AddStreamVariantRequest{Name: camera2Key, Options: opts})
+	test.That(t, err, test.ShouldNotBeNil)
+	variantResp, err := controlClient.AddStreamVariant(ctx, &streamcontrolpb.AddStreamVariantRequest{
+		Name:       camera2Key,
+		Subscriber: "operator",
+		Options:    opts,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, variantResp.Name, test.ShouldEqual, webstream.VariantName(camera2Key, "operator"))
+
+	resp, err = streamClient.ListStreams(ctx, &streampb.ListStreamsRequest{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Names, test.ShouldContain, variant)
+	test.That(t, resp.Names, test.ShouldHaveLength, 4)
+
+	// Remove a camera, which stops its streams and their variants
+	delete(rs, camera.Named(camera2Key))
+	robot.MockResourcesFromMap(rs)
+	test.That(t, updateable.Update(ctx, rs), test.ShouldBeNil)
+	test.That(t, svc.SetStreamOptions(camera2Key, webstream.StreamOptions{}), test.ShouldNotBeNil)
+	test.That(t, svc.SetStreamOptions(webstream.VariantName(camera2Key, "operator"), webstream.StreamOptions{}), test.ShouldNotBeNil)
+	test.That(t, svc.SetStreamOptions(camera1Key, webstream.StreamOptions{}), test.ShouldBeNil)
+
 	// We need to cancel otherwise we are stuck waiting for WebRTC to start streaming.
 	cancel()
 	test.That(t, utils.TryClose(ctx, streamClient), test.ShouldBeNil)
@@ -765,6 +889,87 @@ func TestWebAddFirstStream(t *testing.T) {
 	test.That(t, conn.Close(), test.ShouldBeNil)
 }
 
+type staticVideoReader struct{}
+
+func (staticVideoReader) Read(ctx context.Context) (image.Image, func(), error) {
+	return image.NewRGBA(image.Rect(0, 0, 16, 16)), func() {}, nil
+}
+
+func (staticVideoReader) Close(ctx context.Context) error {
+	return nil
+}
+
+func TestWebRecording(t *testing.T) {
+	const camera1Key = "camera1"
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+
+	cam1, err := camera.NewFromReader(ctx, staticVideoReader{}, nil, camera.ColorStream)
+	test.That(t, err, test.ShouldBeNil)
+	robot := &inject.Robot{}
+	rs := map[resource.Name]interface{}{camera.Named(camera1Key): cam1}
+	robot.MockResourcesFromMap(rs)
+	robot.LoggerFunc = func() golog.Logger { return logger }
+	robot.ResourceRPCSubtypesFunc = func() []resource.RPCSubtype { return nil }
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	svc := web.New(ctx, robot, logger)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+	defer func() {
+		test.That(t, utils.TryClose(ctx, svc), test.ShouldBeNil)
+	}()
+
+	rc, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rc.Close(ctx), test.ShouldBeNil)
+	}()
+
+	// recordings are started and stopped over the stream control service
+	dir := t.TempDir()
+	opts := webstream.RecordingOptions{Dir: dir, FrameRate: 50}
+	test.That(t, rc.StartRecording(ctx, "camera2", opts), test.ShouldNotBeNil)
+	test.That(t, rc.StartRecording(ctx, camera1Key, opts), test.ShouldBeNil)
+	err = rc.StartRecording(ctx, camera1Key, opts)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "already being recorded")
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		files, err := os.ReadDir(dir)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, files, test.ShouldNotBeEmpty)
+	})
+	segments, err := rc.StopRecording(ctx, camera1Key)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, segments, test.ShouldNotBeEmpty)
+	_, err = rc.StopRecording(ctx, camera1Key)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// removing a camera stops its recording
+	opts.Dir = t.TempDir()
+	test.That(t, rc.StartRecording(ctx, camera1Key, opts), test.ShouldBeNil)
+	delete(rs, camera.Named(camera1Key))
+	robot.MockResourcesFromMap(rs)
+	test.That(t, svc.(resource.Updateable).Update(ctx, rs), test.ShouldBeNil)
+	_, err = svc.StopRecording(camera1Key)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not being recorded")
+	recordedSize := func() int64 {
+		var size int64
+		files, err := os.ReadDir(opts.Dir)
+		test.That(t, err, test.ShouldBeNil)
+		for _, f := range files {
+			info, err := f.Info()
+			test.That(t, err, test.ShouldBeNil)
+			size += info.Size()
+		}
+		return size
+	}
+	size := recordedSize()
+	time.Sleep(100 * time.Millisecond)
+	test.That(t, recordedSize(), test.ShouldEqual, size)
+}
+
 func setupRobotCtx(t *testing.T) (context.Context, robot.Robot) {
 	t.Helper()
 
@@ -31,18 +31,29 @@ func init() {
 	resource.AddDefaultService(motion.Named(resource.DefaultServiceName))
 }
 
-// NewBuiltIn returns a new move and grab service for the given robot.
+// NewBuiltIn returns a new move and grab service for the given robot. The directories plans and RRT maps are kept in across restarts
+// are set by its plan_cache_dir and rrt_map_dir attributes, and cannot be set by the options of a request.
 func NewBuiltIn(ctx context.Context, r robot.Robot, config config.Service, logger golog.Logger) (motion.Service, error) {
+	attrs := config.Attributes.Getter()
+	dirs := motionplan.StorageDirs{
+		PlanCacheDir: attrs.GetString("plan_cache_dir", ""),
+		RRTMapDir:    attrs.GetString("rrt_map_dir", ""),
+	}
+	if err := attrs.Err(); err != nil {
+		return nil, err
+	}
 	return &builtIn{
-		r:      r,
-		logger: logger,
+		r:           r,
+		logger:      logger,
+		storageDirs: dirs,
 	}, nil
 }
 
 type builtIn struct {
 	generic.Unimplemented
-	r      robot.Robot
-	logger golog.Logger
+	r           robot.Robot
+	logger      golog.Logger
+	storageDirs motionplan.StorageDirs
 }
 
 // Move takes a goal location and will plan and execute a movement to move a component specified by its name to that destination.
@@ -87,7 +98,7 @@ func (ms *builtIn) Move(
 	goalPose, _ := tf.(*referenceframe.PoseInFrame)
 
 	// the goal is to move the component to goalPose which is specified in coordinates of goalFrameName
-	output, err := motionplan.PlanMotion(ctx,
+	output, err := motionplan.PlanMotion(motionplan.ContextWithStorageDirs(ctx, ms.storageDirs),
 		logger,
 		goalPose,
 		movingFrame,
@@ -107,8 +118,7 @@ func (ms *builtIn) Move(
 			if len(inputs) == 0 {
 				continue
 			}
-			err := resources[name].GoToInputs(ctx, inputs)
-			if err != nil {
+			if err := goToInputs(ctx, name, resources[name], inputs); err != nil {
 				return false, err
 			}
 		}
@@ -116,6 +126,13 @@ func (ms *builtIn) Move(
 	return true, nil
 }
 
+// goToInputs moves a component of a plan as a child operation of the move.
+func goToInputs(ctx context.Context, name string, component referenceframe.InputEnabled, inputs []referenceframe.Input) error {
+	ctx, done := operation.CreateChild(ctx, name+".GoToInputs", nil)
+	defer done()
+	return component.GoToInputs(ctx, inputs)
+}
+
 // MoveSingleComponent will pass through a move command to a component with a MoveToPosition method that takes a pose. Arms are the only
 // component that supports this. This method will transform the destination pose, given in an arbitrary frame, into the pose of the arm.
 // The arm will then move its most distal link to that pose. If you instead wish to move any other component than the arm end to that pose,
@@ -164,6 +181,8 @@ func (ms *builtIn) MoveSingleComponent(
 		goalPose = goalPoseInFrame.Pose()
 		logger.Debugf("converted goal pose %q", spatialmath.PoseToProtobuf(goalPose))
 	}
+	ctx, done := operation.CreateChild(ctx, componentName.ShortName()+".MoveToPosition", nil)
+	defer done()
 	err := movableArm.MoveToPosition(ctx, goalPose, worldState, extra)
 	if err == nil {
 		return true, nil
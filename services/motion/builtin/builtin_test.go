@@ -17,12 +17,15 @@ import (
 	commonpb "go.viam.com/api/common/v1"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/client"
 	framesystemparts "go.viam.com/rdk/robot/framesystem/parts"
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/services/motion/builtin"
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/robottestutils"
 )
 
 func setupMotionServiceFromConfig(t *testing.T, configFilename string) motion.Service {
@@ -211,6 +214,67 @@ func TestMultiplePieces(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 }
 
+// blockingInputs is a component whose GoToInputs blocks until it is released.
+type blockingInputs struct {
+	moving  chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingInputs) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	return []referenceframe.Input{{Value: 0}}, nil
+}
+
+func (b *blockingInputs) GoToInputs(ctx context.Context, goal []referenceframe.Input) error {
+	close(b.moving)
+	<-b.release
+	return nil
+}
+
+func TestGoToInputsOperation(t *testing.T) {
+	ctx := context.Background()
+	logger := golog.NewTestLogger(t)
+	r, err := robotimpl.New(ctx, &config.Config{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	}()
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+	rc, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, rc.Close(context.Background()), test.ShouldBeNil)
+	}()
+
+	moveCtx, done := r.OperationManager().Create(ctx, "/viam.service.motion.v1.MotionService/Move", nil)
+	defer done()
+	component := &blockingInputs{moving: make(chan struct{}), release: make(chan struct{})}
+	moved := make(chan error, 1)
+	go func() {
+		moved <- builtin.GoToInputs(moveCtx, "pieceArm", component, []referenceframe.Input{{Value: 1}})
+	}()
+	<-component.moving
+
+	// the move of the component shows up under the move it is part of, in the same trace
+	tree, err := rc.OperationTree(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tree, test.ShouldHaveLength, 1)
+	parent := operation.Get(moveCtx)
+	test.That(t, tree[0].ID, test.ShouldEqual, parent.ID)
+	test.That(t, tree[0].Children, test.ShouldHaveLength, 1)
+	child := tree[0].Children[0]
+	test.That(t, child.Method, test.ShouldEqual, "pieceArm.GoToInputs")
+	test.That(t, child.ParentID, test.ShouldEqual, parent.ID)
+	test.That(t, child.TraceID, test.ShouldEqual, parent.TraceID)
+
+	close(component.release)
+	test.That(t, <-moved, test.ShouldBeNil)
+	tree, err = rc.OperationTree(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tree, test.ShouldHaveLength, 1)
+	test.That(t, tree[0].Children, test.ShouldBeEmpty)
+}
+
 func TestGetPose(t *testing.T) {
 	var err error
 	ms := setupMotionServiceFromConfig(t, "../data/arm_gantry.json")
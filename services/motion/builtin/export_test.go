@@ -0,0 +1,5 @@
+// export_test.go adds functionality to the builtin package that we only want to use and expose during testing.
+package builtin
+
+// Make goToInputs global for tests.
+var GoToInputs = goToInputs
@@ -6,7 +6,6 @@ import (
 
 	"github.com/edaniels/golog"
 	pb "go.viam.com/api/service/motion/v1"
-	vprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/protoutils"
@@ -41,7 +40,7 @@ func (c *client) Move(
 	worldState *referenceframe.WorldState,
 	extra map[string]interface{},
 ) (bool, error) {
-	ext, err := vprotoutils.StructToStructPb(extra)
+	ext, err := protoutils.MapToStructPb(extra)
 	if err != nil {
 		return false, err
 	}
@@ -69,7 +68,7 @@ func (c *client) MoveSingleComponent(
 	worldState *referenceframe.WorldState,
 	extra map[string]interface{},
 ) (bool, error) {
-	ext, err := vprotoutils.StructToStructPb(extra)
+	ext, err := protoutils.MapToStructPb(extra)
 	if err != nil {
 		return false, err
 	}
@@ -97,7 +96,7 @@ func (c *client) GetPose(
 	supplementalTransforms []*referenceframe.LinkInFrame,
 	extra map[string]interface{},
 ) (*referenceframe.PoseInFrame, error) {
-	ext, err := vprotoutils.StructToStructPb(extra)
+	ext, err := protoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -9,7 +9,6 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/navigation/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -36,7 +35,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) Mode(ctx context.Context, extra map[string]interface{}) (Mode, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return 0, err
 	}
@@ -58,7 +57,7 @@ func (c *client) Mode(ctx context.Context, extra map[string]interface{}) (Mode,
 }
 
 func (c *client) SetMode(ctx context.Context, mode Mode, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -79,7 +78,7 @@ func (c *client) SetMode(ctx context.Context, mode Mode, extra map[string]interf
 }
 
 func (c *client) Location(ctx context.Context, extra map[string]interface{}) (*geo.Point, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +92,7 @@ func (c *client) Location(ctx context.Context, extra map[string]interface{}) (*g
 }
 
 func (c *client) Waypoints(ctx context.Context, extra map[string]interface{}) ([]Waypoint, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +118,7 @@ func (c *client) Waypoints(ctx context.Context, extra map[string]interface{}) ([
 }
 
 func (c *client) AddWaypoint(ctx context.Context, point *geo.Point, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -140,7 +139,7 @@ func (c *client) AddWaypoint(ctx context.Context, point *geo.Point, extra map[st
 }
 
 func (c *client) RemoveWaypoint(ctx context.Context, id primitive.ObjectID, extra map[string]interface{}) error {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -15,6 +15,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"go.uber.org/multierr"
 	mongoutils "go.viam.com/utils/mongo"
+
+	"go.viam.com/rdk/config"
 )
 
 var errNoMoreWaypoints = errors.New("no more waypoints")
@@ -162,13 +164,14 @@ var (
 )
 
 // NewMongoDBNavigationStore creates a new navigation store using MongoDB.
-func NewMongoDBNavigationStore(ctx context.Context, config map[string]interface{}) (*MongoDBNavigationStore, error) {
+func NewMongoDBNavigationStore(ctx context.Context, storeConfig map[string]interface{}) (*MongoDBNavigationStore, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	uri, ok := config["uri"].(string)
-	if !ok {
-		uri = defaultMongoDBURI
+	attrs := config.AttributeMap(storeConfig).Getter()
+	uri := attrs.GetString("uri", defaultMongoDBURI)
+	if err := attrs.Err(); err != nil {
+		return nil, err
 	}
 
 	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
@@ -7,7 +7,6 @@ import (
 	"github.com/edaniels/golog"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/sensors/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	rprotoutils "go.viam.com/rdk/protoutils"
@@ -35,7 +34,7 @@ func NewClientFromConn(ctx context.Context, conn rpc.ClientConn, name string, lo
 }
 
 func (c *client) Sensors(ctx context.Context, extra map[string]interface{}) ([]resource.Name, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +54,7 @@ func (c *client) Readings(ctx context.Context, sensorNames []resource.Name, extr
 	for _, name := range sensorNames {
 		names = append(names, rprotoutils.ResourceNameToProto(name))
 	}
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
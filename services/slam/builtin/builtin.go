@@ -24,7 +24,6 @@ import (
 	"go.opencensus.io/trace"
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/pexec"
-	"go.viam.com/utils/protoutils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
@@ -34,6 +33,7 @@ import (
 	"go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/config"
 	pc "go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/registry"
 	"go.viam.com/rdk/resource"
@@ -418,7 +418,7 @@ func (slamSvc *builtIn) Position(ctx context.Context, name string, extra map[str
 	ctx, span := trace.StartSpan(ctx, "slam::builtIn::Position")
 	defer span.End()
 
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := protoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -455,20 +455,21 @@ func (slamSvc *builtIn) Position(ctx context.Context, name string, extra map[str
 	// TODO DATA-531: https://viam.atlassian.net/jira/software/c/projects/DATA/boards/30?modal=detail&selectedIssue=DATA-531
 	// Remove extraction and conversion of quaternion from the extra field in the response once the Rust
 	// spatial math library is available and the desired math can be implemented on the orbSLAM side
-	if val, ok := returnedExt["quat"]; ok {
-		q := val.(map[string]interface{})
-
-		valReal, ok1 := q["real"].(float64)
-		valIMag, ok2 := q["imag"].(float64)
-		valJMag, ok3 := q["jmag"].(float64)
-		valKMag, ok4 := q["kmag"].(float64)
+	attrs := config.AttributeMap(returnedExt).Getter()
+	if attrs.Has("quat") {
+		q := attrs.GetObject("quat")
+		quat := &spatialmath.Quaternion{
+			Real: q.GetFloat64("real", 0),
+			Imag: q.GetFloat64("imag", 0),
+			Jmag: q.GetFloat64("jmag", 0),
+			Kmag: q.GetFloat64("kmag", 0),
+		}
 
-		if !ok1 || !ok2 || !ok3 || !ok4 {
-			slamSvc.logger.Debugf("quaternion given, but invalid format detected, %v, skipping quaternion transform", q)
+		if err := attrs.Err(); err != nil || !q.Has("real") || !q.Has("imag") || !q.Has("jmag") || !q.Has("kmag") {
+			slamSvc.logger.Debugf("quaternion given, but invalid format detected, %v, skipping quaternion transform", returnedExt["quat"])
 			return pInFrame, nil
 		}
-		newPose := spatialmath.NewPose(pInFrame.Pose().Point(),
-			&spatialmath.Quaternion{Real: valReal, Imag: valIMag, Jmag: valJMag, Kmag: valKMag})
+		newPose := spatialmath.NewPose(pInFrame.Pose().Point(), quat)
 		pInFrame = referenceframe.NewPoseInFrame(pInFrame.Parent(), newPose)
 	}
 
@@ -503,7 +504,7 @@ func (slamSvc *builtIn) GetMap(
 		cameraPosition = referenceframe.PoseInFrameToProtobuf(cp).Pose
 	}
 
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := protoutils.MapToStructPb(extra)
 	if err != nil {
 		return "", nil, nil, err
 	}
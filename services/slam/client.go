@@ -12,7 +12,6 @@ import (
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	pb "go.viam.com/api/service/slam/v1"
-	"go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/pointcloud"
@@ -49,7 +48,7 @@ func (c *client) Position(ctx context.Context, name string, extra map[string]int
 	ctx, span := trace.StartSpan(ctx, "slam::client::Position")
 	defer span.End()
 
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +84,7 @@ func (c *client) GetMap(
 	ctx, span := trace.StartSpan(ctx, "slam::client::GetMap")
 	defer span.End()
 
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return "", nil, nil, err
 	}
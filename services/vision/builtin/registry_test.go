@@ -82,7 +82,7 @@ func TestDetectorRemoval(t *testing.T) {
 		return []objdet.Detection{objdet.NewDetection(image.Rectangle{}, 0.0, "")}, nil
 	}
 	ctx := context.Background()
-	closer, err := addTFLiteModel(ctx, artifact.MustPath("vision/tflite/effdet0.tflite"), nil)
+	closer, err := addTFLiteModel(ctx, artifact.MustPath("vision/tflite/effdet0.tflite"), nil, nil, golog.NewTestLogger(t))
 	test.That(t, err, test.ShouldBeNil)
 	d := registeredModel{Model: fakeDetectFn, Closer: closer, ModelType: TFLiteDetector}
 	testlog := golog.NewTestLogger(t)
@@ -218,7 +218,7 @@ func TestClassifierRemoval(t *testing.T) {
 		return []classification.Classification{classification.NewClassification(0.0, "nothing")}, nil
 	}
 	ctx := context.Background()
-	closer, err := addTFLiteModel(ctx, artifact.MustPath("vision/tflite/effnet0.tflite"), nil)
+	closer, err := addTFLiteModel(ctx, artifact.MustPath("vision/tflite/effnet0.tflite"), nil, nil, golog.NewTestLogger(t))
 	test.That(t, err, test.ShouldBeNil)
 	d := registeredModel{Model: fakeClassifyFn, Closer: closer, ModelType: TFLiteClassifier}
 	testlog := golog.NewTestLogger(t)
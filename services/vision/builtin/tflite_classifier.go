@@ -26,6 +26,8 @@ type TFLiteClassifierConfig struct {
 	ModelPath  string  `json:"model_path"`
 	NumThreads int     `json:"num_threads"`
 	LabelPath  *string `json:"label_path"`
+	// EdgeTPU runs the model on a Coral EdgeTPU when set, falling back to the CPU when none is found.
+	EdgeTPU *inf.EdgeTPUOptions `json:"edgetpu,omitempty"`
 }
 
 // NewTFLiteClassifier creates an RDK classifier given a VisModelConfig. In other words, this
@@ -53,7 +55,7 @@ func NewTFLiteClassifier(ctx context.Context, conf *vision.VisModelConfig,
 		params.NumThreads = runtime.NumCPU() / 4
 	}
 
-	model, err := addTFLiteModel(ctx, params.ModelPath, &params.NumThreads)
+	model, err := addTFLiteModel(ctx, params.ModelPath, &params.NumThreads, params.EdgeTPU, logger)
 	if err != nil {
 		return nil, nil, errors.Wrapf(err, "something wrong with adding the model")
 	}
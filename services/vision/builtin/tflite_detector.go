@@ -32,6 +32,8 @@ type TFLiteDetectorConfig struct {
 	NumThreads int     `json:"num_threads"`
 	LabelPath  *string `json:"label_path"`
 	ServiceURL *string `json:"service_url"`
+	// EdgeTPU runs the model on a Coral EdgeTPU when set, falling back to the CPU when none is found.
+	EdgeTPU *inf.EdgeTPUOptions `json:"edgetpu,omitempty"`
 }
 
 // NewTFLiteDetector creates an RDK detector given a DetectorConfig. In other words, this
@@ -62,7 +64,7 @@ func NewTFLiteDetector(
 	}
 
 	// Add the model
-	model, err := addTFLiteModel(ctx, params.ModelPath, &params.NumThreads)
+	model, err := addTFLiteModel(ctx, params.ModelPath, &params.NumThreads, params.EdgeTPU, logger)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "something wrong with adding the model")
 	}
@@ -99,17 +101,31 @@ func NewTFLiteDetector(
 }
 
 // addTFLiteModel uses the loader (default or otherwise) from the inference package
-// to register a tflite model. Default is chosen if there's no numThreads given.
-func addTFLiteModel(ctx context.Context, filepath string, numThreads *int) (*inf.TFLiteStruct, error) {
+// to register a tflite model. Default is chosen if there's no numThreads given, and
+// the model runs on an EdgeTPU if edgeTPU is given and one is found.
+func addTFLiteModel(
+	ctx context.Context,
+	filepath string,
+	numThreads *int,
+	edgeTPU *inf.EdgeTPUOptions,
+	logger golog.Logger,
+) (*inf.TFLiteStruct, error) {
 	_, span := trace.StartSpan(ctx, "service::vision::addTFLiteModel")
 	defer span.End()
 	var model *inf.TFLiteStruct
 	var loader *inf.TFLiteModelLoader
 	var err error
 
-	if numThreads == nil {
+	switch {
+	case edgeTPU != nil:
+		threads := runtime.NumCPU()
+		if numThreads != nil && *numThreads > 0 {
+			threads = *numThreads
+		}
+		loader, err = inf.NewTFLiteModelLoaderWithEdgeTPU(threads, *edgeTPU, logger)
+	case numThreads == nil:
 		loader, err = inf.NewDefaultTFLiteModelLoader()
-	} else {
+	default:
 		loader, err = inf.NewTFLiteModelLoader(*numThreads)
 	}
 	if err != nil {
@@ -132,6 +148,9 @@ func addTFLiteModel(ctx context.Context, filepath string, numThreads *int) (*inf
 		}
 		return nil, errors.Wrap(err, "loader could not load model")
 	}
+	if device := model.EdgeTPU(); device != nil {
+		logger.Infow("running model on EdgeTPU", "model", filepath, "type", device.Type, "path", device.Path)
+	}
 
 	return model, nil
 }
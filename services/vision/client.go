@@ -51,7 +51,7 @@ func (c *client) GetModelParameterSchema(
 ) (*jsonschema.Schema, error) {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::GetModelParameterSchema")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +73,7 @@ func (c *client) GetModelParameterSchema(
 func (c *client) DetectorNames(ctx context.Context, extra map[string]interface{}) ([]string, error) {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::DetectorNames")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +91,7 @@ func (c *client) AddDetector(ctx context.Context, cfg VisModelConfig, extra map[
 	if err != nil {
 		return err
 	}
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -111,7 +111,7 @@ func (c *client) AddDetector(ctx context.Context, cfg VisModelConfig, extra map[
 func (c *client) RemoveDetector(ctx context.Context, detectorName string, extra map[string]interface{}) error {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::RemoveDetector")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -133,7 +133,7 @@ func (c *client) DetectionsFromCamera(
 ) ([]objdet.Detection, error) {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::DetectionsFromCamera")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +167,7 @@ func (c *client) Detections(ctx context.Context, img image.Image, detectorName s
 	if err != nil {
 		return nil, err
 	}
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +198,7 @@ func (c *client) Detections(ctx context.Context, img image.Image, detectorName s
 func (c *client) ClassifierNames(ctx context.Context, extra map[string]interface{}) ([]string, error) {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::ClassifierNames")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +216,7 @@ func (c *client) AddClassifier(ctx context.Context, cfg VisModelConfig, extra ma
 	if err != nil {
 		return err
 	}
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -236,7 +236,7 @@ func (c *client) AddClassifier(ctx context.Context, cfg VisModelConfig, extra ma
 func (c *client) RemoveClassifier(ctx context.Context, classifierName string, extra map[string]interface{}) error {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::RemoveClassifier")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -256,7 +256,7 @@ func (c *client) ClassificationsFromCamera(ctx context.Context, cameraName,
 ) (classification.Classifications, error) {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::ClassificationsFromCamera")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +288,7 @@ func (c *client) Classifications(ctx context.Context, img image.Image,
 	if err != nil {
 		return nil, err
 	}
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -314,7 +314,7 @@ func (c *client) Classifications(ctx context.Context, img image.Image,
 }
 
 func (c *client) SegmenterNames(ctx context.Context, extra map[string]interface{}) ([]string, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -332,7 +332,7 @@ func (c *client) AddSegmenter(ctx context.Context, cfg VisModelConfig, extra map
 	if err != nil {
 		return err
 	}
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -352,7 +352,7 @@ func (c *client) AddSegmenter(ctx context.Context, cfg VisModelConfig, extra map
 func (c *client) RemoveSegmenter(ctx context.Context, segmenterName string, extra map[string]interface{}) error {
 	ctx, span := trace.StartSpan(ctx, "service::vision::client::RemoveSegmenter")
 	defer span.End()
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return err
 	}
@@ -371,7 +371,7 @@ func (c *client) GetObjectPointClouds(ctx context.Context,
 	cameraName string,
 	segmenterName string, extra map[string]interface{},
 ) ([]*vision.Object, error) {
-	ext, err := protoutils.StructToStructPb(extra)
+	ext, err := rprotoutils.MapToStructPb(extra)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,175 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// boundedGeometryField is the number of a field the Geometry message does not define, which records the geometry that a message of a
+// geometry bounding it was sent for. Decoders that do not know it skip it and read the bounding geometry.
+const boundedGeometryField protowire.Number = 1000
+
+// boundedGeometryKind is a kind of geometry with no message of its own, which is sent as a geometry bounding it.
+type boundedGeometryKind uint64
+
+// Kinds of geometries sent as geometries bounding them.
+const (
+	boundedCylinder boundedGeometryKind = iota + 1
+	boundedCone
+	boundedVoxelGrid
+)
+
+// Numbers of the fields of a boundedGeometryRecord.
+const (
+	boundedKindField      protowire.Number = 1
+	boundedLengthField    protowire.Number = 2
+	boundedVoxelSizeField protowire.Number = 3
+	boundedVoxelsField    protowire.Number = 4
+)
+
+// boundedGeometryRecord records what a geometry sent as a geometry bounding it is made of, beyond what the bounding geometry gives.
+type boundedGeometryRecord struct {
+	kind boundedGeometryKind
+	// length is the length of a cylinder or cone, whose radius is that of the capsule bounding it.
+	length float64
+	// voxelSize and voxels are the size of the voxels of a voxel grid and the indices of them, relative to the pose of the grid.
+	voxelSize float64
+	voxels    [][3]int64
+}
+
+// recordBoundedGeometry records a geometry in the unknown fields of a message of a geometry bounding it.
+func recordBoundedGeometry(geometry *commonpb.Geometry, record boundedGeometryRecord) {
+	var data []byte
+	data = protowire.AppendTag(data, boundedKindField, protowire.VarintType)
+	data = protowire.AppendVarint(data, uint64(record.kind))
+	switch record.kind {
+	case boundedCylinder, boundedCone:
+		data = protowire.AppendTag(data, boundedLengthField, protowire.Fixed64Type)
+		data = protowire.AppendFixed64(data, math.Float64bits(record.length))
+	case boundedVoxelGrid:
+		data = protowire.AppendTag(data, boundedVoxelSizeField, protowire.Fixed64Type)
+		data = protowire.AppendFixed64(data, math.Float64bits(record.voxelSize))
+		var voxels []byte
+		for _, key := range record.voxels {
+			for _, index := range key {
+				voxels = protowire.AppendVarint(voxels, protowire.EncodeZigZag(index))
+			}
+		}
+		data = protowire.AppendTag(data, boundedVoxelsField, protowire.BytesType)
+		data = protowire.AppendBytes(data, voxels)
+	}
+	unknown := protowire.AppendTag(nil, boundedGeometryField, protowire.BytesType)
+	unknown = protowire.AppendBytes(unknown, data)
+	geometry.ProtoReflect().SetUnknown(unknown)
+}
+
+// boundedGeometryFromProto returns the geometry a Geometry proto message of a geometry bounding it was sent for, or false if the
+// message does not record one.
+func boundedGeometryFromProto(geometry *commonpb.Geometry) (Geometry, bool, error) {
+	data, ok := unknownBytesField(geometry.ProtoReflect().GetUnknown(), boundedGeometryField)
+	if !ok {
+		return nil, false, nil
+	}
+	record, err := parseBoundedGeometryRecord(data)
+	if err != nil {
+		return nil, false, err
+	}
+	pose := NewPoseFromProtobuf(geometry.Center)
+	switch capsule, box := geometry.GetCapsule(), geometry.GetBox(); {
+	case record.kind == boundedCylinder && capsule != nil:
+		g, err := NewCylinder(pose, capsule.RadiusMm, record.length, geometry.Label)
+		return g, true, err
+	case record.kind == boundedCone && capsule != nil:
+		g, err := NewCone(pose, capsule.RadiusMm, record.length, geometry.Label)
+		return g, true, err
+	case record.kind == boundedVoxelGrid && box != nil:
+		centers := make([]r3.Vector, 0, len(record.voxels))
+		for _, key := range record.voxels {
+			centers = append(centers, r3.Vector{
+				X: (float64(key[0]) + 0.5) * record.voxelSize,
+				Y: (float64(key[1]) + 0.5) * record.voxelSize,
+				Z: (float64(key[2]) + 0.5) * record.voxelSize,
+			})
+		}
+		g, err := NewVoxelGrid(NewZeroPose(), centers, record.voxelSize, geometry.Label)
+		if err != nil {
+			return nil, true, err
+		}
+		// the box is centered on the voxels, so the grid is offset from it by the center of its voxels
+		grid := g.(*voxelGrid)
+		grid.pose = Compose(pose, NewPoseFromPoint(grid.root.bounds.pose.Point().Mul(-1)))
+		return grid, true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// parseBoundedGeometryRecord parses the fields of a boundedGeometryRecord, skipping any it does not know.
+func parseBoundedGeometryRecord(data []byte) (boundedGeometryRecord, error) {
+	var record boundedGeometryRecord
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return record, protowire.ParseError(n)
+		}
+		data = data[n:]
+		switch {
+		case num == boundedKindField && typ == protowire.VarintType:
+			var kind uint64
+			kind, n = protowire.ConsumeVarint(data)
+			record.kind = boundedGeometryKind(kind)
+		case num == boundedLengthField && typ == protowire.Fixed64Type:
+			var bits uint64
+			bits, n = protowire.ConsumeFixed64(data)
+			record.length = math.Float64frombits(bits)
+		case num == boundedVoxelSizeField && typ == protowire.Fixed64Type:
+			var bits uint64
+			bits, n = protowire.ConsumeFixed64(data)
+			record.voxelSize = math.Float64frombits(bits)
+		case num == boundedVoxelsField && typ == protowire.BytesType:
+			var voxels []byte
+			voxels, n = protowire.ConsumeBytes(data)
+			var key [3]int64
+			for i := 0; len(voxels) > 0; i++ {
+				index, m := protowire.ConsumeVarint(voxels)
+				if m < 0 {
+					return record, protowire.ParseError(m)
+				}
+				voxels = voxels[m:]
+				key[i%3] = protowire.DecodeZigZag(index)
+				if i%3 == 2 {
+					record.voxels = append(record.voxels, key)
+				}
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return record, protowire.ParseError(n)
+		}
+		data = data[n:]
+	}
+	return record, nil
+}
+
+// unknownBytesField returns the value of the last length-delimited field with the given number in the unknown fields of a message.
+func unknownBytesField(unknown []byte, field protowire.Number) ([]byte, bool) {
+	var value []byte
+	var found bool
+	for len(unknown) > 0 {
+		num, typ, n := protowire.ConsumeField(unknown)
+		if n < 0 {
+			return nil, false
+		}
+		if num == field && typ == protowire.BytesType {
+			_, _, tagLen := protowire.ConsumeTag(unknown)
+			value, _ = protowire.ConsumeBytes(unknown[tagLen:n])
+			found = true
+		}
+		unknown = unknown[n:]
+	}
+	return value, found
+}
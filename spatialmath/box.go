@@ -153,6 +153,18 @@ func (b *box) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxCollision(other.position, b), nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.CollidesWith(b)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.CollidesWith(b)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.CollidesWith(b)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.CollidesWith(b)
+	}
 	return true, newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -169,6 +181,18 @@ func (b *box) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pointVsBoxDistance(other.position, b), nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(b)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.DistanceFrom(b)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.DistanceFrom(b)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.DistanceFrom(b)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(b, g)
 }
 
@@ -182,6 +206,15 @@ func (b *box) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*capsule); ok {
 		return boxInCapsule(b, other), nil
 	}
+	if other, ok := g.(*convexHull); ok {
+		return boxInRegion(b, other.containsPoint), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return boxInRegion(b, other.containsPoint), nil
+	}
+	if other, ok := g.(*cone); ok {
+		return boxInRegion(b, other.containsPoint), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
@@ -207,6 +240,29 @@ func (b *box) closestPoint(pt r3.Vector) r3.Vector {
 	return result
 }
 
+// pointSeparation returns the distance from the box to a pt outside of it, or 0 if the pt is inside it. Unlike the distance to the
+// closestPoint, it is exactly 0 for a pt inside the box, as it is not found by moving back to the pt from the center of the box.
+func (b *box) pointSeparation(pt r3.Vector) float64 {
+	direction := pt.Sub(b.pose.Point())
+	rm := b.pose.Orientation().RotationMatrix()
+	var outside r3.Vector
+	for i := 0; i < 3; i++ {
+		excess := math.Abs(direction.Dot(rm.Row(i))) - b.halfSize[i]
+		if excess <= 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			outside.X = excess
+		case 1:
+			outside.Y = excess
+		default:
+			outside.Z = excess
+		}
+	}
+	return outside.Norm()
+}
+
 // penetrationDepth returns the minimum distance needed to move a pt inside the box to the edge of the box.
 func (b *box) pointPenetrationDepth(pt r3.Vector) float64 {
 	direction := pt.Sub(b.pose.Point())
@@ -376,6 +432,17 @@ func boxInCapsule(b *box, c *capsule) bool {
 	return true
 }
 
+// boxInRegion returns a bool describing if the given box is completely encompassed by a convex region, given by whether it contains a
+// point to within a tolerance.
+func boxInRegion(b *box, containsPoint func(pt r3.Vector, tolerance float64) bool) bool {
+	for _, vertex := range b.vertices() {
+		if !containsPoint(vertex, CollisionBuffer) {
+			return false
+		}
+	}
+	return true
+}
+
 // separatingAxisTest projects two boxes onto the given plane and compute how much distance is between them along
 // this plane.  Per the separating hyperplane theorem, if such a plane exists (and a positive number is returned)
 // this proves that there is no collision between the boxes
@@ -166,6 +166,18 @@ func (c *capsule) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleVsSphereDistance(c, other), nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(c)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.DistanceFrom(c)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.DistanceFrom(c)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.DistanceFrom(c)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
 }
 
@@ -179,6 +191,15 @@ func (c *capsule) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*sphere); ok {
 		return capsuleInSphere(c, other), nil
 	}
+	if other, ok := g.(*convexHull); ok {
+		return capsuleInRegion(c, other.containsPoint), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return capsuleInRegion(c, other.containsPoint), nil
+	}
+	if other, ok := g.(*cone); ok {
+		return capsuleInRegion(c, other.containsPoint), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
@@ -291,6 +312,12 @@ func capsuleInSphere(c *capsule, s *sphere) bool {
 	return c.segA.Sub(s.pose.Point()).Norm()+c.radius <= s.radius && c.segB.Sub(s.pose.Point()).Norm()+c.radius <= s.radius
 }
 
+// capsuleInRegion returns a bool describing if the given capsule is fully encompassed by a convex region, given by whether it contains a
+// point to within a tolerance, which is negative for points that far inside of it.
+func capsuleInRegion(c *capsule, containsPoint func(pt r3.Vector, tolerance float64) bool) bool {
+	return containsPoint(c.segA, CollisionBuffer-c.radius) && containsPoint(c.segB, CollisionBuffer-c.radius)
+}
+
 // capsuleVsBoxCollision returns immediately as soon as any result is found indicating that the two objects are not in collision.
 func capsuleVsBoxCollision(c *capsule, b *box) bool {
 	centerDist := b.pose.Point().Sub(c.center)
@@ -0,0 +1,176 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// cone is a collision geometry that represents a solid right circular cone, such as a traffic cone. Its axis is the z axis of its pose,
+// with the center of its base half of its length below the pose and its apex half of its length above it.
+type cone struct {
+	pose   Pose
+	radius float64 // radius of the base
+	length float64 // distance from the base to the apex
+	label  string
+
+	// These values are generated at geometry creation time and should not be altered by hand
+	center r3.Vector
+	axis   r3.Vector // unit vector along the z axis of the pose, towards the apex
+}
+
+// NewCone instantiates a new cone Geometry.
+func NewCone(offset Pose, radius, length float64, label string) (Geometry, error) {
+	if radius <= 0 || length <= 0 {
+		return nil, newBadGeometryDimensionsError(&cone{})
+	}
+	return newCone(offset, radius, length, label), nil
+}
+
+func newCone(offset Pose, radius, length float64, label string) *cone {
+	center, axis := poseAxis(offset)
+	return &cone{pose: offset, radius: radius, length: length, label: label, center: center, axis: axis}
+}
+
+func (c *cone) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// String returns a human readable string that represents the cone.
+func (c *cone) String() string {
+	return fmt.Sprintf("Type: Cone, Radius: %.0f, Length: %.0f", c.radius, c.length)
+}
+
+// Label returns the label of this cone.
+func (c *cone) Label() string {
+	if c != nil {
+		return c.label
+	}
+	return ""
+}
+
+// Pose returns the pose of the cone.
+func (c *cone) Pose() Pose {
+	return c.pose
+}
+
+// AlmostEqual compares the cone with another geometry and checks if they are equivalent.
+func (c *cone) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*cone)
+	if !ok {
+		return false
+	}
+	return PoseAlmostEqual(c.pose, other.pose) &&
+		utils.Float64AlmostEqual(c.radius, other.radius, 1e-8) &&
+		utils.Float64AlmostEqual(c.length, other.length, 1e-8)
+}
+
+// Transform premultiplies the cone pose with a transform, allowing the cone to be moved in space.
+func (c *cone) Transform(toPremultiply Pose) Geometry {
+	return newCone(Compose(toPremultiply, c.pose), c.radius, c.length, c.label)
+}
+
+// ToProtobuf converts the cone to a Geometry proto message of the capsule bounding it, as there is no message for cones. The message
+// also records the cone, so that NewGeometryFromProto decodes it as one.
+func (c *cone) ToProtobuf() *commonpb.Geometry {
+	return roundedBoundingCapsule(boundedCone, c.pose, c.radius, c.length, c.label)
+}
+
+// CollidesWith checks if the given cone collides with the given geometry and returns true if it does.
+func (c *cone) CollidesWith(g Geometry) (bool, error) {
+	if other, ok := g.(*voxelGrid); ok {
+		return other.CollidesWith(c)
+	}
+	distance, err := c.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return distance <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance from the cone to the given geometry. If they are in collision, it is an estimate of their
+// penetration depth which is no smaller than it.
+func (c *cone) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(c)
+	}
+	if other, ok := g.(*point); ok {
+		return c.pointDistance(other.position), nil
+	}
+	other, ok := convexCoreOf(g)
+	if !ok {
+		return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
+	}
+	self, _ := convexCoreOf(c)
+	return convexDistance(self, other), nil
+}
+
+// EncompassedBy returns whether the cone is completely encompassed by the given geometry. This is exact for geometries bounded by
+// planes, and is otherwise checked at its apex and at points spaced around the rim of its base.
+func (c *cone) EncompassedBy(g Geometry) (bool, error) {
+	self, _ := convexCoreOf(c)
+	rim := transformPointsToPose(ringPoints(c.radius, -c.length/2, 2*math.Pi*c.radius/rimSamples), c.pose)
+	return convexEncompassedBy(c, self, append(rim, c.apex()), g)
+}
+
+// ToPoints converts a cone geometry into []r3.Vector. This method takes one argument which determines how many points per sqmm
+// should be on the cone's surface. If the argument is set to 0. we automatically substitute the value with defaultPointDensity.
+func (c *cone) ToPoints(resolution float64) []r3.Vector {
+	spacing := surfacePointSpacing(resolution)
+	vecList := diskPoints(c.radius, -c.length/2, spacing)
+	rings := math.Ceil(math.Hypot(c.radius, c.length) / spacing)
+	for ring := 1.; ring <= rings; ring++ {
+		vecList = append(vecList, ringPoints(c.radius*(1-ring/rings), -c.length/2+c.length*ring/rings, spacing)...)
+	}
+	return transformPointsToPose(vecList, c.pose)
+}
+
+// apex returns the tip of the cone.
+func (c *cone) apex() r3.Vector {
+	return c.center.Add(c.axis.Mul(c.length / 2))
+}
+
+// support returns the point of the cone farthest in a direction, which is either its apex or a point on the rim of its base.
+func (c *cone) support(direction r3.Vector) r3.Vector {
+	apex := c.apex()
+	rim := c.center.Sub(c.axis.Mul(c.length / 2)).Add(radialDirection(direction, c.axis).Mul(c.radius))
+	if rim.Dot(direction) > apex.Dot(direction) {
+		return rim
+	}
+	return apex
+}
+
+// containsPoint returns whether a point, in the frame the pose of the cone is in, is no more than a distance outside of the cone. A
+// negative distance requires the point to be at least that far inside of it.
+func (c *cone) containsPoint(pt r3.Vector, tolerance float64) bool {
+	outsideBase, outsideSlant := c.outsideSides(axialCoordinates(pt, c.center, c.axis))
+	return outsideBase <= tolerance && outsideSlant <= tolerance
+}
+
+// pointDistance returns the distance from a point to the cone, which is negative if the point is inside of it. It is found in the plane
+// through the axis and the point, in which the cone is a triangle.
+func (c *cone) pointDistance(pt r3.Vector) float64 {
+	along, across := axialCoordinates(pt, c.center, c.axis)
+	outsideBase, outsideSlant := c.outsideSides(along, across)
+	if outsideBase <= 0 && outsideSlant <= 0 {
+		return math.Max(outsideBase, outsideSlant)
+	}
+	flat := r3.Vector{X: across, Y: along}
+	apex, rim := r3.Vector{Y: c.length / 2}, r3.Vector{X: c.radius, Y: -c.length / 2}
+	return math.Min(DistToLineSegment(apex, rim, flat), DistToLineSegment(r3.Vector{X: -c.radius, Y: -c.length / 2}, rim, flat))
+}
+
+// outsideSides returns how far a point, given by how far along the axis of the cone and how far from it it is, lies outside of the plane
+// of the base of the cone and outside of the slanted side of it in the plane through the axis and the point.
+func (c *cone) outsideSides(along, across float64) (float64, float64) {
+	return -along - c.length/2, (c.radius*along + c.length*across - c.radius*c.length/2) / math.Hypot(c.radius, c.length)
+}
@@ -0,0 +1,38 @@
+package spatialmath
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func makeTestCone(o Orientation, pt r3.Vector, radius, length float64) Geometry {
+	c, _ := NewCone(NewPose(pt, o), radius, length, "")
+	return c
+}
+
+func TestNewCone(t *testing.T) {
+	offset := NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &OrientationVectorDegrees{OX: 1, Theta: 30})
+	c, err := NewCone(offset, 2, 10, "cone")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, c.Label(), test.ShouldEqual, "cone")
+	test.That(t, R3VectorAlmostEqual(c.(*cone).apex(), r3.Vector{X: 6, Y: 2, Z: 3}, 1e-8), test.ShouldBeTrue)
+	test.That(t, c.AlmostEqual(makeTestCylinder(offset.Orientation(), offset.Point(), 2, 10)), test.ShouldBeFalse)
+	test.That(t, c.AlmostEqual(makeTestCone(offset.Orientation(), offset.Point(), 2, 10)), test.ShouldBeTrue)
+
+	_, err = NewCone(offset, -2, 10, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewCone(offset, 2, 0, "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestConeToPoints(t *testing.T) {
+	c := makeTestCone(&OrientationVectorDegrees{OY: 1, Theta: 20}, r3.Vector{X: 5}, 3, 4).(*cone)
+	points := c.ToPoints(1)
+	test.That(t, len(points), test.ShouldBeGreaterThan, 50)
+	for _, pt := range points {
+		test.That(t, c.containsPoint(pt, 1e-6), test.ShouldBeTrue)
+		test.That(t, c.containsPoint(pt, -1e-6), test.ShouldBeFalse)
+	}
+}
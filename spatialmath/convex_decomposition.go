@@ -0,0 +1,188 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+)
+
+// DecomposeMesh decomposes a closed triangle mesh, such as that of a scanned object, into convex pieces which together cover it, so that
+// the object can be checked for collisions as quickly as convex geometries can. The mesh is given by its vertices, relative to the pose,
+// and the indices of the vertices of each of its triangles, wound counterclockwise as seen from outside of it. It is split by planes,
+// most concave piece first, until the surface of every piece lies within maxConcavity of the convex hull of the piece or there are
+// maxPieces pieces. The pieces are returned as the convex hulls of their parts of the mesh.
+func DecomposeMesh(pose Pose, vertices []r3.Vector, faces [][3]int, maxConcavity float64, maxPieces int, label string) ([]Geometry, error) {
+	if maxConcavity < 0 {
+		return nil, errors.Errorf("max concavity must not be negative, got %f", maxConcavity)
+	}
+	if maxPieces < 1 {
+		return nil, errors.Errorf("must allow at least one piece, got %d", maxPieces)
+	}
+	triangles := make([][3]r3.Vector, 0, len(faces))
+	for _, face := range faces {
+		var triangle [3]r3.Vector
+		for i, v := range face {
+			if v < 0 || v >= len(vertices) {
+				return nil, errors.Errorf("mesh face refers to vertex %d of %d", v, len(vertices))
+			}
+			triangle[i] = vertices[v]
+		}
+		triangles = append(triangles, triangle)
+	}
+	root, err := newMeshPiece(triangles)
+	if err != nil {
+		return nil, err
+	}
+
+	pieces := []*meshPiece{root}
+	for len(pieces) < maxPieces {
+		worst := -1
+		for i, piece := range pieces {
+			if !piece.unsplittable && piece.concavity > maxConcavity && (worst < 0 || piece.concavity > pieces[worst].concavity) {
+				worst = i
+			}
+		}
+		if worst < 0 {
+			break
+		}
+		children := pieces[worst].split()
+		if children == nil {
+			pieces[worst].unsplittable = true
+			continue
+		}
+		pieces[worst] = children[0]
+		pieces = append(pieces, children[1])
+	}
+
+	geometries := make([]Geometry, 0, len(pieces))
+	for _, piece := range pieces {
+		hull, err := NewConvexHull(pose, piece.points(), label)
+		if err != nil {
+			return nil, err
+		}
+		geometries = append(geometries, hull)
+	}
+	return geometries, nil
+}
+
+// meshPiece is a part of a triangle mesh, with how far its surface lies inside of its convex hull.
+type meshPiece struct {
+	triangles    [][3]r3.Vector
+	concavity    float64
+	deepest      r3.Vector // the point of the surface deepest inside the hull
+	unsplittable bool
+}
+
+func newMeshPiece(triangles [][3]r3.Vector) (*meshPiece, error) {
+	piece := &meshPiece{triangles: triangles}
+	vertices, faces, err := ConvexHull(piece.points())
+	if err != nil {
+		return nil, err
+	}
+	// the concavity is measured at the vertices and centers of the triangles
+	samples := piece.points()
+	for _, t := range triangles {
+		samples = append(samples, t[0].Add(t[1]).Add(t[2]).Mul(1./3))
+	}
+	for _, sample := range samples {
+		depth := math.Inf(1)
+		for _, f := range faces {
+			normal := PlaneNormal(vertices[f[0]], vertices[f[1]], vertices[f[2]])
+			depth = math.Min(depth, normal.Dot(vertices[f[0]].Sub(sample)))
+		}
+		if depth > piece.concavity {
+			piece.concavity, piece.deepest = depth, sample
+		}
+	}
+	return piece, nil
+}
+
+// points returns the vertices of the triangles of the piece.
+func (piece *meshPiece) points() []r3.Vector {
+	points := make([]r3.Vector, 0, 3*len(piece.triangles))
+	for _, t := range piece.triangles {
+		points = append(points, t[0], t[1], t[2])
+	}
+	return points
+}
+
+// split splits the piece in two by the plane, perpendicular to an axis and through either the middle of the piece or its deepest point,
+// which leaves the concavity of the more concave half smallest. It returns nil if no such plane splits the piece into two halves which
+// each have a convex hull.
+func (piece *meshPiece) split() []*meshPiece {
+	lo, hi := piece.triangles[0][0], piece.triangles[0][0]
+	for _, pt := range piece.points() {
+		lo = r3.Vector{X: math.Min(lo.X, pt.X), Y: math.Min(lo.Y, pt.Y), Z: math.Min(lo.Z, pt.Z)}
+		hi = r3.Vector{X: math.Max(hi.X, pt.X), Y: math.Max(hi.Y, pt.Y), Z: math.Max(hi.Z, pt.Z)}
+	}
+	mid := lo.Add(hi).Mul(0.5)
+
+	var best []*meshPiece
+	bestConcavity := math.Inf(1)
+	for _, normal := range []r3.Vector{{X: 1}, {Y: 1}, {Z: 1}} {
+		for _, through := range []r3.Vector{mid, piece.deepest} {
+			offset := normal.Dot(through)
+			if offset <= normal.Dot(lo) || offset >= normal.Dot(hi) {
+				continue
+			}
+			above, below := clipTriangles(piece.triangles, normal, offset)
+			if len(above) == 0 || len(below) == 0 {
+				continue
+			}
+			abovePiece, err := newMeshPiece(above)
+			if err != nil {
+				continue
+			}
+			belowPiece, err := newMeshPiece(below)
+			if err != nil {
+				continue
+			}
+			if concavity := math.Max(abovePiece.concavity, belowPiece.concavity); concavity < bestConcavity {
+				best, bestConcavity = []*meshPiece{abovePiece, belowPiece}, concavity
+			}
+		}
+	}
+	return best
+}
+
+// clipTriangles splits triangles by the plane of points whose dot product with the normal is the offset, into the parts of them above
+// and below it. A triangle lying in the plane bounds only the side its face points away from.
+func clipTriangles(triangles [][3]r3.Vector, normal r3.Vector, offset float64) (above, below [][3]r3.Vector) {
+	for _, t := range triangles {
+		if math.Abs(normal.Dot(t[0])-offset) < floatEpsilon &&
+			math.Abs(normal.Dot(t[1])-offset) < floatEpsilon &&
+			math.Abs(normal.Dot(t[2])-offset) < floatEpsilon {
+			if PlaneNormal(t[0], t[1], t[2]).Dot(normal) > 0 {
+				below = append(below, t)
+			} else {
+				above = append(above, t)
+			}
+			continue
+		}
+		above = append(above, clipPolygon(t[:], normal, offset)...)
+		below = append(below, clipPolygon(t[:], normal.Mul(-1), -offset)...)
+	}
+	return above, below
+}
+
+// clipPolygon returns the triangles making up the part of a convex polygon above a plane, by the Sutherland-Hodgman algorithm.
+// Reference: https://en.wikipedia.org/wiki/Sutherland%E2%80%93Hodgman_algorithm
+func clipPolygon(polygon []r3.Vector, normal r3.Vector, offset float64) [][3]r3.Vector {
+	var clipped []r3.Vector
+	for i, current := range polygon {
+		next := polygon[(i+1)%len(polygon)]
+		currentDist, nextDist := normal.Dot(current)-offset, normal.Dot(next)-offset
+		if currentDist >= 0 {
+			clipped = append(clipped, current)
+		}
+		if (currentDist > 0 && nextDist < 0) || (currentDist < 0 && nextDist > 0) {
+			clipped = append(clipped, current.Add(next.Sub(current).Mul(currentDist/(currentDist-nextDist))))
+		}
+	}
+	var triangles [][3]r3.Vector
+	for i := 2; i < len(clipped); i++ {
+		triangles = append(triangles, [3]r3.Vector{clipped[0], clipped[i-1], clipped[i]})
+	}
+	return triangles
+}
@@ -0,0 +1,63 @@
+package spatialmath
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestDecomposeMesh(t *testing.T) {
+	// an L shaped prism, which is concave where the arms of the L meet
+	outline := []r3.Vector{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 20}, {X: 0, Y: 20}}
+	vertices := []r3.Vector{}
+	for _, pt := range outline {
+		vertices = append(vertices, pt)
+	}
+	for _, pt := range outline {
+		vertices = append(vertices, pt.Add(r3.Vector{Z: 10}))
+	}
+	n := len(outline)
+	faces := [][3]int{}
+	for i := 1; i < n-1; i++ {
+		faces = append(faces, [3]int{0, i + 1, i}, [3]int{n, n + i, n + i + 1})
+	}
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		faces = append(faces, [3]int{i, j, n + j}, [3]int{i, n + j, n + i})
+	}
+
+	pose := NewPoseFromPoint(r3.Vector{X: 100})
+	pieces, err := DecomposeMesh(pose, vertices, faces, 1, 8, "l")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(pieces), test.ShouldBeBetweenOrEqual, 2, 8)
+	collidesWithAny := func(pieces []Geometry, pt r3.Vector) bool {
+		for _, piece := range pieces {
+			test.That(t, piece.Label(), test.ShouldEqual, "l")
+			collides, err := piece.CollidesWith(NewPoint(Compose(pose, NewPoseFromPoint(pt)).Point(), ""))
+			test.That(t, err, test.ShouldBeNil)
+			if collides {
+				return true
+			}
+		}
+		return false
+	}
+	for _, pt := range []r3.Vector{{X: 5, Y: 5, Z: 5}, {X: 15, Y: 5, Z: 5}, {X: 5, Y: 15, Z: 5}, {X: 19, Y: 9, Z: 1}} {
+		test.That(t, collidesWithAny(pieces, pt), test.ShouldBeTrue)
+	}
+	notch := r3.Vector{X: 15, Y: 15, Z: 5}
+	test.That(t, collidesWithAny(pieces, notch), test.ShouldBeFalse)
+
+	// a single piece is the convex hull of the whole mesh, which fills in the notch
+	pieces, err = DecomposeMesh(pose, vertices, faces, 1, 1, "l")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pieces, test.ShouldHaveLength, 1)
+	test.That(t, collidesWithAny(pieces, notch), test.ShouldBeTrue)
+
+	_, err = DecomposeMesh(pose, vertices, faces, -1, 8, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = DecomposeMesh(pose, vertices, faces, 1, 0, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = DecomposeMesh(pose, vertices, [][3]int{{0, 1, len(vertices)}}, 1, 8, "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
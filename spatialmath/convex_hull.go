@@ -0,0 +1,346 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+)
+
+// ConvexHull returns the smallest convex polyhedron containing a set of points, as the points which are its vertices and the triangles
+// which are its faces, given by the indices of their vertices in counterclockwise order seen from outside of it. The points must not all
+// lie in one plane. It is found with the quickhull algorithm.
+// Reference: https://en.wikipedia.org/wiki/Quickhull
+func ConvexHull(points []r3.Vector) ([]r3.Vector, [][3]int, error) {
+	hull, err := newQuickhull(points)
+	if err != nil {
+		return nil, nil, err
+	}
+	hull.build()
+	vertices, faces := hull.result()
+	return vertices, faces, nil
+}
+
+// hullFace is a face of a convex hull being built, with the points outside of it which have not yet been added to the hull.
+type hullFace struct {
+	vertices [3]int
+	normal   r3.Vector
+	offset   float64
+	outside  []int
+	removed  bool
+}
+
+func (f *hullFace) distance(pt r3.Vector) float64 {
+	return f.normal.Dot(pt) - f.offset
+}
+
+type quickhull struct {
+	points []r3.Vector
+	faces  []*hullFace
+	eps    float64
+}
+
+// newQuickhull starts the hull of a set of points from the tetrahedron of four of its extreme points.
+func newQuickhull(points []r3.Vector) (*quickhull, error) {
+	if len(points) < 4 {
+		return nil, errors.Errorf("need at least 4 points for a convex hull, got %d", len(points))
+	}
+	// distances below the rounding error of the coordinates of the points are treated as zero
+	scale := 0.
+	for _, pt := range points {
+		scale = math.Max(scale, math.Max(math.Abs(pt.X), math.Max(math.Abs(pt.Y), math.Abs(pt.Z))))
+	}
+	qh := &quickhull{points: points, eps: 1e-10 * math.Max(scale, 1)}
+
+	// the point farthest from the first, the point farthest from the line through them, and the point farthest from the plane through
+	// all three
+	a, b := 0, 0
+	for i, pt := range points {
+		if pt.Sub(points[a]).Norm() > points[b].Sub(points[a]).Norm() {
+			b = i
+		}
+	}
+	line := points[b].Sub(points[a]).Normalize()
+	c, bestDist := -1, qh.eps
+	for i, pt := range points {
+		if dist := pt.Sub(points[a]).Cross(line).Norm(); dist > bestDist {
+			c, bestDist = i, dist
+		}
+	}
+	if c < 0 {
+		return nil, errors.New("cannot find the convex hull of points which all lie on one line")
+	}
+	normal := PlaneNormal(points[a], points[b], points[c])
+	d, bestDist := -1, qh.eps
+	for i, pt := range points {
+		if dist := math.Abs(normal.Dot(pt.Sub(points[a]))); dist > bestDist {
+			d, bestDist = i, dist
+		}
+	}
+	if d < 0 {
+		return nil, errors.New("cannot find the convex hull of points which all lie in one plane")
+	}
+
+	// orient the faces of the tetrahedron outwards, away from its fourth vertex
+	if normal.Dot(points[d].Sub(points[a])) > 0 {
+		b, c = c, b
+	}
+	for _, vertices := range [][3]int{{a, b, c}, {a, d, b}, {b, d, c}, {c, d, a}} {
+		qh.faces = append(qh.faces, qh.newFace(vertices))
+	}
+	all := make([]int, 0, len(points))
+	for i := range points {
+		if i != a && i != b && i != c && i != d {
+			all = append(all, i)
+		}
+	}
+	qh.assignOutside(all, qh.faces)
+	return qh, nil
+}
+
+func (qh *quickhull) newFace(vertices [3]int) *hullFace {
+	p0, p1, p2 := qh.points[vertices[0]], qh.points[vertices[1]], qh.points[vertices[2]]
+	normal := p1.Sub(p0).Cross(p2.Sub(p0)).Normalize()
+	return &hullFace{vertices: vertices, normal: normal, offset: normal.Dot(p0)}
+}
+
+// assignOutside assigns each point to a face it is outside of, dropping those inside all of them.
+func (qh *quickhull) assignOutside(points []int, faces []*hullFace) {
+	for _, i := range points {
+		for _, f := range faces {
+			if f.distance(qh.points[i]) > qh.eps {
+				f.outside = append(f.outside, i)
+				break
+			}
+		}
+	}
+}
+
+// build adds the farthest point outside of each face to the hull until no point is outside of it.
+func (qh *quickhull) build() {
+	for i := 0; i < len(qh.faces); i++ {
+		face := qh.faces[i]
+		if face.removed || len(face.outside) == 0 {
+			continue
+		}
+		eye := face.outside[0]
+		for _, pt := range face.outside[1:] {
+			if face.distance(qh.points[pt]) > face.distance(qh.points[eye]) {
+				eye = pt
+			}
+		}
+
+		// remove the faces the point can see, and replace them with faces from it to the edges between them and the rest of the hull
+		var visible []*hullFace
+		edges := map[[2]int]bool{}
+		for _, f := range qh.faces {
+			if f.removed || f.distance(qh.points[eye]) <= qh.eps {
+				continue
+			}
+			f.removed = true
+			visible = append(visible, f)
+			for j := 0; j < 3; j++ {
+				edges[[2]int{f.vertices[j], f.vertices[(j+1)%3]}] = true
+			}
+		}
+		var newFaces []*hullFace
+		for _, f := range visible {
+			for j := 0; j < 3; j++ {
+				if !edges[[2]int{f.vertices[(j+1)%3], f.vertices[j]}] {
+					newFaces = append(newFaces, qh.newFace([3]int{f.vertices[j], f.vertices[(j+1)%3], eye}))
+				}
+			}
+		}
+		qh.faces = append(qh.faces, newFaces...)
+		for _, f := range visible {
+			outside := make([]int, 0, len(f.outside))
+			for _, pt := range f.outside {
+				if pt != eye {
+					outside = append(outside, pt)
+				}
+			}
+			qh.assignOutside(outside, newFaces)
+		}
+	}
+}
+
+// result returns the vertices and faces of the hull, with the vertices renumbered in the order they are first used by a face.
+func (qh *quickhull) result() ([]r3.Vector, [][3]int) {
+	index := map[int]int{}
+	var vertices []r3.Vector
+	var faces [][3]int
+	for _, f := range qh.faces {
+		if f.removed {
+			continue
+		}
+		var face [3]int
+		for j, v := range f.vertices {
+			if _, ok := index[v]; !ok {
+				index[v] = len(vertices)
+				vertices = append(vertices, qh.points[v])
+			}
+			face[j] = index[v]
+		}
+		faces = append(faces, face)
+	}
+	return vertices, faces
+}
+
+// convexHull is a collision geometry that represents a convex polyhedron, such as the convex hull of the points of a scanned object.
+type convexHull struct {
+	pose     Pose
+	vertices []r3.Vector // relative to the pose
+	faces    [][3]int
+	normals  []r3.Vector // outward normals of the faces, relative to the pose
+	label    string
+
+	// the vertices and normals in the frame the pose is in, generated when first needed
+	cachedVertices []r3.Vector
+	cachedNormals  []r3.Vector
+}
+
+// NewConvexHull instantiates a new convex polyhedron Geometry, the convex hull of the given points, which are relative to the pose.
+func NewConvexHull(pose Pose, points []r3.Vector, label string) (Geometry, error) {
+	vertices, faces, err := ConvexHull(points)
+	if err != nil {
+		return nil, err
+	}
+	normals := make([]r3.Vector, 0, len(faces))
+	for _, f := range faces {
+		normals = append(normals, PlaneNormal(vertices[f[0]], vertices[f[1]], vertices[f[2]]))
+	}
+	return &convexHull{pose: pose, vertices: vertices, faces: faces, normals: normals, label: label}, nil
+}
+
+// String returns a human readable string that represents the convex hull.
+func (ch *convexHull) String() string {
+	return fmt.Sprintf("Type: ConvexHull, Vertices: %d, Faces: %d", len(ch.vertices), len(ch.faces))
+}
+
+func (ch *convexHull) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(ch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// Label returns the label of this convex hull.
+func (ch *convexHull) Label() string {
+	if ch != nil {
+		return ch.label
+	}
+	return ""
+}
+
+// Pose returns the pose of the convex hull.
+func (ch *convexHull) Pose() Pose {
+	return ch.pose
+}
+
+// AlmostEqual compares the convex hull with another geometry and checks if they are equivalent.
+func (ch *convexHull) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*convexHull)
+	if !ok || len(ch.vertices) != len(other.vertices) || !PoseAlmostEqual(ch.pose, other.pose) {
+		return false
+	}
+	for i, v := range ch.vertices {
+		if !R3VectorAlmostEqual(v, other.vertices[i], 1e-8) {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform premultiplies the convex hull pose with a transform, allowing the convex hull to be moved in space.
+func (ch *convexHull) Transform(toPremultiply Pose) Geometry {
+	return &convexHull{
+		pose:     Compose(toPremultiply, ch.pose),
+		vertices: ch.vertices,
+		faces:    ch.faces,
+		normals:  ch.normals,
+		label:    ch.label,
+	}
+}
+
+// ToProtobuf converts the convex hull to a Geometry proto message of the box bounding its vertices, as there is no message for convex
+// hulls.
+func (ch *convexHull) ToProtobuf() *commonpb.Geometry {
+	lo, hi := ch.vertices[0], ch.vertices[0]
+	for _, v := range ch.vertices[1:] {
+		lo = r3.Vector{X: math.Min(lo.X, v.X), Y: math.Min(lo.Y, v.Y), Z: math.Min(lo.Z, v.Z)}
+		hi = r3.Vector{X: math.Max(hi.X, v.X), Y: math.Max(hi.Y, v.Y), Z: math.Max(hi.Z, v.Z)}
+	}
+	bounds := axisAlignedBox(lo.Add(hi).Mul(0.5), hi.Sub(lo)).Transform(ch.pose).ToProtobuf()
+	bounds.Label = ch.label
+	return bounds
+}
+
+// CollidesWith checks if the given convex hull collides with the given geometry and returns true if it does.
+func (ch *convexHull) CollidesWith(g Geometry) (bool, error) {
+	if other, ok := g.(*voxelGrid); ok {
+		return other.CollidesWith(ch)
+	}
+	distance, err := ch.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return distance <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance from the convex hull to the given geometry. If they are in collision, it is an estimate of their
+// penetration depth which is no smaller than it, except for spheres and capsules which only overlap the hull by part of their radius.
+func (ch *convexHull) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(ch)
+	}
+	other, ok := convexCoreOf(g)
+	if !ok {
+		return math.Inf(-1), newCollisionTypeUnsupportedError(ch, g)
+	}
+	self, _ := convexCoreOf(ch)
+	return convexDistance(self, other), nil
+}
+
+// EncompassedBy returns whether the convex hull is completely encompassed by the given geometry, which for convex geometries is
+// whether all of its vertices are.
+func (ch *convexHull) EncompassedBy(g Geometry) (bool, error) {
+	self, _ := convexCoreOf(ch)
+	return convexEncompassedBy(ch, self, ch.worldVertices(), g)
+}
+
+// ToPoints returns the vertices of the convex hull, regardless of the resolution asked for.
+func (ch *convexHull) ToPoints(resolution float64) []r3.Vector {
+	return append([]r3.Vector{}, ch.worldVertices()...)
+}
+
+// worldVertices returns the cached vertices of the convex hull in the frame its pose is in, generating them if needed.
+func (ch *convexHull) worldVertices() []r3.Vector {
+	if ch.cachedVertices == nil {
+		ch.cachedVertices = transformPointsToPose(ch.vertices, ch.pose)
+	}
+	return ch.cachedVertices
+}
+
+// worldNormals returns the cached normals of the faces of the convex hull in the frame its pose is in, generating them if needed.
+func (ch *convexHull) worldNormals() []r3.Vector {
+	if ch.cachedNormals == nil {
+		ch.cachedNormals = transformPointsToPose(ch.normals, NewPoseFromOrientation(ch.pose.Orientation()))
+	}
+	return ch.cachedNormals
+}
+
+// containsPoint returns whether a point, in the frame the pose of the convex hull is in, is no more than a distance outside of every
+// face of the hull.
+func (ch *convexHull) containsPoint(pt r3.Vector, tolerance float64) bool {
+	vertices, normals := ch.worldVertices(), ch.worldNormals()
+	for i, f := range ch.faces {
+		if normals[i].Dot(pt.Sub(vertices[f[0]])) > tolerance {
+			return false
+		}
+	}
+	return true
+}
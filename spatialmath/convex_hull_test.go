@@ -0,0 +1,125 @@
+package spatialmath
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestConvexHull(t *testing.T) {
+	randSeed := rand.New(rand.NewSource(1))
+	points := []r3.Vector{}
+	for _, corner := range boxVertices {
+		points = append(points, corner.Mul(10))
+	}
+	for i := 0; i < 200; i++ {
+		points = append(points, r3.Vector{X: randSeed.Float64()*20 - 10, Y: randSeed.Float64()*20 - 10, Z: randSeed.Float64()*20 - 10})
+	}
+	vertices, faces, err := ConvexHull(points)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, vertices, test.ShouldHaveLength, 8)
+	test.That(t, faces, test.ShouldHaveLength, 12)
+
+	// the hull of points in a ball contains them all, with its faces facing away from them
+	points = points[:0]
+	for i := 0; i < 500; i++ {
+		points = append(points, r3.Vector{X: randSeed.NormFloat64(), Y: randSeed.NormFloat64(), Z: randSeed.NormFloat64()}.Mul(50))
+	}
+	vertices, faces, err = ConvexHull(points)
+	test.That(t, err, test.ShouldBeNil)
+	// a closed surface of triangles has three edges for every two faces, which Euler's formula relates to its vertices
+	test.That(t, len(vertices)-len(faces)*3/2+len(faces), test.ShouldEqual, 2)
+	for _, f := range faces {
+		normal := PlaneNormal(vertices[f[0]], vertices[f[1]], vertices[f[2]])
+		for _, pt := range points {
+			test.That(t, normal.Dot(pt.Sub(vertices[f[0]])), test.ShouldBeLessThanOrEqualTo, 1e-8)
+		}
+	}
+
+	_, _, err = ConvexHull(points[:3])
+	test.That(t, err, test.ShouldNotBeNil)
+	_, _, err = ConvexHull([]r3.Vector{{}, {X: 1}, {Y: 1}, {X: 1, Y: 1}, {X: 0.5, Y: 0.2}})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestConvexHullGeometry(t *testing.T) {
+	pose := NewPose(r3.Vector{X: 10, Y: 20, Z: 30}, &OrientationVectorDegrees{OX: 1, OY: 2, OZ: 3, Theta: 40})
+	corners := []r3.Vector{}
+	for _, corner := range boxVertices {
+		corners = append(corners, r3.Vector{X: corner.X * 10, Y: corner.Y * 20, Z: corner.Z * 30})
+	}
+	hull, err := NewConvexHull(pose, corners, "hull")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, hull.Label(), test.ShouldEqual, "hull")
+	test.That(t, hull.ToPoints(0), test.ShouldHaveLength, 8)
+	moved := hull.Transform(NewPoseFromPoint(r3.Vector{X: 1}))
+	test.That(t, moved.AlmostEqual(hull), test.ShouldBeFalse)
+	test.That(t, moved.Transform(NewPoseFromPoint(r3.Vector{X: -1})).AlmostEqual(hull), test.ShouldBeTrue)
+
+	// the hull of the corners of a box is as far from other geometries as the box is
+	equivalent, err := NewBox(pose, r3.Vector{X: 20, Y: 40, Z: 60}, "")
+	test.That(t, err, test.ShouldBeNil)
+	randSeed := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		center := NewPose(
+			r3.Vector{X: randSeed.Float64()*200 - 90, Y: randSeed.Float64()*200 - 80, Z: randSeed.Float64()*200 - 70},
+			&OrientationVector{OX: randSeed.NormFloat64(), OY: randSeed.NormFloat64(), OZ: randSeed.NormFloat64(), Theta: randSeed.Float64()},
+		)
+		others := []Geometry{NewPoint(center.Point(), "")}
+		for _, other := range []func() (Geometry, error){
+			func() (Geometry, error) { return NewSphere(center, 15, "") },
+			func() (Geometry, error) { return NewCapsule(center, 5, 40, "") },
+			func() (Geometry, error) { return NewBox(center, r3.Vector{X: 30, Y: 10, Z: 10}, "") },
+		} {
+			geometry, err := other()
+			test.That(t, err, test.ShouldBeNil)
+			others = append(others, geometry)
+		}
+		for _, other := range others {
+			expected, err := equivalent.DistanceFrom(other)
+			test.That(t, err, test.ShouldBeNil)
+			distance, err := hull.DistanceFrom(other)
+			test.That(t, err, test.ShouldBeNil)
+			otherDistance, err := other.DistanceFrom(hull)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, otherDistance, test.ShouldEqual, distance)
+			collides, err := other.CollidesWith(hull)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldEqual, distance <= CollisionBuffer)
+			switch {
+			case expected > 0 && distance > 0:
+				switch other.(type) {
+				case *box:
+					// distances between boxes are only estimated, by lower bounds
+					test.That(t, distance, test.ShouldBeGreaterThanOrEqualTo, expected-1e-6)
+				case *capsule:
+					// distances between capsules and boxes are estimated from the meshes of the boxes, by upper bounds
+					test.That(t, distance, test.ShouldBeLessThanOrEqualTo, expected+1e-6)
+				default:
+					test.That(t, distance, test.ShouldAlmostEqual, expected, 1e-6)
+				}
+			case expected < 0:
+				test.That(t, distance, test.ShouldBeLessThanOrEqualTo, CollisionBuffer)
+			}
+		}
+	}
+
+	inside, err := NewSphere(Compose(pose, NewPoseFromPoint(r3.Vector{X: 2})), 5, "")
+	test.That(t, err, test.ShouldBeNil)
+	encompassed, err := inside.EncompassedBy(hull)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encompassed, test.ShouldBeTrue)
+	encompassed, err = inside.Transform(NewPoseFromPoint(r3.Vector{Z: 100})).EncompassedBy(hull)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encompassed, test.ShouldBeFalse)
+	around, err := NewSphere(pose, 40, "")
+	test.That(t, err, test.ShouldBeNil)
+	encompassed, err = hull.EncompassedBy(around)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encompassed, test.ShouldBeTrue)
+	encompassed, err = hull.EncompassedBy(inside)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encompassed, test.ShouldBeFalse)
+}
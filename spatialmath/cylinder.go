@@ -0,0 +1,235 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+
+	"go.viam.com/rdk/utils"
+)
+
+// cylinder is a collision geometry that represents a solid cylinder, such as a post or a roller. Its axis is the z axis of its pose,
+// and it extends half of its length to either side of the pose.
+type cylinder struct {
+	pose   Pose
+	radius float64
+	length float64
+	label  string
+
+	// These values are generated at geometry creation time and should not be altered by hand
+	center r3.Vector
+	axis   r3.Vector // unit vector along the z axis of the pose
+}
+
+// NewCylinder instantiates a new cylinder Geometry.
+func NewCylinder(offset Pose, radius, length float64, label string) (Geometry, error) {
+	if radius <= 0 || length <= 0 {
+		return nil, newBadGeometryDimensionsError(&cylinder{})
+	}
+	return newCylinder(offset, radius, length, label), nil
+}
+
+func newCylinder(offset Pose, radius, length float64, label string) *cylinder {
+	center, axis := poseAxis(offset)
+	return &cylinder{pose: offset, radius: radius, length: length, label: label, center: center, axis: axis}
+}
+
+func (c *cylinder) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// String returns a human readable string that represents the cylinder.
+func (c *cylinder) String() string {
+	return fmt.Sprintf("Type: Cylinder, Radius: %.0f, Length: %.0f", c.radius, c.length)
+}
+
+// Label returns the label of this cylinder.
+func (c *cylinder) Label() string {
+	if c != nil {
+		return c.label
+	}
+	return ""
+}
+
+// Pose returns the pose of the cylinder.
+func (c *cylinder) Pose() Pose {
+	return c.pose
+}
+
+// AlmostEqual compares the cylinder with another geometry and checks if they are equivalent.
+func (c *cylinder) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*cylinder)
+	if !ok {
+		return false
+	}
+	return PoseAlmostEqual(c.pose, other.pose) &&
+		utils.Float64AlmostEqual(c.radius, other.radius, 1e-8) &&
+		utils.Float64AlmostEqual(c.length, other.length, 1e-8)
+}
+
+// Transform premultiplies the cylinder pose with a transform, allowing the cylinder to be moved in space.
+func (c *cylinder) Transform(toPremultiply Pose) Geometry {
+	return newCylinder(Compose(toPremultiply, c.pose), c.radius, c.length, c.label)
+}
+
+// ToProtobuf converts the cylinder to a Geometry proto message of the capsule bounding it, as there is no message for cylinders. The
+// message also records the cylinder, so that NewGeometryFromProto decodes it as one.
+func (c *cylinder) ToProtobuf() *commonpb.Geometry {
+	return roundedBoundingCapsule(boundedCylinder, c.pose, c.radius, c.length, c.label)
+}
+
+// CollidesWith checks if the given cylinder collides with the given geometry and returns true if it does.
+func (c *cylinder) CollidesWith(g Geometry) (bool, error) {
+	if other, ok := g.(*voxelGrid); ok {
+		return other.CollidesWith(c)
+	}
+	distance, err := c.DistanceFrom(g)
+	if err != nil {
+		return true, err
+	}
+	return distance <= CollisionBuffer, nil
+}
+
+// DistanceFrom returns the distance from the cylinder to the given geometry. If they are in collision, it is an estimate of their
+// penetration depth which is no smaller than it.
+func (c *cylinder) DistanceFrom(g Geometry) (float64, error) {
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(c)
+	}
+	if other, ok := g.(*point); ok {
+		return c.pointDistance(other.position), nil
+	}
+	other, ok := convexCoreOf(g)
+	if !ok {
+		return math.Inf(-1), newCollisionTypeUnsupportedError(c, g)
+	}
+	self, _ := convexCoreOf(c)
+	return convexDistance(self, other), nil
+}
+
+// EncompassedBy returns whether the cylinder is completely encompassed by the given geometry. This is exact for geometries bounded by
+// planes, and is otherwise checked at points spaced around the rims of the cylinder.
+func (c *cylinder) EncompassedBy(g Geometry) (bool, error) {
+	self, _ := convexCoreOf(c)
+	rims := append(c.rimPoints(-1), c.rimPoints(1)...)
+	return convexEncompassedBy(c, self, rims, g)
+}
+
+// ToPoints converts a cylinder geometry into []r3.Vector. This method takes one argument which determines how many points per sqmm
+// should be on the cylinder's surface. If the argument is set to 0. we automatically substitute the value with defaultPointDensity.
+func (c *cylinder) ToPoints(resolution float64) []r3.Vector {
+	spacing := surfacePointSpacing(resolution)
+	vecList := append(diskPoints(c.radius, -c.length/2, spacing), diskPoints(c.radius, c.length/2, spacing)...)
+	rings := math.Ceil(c.length / spacing)
+	for ring := 1.; ring < rings; ring++ {
+		vecList = append(vecList, ringPoints(c.radius, -c.length/2+c.length*ring/rings, spacing)...)
+	}
+	return transformPointsToPose(vecList, c.pose)
+}
+
+// support returns the point of the cylinder farthest in a direction.
+func (c *cylinder) support(direction r3.Vector) r3.Vector {
+	end := c.center.Add(c.axis.Mul(c.length / 2))
+	if direction.Dot(c.axis) < 0 {
+		end = c.center.Sub(c.axis.Mul(c.length / 2))
+	}
+	return end.Add(radialDirection(direction, c.axis).Mul(c.radius))
+}
+
+// containsPoint returns whether a point, in the frame the pose of the cylinder is in, is no more than a distance outside of the
+// cylinder. A negative distance requires the point to be at least that far inside of it.
+func (c *cylinder) containsPoint(pt r3.Vector, tolerance float64) bool {
+	along, across := axialCoordinates(pt, c.center, c.axis)
+	return math.Abs(along)-c.length/2 <= tolerance && across-c.radius <= tolerance
+}
+
+// pointDistance returns the distance from a point to the cylinder, which is negative if the point is inside of it.
+func (c *cylinder) pointDistance(pt r3.Vector) float64 {
+	along, across := axialCoordinates(pt, c.center, c.axis)
+	outsideEnd, outsideSide := math.Abs(along)-c.length/2, across-c.radius
+	if outsideEnd <= 0 && outsideSide <= 0 {
+		return math.Max(outsideEnd, outsideSide)
+	}
+	return math.Hypot(math.Max(outsideEnd, 0), math.Max(outsideSide, 0))
+}
+
+// rimPoints returns points spaced around the rim of the end of the cylinder on the given side of its pose.
+func (c *cylinder) rimPoints(side float64) []r3.Vector {
+	return transformPointsToPose(ringPoints(c.radius, side*c.length/2, 2*math.Pi*c.radius/rimSamples), c.pose)
+}
+
+// rimSamples is the number of points spaced around the rims of cylinders and cones at which they are checked to be encompassed by
+// geometries with curved surfaces.
+const rimSamples = 64
+
+// poseAxis returns the point of a pose and the unit vector along its z axis.
+func poseAxis(pose Pose) (r3.Vector, r3.Vector) {
+	center := pose.Point()
+	return center, Compose(pose, NewPoseFromPoint(r3.Vector{Z: 1})).Point().Sub(center)
+}
+
+// axialCoordinates returns how far along an axis through a center a point is, and how far from the axis it is.
+func axialCoordinates(pt, center, axis r3.Vector) (float64, float64) {
+	offset := pt.Sub(center)
+	along := offset.Dot(axis)
+	return along, offset.Sub(axis.Mul(along)).Norm()
+}
+
+// radialDirection returns the unit vector of the part of a direction perpendicular to an axis, or the zero vector if they are parallel.
+func radialDirection(direction, axis r3.Vector) r3.Vector {
+	return direction.Sub(axis.Mul(direction.Dot(axis))).Normalize()
+}
+
+// roundedBoundingCapsule returns a Geometry proto message of the capsule with the given radius bounding a geometry of that radius about
+// the z axis of its pose and of the given length along it, recording the kind and length of the geometry it bounds.
+func roundedBoundingCapsule(kind boundedGeometryKind, pose Pose, radius, length float64, label string) *commonpb.Geometry {
+	geometry := &commonpb.Geometry{
+		Center: PoseToProtobuf(pose),
+		GeometryType: &commonpb.Geometry_Capsule{
+			Capsule: &commonpb.Capsule{
+				RadiusMm: radius,
+				LengthMm: length + 2*radius,
+			},
+		},
+		Label: label,
+	}
+	recordBoundedGeometry(geometry, boundedGeometryRecord{kind: kind, length: length})
+	return geometry
+}
+
+// surfacePointSpacing returns the spacing of points on a surface with the given number of points per sqmm, or defaultPointDensity if it
+// is not positive.
+func surfacePointSpacing(resolution float64) float64 {
+	if resolution <= 0 {
+		resolution = defaultPointDensity
+	}
+	return 1 / math.Sqrt(resolution)
+}
+
+// ringPoints returns points spaced around a circle of the given radius about the z axis, at the given height.
+func ringPoints(radius, z, spacing float64) []r3.Vector {
+	count := math.Max(1, math.Ceil(2*math.Pi*radius/spacing))
+	vecList := make([]r3.Vector, 0, int(count))
+	for i := 0.; i < count; i++ {
+		theta := 2 * math.Pi * i / count
+		vecList = append(vecList, r3.Vector{X: radius * math.Cos(theta), Y: radius * math.Sin(theta), Z: z})
+	}
+	return vecList
+}
+
+// diskPoints returns points spread over a disk of the given radius about the z axis, at the given height.
+func diskPoints(radius, z, spacing float64) []r3.Vector {
+	var vecList []r3.Vector
+	rings := math.Ceil(radius / spacing)
+	for ring := 0.; ring <= rings; ring++ {
+		vecList = append(vecList, ringPoints(radius*ring/rings, z, spacing)...)
+	}
+	return vecList
+}
@@ -0,0 +1,60 @@
+package spatialmath
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func makeTestCylinder(o Orientation, pt r3.Vector, radius, length float64) Geometry {
+	c, _ := NewCylinder(NewPose(pt, o), radius, length, "")
+	return c
+}
+
+func TestNewCylinder(t *testing.T) {
+	offset := NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &OrientationVectorDegrees{OX: 1, Theta: 30})
+	c, err := NewCylinder(offset, 2, 10, "cylinder")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, c.Label(), test.ShouldEqual, "cylinder")
+	test.That(t, R3VectorAlmostEqual(c.(*cylinder).axis, r3.Vector{X: 1}, 1e-8), test.ShouldBeTrue)
+	test.That(t, c.AlmostEqual(c.Transform(NewPoseFromPoint(r3.Vector{Z: 1}))), test.ShouldBeFalse)
+	test.That(t, c.AlmostEqual(makeTestCylinder(offset.Orientation(), offset.Point(), 2, 10)), test.ShouldBeTrue)
+
+	_, err = NewCylinder(offset, 0, 10, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewCylinder(offset, 2, -1, "")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestCylinderToPoints(t *testing.T) {
+	c := makeTestCylinder(&OrientationVectorDegrees{OY: 1, Theta: 20}, r3.Vector{X: 5}, 2, 10).(*cylinder)
+	points := c.ToPoints(1)
+	test.That(t, len(points), test.ShouldBeGreaterThan, 100)
+	for _, pt := range points {
+		test.That(t, c.containsPoint(pt, 1e-6), test.ShouldBeTrue)
+		test.That(t, c.containsPoint(pt, -1e-6), test.ShouldBeFalse)
+	}
+}
+
+func TestCylinderAndConeDistances(t *testing.T) {
+	// distances from spheres, which are found from the convex cores of the geometries, agree with those from their centers
+	randSeed := rand.New(rand.NewSource(1))
+	cyl := makeTestCylinder(&OrientationVectorDegrees{OX: 1, OY: 1, Theta: 10}, r3.Vector{X: 3, Y: 4, Z: 5}, 10, 30).(*cylinder)
+	cone := makeTestCone(&OrientationVectorDegrees{OX: 1, OY: 1, Theta: 10}, r3.Vector{X: 3, Y: 4, Z: 5}, 10, 30).(*cone)
+	for i := 0; i < 100; i++ {
+		center := r3.Vector{X: randSeed.Float64()*100 - 50, Y: randSeed.Float64()*100 - 50, Z: randSeed.Float64()*100 - 50}
+		s := makeTestSphere(center, 1, "")
+		if expected := cyl.pointDistance(center) - 1; expected > 0 {
+			distance, err := cyl.DistanceFrom(s)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, distance, test.ShouldAlmostEqual, expected, 1e-6)
+		}
+		if expected := cone.pointDistance(center) - 1; expected > 0 {
+			distance, err := s.DistanceFrom(cone)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, distance, test.ShouldAlmostEqual, expected, 1e-6)
+		}
+	}
+}
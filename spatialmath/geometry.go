@@ -3,6 +3,7 @@ package spatialmath
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 
 	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
@@ -36,6 +37,8 @@ const (
 	SphereType      = GeometryType("sphere")
 	CapsuleType     = GeometryType("capsule")
 	PointType       = GeometryType("point")
+	CylinderType    = GeometryType("cylinder")
+	ConeType        = GeometryType("cone")
 	CollisionBuffer = 1e-8 // objects must be separated by this many mm to not be in collision
 
 	// Point density corresponding to how many points per square mm.
@@ -51,10 +54,10 @@ type GeometryConfig struct {
 	Y float64 `json:"y"`
 	Z float64 `json:"z"`
 
-	// parameter used for defining a sphere's radius'
+	// parameter used for defining a sphere's, capsule's, cylinder's or cone's radius
 	R float64 `json:"r"`
 
-	// parameter used for defining a capsule's length
+	// parameter used for defining a capsule's, cylinder's or cone's length
 	L float64 `json:"l"`
 
 	// define an offset to position the geometry
@@ -86,6 +89,16 @@ func NewGeometryConfig(gc Geometry) (*GeometryConfig, error) {
 	case *point:
 		config.Type = PointType
 		config.Label = gc.(*point).label
+	case *cylinder:
+		config.Type = CylinderType
+		config.R = gc.(*cylinder).radius
+		config.L = gc.(*cylinder).length
+		config.Label = gc.(*cylinder).label
+	case *cone:
+		config.Type = ConeType
+		config.R = gc.(*cone).radius
+		config.L = gc.(*cone).length
+		config.Label = gc.(*cone).label
 	default:
 		return nil, fmt.Errorf("%w %s", ErrGeometryTypeUnsupported, fmt.Sprintf("%T", gcType))
 	}
@@ -119,6 +132,10 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 		return NewCapsule(offset, config.R, config.L, config.Label)
 	case PointType:
 		return NewPoint(offset.Point(), config.Label), nil
+	case CylinderType:
+		return NewCylinder(offset, config.R, config.L, config.Label)
+	case ConeType:
+		return NewCone(offset, config.R, config.L, config.Label)
 	case UnknownType:
 		// no type specified, iterate through supported types and try to infer intent
 		boxDims := r3.Vector{X: config.X, Y: config.Y, Z: config.Z}
@@ -140,6 +157,9 @@ func (config *GeometryConfig) ParseConfig() (Geometry, error) {
 
 // NewGeometryFromProto instantiates a new Geometry from a protobuf Geometry message.
 func NewGeometryFromProto(geometry *commonpb.Geometry) (Geometry, error) {
+	if bounded, ok, err := boundedGeometryFromProto(geometry); ok || err != nil {
+		return bounded, err
+	}
 	pose := NewPoseFromProtobuf(geometry.Center)
 	if box := geometry.GetBox().GetDimsMm(); box != nil {
 		return NewBox(pose, r3.Vector{X: box.X, Y: box.Y, Z: box.Z}, geometry.Label)
@@ -164,3 +184,29 @@ func (config *GeometryConfig) ToProtobuf() (*commonpb.Geometry, error) {
 	}
 	return creator.ToProtobuf(), nil
 }
+
+// BoundingRadius returns the distance from the pose of a geometry to the furthest point of it, which is how far any point of the
+// geometry can move when it rotates about its pose by one radian.
+func BoundingRadius(g Geometry) float64 {
+	switch g := g.(type) {
+	case *box:
+		return g.boundingSphereR
+	case *sphere:
+		return g.radius
+	case *capsule:
+		return g.length / 2
+	case *point:
+		return 0
+	case *cylinder:
+		return math.Hypot(g.radius, g.length/2)
+	case *cone:
+		return math.Hypot(g.radius, g.length/2)
+	default:
+		center := g.Pose().Point()
+		radius := 0.
+		for _, pt := range g.ToPoints(defaultPointDensity) {
+			radius = math.Max(radius, pt.Sub(center).Norm())
+		}
+		return radius
+	}
+}
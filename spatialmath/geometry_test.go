@@ -9,6 +9,7 @@ import (
 	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/test"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestGeometrySerialization(t *testing.T) {
@@ -39,6 +40,10 @@ func TestGeometrySerialization(t *testing.T) {
 		{"bad type", GeometryConfig{Type: "bad"}, false},
 		{"c", GeometryConfig{Type: "capsule", L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "c"}, true},
 		{"infer c", GeometryConfig{L: 4, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "infer c"}, true},
+		{"cyl", GeometryConfig{Type: "cylinder", L: 1, R: 2, TranslationOffset: translation, OrientationOffset: orientation, Label: "cyl"}, true},
+		{"cylinder bad dims", GeometryConfig{Type: "cylinder", L: 0, R: 1}, false},
+		{"cone", GeometryConfig{Type: "cone", L: 3, R: 1, TranslationOffset: translation, OrientationOffset: orientation, Label: "cone"}, true},
+		{"cone bad dims", GeometryConfig{Type: "cone", L: 3, R: -1}, false},
 	}
 
 	pose := NewPoseFromPoint(r3.Vector{X: 1, Y: 1, Z: 1})
@@ -82,8 +87,37 @@ func TestGeometryToFromProtobuf(t *testing.T) {
 		})
 	}
 
+	// cylinders and cones have no messages, and are sent as the capsules bounding them which record what they bound
+	offset := NewPose(r3.Vector{3, 4, 5}, &OrientationVectorDegrees{OX: 1, Theta: 30})
+	cylinder, err := NewCylinder(offset, 2, 10, "cylinder")
+	test.That(t, err, test.ShouldBeNil)
+	cone, err := NewCone(offset, 2, 10, "cone")
+	test.That(t, err, test.ShouldBeNil)
+	for _, geometry := range []Geometry{cylinder, cone} {
+		data, err := proto.Marshal(geometry.ToProtobuf())
+		test.That(t, err, test.ShouldBeNil)
+		var msg commonpb.Geometry
+		test.That(t, proto.Unmarshal(data, &msg), test.ShouldBeNil)
+		decoded, err := NewGeometryFromProto(&msg)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, fmt.Sprintf("%T", decoded), test.ShouldEqual, fmt.Sprintf("%T", geometry))
+		test.That(t, decoded.AlmostEqual(geometry), test.ShouldBeTrue)
+		test.That(t, decoded.Label(), test.ShouldEqual, geometry.Label())
+
+		// a decoder that does not know the record reads the bounding capsule
+		msg.ProtoReflect().SetUnknown(nil)
+		bounds, err := NewGeometryFromProto(&msg)
+		test.That(t, err, test.ShouldBeNil)
+		_, ok := bounds.(*capsule)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, bounds.Label(), test.ShouldEqual, geometry.Label())
+		encompassed, err := geometry.EncompassedBy(bounds)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, encompassed, test.ShouldBeTrue)
+	}
+
 	// test that bad message does not generate error
-	_, err := NewGeometryFromProto(&commonpb.Geometry{Center: PoseToProtobuf(NewZeroPose())})
+	_, err = NewGeometryFromProto(&commonpb.Geometry{Center: PoseToProtobuf(NewZeroPose())})
 	test.That(t, err.Error(), test.ShouldContainSubstring, ErrGeometryTypeUnsupported.Error())
 }
 
@@ -800,3 +834,94 @@ func TestCapsuleVsPointEncompassed(t *testing.T) {
 	}
 	testGeometryEncompassed(t, cases)
 }
+
+func TestBoundingRadius(t *testing.T) {
+	offset := NewPose(r3.Vector{10, -20, 30}, &OrientationVectorDegrees{OX: 1, Theta: 45})
+	b, err := NewBox(offset, r3.Vector{2, 4, 4}, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, BoundingRadius(b), test.ShouldAlmostEqual, 3)
+	s, err := NewSphere(offset, 5, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, BoundingRadius(s), test.ShouldAlmostEqual, 5)
+	c, err := NewCapsule(offset, 1, 8, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, BoundingRadius(c), test.ShouldAlmostEqual, 4)
+	test.That(t, BoundingRadius(NewPoint(offset.Point(), "")), test.ShouldEqual, 0)
+	cyl, err := NewCylinder(offset, 3, 8, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, BoundingRadius(cyl), test.ShouldAlmostEqual, 5)
+	cone, err := NewCone(offset, 3, 8, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, BoundingRadius(cone), test.ShouldAlmostEqual, 5)
+}
+
+func TestCylinderCollision(t *testing.T) {
+	cyl := makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 2, 4)
+	cases := []geometryComparisonTestCase{
+		{"separated point beside", [2]Geometry{cyl, NewPoint(r3.Vector{5, 0, 0}, "")}, 3},
+		{"separated point above", [2]Geometry{cyl, NewPoint(r3.Vector{0, 0, 3}, "")}, 1},
+		{"separated point by rim", [2]Geometry{cyl, NewPoint(r3.Vector{3, 0, 3}, "")}, math.Sqrt2},
+		{"inside point near end", [2]Geometry{cyl, NewPoint(r3.Vector{0, 0, 1.5}, "")}, -0.5},
+		{"inside point near side", [2]Geometry{cyl, NewPoint(r3.Vector{1, 0, 0}, "")}, -1},
+		{"separated sphere", [2]Geometry{cyl, makeTestSphere(r3.Vector{5, 0, 0}, 1, "")}, 2},
+		{"colliding sphere", [2]Geometry{cyl, makeTestSphere(r3.Vector{2.5, 0, 0}, 1, "")}, -0.5},
+		{"separated capsule", [2]Geometry{cyl, makeTestCapsule(&OrientationVector{OX: 1}, r3.Vector{0, 0, 5}, 1, 6)}, 2},
+		{"separated box face", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{5, 0, 0}, r3.Vector{2, 2, 2}, "")}, 2},
+		{
+			"separated box edge",
+			[2]Geometry{cyl, makeTestBox(&OrientationVectorDegrees{OZ: 1, Theta: 45}, r3.Vector{5, 0, 0}, r3.Vector{2, 2, 2}, "")},
+			3 - math.Sqrt2,
+		},
+		{"separated parallel cylinder", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{5, 0, 0}, 2, 4)}, 1},
+		{"separated crossed cylinder", [2]Geometry{cyl, makeTestCylinder(&OrientationVector{OX: 1}, r3.Vector{0, 0, 5}, 1, 2)}, 2},
+		{"separated cone", [2]Geometry{cyl, makeTestCone(&OrientationVector{OZ: -1}, r3.Vector{0, 0, 6}, 2, 4)}, 2},
+	}
+	testGeometryCollision(t, cases)
+}
+
+func TestConeCollision(t *testing.T) {
+	cone := makeTestCone(NewZeroOrientation(), r3.Vector{}, 3, 4)
+	cases := []geometryComparisonTestCase{
+		{"separated point above apex", [2]Geometry{cone, NewPoint(r3.Vector{0, 0, 3}, "")}, 1},
+		{"separated point below base", [2]Geometry{cone, NewPoint(r3.Vector{0, 0, -3}, "")}, 1},
+		{"separated point beside", [2]Geometry{cone, NewPoint(r3.Vector{3.1, 0, 1.2}, "")}, 2},
+		{"separated point by rim", [2]Geometry{cone, NewPoint(r3.Vector{0, 4, -3}, "")}, math.Sqrt2},
+		{"inside point", [2]Geometry{cone, NewPoint(r3.Vector{0, 0, 1.5}, "")}, -0.3},
+		{"separated sphere", [2]Geometry{cone, makeTestSphere(r3.Vector{0, 3.1, 1.2}, 0.5, "")}, 1.5},
+		{"colliding sphere", [2]Geometry{cone, makeTestSphere(r3.Vector{0, 3.1, 1.2}, 2.5, "")}, -0.5},
+		{"separated box", [2]Geometry{cone, makeTestBox(NewZeroOrientation(), r3.Vector{0, 0, 4}, r3.Vector{2, 2, 2}, "")}, 1},
+		{"separated cone", [2]Geometry{cone, makeTestCone(&OrientationVector{OZ: -1}, r3.Vector{0, 0, 6}, 3, 4)}, 2},
+	}
+	testGeometryCollision(t, cases)
+}
+
+func TestCylinderAndConeEncompassed(t *testing.T) {
+	cyl := makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 2, 4)
+	cone := makeTestCone(NewZeroOrientation(), r3.Vector{}, 3, 4)
+	cases := []geometryComparisonTestCase{
+		{"cylinder in box", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{4, 4, 4}, "")}, 0},
+		{
+			"cylinder in rotated box",
+			[2]Geometry{cyl, makeTestBox(&OrientationVectorDegrees{OZ: 1, Theta: 45}, r3.Vector{}, r3.Vector{4, 4, 4}, "")},
+			0,
+		},
+		{"cylinder not in box", [2]Geometry{cyl, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{4, 4, 3.9}, "")}, 1},
+		{"cylinder in sphere", [2]Geometry{cyl, makeTestSphere(r3.Vector{}, 2.9, "")}, 0},
+		{"cylinder not in sphere", [2]Geometry{cyl, makeTestSphere(r3.Vector{}, 2.8, "")}, 1},
+		{"cylinder in cylinder", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 2.5, 4)}, 0},
+		{"cylinder not in cylinder", [2]Geometry{cyl, makeTestCylinder(NewZeroOrientation(), r3.Vector{}, 2.5, 3)}, 1},
+		{"cylinder not in point", [2]Geometry{cyl, NewPoint(r3.Vector{}, "")}, 1},
+		{"cone in cylinder", [2]Geometry{makeTestCone(NewZeroOrientation(), r3.Vector{}, 2, 4), cyl}, 0},
+		{"cone not in cylinder", [2]Geometry{cone, cyl}, 1},
+		{"box in cylinder", [2]Geometry{makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{2, 2, 4}, ""), cyl}, 0},
+		{"box not in cylinder", [2]Geometry{makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{3, 3, 4}, ""), cyl}, 1},
+		{"capsule in cylinder", [2]Geometry{makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 1, 4), cyl}, 0},
+		{"capsule not in cylinder", [2]Geometry{makeTestCapsule(NewZeroOrientation(), r3.Vector{}, 1, 4.2), cyl}, 1},
+		{"point in cylinder", [2]Geometry{NewPoint(r3.Vector{1, 1, 1}, ""), cyl}, 0},
+		{"sphere in cone", [2]Geometry{makeTestSphere(r3.Vector{0, 0, -1}, 0.9, ""), cone}, 0},
+		{"sphere not in cone", [2]Geometry{makeTestSphere(r3.Vector{0, 0, -1}, 1.1, ""), cone}, 1},
+		{"cone in box", [2]Geometry{cone, makeTestBox(NewZeroOrientation(), r3.Vector{}, r3.Vector{6, 6, 4}, "")}, 0},
+		{"cone not in sphere", [2]Geometry{cone, makeTestSphere(r3.Vector{}, 3.5, "")}, 1},
+	}
+	testGeometryEncompassed(t, cases)
+}
@@ -0,0 +1,258 @@
+package spatialmath
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+)
+
+// gjkMaxIterations bounds the iterations of the GJK algorithm, which converges in far fewer for the geometries it is used with.
+const gjkMaxIterations = 64
+
+// convexCore is a convex geometry described by its support function, the point of it farthest in a direction, together with a margin
+// around it. Spheres and capsules are a point and a segment with a margin of their radius, which keeps the distances between them exact.
+type convexCore struct {
+	support func(direction r3.Vector) r3.Vector
+	margin  float64
+	// axes are the directions along which the geometry is most likely to separate from another, the normals of its faces
+	axes []r3.Vector
+}
+
+// convexCoreOf returns the convex core of a geometry, and whether it has one.
+func convexCoreOf(g Geometry) (*convexCore, bool) {
+	switch g := g.(type) {
+	case *box:
+		vertices := g.vertices()
+		rm := g.rotationMatrix()
+		return &convexCore{
+			support: func(direction r3.Vector) r3.Vector { return farthestVertex(vertices, direction) },
+			axes:    []r3.Vector{rm.Row(0), rm.Row(1), rm.Row(2)},
+		}, true
+	case *sphere:
+		center := g.pose.Point()
+		return &convexCore{support: func(r3.Vector) r3.Vector { return center }, margin: g.radius}, true
+	case *capsule:
+		return &convexCore{
+			support: func(direction r3.Vector) r3.Vector {
+				if direction.Dot(g.segB.Sub(g.segA)) > 0 {
+					return g.segB
+				}
+				return g.segA
+			},
+			margin: g.radius,
+		}, true
+	case *point:
+		return &convexCore{support: func(r3.Vector) r3.Vector { return g.position }}, true
+	case *convexHull:
+		vertices := g.worldVertices()
+		return &convexCore{
+			support: func(direction r3.Vector) r3.Vector { return farthestVertex(vertices, direction) },
+			axes:    g.worldNormals(),
+		}, true
+	case *cylinder:
+		return &convexCore{support: g.support, axes: []r3.Vector{g.axis}}, true
+	case *cone:
+		return &convexCore{support: g.support, axes: []r3.Vector{g.axis}}, true
+	default:
+		return nil, false
+	}
+}
+
+// farthestVertex returns the vertex farthest in a direction.
+func farthestVertex(vertices []r3.Vector, direction r3.Vector) r3.Vector {
+	best := vertices[0]
+	bestDot := best.Dot(direction)
+	for _, v := range vertices[1:] {
+		if dot := v.Dot(direction); dot > bestDot {
+			best, bestDot = v, dot
+		}
+	}
+	return best
+}
+
+// convexEncompassedBy returns whether a convex geometry, given by its convex core and the points of it from which every other point of it
+// is made up, is encompassed by another geometry. Geometries bounded by planes encompass it exactly if it lies within all of their faces,
+// and other geometries are only checked at the points, so for geometries with curved edges the points should be spaced along them.
+func convexEncompassedBy(self Geometry, core *convexCore, extremePoints []r3.Vector, g Geometry) (bool, error) {
+	if _, ok := g.(*point); ok {
+		return false, nil
+	}
+	other, ok := convexCoreOf(g)
+	if !ok {
+		return false, newCollisionTypeUnsupportedError(self, g)
+	}
+	switch g.(type) {
+	case *box, *convexHull:
+		for _, axis := range other.axes {
+			for _, direction := range []r3.Vector{axis, axis.Mul(-1)} {
+				if core.support(direction).Dot(direction)+core.margin > other.support(direction).Dot(direction)+CollisionBuffer {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+	default:
+		for _, pt := range extremePoints {
+			distance, err := NewPoint(pt, "").DistanceFrom(g)
+			if err != nil || distance > CollisionBuffer {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// convexDistance returns the distance between two convex geometries. If they are in collision it is negative, with a magnitude which is
+// the penetration depth if only their margins overlap, and otherwise an estimate of it which is no smaller, found by separating them
+// along their axes.
+func convexDistance(a, b *convexCore) float64 {
+	if distance := gjkDistance(a, b); distance > 0 {
+		return distance - a.margin - b.margin
+	}
+	penetration := math.Inf(1)
+	for _, axes := range [][]r3.Vector{a.axes, b.axes} {
+		for _, axis := range axes {
+			// the overlap of the projections of the geometries onto the axis, in the direction of each of its ends
+			aMax, aMin := a.support(axis).Dot(axis)+a.margin, a.support(axis.Mul(-1)).Dot(axis)-a.margin
+			bMax, bMin := b.support(axis).Dot(axis)+b.margin, b.support(axis.Mul(-1)).Dot(axis)-b.margin
+			penetration = math.Min(penetration, math.Min(aMax-bMin, bMax-aMin))
+		}
+	}
+	if math.IsInf(penetration, 1) {
+		// geometries without axes overlap only by their margins
+		penetration = a.margin + b.margin
+	}
+	return -penetration
+}
+
+// gjkDistance returns the distance between the supports of two convex geometries, not including their margins, found with the
+// Gilbert-Johnson-Keerthi algorithm as the distance from the origin to their Minkowski difference. It is zero if they intersect.
+// Reference: https://en.wikipedia.org/wiki/Gilbert%E2%80%93Johnson%E2%80%93Keerthi_distance_algorithm
+func gjkDistance(a, b *convexCore) float64 {
+	minkowskiSupport := func(direction r3.Vector) r3.Vector {
+		return a.support(direction).Sub(b.support(direction.Mul(-1)))
+	}
+	closest := minkowskiSupport(r3.Vector{X: 1})
+	simplex := []r3.Vector{closest}
+	for i := 0; i < gjkMaxIterations; i++ {
+		distSq := closest.Norm2()
+		if distSq < CollisionBuffer*CollisionBuffer {
+			return 0
+		}
+		next := minkowskiSupport(closest.Mul(-1))
+		// stop once the Minkowski difference extends no closer to the origin than the closest point found so far
+		if distSq-closest.Dot(next) <= 1e-10*distSq {
+			break
+		}
+		pt, reduced := closestPointOfSimplex(append(simplex, next))
+		if len(reduced) == 4 {
+			return 0
+		}
+		if reduced == nil || pt.Norm2() >= distSq {
+			// rounding has stopped the search from making progress
+			break
+		}
+		closest, simplex = pt, reduced
+	}
+	return closest.Norm()
+}
+
+// closestPointOfSimplex returns the point of a simplex of up to four points closest to the origin, and the smallest subset of the
+// simplex whose hull contains it. The point is the closest to the origin within the affine hull of exactly one subset while lying inside
+// that subset's hull, so each subset is tried. The subset is nil if rounding leaves no subset containing its closest point.
+func closestPointOfSimplex(simplex []r3.Vector) (r3.Vector, []r3.Vector) {
+	var best r3.Vector
+	var bestSubset []r3.Vector
+	bestDistSq := math.Inf(1)
+	for mask := 1; mask < 1<<len(simplex); mask++ {
+		subset := make([]r3.Vector, 0, len(simplex))
+		for i, pt := range simplex {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, pt)
+			}
+		}
+		pt, inside := closestPointOfAffineHull(subset)
+		if !inside {
+			continue
+		}
+		if distSq := pt.Norm2(); distSq < bestDistSq {
+			best, bestSubset, bestDistSq = pt, subset, distSq
+		}
+	}
+	return best, bestSubset
+}
+
+// closestPointOfAffineHull returns the point of the affine hull of some points closest to the origin, and whether it lies strictly inside
+// their hull. Points which are not affinely independent have no such point.
+func closestPointOfAffineHull(pts []r3.Vector) (r3.Vector, bool) {
+	if len(pts) == 1 {
+		return pts[0], true
+	}
+	// minimize |pts[0] + sum(mu_i * edges_i)| by solving the normal equations of the edges from pts[0]
+	n := len(pts) - 1
+	edges := make([]r3.Vector, n)
+	for i := range edges {
+		edges[i] = pts[i+1].Sub(pts[0])
+	}
+	system := make([][]float64, n)
+	for i := range system {
+		system[i] = make([]float64, n+1)
+		for j := range edges {
+			system[i][j] = edges[i].Dot(edges[j])
+		}
+		system[i][n] = -edges[i].Dot(pts[0])
+	}
+	mu, ok := solveLinearSystem(system)
+	if !ok {
+		return r3.Vector{}, false
+	}
+	pt := pts[0]
+	first := 1.
+	for i, m := range mu {
+		if m <= 0 {
+			return r3.Vector{}, false
+		}
+		first -= m
+		pt = pt.Add(edges[i].Mul(m))
+	}
+	return pt, first > 0
+}
+
+// solveLinearSystem solves a small system of linear equations, given as the rows of its augmented matrix, by Gaussian elimination
+// with partial pivoting. It returns false if the system is singular.
+func solveLinearSystem(system [][]float64) ([]float64, bool) {
+	n := len(system)
+	scale := 0.
+	for _, row := range system {
+		for _, v := range row[:n] {
+			scale = math.Max(scale, math.Abs(v))
+		}
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(system[row][col]) > math.Abs(system[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(system[pivot][col]) <= 1e-12*scale {
+			return nil, false
+		}
+		system[col], system[pivot] = system[pivot], system[col]
+		for row := col + 1; row < n; row++ {
+			factor := system[row][col] / system[col][col]
+			for k := col; k <= n; k++ {
+				system[row][k] -= factor * system[col][k]
+			}
+		}
+	}
+	solution := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := system[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= system[row][k] * solution[k]
+		}
+		solution[row] = sum / system[row][row]
+	}
+	return solution, true
+}
@@ -88,6 +88,18 @@ func (pt *point) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return pt.AlmostEqual(other), nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.CollidesWith(pt)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.CollidesWith(pt)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.CollidesWith(pt)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.CollidesWith(pt)
+	}
 	return true, newCollisionTypeUnsupportedError(pt, g)
 }
 
@@ -105,6 +117,18 @@ func (pt *point) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return pt.position.Sub(other.position).Norm(), nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(pt)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.DistanceFrom(pt)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.DistanceFrom(pt)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.DistanceFrom(pt)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(pt, g)
 }
 
@@ -116,14 +140,14 @@ func (pt *point) EncompassedBy(g Geometry) (bool, error) {
 // pointVsBoxCollision takes a box and a point as arguments and returns a bool describing if they are in collision. \
 // true == collision / false == no collision.
 func pointVsBoxCollision(pt r3.Vector, b *box) bool {
-	return b.closestPoint(pt).Sub(pt).Norm() <= 0
+	return b.pointSeparation(pt) <= 0
 }
 
 // pointVsBoxDistance takes a box and a point as arguments and returns a floating point number.  If this number is nonpositive it represents
 // the penetration depth of the point within the box.  If the returned float is positive it represents the separation distance between the
 // point and the box, which are not in collision.
 func pointVsBoxDistance(pt r3.Vector, b *box) float64 {
-	distance := b.closestPoint(pt).Sub(pt).Norm()
+	distance := b.pointSeparation(pt)
 	if distance > 0 {
 		return distance
 	}
@@ -6,6 +6,8 @@
 package spatialmath
 
 import (
+	"math"
+
 	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
 	"gonum.org/v1/gonum/num/dualquat"
@@ -148,6 +150,51 @@ func Interpolate(p1, p2 Pose, by float64) Pose {
 	return intQ
 }
 
+// ScrewInterpolate will return a new Pose that has been interpolated the set amount between two poses along the screw motion between
+// them, the single rotation about an axis and translation along it which takes p1 to p2. Whereas Interpolate moves the position in a
+// straight line while the orientation turns, this moves every point of the frame along a helix about the axis, as a nut is driven along
+// a bolt, which is the shortest path between the poses in SE(3).
+// by == 0 will return p1, by == 1 will return p2, and by == 0.5 will return the pose halfway between them.
+func ScrewInterpolate(p1, p2 Pose, by float64) Pose {
+	delta, axis, pivot, angle, along := screwMotion(p1, p2)
+	// without rotation the screw axis is infinitely far away, and the motion is a straight line
+	if angle < defaultDistanceEpsilon {
+		return Compose(NewPoseFromPoint(delta.Point().Mul(by)), p1)
+	}
+	turn := NewPoseFromOrientation(&R4AA{Theta: by * angle, RX: axis.X, RY: axis.Y, RZ: axis.Z})
+	swung := Compose(turn, NewPoseFromPoint(pivot.Mul(-1))).Point()
+	return Compose(NewPose(pivot.Add(swung).Add(axis.Mul(by*along)), turn.Orientation()), p1)
+}
+
+// ScrewPathLength returns the length of the helix the position of p1 follows along the screw motion to p2, see ScrewInterpolate. It is
+// at least the distance between the poses, and longer the further the position is from the screw axis.
+func ScrewPathLength(p1, p2 Pose) float64 {
+	delta, axis, pivot, angle, along := screwMotion(p1, p2)
+	if angle < defaultDistanceEpsilon {
+		return delta.Point().Norm()
+	}
+	offset := p1.Point().Sub(pivot)
+	radius := offset.Sub(axis.Mul(offset.Dot(axis))).Norm()
+	return math.Hypot(angle*radius, along)
+}
+
+// screwMotion returns the transform from p1 to p2 in the world frame, and the screw motion making it up: the axis and angle it turns by,
+// a point of the axis, and how far it moves along the axis. The axis and point are zero if it does not turn.
+func screwMotion(p1, p2 Pose) (delta Pose, axis, pivot r3.Vector, angle, along float64) {
+	delta = Compose(p2, PoseInverse(p1))
+	rotation := QuatToR3AA(delta.Orientation().Quaternion())
+	angle = rotation.Norm()
+	if angle < defaultDistanceEpsilon {
+		return delta, r3.Vector{}, r3.Vector{}, angle, delta.Point().Norm()
+	}
+	axis = rotation.Mul(1 / angle)
+	along = delta.Point().Dot(axis)
+	across := delta.Point().Sub(axis.Mul(along))
+	// the point of the screw axis about which rotating by the whole angle carries the origin to the part of the translation across it
+	pivot = across.Add(axis.Cross(across).Mul(1 / math.Tan(angle/2))).Mul(0.5)
+	return delta, axis, pivot, angle, along
+}
+
 // PoseAlmostEqual will return a bool describing whether 2 poses are approximately the same.
 func PoseAlmostEqual(a, b Pose) bool {
 	return PoseAlmostCoincident(a, b) && OrientationAlmostEqual(a.Orientation(), b.Orientation())
@@ -0,0 +1,126 @@
+package spatialmath
+
+import (
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+)
+
+// AveragePoses returns the weighted average of a set of poses, such as repeated measurements of a fiducial or of a hand-eye
+// calibration. Positions are averaged directly. Orientations are averaged as the quaternion maximizing the weighted sum of its squared
+// dot products with theirs, which, unlike averaging their components, does not depend on which of the two quaternions representing each
+// orientation is used, nor on the order of the poses. If weights is nil the poses are weighted equally.
+func AveragePoses(poses []Pose, weights []float64) (Pose, error) {
+	if len(poses) == 0 {
+		return nil, errors.New("cannot average no poses")
+	}
+	if weights != nil && len(weights) != len(poses) {
+		return nil, errors.Errorf("got %d weights for %d poses", len(weights), len(poses))
+	}
+
+	var point r3.Vector
+	var total float64
+	outer := mat.NewSymDense(4, nil)
+	for i, p := range poses {
+		w := 1.
+		if weights != nil {
+			w = weights[i]
+		}
+		if w < 0 || math.IsNaN(w) {
+			return nil, errors.Errorf("weight %d must be a non-negative number, got %f", i, w)
+		}
+		point = point.Add(p.Point().Mul(w))
+		total += w
+
+		q := p.Orientation().Quaternion()
+		qv := mat.NewVecDense(4, []float64{q.Real, q.Imag, q.Jmag, q.Kmag})
+		outer.SymRankOne(outer, w, qv)
+	}
+	if total == 0 {
+		return nil, errors.New("cannot average poses whose weights sum to zero")
+	}
+
+	var eig mat.EigenSym
+	if !eig.Factorize(outer, true) {
+		return nil, errors.New("could not average the orientations of the poses")
+	}
+	var vectors mat.Dense
+	eig.VectorsTo(&vectors)
+	// eigenvalues are in ascending order, so the average is the eigenvector of the last and largest
+	average := quat.Number{Real: vectors.At(0, 3), Imag: vectors.At(1, 3), Jmag: vectors.At(2, 3), Kmag: vectors.At(3, 3)}
+	return NewPose(point.Mul(1/total), (*Quaternion)(&average)), nil
+}
+
+// PoseFilter is an exponential low-pass filter of a stream of poses, such as the noisy output of a localization or a pose tracker. Each
+// pose it is updated with moves the filtered pose the fraction alpha of the way towards it, with the position moving in a straight line
+// and the orientation by slerp. It is not safe for concurrent use.
+type PoseFilter struct {
+	alpha float64
+	pose  Pose
+}
+
+// NewPoseFilter returns a PoseFilter smoothing by alpha, which must be in (0, 1]. Smaller values smooth more and lag more, and 1
+// does not filter at all.
+func NewPoseFilter(alpha float64) (*PoseFilter, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, errors.Errorf("alpha must be in (0, 1], got %f", alpha)
+	}
+	return &PoseFilter{alpha: alpha}, nil
+}
+
+// PoseFilterAlpha returns the alpha of a PoseFilter updated every interval which filters with the given time constant, the time it
+// takes the filtered pose to move about 63% of the way to a pose it is held at.
+func PoseFilterAlpha(timeConstant, interval time.Duration) float64 {
+	if timeConstant <= 0 {
+		return 1
+	}
+	return 1 - math.Exp(-interval.Seconds()/timeConstant.Seconds())
+}
+
+// Update filters a new pose into the filtered pose and returns it. The first pose the filter is updated with is taken as is.
+func (f *PoseFilter) Update(p Pose) Pose {
+	if f.pose == nil {
+		f.pose = NewPose(p.Point(), p.Orientation())
+		return f.pose
+	}
+	f.pose = f.step(p, f.alpha)
+	return f.pose
+}
+
+// UpdateWithAlpha filters a new pose into the filtered pose using the given alpha instead of that of the filter, such as one from
+// PoseFilterAlpha for a stream of poses which arrive at irregular intervals, and returns it.
+func (f *PoseFilter) UpdateWithAlpha(p Pose, alpha float64) (Pose, error) {
+	if alpha <= 0 || alpha > 1 {
+		return nil, errors.Errorf("alpha must be in (0, 1], got %f", alpha)
+	}
+	if f.pose == nil {
+		return f.Update(p), nil
+	}
+	f.pose = f.step(p, alpha)
+	return f.pose, nil
+}
+
+// Pose returns the filtered pose, or nil if the filter has not been updated since it was made or reset.
+func (f *PoseFilter) Pose() Pose {
+	return f.pose
+}
+
+// Reset forgets the filtered pose, so that the next pose the filter is updated with is taken as is.
+func (f *PoseFilter) Reset() {
+	f.pose = nil
+}
+
+func (f *PoseFilter) step(p Pose, alpha float64) Pose {
+	point := f.pose.Point().Add(p.Point().Sub(f.pose.Point()).Mul(alpha))
+	from, to := f.pose.Orientation().Quaternion(), p.Orientation().Quaternion()
+	// turn the short way, whichever of the two quaternions representing the new orientation it was given as
+	if from.Real*to.Real+from.Imag*to.Imag+from.Jmag*to.Jmag+from.Kmag*to.Kmag < 0 {
+		to = quat.Scale(-1, to)
+	}
+	orientation := slerp(from, to, alpha)
+	return NewPose(point, (*Quaternion)(&orientation))
+}
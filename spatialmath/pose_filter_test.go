@@ -0,0 +1,111 @@
+package spatialmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"gonum.org/v1/gonum/num/quat"
+)
+
+func TestAveragePoses(t *testing.T) {
+	// poses spread evenly about a mean pose average to it
+	mean := NewPose(r3.Vector{10, 20, 30}, &OrientationVectorDegrees{OX: 1, OY: 1, OZ: 1, Theta: 30})
+	var poses []Pose
+	for _, axis := range []r3.Vector{{X: 1}, {Y: 1}, {Z: 1}} {
+		for _, sign := range []float64{-1, 1} {
+			offset := NewPose(axis.Mul(5*sign), &R4AA{Theta: 0.2 * sign, RX: axis.X, RY: axis.Y, RZ: axis.Z})
+			poses = append(poses, Compose(mean, offset))
+		}
+	}
+	average, err := AveragePoses(poses, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqualEps(average, mean, 1e-6), test.ShouldBeTrue)
+
+	// which quaternion represents each orientation does not matter
+	flipped := make([]Pose, 0, len(poses))
+	for _, p := range poses {
+		q := quat.Scale(-1, p.Orientation().Quaternion())
+		flipped = append(flipped, NewPose(p.Point(), (*Quaternion)(&q)))
+	}
+	average, err = AveragePoses(flipped, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqualEps(average, mean, 1e-6), test.ShouldBeTrue)
+
+	// weights pull the average towards the heavier poses
+	p1 := NewPoseFromPoint(r3.Vector{})
+	p2 := NewPose(r3.Vector{X: 40}, &R4AA{Theta: math.Pi / 2, RZ: 1})
+	average, err = AveragePoses([]Pose{p1, p2}, []float64{3, 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostCoincident(average, NewPoseFromPoint(r3.Vector{X: 10})), test.ShouldBeTrue)
+	test.That(t, OrientationBetween(average.Orientation(), p1.Orientation()).AxisAngles().Theta, test.ShouldBeLessThan, math.Pi/4)
+	average, err = AveragePoses([]Pose{p1, p2}, []float64{0, 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqual(average, p2), test.ShouldBeTrue)
+
+	for _, weights := range [][]float64{{1}, {1, -1}, {0, 0}, {1, math.NaN()}} {
+		_, err = AveragePoses([]Pose{p1, p2}, weights)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+	_, err = AveragePoses(nil, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPoseFilter(t *testing.T) {
+	_, err := NewPoseFilter(0)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewPoseFilter(1.5)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	filter, err := NewPoseFilter(0.5)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, filter.Pose(), test.ShouldBeNil)
+
+	// the first pose is taken as is, and later ones are moved towards by alpha
+	start := NewPoseFromPoint(r3.Vector{})
+	test.That(t, PoseAlmostEqual(filter.Update(start), start), test.ShouldBeTrue)
+	goal := NewPose(r3.Vector{X: 100}, &R4AA{Theta: math.Pi / 2, RZ: 1})
+	filtered := filter.Update(goal)
+	test.That(t, PoseAlmostEqual(filtered, NewPose(r3.Vector{X: 50}, &R4AA{Theta: math.Pi / 4, RZ: 1})), test.ShouldBeTrue)
+	test.That(t, filter.Pose(), test.ShouldEqual, filtered)
+
+	// the filter turns the short way to an orientation given by the negated quaternion
+	negated := quat.Scale(-1, goal.Orientation().Quaternion())
+	filtered = filter.Update(NewPose(goal.Point(), (*Quaternion)(&negated)))
+	test.That(t, PoseAlmostEqual(filtered, NewPose(r3.Vector{X: 75}, &R4AA{Theta: 3 * math.Pi / 8, RZ: 1})), test.ShouldBeTrue)
+
+	// a pose held long enough is converged to
+	for i := 0; i < 60; i++ {
+		filtered = filter.Update(goal)
+	}
+	test.That(t, PoseAlmostEqualEps(filtered, goal, 1e-6), test.ShouldBeTrue)
+
+	// noise about a pose is smoothed
+	randSeed := rand.New(rand.NewSource(1))
+	smooth, err := NewPoseFilter(0.05)
+	test.That(t, err, test.ShouldBeNil)
+	var worstRaw, worstFiltered float64
+	for i := 0; i < 500; i++ {
+		noisy := NewPoseFromPoint(r3.Vector{randSeed.NormFloat64(), randSeed.NormFloat64(), randSeed.NormFloat64()})
+		filtered = smooth.Update(noisy)
+		if i > 100 {
+			worstRaw = math.Max(worstRaw, noisy.Point().Norm())
+			worstFiltered = math.Max(worstFiltered, filtered.Point().Norm())
+		}
+	}
+	test.That(t, worstFiltered, test.ShouldBeLessThan, worstRaw/2)
+
+	filter.Reset()
+	test.That(t, filter.Pose(), test.ShouldBeNil)
+	filtered, err = filter.UpdateWithAlpha(start, 0.1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, PoseAlmostEqual(filtered, start), test.ShouldBeTrue)
+	_, err = filter.UpdateWithAlpha(goal, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, PoseFilterAlpha(time.Second, time.Second), test.ShouldAlmostEqual, 1-math.Exp(-1))
+	test.That(t, PoseFilterAlpha(0, time.Second), test.ShouldEqual, 1)
+}
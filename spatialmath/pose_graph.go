@@ -0,0 +1,437 @@
+package spatialmath
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+)
+
+// PoseGraphMethod is the algorithm used to optimize a pose graph.
+type PoseGraphMethod int
+
+const (
+	// PoseGraphLevenbergMarquardt damps every step and only takes steps that reduce the error. It is the default.
+	PoseGraphLevenbergMarquardt PoseGraphMethod = iota
+	// PoseGraphGaussNewton takes undamped steps, which converge faster from a good initial estimate.
+	PoseGraphGaussNewton
+)
+
+const (
+	poseGraphDefaultIterations = 100
+	poseGraphDefaultTolerance  = 1e-9
+	poseGraphInitialDamping    = 1e-3
+	poseGraphMaxDamping        = 1e12
+	// poseGraphJacobianStep is the perturbation, in mm and radians, used to differentiate edge errors numerically.
+	poseGraphJacobianStep = 1e-6
+	// poseGraphMinStep is the largest change, in mm and radians, of a step too small to matter.
+	poseGraphMinStep = 1e-7
+)
+
+// PoseGraphNode is a pose of a trajectory at a point in time, relative to the world frame.
+// Fixed nodes, such as ones at surveyed fiducials, keep their pose during optimization.
+type PoseGraphNode struct {
+	ID    int
+	Time  time.Time
+	Pose  Pose
+	Fixed bool
+}
+
+// PoseGraphEdge is a measurement of the pose of node To relative to the pose of node From, such as wheel odometry
+// between consecutive poses, or a fiducial observed from both. Information is the inverse of the covariance of the
+// measurement, ordered as the translation in mm followed by the rotation vector in radians, in the frame of node To.
+type PoseGraphEdge struct {
+	From        int
+	To          int
+	Measurement Pose
+	Information *mat.SymDense
+}
+
+// PoseGraphOptions configures the optimization of a pose graph.
+type PoseGraphOptions struct {
+	Method PoseGraphMethod
+	// MaxIterations defaults to 100.
+	MaxIterations int
+	// Tolerance is the relative decrease of the error below which the optimization has converged, and defaults to 1e-9.
+	Tolerance float64
+}
+
+// PoseGraphResult describes an optimization of a pose graph. The errors are the sums of the squared errors
+// of every edge, weighted by their information.
+type PoseGraphResult struct {
+	Iterations   int
+	InitialError float64
+	FinalError   float64
+	Converged    bool
+}
+
+// PoseGraph holds stamped poses and the relative measurements between them, so that poses from different sources,
+// such as wheel odometry, fiducial observations and SLAM, can be fused into one consistent trajectory.
+// It is solved densely, which suits graphs of up to a few thousand nodes.
+type PoseGraph struct {
+	nodes []PoseGraphNode
+	index map[int]int
+	edges []PoseGraphEdge
+}
+
+// NewPoseGraph returns an empty pose graph.
+func NewPoseGraph() *PoseGraph {
+	return &PoseGraph{index: map[int]int{}}
+}
+
+// AddNode adds a node with an initial estimate of its pose, which is the zero pose if nil.
+func (pg *PoseGraph) AddNode(id int, t time.Time, initial Pose) error {
+	if _, ok := pg.index[id]; ok {
+		return errors.Errorf("pose graph already has a node %d", id)
+	}
+	if initial == nil {
+		initial = NewZeroPose()
+	}
+	pg.index[id] = len(pg.nodes)
+	pg.nodes = append(pg.nodes, PoseGraphNode{ID: id, Time: t, Pose: initial})
+	return nil
+}
+
+// FixNode keeps the pose of a node constant during optimization. If no node is fixed, the first node added is.
+func (pg *PoseGraph) FixNode(id int) error {
+	i, ok := pg.index[id]
+	if !ok {
+		return errors.Errorf("pose graph has no node %d", id)
+	}
+	pg.nodes[i].Fixed = true
+	return nil
+}
+
+// AddEdge adds a measurement of the pose of node to relative to node from, with a 6x6 covariance as described
+// by PoseGraphEdge. A nil covariance is the identity.
+func (pg *PoseGraph) AddEdge(from, to int, measurement Pose, covariance *mat.SymDense) error {
+	for _, id := range []int{from, to} {
+		if _, ok := pg.index[id]; !ok {
+			return errors.Errorf("pose graph has no node %d", id)
+		}
+	}
+	if from == to {
+		return errors.Errorf("pose graph edge cannot connect node %d to itself", from)
+	}
+	info := mat.NewSymDense(6, nil)
+	if covariance == nil {
+		for i := 0; i < 6; i++ {
+			info.SetSym(i, i, 1)
+		}
+	} else {
+		if covariance.SymmetricDim() != 6 {
+			return errors.Errorf("pose graph edge covariance must be 6x6, not %dx%d", covariance.SymmetricDim(), covariance.SymmetricDim())
+		}
+		var chol mat.Cholesky
+		if !chol.Factorize(covariance) {
+			return errors.New("pose graph edge covariance must be positive definite")
+		}
+		if err := chol.InverseTo(info); err != nil {
+			return errors.Wrap(err, "cannot invert pose graph edge covariance")
+		}
+	}
+	pg.edges = append(pg.edges, PoseGraphEdge{From: from, To: to, Measurement: measurement, Information: info})
+	return nil
+}
+
+// DiagonalCovariance returns the covariance of a measurement with independent errors of the given standard
+// deviations, in mm for the translation and radians for the rotation.
+func DiagonalCovariance(translationStdDev, rotationStdDev float64) *mat.SymDense {
+	cov := mat.NewSymDense(6, nil)
+	for i := 0; i < 3; i++ {
+		cov.SetSym(i, i, translationStdDev*translationStdDev)
+		cov.SetSym(i+3, i+3, rotationStdDev*rotationStdDev)
+	}
+	return cov
+}
+
+// Node returns the node with the given id.
+func (pg *PoseGraph) Node(id int) (PoseGraphNode, bool) {
+	i, ok := pg.index[id]
+	if !ok {
+		return PoseGraphNode{}, false
+	}
+	return pg.nodes[i], true
+}
+
+// Nodes returns the nodes of the graph in order of time, which is the trajectory they make up.
+func (pg *PoseGraph) Nodes() []PoseGraphNode {
+	nodes := make([]PoseGraphNode, len(pg.nodes))
+	copy(nodes, pg.nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Time.Before(nodes[j].Time)
+	})
+	return nodes
+}
+
+// Edges returns the edges of the graph in the order they were added.
+func (pg *PoseGraph) Edges() []PoseGraphEdge {
+	edges := make([]PoseGraphEdge, len(pg.edges))
+	copy(edges, pg.edges)
+	return edges
+}
+
+// TotalError returns the sum of the squared errors of every edge, weighted by their information.
+func (pg *PoseGraph) TotalError() float64 {
+	total := 0.
+	for _, edge := range pg.edges {
+		e := mat.NewVecDense(6, pg.edgeError(edge, nil, nil))
+		total += mat.Inner(e, edge.Information, e)
+	}
+	return total
+}
+
+// Optimize moves the poses of the nodes that are not fixed to minimize the total error of the graph.
+func (pg *PoseGraph) Optimize(opts PoseGraphOptions) (PoseGraphResult, error) {
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = poseGraphDefaultIterations
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = poseGraphDefaultTolerance
+	}
+	blocks, size, err := pg.variableBlocks()
+	if err != nil {
+		return PoseGraphResult{}, err
+	}
+
+	current := pg.TotalError()
+	result := PoseGraphResult{InitialError: current, FinalError: current}
+	if size == 0 || current == 0 {
+		result.Converged = true
+		return result, nil
+	}
+
+	damping := poseGraphInitialDamping
+	var h *mat.SymDense
+	var g *mat.VecDense
+	relinearize := true
+	for result.Iterations < opts.MaxIterations {
+		result.Iterations++
+		if relinearize {
+			h, g = pg.linearize(blocks, size)
+		}
+		system := h
+		if opts.Method == PoseGraphLevenbergMarquardt {
+			system = mat.NewSymDense(size, nil)
+			system.CopySym(h)
+			for i := 0; i < size; i++ {
+				system.SetSym(i, i, h.At(i, i)*(1+damping))
+			}
+		}
+		var chol mat.Cholesky
+		if !chol.Factorize(system) {
+			return result, errors.New("pose graph cannot be solved, the information of its edges must be positive definite")
+		}
+		var step mat.VecDense
+		if err := chol.SolveVecTo(&step, g); err != nil {
+			return result, errors.Wrap(err, "cannot solve pose graph")
+		}
+		step.ScaleVec(-1, &step)
+		negligible := mat.Norm(&step, math.Inf(1)) < poseGraphMinStep
+
+		previous := pg.poses()
+		pg.applyStep(blocks, &step)
+		next := pg.TotalError()
+		if opts.Method == PoseGraphLevenbergMarquardt && next >= current {
+			// the step made things worse, so take a shorter one in the direction of the gradient
+			pg.setPoses(previous)
+			damping *= 10
+			relinearize = false
+			if negligible || damping > poseGraphMaxDamping {
+				// no step reduces the error any more
+				result.Converged = true
+				break
+			}
+			continue
+		}
+		damping /= 10
+		relinearize = true
+		decrease := current - next
+		current = next
+		result.FinalError = current
+		if negligible || math.Abs(decrease) <= opts.Tolerance*(current+decrease) || current == 0 {
+			result.Converged = true
+			break
+		}
+	}
+	return result, nil
+}
+
+// variableBlocks returns, for every node, the index of the block of 6 variables of its pose in the linear system,
+// or -1 if it is fixed, and the size of the system. Every free node must be connected to a fixed one, or the
+// system has no unique solution.
+func (pg *PoseGraph) variableBlocks() ([]int, int, error) {
+	fixed := false
+	for _, node := range pg.nodes {
+		fixed = fixed || node.Fixed
+	}
+	neighbors := make([][]int, len(pg.nodes))
+	for _, edge := range pg.edges {
+		i, j := pg.index[edge.From], pg.index[edge.To]
+		neighbors[i] = append(neighbors[i], j)
+		neighbors[j] = append(neighbors[j], i)
+	}
+
+	blocks := make([]int, len(pg.nodes))
+	anchored := make([]bool, len(pg.nodes))
+	queue := []int{}
+	size := 0
+	for i, node := range pg.nodes {
+		if node.Fixed || (!fixed && i == 0) {
+			blocks[i] = -1
+			anchored[i] = true
+			queue = append(queue, i)
+			continue
+		}
+		blocks[i] = size / 6
+		size += 6
+	}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, j := range neighbors[i] {
+			if !anchored[j] {
+				anchored[j] = true
+				queue = append(queue, j)
+			}
+		}
+	}
+	for i, ok := range anchored {
+		if !ok {
+			return nil, 0, errors.Errorf("pose graph node %d is not connected to a fixed node", pg.nodes[i].ID)
+		}
+	}
+	return blocks, size, nil
+}
+
+// linearize returns the Gauss-Newton approximation of the hessian of the total error, and its gradient.
+func (pg *PoseGraph) linearize(blocks []int, size int) (*mat.SymDense, *mat.VecDense) {
+	h := mat.NewSymDense(size, nil)
+	g := mat.NewVecDense(size, nil)
+	for _, edge := range pg.edges {
+		ends := [2]int{pg.index[edge.From], pg.index[edge.To]}
+		e := mat.NewVecDense(6, pg.edgeError(edge, nil, nil))
+
+		// the jacobian of the edge error with respect to both of its poses, by central differences
+		jac := mat.NewDense(6, 12, nil)
+		delta := make([]float64, 6)
+		for k := 0; k < 12; k++ {
+			if blocks[ends[k/6]] < 0 {
+				continue
+			}
+			delta[k%6] = poseGraphJacobianStep
+			var plus, minus []float64
+			if k < 6 {
+				plus = pg.edgeError(edge, delta, nil)
+				delta[k%6] = -poseGraphJacobianStep
+				minus = pg.edgeError(edge, delta, nil)
+			} else {
+				plus = pg.edgeError(edge, nil, delta)
+				delta[k%6] = -poseGraphJacobianStep
+				minus = pg.edgeError(edge, nil, delta)
+			}
+			delta[k%6] = 0
+			for r := 0; r < 6; r++ {
+				jac.Set(r, k, (plus[r]-minus[r])/(2*poseGraphJacobianStep))
+			}
+		}
+
+		var jtInfo, contribution mat.Dense
+		jtInfo.Mul(jac.T(), edge.Information)
+		contribution.Mul(&jtInfo, jac)
+		var gradient mat.VecDense
+		gradient.MulVec(&jtInfo, e)
+
+		for r := 0; r < 12; r++ {
+			rowBlock := blocks[ends[r/6]]
+			if rowBlock < 0 {
+				continue
+			}
+			row := 6*rowBlock + r%6
+			g.SetVec(row, g.AtVec(row)+gradient.AtVec(r))
+			for c := 0; c < 12; c++ {
+				colBlock := blocks[ends[c/6]]
+				if colBlock < 0 {
+					continue
+				}
+				// the system is symmetric, so only its upper triangle is accumulated
+				if col := 6*colBlock + c%6; row <= col {
+					h.SetSym(row, col, h.At(row, col)+contribution.At(r, c))
+				}
+			}
+		}
+	}
+	return h, g
+}
+
+// edgeError returns the error of an edge, optionally with its poses perturbed, as the translation and rotation vector
+// of the difference between the measured and estimated relative poses.
+func (pg *PoseGraph) edgeError(edge PoseGraphEdge, fromDelta, toDelta []float64) []float64 {
+	from := pg.nodes[pg.index[edge.From]].Pose
+	to := pg.nodes[pg.index[edge.To]].Pose
+	if fromDelta != nil {
+		from = Compose(from, poseGraphExp(fromDelta))
+	}
+	if toDelta != nil {
+		to = Compose(to, poseGraphExp(toDelta))
+	}
+	estimate := Compose(PoseInverse(from), to)
+	return poseGraphLog(Compose(PoseInverse(edge.Measurement), estimate))
+}
+
+func (pg *PoseGraph) applyStep(blocks []int, step *mat.VecDense) {
+	for i, block := range blocks {
+		if block < 0 {
+			continue
+		}
+		delta := make([]float64, 6)
+		for k := range delta {
+			delta[k] = step.AtVec(6*block + k)
+		}
+		pg.nodes[i].Pose = Compose(pg.nodes[i].Pose, poseGraphExp(delta))
+	}
+}
+
+func (pg *PoseGraph) poses() []Pose {
+	poses := make([]Pose, len(pg.nodes))
+	for i, node := range pg.nodes {
+		poses[i] = node.Pose
+	}
+	return poses
+}
+
+func (pg *PoseGraph) setPoses(poses []Pose) {
+	for i := range pg.nodes {
+		pg.nodes[i].Pose = poses[i]
+	}
+}
+
+// poseGraphExp returns the pose of a translation and rotation vector.
+func poseGraphExp(delta []float64) Pose {
+	v := r3.Vector{delta[3], delta[4], delta[5]}
+	q := Quaternion{Real: 1, Imag: v.X / 2, Jmag: v.Y / 2, Kmag: v.Z / 2}
+	if theta := v.Norm(); theta > 1e-12 {
+		s := math.Sin(theta/2) / theta
+		q = Quaternion{Real: math.Cos(theta / 2), Imag: s * v.X, Jmag: s * v.Y, Kmag: s * v.Z}
+	}
+	return NewPose(r3.Vector{delta[0], delta[1], delta[2]}, &q)
+}
+
+// poseGraphLog returns the translation and rotation vector of a pose.
+func poseGraphLog(p Pose) []float64 {
+	q := p.Orientation().Quaternion()
+	if q.Real < 0 {
+		q = quat.Scale(-1, q)
+	}
+	v := r3.Vector{q.Imag, q.Jmag, q.Kmag}
+	scale := 2.
+	if n := v.Norm(); n > 1e-12 {
+		scale = 2 * math.Atan2(n, q.Real) / n
+	}
+	pt := p.Point()
+	return []float64{pt.X, pt.Y, pt.Z, scale * v.X, scale * v.Y, scale * v.Z}
+}
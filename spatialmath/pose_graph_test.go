@@ -0,0 +1,101 @@
+package spatialmath
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"gonum.org/v1/gonum/mat"
+)
+
+// squarePoseGraph makes a graph of a robot driving around a square, with its odometry and a loop closure,
+// and returns it with the true poses.
+func squarePoseGraph(t *testing.T) (*PoseGraph, []Pose) {
+	t.Helper()
+	truth := []Pose{
+		NewZeroPose(),
+		NewPose(r3.Vector{1000, 0, 0}, &R4AA{math.Pi / 2, 0, 0, 1}),
+		NewPose(r3.Vector{1000, 1000, 0}, &R4AA{math.Pi, 0, 0, 1}),
+		NewPose(r3.Vector{0, 1000, 0}, &R4AA{3 * math.Pi / 2, 0, 0, 1}),
+	}
+	drift := NewPose(r3.Vector{30, -20, 10}, &R4AA{0.1, 0, 0, 1})
+	start := time.Now()
+	pg := NewPoseGraph()
+	estimate := truth[0]
+	for i := range truth {
+		test.That(t, pg.AddNode(i, start.Add(time.Duration(i)*time.Second), estimate), test.ShouldBeNil)
+		estimate = Compose(Compose(estimate, drift), Compose(PoseInverse(truth[i]), truth[(i+1)%len(truth)]))
+	}
+	for i := range truth {
+		j := (i + 1) % len(truth)
+		measurement := Compose(PoseInverse(truth[i]), truth[j])
+		test.That(t, pg.AddEdge(i, j, measurement, DiagonalCovariance(10, 0.01)), test.ShouldBeNil)
+	}
+	return pg, truth
+}
+
+func TestPoseGraphOptimize(t *testing.T) {
+	for _, method := range []PoseGraphMethod{PoseGraphLevenbergMarquardt, PoseGraphGaussNewton} {
+		pg, truth := squarePoseGraph(t)
+		result, err := pg.Optimize(PoseGraphOptions{Method: method})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result.Converged, test.ShouldBeTrue)
+		test.That(t, result.InitialError, test.ShouldBeGreaterThan, 1)
+		test.That(t, result.FinalError, test.ShouldBeLessThan, 1e-6)
+		test.That(t, pg.TotalError(), test.ShouldAlmostEqual, result.FinalError)
+		for i, node := range pg.Nodes() {
+			test.That(t, node.ID, test.ShouldEqual, i)
+			test.That(t, PoseAlmostEqualEps(node.Pose, truth[i], 1e-3), test.ShouldBeTrue)
+		}
+	}
+}
+
+func TestPoseGraphFusion(t *testing.T) {
+	// odometry and a fiducial disagree about where the robot went, and the fiducial is trusted more
+	pg := NewPoseGraph()
+	test.That(t, pg.AddNode(1, time.Now(), nil), test.ShouldBeNil)
+	test.That(t, pg.AddNode(2, time.Now(), nil), test.ShouldBeNil)
+	test.That(t, pg.AddEdge(1, 2, NewPoseFromPoint(r3.Vector{100, 0, 0}), DiagonalCovariance(2, 0.1)), test.ShouldBeNil)
+	test.That(t, pg.AddEdge(1, 2, NewPoseFromPoint(r3.Vector{110, 0, 0}), DiagonalCovariance(1, 0.1)), test.ShouldBeNil)
+	test.That(t, pg.FixNode(1), test.ShouldBeNil)
+
+	result, err := pg.Optimize(PoseGraphOptions{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, result.Converged, test.ShouldBeTrue)
+	node, ok := pg.Node(2)
+	test.That(t, ok, test.ShouldBeTrue)
+	// the information weighted mean of 100 and 110 with variances of 4 and 1
+	test.That(t, node.Pose.Point().X, test.ShouldAlmostEqual, 108, 1e-6)
+	node, ok = pg.Node(1)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, PoseAlmostEqual(node.Pose, NewZeroPose()), test.ShouldBeTrue)
+	_, ok = pg.Node(3)
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+func TestPoseGraphErrors(t *testing.T) {
+	pg := NewPoseGraph()
+	test.That(t, pg.AddNode(0, time.Now(), nil), test.ShouldBeNil)
+	test.That(t, pg.AddNode(0, time.Now(), nil), test.ShouldNotBeNil)
+	test.That(t, pg.AddNode(1, time.Now(), nil), test.ShouldBeNil)
+	test.That(t, pg.FixNode(2), test.ShouldNotBeNil)
+	test.That(t, pg.AddEdge(0, 2, NewZeroPose(), nil), test.ShouldNotBeNil)
+	test.That(t, pg.AddEdge(0, 0, NewZeroPose(), nil), test.ShouldNotBeNil)
+	test.That(t, pg.AddEdge(0, 1, NewZeroPose(), mat.NewSymDense(3, nil)), test.ShouldNotBeNil)
+	test.That(t, pg.AddEdge(0, 1, NewZeroPose(), mat.NewSymDense(6, nil)), test.ShouldNotBeNil)
+	test.That(t, pg.Edges(), test.ShouldBeEmpty)
+
+	// node 1 is not connected to node 0, which anchors the graph
+	_, err := pg.Optimize(PoseGraphOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "node 1")
+
+	test.That(t, pg.AddEdge(0, 1, NewZeroPose(), nil), test.ShouldBeNil)
+	test.That(t, pg.Edges(), test.ShouldHaveLength, 1)
+	result, err := pg.Optimize(PoseGraphOptions{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, result.Converged, test.ShouldBeTrue)
+	test.That(t, result.Iterations, test.ShouldEqual, 0)
+}
@@ -2,6 +2,7 @@ package spatialmath
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/golang/geo/r3"
@@ -97,6 +98,44 @@ func TestPoseInterpolation(t *testing.T) {
 	ptCompare(t, intP.Point(), r3.Vector{100, 110, 200})
 }
 
+func TestScrewInterpolation(t *testing.T) {
+	// without rotation, a screw motion is a straight line
+	ov := &OrientationVectorDegrees{OX: 1, OY: 2, OZ: 3, Theta: 40}
+	intP := ScrewInterpolate(NewPose(r3.Vector{0, 0, 0}, ov), NewPose(r3.Vector{10, 100, 1000}, ov), 0.33)
+	test.That(t, PoseAlmostEqual(intP, NewPose(r3.Vector{3.3, 33, 330}, ov)), test.ShouldBeTrue)
+
+	// a quarter turn about the z axis while rising follows a helix about it
+	p1 := NewPoseFromPoint(r3.Vector{10, 0, 0})
+	p2 := NewPose(r3.Vector{0, 10, 5}, &R4AA{Theta: math.Pi / 2, RZ: 1})
+	intP = ScrewInterpolate(p1, p2, 0.5)
+	expected := NewPose(r3.Vector{10 * math.Cos(math.Pi/4), 10 * math.Sin(math.Pi/4), 2.5}, &R4AA{Theta: math.Pi / 4, RZ: 1})
+	test.That(t, PoseAlmostEqual(intP, expected), test.ShouldBeTrue)
+	test.That(t, ScrewPathLength(p1, p2), test.ShouldAlmostEqual, math.Hypot(10*math.Pi/2, 5))
+	test.That(t, ScrewPathLength(p1, NewPoseFromPoint(r3.Vector{10, 30, 40})), test.ShouldAlmostEqual, 50)
+
+	randSeed := rand.New(rand.NewSource(1))
+	randPose := func() Pose {
+		return NewPose(
+			r3.Vector{randSeed.Float64() * 100, randSeed.Float64() * 100, randSeed.Float64() * 100},
+			&OrientationVector{OX: randSeed.NormFloat64(), OY: randSeed.NormFloat64(), OZ: randSeed.NormFloat64(), Theta: randSeed.Float64() * 6},
+		)
+	}
+	for i := 0; i < 100; i++ {
+		p1, p2 := randPose(), randPose()
+		test.That(t, PoseAlmostEqualEps(ScrewInterpolate(p1, p2, 0), p1, 1e-6), test.ShouldBeTrue)
+		test.That(t, PoseAlmostEqualEps(ScrewInterpolate(p1, p2, 1), p2, 1e-6), test.ShouldBeTrue)
+		// the halves of a screw motion are screw motions
+		mid := ScrewInterpolate(p1, p2, 0.5)
+		test.That(t, PoseAlmostEqualEps(ScrewInterpolate(p1, mid, 0.5), ScrewInterpolate(p1, p2, 0.25), 1e-6), test.ShouldBeTrue)
+		// the length of the helix is that of the steps along it
+		length := 0.
+		for j := 1; j <= 1000; j++ {
+			length += ScrewInterpolate(p1, p2, float64(j-1)/1000).Point().Distance(ScrewInterpolate(p1, p2, float64(j)/1000).Point())
+		}
+		test.That(t, ScrewPathLength(p1, p2), test.ShouldAlmostEqual, length, 1e-3)
+	}
+}
+
 func TestLidarPose(t *testing.T) {
 	ea := NewEulerAngles()
 	// 45 degrees above horizon
@@ -98,6 +98,18 @@ func (s *sphere) CollidesWith(g Geometry) (bool, error) {
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position) <= CollisionBuffer, nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.CollidesWith(s)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.CollidesWith(s)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.CollidesWith(s)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.CollidesWith(s)
+	}
 	return true, newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -114,6 +126,18 @@ func (s *sphere) DistanceFrom(g Geometry) (float64, error) {
 	if other, ok := g.(*point); ok {
 		return sphereVsPointDistance(s, other.position), nil
 	}
+	if other, ok := g.(*voxelGrid); ok {
+		return other.DistanceFrom(s)
+	}
+	if other, ok := g.(*convexHull); ok {
+		return other.DistanceFrom(s)
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.DistanceFrom(s)
+	}
+	if other, ok := g.(*cone); ok {
+		return other.DistanceFrom(s)
+	}
 	return math.Inf(-1), newCollisionTypeUnsupportedError(s, g)
 }
 
@@ -127,6 +151,15 @@ func (s *sphere) EncompassedBy(g Geometry) (bool, error) {
 	if other, ok := g.(*box); ok {
 		return sphereInBox(s, other), nil
 	}
+	if other, ok := g.(*convexHull); ok {
+		return other.containsPoint(s.pose.Point(), CollisionBuffer-s.radius), nil
+	}
+	if other, ok := g.(*cylinder); ok {
+		return other.containsPoint(s.pose.Point(), CollisionBuffer-s.radius), nil
+	}
+	if other, ok := g.(*cone); ok {
+		return other.containsPoint(s.pose.Point(), CollisionBuffer-s.radius), nil
+	}
 	if _, ok := g.(*point); ok {
 		return false, nil
 	}
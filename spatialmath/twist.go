@@ -0,0 +1,15 @@
+package spatialmath
+
+import "github.com/golang/geo/r3"
+
+// Twist is the velocity of a rigid body, such as the end of an arm, as the linear velocity of its origin in mm per second and its
+// angular velocity in radians per second, both expressed in the axes of the frame it is measured in.
+type Twist struct {
+	Linear  r3.Vector
+	Angular r3.Vector
+}
+
+// Mul returns the twist scaled by a scalar, such as to find how far a body moves at a twist over a time in seconds.
+func (t Twist) Mul(scalar float64) Twist {
+	return Twist{Linear: t.Linear.Mul(scalar), Angular: t.Angular.Mul(scalar)}
+}
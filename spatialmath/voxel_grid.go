@@ -0,0 +1,292 @@
+package spatialmath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+)
+
+// maxVoxelsPerLeaf is the most voxels a node of the octree of a voxel grid holds before it is split into octants.
+const maxVoxelsPerLeaf = 8
+
+// voxelGrid is a collision geometry made up of equally sized cubes, such as those occupied by the points of a point cloud from a depth
+// camera. Its voxels are kept in an octree of the boxes bounding them, so that collisions and distances with other geometries are found
+// without visiting most of the voxels.
+type voxelGrid struct {
+	pose      Pose
+	voxelSize float64
+	root      *voxelNode // relative to the pose
+	size      int
+	label     string
+}
+
+// voxelNode is a node of the octree of a voxel grid, holding either voxels or the nodes of the octants it is split into.
+type voxelNode struct {
+	bounds         *box
+	boundingSphere *sphere
+	voxels         []*box
+	children       []*voxelNode
+}
+
+// NewVoxelGrid instantiates a new voxel grid Geometry, made up of the cubes with the given side length which are occupied by the given
+// points. The points are relative to the pose, and the voxels are aligned to its axes.
+func NewVoxelGrid(pose Pose, points []r3.Vector, voxelSize float64, label string) (Geometry, error) {
+	if voxelSize <= 0 {
+		return nil, newBadGeometryDimensionsError(&voxelGrid{})
+	}
+	if len(points) == 0 {
+		return nil, errors.New("cannot create a voxel grid without any points")
+	}
+	occupied := map[[3]int64]bool{}
+	centers := make([]r3.Vector, 0, len(points))
+	for _, pt := range points {
+		key := voxelKey(pt, voxelSize)
+		if occupied[key] {
+			continue
+		}
+		occupied[key] = true
+		centers = append(centers, r3.Vector{
+			X: (float64(key[0]) + 0.5) * voxelSize,
+			Y: (float64(key[1]) + 0.5) * voxelSize,
+			Z: (float64(key[2]) + 0.5) * voxelSize,
+		})
+	}
+	return &voxelGrid{
+		pose:      pose,
+		voxelSize: voxelSize,
+		root:      newVoxelNode(centers, voxelSize),
+		size:      len(centers),
+		label:     label,
+	}, nil
+}
+
+// voxelKey returns the index of the voxel a point falls in.
+func voxelKey(pt r3.Vector, voxelSize float64) [3]int64 {
+	return [3]int64{int64(math.Floor(pt.X / voxelSize)), int64(math.Floor(pt.Y / voxelSize)), int64(math.Floor(pt.Z / voxelSize))}
+}
+
+// axisAlignedBox returns a box with the given center and dimensions, aligned to the axes of the frame it is in.
+func axisAlignedBox(center, dims r3.Vector) *box {
+	halfSize := dims.Mul(0.5)
+	return &box{
+		pose:            NewPoseFromPoint(center),
+		halfSize:        [3]float64{halfSize.X, halfSize.Y, halfSize.Z},
+		boundingSphereR: halfSize.Norm(),
+	}
+}
+
+// newVoxelNode builds the octree of the voxels with the given distinct centers.
+func newVoxelNode(centers []r3.Vector, voxelSize float64) *voxelNode {
+	lo, hi := centers[0], centers[0]
+	for _, c := range centers[1:] {
+		lo = r3.Vector{X: math.Min(lo.X, c.X), Y: math.Min(lo.Y, c.Y), Z: math.Min(lo.Z, c.Z)}
+		hi = r3.Vector{X: math.Max(hi.X, c.X), Y: math.Max(hi.Y, c.Y), Z: math.Max(hi.Z, c.Z)}
+	}
+	voxelDims := r3.Vector{X: voxelSize, Y: voxelSize, Z: voxelSize}
+	mid := lo.Add(hi).Mul(0.5)
+	bounds := axisAlignedBox(mid, hi.Sub(lo).Add(voxelDims))
+	node := &voxelNode{bounds: bounds, boundingSphere: &sphere{pose: bounds.pose, radius: bounds.boundingSphereR}}
+	if len(centers) <= maxVoxelsPerLeaf {
+		for _, c := range centers {
+			node.voxels = append(node.voxels, axisAlignedBox(c, voxelDims))
+		}
+		return node
+	}
+
+	// the centers are distinct, so they differ along some axis and the midpoint along it splits them between at least two octants
+	var octants [8][]r3.Vector
+	for _, c := range centers {
+		i := 0
+		if c.X >= mid.X {
+			i |= 1
+		}
+		if c.Y >= mid.Y {
+			i |= 2
+		}
+		if c.Z >= mid.Z {
+			i |= 4
+		}
+		octants[i] = append(octants[i], c)
+	}
+	for _, octant := range octants {
+		if len(octant) > 0 {
+			node.children = append(node.children, newVoxelNode(octant, voxelSize))
+		}
+	}
+	return node
+}
+
+// String returns a human readable string that represents the voxel grid.
+func (vg *voxelGrid) String() string {
+	return fmt.Sprintf("Type: VoxelGrid, Voxels: %d, Voxel Size: %.0f", vg.size, vg.voxelSize)
+}
+
+func (vg *voxelGrid) MarshalJSON() ([]byte, error) {
+	config, err := NewGeometryConfig(vg)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// Label returns the label of this voxel grid.
+func (vg *voxelGrid) Label() string {
+	if vg != nil {
+		return vg.label
+	}
+	return ""
+}
+
+// Pose returns the pose of the voxel grid.
+func (vg *voxelGrid) Pose() Pose {
+	return vg.pose
+}
+
+// AlmostEqual compares the voxel grid with another geometry and checks if they are equivalent.
+func (vg *voxelGrid) AlmostEqual(g Geometry) bool {
+	other, ok := g.(*voxelGrid)
+	if !ok || vg.size != other.size || vg.voxelSize != other.voxelSize || !PoseAlmostEqual(vg.pose, other.pose) {
+		return false
+	}
+	occupied := make(map[[3]int64]bool, vg.size)
+	for _, c := range vg.centers() {
+		occupied[voxelKey(c, vg.voxelSize)] = true
+	}
+	for _, c := range other.centers() {
+		if !occupied[voxelKey(c, vg.voxelSize)] {
+			return false
+		}
+	}
+	return true
+}
+
+// Transform premultiplies the voxel grid pose with a transform, allowing the voxel grid to be moved in space.
+func (vg *voxelGrid) Transform(toPremultiply Pose) Geometry {
+	return &voxelGrid{
+		pose:      Compose(toPremultiply, vg.pose),
+		voxelSize: vg.voxelSize,
+		root:      vg.root,
+		size:      vg.size,
+		label:     vg.label,
+	}
+}
+
+// ToProtobuf converts the voxel grid to a Geometry proto message of the box bounding its voxels, as there is no message for voxel grids.
+// The message also records the voxels, so that NewGeometryFromProto decodes the voxel grid.
+func (vg *voxelGrid) ToProtobuf() *commonpb.Geometry {
+	bounds := vg.root.bounds.Transform(vg.pose).ToProtobuf()
+	bounds.Label = vg.label
+	voxels := make([][3]int64, 0, vg.size)
+	for _, c := range vg.centers() {
+		voxels = append(voxels, voxelKey(c, vg.voxelSize))
+	}
+	recordBoundedGeometry(bounds, boundedGeometryRecord{kind: boundedVoxelGrid, voxelSize: vg.voxelSize, voxels: voxels})
+	return bounds
+}
+
+// CollidesWith checks if the given voxel grid collides with the given geometry and returns true if it does.
+func (vg *voxelGrid) CollidesWith(g Geometry) (bool, error) {
+	return vg.root.collidesWith(g.Transform(PoseInverse(vg.pose)))
+}
+
+// DistanceFrom returns the distance from the given geometry to the nearest voxel of the voxel grid.
+func (vg *voxelGrid) DistanceFrom(g Geometry) (float64, error) {
+	distance, err := vg.root.distanceFrom(g.Transform(PoseInverse(vg.pose)), math.Inf(1))
+	if err != nil {
+		return math.Inf(-1), err
+	}
+	return distance, nil
+}
+
+// EncompassedBy returns whether every voxel of the voxel grid is encompassed by the given geometry.
+func (vg *voxelGrid) EncompassedBy(g Geometry) (bool, error) {
+	return vg.root.encompassedBy(g.Transform(PoseInverse(vg.pose)))
+}
+
+// ToPoints returns the centers of the voxels of the voxel grid, regardless of the resolution asked for.
+func (vg *voxelGrid) ToPoints(resolution float64) []r3.Vector {
+	centers := vg.centers()
+	for i, c := range centers {
+		centers[i] = Compose(vg.pose, NewPoseFromPoint(c)).Point()
+	}
+	return centers
+}
+
+// centers returns the centers of the voxels of the voxel grid, relative to its pose.
+func (vg *voxelGrid) centers() []r3.Vector {
+	centers := make([]r3.Vector, 0, vg.size)
+	var walk func(*voxelNode)
+	walk = func(node *voxelNode) {
+		for _, voxel := range node.voxels {
+			centers = append(centers, voxel.pose.Point())
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(vg.root)
+	return centers
+}
+
+// collidesWith returns whether a geometry, in the frame of the voxel grid, collides with any voxel of the node.
+func (node *voxelNode) collidesWith(g Geometry) (bool, error) {
+	if collides, err := node.bounds.CollidesWith(g); err != nil || !collides {
+		return collides, err
+	}
+	for _, voxel := range node.voxels {
+		if collides, err := voxel.CollidesWith(g); err != nil || collides {
+			return collides, err
+		}
+	}
+	for _, child := range node.children {
+		if collides, err := child.collidesWith(g); err != nil || collides {
+			return collides, err
+		}
+	}
+	return false, nil
+}
+
+// distanceFrom returns the distance from a geometry, in the frame of the voxel grid, to the nearest voxel of the node, or the given
+// distance if no voxel of the node is nearer.
+func (node *voxelNode) distanceFrom(g Geometry, nearest float64) (float64, error) {
+	// no voxel is nearer than the sphere bounding them all, whose distance from any geometry is found exactly, unlike that of a box
+	bound, err := node.boundingSphere.DistanceFrom(g)
+	if err != nil || bound >= nearest {
+		return nearest, err
+	}
+	for _, voxel := range node.voxels {
+		distance, err := voxel.DistanceFrom(g)
+		if err != nil {
+			return nearest, err
+		}
+		nearest = math.Min(nearest, distance)
+	}
+	for _, child := range node.children {
+		if nearest, err = child.distanceFrom(g, nearest); err != nil {
+			return nearest, err
+		}
+	}
+	return nearest, nil
+}
+
+// encompassedBy returns whether a geometry, in the frame of the voxel grid, encompasses every voxel of the node.
+func (node *voxelNode) encompassedBy(g Geometry) (bool, error) {
+	if encompassed, err := node.bounds.EncompassedBy(g); err != nil || encompassed {
+		return encompassed, err
+	}
+	for _, voxel := range node.voxels {
+		if encompassed, err := voxel.EncompassedBy(g); err != nil || !encompassed {
+			return encompassed, err
+		}
+	}
+	for _, child := range node.children {
+		if encompassed, err := child.encompassedBy(g); err != nil || !encompassed {
+			return encompassed, err
+		}
+	}
+	return true, nil
+}
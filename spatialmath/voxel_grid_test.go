@@ -0,0 +1,126 @@
+package spatialmath
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestVoxelGrid(t *testing.T) {
+	randSeed := rand.New(rand.NewSource(1))
+	points := make([]r3.Vector, 0, 500)
+	for i := 0; i < 500; i++ {
+		points = append(points, r3.Vector{X: randSeed.Float64() * 200, Y: randSeed.Float64() * 100, Z: randSeed.Float64() * 50})
+	}
+	pose := NewPose(r3.Vector{X: 100, Y: -50}, &OrientationVectorDegrees{OX: 1, OY: 1, OZ: 1, Theta: 30})
+	grid, err := NewVoxelGrid(pose, points, 10, "cloud")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grid.Label(), test.ShouldEqual, "cloud")
+	_, err = NewVoxelGrid(pose, points, 0, "")
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = NewVoxelGrid(pose, nil, 10, "")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the voxels are the cubes occupied by the points
+	voxels := []Geometry{}
+	for _, center := range grid.(*voxelGrid).centers() {
+		voxel, err := NewBox(Compose(pose, NewPoseFromPoint(center)), r3.Vector{X: 10, Y: 10, Z: 10}, "")
+		test.That(t, err, test.ShouldBeNil)
+		voxels = append(voxels, voxel)
+	}
+	test.That(t, len(voxels), test.ShouldBeLessThan, len(points))
+	test.That(t, grid.ToPoints(0), test.ShouldHaveLength, len(voxels))
+	for _, pt := range points {
+		distance, err := grid.DistanceFrom(NewPoint(Compose(pose, NewPoseFromPoint(pt)).Point(), ""))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, distance, test.ShouldBeLessThanOrEqualTo, CollisionBuffer)
+	}
+
+	// collisions and distances are those of the nearest voxel, whichever geometry is asked
+	for i := 0; i < 50; i++ {
+		center := Compose(pose, NewPoseFromPoint(r3.Vector{
+			X: randSeed.Float64()*300 - 50,
+			Y: randSeed.Float64()*200 - 50,
+			Z: randSeed.Float64()*150 - 50,
+		}))
+		others := []Geometry{NewPoint(center.Point(), "")}
+		for _, other := range []func() (Geometry, error){
+			func() (Geometry, error) { return NewBox(center, r3.Vector{X: 20, Y: 5, Z: 5}, "") },
+			func() (Geometry, error) { return NewSphere(center, 8, "") },
+			func() (Geometry, error) { return NewCapsule(center, 4, 30, "") },
+		} {
+			geometry, err := other()
+			test.That(t, err, test.ShouldBeNil)
+			others = append(others, geometry)
+		}
+		for _, other := range others {
+			expectedCollides := false
+			expectedDistance := math.Inf(1)
+			for _, voxel := range voxels {
+				collides, err := voxel.CollidesWith(other)
+				test.That(t, err, test.ShouldBeNil)
+				expectedCollides = expectedCollides || collides
+				distance, err := voxel.DistanceFrom(other)
+				test.That(t, err, test.ShouldBeNil)
+				expectedDistance = math.Min(expectedDistance, distance)
+			}
+			collides, err := grid.CollidesWith(other)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldEqual, expectedCollides)
+			collides, err = other.CollidesWith(grid)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, collides, test.ShouldEqual, expectedCollides)
+			distance, err := grid.DistanceFrom(other)
+			test.That(t, err, test.ShouldBeNil)
+			otherDistance, err := other.DistanceFrom(grid)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, otherDistance, test.ShouldEqual, distance)
+			if _, ok := other.(*box); ok {
+				// distances between boxes are only estimated, by lower bounds which are tighter for some boxes than for others
+				test.That(t, distance, test.ShouldBeGreaterThanOrEqualTo, expectedDistance-1e-6)
+				test.That(t, distance <= CollisionBuffer, test.ShouldEqual, expectedCollides)
+			} else {
+				test.That(t, distance, test.ShouldAlmostEqual, expectedDistance, 1e-6)
+			}
+		}
+	}
+
+	// voxel grids move as a whole, and collide with each other
+	moved := grid.Transform(NewPoseFromPoint(r3.Vector{Z: 1000}))
+	test.That(t, moved.AlmostEqual(grid), test.ShouldBeFalse)
+	test.That(t, moved.Transform(NewPoseFromPoint(r3.Vector{Z: -1000})).AlmostEqual(grid), test.ShouldBeTrue)
+	collides, err := grid.CollidesWith(moved)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeFalse)
+	collides, err = grid.CollidesWith(grid.Transform(NewPoseFromPoint(r3.Vector{X: 5})))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+
+	encompassing, err := NewBox(Compose(pose, NewPoseFromPoint(r3.Vector{X: 100, Y: 50, Z: 25})), r3.Vector{X: 220, Y: 120, Z: 70}, "")
+	test.That(t, err, test.ShouldBeNil)
+	encompassed, err := grid.EncompassedBy(encompassing)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encompassed, test.ShouldBeTrue)
+	encompassed, err = moved.EncompassedBy(encompassing)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, encompassed, test.ShouldBeFalse)
+
+	// voxel grids are sent as the boxes bounding them which record their voxels
+	data, err := proto.Marshal(grid.ToProtobuf())
+	test.That(t, err, test.ShouldBeNil)
+	var msg commonpb.Geometry
+	test.That(t, proto.Unmarshal(data, &msg), test.ShouldBeNil)
+	decoded, err := NewGeometryFromProto(&msg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, decoded.AlmostEqual(grid), test.ShouldBeTrue)
+	test.That(t, decoded.Label(), test.ShouldEqual, "cloud")
+	msg.ProtoReflect().SetUnknown(nil)
+	bounds, err := NewGeometryFromProto(&msg)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bounds.AlmostEqual(grid.(*voxelGrid).root.bounds.Transform(pose)), test.ShouldBeTrue)
+}
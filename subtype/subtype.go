@@ -1,7 +1,20 @@
 // Package subtype contains a Service type that can be used to hold all resources of a certain subtype.
+//
+// Resources are looked up by name. Resources of remotes are named by the remotes they are on,
+// such as "rover2:cam" or "base:rover2:cam", and may be looked up by any unambiguous suffix of
+// their name: "cam" finds "rover2:cam" unless another resource is named "cam" on some other
+// remote, and "rover2:cam" finds "base:rover2:cam". A resource whose full name matches always
+// wins, so local resources are never shadowed by those of remotes.
+//
+// Several resources may be looked up at once with a pattern whose parts, separated by ":", are
+// matched like path.Match does, against the same number of trailing parts of each name:
+// "rover2:*" matches every resource on remote rover2 and "*:cam" every resource named cam on a
+// remote. Looking up a single resource by a pattern finds it when it is the only match, so
+// "lid*" finds "rover2:lidar" if no other lidar is named like it.
 package subtype
 
 import (
+	"path"
 	"strings"
 	"sync"
 
@@ -17,11 +30,15 @@ type Service interface {
 	Add(name resource.Name, iface interface{}) error
 	Remove(name resource.Name) error
 	ReplaceOne(n resource.Name, iface interface{}) error
+	// Matching returns the resources matching a pattern by their full names.
+	Matching(pattern string) (map[string]interface{}, error)
 }
 
 type subtypeSvc struct {
-	mu         sync.RWMutex
-	resources  map[string]interface{}
+	mu        sync.RWMutex
+	resources map[string]interface{}
+	// shortNames maps every partial name of a resource to its full name, or to "" when the
+	// partial name is shared by several resources.
 	shortNames map[string]string
 }
 
@@ -45,9 +62,59 @@ func (s *subtypeSvc) Resource(name string) interface{} {
 	if resource, ok := s.resources[s.shortNames[name]]; ok {
 		return resource
 	}
+	// looking for the only resource matching the name as a pattern
+	if !strings.ContainsAny(name, "*?[\\") {
+		return nil
+	}
+	matches, err := s.matching(name)
+	if err != nil || len(matches) != 1 {
+		return nil
+	}
+	for _, resource := range matches {
+		return resource
+	}
 	return nil
 }
 
+func (s *subtypeSvc) Matching(pattern string) (map[string]interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.matching(pattern)
+}
+
+// matching returns the resources matching a pattern by their full names. It must be called with
+// the lock held.
+func (s *subtypeSvc) matching(pattern string) (map[string]interface{}, error) {
+	patternParts := strings.Split(pattern, ":")
+	for _, part := range patternParts {
+		if _, err := path.Match(part, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid resource name pattern %q", pattern)
+		}
+	}
+
+	matches := map[string]interface{}{}
+	for name, resource := range s.resources {
+		if nameMatches(patternParts, strings.Split(name, ":")) {
+			matches[name] = resource
+		}
+	}
+	return matches, nil
+}
+
+// nameMatches returns whether the parts of a pattern match the trailing parts of a name.
+func nameMatches(patternParts, nameParts []string) bool {
+	if len(patternParts) > len(nameParts) {
+		return false
+	}
+	nameParts = nameParts[len(nameParts)-len(patternParts):]
+	for i, part := range patternParts {
+		if ok, err := path.Match(part, nameParts[i]); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
 // ReplaceAll replaces all resources with r.
 func (s *subtypeSvc) ReplaceAll(r map[resource.Name]interface{}) error {
 	s.mu.Lock()
@@ -98,14 +165,7 @@ func (s *subtypeSvc) doAdd(n resource.Name, iface interface{}) error {
 	}
 
 	s.resources[name] = iface
-	shortcut := getShortcutName(name)
-	if shortcut != name {
-		if _, ok := s.shortNames[shortcut]; ok {
-			s.shortNames[shortcut] = ""
-		} else {
-			s.shortNames[shortcut] = name
-		}
-	}
+	s.addShortNames(name)
 	return nil
 }
 
@@ -117,25 +177,33 @@ func (s *subtypeSvc) doRemove(n resource.Name) error {
 	}
 	delete(s.resources, name)
 
-	shortcut := getShortcutName(name)
-	_, ok = s.shortNames[shortcut]
-	if ok {
-		delete(s.shortNames, shortcut)
-	}
-
 	// case: remote1:nameA and remote2:nameA both existed, and remote2:nameA is being deleted, restore shortcut to remote1:nameA
+	s.shortNames = make(map[string]string, len(s.resources))
 	for k := range s.resources {
-		if shortcut == getShortcutName(k) && name != getShortcutName(k) {
-			if _, ok := s.shortNames[shortcut]; ok {
-				s.shortNames[shortcut] = ""
-			} else {
-				s.shortNames[shortcut] = k
-			}
-		}
+		s.addShortNames(k)
 	}
 	return nil
 }
 
-func getShortcutName(name string) string {
-	return name[strings.LastIndexAny(name, ":")+1:]
+// addShortNames adds the partial names of a resource, marking those that become ambiguous.
+func (s *subtypeSvc) addShortNames(name string) {
+	for _, shortcut := range getShortcutNames(name) {
+		if _, ok := s.shortNames[shortcut]; ok {
+			s.shortNames[shortcut] = ""
+		} else {
+			s.shortNames[shortcut] = name
+		}
+	}
+}
+
+// getShortcutNames returns the partial names of a remote resource, which are the suffixes of its
+// name that start after a ":".
+func getShortcutNames(name string) []string {
+	var shortcuts []string
+	for i, c := range name {
+		if c == ':' {
+			shortcuts = append(shortcuts, name[i+1:])
+		}
+	}
+	return shortcuts
 }
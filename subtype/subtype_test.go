@@ -1,6 +1,7 @@
 package subtype_test
 
 import (
+	"sort"
 	"testing"
 
 	"go.viam.com/test"
@@ -157,6 +158,62 @@ func TestSubtypeRemoteNames(t *testing.T) {
 
 	test.That(t, svc.Resource("name0"), test.ShouldEqual, name0)
 	test.That(t, svc.Resource("nameX"), test.ShouldBeNil)
+
+	// partially qualified names
+	test.That(t, svc.Resource("remote4:name1"), test.ShouldEqual, name7)
+	test.That(t, svc.Resource("remote1:name4"), test.ShouldEqual, name4)
+	test.That(t, svc.Resource("remote1:name5"), test.ShouldBeNil)
+	test.That(t, svc.Resource(":name4"), test.ShouldBeNil)
+}
+
+func TestSubtypeMatching(t *testing.T) {
+	strType := resource.SubtypeName("string")
+	names := []string{
+		"cam",
+		"rover1:cam",
+		"rover2:cam",
+		"rover2:lidar",
+		"base:rover2:cam",
+		"base:rover2:arm:cam",
+	}
+	resources := map[resource.Name]interface{}{}
+	for _, name := range names {
+		resources[resource.NewName(resource.ResourceNamespaceRDK, resource.ResourceTypeComponent, strType, name)] = name
+	}
+	svc, err := subtype.New(resources)
+	test.That(t, err, test.ShouldBeNil)
+
+	matching := func(pattern string) []string {
+		t.Helper()
+		matches, err := svc.Matching(pattern)
+		test.That(t, err, test.ShouldBeNil)
+		var names []string
+		for name, v := range matches {
+			test.That(t, v, test.ShouldEqual, name)
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	test.That(t, matching("*"), test.ShouldHaveLength, len(names))
+	test.That(t, matching("rover2:*"), test.ShouldResemble, []string{"base:rover2:cam", "rover2:cam", "rover2:lidar"})
+	test.That(t, matching("*:cam"), test.ShouldResemble, []string{"base:rover2:arm:cam", "base:rover2:cam", "rover1:cam", "rover2:cam"})
+	test.That(t, matching("rover?:cam"), test.ShouldResemble, []string{"base:rover2:cam", "rover1:cam", "rover2:cam"})
+	test.That(t, matching("base:*:*"), test.ShouldResemble, []string{"base:rover2:cam"})
+	test.That(t, matching("lid*"), test.ShouldResemble, []string{"rover2:lidar"})
+	test.That(t, matching("gripper"), test.ShouldBeNil)
+
+	_, err = svc.Matching("rover[:cam")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a single resource is found by a pattern only it matches
+	test.That(t, svc.Resource("lid*"), test.ShouldEqual, "rover2:lidar")
+	test.That(t, svc.Resource("rover1:*"), test.ShouldEqual, "rover1:cam")
+	test.That(t, svc.Resource("*:arm:*"), test.ShouldEqual, "base:rover2:arm:cam")
+	test.That(t, svc.Resource("rover2:*"), test.ShouldBeNil)
+	test.That(t, svc.Resource("grip*"), test.ShouldBeNil)
+	test.That(t, svc.Resource("rover[:cam"), test.ShouldBeNil)
 }
 
 func TestSubtypeAddRemoveReplaceOne(t *testing.T) {
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// Defaults of a Backoff.
+const (
+	defaultBackoffInitial    = 100 * time.Millisecond
+	defaultBackoffMax        = 10 * time.Second
+	defaultBackoffMultiplier = 2
+)
+
+// A Backoff computes exponentially growing delays between attempts at something. The zero
+// value starts at 100ms and doubles up to 10s without jitter.
+type Backoff struct {
+	// Initial is the delay after the first attempt.
+	Initial time.Duration
+	// Max bounds the delay.
+	Max time.Duration
+	// Multiplier is how much the delay grows with every attempt.
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction of it, between 0 and 1, so that many
+	// clients retrying at once spread out.
+	Jitter float64
+}
+
+// Delay returns how long to wait after the given attempt, counting from 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	initial, maxDelay, multiplier := b.Initial, b.Max, b.Multiplier
+	if initial <= 0 {
+		initial = defaultBackoffInitial
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultBackoffMax
+	}
+	if multiplier < 1 {
+		multiplier = defaultBackoffMultiplier
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := math.Min(float64(initial)*math.Pow(multiplier, float64(attempt-1)), float64(maxDelay))
+	if b.Jitter > 0 {
+		//nolint:gosec
+		delay -= delay * math.Min(b.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// RetryOptions configure Retry.
+type RetryOptions struct {
+	Backoff
+	// MaxAttempts bounds the number of attempts. Zero retries until the context is done.
+	MaxAttempts int
+	// Retryable decides which errors are worth retrying. If nil, every error that is not marked
+	// with Permanent is.
+	Retryable func(err error) bool
+	// OnRetry, if set, is called before waiting to retry, for example to log the error.
+	OnRetry func(attempt int, delay time.Duration, err error)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks an error so that Retry returns it rather than trying again.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+// IsTemporary reports whether an error is one that commonly goes away when an operation is
+// retried: network timeouts, temporary DNS failures, refused or reset connections, and busy or
+// unavailable devices. Errors of a done context are not temporary.
+func IsTemporary(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsTemporary {
+		return true
+	}
+	for _, errno := range []syscall.Errno{
+		syscall.EAGAIN, syscall.EBUSY, syscall.EINTR, syscall.ETIMEDOUT,
+		syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED,
+	} {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// Retry calls fn until it succeeds, returns an error that is not retryable, runs out of
+// attempts, or the context is done. Waits are aware of the context's deadline: if the next
+// attempt would start after it, the last error is returned right away instead of waiting.
+// When the context ends, its error is combined with the last error of fn.
+func Retry(ctx context.Context, opts RetryOptions, fn func(ctx context.Context) error) error {
+	_, err := RetryValue(ctx, opts, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// RetryValue is like Retry for functions that return a value, which it returns on success.
+func RetryValue[T any](ctx context.Context, opts RetryOptions, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		v, err := fn(ctx)
+		if err == nil {
+			return v, nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return zero, permanent.err
+		}
+		if opts.Retryable != nil && !opts.Retryable(err) {
+			return zero, err
+		}
+		if opts.MaxAttempts > 0 && attempt >= opts.MaxAttempts {
+			return zero, err
+		}
+
+		delay := opts.Delay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return zero, multierr.Combine(err, context.DeadlineExceeded)
+		}
+		if opts.OnRetry != nil {
+			opts.OnRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, multierr.Combine(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{}
+	test.That(t, b.Delay(0), test.ShouldEqual, 100*time.Millisecond)
+	test.That(t, b.Delay(1), test.ShouldEqual, 100*time.Millisecond)
+	test.That(t, b.Delay(2), test.ShouldEqual, 200*time.Millisecond)
+	test.That(t, b.Delay(3), test.ShouldEqual, 400*time.Millisecond)
+	test.That(t, b.Delay(100), test.ShouldEqual, 10*time.Second)
+
+	b = Backoff{Initial: time.Second, Max: 5 * time.Second, Multiplier: 3}
+	test.That(t, b.Delay(2), test.ShouldEqual, 3*time.Second)
+	test.That(t, b.Delay(3), test.ShouldEqual, 5*time.Second)
+
+	b = Backoff{Initial: time.Second, Jitter: 0.5}
+	for i := 0; i < 10; i++ {
+		delay := b.Delay(1)
+		test.That(t, delay, test.ShouldBeGreaterThan, 500*time.Millisecond)
+		test.That(t, delay, test.ShouldBeLessThanOrEqualTo, time.Second)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	opts := RetryOptions{Backoff: Backoff{Initial: time.Millisecond, Max: time.Millisecond}}
+	errFailed := errors.New("failed")
+
+	t.Run("succeeds after failures", func(t *testing.T) {
+		var attempts, retries int
+		opts := opts
+		opts.OnRetry = func(attempt int, delay time.Duration, err error) {
+			retries++
+			test.That(t, attempt, test.ShouldEqual, retries)
+			test.That(t, err, test.ShouldEqual, errFailed)
+		}
+		v, err := RetryValue(context.Background(), opts, func(ctx context.Context) (int, error) {
+			attempts++
+			if attempts < 3 {
+				return 0, errFailed
+			}
+			return attempts, nil
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, v, test.ShouldEqual, 3)
+		test.That(t, retries, test.ShouldEqual, 2)
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		var attempts int
+		opts := opts
+		opts.MaxAttempts = 4
+		err := Retry(context.Background(), opts, func(ctx context.Context) error {
+			attempts++
+			return errFailed
+		})
+		test.That(t, err, test.ShouldEqual, errFailed)
+		test.That(t, attempts, test.ShouldEqual, 4)
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		var attempts int
+		err := Retry(context.Background(), opts, func(ctx context.Context) error {
+			attempts++
+			return Permanent(errFailed)
+		})
+		test.That(t, err, test.ShouldEqual, errFailed)
+		test.That(t, attempts, test.ShouldEqual, 1)
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		var attempts int
+		opts := opts
+		opts.Retryable = IsTemporary
+		err := Retry(context.Background(), opts, func(ctx context.Context) error {
+			attempts++
+			if attempts == 1 {
+				return syscall.EAGAIN
+			}
+			return errFailed
+		})
+		test.That(t, err, test.ShouldEqual, errFailed)
+		test.That(t, attempts, test.ShouldEqual, 2)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var attempts int
+		start := time.Now()
+		err := Retry(ctx, RetryOptions{Backoff: Backoff{Initial: time.Second}}, func(ctx context.Context) error {
+			attempts++
+			return errFailed
+		})
+		test.That(t, errors.Is(err, errFailed), test.ShouldBeTrue)
+		test.That(t, errors.Is(err, context.DeadlineExceeded), test.ShouldBeTrue)
+		test.That(t, attempts, test.ShouldEqual, 1)
+		test.That(t, time.Since(start), test.ShouldBeLessThan, 50*time.Millisecond)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var attempts int
+		err := Retry(ctx, opts, func(ctx context.Context) error {
+			attempts++
+			if attempts == 2 {
+				cancel()
+			}
+			return errFailed
+		})
+		test.That(t, errors.Is(err, errFailed), test.ShouldBeTrue)
+		test.That(t, errors.Is(err, context.Canceled), test.ShouldBeTrue)
+		test.That(t, attempts, test.ShouldEqual, 2)
+	})
+}
+
+func TestIsTemporary(t *testing.T) {
+	test.That(t, IsTemporary(errors.New("failed")), test.ShouldBeFalse)
+	test.That(t, IsTemporary(syscall.ENOENT), test.ShouldBeFalse)
+	test.That(t, IsTemporary(errors.Wrap(syscall.EBUSY, "write")), test.ShouldBeTrue)
+	test.That(t, IsTemporary(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), test.ShouldBeTrue)
+	test.That(t, IsTemporary(&net.DNSError{Err: "no such host", IsNotFound: true}), test.ShouldBeFalse)
+	test.That(t, IsTemporary(&net.DNSError{Err: "timeout", IsTimeout: true}), test.ShouldBeTrue)
+	test.That(t, IsTemporary(context.DeadlineExceeded), test.ShouldBeFalse)
+}
@@ -20,6 +20,8 @@ type RadiusClusteringConfig struct {
 	ClusteringRadiusMm float64 `json:"clustering_radius_mm"`
 	MeanKFiltering     int     `json:"mean_k_filtering"`
 	Label              string  `json:"label,omitempty"`
+	// Filters are applied in order to the point cloud before segmenting it.
+	Filters []pc.FilterConfig `json:"filters,omitempty"`
 }
 
 // CheckValid checks to see in the input values are valid.
@@ -33,6 +35,9 @@ func (rcc *RadiusClusteringConfig) CheckValid() error {
 	if rcc.ClusteringRadiusMm <= 0 {
 		return errors.Errorf("clustering_radius_mm must be greater than 0, got %v", rcc.ClusteringRadiusMm)
 	}
+	if _, err := pc.FiltersFromConfigs(rcc.Filters); err != nil {
+		return errors.Wrap(err, "filters")
+	}
 	return nil
 }
 
@@ -72,6 +77,10 @@ func (rcc *RadiusClusteringConfig) RadiusClustering(ctx context.Context, c camer
 	if err != nil {
 		return nil, err
 	}
+	cloud, err = filterPointCloud(cloud, rcc.Filters)
+	if err != nil {
+		return nil, err
+	}
 	ps := NewPointCloudPlaneSegmentation(cloud, 10, rcc.MinPtsInPlane)
 	// if there are found planes, remove them, and keep all the non-plane points
 	_, nonPlane, err := ps.FindPlanes(ctx)
@@ -101,6 +110,18 @@ func (rcc *RadiusClusteringConfig) RadiusClustering(ctx context.Context, c camer
 	return objects.Objects, nil
 }
 
+// filterPointCloud applies the filters described by configs to a point cloud.
+func filterPointCloud(cloud pc.PointCloud, configs []pc.FilterConfig) (pc.PointCloud, error) {
+	if len(configs) == 0 {
+		return cloud, nil
+	}
+	filter, err := pc.FiltersFromConfigs(configs)
+	if err != nil {
+		return nil, err
+	}
+	return filter(cloud)
+}
+
 // segmentPointCloudObjects uses radius based nearest neighbors to segment the images, and then prunes away
 // segments that do not pass a certain threshold of points.
 func segmentPointCloudObjects(cloud pc.PointCloud, radius float64, nMin int) ([]pc.PointCloud, error) {
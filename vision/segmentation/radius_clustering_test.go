@@ -33,6 +33,15 @@ func TestRadiusClusteringValidate(t *testing.T) {
 	cfg.MeanKFiltering = 5
 	err = cfg.CheckValid()
 	test.That(t, err, test.ShouldBeNil)
+	// invalid filter
+	cfg.Filters = []pc.FilterConfig{{Type: pc.FilterTypeVoxelGrid, VoxelSizeMm: 2}, {Type: pc.FilterTypePassthrough, Axis: "w"}}
+	err = cfg.CheckValid()
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid point cloud filter 1")
+	// valid filters
+	cfg.Filters[1].Axis = "z"
+	cfg.Filters[1].MaxMm = 1000
+	err = cfg.CheckValid()
+	test.That(t, err, test.ShouldBeNil)
 }
 
 // get a segmentation of a pointcloud and calculate each object's center.
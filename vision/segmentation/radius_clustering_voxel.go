@@ -26,6 +26,8 @@ type RadiusClusteringVoxelConfig struct {
 	CosineThresh       float64 `json:"cosine_threshold"` // between -1 and 1, the value after evaluating Cosine(theta)
 	DistanceThresh     float64 `json:"distance_threshold_mm"`
 	Label              string  `json:"label,omitempty"`
+	// Filters are applied in order to the point cloud before turning it into a voxel grid.
+	Filters []pc.FilterConfig `json:"filters,omitempty"`
 }
 
 // CheckValid checks to see in the input values are valid.
@@ -36,7 +38,14 @@ func (rcc *RadiusClusteringVoxelConfig) CheckValid() error {
 	if rcc.Lambda <= 0 {
 		return errors.Errorf("lambda must be greater than 0, got %v", rcc.Lambda)
 	}
-	radiusClustering := RadiusClusteringConfig{rcc.MinPtsInPlane, rcc.MinPtsInSegment, rcc.ClusteringRadiusMm, 50.0, rcc.Label}
+	radiusClustering := RadiusClusteringConfig{
+		MinPtsInPlane:      rcc.MinPtsInPlane,
+		MinPtsInSegment:    rcc.MinPtsInSegment,
+		ClusteringRadiusMm: rcc.ClusteringRadiusMm,
+		MeanKFiltering:     50.0,
+		Label:              rcc.Label,
+		Filters:            rcc.Filters,
+	}
 	err := radiusClustering.CheckValid()
 	if err != nil {
 		return err
@@ -84,6 +93,10 @@ func (rcc *RadiusClusteringVoxelConfig) RadiusClusteringVoxels(ctx context.Conte
 	if err != nil {
 		return nil, err
 	}
+	cloud, err = filterPointCloud(cloud, rcc.Filters)
+	if err != nil {
+		return nil, err
+	}
 	// turn the point cloud into a voxel grid
 	vg := pc.NewVoxelGridFromPointCloud(cloud, rcc.VoxelSize, rcc.Lambda)
 	planeConfig := VoxelGridPlaneConfig{rcc.WeightThresh, rcc.AngleThresh, rcc.CosineThresh, rcc.DistanceThresh}
@@ -13,3 +13,10 @@ export const selectedMap = {
   'Every 10 Seconds': 10,
   'Every Second': 1,
 } as const;
+
+// Lower qualities subscribe to variants of a stream the server scales down, for viewing over poor links.
+export const streamQualities = ['High', 'Medium', 'Low'] as const;
+
+export const streamName = (cameraName: string, quality = 'High') => (
+  quality === 'High' ? cameraName : `${cameraName}~${quality.toLowerCase()}`
+);
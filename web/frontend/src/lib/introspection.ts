@@ -0,0 +1,174 @@
+/* eslint-disable max-classes-per-file, class-methods-use-this */
+
+/*
+ * A grpc-web client for the RobotIntrospectionService the rdk serves alongside the robot service
+ * (proto/api/robot/v1/introspection.proto). The SDK does not ship this service, so its messages are
+ * written out here rather than generated.
+ */
+import { grpc } from '@improbable-eng/grpc-web';
+import { BinaryReader } from 'google-protobuf';
+import type { ServiceError } from '@viamrobotics/sdk';
+
+export interface ResourceGraphNode {
+  name: string
+  model: string
+  source: string
+  dependsOn: string[]
+  state: string
+  error: string
+}
+
+export interface ResourceGraphEdge {
+  from: string
+  to: string
+}
+
+export interface ResourceGraph {
+  nodes: ResourceGraphNode[]
+  edges: ResourceGraphEdge[]
+}
+
+export class GetResourceGraphRequest {
+  static deserializeBinary () {
+    return new GetResourceGraphRequest();
+  }
+
+  serializeBinary () {
+    return new Uint8Array();
+  }
+
+  toObject () {
+    return {};
+  }
+}
+
+const readNode = (node: ResourceGraphNode, reader: BinaryReader) => {
+  while (reader.nextField() && !reader.isEndGroup()) {
+    switch (reader.getFieldNumber()) {
+      case 1: {
+        node.name = reader.readString();
+        break;
+      }
+      case 2: {
+        node.model = reader.readString();
+        break;
+      }
+      case 3: {
+        node.source = reader.readString();
+        break;
+      }
+      case 4: {
+        node.dependsOn.push(reader.readString());
+        break;
+      }
+      case 5: {
+        node.state = reader.readString();
+        break;
+      }
+      case 6: {
+        node.error = reader.readString();
+        break;
+      }
+      default: {
+        reader.skipField();
+      }
+    }
+  }
+};
+
+const readEdge = (edge: ResourceGraphEdge, reader: BinaryReader) => {
+  while (reader.nextField() && !reader.isEndGroup()) {
+    switch (reader.getFieldNumber()) {
+      case 1: {
+        edge.from = reader.readString();
+        break;
+      }
+      case 2: {
+        edge.to = reader.readString();
+        break;
+      }
+      default: {
+        reader.skipField();
+      }
+    }
+  }
+};
+
+export class GetResourceGraphResponse {
+  graph: ResourceGraph = { nodes: [], edges: [] };
+
+  static deserializeBinary (bytes: Uint8Array) {
+    const resp = new GetResourceGraphResponse();
+    const reader = new BinaryReader(bytes);
+    while (reader.nextField() && !reader.isEndGroup()) {
+      switch (reader.getFieldNumber()) {
+        case 1: {
+          const node: ResourceGraphNode = { name: '', model: '', source: '', dependsOn: [], state: '', error: '' };
+          reader.readMessage(node, readNode);
+          resp.graph.nodes.push(node);
+          break;
+        }
+        case 2: {
+          const edge: ResourceGraphEdge = { from: '', to: '' };
+          reader.readMessage(edge, readEdge);
+          resp.graph.edges.push(edge);
+          break;
+        }
+        default: {
+          reader.skipField();
+        }
+      }
+    }
+    return resp;
+  }
+
+  serializeBinary (): Uint8Array {
+    throw new Error('GetResourceGraphResponse is only ever received');
+  }
+
+  toObject () {
+    return this.graph;
+  }
+}
+
+const service = { serviceName: 'proto.api.robot.v1.RobotIntrospectionService' };
+
+const getResourceGraphMethod: grpc.UnaryMethodDefinition<GetResourceGraphRequest, GetResourceGraphResponse> = {
+  methodName: 'GetResourceGraph',
+  service,
+  requestStream: false,
+  responseStream: false,
+  requestType: GetResourceGraphRequest,
+  responseType: GetResourceGraphResponse,
+};
+
+export class RobotIntrospectionServiceClient {
+  serviceHost: string;
+  options: grpc.RpcOptions;
+
+  constructor (serviceHost: string, options: grpc.RpcOptions = {}) {
+    this.serviceHost = serviceHost;
+    this.options = options;
+  }
+
+  getResourceGraph (
+    req: GetResourceGraphRequest,
+    metadata: grpc.Metadata,
+    callback: (error: ServiceError | null, resp: GetResourceGraphResponse | null) => void
+  ) {
+    return grpc.unary(getResourceGraphMethod, {
+      request: req,
+      host: this.serviceHost,
+      metadata,
+      transport: this.options.transport,
+      debug: this.options.debug,
+      onEnd: ({ status, statusMessage, trailers, message }) => {
+        if (status === grpc.Code.OK) {
+          callback(null, message as GetResourceGraphResponse);
+          return;
+        }
+        callback({ message: statusMessage, code: status, metadata: trailers } as ServiceError, null);
+      },
+    });
+  }
+}
@@ -34,6 +34,7 @@ type Arguments struct {
 	Debug                        bool   `flag:"debug"`
 	LimitConfigurableDirectories bool   `flag:"limit-configurable-directories,usage=limit which directories users can configure for storing data on-robot"` //nolint:lll
 	SharedDir                    string `flag:"shareddir,usage=web resource directory"`
+	Simulate                     bool   `flag:"simulate,usage=replace every component with its fake model"`
 	Version                      bool   `flag:"version,usage=print version"`
 	WebProfile                   bool   `flag:"webprofile,usage=include profiler in http server"`
 	WebRTC                       bool   `flag:"webrtc,usage=force webrtc connections instead of direct"`
@@ -222,6 +223,9 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 		out.LimitConfigurableDirectories = s.args.LimitConfigurableDirectories
 		out.UntrustedEnv = s.args.UntrustedEnv
 		out.PackagePath = path.Join(viamDotDir, "packages")
+		if s.args.Simulate {
+			return robotimpl.SimulatedConfig(out, s.logger)
+		}
 		return out, nil
 	}
 
@@ -317,6 +321,9 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 					s.logger.Errorw("reconfiguration aborted: error diffing config", "error", err)
 					continue
 				}
+				if !diff.ResourcesEqual {
+					s.logger.Infow("reconfiguring changed resources", "changes", diff.Summary())
+				}
 				var options weboptions.Options
 
 				if !diff.NetworkEqual || !diff.MediaEqual {